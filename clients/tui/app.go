@@ -2,7 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -35,6 +39,21 @@ func WithHistory(msgs []HistoryMessage) AppOption {
 	return func(a *App) { a.history = msgs }
 }
 
+// WithKeyMap sets the key bindings. Warnings (e.g. conflicting bindings) are
+// printed once the TUI starts.
+func WithKeyMap(keys *components.KeyMap, warnings []string) AppOption {
+	return func(a *App) {
+		a.keys = keys
+		a.keyWarnings = warnings
+	}
+}
+
+// WithNotifications alerts with a bell or a desktop notification when a task
+// or skill finishes while the terminal is out of focus (default: off).
+func WithNotifications(mode NotifyMode) AppOption {
+	return func(a *App) { a.notify = mode }
+}
+
 // App is the main TUI application model.
 // Architecture: terminal-streaming with tea.Println for flushed history
 // and a small active zone rendered in View().
@@ -42,11 +61,20 @@ type App struct {
 	// Components (sticky footer)
 	header    *components.Header
 	inputZone *components.InputZone
+	tasks     *components.TaskPanel // shown above the input when showTasks
 
 	// Active interaction state (rendered in View)
 	activeTools  []components.ToolCall
+	toolRetries  map[string]ToolRecoveryMsg // retry announced before the tool restarts
 	streaming    string
 	showThinking bool
+	showTasks    bool
+
+	// Throttled markdown rendering of the streaming text
+	streamRendered      string // rendered snapshot of streaming[:streamRenderedLen]
+	streamRenderedLen   int
+	streamRenderedAt    time.Time
+	streamRenderPending bool // a streamRenderMsg is scheduled
 
 	// State
 	width       int
@@ -65,6 +93,14 @@ type App struct {
 	// Current prompt state (token for response)
 	currentPromptToken string
 
+	// Key bindings
+	keys        *components.KeyMap
+	keyWarnings []string
+
+	// Completion alerts
+	notify NotifyMode
+	focus  focusState
+
 	// Dependencies
 	client    *wsclient.Client
 	sessionID string
@@ -75,12 +111,22 @@ func NewApp(client *wsclient.Client, sessionID string, opts ...AppOption) *App {
 	a := &App{
 		header:    components.NewHeader(),
 		inputZone: components.NewInputZone(),
+		tasks:     components.NewTaskPanel(),
 		client:    client,
 		sessionID: sessionID,
+		keys:      components.DefaultKeyMap(),
 	}
 	for _, opt := range opts {
 		opt(a)
 	}
+	a.inputZone.SetKeyMap(a.keys)
+	a.inputZone.SetCommands(slashCommands)
+	// Resumed sessions recall their previous user messages with up/down.
+	for _, m := range a.history {
+		if m.Role == "user" {
+			a.inputZone.AddHistory(m.Content)
+		}
+	}
 	return a
 }
 
@@ -117,6 +163,10 @@ func (a *App) Init() tea.Cmd {
 		cmds = append(cmds, tea.Println(components.RenderWelcome()))
 	}
 
+	for _, w := range a.keyWarnings {
+		cmds = append(cmds, tea.Println(components.RenderError("Key bindings: "+w, a.width)))
+	}
+
 	return tea.Batch(cmds...)
 }
 
@@ -157,6 +207,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 		a.updateSizes()
+		if a.streamRendered != "" {
+			a.streamRenderedLen = 0 // re-wrap the snapshot at the new width
+			cmds = append(cmds, a.scheduleStreamRender())
+		}
 		if a.inputZone.Mode() == components.ModeChat && !a.isStreaming {
 			cmds = append(cmds, a.inputZone.Focus())
 		}
@@ -167,12 +221,31 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-		switch msg.String() {
-		case "ctrl+c":
+		if a.keys.Action(msg) == components.KeyQuit {
 			a.quitting = true
 			return a, tea.Quit
 		}
 
+		// The open task panel takes the keys until it is closed.
+		if a.keys.Action(msg) == components.KeyTasks {
+			return a, a.toggleTasks()
+		}
+		if a.showTasks {
+			return a, a.handleTaskPanelKey(msg)
+		}
+
+		// Cancel while the agent works stops its turn server-side.
+		if a.isStreaming && a.inputZone.Mode() == components.ModeChat &&
+			a.keys.Action(msg) == components.KeyCancel {
+			client := a.client
+			return a, func() tea.Msg {
+				if err := client.Interrupt(""); err != nil {
+					return sendErrorMsg{err: err}
+				}
+				return nil
+			}
+		}
+
 		// Update input zone
 		var cmd tea.Cmd
 		a.inputZone, cmd = a.inputZone.Update(msg)
@@ -185,7 +258,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case StreamStartMsg:
 		a.isStreaming = true
-		a.streaming = ""
+		a.resetStreaming()
 		a.showThinking = true
 		a.header.SetStreaming(true)
 		a.inputZone.SetDisabled(true)
@@ -193,6 +266,11 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StreamDeltaMsg:
 		a.showThinking = false
 		a.streaming += msg.Content
+		cmds = append(cmds, a.scheduleStreamRender())
+
+	case streamRenderMsg:
+		a.streamRenderPending = false
+		a.renderStream()
 
 	case StreamEndMsg:
 		// No-op: content finalized by AssistantMessageMsg
@@ -205,7 +283,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.showThinking = false
 		a.header.SetStreaming(false)
 		a.inputZone.SetDisabled(false)
-		a.streaming = ""
+		a.resetStreaming()
 
 		if msg.Error != "" {
 			cmds = append(cmds, tea.Println("\n"+components.RenderError(msg.Error, a.width)))
@@ -225,9 +303,62 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ToolCallMsg:
 		cmds = append(cmds, a.handleToolCall(msg)...)
 
+	case ToolCallDeltaMsg:
+		a.handleToolCallDelta(msg)
+
+	case ToolRecoveryMsg:
+		a.handleToolRecovery(msg)
+
 	case PromptRequestMsg:
 		cmds = append(cmds, a.handlePromptRequest(msg)...)
 
+	case TaskNarrationMsg:
+		// Printed to scrollback so it never mixes with the interactive stream.
+		label := msg.Title
+		if label == "" {
+			label = msg.TaskID
+		}
+		cmds = append(cmds, tea.Println(components.RenderToolLog(fmt.Sprintf("[%s] %s", components.TruncateString(label, 40), msg.Text))))
+
+	case tea.FocusMsg:
+		a.focus = focusIn
+
+	case tea.BlurMsg:
+		a.focus = focusOut
+
+	case TaskUpdateMsg:
+		a.applyTaskUpdate(msg)
+		if msg.Status == "completed" || msg.Status == "failed" {
+			label := msg.TaskID
+			if t, _ := a.tasks.Task(msg.TaskID); t.Title != "" {
+				label = t.Title
+			}
+			cmds = append(cmds, a.notifyDone(true, "task "+msg.Status, label))
+		}
+
+	case SkillCompletedMsg:
+		// A skill finishing while no turn streams was not started by the user.
+		status := "skill completed"
+		if msg.Error != "" {
+			status = "skill failed"
+		}
+		cmds = append(cmds, a.notifyDone(!a.isStreaming, status, msg.Name))
+
+	case taskListMsg:
+		if msg.err != nil {
+			cmds = append(cmds, tea.Println(components.RenderError(fmt.Sprintf("Tasks: %v", msg.err), a.width)))
+			return a, tea.Batch(cmds...)
+		}
+		a.setTasks(msg.tasks)
+
+	case taskCheckMsg:
+		cmds = append(cmds, a.renderTaskCheck(msg))
+		return a, tea.Batch(cmds...)
+
+	case taskCancelMsg:
+		cmds = append(cmds, a.renderTaskCancel(msg))
+		return a, tea.Batch(cmds...)
+
 	case LLMTelemetryMsg:
 		a.header.AddTokens(msg.TokensOut)
 
@@ -240,6 +371,34 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DisconnectedMsg:
 		// Phase 2
 
+	case auditLogMsg:
+		cmds = append(cmds, a.renderAuditLog(msg)...)
+		return a, tea.Batch(cmds...)
+
+	case findMsg:
+		cmds = append(cmds, a.renderFind(msg))
+		return a, tea.Batch(cmds...)
+
+	case modelMsg:
+		cmds = append(cmds, a.renderModelSelection(msg)...)
+		return a, tea.Batch(cmds...)
+
+	case personaMsg:
+		cmds = append(cmds, a.renderPersona(msg))
+		return a, tea.Batch(cmds...)
+
+	case cancelAllMsg:
+		cmds = append(cmds, a.renderCancelAll(msg))
+		return a, tea.Batch(cmds...)
+
+	case taskGraphMsg:
+		cmds = append(cmds, a.renderTaskGraph(msg))
+		return a, tea.Batch(cmds...)
+
+	case artifactsMsg:
+		cmds = append(cmds, a.renderArtifacts(msg))
+		return a, tea.Batch(cmds...)
+
 	case sendErrorMsg:
 		cmds = append(cmds, tea.Println(components.RenderError(fmt.Sprintf("Send error: %v", msg.err), a.width)))
 		return a, tea.Batch(cmds...)
@@ -261,8 +420,14 @@ func (a *App) View() tea.View {
 		parts = append(parts, active)
 	}
 
+	if a.showTasks {
+		parts = append(parts, a.tasks.View())
+	}
+
 	parts = append(parts, a.inputZone.View(), a.header.View())
-	return tea.NewView(lipgloss.JoinVertical(lipgloss.Left, parts...))
+	v := tea.NewView(lipgloss.JoinVertical(lipgloss.Left, parts...))
+	v.ReportFocus = a.notify == NotifyBell || a.notify == NotifyDesktop
+	return v
 }
 
 // renderActive renders only in-progress elements (tools + streaming + thinking).
@@ -279,8 +444,11 @@ func (a *App) renderActive() string {
 		parts = append(parts, components.RenderThinking())
 	}
 
-	// Streaming text (raw, no glamour)
-	if a.streaming != "" {
+	// Streaming text: last markdown snapshot, raw until the first one
+	switch {
+	case a.streamRendered != "":
+		parts = append(parts, components.RenderStreamingText(a.streamRendered))
+	case a.streaming != "":
 		parts = append(parts, components.RenderStreamingText(a.streaming))
 	}
 
@@ -290,8 +458,42 @@ func (a *App) renderActive() string {
 	return "\n" + strings.Join(parts, "\n")
 }
 
+// streamRenderInterval is the minimum delay between two markdown renders of
+// the streaming text: re-rendering on every delta would flicker and burn CPU.
+const streamRenderInterval = 100 * time.Millisecond
+
+// scheduleStreamRender schedules a markdown render of the streaming text,
+// at most one per streamRenderInterval. Deltas arriving in between are
+// picked up by the pending render.
+func (a *App) scheduleStreamRender() tea.Cmd {
+	if a.streamRenderPending {
+		return nil
+	}
+	a.streamRenderPending = true
+	delay := max(streamRenderInterval-time.Since(a.streamRenderedAt), 0)
+	return tea.Tick(delay, func(time.Time) tea.Msg { return streamRenderMsg{} })
+}
+
+// renderStream refreshes the markdown snapshot of the streaming text.
+func (a *App) renderStream() {
+	if a.streaming == "" || len(a.streaming) == a.streamRenderedLen {
+		return
+	}
+	a.streamRendered = components.RenderStreamingMarkdown(a.streaming, a.width)
+	a.streamRenderedLen = len(a.streaming)
+	a.streamRenderedAt = time.Now()
+}
+
+// resetStreaming clears the streaming text and its rendered snapshot.
+func (a *App) resetStreaming() {
+	a.streaming = ""
+	a.streamRendered = ""
+	a.streamRenderedLen = 0
+}
+
 func (a *App) updateSizes() {
 	a.header.SetWidth(a.width)
+	a.tasks.SetWidth(a.width)
 	a.inputZone.SetSize(a.width, a.inputHeightForMode(a.inputZone.Mode()))
 }
 
@@ -342,7 +544,7 @@ func (a *App) handleInputResult(result components.InputResult) tea.Cmd {
 	switch result.Mode {
 	case components.ModeChat:
 		text := result.Text
-		if strings.HasPrefix(text, "/") {
+		if isSlashCommand(text) {
 			return a.handleSlashCommand(text)
 		}
 
@@ -352,7 +554,7 @@ func (a *App) handleInputResult(result components.InputResult) tea.Cmd {
 		a.inputZone.SetDisabled(true)
 		a.showThinking = true
 		a.isStreaming = true
-		a.streaming = ""
+		a.resetStreaming()
 		a.header.SetStreaming(true)
 
 		client := a.client
@@ -452,9 +654,20 @@ func (a *App) handleToolCall(msg ToolCallMsg) []tea.Cmd {
 	case string(events.ToolStatusStarted):
 		args := components.FormatArguments(msg.Arguments)
 		a.showThinking = false
+		retry := a.toolRetries[msg.Name]
+		delete(a.toolRetries, msg.Name)
+		// The call replaces its entry from the streamed arguments, if any.
+		for i := range a.activeTools {
+			if a.activeTools[i].Name == msg.Name && a.activeTools[i].StreamedArgs != "" {
+				a.activeTools = append(a.activeTools[:i], a.activeTools[i+1:]...)
+				break
+			}
+		}
 		a.activeTools = append(a.activeTools, components.ToolCall{
-			Name:      msg.Name,
-			Arguments: args,
+			Name:        msg.Name,
+			Arguments:   args,
+			Attempt:     retry.Attempt,
+			MaxAttempts: retry.MaxAttempts,
 		})
 
 	case string(events.ToolStatusCompleted):
@@ -490,6 +703,43 @@ func (a *App) handleToolCall(msg ToolCallMsg) []tea.Cmd {
 	return nil
 }
 
+// handleToolCallDelta accumulates the arguments of a tool call the model is
+// still generating, shown as a pending entry until the call starts.
+func (a *App) handleToolCallDelta(msg ToolCallDeltaMsg) {
+	if msg.Delta == "" {
+		return
+	}
+	a.showThinking = false
+	for i := range a.activeTools {
+		if a.activeTools[i].CallID == msg.CallID && a.activeTools[i].StreamedArgs != "" {
+			a.activeTools[i].StreamedArgs += msg.Delta
+			return
+		}
+	}
+	a.activeTools = append(a.activeTools, components.ToolCall{
+		Name:         msg.Name,
+		CallID:       msg.CallID,
+		StreamedArgs: msg.Delta,
+		Status:       components.ToolStatusPending,
+	})
+}
+
+// handleToolRecovery marks a tool call as being retried. The failed run is
+// usually flushed already, so the attempt is kept for the tool's next start.
+func (a *App) handleToolRecovery(msg ToolRecoveryMsg) {
+	for i := len(a.activeTools) - 1; i >= 0; i-- {
+		if a.activeTools[i].Name == msg.Name && !a.activeTools[i].Completed {
+			a.activeTools[i].Attempt = msg.Attempt
+			a.activeTools[i].MaxAttempts = msg.MaxAttempts
+			return
+		}
+	}
+	if a.toolRetries == nil {
+		a.toolRetries = make(map[string]ToolRecoveryMsg)
+	}
+	a.toolRetries[msg.Name] = msg
+}
+
 // handlePromptRequest bridges PromptRequestMsg to InputZone prompts.
 func (a *App) handlePromptRequest(msg PromptRequestMsg) []tea.Cmd {
 	// Flush active tools before showing prompt
@@ -498,7 +748,7 @@ func (a *App) handlePromptRequest(msg PromptRequestMsg) []tea.Cmd {
 	// Flush any streaming content
 	if a.streaming != "" {
 		cmds = append(cmds, tea.Println(components.RenderAssistantMessage(a.streaming, a.width)))
-		a.streaming = ""
+		a.resetStreaming()
 	}
 
 	a.isStreaming = false
@@ -538,7 +788,7 @@ func (a *App) handlePromptRequest(msg PromptRequestMsg) []tea.Cmd {
 				Description: opt.Description,
 			}
 		}
-		a.inputZone.PromptMulti(msg.Label, "", msg.Token, options)
+		a.inputZone.PromptMulti(msg.Label, "", msg.Token, options, promptDefaults(msg.Default), msg.MinSelect, msg.MaxSelect)
 	}
 
 	// Resize for the new input mode
@@ -546,6 +796,29 @@ func (a *App) handlePromptRequest(msg PromptRequestMsg) []tea.Cmd {
 	return cmds
 }
 
+// promptDefaults converts a prompt default (a single value or a JSON list) to
+// the values to pre-check in a multi-select prompt.
+func promptDefaults(def any) []string {
+	switch v := def.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
 // sendPromptCancel sends a cancellation response to the gateway.
 func (a *App) sendPromptCancel(token string) tea.Cmd {
 	client := a.client
@@ -557,6 +830,36 @@ func (a *App) sendPromptCancel(token string) tea.Cmd {
 	}
 }
 
+// slashCommands lists the commands handled by handleSlashCommand, as offered
+// by the input zone's command palette.
+var slashCommands = []components.InputOption{
+	{Value: "/artifacts", Label: "/artifacts <task_id> [name]", Description: "List a task's artifacts or save one"},
+	{Value: "/audit", Label: "/audit [count]", Description: "Show recent tool invocations"},
+	{Value: "/cancel-all", Label: "/cancel-all", Description: "Cancel this session's running tasks"},
+	{Value: "/find", Label: "/find <query>", Description: "Search past sessions by content"},
+	{Value: "/keys", Label: "/keys", Description: "List the active key bindings"},
+	{Value: "/model", Label: "/model [name]", Description: "Show or switch the session model"},
+	{Value: "/persona", Label: "/persona [text|clear]", Description: "Show or set the session persona"},
+	{Value: "/quit", Label: "/quit", Description: "Exit"},
+	{Value: "/task-graph", Label: "/task-graph", Description: "Show the session's task dependency graph"},
+	{Value: "/tasks", Label: "/tasks", Description: "Open the task panel"},
+}
+
+// isSlashCommand reports whether text invokes a known slash command. Other
+// input starting with "/" (e.g. a path) is sent as a regular message.
+func isSlashCommand(text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, c := range slashCommands {
+		if c.Value == fields[0] {
+			return true
+		}
+	}
+	return false
+}
+
 // handleSlashCommand processes slash commands.
 func (a *App) handleSlashCommand(cmd string) tea.Cmd {
 	parts := strings.Fields(cmd)
@@ -566,11 +869,217 @@ func (a *App) handleSlashCommand(cmd string) tea.Cmd {
 	case "/quit":
 		a.quitting = true
 		return tea.Quit
+	case "/keys":
+		return a.renderKeys()
+	case "/audit":
+		limit := defaultAuditLimit
+		if len(parts) > 1 {
+			n, err := strconv.Atoi(parts[1])
+			if err != nil || n <= 0 {
+				return tea.Println(components.RenderError("Usage: /audit [count]", a.width))
+			}
+			limit = n
+		}
+		client := a.client
+		return func() tea.Msg {
+			entries, err := client.GetAuditLog(limit)
+			return auditLogMsg{entries: entries, err: err}
+		}
+	case "/find":
+		query := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmd), command))
+		if query == "" {
+			return tea.Println(components.RenderError("Usage: /find <query>", a.width))
+		}
+		client := a.client
+		return func() tea.Msg {
+			matches, err := client.SearchSessions(query, "", findLimit)
+			return findMsg{query: query, matches: matches, err: err}
+		}
+	case "/model":
+		var name string
+		if len(parts) > 1 {
+			name = parts[1]
+		}
+		client := a.client
+		return func() tea.Msg {
+			sel, err := client.SetModel(name)
+			return modelMsg{selection: sel, switched: name != "", err: err}
+		}
+	case "/persona":
+		// Keep the instruction text verbatim (Fields would collapse whitespace).
+		var persona *string
+		if text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmd), command)); text != "" {
+			if text == "clear" {
+				text = ""
+			}
+			persona = &text
+		}
+		client := a.client
+		return func() tea.Msg {
+			res, err := client.SetPersona(persona)
+			return personaMsg{result: res, err: err}
+		}
+	case "/cancel-all":
+		client := a.client
+		return func() tea.Msg {
+			cancelled, err := client.CancelSessionTasks("cancelled via /cancel-all")
+			return cancelAllMsg{cancelled: cancelled, err: err}
+		}
+	case "/task-graph":
+		client := a.client
+		return func() tea.Msg {
+			graph, err := client.GetTaskGraph()
+			return taskGraphMsg{graph: graph, err: err}
+		}
+	case "/tasks":
+		if a.showTasks {
+			return nil
+		}
+		return a.toggleTasks()
+	case "/artifacts":
+		if len(parts) < 2 || len(parts) > 3 {
+			return tea.Println(components.RenderError("Usage: /artifacts <task_id> [name]", a.width))
+		}
+		taskID := parts[1]
+		client := a.client
+		if len(parts) == 2 {
+			return func() tea.Msg {
+				artifacts, err := client.ListTaskArtifacts(taskID)
+				return artifactsMsg{taskID: taskID, artifacts: artifacts, err: err}
+			}
+		}
+		name := parts[2]
+		return func() tea.Msg {
+			_, content, err := client.GetTaskArtifact(taskID, name)
+			if err != nil {
+				return artifactsMsg{taskID: taskID, err: err}
+			}
+			path, err := saveArtifact(name, content)
+			return artifactsMsg{taskID: taskID, savedTo: path, err: err}
+		}
 	default:
 		return tea.Println(components.RenderError(fmt.Sprintf("Unknown command: %s", command), a.width))
 	}
 }
 
+// renderKeys prints the active key bindings after /keys.
+func (a *App) renderKeys() tea.Cmd {
+	lines := []string{components.RenderToolLog("Key bindings:")}
+	for _, line := range a.keys.Describe() {
+		lines = append(lines, components.RenderToolLog("  "+line))
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// renderPersona prints the session persona after /persona.
+func (a *App) renderPersona(msg personaMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Persona: %v", msg.err), a.width))
+	}
+	switch {
+	case msg.result.Status == "cleared":
+		return tea.Println(components.RenderToolLog("Session persona cleared"))
+	case msg.result.Persona == "":
+		return tea.Println(components.RenderToolLog("No session persona (usage: /persona <instructions> | /persona clear)"))
+	case msg.result.Status == "set":
+		return tea.Println(components.RenderToolLog("Session persona set: " + components.TruncateString(msg.result.Persona, 80)))
+	default:
+		return tea.Println(components.RenderToolLog("Session persona: " + msg.result.Persona))
+	}
+}
+
+// renderModelSelection prints the result of /model: the new model after a
+// switch, or the current model and available providers.
+func (a *App) renderModelSelection(msg modelMsg) []tea.Cmd {
+	if msg.err != nil {
+		return []tea.Cmd{tea.Println(components.RenderError(fmt.Sprintf("Model: %v", msg.err), a.width))}
+	}
+	sel := msg.selection
+	provider := sel.Driver
+	if provider == "" {
+		provider = sel.Model // older gateways only report the provider name
+	}
+	a.header.SetProvider(provider, sel.ModelID, sel.Endpoint)
+	if msg.switched {
+		return []tea.Cmd{tea.Println(components.RenderToolLog("Model switched to " + sel.Model))}
+	}
+
+	lines := []string{components.RenderToolLog("Current model: " + sel.Model)}
+	for _, name := range sel.Models {
+		label := "  " + name
+		if name == sel.Default {
+			label += " (default)"
+		}
+		lines = append(lines, components.RenderToolLog(label))
+	}
+	return []tea.Cmd{tea.Println(strings.Join(lines, "\n"))}
+}
+
+// findLimit is the number of sessions /find lists.
+const findLimit = 10
+
+// renderFind prints the sessions matching a /find query, best first.
+func (a *App) renderFind(msg findMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Find: %v", msg.err), a.width))
+	}
+	if len(msg.matches) == 0 {
+		return tea.Println(components.RenderToolLog(fmt.Sprintf("No session matches %q", msg.query)))
+	}
+
+	lines := []string{components.RenderToolLog(fmt.Sprintf("Sessions matching %q:", msg.query))}
+	for _, m := range msg.matches {
+		label := "  " + m.SessionID + " " + m.UpdatedAt.Local().Format("2006-01-02 15:04")
+		if m.Project != "" && m.Project != "default" {
+			label += " [" + m.Project + "]"
+		}
+		if m.Title != "" {
+			label += " " + components.TruncateString(m.Title, 40)
+		}
+		lines = append(lines, components.RenderToolLog(label))
+		if m.Snippet != "" {
+			lines = append(lines, components.RenderToolLog("    "+components.TruncateString(m.Snippet, 100)))
+		}
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// defaultAuditLimit is the number of entries /audit shows without an argument.
+const defaultAuditLimit = 20
+
+// renderAuditLog prints one line per audited tool invocation, oldest first.
+func (a *App) renderAuditLog(msg auditLogMsg) []tea.Cmd {
+	if msg.err != nil {
+		return []tea.Cmd{tea.Println(components.RenderError(fmt.Sprintf("Audit log: %v", msg.err), a.width))}
+	}
+	if len(msg.entries) == 0 {
+		return []tea.Cmd{tea.Println(components.RenderToolLog("No tool calls recorded for this session"))}
+	}
+
+	lines := make([]string, 0, len(msg.entries))
+	for _, e := range msg.entries {
+		var b strings.Builder
+		b.WriteString(e.Ts.Local().Format("15:04:05") + " ")
+		if e.Autonomous {
+			b.WriteString("[auto] ")
+		}
+		if e.AutoApproved != "" {
+			b.WriteString("[auto-approved] ")
+		}
+		b.WriteString(e.Tool)
+		if e.Arguments != "" {
+			b.WriteString("(" + components.TruncateString(e.Arguments, 60) + ")")
+		}
+		if e.Success {
+			b.WriteString(fmt.Sprintf(" ok %dms", e.DurationMs))
+		} else {
+			b.WriteString(" failed: " + components.TruncateString(e.Error, 80))
+		}
+		lines = append(lines, components.RenderToolLog(b.String()))
+	}
+	return []tea.Cmd{tea.Println(strings.Join(lines, "\n"))}
+}
+
 // isMouseEscapeFragment returns true if s looks like one or more unparsed
 // SGR mouse escape sequence fragments (e.g. "[<65;80;14M" or concatenated
 // "[<65;80;14M[<64;80;14M").
@@ -588,3 +1097,285 @@ func isMouseEscapeFragment(s string) bool {
 	}
 	return true
 }
+
+// renderCancelAll prints the tasks cancelled by /cancel-all.
+func (a *App) renderCancelAll(msg cancelAllMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Cancel all: %v", msg.err), a.width))
+	}
+	if len(msg.cancelled) == 0 {
+		return tea.Println(components.RenderToolLog("No pending or running tasks to cancel"))
+	}
+
+	lines := []string{components.RenderToolLog(fmt.Sprintf("Cancelled %d task(s):", len(msg.cancelled)))}
+	for _, t := range msg.cancelled {
+		line := fmt.Sprintf("  %s  %s (was %s)", t.ID, components.TruncateString(t.Title, 60), t.Status)
+		if t.ParentID != "" {
+			line += " — child of " + t.ParentID
+		}
+		lines = append(lines, components.RenderToolLog(line))
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// renderTaskGraph prints the session's tasks as an indented parent-child tree,
+// annotated with unfinished dependencies and cycles.
+func (a *App) renderTaskGraph(msg taskGraphMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Task graph: %v", msg.err), a.width))
+	}
+	g := msg.graph
+	if len(g.Nodes) == 0 {
+		return tea.Println(components.RenderToolLog("No tasks in this session"))
+	}
+
+	nodes := make(map[string]wsclient.TaskGraphNode, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	children := map[string][]string{}
+	hasParent := map[string]bool{}
+	for _, e := range g.Edges {
+		if e.Kind == "parent" {
+			children[e.From] = append(children[e.From], e.To)
+			hasParent[e.To] = true
+		}
+	}
+
+	label := func(id string) string {
+		n := nodes[id]
+		switch {
+		case n.Missing:
+			return id + " (missing)"
+		case n.External:
+			return fmt.Sprintf("%s [%s] (other session)", components.TruncateString(n.Title, 50), n.Status)
+		default:
+			return fmt.Sprintf("%s [%s]", components.TruncateString(n.Title, 50), n.Status)
+		}
+	}
+
+	lines := []string{components.RenderToolLog(fmt.Sprintf("Task graph (%d tasks):", len(g.Nodes)))}
+	printed := map[string]bool{}
+	var walk func(id string, depth int)
+	walk = func(id string, depth int) {
+		if printed[id] {
+			return
+		}
+		printed[id] = true
+		n := nodes[id]
+		line := strings.Repeat("  ", depth+1) + "- " + label(id) + "  " + id
+		if len(n.WaitingOn) > 0 {
+			waits := make([]string, len(n.WaitingOn))
+			for i, dep := range n.WaitingOn {
+				waits[i] = label(dep)
+			}
+			line += " — waiting on " + strings.Join(waits, ", ")
+		}
+		if n.InCycle {
+			line += " — in dependency cycle"
+		}
+		lines = append(lines, components.RenderToolLog(line))
+		for _, child := range children[id] {
+			walk(child, depth+1)
+		}
+	}
+	for _, n := range g.Nodes {
+		// Out-of-session dependencies are shown on the tasks waiting on them.
+		if (n.External || n.Missing) && len(children[n.ID]) == 0 {
+			continue
+		}
+		if !hasParent[n.ID] {
+			walk(n.ID, 0)
+		}
+	}
+	// Nodes only reachable through a parent cycle have no root.
+	for _, n := range g.Nodes {
+		if !n.External && !n.Missing {
+			walk(n.ID, 0)
+		}
+	}
+
+	for _, c := range g.Cycles {
+		lines = append(lines, components.RenderError("Cycle: "+strings.Join(c, " -> ")+" -> "+c[0], a.width))
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// saveArtifact writes a downloaded artifact into the current directory,
+// refusing to overwrite an existing file.
+func saveArtifact(name string, content []byte) (string, error) {
+	path := filepath.Base(name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return filepath.Abs(path)
+}
+
+// renderArtifacts prints a task's artifacts, or where one was saved.
+func (a *App) renderArtifacts(msg artifactsMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Artifacts: %v", msg.err), a.width))
+	}
+	if msg.savedTo != "" {
+		return tea.Println(components.RenderToolLog("Artifact saved to " + msg.savedTo))
+	}
+	if len(msg.artifacts) == 0 {
+		return tea.Println(components.RenderToolLog("No artifacts for task " + msg.taskID))
+	}
+
+	lines := []string{components.RenderToolLog(fmt.Sprintf("Artifacts of %s (/artifacts %s <name> to download):", msg.taskID, msg.taskID))}
+	for _, art := range msg.artifacts {
+		line := fmt.Sprintf("  %s  %d bytes", art.Name, art.Size)
+		if art.Description != "" {
+			line += " — " + components.TruncateString(art.Description, 80)
+		}
+		lines = append(lines, components.RenderToolLog(line))
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// toggleTasks opens the task panel, refreshed with list_tasks, or closes it.
+func (a *App) toggleTasks() tea.Cmd {
+	a.showTasks = !a.showTasks
+	if !a.showTasks {
+		return nil
+	}
+	client := a.client
+	return func() tea.Msg {
+		list, err := client.ListTasks()
+		return taskListMsg{tasks: list, err: err}
+	}
+}
+
+// handleTaskPanelKey navigates the open task panel and runs its quick
+// actions on the selected task: enter checks it, c cancels it.
+func (a *App) handleTaskPanelKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch a.keys.Action(msg) {
+	case components.KeyUp:
+		a.tasks.MoveUp()
+		return nil
+	case components.KeyDown:
+		a.tasks.MoveDown()
+		return nil
+	case components.KeyCancel:
+		a.showTasks = false
+		return nil
+	}
+
+	task, ok := a.tasks.Selected()
+	if !ok {
+		return nil
+	}
+	client := a.client
+	switch {
+	case a.keys.Action(msg) == components.KeySubmit:
+		return func() tea.Msg {
+			t, err := client.CheckTask(task.ID)
+			return taskCheckMsg{task: t, err: err}
+		}
+	case msg.String() == "c":
+		if !task.Active() {
+			return tea.Println(components.RenderError(fmt.Sprintf("Task %s is already %s", task.ID, task.Status), a.width))
+		}
+		return func() tea.Msg {
+			err := client.CancelTask(task.ID, "cancelled from the task panel")
+			return taskCancelMsg{taskID: task.ID, title: task.Title, err: err}
+		}
+	}
+	return nil
+}
+
+// applyTaskUpdate merges a task event into the task panel, open or not.
+func (a *App) applyTaskUpdate(msg TaskUpdateMsg) {
+	t, _ := a.tasks.Task(msg.TaskID)
+	t.ID = msg.TaskID
+	if msg.Title != "" {
+		t.Title = msg.Title
+	}
+	if msg.Status == "" { // progress
+		t.Percentage, t.StepLabel = msg.Percentage, msg.StepLabel
+		if t.Status == "" {
+			t.Status = "running"
+		}
+	} else {
+		t.Status, t.Error = msg.Status, msg.Error
+		if msg.Status == "completed" {
+			t.Percentage = 100
+		}
+	}
+	a.tasks.UpsertTask(t)
+}
+
+// setTasks replaces the task panel content with a list_tasks result. Failure
+// reasons only come from events, so they are kept.
+func (a *App) setTasks(list []wsclient.TaskSummary) {
+	entries := make([]components.TaskEntry, len(list))
+	for i, s := range list {
+		entries[i] = taskEntry(s)
+		if prev, ok := a.tasks.Task(s.ID); ok && prev.Status == s.Status {
+			entries[i].Error = prev.Error
+		}
+	}
+	a.tasks.SetTasks(entries)
+}
+
+// taskEntry converts a task summary into a task panel entry.
+func taskEntry(s wsclient.TaskSummary) components.TaskEntry {
+	return components.TaskEntry{
+		ID:         s.ID,
+		Title:      s.Title,
+		Status:     s.Status,
+		Percentage: s.Progress.Percentage,
+		StepLabel:  s.Progress.CurrentStepLabel,
+	}
+}
+
+// renderTaskCheck prints the status of the task checked from the panel.
+func (a *App) renderTaskCheck(msg taskCheckMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Check task: %v", msg.err), a.width))
+	}
+	t := msg.task
+	entry := taskEntry(*t)
+	if prev, ok := a.tasks.Task(t.ID); ok && prev.Status == t.Status {
+		entry.Error = prev.Error
+	}
+	a.tasks.UpsertTask(entry)
+
+	line := fmt.Sprintf("Task %s (%s): %s", components.TruncateString(t.Title, 60), t.ID, t.Status)
+	if t.Status == "running" && t.Progress.TotalSteps > 0 {
+		line += fmt.Sprintf(", step %d/%d (%d%%)", t.Progress.CurrentStep, t.Progress.TotalSteps, t.Progress.Percentage)
+		if t.Progress.CurrentStepLabel != "" {
+			line += " " + t.Progress.CurrentStepLabel
+		}
+	}
+	if entry.Error != "" {
+		line += " — " + components.TruncateString(entry.Error, 80)
+	}
+	lines := []string{components.RenderToolLog(line)}
+	if len(t.Artifacts) > 0 {
+		lines = append(lines, components.RenderToolLog(fmt.Sprintf("  artifacts: %s (/artifacts %s <name> to download)", strings.Join(t.Artifacts, ", "), t.ID)))
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// renderTaskCancel prints the outcome of a cancellation from the panel.
+func (a *App) renderTaskCancel(msg taskCancelMsg) tea.Cmd {
+	if msg.err != nil {
+		return tea.Println(components.RenderError(fmt.Sprintf("Cancel task: %v", msg.err), a.width))
+	}
+	a.applyTaskUpdate(TaskUpdateMsg{TaskID: msg.taskID, Status: "cancelled"})
+	label := msg.title
+	if label == "" {
+		label = msg.taskID
+	}
+	return tea.Println(components.RenderToolLog("Cancelled task " + components.TruncateString(label, 60)))
+}
@@ -19,6 +19,9 @@ type StreamDeltaMsg struct {
 // StreamEndMsg signals the end of streaming.
 type StreamEndMsg struct{}
 
+// streamRenderMsg triggers a throttled markdown render of the streaming text.
+type streamRenderMsg struct{}
+
 // AssistantMessageMsg carries a complete (non-streamed) assistant response.
 type AssistantMessageMsg struct {
 	Content string
@@ -34,6 +37,21 @@ type ToolCallMsg struct {
 	Error     string
 }
 
+// ToolCallDeltaMsg carries a fragment of a tool call's arguments while the
+// model is still generating them.
+type ToolCallDeltaMsg struct {
+	CallID string
+	Name   string
+	Delta  string
+}
+
+// ToolRecoveryMsg reports that a failing tool call is being retried.
+type ToolRecoveryMsg struct {
+	Name        string
+	Attempt     int
+	MaxAttempts int
+}
+
 // PromptRequestMsg asks the user for interactive input.
 type PromptRequestMsg struct {
 	Type        string
@@ -42,6 +60,7 @@ type PromptRequestMsg struct {
 	Token       string
 	HelpText    string
 	Placeholder string
+	Default     any
 	MinSelect   int
 	MaxSelect   int
 }
@@ -62,6 +81,25 @@ type UserSubmitMsg struct {
 	Content string
 }
 
+// TaskNarrationMsg carries interim output of a verbose background task.
+type TaskNarrationMsg struct {
+	TaskID string
+	Title  string
+	Text   string
+}
+
+// TaskUpdateMsg carries a task lifecycle event (task.created, task.started,
+// task.progress, task.completed, task.failed, task.cancelled). Empty fields
+// are not reported by the event.
+type TaskUpdateMsg struct {
+	TaskID     string
+	Title      string
+	Status     string
+	Percentage int
+	StepLabel  string
+	Error      string
+}
+
 // SkillStartedMsg signals the start of a skill execution.
 type SkillStartedMsg struct {
 	Name string
@@ -102,6 +140,72 @@ type HistoryMessage struct {
 	Content string
 }
 
+// auditLogMsg carries the result of a /audit request.
+type auditLogMsg struct {
+	entries []wsclient.AuditEntry
+	err     error
+}
+
+// findMsg carries the result of a /find request.
+type findMsg struct {
+	query   string
+	matches []wsclient.SessionMatch
+	err     error
+}
+
+// modelMsg carries the result of a /model request.
+type modelMsg struct {
+	selection *wsclient.ModelSelection
+	switched  bool // a model name was given
+	err       error
+}
+
+// personaMsg carries the result of a /persona request.
+type personaMsg struct {
+	result *wsclient.PersonaResult
+	err    error
+}
+
+// cancelAllMsg carries the result of a /cancel-all request.
+type cancelAllMsg struct {
+	cancelled []wsclient.CancelledTask
+	err       error
+}
+
+// taskGraphMsg carries the result of a /task-graph request.
+type taskGraphMsg struct {
+	graph *wsclient.TaskGraph
+	err   error
+}
+
+// taskListMsg carries the list_tasks result fetched when the task panel opens.
+type taskListMsg struct {
+	tasks []wsclient.TaskSummary
+	err   error
+}
+
+// taskCheckMsg carries the result of checking the task selected in the panel.
+type taskCheckMsg struct {
+	task *wsclient.TaskSummary
+	err  error
+}
+
+// taskCancelMsg carries the result of cancelling the task selected in the panel.
+type taskCancelMsg struct {
+	taskID string
+	title  string
+	err    error
+}
+
+// artifactsMsg carries the result of a /artifacts request: the artifact list,
+// or the path an artifact was saved to.
+type artifactsMsg struct {
+	taskID    string
+	artifacts []wsclient.TaskArtifact
+	savedTo   string
+	err       error
+}
+
 // sendErrorMsg carries an error from an async WS send.
 type sendErrorMsg struct {
 	err error
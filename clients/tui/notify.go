@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// NotifyMode selects how the TUI alerts the user when a task or skill
+// finishes while they are doing something else.
+type NotifyMode string
+
+const (
+	NotifyOff     NotifyMode = "off"
+	NotifyBell    NotifyMode = "bell"    // terminal bell only
+	NotifyDesktop NotifyMode = "desktop" // bell + OSC 9 / OSC 777 desktop notification
+)
+
+// focusState is the terminal focus, as reported by terminals that support
+// focus events.
+type focusState int
+
+const (
+	focusUnknown focusState = iota
+	focusIn
+	focusOut
+)
+
+// shouldNotify reports whether finished work deserves an alert. A terminal
+// known to be in focus needs none, one known to be out of focus always
+// does; otherwise only work outside the current turn (background) alerts.
+func (a *App) shouldNotify(background bool) bool {
+	if a.notify != NotifyBell && a.notify != NotifyDesktop {
+		return false
+	}
+	switch a.focus {
+	case focusIn:
+		return false
+	case focusOut:
+		return true
+	default:
+		return background
+	}
+}
+
+// notifyDone alerts that a task or skill finished, when shouldNotify allows it.
+func (a *App) notifyDone(background bool, status, label string) tea.Cmd {
+	if !a.shouldNotify(background) {
+		return nil
+	}
+	return tea.Raw(notificationSeq(a.notify, "Ozzie: "+status, label))
+}
+
+// notificationSeq returns the escape sequence alerting about finished work:
+// a bell, preceded in desktop mode by OSC 9 (iTerm2, WezTerm, Windows
+// Terminal) and OSC 777 (foot, Ghostty, rxvt) notifications. Terminals
+// ignore the OSC they do not support.
+func notificationSeq(mode NotifyMode, title, body string) string {
+	const bell = "\a"
+	if mode != NotifyDesktop {
+		return bell
+	}
+	title, body = sanitizeOSC(title), sanitizeOSC(body)
+	return "\x1b]9;" + title + ": " + body + bell +
+		"\x1b]777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + bell +
+		bell
+}
+
+// sanitizeOSC drops the control characters that would end an OSC sequence
+// early or inject another one.
+func sanitizeOSC(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,36 @@
+package tui
+
+import "testing"
+
+func TestNotificationSeq(t *testing.T) {
+	if got := notificationSeq(NotifyBell, "Ozzie: task completed", "build"); got != "\a" {
+		t.Errorf("bell mode = %q, want a bell only", got)
+	}
+	got := notificationSeq(NotifyDesktop, "Ozzie: a;b", "evil\x1b]0;x\a\nbody")
+	want := "\x1b]9;Ozzie: a;b: evil]0;xbody\a" + "\x1b]777;notify;Ozzie: a,b;evil]0;xbody\a" + "\a"
+	if got != want {
+		t.Errorf("desktop mode = %q, want %q", got, want)
+	}
+}
+
+func TestShouldNotify(t *testing.T) {
+	tests := []struct {
+		mode       NotifyMode
+		focus      focusState
+		background bool
+		want       bool
+	}{
+		{NotifyOff, focusOut, true, false},
+		{"", focusOut, true, false},
+		{NotifyBell, focusIn, true, false},
+		{NotifyBell, focusOut, false, true},
+		{NotifyDesktop, focusUnknown, true, true},
+		{NotifyDesktop, focusUnknown, false, false},
+	}
+	for _, tt := range tests {
+		a := &App{notify: tt.mode, focus: tt.focus}
+		if got := a.shouldNotify(tt.background); got != tt.want {
+			t.Errorf("shouldNotify(mode=%q focus=%d background=%v) = %v, want %v", tt.mode, tt.focus, tt.background, got, tt.want)
+		}
+	}
+}
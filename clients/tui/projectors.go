@@ -23,10 +23,19 @@ func Project(frame ws.Frame) tea.Msg {
 		return projectAssistantMessage(frame)
 	case events.EventToolCall:
 		return projectToolCall(frame)
+	case events.EventToolCallDelta:
+		return projectToolCallDelta(frame)
+	case events.EventToolRecovery:
+		return projectToolRecovery(frame)
 	case events.EventPromptRequest:
 		return projectPromptRequest(frame)
 	case events.EventLLMCall:
 		return projectLLMCall(frame)
+	case events.EventTaskNarration:
+		return projectTaskNarration(frame)
+	case events.EventTaskCreated, events.EventTaskStarted, events.EventTaskProgress,
+		events.EventTaskCompleted, events.EventTaskFailed, events.EventTaskCancelled:
+		return projectTaskUpdate(frame)
 	case events.EventSkillStarted:
 		return projectSkillStarted(frame)
 	case events.EventSkillCompleted:
@@ -91,6 +100,30 @@ func projectToolCall(frame ws.Frame) tea.Msg {
 	}
 }
 
+func projectToolCallDelta(frame ws.Frame) tea.Msg {
+	var evt events.Event
+	if err := json.Unmarshal(frame.Payload, &evt); err != nil {
+		return nil
+	}
+	payload, ok := events.GetToolCallDeltaPayload(evt)
+	if !ok {
+		return nil
+	}
+	return ToolCallDeltaMsg{CallID: payload.CallID, Name: payload.Name, Delta: payload.Delta}
+}
+
+func projectToolRecovery(frame ws.Frame) tea.Msg {
+	var evt events.Event
+	if err := json.Unmarshal(frame.Payload, &evt); err != nil {
+		return nil
+	}
+	payload, ok := events.GetToolRecoveryPayload(evt)
+	if !ok {
+		return nil
+	}
+	return ToolRecoveryMsg{Name: payload.ToolName, Attempt: payload.Attempt, MaxAttempts: payload.MaxAttempts}
+}
+
 func projectPromptRequest(frame ws.Frame) tea.Msg {
 	var evt events.Event
 	if err := json.Unmarshal(frame.Payload, &evt); err != nil {
@@ -107,6 +140,7 @@ func projectPromptRequest(frame ws.Frame) tea.Msg {
 		Token:       payload.Token,
 		HelpText:    payload.HelpText,
 		Placeholder: payload.Placeholder,
+		Default:     payload.Default,
 		MinSelect:   payload.MinSelect,
 		MaxSelect:   payload.MaxSelect,
 	}
@@ -131,6 +165,56 @@ func projectLLMCall(frame ws.Frame) tea.Msg {
 	}
 }
 
+func projectTaskNarration(frame ws.Frame) tea.Msg {
+	var evt events.Event
+	if err := json.Unmarshal(frame.Payload, &evt); err != nil {
+		return nil
+	}
+	payload, ok := events.GetTaskNarrationPayload(evt)
+	if !ok {
+		return nil
+	}
+	return TaskNarrationMsg{TaskID: payload.TaskID, Title: payload.Title, Text: payload.Text}
+}
+
+func projectTaskUpdate(frame ws.Frame) tea.Msg {
+	var evt events.Event
+	if err := json.Unmarshal(frame.Payload, &evt); err != nil {
+		return nil
+	}
+	switch evt.Type {
+	case events.EventTaskCreated:
+		if p, ok := events.GetTaskCreatedPayload(evt); ok {
+			return TaskUpdateMsg{TaskID: p.TaskID, Title: p.Title, Status: "pending"}
+		}
+	case events.EventTaskStarted:
+		if p, ok := events.GetTaskStartedPayload(evt); ok {
+			return TaskUpdateMsg{TaskID: p.TaskID, Title: p.Title, Status: "running"}
+		}
+	case events.EventTaskProgress:
+		if p, ok := events.GetTaskProgressPayload(evt); ok {
+			return TaskUpdateMsg{TaskID: p.TaskID, Percentage: p.Percentage, StepLabel: p.CurrentStepLabel}
+		}
+	case events.EventTaskCompleted:
+		if p, ok := events.GetTaskCompletedPayload(evt); ok {
+			return TaskUpdateMsg{TaskID: p.TaskID, Title: p.Title, Status: "completed"}
+		}
+	case events.EventTaskFailed:
+		if p, ok := events.GetTaskFailedPayload(evt); ok {
+			status := "failed"
+			if p.WillRetry {
+				status = "pending"
+			}
+			return TaskUpdateMsg{TaskID: p.TaskID, Title: p.Title, Status: status, Error: p.Error}
+		}
+	case events.EventTaskCancelled:
+		if p, ok := events.GetTaskCancelledPayload(evt); ok {
+			return TaskUpdateMsg{TaskID: p.TaskID, Status: "cancelled"}
+		}
+	}
+	return nil
+}
+
 func projectSkillStarted(frame ws.Frame) tea.Msg {
 	var evt events.Event
 	if err := json.Unmarshal(frame.Payload, &evt); err != nil {
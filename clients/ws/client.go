@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync/atomic"
+	"time"
 
 	"github.com/coder/websocket"
 
@@ -17,7 +18,8 @@ import (
 type DialOption func(*dialConfig)
 
 type dialConfig struct {
-	token string
+	token    string
+	encoding wsprotocol.Encoding
 }
 
 // WithToken sets the bearer token for authentication.
@@ -25,12 +27,19 @@ func WithToken(token string) DialOption {
 	return func(c *dialConfig) { c.token = token }
 }
 
+// WithEncoding requests a frame encoding at handshake. The gateway may
+// decline it, in which case the connection falls back to JSON.
+func WithEncoding(enc wsprotocol.Encoding) DialOption {
+	return func(c *dialConfig) { c.encoding = enc }
+}
+
 // Client is a WebSocket client for the Ozzie gateway.
 type Client struct {
 	conn      *websocket.Conn
 	reqSeq    uint64
 	ctx       context.Context
 	cancel    context.CancelFunc
+	encoding  wsprotocol.Encoding
 	SessionID string
 }
 
@@ -41,14 +50,15 @@ func Dial(ctx context.Context, url string, opts ...DialOption) (*Client, error)
 		o(cfg)
 	}
 
-	var wsOpts *websocket.DialOptions
+	wsOpts := &websocket.DialOptions{}
 	if cfg.token != "" {
-		wsOpts = &websocket.DialOptions{
-			HTTPHeader: http.Header{
-				"Authorization": []string{"Bearer " + cfg.token},
-			},
+		wsOpts.HTTPHeader = http.Header{
+			"Authorization": []string{"Bearer " + cfg.token},
 		}
 	}
+	if cfg.encoding != "" && cfg.encoding != wsprotocol.EncodingJSON {
+		wsOpts.Subprotocols = []string{cfg.encoding.Subprotocol(), wsprotocol.SubprotocolJSON}
+	}
 
 	conn, _, err := websocket.Dial(ctx, url, wsOpts)
 	if err != nil {
@@ -58,12 +68,18 @@ func Dial(ctx context.Context, url string, opts ...DialOption) (*Client, error)
 	clientCtx, cancel := context.WithCancel(ctx)
 
 	return &Client{
-		conn:   conn,
-		ctx:    clientCtx,
-		cancel: cancel,
+		conn:     conn,
+		ctx:      clientCtx,
+		cancel:   cancel,
+		encoding: wsprotocol.EncodingForSubprotocol(conn.Subprotocol()),
 	}, nil
 }
 
+// Encoding returns the frame encoding negotiated with the gateway.
+func (c *Client) Encoding() wsprotocol.Encoding {
+	return c.encoding
+}
+
 // sendFire marshals and sends a request frame (fire-and-forget).
 func (c *Client) sendFire(method string, params any) error {
 	seq := atomic.AddUint64(&c.reqSeq, 1)
@@ -84,12 +100,12 @@ func (c *Client) sendFire(method string, params any) error {
 		Params: rawParams,
 	}
 
-	data, err := wsprotocol.MarshalFrame(frame)
+	data, err := wsprotocol.EncodeFrame(frame, c.encoding)
 	if err != nil {
 		return fmt.Errorf("marshal %s: %w", method, err)
 	}
 
-	return c.conn.Write(c.ctx, websocket.MessageText, data)
+	return c.conn.Write(c.ctx, c.encoding.MessageType(), data)
 }
 
 // sendRequest sends a request and waits for the response.
@@ -114,6 +130,7 @@ func (c *Client) sendRequest(method string, params any) (wsprotocol.Frame, error
 type OpenSessionOpts struct {
 	SessionID string `json:"session_id,omitempty"`
 	RootDir   string `json:"root_dir,omitempty"`
+	Confined  bool   `json:"confined,omitempty"` // jail filesystem/exec tools to RootDir
 }
 
 // OpenSession sends an open_session request and returns the session ID.
@@ -143,6 +160,12 @@ func (c *Client) SendMessage(content string) error {
 	return c.sendFire(string(wsprotocol.MethodSendMessage), map[string]string{"content": content})
 }
 
+// Interrupt stops the session's running turn. A non-empty content starts a
+// new turn with it right away; the partial answer is kept as context.
+func (c *Client) Interrupt(content string) error {
+	return c.sendFire(string(wsprotocol.MethodInterrupt), map[string]string{"content": content})
+}
+
 // RespondToPrompt sends a prompt_response to confirm or deny a tool execution.
 func (c *Client) RespondToPrompt(token string, cancelled bool) error {
 	return c.sendFire(string(wsprotocol.MethodPromptResponse), map[string]any{
@@ -198,13 +221,334 @@ func (c *Client) LoadMessages(limit int) ([]HistoryMessage, error) {
 	return msgs, nil
 }
 
+// AuditEntry is a tool invocation returned by GetAuditLog.
+type AuditEntry struct {
+	Ts         time.Time `json:"ts"`
+	SessionID  string    `json:"session_id,omitempty"`
+	TaskID     string    `json:"task_id,omitempty"`
+	CallID     string    `json:"call_id,omitempty"`
+	Tool       string    `json:"tool"`
+	Arguments  string    `json:"arguments,omitempty"`
+	Result     string    `json:"result,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	Autonomous bool      `json:"autonomous"`
+	DurationMs int64     `json:"duration_ms"`
+	// AutoApproved names the auto-approve rule that skipped confirmation, if any.
+	AutoApproved string `json:"auto_approved,omitempty"`
+}
+
+// GetAuditLog fetches the last N tool invocations of the current session.
+func (c *Client) GetAuditLog(limit int) ([]AuditEntry, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodGetAuditLog), map[string]int{"limit": limit})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &entries); err != nil {
+			return nil, fmt.Errorf("unmarshal audit log: %w", err)
+		}
+	}
+
+	return entries, nil
+}
+
+// SessionMatch is a session returned by SearchSessions.
+type SessionMatch struct {
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	Score     float64   `json:"score"`
+	Snippet   string    `json:"snippet,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SearchSessions finds past sessions by content, best matches first.
+// A non-empty project restricts the search to that project.
+func (c *Client) SearchSessions(query, project string, limit int) ([]SessionMatch, error) {
+	params := map[string]any{"query": query, "limit": limit}
+	if project != "" {
+		params["project"] = project
+	}
+	resp, err := c.sendRequest(string(wsprotocol.MethodSearchSessions), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SessionMatch
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &matches); err != nil {
+			return nil, fmt.Errorf("unmarshal search results: %w", err)
+		}
+	}
+
+	return matches, nil
+}
+
+// ModelSelection is the session's model and the providers it can switch to.
+type ModelSelection struct {
+	Model    string   `json:"model"`
+	Driver   string   `json:"driver"`
+	ModelID  string   `json:"model_id"`
+	Endpoint string   `json:"endpoint"`
+	Default  string   `json:"default"`
+	Models   []string `json:"models"`
+}
+
+// SetModel switches the current session to the named provider. An empty name
+// leaves the selection unchanged and only returns it.
+func (c *Client) SetModel(name string) (*ModelSelection, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodSetModel), map[string]string{"model": name})
+	if err != nil {
+		return nil, err
+	}
+
+	var sel ModelSelection
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &sel); err != nil {
+			return nil, fmt.Errorf("unmarshal model selection: %w", err)
+		}
+	}
+
+	return &sel, nil
+}
+
+// PersonaResult is the session's persona override after a set_persona request.
+type PersonaResult struct {
+	Status  string `json:"status"` // "set", "cleared" or "unchanged"
+	Persona string `json:"persona"`
+}
+
+// SetPersona sets the current session's persona override. An empty persona
+// clears it; nil leaves it unchanged and only returns it.
+func (c *Client) SetPersona(persona *string) (*PersonaResult, error) {
+	params := map[string]any{}
+	if persona != nil {
+		params["persona"] = *persona
+	}
+	resp, err := c.sendRequest(string(wsprotocol.MethodSetPersona), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res PersonaResult
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &res); err != nil {
+			return nil, fmt.Errorf("unmarshal persona: %w", err)
+		}
+	}
+
+	return &res, nil
+}
+
+// Subscribe restricts the events pushed to this connection to the given
+// event types or patterns (e.g. "task.*"). No pattern, or "*", restores the
+// default of receiving every event. Returns the active subscription.
+func (c *Client) Subscribe(patterns ...string) ([]string, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodSubscribe), map[string][]string{"events": patterns})
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Events []string `json:"events"`
+	}
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &res); err != nil {
+			return nil, fmt.Errorf("unmarshal subscription: %w", err)
+		}
+	}
+
+	return res.Events, nil
+}
+
+// TaskProgress is the step-level progress of a task.
+type TaskProgress struct {
+	CurrentStep      int    `json:"current_step"`
+	TotalSteps       int    `json:"total_steps"`
+	CurrentStepLabel string `json:"current_step_label"`
+	Percentage       int    `json:"percentage"`
+}
+
+// TaskSummary is a task as returned by ListTasks and CheckTask.
+type TaskSummary struct {
+	ID        string       `json:"id"`
+	Title     string       `json:"title"`
+	Status    string       `json:"status"`
+	Progress  TaskProgress `json:"progress"`
+	Artifacts []string     `json:"artifacts"` // CheckTask only
+}
+
+// ListTasks lists the tasks of the current session.
+func (c *Client) ListTasks() ([]TaskSummary, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodListTasks), map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	var list []TaskSummary
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &list); err != nil {
+			return nil, fmt.Errorf("unmarshal tasks: %w", err)
+		}
+	}
+
+	return list, nil
+}
+
+// CheckTask fetches the current status of a task.
+func (c *Client) CheckTask(taskID string) (*TaskSummary, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodCheckTask), map[string]string{"task_id": taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	var t TaskSummary
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &t); err != nil {
+			return nil, fmt.Errorf("unmarshal task: %w", err)
+		}
+	}
+
+	return &t, nil
+}
+
+// CancelTask cancels a pending or running task.
+func (c *Client) CancelTask(taskID, reason string) error {
+	_, err := c.sendRequest(string(wsprotocol.MethodCancelTask), map[string]string{"task_id": taskID, "reason": reason})
+	return err
+}
+
+// CancelledTask is a task cancelled by CancelSessionTasks.
+type CancelledTask struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"` // status before cancellation
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// CancelSessionTasks cancels every pending or running task of the current
+// session, descendants included, and returns the cancelled tasks.
+func (c *Client) CancelSessionTasks(reason string) ([]CancelledTask, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodCancelSessionTasks), map[string]string{"reason": reason})
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Cancelled []CancelledTask `json:"cancelled"`
+	}
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &res); err != nil {
+			return nil, fmt.Errorf("unmarshal cancelled tasks: %w", err)
+		}
+	}
+
+	return res.Cancelled, nil
+}
+
+// TaskGraphNode is a task of a TaskGraph.
+type TaskGraphNode struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	External  bool     `json:"external"`
+	Missing   bool     `json:"missing"`
+	WaitingOn []string `json:"waiting_on"`
+	InCycle   bool     `json:"in_cycle"`
+}
+
+// TaskGraphEdge links two tasks: "parent" (From is the parent of To) or
+// "depends_on" (To depends on From).
+type TaskGraphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Kind    string `json:"kind"`
+	InCycle bool   `json:"in_cycle"`
+}
+
+// TaskGraph is the parent-child and dependency graph of a session's tasks.
+type TaskGraph struct {
+	SessionID string          `json:"session_id"`
+	Nodes     []TaskGraphNode `json:"nodes"`
+	Edges     []TaskGraphEdge `json:"edges"`
+	Cycles    [][]string      `json:"cycles"`
+}
+
+// GetTaskGraph fetches the task graph of the current session.
+func (c *Client) GetTaskGraph() (*TaskGraph, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodTaskGraph), map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	var g TaskGraph
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &g); err != nil {
+			return nil, fmt.Errorf("unmarshal task graph: %w", err)
+		}
+	}
+
+	return &g, nil
+}
+
+// TaskArtifact is a file registered by a task.
+type TaskArtifact struct {
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Description string    `json:"description"`
+	SourcePath  string    `json:"source_path"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListTaskArtifacts lists the artifacts registered by a task.
+func (c *Client) ListTaskArtifacts(taskID string) ([]TaskArtifact, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodGetTaskArtifacts), map[string]string{"task_id": taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []TaskArtifact
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &artifacts); err != nil {
+			return nil, fmt.Errorf("unmarshal artifacts: %w", err)
+		}
+	}
+
+	return artifacts, nil
+}
+
+// GetTaskArtifact fetches one artifact of a task with its content.
+func (c *Client) GetTaskArtifact(taskID, name string) (*TaskArtifact, []byte, error) {
+	resp, err := c.sendRequest(string(wsprotocol.MethodGetTaskArtifacts), map[string]string{"task_id": taskID, "name": name})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var res struct {
+		Artifact TaskArtifact `json:"artifact"`
+		Content  []byte       `json:"content"`
+	}
+	if resp.Payload != nil {
+		if err := json.Unmarshal(resp.Payload, &res); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal artifact: %w", err)
+		}
+	}
+
+	return &res.Artifact, res.Content, nil
+}
+
 // ReadFrame reads the next frame from the connection.
+// Both JSON (text) and MessagePack (binary) messages are accepted.
 func (c *Client) ReadFrame() (wsprotocol.Frame, error) {
-	_, data, err := c.conn.Read(c.ctx)
+	typ, data, err := c.conn.Read(c.ctx)
 	if err != nil {
 		return wsprotocol.Frame{}, err
 	}
-	return wsprotocol.UnmarshalFrame(data)
+	return wsprotocol.DecodeMessage(typ, data)
 }
 
 // Close gracefully closes the connection.
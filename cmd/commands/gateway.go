@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/urfave/cli/v3"
 
 	"github.com/dohr-michael/ozzie/internal/core/brain"
@@ -25,6 +27,8 @@ import (
 	layeredctx "github.com/dohr-michael/ozzie/internal/core/layered"
 	"github.com/dohr-michael/ozzie/internal/infra/models"
 	"github.com/dohr-michael/ozzie/internal/infra/hands"
+	ozziemcp "github.com/dohr-michael/ozzie/internal/infra/mcp"
+	"github.com/dohr-michael/ozzie/internal/infra/metrics"
 	"github.com/dohr-michael/ozzie/internal/core/policy"
 	"github.com/dohr-michael/ozzie/internal/infra/scheduler"
 	"github.com/dohr-michael/ozzie/internal/infra/secrets"
@@ -52,6 +56,10 @@ func NewGatewayCommand() *cli.Command {
 				Name:  "insecure",
 				Usage: "Disable authentication (dev mode only)",
 			},
+			&cli.BoolFlag{
+				Name:  "reindex",
+				Usage: "Rebuild memory embeddings if the embedding model changed",
+			},
 		},
 		Action: runGateway,
 	}
@@ -66,9 +74,11 @@ type gateway struct {
 	cfg      *config.Config
 	kr       *secrets.KeyRing
 	reloader *config.Reloader
+	logLevel *slog.LevelVar
 
 	// Infra
-	bus events.EventBus
+	bus     events.EventBus
+	journal *events.Journal
 
 	// Models
 	registry    *models.Registry
@@ -78,8 +88,15 @@ type gateway struct {
 	// Tools
 	toolRegistry *hands.ToolRegistry
 	toolPerms    *conscience.ToolPermissions
+	toolMetrics  *hands.ToolMetrics
+	toolLimiter  *hands.ToolLimiter
 	toolSet      *brain.ToolSet
 	tmpDir       string
+	sandboxPaths *conscience.PathList
+	submitGuard  *tasks.SubmissionGuard
+	// Sandbox allowlists shared by every guard, replaced on config reload
+	sandboxCommands *conscience.Allowlist
+	sandboxHosts    *conscience.Allowlist
 
 	// Skills
 	skillRegistry *skills.Registry
@@ -88,13 +105,14 @@ type gateway struct {
 	skillDescs    map[string]string
 
 	// Stores
-	sessionStore sessions.Store
+	sessionStore *sessions.FileStore
 	taskStore    tasks.Store
 
 	// Memory
 	memoryStore     *memory.SQLiteStore
 	memoryRetriever *memory.HybridRetriever
 	pipeline        *memory.Pipeline
+	sessionSearcher *sessions.SemanticSearcher
 	embFingerprint  string
 
 	// Policy
@@ -127,7 +145,6 @@ func runGateway(parentCtx context.Context, cmd *cli.Command) error {
 	ctx, stop := signal.NotifyContext(parentCtx, os.Interrupt)
 	defer stop()
 	g.ctx = ctx
-	g.startSIGHUP()
 
 	if err := g.initInfra(); err != nil {
 		return err
@@ -136,12 +153,10 @@ func runGateway(parentCtx context.Context, cmd *cli.Command) error {
 	if err := g.initModels(); err != nil {
 		return err
 	}
+	g.startSIGHUP()
 	if err := g.initToolPipeline(); err != nil {
 		return err
 	}
-	if err := g.initSkills(); err != nil {
-		return err
-	}
 	if err := g.initStores(); err != nil {
 		return err
 	}
@@ -149,10 +164,14 @@ func runGateway(parentCtx context.Context, cmd *cli.Command) error {
 	if err := g.initMemory(); err != nil {
 		return err
 	}
+	if err := g.initSkills(); err != nil {
+		return err
+	}
 	if err := g.initRuntime(); err != nil {
 		return err
 	}
 	g.registerTools()
+	g.watchReloadable()
 	if err := g.initAgent(); err != nil {
 		return err
 	}
@@ -191,6 +210,43 @@ func (g *gateway) serve() error {
 	taskHandler := ozzieGateway.NewWSTaskHandler(g.pool)
 	server.SetTaskHandler(taskHandler)
 
+	// Admin methods (provider key rotation) and per-session model selection
+	server.SetAdminHandler(g)
+	server.SetModelHandler(g)
+	server.SetConfineInteractive(g.cfg.Sandbox.ConfineInteractive)
+
+	// Semantic session search (substring match without embeddings)
+	if g.sessionSearcher != nil {
+		server.SetSessionSearcher(g.sessionSearcher)
+	}
+
+	// Tool registry over MCP, dangerous tools limited to pre-approved ones
+	if g.cfg.Gateway.MCP {
+		mcpServer := ozziemcp.NewMCPServer(g.toolRegistry, "", ozziemcp.WithDangerousGate(g.toolPerms))
+		server.SetMCPHandler(mcpsdk.NewStreamableHTTPHandler(
+			func(*http.Request) *mcpsdk.Server { return mcpServer }, nil))
+		slog.Info("mcp endpoint enabled", "path", "/api/mcp")
+	}
+
+	// Prometheus metrics
+	if g.cfg.Gateway.Metrics {
+		exporter := metrics.NewExporter(metrics.Sources{
+			Bus:       g.bus,
+			Tools:     g.toolMetrics.Stats,
+			Actors:    g.pool.Load,
+			Tasks:     g.pool.Store(),
+			WSClients: server.WSClientCount,
+		})
+		g.closers = append(g.closers, exporter.Close)
+		server.SetMetricsHandler(exporter, g.cfg.Gateway.MetricsPublic)
+		slog.Info("metrics endpoint enabled", "path", "/metrics", "public", g.cfg.Gateway.MetricsPublic)
+	}
+
+	// Auto-close sessions left idle
+	if d := g.cfg.Gateway.SessionIdleTimeout.Duration(); d > 0 {
+		server.StartIdleReaper(g.ctx, d)
+	}
+
 	// Start server in goroutine
 	errCh := make(chan error, 1)
 	go func() {
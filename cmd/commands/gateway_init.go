@@ -2,15 +2,21 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/cloudwego/eino/adk"
 	einoCallbacks "github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 
 	einoFs "github.com/cloudwego/eino/adk/middlewares/filesystem"
@@ -68,6 +74,9 @@ func (g *gateway) loadConfig() error {
 		loadOpts = append(loadOpts, config.WithDecrypt(decryptFn))
 	}
 	cfg, err := config.Load(configPath, loadOpts...)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("load config %s: %w", configPath, err)
+	}
 	if err != nil {
 		slog.Warn("config not found, using defaults", "path", configPath, "error", err)
 		cfg = &config.Config{}
@@ -78,12 +87,14 @@ func (g *gateway) loadConfig() error {
 	}
 	g.cfg = cfg
 
-	// Setup log level: config value, with --debug CLI override
+	// Setup log level and format: config values, with --debug CLI override
 	logLevel := introspection.ResolveLogLevel(cfg.Events.LogLevel)
 	if g.cmd.Bool("debug") {
 		logLevel = slog.LevelDebug
 	}
-	introspection.SetupLogger(logLevel)
+	g.logLevel = new(slog.LevelVar)
+	g.logLevel.Set(logLevel)
+	introspection.SetupLogger(g.logLevel, cfg.Events.LogFormat)
 
 	// CLI flags override config
 	if g.cmd.IsSet("host") {
@@ -93,29 +104,131 @@ func (g *gateway) loadConfig() error {
 		cfg.Gateway.Port = g.cmd.Int("port")
 	}
 
+	// Report every config problem at once; refuse to start on errors
+	var verr *config.ValidationError
+	if errors.As(config.Validate(cfg), &verr) {
+		for _, w := range verr.Warnings {
+			slog.Warn("config: " + w)
+		}
+		for _, e := range verr.Errors {
+			slog.Error("config: " + e)
+		}
+		if verr.Fatal() {
+			return fmt.Errorf("invalid config %s: %d error(s), see log", configPath, len(verr.Errors))
+		}
+	}
+
 	// Config reloader with decrypt
 	g.reloader = config.NewReloader(configPath, config.DotenvPath(), cfg, decryptFn)
 	return nil
 }
 
 // startSIGHUP launches a goroutine that triggers config hot-reload on SIGHUP.
+// Must be called once the model registry exists.
 func (g *gateway) startSIGHUP() {
 	sighupCh := make(chan os.Signal, 1)
 	signal.Notify(sighupCh, syscall.SIGHUP)
 	go func() {
 		for range sighupCh {
-			if err := g.reloader.Reload(); err != nil {
+			if _, err := g.ReloadAuth(); err != nil {
 				slog.Error("config reload failed", "error", err)
 			}
 		}
 	}()
 }
 
+// ReloadAuth re-reads .env and the config file (notifying reload hooks), then
+// rebuilds the model clients whose credentials changed, so a rotated provider
+// key takes effect without restarting the gateway or dropping sessions.
+func (g *gateway) ReloadAuth() ([]string, error) {
+	if err := g.reloader.Reload(); err != nil {
+		return nil, err
+	}
+	rotated := g.registry.ReloadAuth()
+	if len(rotated) > 0 {
+		slog.Info("provider credentials rotated", "providers", rotated)
+	}
+	return rotated, nil
+}
+
+// watchReloadable applies the hot-reloadable config subset in place on
+// reload: log level, globally allowed dangerous tools, sandbox allowed paths
+// and task submission limits. Changes to other startup-only settings are
+// logged as requiring a restart.
+func (g *gateway) watchReloadable() {
+	g.reloader.OnReload(func(newCfg *config.Config) {
+		if !g.cmd.Bool("debug") {
+			g.logLevel.Set(introspection.ResolveLogLevel(newCfg.Events.LogLevel))
+		}
+		g.toolPerms.SetGlobalAllowed(newCfg.Tools.AllowedDangerous)
+		if rules, err := autoApproveRules(newCfg.Tools.AutoApprove); err != nil {
+			slog.Warn("config reload: auto-approve rules not applied", "error", err)
+		} else {
+			g.toolPerms.SetAutoApproveRules(rules)
+		}
+		if g.sandboxPaths != nil {
+			g.sandboxPaths.Set(append([]string{g.tmpDir}, newCfg.Sandbox.AllowedPaths...))
+			g.sandboxCommands.Set(newCfg.Sandbox.AllowedCommands)
+			g.sandboxHosts.Set(newCfg.Sandbox.AllowedHosts)
+		}
+		g.submitGuard.SetLimits(newCfg.Tasks.MaxDepth, newCfg.Tasks.MaxPerMinute)
+
+		restart := func(field string, changed bool) {
+			if changed {
+				slog.Warn("config change requires restart", "field", field)
+			}
+		}
+		restart("gateway.host", !g.cmd.IsSet("host") && newCfg.Gateway.Host != g.cfg.Gateway.Host)
+		restart("gateway.port", !g.cmd.IsSet("port") && newCfg.Gateway.Port != g.cfg.Gateway.Port)
+		restart("events.log_format", !strings.EqualFold(newCfg.Events.LogFormat, g.cfg.Events.LogFormat))
+		restart("sandbox.enabled", newCfg.Sandbox.IsSandboxEnabled() != g.cfg.Sandbox.IsSandboxEnabled())
+		restart("sandbox.deny_rules", !slices.Equal(newCfg.Sandbox.DenyRules, g.cfg.Sandbox.DenyRules))
+		restart("sandbox.disabled_rules", !slices.Equal(newCfg.Sandbox.DisabledRules, g.cfg.Sandbox.DisabledRules))
+	})
+}
+
+// autoApproveRules compiles the configured auto-approve rules.
+func autoApproveRules(cfg []config.AutoApproveRule) ([]*conscience.AutoApproveRule, error) {
+	rules := make([]*conscience.AutoApproveRule, 0, len(cfg))
+	for _, r := range cfg {
+		rule, err := conscience.NewAutoApproveRule(r.Tool, r.Arg, r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ToolStats returns per-tool invocation metrics (ws.AdminHandler).
+func (g *gateway) ToolStats() any { return g.toolMetrics.Stats() }
+
+// ModelNames returns the configured provider names (ws.ModelHandler).
+func (g *gateway) ModelNames() []string { return g.registry.Names() }
+
+// DefaultModel returns the default provider name (ws.ModelHandler).
+func (g *gateway) DefaultModel() string { return g.registry.DefaultName() }
+
+// ModelInfo returns the driver, model ID and endpoint of a provider (ws.ModelHandler).
+func (g *gateway) ModelInfo(name string) (driver, model, endpoint string) {
+	return g.registry.ProviderInfo(name)
+}
+
+// sessionModel resolves a session's model override to a live registry model.
+func (g *gateway) sessionModel(provider string) model.ToolCallingChatModel {
+	return g.registry.Live(provider)
+}
+
 // initInfra creates the event bus, wires Eino callbacks, starts the event
 // logger, and validates provider capabilities.
 func (g *gateway) initInfra() error {
 	// Event bus
-	g.bus = events.NewBus(g.cfg.Events.BufferSize)
+	overflow, err := events.ParseOverflowPolicy(g.cfg.Events.Overflow)
+	if err != nil {
+		return fmt.Errorf("events: %w", err)
+	}
+	g.bus = events.NewBus(g.cfg.Events.BufferSize,
+		events.WithOverflowPolicy(overflow, g.cfg.Events.BlockTimeout.Duration()))
 	g.closers = append(g.closers, func() { g.bus.Close() })
 
 	// Register Eino callbacks → event bus bridge
@@ -127,6 +240,14 @@ func (g *gateway) initInfra() error {
 	eventLogger := events.NewEventLogger(logsDir, g.bus)
 	g.closers = append(g.closers, func() { eventLogger.Close() })
 
+	// Event journal (offset-addressable, for consumers resuming across restarts)
+	journal, err := events.NewJournal(filepath.Join(logsDir, "journal"), g.bus)
+	if err != nil {
+		return fmt.Errorf("event journal: %w", err)
+	}
+	g.journal = journal
+	g.closers = append(g.closers, journal.Close)
+
 	// Validate provider capabilities (warning only — allows future extensibility)
 	for name, prov := range g.cfg.Models.Providers {
 		if len(prov.Capabilities) > 0 {
@@ -150,12 +271,12 @@ func (g *gateway) initModels() error {
 		g.registry.UpdateProviders(newCfg.Models)
 	})
 
-	// Get default model
-	var err error
-	g.chatModel, err = g.registry.Default(g.ctx)
-	if err != nil {
+	// Validate the default model, then hand out a live handle so rebuilt
+	// clients (key rotation, config reload) reach long-lived agents.
+	if _, err := g.registry.Default(g.ctx); err != nil {
 		return fmt.Errorf("init default model: %w", err)
 	}
+	g.chatModel = g.registry.Live("")
 	return nil
 }
 
@@ -177,6 +298,11 @@ func (g *gateway) initToolPipeline() error {
 
 	// Tool permissions — global auto-approved tools from config
 	g.toolPerms = conscience.NewToolPermissions(g.cfg.Tools.AllowedDangerous)
+	autoApprove, err := autoApproveRules(g.cfg.Tools.AutoApprove)
+	if err != nil {
+		return fmt.Errorf("tools config: %w", err)
+	}
+	g.toolPerms.SetAutoApproveRules(autoApprove)
 
 	// Prepare tmp dir early — needed by both sandbox guard and filesystem middleware
 	g.tmpDir = filepath.Join(config.OzziePath(), "tmp")
@@ -184,10 +310,38 @@ func (g *gateway) initToolPipeline() error {
 		return fmt.Errorf("create tmp dir: %w", err)
 	}
 
+	// Tool metrics — innermost wrapper, so durations exclude approval waits
+	g.toolMetrics = hands.NewToolMetrics(g.cfg.Tools.SlowThreshold.Duration())
+	hands.WrapRegistryMetrics(g.toolRegistry, g.toolMetrics)
+
+	// Per-tool concurrency limits — shared by every session and task
+	g.toolLimiter = hands.NewToolLimiter(g.cfg.Tools.MaxConcurrent)
+	hands.WrapRegistryConcurrency(g.toolRegistry, g.toolLimiter)
+
 	// Sandbox guard — validates command content in autonomous mode (before dangerous wrapper)
 	if g.cfg.Sandbox.IsSandboxEnabled() {
-		sandboxPaths := append([]string{g.tmpDir}, g.cfg.Sandbox.AllowedPaths...)
-		hands.WrapRegistrySandbox(g.toolRegistry, sandboxPaths)
+		denyRules := make([]conscience.DenyRule, 0, len(g.cfg.Sandbox.DenyRules))
+		for _, r := range g.cfg.Sandbox.DenyRules {
+			rule, err := conscience.NewDenyRule(r.Pattern, r.Reason, r.Suggestion)
+			if err != nil {
+				return fmt.Errorf("sandbox config: %w", err)
+			}
+			denyRules = append(denyRules, rule)
+		}
+		commandRules, err := conscience.NewCommandRules(g.cfg.Sandbox.DisabledRules, denyRules)
+		if err != nil {
+			return fmt.Errorf("sandbox config: %w", err)
+		}
+
+		// Shared by every guard so a config reload updates them all
+		g.sandboxPaths = conscience.NewPathList(append([]string{g.tmpDir}, g.cfg.Sandbox.AllowedPaths...))
+		g.sandboxCommands = conscience.NewAllowlist(g.cfg.Sandbox.AllowedCommands)
+		g.sandboxHosts = conscience.NewAllowlist(g.cfg.Sandbox.AllowedHosts)
+		hands.WrapRegistrySandbox(g.toolRegistry, nil,
+			conscience.WithCommandRules(commandRules),
+			conscience.WithPathList(g.sandboxPaths),
+			conscience.WithCommandAllowlist(g.sandboxCommands),
+			conscience.WithHostAllowlist(g.sandboxHosts))
 	}
 
 	// Constraint guard — per-tool argument validation (between sandbox and dangerous)
@@ -202,14 +356,20 @@ func (g *gateway) initToolPipeline() error {
 // initSkills loads skill definitions, creates the verifier, runner config,
 // catalog, and executor.
 func (g *gateway) initSkills() error {
-	// Skill registry — load from SKILL.md directories
-	g.skillRegistry = skills.NewRegistry()
-	for _, dir := range g.cfg.Skills.Dirs {
-		if err := g.skillRegistry.LoadDir(dir); err != nil {
-			slog.Warn("failed to load skills", "dir", dir, "error", err)
-		}
-	}
+	// Skill registry — load from SKILL.md directories, then the cached
+	// remote libraries. The libraries are refreshed in the background so a
+	// slow or unreachable host does not delay startup; the refreshed copies
+	// are loaded at the next start.
+	g.skillRegistry = loadSkillRegistry(g.cfg)
 	slog.Info("skills loaded", "count", len(g.skillRegistry.All()))
+	if len(g.cfg.Skills.Remote) > 0 {
+		go func() {
+			if failed := syncRemoteSkills(g.ctx, g.cfg); failed < len(g.cfg.Skills.Remote) {
+				slog.Info("remote skill libraries refreshed, updates apply at the next start",
+					"synced", len(g.cfg.Skills.Remote)-failed)
+			}
+		}()
+	}
 
 	// Verifier for acceptance criteria — uses a SummarizeFunc closure
 	verifier := skills.NewVerifier(func(ctx context.Context, prompt string) (string, error) {
@@ -227,10 +387,12 @@ func (g *gateway) initSkills() error {
 	})
 
 	g.skillRunCfg = skills.RunnerConfig{
-		RunnerFactory: agent.NewRunnerFactory(g.registry),
+		RunnerFactory: agent.NewRunnerFactory(g.registry, g.cfg.Tools.MaxParallel),
 		ToolLookup:    g.toolRegistry.AsDomainToolLookup(),
 		EventBus:      g.bus,
 		Verifier:      verifier,
+		Memory:        g.memoryRetriever,
+		SessionField:  g.sessionField,
 	}
 
 	// Catalog for context middleware (name → description only)
@@ -242,16 +404,54 @@ func (g *gateway) initSkills() error {
 	return nil
 }
 
+// sessionField resolves the session_field var sources of skills.
+func (g *gateway) sessionField(sessionID, field string) (string, bool) {
+	s, err := g.sessionStore.Get(sessionID)
+	if err != nil {
+		return "", false
+	}
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		v, ok := s.Metadata[key]
+		return v, ok
+	}
+	if key, ok := strings.CutPrefix(field, "vars."); ok {
+		v, ok := s.Vars[key]
+		return v, ok
+	}
+	switch field {
+	case "title":
+		return s.Title, true
+	case "language":
+		return s.Language, true
+	case "root_dir":
+		return s.RootDir, true
+	case "summary":
+		return s.Summary, true
+	}
+	return "", false
+}
+
 // initStores creates session and task stores, runs crash recovery,
-// starts the heartbeat writer, and registers the update_session tool.
+// starts the heartbeat writer, and registers the session-bound tools
+// (update_session, set_var, get_var).
 func (g *gateway) initStores() error {
 	// Session store
 	sessionsDir := filepath.Join(config.OzziePath(), "sessions")
 	g.sessionStore = sessions.NewFileStore(sessionsDir)
+	if rc := g.cfg.Sessions.Redaction; rc.IsRedactionEnabled() {
+		redactor, err := sessions.NewRedactor(rc.Patterns, rc.IsEntropyEnabled())
+		if err != nil {
+			return fmt.Errorf("session redaction: %w", err)
+		}
+		g.sessionStore.SetRedactor(redactor)
+	}
 
-	// Task store
+	// Task store + retention janitor
 	tasksDir := filepath.Join(config.OzziePath(), "tasks")
-	g.taskStore = tasks.NewFileStore(tasksDir)
+	retention := g.cfg.Tasks.Retention
+	taskStore := tasks.NewFileStore(tasksDir)
+	taskStore.SetOutputFile(retention.OutputFile)
+	g.taskStore = taskStore
 
 	// Crash recovery — re-queue interrupted tasks
 	recovered, recoverErr := tasks.RecoverTasks(g.taskStore)
@@ -262,6 +462,17 @@ func (g *gateway) initStores() error {
 		slog.Info("recovered interrupted tasks", "count", recovered)
 	}
 
+	policy := tasks.RetentionPolicy{
+		MaxAge:        time.Duration(retention.MaxAge),
+		MaxPerSession: retention.MaxPerSession,
+		KeepOutputs:   retention.IsKeepOutputs(),
+	}
+	if policy.Enabled() {
+		janitor := tasks.NewJanitor(taskStore, policy, time.Duration(retention.Interval))
+		janitor.Start()
+		g.closers = append(g.closers, func() { janitor.Stop() })
+	}
+
 	// Heartbeat writer
 	hbWriter := heartbeat.NewWriter(filepath.Join(config.OzziePath(), "heartbeat.json"))
 	hbWriter.Start()
@@ -273,6 +484,14 @@ func (g *gateway) initStores() error {
 		slog.Warn("failed to register update_session tool", "error", err)
 	}
 
+	// Register session scratch variable tools (working memory, not long-term memory)
+	if err := g.toolRegistry.RegisterNative("set_var", hands.NewSetVarTool(g.sessionStore), hands.SetVarManifest()); err != nil {
+		slog.Warn("failed to register set_var tool", "error", err)
+	}
+	if err := g.toolRegistry.RegisterNative("get_var", hands.NewGetVarTool(g.sessionStore), hands.GetVarManifest()); err != nil {
+		slog.Warn("failed to register get_var tool", "error", err)
+	}
+
 	// Resolve default model tier (used for prompt adaptation)
 	g.defaultTier = g.registry.DefaultTier()
 	slog.Info("default model tier", "tier", g.defaultTier)
@@ -304,7 +523,7 @@ func (g *gateway) initPolicy() {
 
 // initMemory opens the SQLite memory store, optionally creates the vector
 // store and embedding pipeline, wires the reloader hook for hot-reload,
-// and starts the cross-task learning extractor.
+// and starts the cross-task learning extractor and task result recorder.
 func (g *gateway) initMemory() error {
 	// Memory store (SQLite) + optional vector embedding
 	memoryDir := filepath.Join(config.OzziePath(), "memory")
@@ -317,35 +536,34 @@ func (g *gateway) initMemory() error {
 
 	var vectorStore memory.VectorStorer
 	if g.cfg.Embedding.IsEnabled() {
-		embedder, embedErr := membridge.NewEmbedder(g.ctx, g.cfg.Embedding, g.kr)
-		if embedErr != nil {
-			slog.Warn("embedding disabled: failed to create embedder", "error", embedErr)
+		vs, vsErr := g.openVectorStore(g.cfg.Embedding, g.cmd.Bool("reindex"))
+		if errors.Is(vsErr, memory.ErrEmbeddingModelChanged) {
+			slog.Warn("embedding disabled: run `ozzie memory reindex` or restart with --reindex", "error", vsErr)
+		} else if vsErr != nil {
+			slog.Warn("embedding disabled", "error", vsErr)
 		} else {
-			dims := g.cfg.Embedding.Dims
-			if dims <= 0 {
-				dims = 1536 // default for most models
+			vectorStore = vs
+			queueSize := g.cfg.Embedding.QueueSize
+			if queueSize <= 0 {
+				queueSize = 100
 			}
-			vs, vsErr := memory.NewSQLiteVectorStore(g.memoryStore.DB(), embedder, dims)
-			if vsErr != nil {
-				slog.Warn("embedding disabled: failed to create vector store", "error", vsErr)
-			} else {
-				vectorStore = vs
-				queueSize := g.cfg.Embedding.QueueSize
-				if queueSize <= 0 {
-					queueSize = 100
+			embeddingModel := g.cfg.Embedding.Model
+			g.pipeline = memory.NewPipeline(vectorStore, g.memoryStore, embeddingModel, queueSize)
+			g.pipeline.Start(g.ctx)
+			g.closers = append(g.closers, func() { g.pipeline.Stop() })
+
+			// Async startup reindex (incremental — skips already-indexed entries)
+			go func() {
+				if _, err := memory.Reindex(g.ctx, g.memoryStore, vectorStore, embeddingModel); err != nil {
+					slog.Warn("startup reindex failed", "error", err)
 				}
-				embeddingModel := g.cfg.Embedding.Model
-				g.pipeline = memory.NewPipeline(vectorStore, g.memoryStore, embeddingModel, queueSize)
-				g.pipeline.Start(g.ctx)
-				g.closers = append(g.closers, func() { g.pipeline.Stop() })
-
-				// Async startup reindex (incremental — skips already-indexed entries)
-				go func() {
-					if _, err := memory.Reindex(g.ctx, g.memoryStore, vectorStore, embeddingModel); err != nil {
-						slog.Warn("startup reindex failed", "error", err)
-					}
-				}()
-				slog.Info("semantic memory enabled", "driver", g.cfg.Embedding.Driver, "model", embeddingModel)
+			}()
+			slog.Info("semantic memory enabled", "driver", g.cfg.Embedding.Driver, "model", embeddingModel)
+
+			if embedder, err := membridge.NewEmbedder(g.ctx, g.cfg.Embedding, g.kr); err != nil {
+				slog.Warn("semantic session search disabled", "error", err)
+			} else {
+				g.sessionSearcher = sessions.NewSemanticSearcher(g.sessionStore, embedder, vs)
 			}
 		}
 	}
@@ -379,28 +597,35 @@ func (g *gateway) initMemory() error {
 		if !newCfg.Embedding.IsEnabled() {
 			g.pipeline.Swap(nil, "")
 			g.memoryRetriever.SwapVector(nil)
+			if g.sessionSearcher != nil {
+				g.sessionSearcher.Swap(nil, nil)
+			}
 			slog.Info("embedding disabled via config reload")
 			return
 		}
 
-		// Recreate embedder + vector store
-		newEmbedder, err := membridge.NewEmbedder(g.ctx, newCfg.Embedding, g.kr)
-		if err != nil {
-			slog.Error("embedding reload: create embedder failed", "error", err)
-			return
+		// Recreate embedder + vector store. The config change is explicit,
+		// so incompatible vectors are dropped and rebuilt by the reindex below.
+		g.pipeline.Swap(nil, "")
+		g.memoryRetriever.SwapVector(nil)
+		if g.sessionSearcher != nil {
+			g.sessionSearcher.Swap(nil, nil)
 		}
-		newDims := newCfg.Embedding.Dims
-		if newDims <= 0 {
-			newDims = 1536
-		}
-		newVS, err := memory.NewSQLiteVectorStore(g.memoryStore.DB(), newEmbedder, newDims)
+		newVS, err := g.openVectorStore(newCfg.Embedding, true)
 		if err != nil {
-			slog.Error("embedding reload: create vector store failed", "error", err)
+			slog.Error("embedding reload failed, semantic memory disabled", "error", err)
 			return
 		}
 		newModel := newCfg.Embedding.Model
 		g.pipeline.Swap(newVS, newModel)
 		g.memoryRetriever.SwapVector(newVS)
+		if g.sessionSearcher != nil {
+			if embedder, err := membridge.NewEmbedder(g.ctx, newCfg.Embedding, g.kr); err != nil {
+				slog.Warn("semantic session search disabled", "error", err)
+			} else {
+				g.sessionSearcher.Swap(embedder, newVS)
+			}
+		}
 
 		go func() {
 			if _, err := memory.Reindex(g.ctx, g.memoryStore, newVS, newModel); err != nil {
@@ -422,36 +647,84 @@ func (g *gateway) initMemory() error {
 		})
 		extractor.Start()
 		g.closers = append(g.closers, func() { extractor.Stop() })
+
+		// Tasks submitted with remember: store a summary of their output
+		recorder := membridge.NewRecorder(membridge.RecorderConfig{
+			Store:      g.memoryStore,
+			Pipeline:   g.pipeline,
+			Tasks:      g.taskStore,
+			Summarizer: &extractorLLMAdapter{chatModel: g.chatModel},
+			Bus:        g.bus,
+		})
+		recorder.Start()
+		g.closers = append(g.closers, func() { recorder.Stop() })
 	}
 
 	return nil
 }
 
+// openVectorStore opens the vector store for cfg. If the store was indexed
+// with another embedding model and reset is true, stored vectors are dropped
+// so that the caller's reindex rebuilds them from scratch.
+func (g *gateway) openVectorStore(cfg config.EmbeddingConfig, reset bool) (*memory.SQLiteVectorStore, error) {
+	vs, err := membridge.NewVectorStore(g.ctx, g.memoryStore.DB(), cfg, g.kr)
+	if !reset || !errors.Is(err, memory.ErrEmbeddingModelChanged) {
+		return vs, err
+	}
+	slog.Warn("embedding model changed, dropping stored embeddings for reindex", "error", err)
+	if err := memory.ResetSQLiteVectorStore(g.memoryStore.DB()); err != nil {
+		return nil, err
+	}
+	return membridge.NewVectorStore(g.ctx, g.memoryStore.DB(), cfg, g.kr)
+}
+
 // initRuntime creates the actor pool, schedule store, skill schedules,
 // and the scheduler.
 func (g *gateway) initRuntime() error {
+	// Quiet hours — shared by the pool and the scheduler
+	qh := g.cfg.QuietHours
+	quiet, err := brain.ParseQuietHours(qh.Ranges, qh.Timezone, qh.Policy)
+	if err != nil {
+		return fmt.Errorf("quiet_hours: %w", err)
+	}
+
 	// Actor pool — capacity-aware LLM orchestration (replaces WorkerPool)
 	providerSpecs := make(map[string]actors.ProviderSpec, len(g.cfg.Models.Providers))
 	for name, prov := range g.cfg.Models.Providers {
 		providerSpecs[name] = actors.ProviderSpec{
 			MaxConcurrent: prov.MaxConcurrent,
+			MaxActors:     prov.MaxActors,
 			Tags:          prov.Tags,
 			Capabilities:  prov.Capabilities,
 			PromptPrefix:  prov.PromptPrefix,
 		}
 	}
+	// Compacts task context that overflowed the model's context window
+	compactor := agent.NewContextCompactor(
+		agent.NewCompressor(agent.CompressorConfig{ContextWindow: g.registry.DefaultContextWindow()}),
+		(&extractorLLMAdapter{chatModel: g.chatModel}).Summarize,
+	)
 	g.pool = actors.NewActorPool(actors.ActorPoolConfig{
 		Providers:       providerSpecs,
 		Store:           g.taskStore,
 		Bus:             g.bus,
-		RunnerFactory:   agent.NewRunnerFactory(g.registry),
+		RunnerFactory:   agent.NewRunnerFactory(g.registry, g.cfg.Tools.MaxParallel),
 		TierResolver:    g.registry,
 		ToolLookup:      g.toolRegistry.AsDomainToolLookup(),
 		SkillRunner:     g.skillExecutor,
 		TaskMiddlewares: g.taskMws,
 		Retriever:       g.memoryRetriever,
 		Perms:           g.toolPerms,
-		ExecutorFactory: tasks.NewTaskExecutorFactory(),
+		ExecutorFactory: tasks.NewTaskExecutorFactory(tasks.ContextLimits{
+			DependencyOutputChars: g.cfg.Limits.DependencyOutputChars,
+			MemoryContextChars:    g.cfg.Limits.MemoryContextChars,
+		}, compactor, g.cfg.Agent.MaxIterations.Task, g.cfg.Agent.MaxIterations.TaskLimit),
+		QuietHours:      quiet,
+		Autoscale: actors.AutoscaleConfig{
+			BacklogThreshold: g.cfg.Tasks.Autoscale.BacklogThreshold,
+			SustainFor:       time.Duration(g.cfg.Tasks.Autoscale.Sustain),
+			IdleCooldown:     time.Duration(g.cfg.Tasks.Autoscale.IdleCooldown),
+		},
 	})
 	g.pool.Start()
 	g.closers = append(g.closers, func() { g.pool.Stop() })
@@ -467,8 +740,10 @@ func (g *gateway) initRuntime() error {
 			continue
 		}
 		info := scheduler.SkillScheduleInfo{
-			Name: sk.Name,
-			Cron: sk.Triggers.Cron,
+			Name:       sk.Name,
+			Version:    sk.Version,
+			Cron:       sk.Triggers.Cron,
+			AfterSkill: sk.Triggers.OnSkillCompleted,
 		}
 		if sk.Triggers.OnEvent != nil {
 			info.OnEvent = &scheduler.EventTrigger{
@@ -485,6 +760,7 @@ func (g *gateway) initRuntime() error {
 		Bus:    g.bus,
 		Skills: schedSkills,
 		Store:  scheduleStore,
+		Quiet:  quiet,
 	})
 	g.sched.Start()
 	g.closers = append(g.closers, func() { g.sched.Stop() })
@@ -512,21 +788,38 @@ func (g *gateway) registerTools() {
 	}
 
 	// Register task tools
-	submitTool := hands.NewSubmitTaskTool(g.pool, g.toolRegistry, g.toolPerms, g.bus)
+	taskTemplates := tasks.NewTemplateStore(filepath.Join(config.OzziePath(), "task_templates"))
+	g.submitGuard = tasks.NewSubmissionGuard(g.taskStore, g.cfg.Tasks.MaxDepth, g.cfg.Tasks.MaxPerMinute)
+	submitTool := hands.NewSubmitTaskTool(g.pool, g.toolRegistry, g.toolPerms, g.bus, taskTemplates, g.submitGuard, g.cfg.Limits)
 	if err := g.toolRegistry.RegisterNative("submit_task", submitTool, hands.SubmitTaskManifest()); err != nil {
 		slog.Warn("failed to register submit_task tool", "error", err)
 	}
 
-	queryTasksTool := hands.NewQueryTasksTool(g.taskStore)
+	saveTemplateTool := hands.NewSaveTaskTemplateTool(g.taskStore, taskTemplates)
+	if err := g.toolRegistry.RegisterNative("save_task_template", saveTemplateTool, hands.SaveTaskTemplateManifest()); err != nil {
+		slog.Warn("failed to register save_task_template tool", "error", err)
+	}
+
+	queryTasksTool := hands.NewQueryTasksTool(g.taskStore, g.cfg.Limits)
 	if err := g.toolRegistry.RegisterNative(hands.ToolQueryTasks, queryTasksTool, hands.QueryTasksManifest()); err != nil {
 		slog.Warn("failed to register query_tasks tool", "error", err)
 	}
 
+	explainErrorTool := hands.NewExplainErrorTool(g.taskStore, filepath.Join(config.OzziePath(), "logs"))
+	if err := g.toolRegistry.RegisterNative("explain_error", explainErrorTool, hands.ExplainErrorManifest()); err != nil {
+		slog.Warn("failed to register explain_error tool", "error", err)
+	}
+
 	cancelTool := hands.NewCancelTaskTool(g.pool)
 	if err := g.toolRegistry.RegisterNative("cancel_task", cancelTool, hands.CancelTaskManifest()); err != nil {
 		slog.Warn("failed to register cancel_task tool", "error", err)
 	}
 
+	artifactTool := hands.NewRegisterArtifactTool(g.taskStore)
+	if err := g.toolRegistry.RegisterNative(tasks.RegisterArtifactTool, artifactTool, hands.RegisterArtifactManifest()); err != nil {
+		slog.Warn("failed to register register_artifact tool", "error", err)
+	}
+
 	// Register schedule tools
 	scheduleTaskTool := hands.NewScheduleTaskTool(g.sched, g.bus, g.toolRegistry, g.toolPerms)
 	if err := g.toolRegistry.RegisterNative("schedule_task", scheduleTaskTool, hands.ScheduleTaskManifest()); err != nil {
@@ -565,6 +858,7 @@ func (g *gateway) registerTools() {
 		"web_fetch":        true,
 		"web_search":       true,
 		"query_memories":   true,
+		"explain_error":    true,
 	}
 	all := g.toolRegistry.NativeToolNames()
 	var coreTools []string
@@ -583,6 +877,12 @@ func (g *gateway) registerTools() {
 	}
 	g.toolSet.RegisterCore(hands.ToolActivate)
 
+	listAvailableTool := hands.NewListAvailableToolsTool(g.toolSet, g.toolRegistry)
+	if err := g.toolRegistry.RegisterNative(hands.ToolListAvailable, listAvailableTool, hands.ListAvailableToolsManifest()); err != nil {
+		slog.Warn("failed to register list_available_tools tool", "error", err)
+	}
+	g.toolSet.RegisterCore(hands.ToolListAvailable)
+
 	// Register run_workflow (on-demand — activated via activate_skill or explicitly)
 	runWorkflowTool := hands.NewRunWorkflowTool(g.skillExecutor)
 	if err := g.toolRegistry.RegisterNative("run_workflow", runWorkflowTool, hands.RunWorkflowManifest()); err != nil {
@@ -607,9 +907,18 @@ func (g *gateway) initAgent() error {
 	}
 	fsBackend := agent.NewOzzieBackend(g.bus, g.toolPerms, fsOpts...)
 
-	// Register str_replace_editor (filesystem-based, needs fsBackend)
+	// Register str_replace_editor and write_files (filesystem-based, need fsBackend)
 	hands.RegisterFilesystemTools(g.toolRegistry, fsBackend)
 	g.toolSet.RegisterCore("str_replace_editor")
+	g.toolSet.RegisterCore("write_files")
+
+	// Meter and gate the tools registered since initToolPipeline
+	hands.WrapRegistryMetrics(g.toolRegistry, g.toolMetrics)
+	hands.WrapRegistryConcurrency(g.toolRegistry, g.toolLimiter)
+
+	// Schema guard — outermost, once every tool is registered, so malformed
+	// arguments are rejected before approval is asked
+	hands.WrapRegistrySchema(g.toolRegistry)
 
 	fsMw, err := einoFs.NewMiddleware(g.ctx, &einoFs.Config{
 		Backend:                          fsBackend,
@@ -618,6 +927,8 @@ func (g *gateway) initAgent() error {
 	if err != nil {
 		return fmt.Errorf("init filesystem middleware: %w", err)
 	}
+	// Append a unified diff of the change to write_file / edit_file results
+	fsMw.WrapToolCall = agent.NewFileDiffMiddleware(fsBackend)
 
 	// Reduction middleware — clears old tool results and offloads large ones to filesystem
 	reductionMw, err := einoReduction.NewToolResultMiddleware(g.ctx, &einoReduction.ToolResultConfig{
@@ -637,9 +948,15 @@ func (g *gateway) initAgent() error {
 	// SubAgent middleware — injects SubAgentInstructions + runtime (tool reference + workflow)
 	subAgentMw := agent.NewSubAgentMiddleware(runtimeInstruction, g.defaultTier)
 
-	// Task middlewares — subagent instructions + filesystem + reduction for sub-agents (no context middleware)
+	// Tool loop detection — nudges an agent repeating the same tool call
+	toolLoopMw := agent.NewToolLoopMiddleware(agent.ToolLoopConfig{
+		Threshold:  g.cfg.Agent.ToolLoop.Threshold,
+		MaxRepeats: g.cfg.Agent.ToolLoop.MaxRepeats,
+	})
+
+	// Task middlewares — subagent instructions + filesystem + reduction + tool loop detection for sub-agents (no context middleware)
 	// Stored as []any (opaque) — the RunnerFactory adapter casts them back to adk.AgentMiddleware.
-	g.taskMws = []any{subAgentMw, fsMw, reductionMw}
+	g.taskMws = []any{subAgentMw, fsMw, reductionMw, toolLoopMw}
 
 	// Build full tool descriptions for prompt composer
 	allToolDescs := g.toolRegistry.AllToolDescriptions()
@@ -670,22 +987,55 @@ func (g *gateway) initAgent() error {
 		Retriever:           g.memoryRetriever,
 		Tier:                g.defaultTier,
 		ActorDescriptions:   actorDescs,
+		IncludeSessionVars:  g.cfg.Agent.SessionVarsInContext,
+		TaskStore:           g.taskStore,
+		RecentTasks:         g.cfg.Agent.RecentTasksInContext,
+		RecentTasksTokens:   g.cfg.Agent.RecentTasksTokens,
 	})
 
 	var middlewares []adk.AgentMiddleware
-	middlewares = append(middlewares, fsMw, reductionMw, contextMw)
+	middlewares = append(middlewares, fsMw, reductionMw)
+
+	// Tool call budget — summarizes older tool results in tool-heavy turns
+	if n := g.cfg.Agent.MaxToolCallsBeforeSummary; n > 0 {
+		middlewares = append(middlewares, agent.NewToolCallBudgetMiddleware(agent.ToolCallBudgetConfig{
+			MaxToolCalls: n,
+			Backend:      fsBackend,
+			Dir:          filepath.Join(g.tmpDir, "tool_results"),
+		}))
+	}
+	middlewares = append(middlewares, toolLoopMw, contextMw)
+
+	// Tool recovery — in-place retries of failing tool calls
+	recoveryCfg := agent.ToolRecoveryConfig{
+		MaxAttempts:   g.cfg.Agent.ToolRecovery.MaxAttempts,
+		Backoff:       g.cfg.Agent.ToolRecovery.Backoff.Duration(),
+		Idempotent:    append(slices.Clone(agent.DefaultIdempotentTools), g.cfg.Agent.ToolRecovery.IdempotentTools...),
+		OmitErrorText: g.cfg.Agent.ToolRecovery.OmitErrorText,
+		Bus:           g.bus,
+	}
+	for _, pattern := range g.cfg.Agent.ToolRecovery.Recoverable {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("tool_recovery: invalid pattern %q: %w", pattern, err)
+		}
+		recoveryCfg.Recoverable = append(recoveryCfg.Recoverable, re)
+	}
 
 	// AgentFactory (replaces single runner — creates fresh runner per turn)
-	g.factory = agent.NewAgentFactory(g.chatModel, g.persona, middlewares)
+	g.factory = agent.NewAgentFactory(g.chatModel, g.persona, middlewares, recoveryCfg)
 
 	// Cost tracker — accumulates token usage per session
-	sessionsDir := filepath.Join(config.OzziePath(), "sessions")
 	costTracker := sessions.NewCostTracker(g.bus, g.sessionStore)
 	g.closers = append(g.closers, func() { costTracker.Close() })
 
+	// Audit log — append-only record of every tool invocation per session
+	auditLogger := sessions.NewAuditLogger(g.bus, g.sessionStore)
+	g.closers = append(g.closers, func() { auditLogger.Close() })
+
 	// Layered context manager (optional)
 	if g.cfg.LayeredContext.IsEnabled() {
-		layeredStore := layeredctx.NewStore(sessionsDir)
+		layeredStore := layeredctx.NewStoreFunc(g.sessionStore.SessionDir)
 		layeredCfg := layeredctx.DefaultConfig()
 		layeredCfg.Enabled = true
 		layeredCfg.MaxArchives = g.cfg.LayeredContext.MaxArchives
@@ -708,6 +1058,12 @@ func (g *gateway) initAgent() error {
 		)
 	}
 
+	// Stream deltas are batched unless disabled (agent.streaming.coalesce)
+	var streamFlushInterval time.Duration
+	if g.cfg.Agent.Streaming.IsCoalesce() {
+		streamFlushInterval = g.cfg.Agent.Streaming.FlushInterval.Duration()
+	}
+
 	// Event runner with dynamic tool selection and actor pool integration
 	g.eventRunner = agent.NewEventRunner(agent.EventRunnerConfig{
 		Factory:         g.factory,
@@ -717,9 +1073,18 @@ func (g *gateway) initAgent() error {
 		Store:           g.sessionStore,
 		Pool:            actors.NewPoolAdapter(g.pool),
 		DefaultProvider: g.registry.DefaultName(),
+		SessionModel:    g.sessionModel,
 		ContextWindow:   g.registry.DefaultContextWindow(),
 		Tier:            g.defaultTier,
 		Layered:         g.layered,
+		Confine:         g.cfg.Sandbox.ConfineInteractive,
+		ToolIdleTurns:   g.cfg.Agent.ToolIdleTurns,
+		MaxParallel:     g.cfg.Tools.MaxParallel,
+		MaxIterations:   g.cfg.Agent.MaxIterations.Interactive,
+
+		StreamFlushInterval: streamFlushInterval,
+		StreamFlushRunes:    g.cfg.Agent.Streaming.FlushRunes,
+		Redactor:            g.sessionStore.Redactor(),
 	})
 	g.closers = append(g.closers, func() { g.eventRunner.Close() })
 
@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -56,8 +57,11 @@ func NewMemoryCommand() *cli.Command {
 				Action:    runMemoryForget,
 			},
 			{
-				Name:   "reindex",
-				Usage:  "Rebuild vector embeddings for all memories",
+				Name:  "reindex",
+				Usage: "Rebuild vector embeddings for all memories",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "reset", Usage: "Drop all stored embeddings and rebuild from scratch"},
+				},
 				Action: runMemoryReindex,
 			},
 			{
@@ -128,16 +132,12 @@ func runMemorySearch(ctx context.Context, cmd *cli.Command) error {
 	var vectorStore memory.VectorStorer
 	cfg, kr, cfgErr := loadConfigWithKeyRing(cmd.String("config"))
 	if cfgErr == nil && cfg.Embedding.IsEnabled() {
-		embedder, embErr := membridge.NewEmbedder(ctx, cfg.Embedding, kr)
-		if embErr == nil {
-			dims := cfg.Embedding.Dims
-			if dims <= 0 {
-				dims = 1536
-			}
-			vs, vsErr := memory.NewSQLiteVectorStore(store.DB(), embedder, dims)
-			if vsErr == nil {
-				vectorStore = vs
-			}
+		vs, vsErr := membridge.NewVectorStore(ctx, store.DB(), cfg.Embedding, kr)
+		switch {
+		case vsErr == nil:
+			vectorStore = vs
+		case errors.Is(vsErr, memory.ErrEmbeddingModelChanged):
+			fmt.Fprintf(os.Stderr, "warning: %v — run `ozzie memory reindex`; falling back to keyword search\n", vsErr)
 		}
 	}
 
@@ -246,11 +246,6 @@ func runMemoryReindex(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("embedding is not enabled in config (set embedding.enabled = true)")
 	}
 
-	embedder, err := membridge.NewEmbedder(ctx, cfg.Embedding, kr)
-	if err != nil {
-		return fmt.Errorf("create embedder: %w", err)
-	}
-
 	memoryDir := filepath.Join(config.OzziePath(), "memory")
 	store, err := memory.NewSQLiteStore(memoryDir)
 	if err != nil {
@@ -258,13 +253,24 @@ func runMemoryReindex(ctx context.Context, cmd *cli.Command) error {
 	}
 	defer store.Close()
 
-	dims := cfg.Embedding.Dims
-	if dims <= 0 {
-		dims = 1536
+	if cmd.Bool("reset") {
+		if err := memory.ResetSQLiteVectorStore(store.DB()); err != nil {
+			return err
+		}
+	}
+
+	vectorStore, err := membridge.NewVectorStore(ctx, store.DB(), cfg.Embedding, kr)
+	if errors.Is(err, memory.ErrEmbeddingModelChanged) {
+		// The configured model differs from the indexed one: vectors are
+		// incompatible, so a full rebuild is the only way forward.
+		fmt.Fprintf(os.Stderr, "%v — dropping stored embeddings\n", err)
+		if err := memory.ResetSQLiteVectorStore(store.DB()); err != nil {
+			return err
+		}
+		vectorStore, err = membridge.NewVectorStore(ctx, store.DB(), cfg.Embedding, kr)
 	}
-	vectorStore, err := memory.NewSQLiteVectorStore(store.DB(), embedder, dims)
 	if err != nil {
-		return fmt.Errorf("create vector store: %w", err)
+		return err
 	}
 
 	slog.Info("starting reindex", "driver", cfg.Embedding.Driver, "model", cfg.Embedding.Model)
@@ -295,18 +301,12 @@ func runMemoryConsolidate(ctx context.Context, cmd *cli.Command) error {
 	}
 	defer store.Close()
 
-	embedder, err := membridge.NewEmbedder(ctx, cfg.Embedding, kr)
-	if err != nil {
-		return fmt.Errorf("create embedder: %w", err)
+	vectorStore, err := membridge.NewVectorStore(ctx, store.DB(), cfg.Embedding, kr)
+	if errors.Is(err, memory.ErrEmbeddingModelChanged) {
+		return fmt.Errorf("%w (run `ozzie memory reindex` first)", err)
 	}
-
-	dims := cfg.Embedding.Dims
-	if dims <= 0 {
-		dims = 1536
-	}
-	vectorStore, err := memory.NewSQLiteVectorStore(store.DB(), embedder, dims)
 	if err != nil {
-		return fmt.Errorf("create vector store: %w", err)
+		return err
 	}
 
 	// Load primary chat model for LLM-based merge
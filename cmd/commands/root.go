@@ -36,6 +36,7 @@ func NewRootCommand(version, commit string) *cli.Command {
 			NewTasksCommand(),
 			NewEventsCommand(),
 			NewScheduleCommand(),
+			NewSkillsCommand(),
 			NewMemoryCommand(),
 			NewSecretCommand(),
 			NewMCPServeCommand(),
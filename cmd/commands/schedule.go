@@ -14,7 +14,6 @@ import (
 	"github.com/dohr-michael/ozzie/internal/config"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/scheduler"
-	"github.com/dohr-michael/ozzie/internal/core/skills"
 )
 
 // NewScheduleCommand returns the schedule subcommand.
@@ -44,12 +43,7 @@ func runScheduleList(_ context.Context, _ *cli.Command) error {
 		cfg = &config.Config{}
 	}
 
-	reg := skills.NewRegistry()
-	for _, dir := range cfg.Skills.Dirs {
-		if err := reg.LoadDir(dir); err != nil {
-			continue
-		}
-	}
+	reg := loadSkillRegistry(cfg)
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
 	fmt.Fprintln(w, "SOURCE\tNAME\tCRON\tEVENT\tENABLED")
@@ -69,9 +63,15 @@ func runScheduleList(_ context.Context, _ *cli.Command) error {
 		eventStr := "-"
 		if sk.Triggers.OnEvent != nil {
 			eventStr = sk.Triggers.OnEvent.Event
+		} else if sk.Triggers.OnSkillCompleted != "" {
+			eventStr = "after " + sk.Triggers.OnSkillCompleted
 		}
 
-		fmt.Fprintf(w, "skill\t%s\t%s\t%s\tyes\n", sk.Name, cronStr, eventStr)
+		source := "skill"
+		if sk.IsRemote() {
+			source = "remote:" + sk.Source
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\tyes\n", source, sk.Name, cronStr, eventStr)
 	}
 
 	// Dynamic schedules from persistent store
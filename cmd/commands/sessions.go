@@ -26,6 +26,7 @@ func NewSessionsCommand() *cli.Command {
 				Usage: "List all sessions",
 				Flags: []cli.Flag{
 					&cli.BoolFlag{Name: "json", Usage: "Output raw JSON"},
+					&cli.StringFlag{Name: "project", Usage: "Only list the sessions of this project (\"default\" for none)"},
 				},
 				Action: runSessionsList,
 			},
@@ -54,6 +55,7 @@ func runSessionsList(_ context.Context, cmd *cli.Command) error {
 	if err != nil {
 		return fmt.Errorf("list sessions: %w", err)
 	}
+	list = sessions.FilterByProject(list, cmd.String("project"))
 
 	if cmd.Bool("json") {
 		return json.NewEncoder(os.Stdout).Encode(list)
@@ -65,15 +67,16 @@ func runSessionsList(_ context.Context, cmd *cli.Command) error {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-	fmt.Fprintln(w, "NAME\tSTATUS\tMESSAGES\tUPDATED\tTITLE")
+	fmt.Fprintln(w, "NAME\tPROJECT\tSTATUS\tMESSAGES\tUPDATED\tTITLE")
 	for _, s := range list {
 		title := s.Title
 		if title == "" {
 			title = "-"
 		}
 		displayName := names.DisplayName(s.ID)
-		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
 			displayName,
+			s.ProjectName(),
 			s.Status,
 			s.MessageCount,
 			s.UpdatedAt.Format("2006-01-02 15:04"),
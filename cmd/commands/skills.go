@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/dohr-michael/ozzie/internal/config"
+	"github.com/dohr-michael/ozzie/internal/core/skills"
+)
+
+// remoteSkillsTimeout bounds the fetch of one remote skill library.
+const remoteSkillsTimeout = 30 * time.Second
+
+// NewSkillsCommand returns the skills subcommand.
+func NewSkillsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "skills",
+		Usage: "List skills and sync remote skill libraries",
+		Commands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List local and cached remote skills",
+				Action: runSkillsList,
+			},
+			{
+				Name:   "sync",
+				Usage:  "Fetch the remote skill libraries configured in skills.remote",
+				Action: runSkillsSync,
+			},
+		},
+		DefaultCommand: "list",
+	}
+}
+
+// remoteSkillsDir is where remote skill libraries are cached.
+func remoteSkillsDir() string {
+	return filepath.Join(config.OzziePath(), "remote_skills")
+}
+
+// syncRemoteSkills fetches every configured remote library into the cache.
+// Failures are logged: the previously cached copies stay usable offline.
+func syncRemoteSkills(ctx context.Context, cfg *config.Config) (failed int) {
+	client := &http.Client{Timeout: remoteSkillsTimeout}
+	for _, r := range cfg.Skills.Remote {
+		src := skills.RemoteSource{Name: r.Name, URL: r.URL, IndexSHA256: r.SHA256}
+		if err := skills.SyncRemote(ctx, client, src, remoteSkillsDir()); err != nil {
+			slog.Warn("remote skills unavailable, using cached copy", "source", r.Name, "error", err)
+			failed++
+		}
+	}
+	return failed
+}
+
+// loadSkillRegistry loads the local skill directories, then the cached
+// remote libraries (local skills win on name conflicts).
+func loadSkillRegistry(cfg *config.Config) *skills.Registry {
+	reg := skills.NewRegistry()
+	for _, dir := range cfg.Skills.Dirs {
+		if err := reg.LoadDir(dir); err != nil {
+			slog.Warn("failed to load skills", "dir", dir, "error", err)
+		}
+	}
+	for _, r := range cfg.Skills.Remote {
+		if err := reg.LoadRemoteDir(filepath.Join(remoteSkillsDir(), r.Name), r.Name); err != nil {
+			slog.Warn("failed to load remote skills", "source", r.Name, "error", err)
+		}
+	}
+	return reg
+}
+
+// skillSource describes where a skill comes from in list output.
+func skillSource(sk *skills.SkillMD) string {
+	if sk.IsRemote() {
+		return "remote:" + sk.Source
+	}
+	return "local"
+}
+
+func runSkillsList(_ context.Context, _ *cli.Command) error {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	reg := loadSkillRegistry(cfg)
+	all := reg.All()
+	if len(all) == 0 {
+		fmt.Println("No skills found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSOURCE\tWORKFLOW\tDESCRIPTION")
+	for _, latest := range all {
+		// Every loaded version, the latest (run when unpinned) first
+		versions := reg.Versions(latest.Name)
+		slices.Reverse(versions)
+		for _, sk := range versions {
+			version := cmp.Or(sk.Version, "-")
+			if sk == latest && len(versions) > 1 {
+				version += " (latest)"
+			}
+			workflow := "no"
+			if sk.HasWorkflow() {
+				workflow = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", sk.Name, version, skillSource(sk), workflow, sk.Description)
+		}
+	}
+	return w.Flush()
+}
+
+func runSkillsSync(ctx context.Context, _ *cli.Command) error {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if len(cfg.Skills.Remote) == 0 {
+		fmt.Println("No remote skill libraries configured (skills.remote).")
+		return nil
+	}
+
+	failed := syncRemoteSkills(ctx, cfg)
+	fmt.Printf("Synced %d/%d remote skill libraries.\n", len(cfg.Skills.Remote)-failed, len(cfg.Skills.Remote))
+	if failed > 0 {
+		return fmt.Errorf("%d remote skill libraries could not be fetched", failed)
+	}
+	return nil
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/dohr-michael/ozzie/clients/tui"
 	wsclient "github.com/dohr-michael/ozzie/clients/ws"
+	wsprotocol "github.com/dohr-michael/ozzie/internal/infra/gateway/ws"
+	"github.com/dohr-michael/ozzie/internal/infra/ui/components"
 )
 
 // NewTUICommand returns the tui subcommand.
@@ -34,10 +36,19 @@ func NewTUICommand() *cli.Command {
 				Aliases: []string{"w"},
 				Usage:   "Working directory for the session (default: current directory)",
 			},
+			&cli.BoolFlag{
+				Name:  "confine",
+				Usage: "Confine filesystem and command tools to the working directory",
+			},
 			&cli.BoolFlag{
 				Name:  "insecure",
 				Usage: "Skip authentication (for dev mode)",
 			},
+			&cli.StringFlag{
+				Name:  "encoding",
+				Usage: "Frame encoding requested from the gateway (json, msgpack)",
+				Value: string(wsprotocol.EncodingJSON),
+			},
 		},
 		Action: runTUI,
 	}
@@ -60,6 +71,12 @@ func runTUI(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
+	encoding, err := wsprotocol.ParseEncoding(cmd.String("encoding"))
+	if err != nil {
+		return err
+	}
+	dialOpts = append(dialOpts, wsclient.WithEncoding(encoding))
+
 	client, err := wsclient.Dial(ctx, gatewayURL, dialOpts...)
 	if err != nil {
 		return fmt.Errorf("connect to gateway: %w", err)
@@ -68,6 +85,7 @@ func runTUI(ctx context.Context, cmd *cli.Command) error {
 	sid, err := client.OpenSession(wsclient.OpenSessionOpts{
 		SessionID: sessionFlag,
 		RootDir:   workDir,
+		Confined:  cmd.Bool("confine"),
 	})
 	if err != nil {
 		client.Close()
@@ -75,6 +93,17 @@ func runTUI(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	var opts []tui.AppOption
+	// TUI settings are optional: without a readable config, the defaults apply.
+	if cfg, _, err := loadConfigWithKeyRing(cmd.String("config")); err == nil {
+		if len(cfg.TUI.Keys) > 0 {
+			keys, warnings := components.NewKeyMap(cfg.TUI.Keys)
+			opts = append(opts, tui.WithKeyMap(keys, warnings))
+		}
+		components.SetSyntaxHighlighting(cfg.TUI.IsSyntaxHighlightEnabled())
+		if cfg.TUI.Notifications != "" {
+			opts = append(opts, tui.WithNotifications(tui.NotifyMode(cfg.TUI.Notifications)))
+		}
+	}
 	if sessionFlag != "" {
 		msgs, err := client.LoadMessages(10)
 		if err == nil && len(msgs) > 0 {
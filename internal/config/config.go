@@ -1,7 +1,13 @@
 package config
 
 import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"slices"
+	"strings"
 	"time"
 )
 
@@ -15,6 +21,8 @@ type Config struct {
 	Plugins        PluginsConfig        `json:"plugins"`
 	Skills         SkillsConfig         `json:"skills"`
 	Tools          ToolsConfig          `json:"tools"`
+	Tasks          TasksConfig          `json:"tasks"`
+	Limits         LimitsConfig         `json:"limits"`
 	Sandbox        SandboxConfig        `json:"sandbox"`
 	Runtime        RuntimeConfig        `json:"runtime"`
 	Web            WebConfig            `json:"web"`
@@ -22,6 +30,88 @@ type Config struct {
 	LayeredContext LayeredContextConfig `json:"layered_context"`
 	Policies       PoliciesConfig       `json:"policies"`
 	Connectors     ConnectorsConfig     `json:"connectors"`
+	QuietHours     QuietHoursConfig     `json:"quiet_hours"`
+	TUI            TUIConfig            `json:"tui"`
+	Sessions       SessionsConfig       `json:"sessions"`
+}
+
+// SessionsConfig configures session persistence.
+type SessionsConfig struct {
+	Redaction RedactionConfig `json:"redaction"`
+}
+
+// RedactionConfig masks secrets (API keys, bearer tokens, private keys...)
+// in session messages before they are written to disk. The live context
+// keeps the original values.
+type RedactionConfig struct {
+	Enabled  *bool    `json:"enabled"`            // default: true
+	Patterns []string `json:"patterns,omitempty"` // extra Go regexps; the first capture group, or the whole match, is masked
+	Entropy  *bool    `json:"entropy,omitempty"`  // also mask high-entropy tokens (default: false)
+}
+
+// IsRedactionEnabled returns true if message redaction is enabled (default: true).
+func (c RedactionConfig) IsRedactionEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+	return *c.Enabled
+}
+
+// IsEntropyEnabled returns true if high-entropy tokens are masked (default: false).
+// Opt-in: the heuristic also masks legitimate identifiers and encoded data.
+func (c RedactionConfig) IsEntropyEnabled() bool {
+	return c.Entropy != nil && *c.Entropy
+}
+
+// Validate checks that every custom redaction pattern compiles.
+func (c RedactionConfig) Validate() error {
+	for i, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("sessions.redaction.patterns[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// TUIConfig configures the terminal UI client.
+type TUIConfig struct {
+	// Keys remaps TUI actions (quit, submit, cancel, up, down, complete,
+	// toggle, confirm_yes, confirm_no, tasks) to bubbletea key strings, e.g.
+	// {"down": ["down", "ctrl+j"]}. Unlisted actions keep their defaults.
+	Keys map[string][]string `json:"keys,omitempty"`
+	// SyntaxHighlight colors fenced code blocks in assistant messages and
+	// tool results (default: true). Disable it on limited terminals.
+	SyntaxHighlight *bool `json:"syntax_highlight,omitempty"`
+	// Notifications alerts when a task or skill finishes while the terminal
+	// is not in focus: "off" (default), "bell" (terminal bell) or "desktop"
+	// (bell plus an OSC 9 / OSC 777 desktop notification).
+	Notifications string `json:"notifications,omitempty"`
+}
+
+// Validate checks the notification mode.
+func (c TUIConfig) Validate() error {
+	switch c.Notifications {
+	case "", "off", "bell", "desktop":
+		return nil
+	default:
+		return fmt.Errorf("tui.notifications: unknown mode %q (want off, bell or desktop)", c.Notifications)
+	}
+}
+
+// IsSyntaxHighlightEnabled returns true if code blocks are highlighted (default: true).
+func (c TUIConfig) IsSyntaxHighlightEnabled() bool {
+	if c.SyntaxHighlight == nil {
+		return true
+	}
+	return *c.SyntaxHighlight
+}
+
+// QuietHoursConfig configures daily windows during which scheduled and
+// background work is held back.
+type QuietHoursConfig struct {
+	Ranges   []string `json:"ranges,omitempty"`   // "HH:MM-HH:MM", may wrap past midnight
+	Timezone string   `json:"timezone,omitempty"` // IANA name (default: local time)
+	Policy   string   `json:"policy,omitempty"`   // "defer" (default) | "skip"
 }
 
 // ConnectorsConfig configures external platform connectors.
@@ -140,8 +230,37 @@ type RuntimeConfig struct {
 
 // SandboxConfig configures the sandbox guard for autonomous sub-agents.
 type SandboxConfig struct {
-	Enabled      *bool    `json:"enabled"`       // default: true
-	AllowedPaths []string `json:"allowed_paths"` // extra paths allowed outside WorkDir
+	Enabled         *bool    `json:"enabled"`                    // default: true
+	AllowedPaths    []string `json:"allowed_paths"`              // extra paths allowed outside WorkDir
+	AllowedCommands []string `json:"allowed_commands,omitempty"` // non-empty = only these binaries may run in autonomous mode
+	AllowedHosts    []string `json:"allowed_hosts,omitempty"`    // non-empty = network tools may only reach these hosts in autonomous mode
+	// ConfineInteractive jails filesystem/exec tools of interactive sessions
+	// to the session RootDir, as in autonomous mode (sessions can also opt in).
+	// Sessions without a RootDir are then rejected.
+	ConfineInteractive bool `json:"confine_interactive,omitempty"`
+	// DenyRules are extra regex rules blocking autonomous exec commands.
+	DenyRules []SandboxRuleConfig `json:"deny_rules,omitempty"`
+	// DisabledRules names built-in denylist rules to skip (e.g. "sudo" in a
+	// trusted container): a denylisted binary, "dd", "raw_device",
+	// "fork_bomb" or "dynamic_command".
+	DisabledRules []string `json:"disabled_rules,omitempty"`
+}
+
+// SandboxRuleConfig is a custom sandbox denylist rule.
+type SandboxRuleConfig struct {
+	Pattern    string `json:"pattern"`              // Go regexp matched against the raw command
+	Reason     string `json:"reason"`               // reported to the agent when the rule matches
+	Suggestion string `json:"suggestion,omitempty"` // safer alternative reported to the agent
+}
+
+// Validate checks that every custom deny rule compiles.
+func (c SandboxConfig) Validate() error {
+	for i, r := range c.DenyRules {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("sandbox.deny_rules[%d]: %w", i, err)
+		}
+	}
+	return nil
 }
 
 // IsSandboxEnabled returns true if the sandbox is enabled (default: true).
@@ -158,7 +277,7 @@ type EmbeddingConfig struct {
 	Driver    string     `json:"driver"`               // "openai" | "ollama"
 	Model     string     `json:"model"`                // e.g. "text-embedding-3-small", "nomic-embed-text"
 	BaseURL   string     `json:"base_url,omitempty"`   // for ollama or custom endpoints
-	Dims      int        `json:"dims,omitempty"`       // embedding dimensions (OpenAI v3 supports this)
+	Dims      int        `json:"dims,omitempty"`       // embedding dimensions (OpenAI v3 supports this; 0 = detect)
 	Auth      AuthConfig `json:"auth,omitempty"`       // reuses existing AuthConfig
 	QueueSize int        `json:"queue_size,omitempty"` // buffer channel size (default: 100)
 }
@@ -170,13 +289,150 @@ func (c EmbeddingConfig) IsEnabled() bool {
 
 // ToolsConfig configures tool permissions.
 type ToolsConfig struct {
-	AllowedDangerous []string `json:"allowed_dangerous"` // globally auto-approved dangerous tools
+	AllowedDangerous []string          `json:"allowed_dangerous"`      // globally auto-approved dangerous tools
+	AutoApprove      []AutoApproveRule `json:"auto_approve,omitempty"`   // per-call approval by argument pattern
+	SlowThreshold    Duration          `json:"slow_threshold,omitempty"` // median duration above which a tool is logged as slow (default: 10s)
+	MaxConcurrent    map[string]int    `json:"max_concurrent,omitempty"` // per-tool concurrent call limit, overrides the manifest (0 = unlimited)
+	MaxParallel      int               `json:"max_parallel,omitempty"`   // tool calls of one assistant message run at once (default: 4, 1 = sequential)
+}
+
+// LimitsConfig bounds how much task output is injected into model context.
+// Raise the limits for large-context models, lower them for small ones.
+type LimitsConfig struct {
+	TaskOutputChars       int `json:"task_output_chars,omitempty"`        // task output returned by query_tasks (default: 500)
+	InlineTaskOutputChars int `json:"inline_task_output_chars,omitempty"` // output of tasks run inline by submit_task (default: 2000)
+	TaskListSize          int `json:"task_list_size,omitempty"`           // tasks listed by query_tasks (default: 20)
+	DependencyOutputChars int `json:"dependency_output_chars,omitempty"`  // output of each dependency injected into a task (default: 1000)
+	MemoryContextChars    int `json:"memory_context_chars,omitempty"`     // memories injected into a task (default: 2000)
+}
+
+// WithDefaults returns the limits with unset (zero) values replaced by defaults.
+func (l LimitsConfig) WithDefaults() LimitsConfig {
+	l.TaskOutputChars = cmp.Or(l.TaskOutputChars, 500)
+	l.InlineTaskOutputChars = cmp.Or(l.InlineTaskOutputChars, 2000)
+	l.TaskListSize = cmp.Or(l.TaskListSize, 20)
+	l.DependencyOutputChars = cmp.Or(l.DependencyOutputChars, 1000)
+	l.MemoryContextChars = cmp.Or(l.MemoryContextChars, 2000)
+	return l
+}
+
+// Validate checks that every limit is positive.
+func (l LimitsConfig) Validate() error {
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"task_output_chars", l.TaskOutputChars},
+		{"inline_task_output_chars", l.InlineTaskOutputChars},
+		{"task_list_size", l.TaskListSize},
+		{"dependency_output_chars", l.DependencyOutputChars},
+		{"memory_context_chars", l.MemoryContextChars},
+	}
+	for _, lim := range limits {
+		if lim.value <= 0 {
+			return fmt.Errorf("limits.%s must be positive, got %d", lim.name, lim.value)
+		}
+	}
+	return nil
+}
+
+// TasksConfig bounds async task creation.
+type TasksConfig struct {
+	MaxDepth     int             `json:"max_depth,omitempty"`      // max task tree depth via submit_task (default: 5)
+	MaxPerMinute int             `json:"max_per_minute,omitempty"` // max tasks created per session per minute (default: 10)
+	Autoscale    AutoscaleConfig `json:"autoscale"`
+	Retention    RetentionConfig `json:"retention"`
+}
+
+// RetentionConfig prunes finished (completed, failed, cancelled) tasks from
+// disk. Pending and running tasks, and the tasks they depend on, are kept.
+type RetentionConfig struct {
+	MaxAge        Duration `json:"max_age,omitempty"`         // delete finished tasks older than this (default: keep)
+	MaxPerSession int      `json:"max_per_session,omitempty"` // finished tasks kept per session (default: unlimited)
+	Interval      Duration `json:"interval,omitempty"`        // how often the janitor sweeps (default: 1h)
+	OutputFile    string   `json:"output_file,omitempty"`     // output file name in task directories (default: output.md)
+	KeepOutputs   *bool    `json:"keep_outputs,omitempty"`    // keep outputs of finished tasks (default: true)
+}
+
+// IsKeepOutputs returns true if finished tasks keep their output (default: true).
+func (c RetentionConfig) IsKeepOutputs() bool {
+	return c.KeepOutputs == nil || *c.KeepOutputs
+}
+
+// Validate checks the retention bounds and the output file name.
+func (c RetentionConfig) Validate() error {
+	if c.MaxAge < 0 || c.Interval < 0 {
+		return fmt.Errorf("tasks.retention: max_age and interval must not be negative")
+	}
+	if c.MaxPerSession < 0 {
+		return fmt.Errorf("tasks.retention.max_per_session must not be negative, got %d", c.MaxPerSession)
+	}
+	if name := c.OutputFile; name != "" && (name != filepath.Base(name) || name == "." || name == ".." || name == "meta.json" || name == "checkpoints.jsonl") {
+		return fmt.Errorf("tasks.retention.output_file: %q must be a plain file name", name)
+	}
+	return nil
+}
+
+// AutoscaleConfig tunes actor autoscaling for providers with a max_actors
+// ceiling: extra actors are added when the backlog of ready tasks waiting for
+// a provider stays at or above the threshold, and retired once idle.
+type AutoscaleConfig struct {
+	BacklogThreshold int      `json:"backlog_threshold,omitempty"` // waiting tasks that trigger scaling up (default: 2)
+	Sustain          Duration `json:"sustain,omitempty"`           // how long the backlog must last (default: 30s)
+	IdleCooldown     Duration `json:"idle_cooldown,omitempty"`     // idle time before an extra actor is retired (default: 2m)
+}
+
+// AutoApproveRule auto-approves calls of a dangerous tool whose argument
+// matches a regular expression. The pattern must match the whole value.
+type AutoApproveRule struct {
+	Tool    string `json:"tool"`
+	Arg     string `json:"arg,omitempty"` // argument to match (empty = raw JSON arguments)
+	Pattern string `json:"pattern"`
 }
 
 // SkillsConfig configures the skill system.
 type SkillsConfig struct {
-	Dirs    []string `json:"dirs"`    // skill directories (default: [$OZZIE_PATH/skills])
-	Enabled []string `json:"enabled"` // enabled skill names (empty = all)
+	Dirs    []string             `json:"dirs"`             // skill directories (default: [$OZZIE_PATH/skills])
+	Enabled []string             `json:"enabled"`          // enabled skill names (empty = all)
+	Remote  []RemoteSkillsConfig `json:"remote,omitempty"` // shared skill libraries fetched over HTTP (none by default)
+}
+
+// RemoteSkillsConfig declares a remote skill library: an HTTPS index listing
+// its skills and the SHA-256 checksum of every file. A plain HTTP index is
+// only accepted with its own checksum pinned.
+type RemoteSkillsConfig struct {
+	Name   string `json:"name"`             // library name, shown as "remote:<name>"
+	URL    string `json:"url"`              // index URL
+	SHA256 string `json:"sha256,omitempty"` // pinned index checksum (hex); required for http URLs
+}
+
+// sha256Pattern matches a hex-encoded SHA-256 checksum.
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// Validate checks that remote libraries have unique names and HTTPS URLs, or
+// HTTP URLs with a pinned index checksum.
+func (c SkillsConfig) Validate() error {
+	seen := make(map[string]bool, len(c.Remote))
+	for i, r := range c.Remote {
+		if r.Name == "" || strings.ContainsAny(r.Name, `/\`) || r.Name == "." || r.Name == ".." {
+			return fmt.Errorf("skills.remote[%d]: invalid name %q", i, r.Name)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("skills.remote[%d]: duplicate name %q", i, r.Name)
+		}
+		seen[r.Name] = true
+		if r.SHA256 != "" && !sha256Pattern.MatchString(r.SHA256) {
+			return fmt.Errorf("skills.remote[%d]: sha256 must be 64 hex characters", i)
+		}
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("skills.remote[%d]: url must be an https URL, got %q", i, r.URL)
+		}
+		if u.Scheme == "http" && r.SHA256 == "" {
+			return fmt.Errorf("skills.remote[%d]: http url %q requires a pinned index sha256 (or use https)", i, r.URL)
+		}
+	}
+	return nil
 }
 
 // PluginsConfig configures the plugin system.
@@ -192,6 +448,7 @@ type PluginAuthorizationConfig struct {
 	HTTP       *HTTPAuthConfig       `json:"http,omitempty"`
 	Filesystem *FSAuthConfig         `json:"filesystem,omitempty"`
 	Secrets    *SecretsAuthConfig    `json:"secrets,omitempty"`
+	Env        map[string]string     `json:"env,omitempty"` // plugin-visible name → value; use ${{ .Env.NAME }} for secrets
 	Deny       []string              `json:"deny,omitempty"`
 	Resources  *ResourceLimitsConfig `json:"resources,omitempty"`
 }
@@ -222,6 +479,18 @@ type ResourceLimitsConfig struct {
 type GatewayConfig struct {
 	Host string `json:"host"`
 	Port int    `json:"port"`
+	// SessionIdleTimeout closes sessions without user messages or tasks for that long (0 = never).
+	SessionIdleTimeout Duration `json:"session_idle_timeout,omitempty"`
+	// MCP serves the tool registry over MCP (streamable HTTP) at /api/mcp.
+	// Dangerous tools only run when pre-approved (tools.allowed_dangerous).
+	MCP bool `json:"mcp,omitempty"`
+	// Metrics serves Prometheus metrics at /metrics, behind gateway auth
+	// (scrapers send the local token as a bearer token).
+	Metrics bool `json:"metrics,omitempty"`
+	// MetricsPublic serves /metrics without auth, for scrapers that cannot
+	// send a token. It exposes operational detail to anyone who can reach
+	// the gateway.
+	MetricsPublic bool `json:"metrics_public,omitempty"`
 }
 
 // ModelsConfig holds model provider configuration.
@@ -239,6 +508,7 @@ type ProviderConfig struct {
 	MaxTokens     int            `json:"max_tokens,omitempty"`
 	ContextWindow int            `json:"context_window,omitempty"` // total context window in tokens (0 = driver default)
 	MaxConcurrent int            `json:"max_concurrent,omitempty"`
+	MaxActors     int            `json:"max_actors,omitempty"` // autoscaling ceiling for background tasks (0 = fixed at max_concurrent)
 	Tags          []string       `json:"tags,omitempty"`
 	Capabilities  []string       `json:"capabilities,omitempty"`  // e.g. ["thinking", "tool_use", "coding"]
 	PromptPrefix  string         `json:"prompt_prefix,omitempty"` // custom instruction injected for this overlay
@@ -257,7 +527,7 @@ func (p ProviderConfig) Equal(other ProviderConfig) bool {
 	if p.Auth != other.Auth || p.MaxTokens != other.MaxTokens || p.ContextWindow != other.ContextWindow {
 		return false
 	}
-	if p.MaxConcurrent != other.MaxConcurrent || p.PromptPrefix != other.PromptPrefix {
+	if p.MaxConcurrent != other.MaxConcurrent || p.MaxActors != other.MaxActors || p.PromptPrefix != other.PromptPrefix {
 		return false
 	}
 	if p.Tier != other.Tier || p.Timeout != other.Timeout || p.Fallback != other.Fallback {
@@ -285,14 +555,106 @@ type AuthConfig struct {
 
 // EventsConfig holds event bus settings.
 type EventsConfig struct {
-	BufferSize int    `json:"buffer_size"`
-	LogLevel   string `json:"log_level"` // "debug" | "info" | "warn" | "error" (default: "info")
+	BufferSize   int      `json:"buffer_size"`
+	LogLevel     string   `json:"log_level"`               // "debug" | "info" | "warn" | "error" (default: "info")
+	LogFormat    string   `json:"log_format,omitempty"`    // "text" | "json" (default: "text")
+	Overflow     string   `json:"overflow,omitempty"`      // "drop_newest" | "drop_oldest" | "block" (default: "drop_newest")
+	BlockTimeout Duration `json:"block_timeout,omitempty"` // max Publish wait under "block" (default: 500ms)
 }
 
 // AgentConfig holds agent settings.
 type AgentConfig struct {
-	SystemPrompt      string `json:"system_prompt,omitempty"`
-	PreferredLanguage string `json:"preferred_language,omitempty"` // e.g. "en", "fr"
+	SystemPrompt         string `json:"system_prompt,omitempty"`
+	PreferredLanguage    string `json:"preferred_language,omitempty"`      // e.g. "en", "fr"
+	SessionVarsInContext bool   `json:"session_vars_in_context,omitempty"` // inject set_var variables into the prompt
+	RecentTasksInContext int    `json:"recent_tasks_in_context,omitempty"` // inject the last N finished tasks (0 = disabled)
+	RecentTasksTokens    int    `json:"recent_tasks_tokens,omitempty"`     // token budget for injected tasks (default: 500)
+	// MaxToolCallsBeforeSummary summarizes older tool results once a turn exceeds N tool calls (0 = disabled).
+	MaxToolCallsBeforeSummary int                `json:"max_tool_calls_before_summary,omitempty"`
+	ToolRecovery              ToolRecoveryConfig `json:"tool_recovery"` // in-place retries of failing tool calls
+	// ToolIdleTurns deactivates on-demand tools left unused for N turns (0 = never).
+	ToolIdleTurns int `json:"tool_idle_turns,omitempty"`
+	// MaxIterations caps the ReAct loop (model calls) of one run.
+	MaxIterations MaxIterationsConfig `json:"max_iterations"`
+	// ToolLoop detects the agent repeating the same tool call within a turn.
+	ToolLoop ToolLoopConfig `json:"tool_loop"`
+	// Streaming tunes how streamed responses are published to clients.
+	Streaming StreamingConfig `json:"streaming"`
+}
+
+// StreamingConfig batches the text deltas of streamed responses: instead of
+// one event (and WS frame) per model chunk, accumulated chunks are emitted
+// every FlushInterval, or earlier once FlushRunes are buffered.
+type StreamingConfig struct {
+	Coalesce      *bool    `json:"coalesce,omitempty"`       // batch deltas (default: true; false = one event per chunk)
+	FlushInterval Duration `json:"flush_interval,omitempty"` // max delay of a buffered delta (default: 50ms)
+	FlushRunes    int      `json:"flush_runes,omitempty"`    // flush once this many runes are buffered (default: 256)
+}
+
+// IsCoalesce returns true if stream deltas are batched (default: true).
+func (c StreamingConfig) IsCoalesce() bool {
+	return c.Coalesce == nil || *c.Coalesce
+}
+
+// Validate checks the flush thresholds.
+func (c StreamingConfig) Validate() error {
+	if c.FlushInterval < 0 || c.FlushRunes < 0 {
+		return fmt.Errorf("agent.streaming: flush_interval and flush_runes must not be negative")
+	}
+	if time.Duration(c.FlushInterval) > time.Second {
+		return fmt.Errorf("agent.streaming.flush_interval must be at most 1s, got %s", time.Duration(c.FlushInterval))
+	}
+	return nil
+}
+
+// ToolLoopConfig configures the detection of repeated identical tool calls
+// (same tool, same arguments) within a turn.
+type ToolLoopConfig struct {
+	Threshold  int `json:"threshold,omitempty"`   // identical calls before the agent is nudged to change approach (default: 3)
+	MaxRepeats int `json:"max_repeats,omitempty"` // identical calls before the run is stopped (default: 0 = never)
+}
+
+// Validate checks the thresholds.
+func (c ToolLoopConfig) Validate() error {
+	if c.Threshold < 0 || c.MaxRepeats < 0 {
+		return fmt.Errorf("agent.tool_loop: threshold and max_repeats must not be negative")
+	}
+	if c.MaxRepeats > 0 && c.MaxRepeats <= cmp.Or(c.Threshold, 3) {
+		return fmt.Errorf("agent.tool_loop.max_repeats (%d) must be greater than threshold, so the agent is nudged before being stopped", c.MaxRepeats)
+	}
+	return nil
+}
+
+// MaxIterationsConfig caps the ReAct iterations of agent runs per mode.
+// Zero values select the defaults.
+type MaxIterationsConfig struct {
+	Interactive int `json:"interactive,omitempty"` // one conversation turn (default: 25)
+	Task        int `json:"task,omitempty"`        // one background task (default: 30; submit_task can override, up to task_limit)
+	TaskLimit   int `json:"task_limit,omitempty"`  // highest cap submit_task may request (default: task)
+}
+
+// Validate checks that no cap is negative and that the task limit is not
+// below the task cap.
+func (c MaxIterationsConfig) Validate() error {
+	if c.Interactive < 0 || c.Task < 0 || c.TaskLimit < 0 {
+		return fmt.Errorf("agent.max_iterations: interactive, task and task_limit must not be negative")
+	}
+	if c.TaskLimit > 0 && c.TaskLimit < c.Task {
+		return fmt.Errorf("agent.max_iterations.task_limit (%d) must not be below task (%d)", c.TaskLimit, c.Task)
+	}
+	return nil
+}
+
+// ToolRecoveryConfig configures in-place retries of failing tool calls.
+// Transient errors are always retried; other errors reach the model at once.
+type ToolRecoveryConfig struct {
+	MaxAttempts   int      `json:"max_attempts,omitempty"`    // runs per tool call, first included (default: 3, 1 = no retry)
+	Backoff       Duration `json:"backoff,omitempty"`         // delay before the first retry, doubled each time (default: 500ms)
+	Recoverable   []string `json:"recoverable,omitempty"`     // regexps of other errors worth retrying (idempotent tools only)
+	OmitErrorText bool     `json:"omit_error_text,omitempty"` // keep error messages out of the model's context
+	// IdempotentTools are tools safe to re-run in place, in addition to the
+	// built-in read-only ones (read_file, web_fetch, query_memories, ...).
+	IdempotentTools []string `json:"idempotent_tools,omitempty"`
 }
 
 // Duration wraps time.Duration for JSON unmarshaling.
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/tailscale/hujson"
 )
@@ -55,6 +56,33 @@ func Load(path string, opts ...LoadOption) (*Config, error) {
 	}
 
 	applyDefaults(&cfg)
+	if err := cfg.Limits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Sandbox.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Sessions.Redaction.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.TUI.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Tasks.Retention.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Skills.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Agent.MaxIterations.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Agent.ToolLoop.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Agent.Streaming.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 	return &cfg, nil
 }
 
@@ -101,6 +129,19 @@ func applyDefaults(cfg *Config) {
 	if cfg.Events.LogLevel == "" {
 		cfg.Events.LogLevel = "info"
 	}
+	cfg.Limits = cfg.Limits.WithDefaults()
+	if cfg.Tools.SlowThreshold == 0 {
+		cfg.Tools.SlowThreshold = Duration(10 * time.Second)
+	}
+	if cfg.Tools.MaxParallel == 0 {
+		cfg.Tools.MaxParallel = 4
+	}
+	if cfg.Agent.Streaming.FlushInterval == 0 {
+		cfg.Agent.Streaming.FlushInterval = Duration(50 * time.Millisecond)
+	}
+	if cfg.Agent.Streaming.FlushRunes == 0 {
+		cfg.Agent.Streaming.FlushRunes = 256
+	}
 	if len(cfg.Skills.Dirs) == 0 {
 		cfg.Skills.Dirs = []string{filepath.Join(OzziePath(), "skills")}
 	}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -107,6 +109,95 @@ func TestLoadDefaults_LogLevel(t *testing.T) {
 	}
 }
 
+func TestLoad_Limits(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{"limits": {"task_output_chars": 4000}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Limits.TaskOutputChars != 4000 || cfg.Limits.DependencyOutputChars != 1000 {
+		t.Errorf("unexpected limits: %+v", cfg.Limits)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"limits": {"task_list_size": -1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "limits.task_list_size") {
+		t.Errorf("expected limits validation error, got %v", err)
+	}
+}
+
+func TestLoad_SandboxDenyRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.jsonc")
+	content := `{"sandbox": {"deny_rules": [{"pattern": "\\bcurl\\b.*\\|\\s*sh", "reason": "pipe to shell"}], "disabled_rules": ["sudo"]}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Sandbox.DenyRules) != 1 || cfg.Sandbox.DenyRules[0].Reason != "pipe to shell" || cfg.Sandbox.DisabledRules[0] != "sudo" {
+		t.Errorf("unexpected sandbox rules: %+v", cfg.Sandbox)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"sandbox": {"deny_rules": [{"pattern": "(unclosed"}]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "sandbox.deny_rules[0]") {
+		t.Errorf("expected deny rule validation error, got %v", err)
+	}
+}
+
+func TestLoad_SessionRedaction(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc := cfg.Sessions.Redaction; !rc.IsRedactionEnabled() || rc.IsEntropyEnabled() {
+		t.Errorf("redaction should be enabled by default, entropy masking opt-in: %+v", rc)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"sessions": {"redaction": {"patterns": ["ok", "(unclosed"]}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "sessions.redaction.patterns[1]") {
+		t.Errorf("expected pattern validation error, got %v", err)
+	}
+}
+
+func TestLoad_TUINotifications(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{"tui": {"notifications": "desktop"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TUI.Notifications != "desktop" {
+		t.Errorf("notifications = %q, want desktop", cfg.TUI.Notifications)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"tui": {"notifications": "loud"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "tui.notifications") {
+		t.Errorf("expected notifications validation error, got %v", err)
+	}
+}
+
 func TestExpandEnvTemplates(t *testing.T) {
 	t.Setenv("TEST_KEY", "my-secret")
 	result := expandEnvTemplates(`{"key": "${{ .Env.TEST_KEY }}"}`, nil)
@@ -149,3 +240,138 @@ func TestExpandEnvTemplates_JSONEscape(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_TaskRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{"tasks": {"retention": {"max_age": "720h", "max_per_session": 50}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := cfg.Tasks.Retention
+	if time.Duration(rc.MaxAge) != 720*time.Hour || rc.MaxPerSession != 50 || !rc.IsKeepOutputs() {
+		t.Errorf("unexpected retention: %+v", rc)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"tasks": {"retention": {"output_file": "../escape.md"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "tasks.retention.output_file") {
+		t.Errorf("expected output_file validation error, got %v", err)
+	}
+}
+
+func TestLoad_RemoteSkills(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		wantErr string
+	}{
+		{"valid", `[{"name": "team", "url": "https://example.com/skills/index.json"}]`, ""},
+		{"not http", `[{"name": "team", "url": "file:///etc/skills.json"}]`, "skills.remote[0]: url"},
+		{"http unpinned", `[{"name": "team", "url": "http://example.com/index.json"}]`, "requires a pinned index sha256"},
+		{"http pinned", `[{"name": "team", "url": "http://example.com/index.json", "sha256": "` + strings.Repeat("ab", 32) + `"}]`, ""},
+		{"bad sha256", `[{"name": "team", "url": "https://example.com/index.json", "sha256": "abc"}]`, "skills.remote[0]: sha256"},
+		{"path name", `[{"name": "../team", "url": "https://example.com/index.json"}]`, "skills.remote[0]: invalid name"},
+		{"duplicate", `[{"name": "team", "url": "https://a.example/i.json"}, {"name": "team", "url": "https://b.example/i.json"}]`, "skills.remote[1]: duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.jsonc")
+			if err := os.WriteFile(path, []byte(`{"skills": {"remote": `+tt.remote+`}}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_MaxIterations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{"agent": {"max_iterations": {"interactive": 10, "task": 60}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mi := cfg.Agent.MaxIterations; mi.Interactive != 10 || mi.Task != 60 {
+		t.Errorf("unexpected max_iterations: %+v", mi)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"agent": {"max_iterations": {"task": -1}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "agent.max_iterations") {
+		t.Errorf("expected max_iterations validation error, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"agent": {"max_iterations": {"task": 60, "task_limit": 40}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "agent.max_iterations.task_limit") {
+		t.Errorf("expected task_limit validation error, got %v", err)
+	}
+}
+
+func TestLoad_ToolLoop(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolLoop string
+		wantErr  string
+	}{
+		{"defaults", `{}`, ""},
+		{"stop after nudge", `{"threshold": 3, "max_repeats": 6}`, ""},
+		{"negative", `{"threshold": -1}`, "agent.tool_loop"},
+		{"stop before nudge", `{"max_repeats": 2}`, "agent.tool_loop.max_repeats"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.jsonc")
+			if err := os.WriteFile(path, []byte(`{"agent": {"tool_loop": `+tt.toolLoop+`}}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Streaming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := cfg.Agent.Streaming
+	if !sc.IsCoalesce() || time.Duration(sc.FlushInterval) != 50*time.Millisecond || sc.FlushRunes != 256 {
+		t.Errorf("unexpected streaming defaults: %+v", sc)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"agent": {"streaming": {"flush_interval": "5s"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "agent.streaming.flush_interval") {
+		t.Errorf("expected flush_interval validation error, got %v", err)
+	}
+}
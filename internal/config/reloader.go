@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -59,6 +60,11 @@ func (r *Reloader) Reload() error {
 	if err != nil {
 		return fmt.Errorf("reload config: %w", err)
 	}
+	// Keep the running config when the new one would not start
+	var verr *ValidationError
+	if errors.As(Validate(cfg), &verr) && verr.Fatal() {
+		return fmt.Errorf("reload config: %w", verr)
+	}
 
 	r.current.Store(cfg)
 	slog.Info("config reloaded")
@@ -89,3 +89,28 @@ func TestReloader_ReloadMissingDotenv(t *testing.T) {
 		t.Fatalf("Reload with missing .env: %v", err)
 	}
 }
+
+func TestReloader_ReloadInvalidKeepsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.jsonc")
+
+	configContent := `{"gateway": {"port": 70000}, "models": {"default": "missing", "providers": {"a": {"driver": "anthropic", "model": "m"}}}}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	initial := &Config{}
+	r := NewReloader(configPath, filepath.Join(dir, ".env"), initial, nil)
+	var called atomic.Bool
+	r.OnReload(func(*Config) { called.Store(true) })
+
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected invalid config to be rejected")
+	}
+	if r.Current() != initial {
+		t.Error("Current() changed after a rejected reload")
+	}
+	if called.Load() {
+		t.Error("listener called after a rejected reload")
+	}
+}
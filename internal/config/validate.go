@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Drivers known to the model and embedding factories.
+var (
+	knownModelDrivers     = []string{"anthropic", "openai", "openai-like", "mistral", "gemini", "ollama"}
+	knownEmbeddingDrivers = []string{"openai", "ollama", "mistral", "gemini"}
+)
+
+// ValidationError aggregates every problem found by Validate. Errors are
+// fatal: the gateway refuses to start. Warnings are logged and ignored.
+type ValidationError struct {
+	Errors   []string
+	Warnings []string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config: %d error(s), %d warning(s)", len(e.Errors), len(e.Warnings))
+	for _, msg := range e.Errors {
+		b.WriteString("\n  error: " + msg)
+	}
+	for _, msg := range e.Warnings {
+		b.WriteString("\n  warning: " + msg)
+	}
+	return b.String()
+}
+
+// Fatal reports whether at least one problem is an error.
+func (e *ValidationError) Fatal() bool {
+	return len(e.Errors) > 0
+}
+
+func (e *ValidationError) errorf(format string, args ...any) {
+	e.Errors = append(e.Errors, fmt.Sprintf(format, args...))
+}
+
+func (e *ValidationError) warnf(format string, args ...any) {
+	e.Warnings = append(e.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Validate checks a loaded config for problems that would otherwise only
+// surface later: unknown drivers and provider references, unset auth env
+// vars, missing skill dirs, incoherent embedding settings, relative sandbox
+// paths and an out-of-range gateway port. It returns nil or a
+// *ValidationError listing every problem found.
+func Validate(cfg *Config) error {
+	v := &ValidationError{}
+
+	if cfg.Gateway.Port < 1 || cfg.Gateway.Port > 65535 {
+		v.errorf("gateway.port %d out of range (1-65535)", cfg.Gateway.Port)
+	}
+	if f := strings.ToLower(cfg.Events.LogFormat); f != "" && f != "text" && f != "json" {
+		v.warnf("events.log_format %q unknown (want text or json), using text", cfg.Events.LogFormat)
+	}
+
+	validateModels(v, cfg.Models)
+	validateEmbedding(v, cfg.Embedding)
+
+	for _, dir := range cfg.Skills.Dirs {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			v.warnf("skills.dirs: %q is not a directory", dir)
+		}
+	}
+	for _, p := range cfg.Sandbox.AllowedPaths {
+		if !filepath.IsAbs(p) {
+			v.errorf("sandbox.allowed_paths: %q must be absolute", p)
+		}
+	}
+
+	if len(v.Errors) == 0 && len(v.Warnings) == 0 {
+		return nil
+	}
+	return v
+}
+
+func validateModels(v *ValidationError, m ModelsConfig) {
+	names := make([]string, 0, len(m.Providers))
+	for name := range m.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if m.Default != "" && len(m.Providers) > 0 {
+		if _, ok := m.Providers[m.Default]; !ok {
+			v.errorf("models.default %q is not a configured provider (have: %s)", m.Default, strings.Join(names, ", "))
+		}
+	}
+
+	for _, name := range names {
+		p := m.Providers[name]
+		prefix := "models.providers." + name
+		if !slices.Contains(knownModelDrivers, strings.ToLower(p.Driver)) {
+			v.errorf("%s.driver %q unknown (want one of: %s)", prefix, p.Driver, strings.Join(knownModelDrivers, ", "))
+		}
+		if p.Model == "" {
+			v.errorf("%s.model is required", prefix)
+		}
+		if p.Fallback != "" {
+			if p.Fallback == name {
+				v.errorf("%s.fallback refers to itself", prefix)
+			} else if _, ok := m.Providers[p.Fallback]; !ok {
+				v.errorf("%s.fallback %q is not a configured provider", prefix, p.Fallback)
+			}
+		}
+		validateAuth(v, prefix, p.Auth)
+	}
+}
+
+func validateEmbedding(v *ValidationError, e EmbeddingConfig) {
+	if !e.IsEnabled() {
+		return
+	}
+	if !slices.Contains(knownEmbeddingDrivers, strings.ToLower(e.Driver)) {
+		v.errorf("embedding.driver %q unknown (want one of: %s)", e.Driver, strings.Join(knownEmbeddingDrivers, ", "))
+	}
+	if e.Model == "" {
+		v.errorf("embedding.model is required when embedding is enabled")
+	}
+	if e.Dims < 0 {
+		v.errorf("embedding.dims must not be negative, got %d", e.Dims)
+	}
+	validateAuth(v, "embedding", e.Auth)
+}
+
+// validateAuth warns about ${VAR} references to unset environment variables,
+// which resolve to an empty credential at model init.
+func validateAuth(v *ValidationError, prefix string, a AuthConfig) {
+	fields := []struct{ name, value string }{{"api_key", a.APIKey}, {"token", a.Token}}
+	for _, f := range fields {
+		value := strings.TrimSpace(f.value)
+		if !strings.HasPrefix(value, "${") || !strings.HasSuffix(value, "}") {
+			continue
+		}
+		if name := value[2 : len(value)-1]; os.Getenv(name) == "" {
+			v.warnf("%s.auth.%s references unset environment variable %s", prefix, f.name, name)
+		}
+	}
+}
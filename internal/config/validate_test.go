@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	enabled := true
+	return &Config{
+		Gateway: GatewayConfig{Host: "127.0.0.1", Port: 18420},
+		Models: ModelsConfig{
+			Default: "main",
+			Providers: map[string]ProviderConfig{
+				"main":  {Driver: "anthropic", Model: "claude-sonnet-4-20250514", Fallback: "local"},
+				"local": {Driver: "ollama", Model: "qwen3"},
+			},
+		},
+		Embedding: EmbeddingConfig{Enabled: &enabled, Driver: "ollama", Model: "nomic-embed-text", Dims: 768},
+		Skills:    SkillsConfig{Dirs: []string{t.TempDir()}},
+		Sandbox:   SandboxConfig{AllowedPaths: []string{"/tmp"}},
+	}
+}
+
+func TestValidate_OK(t *testing.T) {
+	if err := Validate(validConfig(t)); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+}
+
+func TestValidate_AggregatesProblems(t *testing.T) {
+	t.Setenv("OZZIE_TEST_UNSET_KEY", "")
+	cfg := validConfig(t)
+	cfg.Gateway.Port = 70000
+	cfg.Models.Default = "mian"
+	cfg.Models.Providers["main"] = ProviderConfig{
+		Driver: "antropic", Model: "x", Fallback: "nope",
+		Auth: AuthConfig{APIKey: "${OZZIE_TEST_UNSET_KEY}"},
+	}
+	cfg.Embedding.Model = ""
+	cfg.Skills.Dirs = append(cfg.Skills.Dirs, filepath.Join(t.TempDir(), "missing"))
+	cfg.Sandbox.AllowedPaths = []string{"relative/dir"}
+
+	var verr *ValidationError
+	if !errors.As(Validate(cfg), &verr) {
+		t.Fatal("Validate() did not return a *ValidationError")
+	}
+	if !verr.Fatal() {
+		t.Error("Fatal() = false, want true")
+	}
+
+	wantErrors := []string{"gateway.port 70000", `models.default "mian"`, `main.driver "antropic"`,
+		`main.fallback "nope"`, "embedding.model", `"relative/dir" must be absolute`}
+	if len(verr.Errors) != len(wantErrors) {
+		t.Errorf("got %d errors, want %d:\n%v", len(verr.Errors), len(wantErrors), verr)
+	}
+	for _, want := range wantErrors {
+		if !strings.Contains(verr.Error(), want) {
+			t.Errorf("missing error %q in:\n%v", want, verr)
+		}
+	}
+
+	wantWarnings := []string{"OZZIE_TEST_UNSET_KEY", "missing"}
+	if len(verr.Warnings) != len(wantWarnings) {
+		t.Errorf("got %d warnings, want %d: %v", len(verr.Warnings), len(wantWarnings), verr.Warnings)
+	}
+}
+
+func TestValidate_WarningsOnly(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Events.LogFormat = "xml"
+
+	var verr *ValidationError
+	if !errors.As(Validate(cfg), &verr) || verr.Fatal() || len(verr.Warnings) != 1 {
+		t.Fatalf("Validate() = %v, want a single warning", verr)
+	}
+}
@@ -1,6 +1,8 @@
 // Package actors provides capacity-aware LLM orchestration via typed slots (actors).
 package actors
 
+import "time"
+
 // ActorStatus represents the state of an actor slot.
 type ActorStatus string
 
@@ -13,6 +15,7 @@ const (
 // This decouples the domain package from internal/config.
 type ProviderSpec struct {
 	MaxConcurrent int      `json:"max_concurrent"`
+	MaxActors     int      `json:"max_actors,omitempty"` // autoscaling ceiling (<= MaxConcurrent = fixed size)
 	Tags          []string `json:"tags,omitempty"`
 	Capabilities  []string `json:"capabilities,omitempty"`
 	PromptPrefix  string   `json:"prompt_prefix,omitempty"`
@@ -27,6 +30,9 @@ type Actor struct {
 	PromptPrefix string      `json:"prompt_prefix,omitempty"`
 	Status       ActorStatus `json:"status"`
 	CurrentTask  string      `json:"current_task,omitempty"`
+
+	extra     bool      // added by autoscaling, retired once idle long enough
+	idleSince time.Time // last transition to idle
 }
 
 // MatchesTags returns true if the actor supports all requested tags.
@@ -0,0 +1,159 @@
+package actors
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+// Autoscaling defaults, applied to zero AutoscaleConfig fields.
+const (
+	defaultBacklogThreshold = 2
+	defaultBacklogSustain   = 30 * time.Second
+	defaultIdleCooldown     = 2 * time.Minute
+)
+
+// AutoscaleConfig tunes how the pool grows providers with a MaxActors
+// ceiling above their MaxConcurrent base.
+type AutoscaleConfig struct {
+	// BacklogThreshold is the number of ready tasks waiting for a provider
+	// that triggers scaling up (default: 2).
+	BacklogThreshold int
+	// SustainFor is how long the backlog must stay at or above the threshold
+	// before actors are added (default: 30s).
+	SustainFor time.Duration
+	// IdleCooldown is how long an added actor stays idle before it is
+	// retired (default: 2m).
+	IdleCooldown time.Duration
+}
+
+func (c AutoscaleConfig) withDefaults() AutoscaleConfig {
+	if c.BacklogThreshold <= 0 {
+		c.BacklogThreshold = defaultBacklogThreshold
+	}
+	if c.SustainFor <= 0 {
+		c.SustainFor = defaultBacklogSustain
+	}
+	if c.IdleCooldown <= 0 {
+		c.IdleCooldown = defaultIdleCooldown
+	}
+	return c
+}
+
+// scalable reports whether a provider may grow beyond its base actors.
+func (s ProviderSpec) scalable() bool {
+	return s.MaxActors > max(s.MaxConcurrent, 1)
+}
+
+// newActor creates the next idle actor of a provider.
+// Caller must hold p.mu (or own the pool exclusively).
+func (p *ActorPool) newActor(name string, spec ProviderSpec, extra bool) *Actor {
+	i := p.actorSeq[name]
+	p.actorSeq[name] = i + 1
+	return &Actor{
+		ID:           fmt.Sprintf("%s-%d", name, i),
+		ProviderName: name,
+		Tags:         spec.Tags,
+		Capabilities: spec.Capabilities,
+		PromptPrefix: spec.PromptPrefix,
+		Status:       ActorIdle,
+		extra:        extra,
+		idleSince:    time.Now(),
+	}
+}
+
+// actorCounts returns the number of actors per provider.
+// Caller must hold p.mu.
+func (p *ActorPool) actorCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, a := range p.actors {
+		counts[a.ProviderName]++
+	}
+	return counts
+}
+
+// backlogProvider returns the scalable provider a task waiting for an actor
+// counts against: its preferred provider, or else the first matching
+// provider by name that is still below its ceiling. Returns "" when no
+// scalable provider can serve the task.
+// Caller must hold p.mu.
+func (p *ActorPool) backlogProvider(t *brain.Task, counts map[string]int) string {
+	preferred := p.preferredProvider(t)
+	var fallback string
+	for _, name := range p.scalableNames {
+		if preferred != "" && name != preferred {
+			continue
+		}
+		spec := p.providers[name]
+		probe := Actor{Tags: spec.Tags, Capabilities: spec.Capabilities}
+		if !probe.MatchesTags(t.Tags) || !probe.MatchesCapabilities(t.Config.RequiredCapabilities) {
+			continue
+		}
+		if counts[name] < spec.MaxActors {
+			return name
+		}
+		if fallback == "" {
+			fallback = name
+		}
+	}
+	return fallback
+}
+
+// autoscale adds actors to providers whose backlog stayed at or above the
+// threshold for SustainFor, up to their MaxActors ceiling, and retires extra
+// actors idle for IdleCooldown. Providers in cooldown (rate limited or
+// unavailable) are not grown. backlog counts the ready tasks that found no
+// idle actor, per provider. Returns true when actors were added.
+func (p *ActorPool) autoscale(backlog map[string]int, now time.Time) bool {
+	if len(p.scalableNames) == 0 {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Retire extra actors idle for too long.
+	p.actors = slices.DeleteFunc(p.actors, func(a *Actor) bool {
+		retire := a.extra && a.Status == ActorIdle && now.Sub(a.idleSince) >= p.autoscaleCfg.IdleCooldown
+		if retire {
+			slog.Info("actor pool scaled down", "actor", a.ID, "provider", a.ProviderName)
+		}
+		return retire
+	})
+
+	counts := p.actorCounts()
+	grew := false
+	for _, name := range p.scalableNames {
+		n := backlog[name]
+		if n < p.autoscaleCfg.BacklogThreshold {
+			delete(p.backlogSince, name)
+			continue
+		}
+		since, ok := p.backlogSince[name]
+		if !ok {
+			p.backlogSince[name] = now
+			continue
+		}
+		if now.Sub(since) < p.autoscaleCfg.SustainFor {
+			continue
+		}
+		if expiry, ok := p.providerCooldown[name]; ok && now.Before(expiry) {
+			continue
+		}
+
+		spec := p.providers[name]
+		add := min(n, spec.MaxActors-counts[name])
+		for range add {
+			p.actors = append(p.actors, p.newActor(name, spec, true))
+		}
+		if add > 0 {
+			grew = true
+			slog.Info("actor pool scaled up", "provider", name, "added", add, "actors", counts[name]+add, "max_actors", spec.MaxActors, "backlog", n)
+		}
+		// The next growth needs another sustained backlog.
+		p.backlogSince[name] = now
+	}
+	return grew
+}
@@ -0,0 +1,137 @@
+package actors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+func newAutoscalePool(t *testing.T, providers map[string]ProviderSpec) *ActorPool {
+	t.Helper()
+	bus := events.NewBus(64)
+	t.Cleanup(bus.Close)
+
+	return NewActorPool(ActorPoolConfig{
+		Providers: providers,
+		Store:     newMemStore(),
+		Bus:       bus,
+		Autoscale: AutoscaleConfig{BacklogThreshold: 2, SustainFor: time.Minute, IdleCooldown: 2 * time.Minute},
+	})
+}
+
+func TestAutoscale_GrowsAfterSustainedBacklog(t *testing.T) {
+	pool := newAutoscalePool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1, MaxActors: 3},
+		"ollama": {MaxConcurrent: 1},
+	})
+	t0 := time.Now()
+	backlog := map[string]int{"claude": 5}
+
+	if pool.autoscale(backlog, t0) || pool.autoscale(backlog, t0.Add(30*time.Second)) {
+		t.Fatal("pool grew before the backlog was sustained")
+	}
+	if !pool.autoscale(backlog, t0.Add(61*time.Second)) {
+		t.Fatal("pool did not grow after a sustained backlog")
+	}
+	if got := pool.actorCounts()["claude"]; got != 3 {
+		t.Errorf("claude actors = %d, want the ceiling (3)", got)
+	}
+
+	ids := map[string]bool{}
+	for _, a := range pool.actors {
+		if ids[a.ID] {
+			t.Errorf("duplicate actor ID %q", a.ID)
+		}
+		ids[a.ID] = true
+	}
+
+	// A short backlog resets the timer.
+	pool.autoscale(map[string]int{"claude": 1}, t0.Add(62*time.Second))
+	if _, ok := pool.backlogSince["claude"]; ok {
+		t.Error("backlog below the threshold should reset the timer")
+	}
+}
+
+func TestAutoscale_RetiresIdleExtras(t *testing.T) {
+	pool := newAutoscalePool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1, MaxActors: 3},
+	})
+	pool.mu.Lock()
+	busy := pool.newActor("claude", pool.providers["claude"], true)
+	busy.Status = ActorBusy
+	pool.actors = append(pool.actors, busy, pool.newActor("claude", pool.providers["claude"], true))
+	pool.mu.Unlock()
+
+	pool.autoscale(nil, time.Now().Add(time.Minute))
+	if got := len(pool.actors); got != 3 {
+		t.Fatalf("actors = %d, want 3 before the idle cooldown", got)
+	}
+	pool.autoscale(nil, time.Now().Add(3*time.Minute))
+	if got := len(pool.actors); got != 2 {
+		t.Fatalf("actors = %d, want 2 (base + busy extra)", got)
+	}
+	for _, a := range pool.actors {
+		if a.extra && a != busy {
+			t.Errorf("idle extra actor %q was not retired", a.ID)
+		}
+	}
+}
+
+func TestAutoscale_RespectsProviderCooldown(t *testing.T) {
+	pool := newAutoscalePool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1, MaxActors: 3},
+	})
+	t0 := time.Now()
+	pool.mu.Lock()
+	pool.providerCooldown["claude"] = t0.Add(5 * time.Minute)
+	pool.mu.Unlock()
+
+	backlog := map[string]int{"claude": 4}
+	pool.autoscale(backlog, t0)
+	if pool.autoscale(backlog, t0.Add(2*time.Minute)) {
+		t.Error("a provider in cooldown must not grow")
+	}
+}
+
+func TestSchedule_CountsBacklog(t *testing.T) {
+	pool := newAutoscalePool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1, MaxActors: 3, Tags: []string{"coding"}},
+		"ollama": {MaxConcurrent: 1},
+	})
+	pool.mu.Lock()
+	for _, a := range pool.actors {
+		a.Status = ActorBusy
+	}
+	pool.mu.Unlock()
+
+	for _, tags := range [][]string{{"coding"}, nil, {"writing"}} {
+		if err := pool.Submit(&brain.Task{Title: "work", Tags: tags}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pool.schedule()
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if _, ok := pool.backlogSince["claude"]; !ok {
+		t.Error("two tasks waiting for claude should start the backlog timer")
+	}
+}
+
+func TestShouldInline_ScalablePool(t *testing.T) {
+	pool := newAutoscalePool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1, MaxActors: 2},
+	})
+	if pool.ShouldInline() {
+		t.Error("a pool that can scale past one actor should not inline")
+	}
+
+	pool.mu.Lock()
+	pool.actors = append(pool.actors, pool.newActor("claude", pool.providers["claude"], true))
+	pool.mu.Unlock()
+	if pool.ShouldInline() {
+		t.Error("a scaled-up pool should not inline")
+	}
+}
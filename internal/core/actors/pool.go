@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +20,14 @@ const preemptionTimeout = 30 * time.Second
 // providerCooldownDuration is how long a provider is skipped after returning ErrModelUnavailable.
 const providerCooldownDuration = 2 * time.Minute
 
+// preemptionAgingStep is how long a preempted task waits to gain one priority
+// level against interactive preemption.
+const preemptionAgingStep = 2 * time.Minute
+
+// maxPreemptions is how many times a task can be preempted before it runs
+// to completion undisturbed.
+const maxPreemptions = 3
+
 // defaultMaxRetries is applied to tasks that don't specify MaxRetries.
 const defaultMaxRetries = 3
 
@@ -29,12 +39,21 @@ type runningTask struct {
 	preemptCh chan struct{} // closed to signal cooperative preemption
 }
 
+// preemptionRecord tracks how often a task was preempted, to age it out of
+// preemption and guarantee eventual progress.
+type preemptionRecord struct {
+	count int
+	first time.Time // first preemption
+}
+
 // ActorPool manages LLM capacity slots and task scheduling.
 type ActorPool struct {
 	mu               sync.Mutex
+	submitMu         sync.Mutex // serializes submissions with an idempotency key
 	actors           []*Actor
-	runners          map[string]*runningTask // taskID → running state
-	providerCooldown map[string]time.Time    // provider → cooldown expiry
+	runners          map[string]*runningTask      // taskID → running state
+	providerCooldown map[string]time.Time         // provider → cooldown expiry
+	preemptions      map[string]*preemptionRecord // taskID → preemption history, until the task finishes
 	store            brain.TaskStore
 	bus              events.EventBus
 	runnerFactory    brain.RunnerFactory
@@ -48,6 +67,15 @@ type ActorPool struct {
 
 	executorFactory brain.TaskExecutorFactory // creates a TaskExecutor for each task
 
+	quiet         *brain.QuietHours // holds pending tasks during quiet hours (optional)
+	quietDeferred map[string]bool   // tasks already reported as deferred (schedule loop only)
+
+	providers     map[string]ProviderSpec
+	actorSeq      map[string]int // provider → next actor index
+	autoscaleCfg  AutoscaleConfig
+	scalableNames []string             // providers with a MaxActors ceiling, sorted
+	backlogSince  map[string]time.Time // provider → start of a backlog above the threshold
+
 	scheduleCh chan struct{} // wake-up signal for the scheduler
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -67,11 +95,34 @@ type ActorPoolConfig struct {
 	Retriever        brain.MemoryRetriever       // pre-task memory retrieval (optional)
 	Perms            brain.ToolPermissionsSeeder // for seeding pre-approved tools (optional)
 	ExecutorFactory  brain.TaskExecutorFactory   // creates a TaskExecutor for each task
+	QuietHours       *brain.QuietHours           // pending tasks wait while quiet hours are active (optional)
+	Autoscale        AutoscaleConfig             // growth of providers with a MaxActors ceiling
 }
 
 // NewActorPool creates an ActorPool from provider configurations.
 func NewActorPool(cfg ActorPoolConfig) *ActorPool {
-	var actors []*Actor
+	p := &ActorPool{
+		runners:          make(map[string]*runningTask),
+		providerCooldown: make(map[string]time.Time),
+		preemptions:      make(map[string]*preemptionRecord),
+		store:            cfg.Store,
+		bus:              cfg.Bus,
+		runnerFactory:    cfg.RunnerFactory,
+		tierResolver:     cfg.TierResolver,
+		toolLookup:       cfg.ToolLookup,
+		skillRunner:      cfg.SkillRunner,
+		taskMiddlewares:  cfg.TaskMiddlewares,
+		retriever:        cfg.Retriever,
+		perms:            cfg.Perms,
+		executorFactory:  cfg.ExecutorFactory,
+		quiet:            cfg.QuietHours,
+		quietDeferred:    make(map[string]bool),
+		providers:        cfg.Providers,
+		actorSeq:         make(map[string]int),
+		autoscaleCfg:     cfg.Autoscale.withDefaults(),
+		backlogSince:     make(map[string]time.Time),
+		scheduleCh:       make(chan struct{}, 1),
+	}
 
 	for name, prov := range cfg.Providers {
 		n := prov.MaxConcurrent
@@ -79,33 +130,14 @@ func NewActorPool(cfg ActorPoolConfig) *ActorPool {
 			n = 1
 		}
 		for i := 0; i < n; i++ {
-			actors = append(actors, &Actor{
-				ID:           fmt.Sprintf("%s-%d", name, i),
-				ProviderName: name,
-				Tags:         prov.Tags,
-				Capabilities: prov.Capabilities,
-				PromptPrefix: prov.PromptPrefix,
-				Status:       ActorIdle,
-			})
+			p.actors = append(p.actors, p.newActor(name, prov, false))
+		}
+		if prov.scalable() {
+			p.scalableNames = append(p.scalableNames, name)
 		}
 	}
-
-	return &ActorPool{
-		actors:              actors,
-		runners:             make(map[string]*runningTask),
-		providerCooldown:    make(map[string]time.Time),
-		store:               cfg.Store,
-		bus:                 cfg.Bus,
-		runnerFactory:       cfg.RunnerFactory,
-		tierResolver:        cfg.TierResolver,
-		toolLookup:          cfg.ToolLookup,
-		skillRunner:         cfg.SkillRunner,
-		taskMiddlewares:     cfg.TaskMiddlewares,
-		retriever:           cfg.Retriever,
-		perms:               cfg.Perms,
-		executorFactory:     cfg.ExecutorFactory,
-		scheduleCh:          make(chan struct{}, 1),
-	}
+	slices.Sort(p.scalableNames)
+	return p
 }
 
 // Start launches the scheduler loop and subscribes to task completion events.
@@ -160,6 +192,18 @@ func (p *ActorPool) Submit(t *brain.Task) error {
 		t.MaxRetries = defaultMaxRetries
 	}
 
+	if t.IdempotencyKey != "" {
+		// Serialize keyed submissions so two concurrent duplicates cannot
+		// both miss the lookup.
+		p.submitMu.Lock()
+		defer p.submitMu.Unlock()
+		if existing := p.activeByKey(t.SessionID, t.IdempotencyKey); existing != nil {
+			slog.Info("task submission deduplicated", "task_id", existing.ID, "idempotency_key", t.IdempotencyKey)
+			*t = *existing
+			return nil
+		}
+	}
+
 	if err := p.store.Create(t); err != nil {
 		return err
 	}
@@ -175,12 +219,64 @@ func (p *ActorPool) Submit(t *brain.Task) error {
 	return nil
 }
 
+// activeByKey returns the pending or running task of a session submitted with
+// an idempotency key, or nil.
+func (p *ActorPool) activeByKey(sessionID, key string) *brain.Task {
+	list, err := p.store.List(brain.ListFilter{SessionID: sessionID, IdempotencyKey: key})
+	if err != nil {
+		slog.Warn("idempotency lookup", "idempotency_key", key, "error", err)
+		return nil
+	}
+	for _, t := range list {
+		// An empty session filter matches every session: keep exact matches.
+		if t.SessionID != sessionID {
+			continue
+		}
+		if t.Status == brain.TaskPending || t.Status == brain.TaskRunning {
+			return t
+		}
+	}
+	return nil
+}
+
 // Cancel cancels a running or pending task.
 func (p *ActorPool) Cancel(taskID string, reason string) error {
+	return p.cancelTask(taskID, reason, nil)
+}
+
+// CancelSession cancels every pending or running task of a session, including
+// preempted tasks waiting to resume and their descendants. Returns the tasks
+// that were cancelled, with their status before cancellation.
+func (p *ActorPool) CancelSession(sessionID string, reason string) ([]brain.CancelledTask, error) {
+	list, err := p.store.List(brain.ListFilter{SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled := []brain.CancelledTask{}
+	for _, t := range list {
+		if t.Status != brain.TaskPending && t.Status != brain.TaskRunning {
+			continue
+		}
+		// Descendants of an earlier task may already have been cancelled.
+		if slices.ContainsFunc(cancelled, func(c brain.CancelledTask) bool { return c.ID == t.ID }) {
+			continue
+		}
+		if err := p.cancelTask(t.ID, reason, &cancelled); err != nil {
+			return cancelled, err
+		}
+	}
+	return cancelled, nil
+}
+
+// cancelTask cancels a task and its active children, appending each cancelled
+// task to cancelled when non-nil.
+func (p *ActorPool) cancelTask(taskID string, reason string, cancelled *[]brain.CancelledTask) error {
 	p.mu.Lock()
 	if rt, ok := p.runners[taskID]; ok {
 		rt.cancel()
 	}
+	delete(p.preemptions, taskID)
 	p.mu.Unlock()
 
 	task, err := p.store.Get(taskID)
@@ -192,6 +288,7 @@ func (p *ActorPool) Cancel(taskID string, reason string) error {
 		return nil
 	}
 
+	previous := task.Status
 	now := time.Now()
 	task.Status = brain.TaskCancelled
 	task.CompletedAt = &now
@@ -210,6 +307,15 @@ func (p *ActorPool) Cancel(taskID string, reason string) error {
 		Reason: reason,
 	}, task.SessionID))
 
+	if cancelled != nil {
+		*cancelled = append(*cancelled, brain.CancelledTask{
+			ID:       task.ID,
+			Title:    task.Title,
+			Status:   previous,
+			ParentID: task.ParentTaskID,
+		})
+	}
+
 	// Cancel child tasks recursively
 	children, err := p.store.List(brain.ListFilter{ParentID: taskID})
 	if err != nil {
@@ -217,7 +323,7 @@ func (p *ActorPool) Cancel(taskID string, reason string) error {
 	}
 	for _, child := range children {
 		if child.Status == brain.TaskPending || child.Status == brain.TaskRunning {
-			_ = p.Cancel(child.ID, "parent cancelled")
+			_ = p.cancelTask(child.ID, "parent cancelled", cancelled)
 		}
 	}
 
@@ -253,10 +359,41 @@ func (p *ActorPool) Release(actor *Actor) {
 	p.mu.Lock()
 	actor.Status = ActorIdle
 	actor.CurrentTask = ""
+	actor.idleSince = time.Now()
 	p.mu.Unlock()
 	p.wakeScheduler()
 }
 
+// ProviderLoad is the actor occupancy of one provider.
+type ProviderLoad struct {
+	Provider string `json:"provider"`
+	Busy     int    `json:"busy"`
+	Total    int    `json:"total"`
+}
+
+// Load returns the busy and total actor counts per provider, sorted by name.
+func (p *ActorPool) Load() []ProviderLoad {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index := make(map[string]int)
+	var loads []ProviderLoad
+	for _, a := range p.actors {
+		i, ok := index[a.ProviderName]
+		if !ok {
+			i = len(loads)
+			index[a.ProviderName] = i
+			loads = append(loads, ProviderLoad{Provider: a.ProviderName})
+		}
+		loads[i].Total++
+		if a.Status == ActorBusy {
+			loads[i].Busy++
+		}
+	}
+	slices.SortFunc(loads, func(x, y ProviderLoad) int { return strings.Compare(x.Provider, y.Provider) })
+	return loads
+}
+
 // wakeScheduler sends a non-blocking signal to the schedule loop.
 func (p *ActorPool) wakeScheduler() {
 	select {
@@ -286,10 +423,21 @@ func (p *ActorPool) scheduleLoop() {
 
 // schedule assigns pending tasks to idle actors.
 // It also cancels tasks whose dependencies can never be satisfied (failed/cancelled).
+// During quiet hours, ready tasks stay pending until the window ends.
 func (p *ActorPool) schedule() {
 	// Fetch pending tasks outside the lock (store is independently thread-safe).
 	pending, _ := p.store.List(brain.ListFilter{Status: brain.TaskPending})
 
+	now := time.Now()
+	quiet := p.quiet.Active(now)
+	if !quiet {
+		clear(p.quietDeferred)
+	}
+
+	// Ready tasks left without an actor, per scalable provider.
+	backlog := make(map[string]int)
+	var counts map[string]int
+
 	// List returns sorted by UpdatedAt DESC, iterate in reverse for oldest first
 	for i := len(pending) - 1; i >= 0; i-- {
 		t := pending[i]
@@ -318,10 +466,23 @@ func (p *ActorPool) schedule() {
 			continue
 		}
 
+		if quiet {
+			p.deferForQuietHours(t, now)
+			continue
+		}
+
 		// Lock only for actor state mutations.
 		p.mu.Lock()
-		actor := p.findIdleActor("", t.Tags, t.Config.RequiredCapabilities)
+		actor := p.findIdleActor(p.preferredProvider(t), t.Tags, t.Config.RequiredCapabilities)
 		if actor == nil {
+			if len(p.scalableNames) > 0 {
+				if counts == nil {
+					counts = p.actorCounts()
+				}
+				if name := p.backlogProvider(t, counts); name != "" {
+					backlog[name]++
+				}
+			}
 			p.mu.Unlock()
 			if len(t.Tags) > 0 || len(t.Config.RequiredCapabilities) > 0 {
 				slog.Warn("no actor matches task requirements", "task_id", t.ID, "tags", t.Tags, "capabilities", t.Config.RequiredCapabilities)
@@ -333,6 +494,27 @@ func (p *ActorPool) schedule() {
 		p.startTask(t, actor)
 		p.mu.Unlock()
 	}
+
+	if p.autoscale(backlog, now) {
+		p.wakeScheduler()
+	}
+}
+
+// deferForQuietHours reports (once) that a ready task is held back by quiet hours.
+func (p *ActorPool) deferForQuietHours(t *brain.Task, now time.Time) {
+	if p.quietDeferred[t.ID] {
+		return
+	}
+	p.quietDeferred[t.ID] = true
+
+	until := p.quiet.Until(now)
+	slog.Info("task deferred by quiet hours", "task_id", t.ID, "until", until)
+	p.bus.Publish(events.NewTypedEventWithSession(events.SourceTask, events.ScheduleDeferredPayload{
+		TaskID: t.ID,
+		Title:  t.Title,
+		Action: "deferred",
+		Until:  until.Format(time.RFC3339),
+	}, t.SessionID))
 }
 
 // findIdleActor returns the first idle actor matching the provider (if non-empty), tags, and capabilities.
@@ -364,34 +546,40 @@ func (p *ActorPool) findIdleActor(providerName string, requiredTags []string, re
 	return nil
 }
 
-// preemptLowest requests preemption of the lowest-priority task on the given provider.
-// Returns the actor that will be freed once the task suspends.
+// preferredProvider returns the provider named by the task's model when an
+// actor serves it, or "" (any provider) so unknown models fall back to the default.
 // Caller must hold p.mu.
-func (p *ActorPool) preemptLowest(providerName string) *Actor {
-	var lowestRT *runningTask
-	lowestPriority := priorityRank(brain.PriorityHigh) + 1
-
-	for _, rt := range p.runners {
-		if rt.actor.ProviderName != providerName {
-			continue
-		}
-		task, err := p.store.Get(rt.taskID)
-		if err != nil {
-			continue
-		}
-		rank := priorityRank(task.Priority)
-		if rank < lowestPriority {
-			lowestPriority = rank
-			lowestRT = rt
+func (p *ActorPool) preferredProvider(t *brain.Task) string {
+	if t.Config.Model == "" {
+		return ""
+	}
+	for _, a := range p.actors {
+		if a.ProviderName == t.Config.Model {
+			return t.Config.Model
 		}
 	}
+	return ""
+}
 
+// preemptLowest requests preemption of the lowest-priority task on the given provider.
+// Returns the actor that will be freed once the task suspends.
+// Caller must hold p.mu.
+func (p *ActorPool) preemptLowest(providerName string) *Actor {
+	now := time.Now()
+	lowestRT := p.preemptionCandidate(providerName, now)
 	if lowestRT == nil {
 		return nil
 	}
 
+	rec, ok := p.preemptions[lowestRT.taskID]
+	if !ok {
+		rec = &preemptionRecord{first: now}
+		p.preemptions[lowestRT.taskID] = rec
+	}
+	rec.count++
+
 	slog.Info("preempting task for interactive use",
-		"task_id", lowestRT.taskID, "actor", lowestRT.actor.ID)
+		"task_id", lowestRT.taskID, "actor", lowestRT.actor.ID, "preemptions", rec.count)
 
 	// Signal cooperative preemption
 	select {
@@ -453,6 +641,48 @@ func (p *ActorPool) preemptLowest(providerName string) *Actor {
 	}
 }
 
+// preemptionCandidate returns the running task on the given provider with the
+// lowest effective priority, skipping tasks protected from preemption.
+// Returns nil when no task can be preempted.
+// Caller must hold p.mu.
+func (p *ActorPool) preemptionCandidate(providerName string, now time.Time) *runningTask {
+	var lowestRT *runningTask
+	lowestPriority := priorityRank(brain.PriorityHigh) + 1
+
+	for _, rt := range p.runners {
+		if rt.actor.ProviderName != providerName {
+			continue
+		}
+		task, err := p.store.Get(rt.taskID)
+		if err != nil {
+			continue
+		}
+		rank := p.effectiveRank(task, now)
+		if rank < lowestPriority {
+			lowestPriority = rank
+			lowestRT = rt
+		}
+	}
+	return lowestRT
+}
+
+// effectiveRank is the task's priority rank, raised by one level per
+// preemptionAgingStep since its first preemption. A task preempted
+// maxPreemptions times ranks above any priority, so it cannot be preempted
+// again: background work always makes progress under interactive load.
+// Caller must hold p.mu.
+func (p *ActorPool) effectiveRank(t *brain.Task, now time.Time) int {
+	rank := priorityRank(t.Priority)
+	rec, ok := p.preemptions[t.ID]
+	if !ok {
+		return rank
+	}
+	if rec.count >= maxPreemptions {
+		return priorityRank(brain.PriorityHigh) + 1
+	}
+	return rank + int(now.Sub(rec.first)/preemptionAgingStep)
+}
+
 // taskExecutionTimeout is the hard limit for a single task execution.
 const taskExecutionTimeout = 5 * time.Minute
 
@@ -479,11 +709,19 @@ func (p *ActorPool) startTask(t *brain.Task, actor *Actor) {
 			delete(p.runners, t.ID)
 			actor.Status = ActorIdle
 			actor.CurrentTask = ""
+			actor.idleSince = time.Now()
 			p.mu.Unlock()
 			p.wakeScheduler()
 		}()
 
 		p.executeTask(taskCtx, t, actor, preemptCh)
+
+		// A task back to pending was preempted or re-queued: keep its history.
+		if task, err := p.store.Get(t.ID); err != nil || task.Status != brain.TaskPending {
+			p.mu.Lock()
+			delete(p.preemptions, t.ID)
+			p.mu.Unlock()
+		}
 	}()
 }
 
@@ -610,10 +848,20 @@ func (p *ActorPool) dependenciesResolved(t *brain.Task) bool {
 	return true
 }
 
-// ShouldInline returns true when the pool has exactly one actor, meaning async
-// submission would deadlock (the single actor is occupied by the caller).
+// ShouldInline returns true when the pool can only ever have one actor,
+// meaning async submission would deadlock (the single actor is occupied by
+// the caller). A pool that can scale (max_actors > 1) is multi-actor: the
+// backlog grows it.
 func (p *ActorPool) ShouldInline() bool {
-	return len(p.actors) == 1
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	base := 0
+	for _, a := range p.actors {
+		if !a.extra {
+			base++
+		}
+	}
+	return base == 1 && len(p.scalableNames) == 0
 }
 
 // ExecuteInline runs a task synchronously in the caller's goroutine.
@@ -648,7 +896,14 @@ func (p *ActorPool) ExecuteInline(ctx context.Context, t *brain.Task) (string, e
 		return "", fmt.Errorf("inline: no runner factory configured")
 	}
 
+	p.mu.Lock()
+	if len(p.actors) == 0 {
+		p.mu.Unlock()
+		_ = p.failTaskDirect(t, fmt.Errorf("no actor available"))
+		return "", fmt.Errorf("inline: no actor available")
+	}
 	actor := p.actors[0]
+	p.mu.Unlock()
 
 	var tier brain.ModelTier
 	if p.tierResolver != nil {
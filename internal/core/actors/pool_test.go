@@ -70,6 +70,9 @@ func (s *memStore) List(filter brain.ListFilter) ([]*brain.Task, error) {
 		if filter.ParentID != "" && t.ParentTaskID != filter.ParentID {
 			continue
 		}
+		if filter.IdempotencyKey != "" && t.IdempotencyKey != filter.IdempotencyKey {
+			continue
+		}
 		cp := *t
 		result = append(result, &cp)
 	}
@@ -121,6 +124,14 @@ func (s *memStore) ReadOutput(taskID string) (string, error) {
 	return s.outputs[taskID], nil
 }
 
+func (s *memStore) WriteArtifact(string, brain.Artifact, []byte) error { return nil }
+
+func (s *memStore) ListArtifacts(string) ([]brain.Artifact, error) { return nil, nil }
+
+func (s *memStore) ReadArtifact(taskID, name string) (*brain.Artifact, []byte, error) {
+	return nil, nil, fmt.Errorf("artifact not found: %s", name)
+}
+
 var _ brain.TaskStore = (*memStore)(nil)
 
 func newTestPool(t *testing.T, providers map[string]ProviderSpec) *ActorPool {
@@ -206,6 +217,57 @@ func TestActorPoolCancel(t *testing.T) {
 	}
 }
 
+func TestActorPoolCancelSession(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1},
+	})
+
+	parent := &brain.Task{SessionID: "sess_a", Title: "parent"}
+	if err := pool.Submit(parent); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	// Child in another session: still cancelled as a descendant.
+	child := &brain.Task{SessionID: "sess_b", Title: "child", ParentTaskID: parent.ID}
+	if err := pool.Submit(child); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	other := &brain.Task{SessionID: "sess_b", Title: "other"}
+	if err := pool.Submit(other); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	cancelled, err := pool.CancelSession("sess_a", "testing")
+	if err != nil {
+		t.Fatalf("CancelSession: %v", err)
+	}
+	if len(cancelled) != 2 || cancelled[0].ID != parent.ID || cancelled[1].ID != child.ID {
+		t.Fatalf("cancelled = %+v, want parent then child", cancelled)
+	}
+	if cancelled[1].ParentID != parent.ID || cancelled[1].Status != brain.TaskPending {
+		t.Errorf("child summary = %+v", cancelled[1])
+	}
+
+	for id, want := range map[string]brain.TaskStatus{
+		parent.ID: brain.TaskCancelled,
+		child.ID:  brain.TaskCancelled,
+		other.ID:  brain.TaskPending,
+	} {
+		got, err := pool.Store().Get(id)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Status != want {
+			t.Errorf("%s status: got %s, want %s", got.Title, got.Status, want)
+		}
+	}
+
+	// Nothing left to cancel.
+	cancelled, err = pool.CancelSession("sess_a", "again")
+	if err != nil || len(cancelled) != 0 {
+		t.Errorf("second CancelSession = %+v, %v; want none", cancelled, err)
+	}
+}
+
 func TestActorCreation(t *testing.T) {
 	pool := newTestPool(t, map[string]ProviderSpec{
 		"claude": {MaxConcurrent: 2, Tags: []string{"coding", "chat"}},
@@ -235,6 +297,28 @@ func TestActorCreation(t *testing.T) {
 	}
 }
 
+func TestActorPoolLoad(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{
+		"local":  {MaxConcurrent: 3},
+		"claude": {MaxConcurrent: 2},
+	})
+
+	actor, err := pool.AcquireInteractive("local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Release(actor)
+
+	got := pool.Load()
+	want := []ProviderLoad{
+		{Provider: "claude", Busy: 0, Total: 2},
+		{Provider: "local", Busy: 1, Total: 3},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Load() = %v, want %v", got, want)
+	}
+}
+
 func TestActorMatchesTags(t *testing.T) {
 	actor := &Actor{
 		Tags: []string{"coding", "chat", "fast"},
@@ -321,6 +405,28 @@ func TestFindIdleActorWithCapabilities(t *testing.T) {
 	}
 }
 
+func TestPreferredProvider(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{
+		"large": {MaxConcurrent: 1},
+		"small": {MaxConcurrent: 1},
+	})
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for model, want := range map[string]string{"small": "small", "": "", "unknown": ""} {
+		task := &brain.Task{Config: brain.TaskConfig{Model: model}}
+		if got := pool.preferredProvider(task); got != want {
+			t.Errorf("preferredProvider(%q) = %q, want %q", model, got, want)
+		}
+	}
+
+	actor := pool.findIdleActor(pool.preferredProvider(&brain.Task{Config: brain.TaskConfig{Model: "small"}}), nil, nil)
+	if actor == nil || actor.ProviderName != "small" {
+		t.Errorf("expected the small actor, got %+v", actor)
+	}
+}
+
 func TestActorPoolPropagatesCapabilities(t *testing.T) {
 	pool := newTestPool(t, map[string]ProviderSpec{
 		"overlay": {
@@ -426,6 +532,40 @@ func TestSubmitExplicitMaxRetries(t *testing.T) {
 	}
 }
 
+func TestSubmitIdempotencyKey(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1},
+	})
+
+	submit := func(session, key string) string {
+		t.Helper()
+		task := &brain.Task{Title: "sync", SessionID: session, IdempotencyKey: key}
+		if err := pool.Submit(task); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+		return task.ID
+	}
+
+	first := submit("s1", "sync-repo")
+	if got := submit("s1", "sync-repo"); got != first {
+		t.Errorf("duplicate submission created task %s, want %s", got, first)
+	}
+	if got := submit("s2", "sync-repo"); got == first {
+		t.Error("key must be scoped to the session")
+	}
+	if got := submit("s1", ""); got == first {
+		t.Error("submission without key must not be deduplicated")
+	}
+
+	// Once the task finished, the key can be reused.
+	done, _ := pool.Store().Get(first)
+	done.Status = brain.TaskCompleted
+	_ = pool.Store().Update(done)
+	if got := submit("s1", "sync-repo"); got == first {
+		t.Error("finished task must not absorb a new submission")
+	}
+}
+
 func TestRequeueForRetry(t *testing.T) {
 	pool := newTestPool(t, map[string]ProviderSpec{
 		"claude": {MaxConcurrent: 1},
@@ -541,6 +681,64 @@ func TestPriorityRank(t *testing.T) {
 	}
 }
 
+func TestEffectiveRank_AgesPreemptedTasks(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{"ollama": {MaxConcurrent: 1}})
+	t0 := time.Now()
+	task := &brain.Task{ID: "bg", Priority: brain.PriorityLow}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if got := pool.effectiveRank(task, t0); got != priorityRank(brain.PriorityLow) {
+		t.Errorf("never preempted: rank = %d, want the priority rank", got)
+	}
+
+	pool.preemptions["bg"] = &preemptionRecord{count: 1, first: t0}
+	if got := pool.effectiveRank(task, t0.Add(preemptionAgingStep)); got != priorityRank(brain.PriorityNormal) {
+		t.Errorf("after one aging step: rank = %d, want normal", got)
+	}
+	if got := pool.effectiveRank(task, t0.Add(3*preemptionAgingStep)); got <= priorityRank(brain.PriorityHigh) {
+		t.Errorf("after three aging steps: rank = %d, want above high", got)
+	}
+
+	pool.preemptions["bg"].count = maxPreemptions
+	if got := pool.effectiveRank(task, t0); got <= priorityRank(brain.PriorityHigh) {
+		t.Errorf("at the preemption cap: rank = %d, want above high", got)
+	}
+}
+
+func TestPreemptionCandidate_SkipsProtectedTasks(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{"ollama": {MaxConcurrent: 2}})
+	now := time.Now()
+
+	low := &brain.Task{Title: "background", Priority: brain.PriorityLow}
+	normal := &brain.Task{Title: "regular", Priority: brain.PriorityNormal}
+	for _, task := range []*brain.Task{low, normal} {
+		if err := pool.store.Create(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.runners[low.ID] = &runningTask{taskID: low.ID, actor: pool.actors[0]}
+	pool.runners[normal.ID] = &runningTask{taskID: normal.ID, actor: pool.actors[1]}
+
+	if rt := pool.preemptionCandidate("ollama", now); rt == nil || rt.taskID != low.ID {
+		t.Fatalf("expected the low-priority task to be preempted, got %+v", rt)
+	}
+
+	// Preempted too often: the low-priority task is protected.
+	pool.preemptions[low.ID] = &preemptionRecord{count: maxPreemptions, first: now}
+	if rt := pool.preemptionCandidate("ollama", now); rt == nil || rt.taskID != normal.ID {
+		t.Fatalf("expected the normal task to be preempted instead, got %+v", rt)
+	}
+
+	pool.preemptions[normal.ID] = &preemptionRecord{count: 1, first: now.Add(-3 * preemptionAgingStep)}
+	if rt := pool.preemptionCandidate("ollama", now); rt != nil {
+		t.Errorf("aged tasks must not be preempted, got %q", rt.taskID)
+	}
+}
+
 // --- Dependency resolution tests ---
 
 func TestDependencyResolution(t *testing.T) {
@@ -737,6 +935,72 @@ func TestScheduleCancelsUnresolvableDeps(t *testing.T) {
 	}
 }
 
+func TestScheduleDefersDuringQuietHours(t *testing.T) {
+	pool := newTestPool(t, map[string]ProviderSpec{
+		"claude": {MaxConcurrent: 1},
+	})
+	pool.ctx = t.Context()
+
+	// Two adjacent windows cover the whole day.
+	quiet, err := brain.ParseQuietHours([]string{"00:00-12:00", "12:00-00:00"}, "UTC", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.quiet = quiet
+
+	deferredCh, unsub := pool.bus.SubscribeChan(4, events.EventScheduleDeferred)
+	defer unsub()
+
+	task := &brain.Task{Title: "nightly-report", Description: "background work"}
+	if err := pool.Submit(task); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	pool.schedule()
+	pool.schedule() // reported only once
+
+	got, err := pool.store.Get(task.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status != brain.TaskPending {
+		t.Fatalf("status during quiet hours: got %s, want pending", got.Status)
+	}
+
+	select {
+	case e := <-deferredCh:
+		p, ok := events.GetScheduleDeferredPayload(e)
+		if !ok || p.TaskID != task.ID || p.Action != "deferred" {
+			t.Fatalf("unexpected deferred payload: %+v", e.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for deferred event")
+	}
+	select {
+	case e := <-deferredCh:
+		t.Fatalf("unexpected second deferral: %+v", e.Payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Window over: the task is dispatched.
+	pool.quiet = nil
+	pool.schedule()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		got, _ := pool.store.Get(task.ID)
+		if got.Status != brain.TaskPending {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("task was not dispatched after quiet hours")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	pool.wg.Wait()
+}
+
 func TestEventDrivenSchedulerWake(t *testing.T) {
 	pool := newTestPool(t, map[string]ProviderSpec{
 		"claude": {MaxConcurrent: 1},
@@ -0,0 +1,257 @@
+package brain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PlanStep is one unit of work in a Plan.
+type PlanStep struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"` // step IDs that must complete first
+	Tools       []string `json:"tools,omitempty"`      // tools the step may use (empty = caller default)
+	Model       string   `json:"model,omitempty"`      // provider to run the step on (empty = caller default)
+}
+
+// Plan is the canonical representation of a multi-step plan, shared by skill
+// workflows and task batches. It marshals to JSON via its struct tags and to
+// markdown via Markdown / ParsePlanFromMarkdown.
+type Plan struct {
+	Title string     `json:"title,omitempty"`
+	Steps []PlanStep `json:"steps"`
+}
+
+// Step returns the step with the given ID, or nil.
+func (p *Plan) Step(id string) *PlanStep {
+	for i := range p.Steps {
+		if p.Steps[i].ID == id {
+			return &p.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that the plan has steps, that step IDs are unique and
+// non-empty, and that dependencies reference known steps without cycles.
+func (p *Plan) Validate() error {
+	_, err := p.Order()
+	return err
+}
+
+// Order returns the step IDs in a dependency-respecting order, keeping the
+// declared order among independent steps.
+func (p *Plan) Order() ([]string, error) {
+	if len(p.Steps) == 0 {
+		return nil, fmt.Errorf("plan has no steps")
+	}
+
+	inDegree := make(map[string]int, len(p.Steps))
+	for _, s := range p.Steps {
+		if s.ID == "" {
+			return nil, fmt.Errorf("plan step %q has no id", s.Title)
+		}
+		if _, dup := inDegree[s.ID]; dup {
+			return nil, fmt.Errorf("duplicate plan step id %q", s.ID)
+		}
+		inDegree[s.ID] = len(s.DependsOn)
+	}
+	dependents := make(map[string][]string, len(p.Steps))
+	for _, s := range p.Steps {
+		for _, dep := range s.DependsOn {
+			if _, ok := inDegree[dep]; !ok {
+				return nil, fmt.Errorf("plan step %q depends on unknown step %q", s.ID, dep)
+			}
+			dependents[dep] = append(dependents[dep], s.ID)
+		}
+	}
+
+	order := make([]string, 0, len(p.Steps))
+	done := make(map[string]bool, len(p.Steps))
+	for len(order) < len(p.Steps) {
+		progressed := false
+		for _, s := range p.Steps {
+			if done[s.ID] || inDegree[s.ID] > 0 {
+				continue
+			}
+			done[s.ID] = true
+			order = append(order, s.ID)
+			for _, d := range dependents[s.ID] {
+				inDegree[d]--
+			}
+			progressed = true
+		}
+		if !progressed {
+			return nil, fmt.Errorf("cycle detected in plan steps")
+		}
+	}
+	return order, nil
+}
+
+// Markdown renders the plan as a numbered markdown list that
+// ParsePlanFromMarkdown reads back:
+//
+//	# Title
+//
+//	1. **Step title** (`step_id`)
+//	   Description, possibly on several lines.
+//	   - depends on: `other_id`
+//	   - tools: `run_command`, `git`
+//	   - model: `fast`
+func (p *Plan) Markdown() string {
+	var b strings.Builder
+	if p.Title != "" {
+		b.WriteString("# " + p.Title + "\n\n")
+	}
+	for i, s := range p.Steps {
+		fmt.Fprintf(&b, "%d. **%s** (`%s`)\n", i+1, s.Title, s.ID)
+		if desc := strings.TrimSpace(s.Description); desc != "" {
+			for _, line := range strings.Split(desc, "\n") {
+				b.WriteString(strings.TrimRight("   "+line, " ") + "\n")
+			}
+		}
+		if len(s.DependsOn) > 0 {
+			b.WriteString("   - depends on: " + codeList(s.DependsOn) + "\n")
+		}
+		if len(s.Tools) > 0 {
+			b.WriteString("   - tools: " + codeList(s.Tools) + "\n")
+		}
+		if s.Model != "" {
+			b.WriteString("   - model: `" + s.Model + "`\n")
+		}
+	}
+	return b.String()
+}
+
+// ParsePlanFromMarkdown parses a plan written as a numbered markdown list (see
+// Plan.Markdown). Only the numbered items are required: a step without an
+// explicit (`id`) gets "step<N>", and dependencies may name either step IDs
+// or step numbers. The parsed plan is validated.
+func ParsePlanFromMarkdown(md string) (*Plan, error) {
+	p := &Plan{}
+	var cur *PlanStep
+	var desc []string
+
+	flush := func() {
+		if cur != nil {
+			cur.Description = strings.TrimSpace(strings.Join(desc, "\n"))
+			p.Steps = append(p.Steps, *cur)
+		}
+		cur, desc = nil, nil
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if title, ok := strings.CutPrefix(line, "# "); ok && p.Title == "" && len(p.Steps) == 0 && cur == nil {
+			p.Title = strings.TrimSpace(title)
+			continue
+		}
+		if title, ok := numberedItem(line); ok {
+			flush()
+			cur = &PlanStep{}
+			cur.Title, cur.ID = splitStepTitle(title)
+			if cur.ID == "" {
+				cur.ID = "step" + strconv.Itoa(len(p.Steps)+1)
+			}
+			continue
+		}
+		if cur == nil {
+			continue // preamble
+		}
+		if deps, ok := metaItem(trimmed, "depends on", "needs"); ok {
+			cur.DependsOn = append(cur.DependsOn, deps...)
+			continue
+		}
+		if tools, ok := metaItem(trimmed, "tools"); ok {
+			cur.Tools = append(cur.Tools, tools...)
+			continue
+		}
+		if model, ok := metaItem(trimmed, "model"); ok {
+			if len(model) > 0 {
+				cur.Model = model[0]
+			}
+			continue
+		}
+		desc = append(desc, trimmed)
+	}
+	flush()
+
+	// Dependencies may be written as step numbers.
+	for i := range p.Steps {
+		for j, dep := range p.Steps[i].DependsOn {
+			if p.Step(dep) != nil {
+				continue
+			}
+			if n, err := strconv.Atoi(dep); err == nil && n >= 1 && n <= len(p.Steps) {
+				p.Steps[i].DependsOn[j] = p.Steps[n-1].ID
+			}
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// numberedItem returns the text of a top-level "N. text" (or "N) text") line.
+func numberedItem(line string) (string, bool) {
+	i := 0
+	for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+		i++
+	}
+	if i == 0 || i+1 >= len(line) || (line[i] != '.' && line[i] != ')') || line[i+1] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(line[i+2:]), true
+}
+
+// splitStepTitle extracts a trailing (`id`) from a step title and strips
+// bold markers.
+func splitStepTitle(s string) (title, id string) {
+	if strings.HasSuffix(s, "`)") {
+		if i := strings.LastIndex(s, "(`"); i >= 0 {
+			id = s[i+2 : len(s)-2]
+			s = strings.TrimSpace(s[:i])
+		}
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "**"), "**")
+	return strings.TrimSpace(s), id
+}
+
+// metaItem parses a "- key: a, b" line for one of the given keys
+// (case-insensitive), returning the comma-separated values without backticks.
+func metaItem(line string, keys ...string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(line, "- ")
+	if !ok {
+		return nil, false
+	}
+	key, value, ok := strings.Cut(rest, ":")
+	if !ok {
+		return nil, false
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, k := range keys {
+		if key != k {
+			continue
+		}
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.Trim(strings.TrimSpace(v), "`"); v != "" && v != "none" {
+				values = append(values, v)
+			}
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = "`" + s + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
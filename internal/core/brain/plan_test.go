@@ -0,0 +1,117 @@
+package brain
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func samplePlan() *Plan {
+	return &Plan{
+		Title: "Release v2",
+		Steps: []PlanStep{
+			{ID: "build", Title: "Build binaries", Description: "Run make build.\nKeep the artifacts.", Tools: []string{"run_command"}},
+			{ID: "test", Title: "Run tests", Tools: []string{"run_command", "git"}, Model: "fast"},
+			{ID: "tag", Title: "Tag the release", DependsOn: []string{"build", "test"}},
+		},
+	}
+}
+
+func TestPlan_MarkdownRoundTrip(t *testing.T) {
+	p := samplePlan()
+	md := p.Markdown()
+	if !strings.Contains(md, "3. **Tag the release** (`tag`)\n   - depends on: `build`, `test`\n") {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+
+	got, err := ParsePlanFromMarkdown(md)
+	if err != nil {
+		t.Fatalf("ParsePlanFromMarkdown: %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, p)
+	}
+}
+
+func TestPlan_JSONRoundTrip(t *testing.T) {
+	p := samplePlan()
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Plan
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, p) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, p)
+	}
+}
+
+func TestParsePlanFromMarkdown_Loose(t *testing.T) {
+	md := `Here is my plan:
+
+1. Explore the codebase
+   Find where sessions are stored.
+2) **Write the migration**
+   - Needs: 1
+3. Update docs
+   - depends on: 2
+   - tools: none
+`
+	p, err := ParsePlanFromMarkdown(md)
+	if err != nil {
+		t.Fatalf("ParsePlanFromMarkdown: %v", err)
+	}
+	if len(p.Steps) != 3 || p.Title != "" {
+		t.Fatalf("unexpected plan: %+v", p)
+	}
+	if s := p.Steps[0]; s.ID != "step1" || s.Title != "Explore the codebase" || s.Description != "Find where sessions are stored." {
+		t.Errorf("step 1 = %+v", s)
+	}
+	if s := p.Steps[1]; s.Title != "Write the migration" || !slices.Equal(s.DependsOn, []string{"step1"}) {
+		t.Errorf("step 2 = %+v", s)
+	}
+	if s := p.Steps[2]; !slices.Equal(s.DependsOn, []string{"step2"}) || len(s.Tools) != 0 {
+		t.Errorf("step 3 = %+v", s)
+	}
+}
+
+func TestPlan_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []PlanStep
+		wantErr string
+	}{
+		{"empty", nil, "no steps"},
+		{"missing id", []PlanStep{{Title: "x"}}, "has no id"},
+		{"duplicate", []PlanStep{{ID: "a"}, {ID: "a"}}, "duplicate"},
+		{"unknown dep", []PlanStep{{ID: "a", DependsOn: []string{"b"}}}, "unknown step"},
+		{"cycle", []PlanStep{{ID: "a", DependsOn: []string{"b"}}, {ID: "b", DependsOn: []string{"a"}}}, "cycle"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := (&Plan{Steps: tc.steps}).Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlan_Order(t *testing.T) {
+	p := &Plan{Steps: []PlanStep{
+		{ID: "deploy", DependsOn: []string{"build"}},
+		{ID: "build"},
+		{ID: "docs"},
+	}}
+	order, err := p.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"build", "docs", "deploy"}; !slices.Equal(order, want) {
+		t.Errorf("Order() = %v, want %v", order, want)
+	}
+}
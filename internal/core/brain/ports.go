@@ -65,10 +65,18 @@ type RunnerOption func(*RunnerOpts)
 // RunnerOpts holds optional configuration for CreateRunner.
 type RunnerOpts struct {
 	MaxIterations   int
-	Middlewares      []any       // opaque adapter-specific middlewares
-	PreemptionCheck func() bool // returns true when preemption is requested
+	Middlewares     []any                     // opaque adapter-specific middlewares
+	PreemptionCheck func() bool               // returns true when preemption is requested
+	OnAssistantText func(string)              // called with each intermediate assistant message (optional)
+	OnToolResult    func(tool, result string) // called with each tool result (optional)
+	InterceptTool   ToolCallInterceptor       // may answer tool calls in place of the tools (optional)
 }
 
+// ToolCallInterceptor is consulted before every tool call of a runner,
+// including tools injected by middlewares. When it returns intercepted=true
+// the tool is not run and result is handed to the model instead.
+type ToolCallInterceptor func(ctx context.Context, tool, argumentsInJSON string) (result string, intercepted bool)
+
 // ApplyRunnerOpts processes variadic options into RunnerOpts.
 func ApplyRunnerOpts(opts []RunnerOption) RunnerOpts {
 	var o RunnerOpts
@@ -93,9 +101,40 @@ func WithPreemptionCheck(fn func() bool) RunnerOption {
 	return func(o *RunnerOpts) { o.PreemptionCheck = fn }
 }
 
+// WithAssistantText sets a callback receiving each assistant message the
+// runner produces, including intermediate ones between tool calls.
+func WithAssistantText(fn func(string)) RunnerOption {
+	return func(o *RunnerOpts) { o.OnAssistantText = fn }
+}
+
+// WithToolResult sets a callback receiving the result of each tool call the
+// runner makes.
+func WithToolResult(fn func(tool, result string)) RunnerOption {
+	return func(o *RunnerOpts) { o.OnToolResult = fn }
+}
+
+// WithToolCallInterceptor sets a callback that may answer tool calls in
+// place of the tools (e.g. dry runs).
+func WithToolCallInterceptor(fn ToolCallInterceptor) RunnerOption {
+	return func(o *RunnerOpts) { o.InterceptTool = fn }
+}
+
 // ErrRunnerPreempted is returned by Runner.Run when preemption is triggered.
 var ErrRunnerPreempted = errors.New("runner preempted")
 
+// ErrMaxIterations is returned by Runner.Run when the ReAct loop reached its
+// iteration cap before the model produced a final answer.
+type ErrMaxIterations struct {
+	Limit int // 0 when the adapter default applied
+}
+
+func (e *ErrMaxIterations) Error() string {
+	if e.Limit > 0 {
+		return fmt.Sprintf("max iterations reached (%d): the run stopped before finishing", e.Limit)
+	}
+	return "max iterations reached: the run stopped before finishing"
+}
+
 // SummarizeFunc performs a non-streaming LLM call.
 type SummarizeFunc func(ctx context.Context, prompt string) (string, error)
 
@@ -116,6 +155,17 @@ func (e *ErrModelUnavailable) Error() string {
 
 func (e *ErrModelUnavailable) Unwrap() error { return e.Cause }
 
+// ErrContextOverflow indicates the request exceeded the model's context window.
+type ErrContextOverflow struct {
+	Cause error
+}
+
+func (e *ErrContextOverflow) Error() string {
+	return fmt.Sprintf("context window exceeded: %v", e.Cause)
+}
+
+func (e *ErrContextOverflow) Unwrap() error { return e.Cause }
+
 // ---- Tier Resolution ----
 
 // TierResolver maps provider names to model tiers.
@@ -143,6 +193,12 @@ type CapacityPool interface {
 
 // ---- Task Ports ----
 
+// ContextCompactor shrinks context injected into a task instruction that
+// overflowed the model's context window.
+type ContextCompactor interface {
+	CompactContext(ctx context.Context, text string, maxChars int) (string, error)
+}
+
 // TaskStore is the persistence interface for tasks.
 type TaskStore interface {
 	Create(t *Task) error
@@ -154,6 +210,10 @@ type TaskStore interface {
 	LoadCheckpoints(taskID string) ([]Checkpoint, error)
 	WriteOutput(taskID string, content string) error
 	ReadOutput(taskID string) (string, error)
+	// WriteArtifact stores an artifact's content, replacing one of the same name.
+	WriteArtifact(taskID string, a Artifact, content []byte) error
+	ListArtifacts(taskID string) ([]Artifact, error)
+	ReadArtifact(taskID, name string) (*Artifact, []byte, error)
 }
 
 // SkillExecutor runs a skill by name.
@@ -196,6 +256,7 @@ type TaskExecutorConfig struct {
 type TaskSubmitter interface {
 	Submit(t *Task) error
 	Cancel(taskID string, reason string) error
+	CancelSession(sessionID string, reason string) ([]CancelledTask, error)
 	Store() TaskStore
 	AvailableActors() []ActorInfo
 }
@@ -0,0 +1,130 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietPolicy decides what happens to autonomous work triggered during quiet hours.
+type QuietPolicy string
+
+const (
+	QuietDefer QuietPolicy = "defer" // hold the work until the window ends (default)
+	QuietSkip  QuietPolicy = "skip"  // drop scheduled triggers
+)
+
+// quietRange is a daily window in minutes since midnight. End <= Start wraps
+// past midnight (e.g. 22:00-07:00).
+type quietRange struct {
+	start, end int
+}
+
+func (r quietRange) contains(minute int) bool {
+	if r.start < r.end {
+		return minute >= r.start && minute < r.end
+	}
+	return minute >= r.start || minute < r.end
+}
+
+// QuietHours suppresses background and scheduled execution during daily time
+// windows. A nil *QuietHours is never active.
+type QuietHours struct {
+	ranges []quietRange
+	loc    *time.Location
+	policy QuietPolicy
+}
+
+// ParseQuietHours builds quiet hours from "HH:MM-HH:MM" ranges evaluated in the
+// given IANA timezone (empty = local time). Returns nil when no ranges are set.
+func ParseQuietHours(ranges []string, timezone string, policy string) (*QuietHours, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	qh := &QuietHours{loc: time.Local, policy: QuietDefer}
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours timezone: %w", err)
+		}
+		qh.loc = loc
+	}
+
+	switch QuietPolicy(policy) {
+	case "", QuietDefer:
+	case QuietSkip:
+		qh.policy = QuietSkip
+	default:
+		return nil, fmt.Errorf("quiet hours policy %q: must be %q or %q", policy, QuietDefer, QuietSkip)
+	}
+
+	for _, spec := range ranges {
+		from, to, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, fmt.Errorf("quiet hours range %q: expected HH:MM-HH:MM", spec)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours range %q: %w", spec, err)
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours range %q: %w", spec, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("quiet hours range %q: empty window", spec)
+		}
+		qh.ranges = append(qh.ranges, quietRange{start: start, end: end})
+	}
+	return qh, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Policy returns the configured policy.
+func (q *QuietHours) Policy() QuietPolicy {
+	if q == nil {
+		return QuietDefer
+	}
+	return q.policy
+}
+
+// Active reports whether now falls within a quiet window.
+func (q *QuietHours) Active(now time.Time) bool {
+	if q == nil {
+		return false
+	}
+	local := now.In(q.loc)
+	minute := local.Hour()*60 + local.Minute()
+	for _, r := range q.ranges {
+		if r.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// Until returns when the quiet period containing now ends, following adjacent
+// or overlapping windows. Returns now when quiet hours are not active.
+func (q *QuietHours) Until(now time.Time) time.Time {
+	if !q.Active(now) {
+		return now
+	}
+	t := now.In(q.loc).Truncate(time.Minute)
+	// Walk minute by minute; bounded to one day since windows repeat daily.
+	for i := 0; i < 24*60; i++ {
+		t = t.Add(time.Minute)
+		if !q.Active(t) {
+			return t
+		}
+	}
+	return t
+}
@@ -0,0 +1,82 @@
+package brain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	if qh, err := ParseQuietHours(nil, "", ""); err != nil || qh != nil {
+		t.Fatalf("expected nil quiet hours without ranges, got %v, %v", qh, err)
+	}
+
+	bad := []struct {
+		name     string
+		ranges   []string
+		timezone string
+		policy   string
+	}{
+		{"missing dash", []string{"22:00"}, "", ""},
+		{"invalid time", []string{"25:00-07:00"}, "", ""},
+		{"empty window", []string{"09:00-09:00"}, "", ""},
+		{"unknown timezone", []string{"22:00-07:00"}, "Mars/Olympus", ""},
+		{"unknown policy", []string{"22:00-07:00"}, "", "later"},
+	}
+	for _, tt := range bad {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseQuietHours(tt.ranges, tt.timezone, tt.policy); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	qh, err := ParseQuietHours([]string{"22:00-07:00"}, "UTC", "skip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qh.Policy() != QuietSkip {
+		t.Errorf("policy = %q, want %q", qh.Policy(), QuietSkip)
+	}
+}
+
+func TestQuietHours_Active(t *testing.T) {
+	qh, err := ParseQuietHours([]string{"22:00-07:00", "12:00-13:30"}, "Europe/Paris", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paris, _ := time.LoadLocation("Europe/Paris")
+	at := func(h, m int) time.Time { return time.Date(2026, 1, 15, h, m, 0, 0, paris) }
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"before overnight window", at(21, 59), false},
+		{"overnight start", at(22, 0), true},
+		{"after midnight", at(3, 0), true},
+		{"overnight end is exclusive", at(7, 0), false},
+		{"midday window", at(13, 15), true},
+		{"after midday window", at(13, 30), false},
+		{"other timezone", time.Date(2026, 1, 15, 22, 30, 0, 0, time.UTC), true}, // 23:30 Paris
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := qh.Active(tt.now); got != tt.want {
+				t.Errorf("Active(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+
+	if want := at(7, 0).AddDate(0, 0, 1); !qh.Until(at(23, 10)).Equal(want) {
+		t.Errorf("Until = %s, want %s", qh.Until(at(23, 10)), want)
+	}
+	if now := at(10, 0); !qh.Until(now).Equal(now) {
+		t.Error("Until outside quiet hours should return now")
+	}
+
+	var none *QuietHours
+	if none.Active(at(23, 0)) {
+		t.Error("nil quiet hours must never be active")
+	}
+}
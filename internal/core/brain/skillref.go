@@ -0,0 +1,19 @@
+package brain
+
+import "strings"
+
+// SkillRef returns the reference of a skill pinned to a version
+// ("name@version"), or the bare name (latest version) when version is empty.
+func SkillRef(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
+
+// ParseSkillRef splits a skill reference into its name and pinned version
+// ("" = latest).
+func ParseSkillRef(ref string) (name, version string) {
+	name, version, _ = strings.Cut(ref, "@")
+	return name, version
+}
@@ -45,6 +45,12 @@ type TaskConfig struct {
 	RequiredCapabilities []string                          `json:"required_capabilities,omitempty"`
 	ApprovedTools        []string                          `json:"approved_tools,omitempty"`   // dangerous tools pre-approved
 	ToolConstraints      map[string]*events.ToolConstraint `json:"tool_constraints,omitempty"` // per-tool argument constraints
+	Verbose              bool                              `json:"verbose,omitempty"`          // narrate sub-agent output to the session
+	GitContext           bool                              `json:"git_context,omitempty"`      // summarize the WorkDir repository in the instruction
+	SkillVars            map[string]string                 `json:"skill_vars,omitempty"`       // extra vars passed to Skill (chained skill triggers)
+	Remember             bool                              `json:"remember,omitempty"`         // store a summary of the output in memory on completion
+	Simulate             bool                              `json:"simulate,omitempty"`         // dry run: record side-effecting tool calls instead of executing them
+	MaxIterations        int                               `json:"max_iterations,omitempty"`   // ReAct iteration cap (0 = runner default)
 }
 
 // TokenUsage tracks cumulative token consumption.
@@ -58,30 +64,42 @@ type TaskResult struct {
 	OutputPath string     `json:"output_path,omitempty"`
 	Error      string     `json:"error,omitempty"`
 	TokenUsage TokenUsage `json:"token_usage"`
+	Artifacts  []Artifact `json:"artifacts,omitempty"`
+}
+
+// Artifact is a file produced by a task, stored under the task directory.
+type Artifact struct {
+	Name        string    `json:"name"`
+	Path        string    `json:"path"` // relative to the task directory
+	Description string    `json:"description,omitempty"`
+	SourcePath  string    `json:"source_path,omitempty"` // file the artifact was copied from
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // Task represents an async unit of work.
 type Task struct {
-	ID           string       `json:"id"`
-	SessionID    string       `json:"session_id,omitempty"`
-	ParentTaskID string       `json:"parent_task_id,omitempty"`
-	DependsOn    []string     `json:"depends_on,omitempty"`
-	Title        string       `json:"title"`
-	Description  string       `json:"description"`
-	Status       TaskStatus   `json:"status"`
-	Priority     TaskPriority `json:"priority"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
-	StartedAt    *time.Time   `json:"started_at,omitempty"`
-	CompletedAt  *time.Time   `json:"completed_at,omitempty"`
-	Progress     TaskProgress `json:"progress"`
-	Config       TaskConfig   `json:"config"`
-	Result       *TaskResult  `json:"result,omitempty"`
-	Tags         []string     `json:"tags,omitempty"`
-	RetryCount   int          `json:"retry_count"`
-	MaxRetries   int          `json:"max_retries"`
-	ActorID      string       `json:"actor_id,omitempty"`
-	ProviderName string       `json:"provider_name,omitempty"`
+	ID             string       `json:"id"`
+	SessionID      string       `json:"session_id,omitempty"`
+	ParentTaskID   string       `json:"parent_task_id,omitempty"`
+	DependsOn      []string     `json:"depends_on,omitempty"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	Status         TaskStatus   `json:"status"`
+	Priority       TaskPriority `json:"priority"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	StartedAt      *time.Time   `json:"started_at,omitempty"`
+	CompletedAt    *time.Time   `json:"completed_at,omitempty"`
+	Progress       TaskProgress `json:"progress"`
+	Config         TaskConfig   `json:"config"`
+	Result         *TaskResult  `json:"result,omitempty"`
+	Tags           []string     `json:"tags,omitempty"`
+	RetryCount     int          `json:"retry_count"`
+	MaxRetries     int          `json:"max_retries"`
+	ActorID        string       `json:"actor_id,omitempty"`
+	ProviderName   string       `json:"provider_name,omitempty"`
+	IdempotencyKey string       `json:"idempotency_key,omitempty"` // resubmitting while a pending/running task of the session has the key returns that task
 }
 
 // Checkpoint records a point-in-time snapshot of task progress.
@@ -94,9 +112,18 @@ type Checkpoint struct {
 
 // ListFilter defines criteria for filtering task lists.
 type ListFilter struct {
-	Status    TaskStatus `json:"status,omitempty"`
-	SessionID string     `json:"session_id,omitempty"`
-	ParentID  string     `json:"parent_id,omitempty"`
+	Status         TaskStatus `json:"status,omitempty"`
+	SessionID      string     `json:"session_id,omitempty"`
+	ParentID       string     `json:"parent_id,omitempty"`
+	IdempotencyKey string     `json:"idempotency_key,omitempty"`
+}
+
+// CancelledTask summarizes a task cancelled by a bulk cancellation.
+type CancelledTask struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Status   TaskStatus `json:"status"` // status before cancellation
+	ParentID string     `json:"parent_id,omitempty"`
 }
 
 // ActorInfo describes one available actor for task scheduling.
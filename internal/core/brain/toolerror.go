@@ -0,0 +1,77 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+// ToolErrorCode classifies a tool failure so the agent can decide whether
+// self-correction is worthwhile.
+type ToolErrorCode string
+
+const (
+	ToolErrInvalidInput     ToolErrorCode = "invalid_input"     // bad or missing arguments — fix and retry
+	ToolErrNotFound         ToolErrorCode = "not_found"         // target does not exist — check the reference
+	ToolErrPermissionDenied ToolErrorCode = "permission_denied" // blocked by policy or the user — do not retry
+	ToolErrTransient        ToolErrorCode = "transient"         // timeout or unavailable backend — retry as is
+	ToolErrInternal         ToolErrorCode = "internal"          // anything else
+)
+
+// ToolError is the typed error envelope returned by tools.
+type ToolError struct {
+	Code ToolErrorCode
+	Err  error
+}
+
+func (e *ToolError) Error() string { return e.Err.Error() }
+func (e *ToolError) Unwrap() error { return e.Err }
+
+// NewToolError wraps err with a code. A nil err yields nil.
+func NewToolError(code ToolErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ToolError{Code: code, Err: err}
+}
+
+// ToolErrorf formats an error like fmt.Errorf and tags it with a code.
+func ToolErrorf(code ToolErrorCode, format string, args ...any) error {
+	return &ToolError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// ToolErrorCodeOf returns the code of the outermost ToolError in err's chain.
+// Untagged errors are classified from well-known causes and default to
+// ToolErrInternal. A classified timeout only guides the model: unlike a
+// tagged ToolErrTransient, it says nothing of whether the call had effects.
+func ToolErrorCodeOf(err error) ToolErrorCode {
+	if code := TaggedToolErrorCode(err); code != "" {
+		return code
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return ToolErrNotFound
+	case errors.Is(err, os.ErrPermission):
+		return ToolErrPermissionDenied
+	case errors.Is(err, context.DeadlineExceeded):
+		return ToolErrTransient
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ToolErrTransient
+	default:
+		return ToolErrInternal
+	}
+}
+
+// TaggedToolErrorCode returns the code of the outermost ToolError in err's
+// chain, or "" when no tool tagged the error.
+func TaggedToolErrorCode(err error) ToolErrorCode {
+	var te *ToolError
+	if errors.As(err, &te) {
+		return te.Code
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestToolErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ToolErrorCode
+	}{
+		{"tagged", ToolErrorf(ToolErrInvalidInput, "title is required"), ToolErrInvalidInput},
+		{"wrapped tagged", fmt.Errorf("node: %w", NewToolError(ToolErrTransient, errors.New("busy"))), ToolErrTransient},
+		{"outermost tag wins", ToolErrorf(ToolErrPermissionDenied, "guard: %w", ToolErrorf(ToolErrNotFound, "gone")), ToolErrPermissionDenied},
+		{"not exist", fmt.Errorf("read: %w", os.ErrNotExist), ToolErrNotFound},
+		{"permission", fmt.Errorf("write: %w", os.ErrPermission), ToolErrPermissionDenied},
+		{"deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), ToolErrTransient},
+		{"untagged", errors.New("boom"), ToolErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToolErrorCodeOf(tt.err); got != tt.want {
+				t.Errorf("ToolErrorCodeOf(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaggedToolErrorCode(t *testing.T) {
+	if got := TaggedToolErrorCode(fmt.Errorf("fetch: %w", context.DeadlineExceeded)); got != "" {
+		t.Errorf("classified deadline = %q, want untagged", got)
+	}
+	if got := TaggedToolErrorCode(fmt.Errorf("node: %w", ToolErrorf(ToolErrTransient, "busy"))); got != ToolErrTransient {
+		t.Errorf("tagged transient = %q", got)
+	}
+}
+
+func TestNewToolError_Nil(t *testing.T) {
+	if err := NewToolError(ToolErrInternal, nil); err != nil {
+		t.Errorf("NewToolError(nil) = %v, want nil", err)
+	}
+}
@@ -6,14 +6,16 @@ import (
 )
 
 // ToolSet tracks per-session active tools. Core tools are always active;
-// additional tools can be activated at runtime via activate_tools.
+// additional tools can be activated at runtime via activate_tools, and drop
+// out again once left unused for a number of turns (see BeginTurn).
 // All methods are safe for concurrent use.
 type ToolSet struct {
 	mu        sync.RWMutex
-	core      map[string]bool            // always-on tools
-	active    map[string]map[string]bool // sessionID → tool names
-	turnFlags map[string]bool            // sessionID → activated-during-turn
-	allNames  map[string]bool            // every known tool name
+	core      map[string]bool           // always-on tools
+	active    map[string]map[string]int // sessionID → tool name → turn last activated or used
+	turns     map[string]int            // sessionID → turns begun (see BeginTurn)
+	turnFlags map[string]bool           // sessionID → activated-during-turn
+	allNames  map[string]bool           // every known tool name
 }
 
 // NewToolSet creates a ToolSet. coreTools are always active for every session.
@@ -29,7 +31,8 @@ func NewToolSet(coreTools, allTools []string) *ToolSet {
 	}
 	return &ToolSet{
 		core:      core,
-		active:    make(map[string]map[string]bool),
+		active:    make(map[string]map[string]int),
+		turns:     make(map[string]int),
 		turnFlags: make(map[string]bool),
 		allNames:  all,
 	}
@@ -67,13 +70,49 @@ func (ts *ToolSet) Activate(sessionID, toolName string) bool {
 		return false
 	}
 	if ts.active[sessionID] == nil {
-		ts.active[sessionID] = make(map[string]bool)
+		ts.active[sessionID] = make(map[string]int)
 	}
-	ts.active[sessionID][toolName] = true
+	ts.active[sessionID][toolName] = ts.turns[sessionID]
 	ts.turnFlags[sessionID] = true
 	return true
 }
 
+// MarkUsed records that an activated tool was called during the current
+// turn, postponing its idle deactivation. Core and inactive tools are ignored.
+func (ts *ToolSet) MarkUsed(sessionID, toolName string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if _, ok := ts.active[sessionID][toolName]; ok {
+		ts.active[sessionID][toolName] = ts.turns[sessionID]
+	}
+}
+
+// BeginTurn starts a new turn for the session and deactivates the tools
+// activated for it that were neither activated nor used during the last
+// maxIdleTurns turns (0 = never deactivate). Core tools stay active. Returns
+// the deactivated tool names, sorted.
+func (ts *ToolSet) BeginTurn(sessionID string, maxIdleTurns int) []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.turns[sessionID]++
+	if maxIdleTurns <= 0 {
+		return nil
+	}
+
+	turn := ts.turns[sessionID]
+	var dropped []string
+	for name, last := range ts.active[sessionID] {
+		if turn-last > maxIdleTurns {
+			delete(ts.active[sessionID], name)
+			dropped = append(dropped, name)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
 // IsKnown returns true if toolName is in the full catalog.
 func (ts *ToolSet) IsKnown(toolName string) bool {
 	ts.mu.RLock()
@@ -89,7 +128,8 @@ func (ts *ToolSet) IsActive(sessionID, toolName string) bool {
 	if ts.core[toolName] {
 		return true
 	}
-	return ts.active[sessionID][toolName]
+	_, ok := ts.active[sessionID][toolName]
+	return ok
 }
 
 // ResetTurnFlag clears the activation flag for the current turn.
@@ -134,5 +174,6 @@ func (ts *ToolSet) Cleanup(sessionID string) {
 	ts.mu.Lock()
 	defer ts.mu.Unlock()
 	delete(ts.active, sessionID)
+	delete(ts.turns, sessionID)
 	delete(ts.turnFlags, sessionID)
 }
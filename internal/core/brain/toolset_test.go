@@ -195,3 +195,44 @@ func TestToolSet_Concurrent(t *testing.T) {
 		t.Error("expected at least core tools after concurrent access")
 	}
 }
+
+func TestToolSet_BeginTurnDeactivatesIdle(t *testing.T) {
+	ts := NewToolSet([]string{"cmd"}, []string{"cmd", "search", "git"})
+
+	ts.BeginTurn("s1", 2) // turn 1
+	ts.Activate("s1", "search")
+	ts.Activate("s1", "git")
+
+	ts.BeginTurn("s1", 2) // turn 2
+	ts.MarkUsed("s1", "git")
+	ts.MarkUsed("s1", "cmd") // core: ignored
+
+	if dropped := ts.BeginTurn("s1", 2); len(dropped) != 0 { // turn 3
+		t.Fatalf("turn 3 dropped %v, want none", dropped)
+	}
+	dropped := ts.BeginTurn("s1", 2) // turn 4: search idle since turn 1
+	if len(dropped) != 1 || dropped[0] != "search" {
+		t.Fatalf("turn 4 dropped %v, want [search]", dropped)
+	}
+	if ts.IsActive("s1", "search") || !ts.IsActive("s1", "git") || !ts.IsActive("s1", "cmd") {
+		t.Errorf("ActiveToolNames = %v, want [cmd git]", ts.ActiveToolNames("s1"))
+	}
+
+	// Re-activation on demand.
+	if !ts.Activate("s1", "search") || !ts.IsActive("s1", "search") {
+		t.Error("search should be re-activatable")
+	}
+}
+
+func TestToolSet_BeginTurnZeroKeepsTools(t *testing.T) {
+	ts := NewToolSet([]string{"cmd"}, []string{"cmd", "search"})
+	ts.Activate("s1", "search")
+	for range 10 {
+		if dropped := ts.BeginTurn("s1", 0); dropped != nil {
+			t.Fatalf("dropped %v with idle deactivation disabled", dropped)
+		}
+	}
+	if !ts.IsActive("s1", "search") {
+		t.Error("search should stay active")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
@@ -23,38 +24,36 @@ func PromptToolApproval(
 ) error {
 	token := uuid.New().String()
 
-	bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin, events.PromptRequestPayload{
+	resp, err := bus.Request(ctx, events.NewTypedEventWithSession(events.SourcePlugin, events.PromptRequestPayload{
 		Type:  events.PromptTypeSelect,
 		Label: fmt.Sprintf(msgFmt, strings.Join(needPrompt, ", ")),
 		Options: []events.PromptOption{
-			{Value: "allow", Label: "Allow all listed tools"},
-			{Value: "deny", Label: "Deny"},
+			{Value: ApprovalSession, Label: "Allow the listed tools for this session"},
+			{Value: ApprovalAll, Label: "Allow all tools for this session"},
+			{Value: ApprovalDeny, Label: "Deny"},
 		},
 		Token: token,
 	}, sessionID))
+	if err != nil {
+		return fmt.Errorf("waiting for tool approval: %w", err)
+	}
 
-	ch, unsub := bus.SubscribeChan(1, events.EventPromptResponse)
-	defer unsub()
-
-	for {
-		select {
-		case event := <-ch:
-			payload, ok := events.GetPromptResponsePayload(event)
-			if !ok || payload.Token != token {
-				continue
-			}
-			val, _ := payload.Value.(string)
-			if val == "allow" {
-				for _, name := range needPrompt {
-					perms.AllowForSession(sessionID, name)
-					bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin,
-						events.ToolApprovedPayload{ToolName: name}, sessionID))
-				}
-				return nil
-			}
-			return fmt.Errorf("dangerous tools denied by user: %s", strings.Join(needPrompt, ", "))
-		case <-ctx.Done():
-			return fmt.Errorf("waiting for tool approval: %w", ctx.Err())
-		}
+	payload, _ := events.GetPromptResponsePayload(resp)
+	val, _ := payload.Value.(string)
+	var approved []string
+	switch val {
+	case ApprovalSession:
+		approved = needPrompt
+	case ApprovalAll:
+		approved = []string{AllTools}
+	}
+	if len(approved) == 0 {
+		return brain.ToolErrorf(brain.ToolErrPermissionDenied, "dangerous tools denied by user: %s", strings.Join(needPrompt, ", "))
+	}
+	for _, name := range approved {
+		perms.AllowForSession(sessionID, name)
+		bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin,
+			events.ToolApprovedPayload{ToolName: name}, sessionID))
 	}
+	return nil
 }
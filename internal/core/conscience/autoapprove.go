@@ -0,0 +1,59 @@
+package conscience
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// AutoApproveRule approves calls of a dangerous tool whose arguments match a
+// pattern, so benign repeated operations (e.g. run_command "git status") skip
+// the confirmation prompt. The pattern must match the whole value.
+type AutoApproveRule struct {
+	Tool    string
+	Arg     string // argument to match; empty matches the raw JSON arguments
+	Pattern string
+	re      *regexp.Regexp
+}
+
+// NewAutoApproveRule compiles an auto-approve rule. The pattern is anchored:
+// "git status" does not match "git status; rm -rf /".
+func NewAutoApproveRule(tool, arg, pattern string) (*AutoApproveRule, error) {
+	if tool == "" {
+		return nil, fmt.Errorf("auto-approve rule: tool is required")
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("auto-approve rule for %s: pattern is required", tool)
+	}
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("auto-approve rule for %s: %w", tool, err)
+	}
+	return &AutoApproveRule{Tool: tool, Arg: arg, Pattern: pattern, re: re}, nil
+}
+
+// Matches reports whether a call of toolName with the given JSON arguments is
+// covered by the rule. A missing or non-string argument never matches.
+func (r *AutoApproveRule) Matches(toolName, argumentsInJSON string) bool {
+	if r.Tool != toolName {
+		return false
+	}
+	if r.Arg == "" {
+		return r.re.MatchString(argumentsInJSON)
+	}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
+		return false
+	}
+	value, ok := args[r.Arg].(string)
+	return ok && r.re.MatchString(value)
+}
+
+// String describes the rule for logs and the audit trail.
+func (r *AutoApproveRule) String() string {
+	if r.Arg == "" {
+		return fmt.Sprintf("%s =~ %s", r.Tool, r.Pattern)
+	}
+	return fmt.Sprintf("%s %s =~ %s", r.Tool, r.Arg, r.Pattern)
+}
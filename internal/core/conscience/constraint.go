@@ -45,7 +45,7 @@ func (g *ConstraintGuard) Run(ctx context.Context, argumentsInJSON string) (stri
 	}
 
 	if err := g.validate(tc, argumentsInJSON); err != nil {
-		return "", fmt.Errorf("constraint: %s: %w", g.toolName, err)
+		return "", brain.ToolErrorf(brain.ToolErrPermissionDenied, "constraint: %s: %w", g.toolName, err)
 	}
 
 	return g.inner.Run(ctx, argumentsInJSON)
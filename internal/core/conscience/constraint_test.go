@@ -55,6 +55,9 @@ func TestConstraintGuard_AllowedCommands_Blocked(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for disallowed command, got nil")
 	}
+	if code := brain.ToolErrorCodeOf(err); code != brain.ToolErrPermissionDenied {
+		t.Fatalf("expected code %q, got %q", brain.ToolErrPermissionDenied, code)
+	}
 }
 
 func TestConstraintGuard_AllowedDomains(t *testing.T) {
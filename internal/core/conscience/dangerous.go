@@ -40,8 +40,11 @@ func (d *DangerousToolWrapper) Info(ctx context.Context) (*brain.ToolInfo, error
 }
 
 // Run checks permissions before executing the tool.
-// If pre-approved (global or session), executes immediately.
-// Otherwise, prompts the user with three options: allow once, always allow, deny.
+// If pre-approved (global or session) or covered by an auto-approve rule,
+// executes immediately.
+// Otherwise, prompts the user: allow once, allow this tool for the session,
+// allow all dangerous tools for the session, or deny. Remembered choices are
+// stored in ToolPermissions and persisted via a ToolApproved event.
 // The prompt is routed via session ID so sub-tasks bubble up to the parent client.
 // There is no timeout — waits until the user responds or the context is cancelled.
 func (d *DangerousToolWrapper) Run(ctx context.Context, argumentsInJSON string) (string, error) {
@@ -52,6 +55,19 @@ func (d *DangerousToolWrapper) Run(ctx context.Context, argumentsInJSON string)
 		return d.inner.Run(ctx, argumentsInJSON)
 	}
 
+	// Argument-based rules approve this call only; announce it for the audit log.
+	if d.perms != nil {
+		if rule, ok := d.perms.MatchAutoApprove(d.name, argumentsInJSON); ok {
+			d.bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin, events.ToolCallPayload{
+				Status:       events.ToolStatusStarted,
+				Name:         d.name,
+				Arguments:    map[string]any{"raw": argumentsInJSON},
+				AutoApproved: rule.String(),
+			}, sessionID))
+			return d.inner.Run(ctx, argumentsInJSON)
+		}
+	}
+
 	// Prompt the user — works for both interactive and sub-task contexts
 	// because the session ID routes the event to the correct client.
 	token := uuid.New().String()
@@ -62,53 +78,46 @@ func (d *DangerousToolWrapper) Run(ctx context.Context, argumentsInJSON string)
 		Arguments: map[string]any{"raw": argumentsInJSON},
 	}, sessionID))
 
-	d.bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin, events.PromptRequestPayload{
+	resp, err := d.bus.Request(ctx, events.NewTypedEventWithSession(events.SourcePlugin, events.PromptRequestPayload{
 		Type:  events.PromptTypeSelect,
 		Label: fmt.Sprintf("Tool %q requires approval. Arguments: %s", d.name, truncate(argumentsInJSON, 200)),
 		Options: []events.PromptOption{
-			{Value: "once", Label: "Allow once"},
-			{Value: "session", Label: "Always allow for this session"},
-			{Value: "deny", Label: "Deny"},
+			{Value: ApprovalOnce, Label: "Allow once"},
+			{Value: ApprovalSession, Label: fmt.Sprintf("Always allow %s for this session", d.name)},
+			{Value: ApprovalAll, Label: "Allow all tools for this session"},
+			{Value: ApprovalDeny, Label: "Deny"},
 		},
 		Token: token,
 	}, sessionID))
+	if err != nil {
+		return "", fmt.Errorf("tool %q: waiting for approval: %w", d.name, err)
+	}
 
-	ch, unsub := d.bus.SubscribeChan(1, events.EventPromptResponse)
-	defer unsub()
-
-	for {
-		select {
-		case event := <-ch:
-			payload, ok := events.GetPromptResponsePayload(event)
-			if !ok || payload.Token != token {
-				continue
-			}
-			switch val := payload.Value.(type) {
-			case string:
-				switch val {
-				case "once":
-					return d.inner.Run(ctx, argumentsInJSON)
-				case "session":
-					if d.perms != nil && sessionID != "" {
-						d.perms.AllowForSession(sessionID, d.name)
-					}
-					d.bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin,
-						events.ToolApprovedPayload{ToolName: d.name}, sessionID))
-					return d.inner.Run(ctx, argumentsInJSON)
-				default:
-					return "", fmt.Errorf("tool %q execution denied by user", d.name)
-				}
-			default:
-				// Cancelled or unknown value type
-				if payload.Cancelled {
-					return "", fmt.Errorf("tool %q execution denied by user", d.name)
-				}
-				return "", fmt.Errorf("tool %q execution denied by user", d.name)
-			}
-		case <-ctx.Done():
-			return "", fmt.Errorf("tool %q: waiting for approval: %w", d.name, ctx.Err())
-		}
+	// Cancelled prompts and unknown values count as a denial.
+	payload, _ := events.GetPromptResponsePayload(resp)
+	val, _ := payload.Value.(string)
+	switch val {
+	case ApprovalOnce:
+		return d.inner.Run(ctx, argumentsInJSON)
+	case ApprovalSession:
+		d.remember(sessionID, d.name)
+		return d.inner.Run(ctx, argumentsInJSON)
+	case ApprovalAll:
+		d.remember(sessionID, AllTools)
+		return d.inner.Run(ctx, argumentsInJSON)
+	default:
+		return "", brain.ToolErrorf(brain.ToolErrPermissionDenied, "tool %q execution denied by user", d.name)
+	}
+}
+
+// remember records a session approval (a tool name or AllTools) and publishes
+// it so the gateway persists it with the session.
+func (d *DangerousToolWrapper) remember(sessionID, toolName string) {
+	if d.perms != nil && sessionID != "" {
+		d.perms.AllowForSession(sessionID, toolName)
 	}
+	d.bus.Publish(events.NewTypedEventWithSession(events.SourcePlugin,
+		events.ToolApprovedPayload{ToolName: toolName}, sessionID))
 }
 
 func truncate(s string, maxLen int) string {
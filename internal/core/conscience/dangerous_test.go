@@ -0,0 +1,165 @@
+package conscience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+// autoRespond answers every approval prompt on the bus with the given choice
+// and counts the prompts it saw.
+func autoRespond(bus *events.Bus, choice string) *int {
+	prompts := new(int)
+	bus.Subscribe(func(e events.Event) {
+		p, ok := events.GetPromptRequestPayload(e)
+		if !ok {
+			return
+		}
+		*prompts++
+		bus.Publish(events.NewTypedEventWithSession(events.SourceWS,
+			events.PromptResponsePayload{Value: choice, Token: p.Token}, e.SessionID))
+	}, events.EventPromptRequest)
+	return prompts
+}
+
+func runDangerous(t *testing.T, tool *fakeTool, name string, bus *events.Bus, perms *ToolPermissions) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(events.ContextWithSessionID(context.Background(), "sess1"), 2*time.Second)
+	defer cancel()
+	_, err := WrapDangerous(tool, name, true, bus, perms).Run(ctx, `{}`)
+	return err
+}
+
+func TestDangerousToolWrapper_RememberedChoices(t *testing.T) {
+	tests := []struct {
+		choice       string
+		wantErr      bool
+		sameTool     bool // run_command allowed afterwards
+		otherTool    bool // write_file allowed afterwards
+		wantApproved string
+	}{
+		{choice: ApprovalOnce},
+		{choice: ApprovalSession, sameTool: true, wantApproved: "run_command"},
+		{choice: ApprovalAll, sameTool: true, otherTool: true, wantApproved: AllTools},
+		{choice: ApprovalDeny, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.choice, func(t *testing.T) {
+			bus := events.NewBus(64)
+			defer bus.Close()
+			perms := NewToolPermissions(nil)
+			prompts := autoRespond(bus, tc.choice)
+
+			approved := make(chan string, 4)
+			bus.Subscribe(func(e events.Event) {
+				if p, ok := events.GetToolApprovedPayload(e); ok {
+					approved <- p.ToolName
+				}
+			}, events.EventToolApproved)
+
+			tool := &fakeTool{}
+			err := runDangerous(t, tool, "run_command", bus, perms)
+			if tc.wantErr {
+				if err == nil || tool.called {
+					t.Fatalf("expected denial, got err=%v called=%v", err, tool.called)
+				}
+			} else if err != nil || !tool.called {
+				t.Fatalf("expected tool to run, got err=%v called=%v", err, tool.called)
+			}
+
+			if got := perms.IsAllowed("sess1", "run_command"); got != tc.sameTool {
+				t.Errorf("run_command allowed = %v, want %v", got, tc.sameTool)
+			}
+			if got := perms.IsAllowed("sess1", "write_file"); got != tc.otherTool {
+				t.Errorf("write_file allowed = %v, want %v", got, tc.otherTool)
+			}
+
+			if tc.wantApproved != "" {
+				select {
+				case name := <-approved:
+					if name != tc.wantApproved {
+						t.Errorf("approved event for %q, want %q", name, tc.wantApproved)
+					}
+				case <-time.After(time.Second):
+					t.Error("expected a tool.approved event")
+				}
+			}
+
+			// A remembered choice must not prompt again
+			if tc.sameTool {
+				before := *prompts
+				if err := runDangerous(t, &fakeTool{}, "run_command", bus, perms); err != nil {
+					t.Fatalf("second run: %v", err)
+				}
+				if *prompts != before {
+					t.Error("expected no prompt for a remembered tool")
+				}
+			}
+		})
+	}
+}
+
+func TestDangerousToolWrapper_AutoApproveRules(t *testing.T) {
+	rule, err := NewAutoApproveRule("run_command", "command", `git (status|diff)`)
+	if err != nil {
+		t.Fatalf("NewAutoApproveRule: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		args        string
+		wantPrompts int
+	}{
+		{"matching call skips prompt", `{"command":"git status"}`, 0},
+		{"pattern is anchored", `{"command":"git status; rm -rf /"}`, 1},
+		{"other argument", `{"cmd":"git status"}`, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bus := events.NewBus(64)
+			defer bus.Close()
+			perms := NewToolPermissions(nil)
+			perms.SetAutoApproveRules([]*AutoApproveRule{rule})
+			prompts := autoRespond(bus, ApprovalOnce)
+
+			ctx, cancel := context.WithTimeout(events.ContextWithSessionID(context.Background(), "sess1"), 2*time.Second)
+			defer cancel()
+			tool := &fakeTool{}
+			if _, err := WrapDangerous(tool, "run_command", true, bus, perms).Run(ctx, tc.args); err != nil || !tool.called {
+				t.Fatalf("expected tool to run, got err=%v called=%v", err, tool.called)
+			}
+			if *prompts != tc.wantPrompts {
+				t.Errorf("prompts = %d, want %d", *prompts, tc.wantPrompts)
+			}
+			// A rule approves single calls; it never grants the tool to the session.
+			if perms.IsAllowed("sess1", "run_command") {
+				t.Error("run_command should not be allowed for the session")
+			}
+		})
+	}
+}
+
+func TestNewAutoApproveRule(t *testing.T) {
+	if _, err := NewAutoApproveRule("", "", "x"); err == nil {
+		t.Error("expected error for missing tool")
+	}
+	if _, err := NewAutoApproveRule("git", "", ""); err == nil {
+		t.Error("expected error for missing pattern")
+	}
+	if _, err := NewAutoApproveRule("git", "", "("); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+
+	raw, err := NewAutoApproveRule("git", "", `\{"action":"status"\}`)
+	if err != nil {
+		t.Fatalf("NewAutoApproveRule: %v", err)
+	}
+	if !raw.Matches("git", `{"action":"status"}`) || raw.Matches("git", `{"action":"push"}`) || raw.Matches("run_command", `{"action":"status"}`) {
+		t.Error("raw-arguments rule matched unexpectedly")
+	}
+	if got := raw.String(); got != `git =~ \{"action":"status"\}` {
+		t.Errorf("String() = %q", got)
+	}
+}
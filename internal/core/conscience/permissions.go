@@ -2,13 +2,25 @@ package conscience
 
 import "sync"
 
+// AllTools is the per-session wildcard entry meaning "every dangerous tool is approved".
+const AllTools = "*"
+
+// Approval prompt choices for dangerous tools.
+const (
+	ApprovalOnce    = "once"    // run this call only
+	ApprovalSession = "session" // remember for this tool in this session
+	ApprovalAll     = "all"     // approve every dangerous tool in this session
+	ApprovalDeny    = "deny"
+)
+
 // ToolPermissions tracks which dangerous tools are auto-approved.
 // It supports two levels: global (from config, always approved) and
 // per-session (approved dynamically by the client).
 type ToolPermissions struct {
 	mu             sync.RWMutex
 	globalAllowed  map[string]bool            // from config, always approved
-	sessionAllowed map[string]map[string]bool // sessionID → tool name → allowed ("*" = accept-all)
+	sessionAllowed map[string]map[string]bool // sessionID → tool name → allowed (AllTools = accept-all)
+	autoApprove    []*AutoApproveRule         // per-call approval by tool + argument pattern
 }
 
 // NewToolPermissions creates a ToolPermissions with the given globally allowed tool names.
@@ -39,7 +51,41 @@ func (tp *ToolPermissions) IsAllowed(sessionID, toolName string) bool {
 		return false
 	}
 
-	return sess[toolName] || sess["*"]
+	return sess[toolName] || sess[AllTools]
+}
+
+// SetGlobalAllowed replaces the globally allowed tool names (config reload).
+// Per-session approvals are kept.
+func (tp *ToolPermissions) SetGlobalAllowed(names []string) {
+	ga := make(map[string]bool, len(names))
+	for _, name := range names {
+		ga[name] = true
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.globalAllowed = ga
+}
+
+// SetAutoApproveRules replaces the argument-based auto-approve rules.
+func (tp *ToolPermissions) SetAutoApproveRules(rules []*AutoApproveRule) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.autoApprove = rules
+}
+
+// MatchAutoApprove returns the first auto-approve rule covering a call of
+// toolName with the given JSON arguments.
+func (tp *ToolPermissions) MatchAutoApprove(toolName, argumentsInJSON string) (*AutoApproveRule, bool) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+
+	for _, r := range tp.autoApprove {
+		if r.Matches(toolName, argumentsInJSON) {
+			return r, true
+		}
+	}
+	return nil, false
 }
 
 // AllowForSession marks a specific tool as approved for the given session.
@@ -61,7 +107,7 @@ func (tp *ToolPermissions) AllowAllForSession(sessionID string) {
 	if tp.sessionAllowed[sessionID] == nil {
 		tp.sessionAllowed[sessionID] = make(map[string]bool)
 	}
-	tp.sessionAllowed[sessionID]["*"] = true
+	tp.sessionAllowed[sessionID][AllTools] = true
 }
 
 // IsSessionAcceptAll returns true if the session has accept-all mode enabled.
@@ -70,7 +116,7 @@ func (tp *ToolPermissions) IsSessionAcceptAll(sessionID string) bool {
 	defer tp.mu.RUnlock()
 
 	sess := tp.sessionAllowed[sessionID]
-	return sess != nil && sess["*"]
+	return sess != nil && sess[AllTools]
 }
 
 // CleanupSession removes all per-session permissions.
@@ -16,6 +16,23 @@ func TestToolPermissions_GlobalAllowed(t *testing.T) {
 	}
 }
 
+func TestToolPermissions_SetGlobalAllowed(t *testing.T) {
+	tp := NewToolPermissions([]string{"cmd"})
+	tp.AllowForSession("sess1", "write_file")
+
+	tp.SetGlobalAllowed([]string{"git"})
+
+	if tp.IsAllowed("sess1", "cmd") {
+		t.Error("expected cmd to no longer be globally allowed")
+	}
+	if !tp.IsAllowed("sess2", "git") {
+		t.Error("expected git to be globally allowed")
+	}
+	if !tp.IsAllowed("sess1", "write_file") {
+		t.Error("expected session approvals to survive a reload")
+	}
+}
+
 func TestToolPermissions_SessionAllowed(t *testing.T) {
 	tp := NewToolPermissions(nil)
 
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
@@ -18,28 +20,159 @@ type sandboxToolType string
 const (
 	SandboxExec       sandboxToolType = "exec"
 	SandboxFilesystem sandboxToolType = "filesystem"
+	SandboxNetwork    sandboxToolType = "network"
 )
 
 // SandboxGuard wraps a brain.Tool with command and path validation.
 // In autonomous mode it blocks destructive patterns and jails paths to the WorkDir.
-// In interactive mode it passes through without checks.
+// In confined interactive sessions it only jails paths to the WorkDir.
+// Otherwise it passes through without checks.
 type SandboxGuard struct {
-	inner        brain.Tool
-	toolName     string
-	toolType     sandboxToolType
-	elevated     bool     // true for root_cmd — always blocked in autonomous mode
-	allowedPaths []string // extra paths allowed outside WorkDir
+	inner           brain.Tool
+	toolName        string
+	toolType        sandboxToolType
+	elevated        bool          // true for root_cmd — always blocked in autonomous mode
+	allowedPaths    *PathList     // extra paths allowed outside WorkDir
+	allowedCommands *Allowlist    // non-empty = allowlist mode for exec tools
+	allowedHosts    *Allowlist    // non-empty = outbound host allowlist for network tools
+	commandRules    *CommandRules // nil = built-in denylist only
+}
+
+// PathList is a concurrency-safe list of paths allowed outside the WorkDir.
+// A list shared by several guards lets a config reload replace it in place.
+type PathList struct {
+	mu    sync.RWMutex
+	paths []string
+}
+
+// NewPathList creates a PathList holding paths.
+func NewPathList(paths []string) *PathList {
+	return &PathList{paths: paths}
+}
+
+// Paths returns the current paths.
+func (l *PathList) Paths() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.paths
+}
+
+// Set replaces the paths.
+func (l *PathList) Set(paths []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.paths = paths
+}
+
+// Allowlist is a concurrency-safe list of allowed names (commands or hosts).
+// Like PathList, a list shared by several guards lets a config reload replace
+// it in place. A nil Allowlist is empty.
+type Allowlist struct {
+	mu    sync.RWMutex
+	names []string
+	set   map[string]bool
+}
+
+// NewAllowlist creates an Allowlist holding names.
+func NewAllowlist(names []string) *Allowlist {
+	l := &Allowlist{}
+	l.Set(names)
+	return l
+}
+
+// Names returns the current names.
+func (l *Allowlist) Names() []string {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.names
+}
+
+// Set replaces the names.
+func (l *Allowlist) Set(names []string) {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names = names
+	l.set = set
+}
+
+// lookup returns the names as a set; callers must not modify it.
+func (l *Allowlist) lookup() map[string]bool {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.set
+}
+
+// SandboxOption configures optional SandboxGuard behavior.
+type SandboxOption func(*SandboxGuard)
+
+// WithAllowedCommands enables allowlist mode: in autonomous mode, exec tools may
+// only run commands whose binary is in the list, regardless of the denylist.
+// An empty list leaves allowlist mode disabled.
+func WithAllowedCommands(commands []string) SandboxOption {
+	return WithCommandAllowlist(NewAllowlist(commands))
+}
+
+// WithCommandAllowlist is WithAllowedCommands with a list that can be
+// replaced after the guard is built.
+func WithCommandAllowlist(l *Allowlist) SandboxOption {
+	return func(s *SandboxGuard) {
+		s.allowedCommands = l
+	}
+}
+
+// WithAllowedHosts restricts network tools in autonomous mode to the given
+// hosts. Entries are exact hostnames, "*.domain" for subdomains, or "*".
+// An empty list leaves network tools unrestricted.
+func WithAllowedHosts(hosts []string) SandboxOption {
+	return WithHostAllowlist(NewAllowlist(hosts))
+}
+
+// WithHostAllowlist is WithAllowedHosts with a list that can be replaced
+// after the guard is built.
+func WithHostAllowlist(l *Allowlist) SandboxOption {
+	return func(s *SandboxGuard) {
+		s.allowedHosts = l
+	}
+}
+
+// WithCommandRules replaces the built-in exec denylist with rules.
+func WithCommandRules(rules *CommandRules) SandboxOption {
+	return func(s *SandboxGuard) {
+		s.commandRules = rules
+	}
+}
+
+// WithPathList makes the guard read its allowed paths from l, replacing the
+// allowedPaths given to WrapSandbox.
+func WithPathList(l *PathList) SandboxOption {
+	return func(s *SandboxGuard) {
+		s.allowedPaths = l
+	}
 }
 
 // WrapSandbox wraps a tool with sandbox validation.
-func WrapSandbox(t brain.Tool, name string, tt sandboxToolType, elevated bool, allowedPaths []string) brain.Tool {
-	return &SandboxGuard{
+func WrapSandbox(t brain.Tool, name string, tt sandboxToolType, elevated bool, allowedPaths []string, opts ...SandboxOption) brain.Tool {
+	s := &SandboxGuard{
 		inner:        t,
 		toolName:     name,
 		toolType:     tt,
 		elevated:     elevated,
-		allowedPaths: allowedPaths,
+		allowedPaths: NewPathList(allowedPaths),
 	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
 }
 
 // Info delegates to the inner tool.
@@ -49,15 +182,20 @@ func (s *SandboxGuard) Info(ctx context.Context) (*brain.ToolInfo, error) {
 
 // Run validates the tool call before delegating to the inner tool.
 func (s *SandboxGuard) Run(ctx context.Context, argumentsInJSON string) (string, error) {
-	// Only enforce in autonomous mode
+	// Only enforce in autonomous mode; confined sessions get the path jail alone
 	if !events.IsAutonomousContext(ctx) {
+		if events.IsConfinedContext(ctx) {
+			if err := s.validatePaths(events.WorkDirFromContext(ctx), argumentsInJSON); err != nil {
+				return "", brain.NewToolError(brain.ToolErrPermissionDenied, err)
+			}
+		}
 		return s.inner.Run(ctx, argumentsInJSON)
 	}
 
 	// Elevated tools are unconditionally blocked in autonomous mode.
 	// For the unified run_command tool, check the sudo flag dynamically.
 	if s.elevated || (s.toolType == SandboxExec && isSudo(argumentsInJSON)) {
-		return "", fmt.Errorf("sandbox: tool %q is blocked in autonomous mode (elevated privileges)", s.toolName)
+		return "", brain.ToolErrorf(brain.ToolErrPermissionDenied, "sandbox: tool %q is blocked in autonomous mode (elevated privileges)", s.toolName)
 	}
 
 	workDir := events.WorkDirFromContext(ctx)
@@ -65,12 +203,17 @@ func (s *SandboxGuard) Run(ctx context.Context, argumentsInJSON string) (string,
 	switch s.toolType {
 	case SandboxExec:
 		if err := s.validateExec(workDir, argumentsInJSON); err != nil {
-			return "", err
+			return "", brain.NewToolError(brain.ToolErrPermissionDenied, err)
 		}
 	case SandboxFilesystem:
 		if err := s.validateFilesystem(workDir, argumentsInJSON); err != nil {
-			return "", err
+			return "", brain.NewToolError(brain.ToolErrPermissionDenied, err)
+		}
+	case SandboxNetwork:
+		if err := s.validateNetwork(argumentsInJSON); err != nil {
+			return "", brain.NewToolError(brain.ToolErrPermissionDenied, err)
 		}
+		ctx = context.WithValue(ctx, networkGuardKey{}, s)
 	}
 
 	return s.inner.Run(ctx, argumentsInJSON)
@@ -90,36 +233,64 @@ func (s *SandboxGuard) validateExec(workDir, argsJSON string) error {
 
 	// 1. Denylist — AST-based validation for raw command strings
 	if args.Command != "" {
-		if err := validateCommandAST(args.Command); err != nil {
+		if err := s.commandRules.Validate(args.Command); err != nil {
 			return fmt.Errorf("sandbox: %s: %w", s.toolName, err)
 		}
 	}
 
-	// 2. Path jail — only when WorkDir is set
+	// 1b. Allowlist — when configured, every binary must be explicitly allowed
+	if allowed := s.allowedCommands.lookup(); args.Command != "" && len(allowed) > 0 {
+		if err := validateCommandAllowlist(args.Command, allowed); err != nil {
+			return fmt.Errorf("sandbox: %s: %w", s.toolName, err)
+		}
+	}
+
+	// 2. Path jail
+	return s.validateExecPaths(workDir, args.Command, args.WorkingDir, argsJSON)
+}
+
+// validateExecPaths jails the working_dir override, the paths in the raw
+// command and the path-like JSON args of an exec tool call to the WorkDir.
+// It is a no-op when WorkDir is empty.
+func (s *SandboxGuard) validateExecPaths(workDir, command, workingDir, argsJSON string) error {
 	if workDir == "" {
 		return nil
 	}
 
 	// Check working_dir override
-	if args.WorkingDir != "" {
-		if err := validatePathInWorkDir(workDir, args.WorkingDir, s.allowedPaths); err != nil {
+	if workingDir != "" {
+		if err := validatePathInWorkDir(workDir, workingDir, s.allowedPaths.Paths()); err != nil {
 			return fmt.Errorf("sandbox: %s: working_dir %s", s.toolName, err)
 		}
 	}
 
 	// Check paths in the raw command string (AST-based)
-	if args.Command != "" {
-		for _, p := range extractCommandPathsAST(args.Command) {
-			if err := validatePathInWorkDir(workDir, p, s.allowedPaths); err != nil {
+	if command != "" {
+		for _, p := range extractCommandPathsAST(command) {
+			if err := validatePathInWorkDir(workDir, p, s.allowedPaths.Paths()); err != nil {
 				return fmt.Errorf("sandbox: %s: command path %s", s.toolName, err)
 			}
 		}
 	}
 
+	// Check symlink targets (ln -s), resolved relative to the link location
+	if command != "" {
+		home, _ := os.UserHomeDir()
+		targets, err := extractSymlinkTargetsAST(command, home)
+		if err != nil {
+			return fmt.Errorf("sandbox: %s: %w", s.toolName, err)
+		}
+		for _, p := range targets {
+			if err := validatePathInWorkDir(workDir, p, s.allowedPaths.Paths()); err != nil {
+				return fmt.Errorf("sandbox: %s: symlink target %s", s.toolName, err)
+			}
+		}
+	}
+
 	// Check path-like fields in JSON args (covers structured tools like git
 	// where paths live in nested objects, e.g. {"action":"add","args":{"paths":[...]}})
 	for _, p := range extractToolPaths(argsJSON) {
-		if err := validatePathInWorkDir(workDir, p, s.allowedPaths); err != nil {
+		if err := validatePathInWorkDir(workDir, p, s.allowedPaths.Paths()); err != nil {
 			return fmt.Errorf("sandbox: %s: arg path %s", s.toolName, err)
 		}
 	}
@@ -127,6 +298,28 @@ func (s *SandboxGuard) validateExec(workDir, argsJSON string) error {
 	return nil
 }
 
+// validatePaths applies only the path jail, for confined interactive sessions.
+// A confined session without a WorkDir fails closed.
+func (s *SandboxGuard) validatePaths(workDir, argsJSON string) error {
+	if workDir == "" && s.toolType != SandboxNetwork {
+		return fmt.Errorf("sandbox: %s: confined session has no root directory", s.toolName)
+	}
+	switch s.toolType {
+	case SandboxExec:
+		var args struct {
+			Command    string `json:"command"`
+			WorkingDir string `json:"working_dir"`
+		}
+		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+			return fmt.Errorf("sandbox: %s: parse args: %w", s.toolName, err)
+		}
+		return s.validateExecPaths(workDir, args.Command, args.WorkingDir, argsJSON)
+	case SandboxFilesystem:
+		return s.validateFilesystem(workDir, argsJSON)
+	}
+	return nil
+}
+
 // validateFilesystem checks a filesystem tool call.
 func (s *SandboxGuard) validateFilesystem(workDir, argsJSON string) error {
 	if workDir == "" {
@@ -134,7 +327,7 @@ func (s *SandboxGuard) validateFilesystem(workDir, argsJSON string) error {
 	}
 
 	for _, p := range extractToolPaths(argsJSON) {
-		if err := validatePathInWorkDir(workDir, p, s.allowedPaths); err != nil {
+		if err := validatePathInWorkDir(workDir, p, s.allowedPaths.Paths()); err != nil {
 			return fmt.Errorf("sandbox: %s: %s", s.toolName, err)
 		}
 	}
@@ -142,6 +335,80 @@ func (s *SandboxGuard) validateFilesystem(workDir, argsJSON string) error {
 	return nil
 }
 
+// validateNetwork checks that every URL in a network tool call targets an allowed host.
+func (s *SandboxGuard) validateNetwork(argsJSON string) error {
+	hosts := s.allowedHosts.Names()
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	for _, raw := range extractToolURLs(argsJSON) {
+		host, err := urlHost(raw)
+		if err != nil {
+			return fmt.Errorf("sandbox: %s: %w", s.toolName, err)
+		}
+		if !hostAllowed(host, hosts) {
+			return fmt.Errorf("sandbox: %s: host %q is not in the allowed hosts", s.toolName, host)
+		}
+	}
+	return nil
+}
+
+// networkGuardKey is the context key of the guard that validated a network call.
+type networkGuardKey struct{}
+
+// ValidateRedirect re-runs the host allowlist of the sandbox guard that let a
+// network tool call through on a redirect target, so an allowed host cannot
+// bounce the request elsewhere. Network tools call it from their HTTP client's
+// CheckRedirect with the request context. It is a no-op outside a sandboxed
+// call in autonomous mode.
+func ValidateRedirect(ctx context.Context, target *url.URL) error {
+	s, ok := ctx.Value(networkGuardKey{}).(*SandboxGuard)
+	if !ok {
+		return nil
+	}
+	args, err := json.Marshal(map[string]string{"url": target.String()})
+	if err != nil {
+		return err
+	}
+	if err := s.validateNetwork(string(args)); err != nil {
+		return fmt.Errorf("redirect blocked: %w", err)
+	}
+	return nil
+}
+
+// urlHost returns the lowercased hostname of a URL. Scheme-less URLs
+// (e.g. "example.com/page") are parsed as https.
+func urlHost(raw string) (string, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", fmt.Errorf("URL %q has no host", raw)
+	}
+	return host, nil
+}
+
+// hostAllowed matches a host against an allowlist, mirroring the WASM
+// HTTP capability: exact host, "*.domain" for any subdomain, or "*".
+func hostAllowed(host string, allowed []string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		switch {
+		case a == "*" || a == host:
+			return true
+		case strings.HasPrefix(a, "*.") && strings.HasSuffix(host, a[1:]):
+			return true
+		}
+	}
+	return false
+}
+
 // validatePathInWorkDir checks that a path resolves within the workDir or allowedPaths.
 // It resolves symlinks (best-effort) to prevent symlink escapes.
 func validatePathInWorkDir(workDir, path string, allowedPaths []string) error {
@@ -185,7 +452,7 @@ func validatePathInWorkDir(workDir, path string, allowedPaths []string) error {
 		}
 	}
 
-	return fmt.Errorf("path %q is outside work directory %q", path, workDir)
+	return fmt.Errorf("path %q is outside work directory %q; operate within the work directory", path, workDir)
 }
 
 // IsUnder returns true if child is equal to or a descendant of parent.
@@ -199,7 +466,16 @@ func IsUnder(child, parent string) bool {
 // EvalSymlinksExisting resolves symlinks for the longest existing prefix of a path.
 // For paths where intermediate directories exist but the leaf does not,
 // it resolves what exists and appends the remaining components.
+// Dangling symlinks are followed to their (missing) target, so a write through
+// a link pointing outside a jail resolves to the outside location.
 func EvalSymlinksExisting(path string) (string, error) {
+	return evalSymlinksExisting(path, 0)
+}
+
+// maxSymlinkHops bounds dangling-link resolution to avoid loops.
+const maxSymlinkHops = 40
+
+func evalSymlinksExisting(path string, hops int) (string, error) {
 	real, err := filepath.EvalSymlinks(path)
 	if err == nil {
 		return real, nil
@@ -216,11 +492,28 @@ func EvalSymlinksExisting(path string) (string, error) {
 		return "", err
 	}
 
-	resolvedDir, err := EvalSymlinksExisting(dir)
+	resolvedDir, err := evalSymlinksExisting(dir, hops)
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(resolvedDir, base), nil
+	joined := filepath.Join(resolvedDir, base)
+
+	// The leaf may be a dangling symlink — follow it to where it points
+	fi, lerr := os.Lstat(joined)
+	if lerr != nil || fi.Mode()&os.ModeSymlink == 0 {
+		return joined, nil
+	}
+	if hops >= maxSymlinkHops {
+		return "", fmt.Errorf("too many levels of symbolic links: %s", path)
+	}
+	target, err := os.Readlink(joined)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(resolvedDir, target)
+	}
+	return evalSymlinksExisting(filepath.Clean(target), hops+1)
 }
 
 // isSudo extracts the sudo flag from a tool call's JSON arguments.
@@ -2,46 +2,78 @@ package conscience
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	"mvdan.cc/sh/v3/syntax"
 )
 
+// BlockedCommandError explains why the sandbox blocked a shell command, so
+// the agent can self-correct instead of retrying blindly.
+type BlockedCommandError struct {
+	Rule       string // denylist rule that matched, e.g. "destructive remove"
+	Match      string // offending part of the command, e.g. "rm -rf build"
+	Suggestion string // safer alternative; empty when none is obvious
+}
+
+func (e *BlockedCommandError) Error() string {
+	msg := fmt.Sprintf("blocked: %s: %q", e.Rule, e.Match)
+	if e.Suggestion != "" {
+		msg += "; " + e.Suggestion
+	}
+	return msg
+}
+
 // denylistEntry defines a command and optional flags that make it dangerous.
 // If Flags is nil, the command is always blocked.
 type denylistEntry struct {
-	Flags  []string // nil = always blocked; non-nil = blocked when ANY flag is present
-	Reason string
+	Flags      []string // nil = always blocked; non-nil = blocked when ANY flag is present
+	Reason     string
+	Suggestion string // safer alternative reported to the agent
 }
 
+const (
+	suggestNoPrivileges = "run the command without elevated privileges, or ask the user to run the privileged step"
+	suggestNoSource     = "run the script as a command instead of sourcing it"
+)
+
 // denylist maps binary names to their danger rules.
 var denylist = map[string]denylistEntry{
 	// Always blocked — privilege escalation / eval
-	"sudo":   {Reason: "privilege escalation"},
-	"doas":   {Reason: "privilege escalation"},
-	"pkexec": {Reason: "privilege escalation"},
-	"su":     {Reason: "switch user"},
-	"eval":   {Reason: "eval execution"},
-	"source": {Reason: "source execution"},
-	".":      {Reason: "source execution"},
+	"sudo":   {Reason: "privilege escalation", Suggestion: suggestNoPrivileges},
+	"doas":   {Reason: "privilege escalation", Suggestion: suggestNoPrivileges},
+	"pkexec": {Reason: "privilege escalation", Suggestion: suggestNoPrivileges},
+	"su":     {Reason: "switch user", Suggestion: suggestNoPrivileges},
+	"eval":   {Reason: "eval execution", Suggestion: "run the command directly instead of through eval"},
+	"source": {Reason: "source execution", Suggestion: suggestNoSource},
+	".":      {Reason: "source execution", Suggestion: suggestNoSource},
 
 	// Always blocked — disk/partition
 	"mkfs":  {Reason: "filesystem format"},
 	"fdisk": {Reason: "partition edit"},
 
 	// Blocked with specific flags
-	"rm":    {Flags: []string{"r", "R", "f", "F"}, Reason: "destructive remove"},
-	"chmod": {Flags: []string{"R"}, Reason: "recursive chmod"},
-	"chown": {Flags: []string{"R"}, Reason: "recursive chown"},
-	"find":  {Flags: []string{"delete", "exec", "execdir"}, Reason: "destructive find"},
+	"rm":    {Flags: []string{"r", "R", "f", "F"}, Reason: "destructive remove", Suggestion: "use `rm` without -r/-f on specific files inside the work directory"},
+	"chmod": {Flags: []string{"R"}, Reason: "recursive chmod", Suggestion: "chmod specific files without -R"},
+	"chown": {Flags: []string{"R"}, Reason: "recursive chown", Suggestion: "chown specific files without -R"},
+	"find":  {Flags: []string{"delete", "exec", "execdir"}, Reason: "destructive find", Suggestion: "list the matches with `find` first, then act on specific files"},
 }
 
 // ddEntry is handled separately since "dd" danger is based on of= arg presence.
-const ddReason = "raw disk write (dd)"
+const (
+	ddReason     = "raw disk write (dd)"
+	ddSuggestion = "write to a regular file inside the work directory with of=<file>, never to a device"
+)
 
 // validateCommandAST parses a shell command string into an AST and walks it
-// to detect dangerous commands. Returns an error if a dangerous pattern is found.
+// to detect dangerous commands with the built-in rules. Returns an error if a
+// dangerous pattern is found.
 func validateCommandAST(command string) error {
+	return (*CommandRules)(nil).validateAST(command)
+}
+
+// validateAST is validateCommandAST restricted to the enabled built-in rules.
+func (r *CommandRules) validateAST(command string) error {
 	prog, err := syntax.NewParser().Parse(strings.NewReader(command), "")
 	if err != nil {
 		// Unparseable command — block conservatively
@@ -55,19 +87,33 @@ func validateCommandAST(command string) error {
 		}
 		switch n := node.(type) {
 		case *syntax.CallExpr:
-			walkErr = checkCallExpr(n)
+			walkErr = r.checkCallExpr(command, n)
 		case *syntax.Redirect:
-			walkErr = checkRedirect(n)
+			if r.enabled(RuleRawDevice) {
+				walkErr = checkRedirect(n)
+			}
 		case *syntax.FuncDecl:
-			walkErr = checkForkBomb(n)
+			if r.enabled(RuleForkBomb) {
+				walkErr = checkForkBomb(n)
+			}
 		}
 		return walkErr == nil
 	})
 	return walkErr
 }
 
-// checkCallExpr checks a simple command (binary + args) against the denylist.
-func checkCallExpr(call *syntax.CallExpr) error {
+// nodeSource returns the source text of node within command.
+func nodeSource(command string, node syntax.Node) string {
+	start, end := int(node.Pos().Offset()), int(node.End().Offset())
+	if start < 0 || end > len(command) || start >= end {
+		return command
+	}
+	return command[start:end]
+}
+
+// checkCallExpr checks a simple command (binary + args) against the enabled
+// denylist entries.
+func (r *CommandRules) checkCallExpr(command string, call *syntax.CallExpr) error {
 	if len(call.Args) == 0 {
 		return nil
 	}
@@ -75,37 +121,46 @@ func checkCallExpr(call *syntax.CallExpr) error {
 	name := resolveWord(call.Args[0])
 	if name == "" {
 		// Dynamic command ($cmd or similar) — block conservatively
-		if containsParamExp(call.Args[0]) {
-			return fmt.Errorf("dynamic command (variable expansion) not allowed")
+		if containsParamExp(call.Args[0]) && r.enabled(RuleDynamicCommand) {
+			return &BlockedCommandError{
+				Rule:       "dynamic command (variable expansion)",
+				Match:      nodeSource(command, call.Args[0]),
+				Suggestion: "spell out the command name instead of using a variable",
+			}
 		}
 		return nil
 	}
 
 	// dd special case: blocked if of=... present
 	if name == "dd" {
+		if !r.enabled(RuleDD) {
+			return nil
+		}
 		for _, arg := range call.Args[1:] {
 			w := resolveWord(arg)
 			if strings.HasPrefix(w, "of=") {
-				return fmt.Errorf("blocked: %s", ddReason)
+				return &BlockedCommandError{Rule: ddReason, Match: nodeSource(command, call), Suggestion: ddSuggestion}
 			}
 		}
 		return nil
 	}
 
-	entry, found := denylist[name]
+	rule := name
+	entry, found := denylist[rule]
 	if !found {
 		// Check prefix match for commands like mkfs.ext4 → mkfs
 		if idx := strings.IndexByte(name, '.'); idx > 0 {
-			entry, found = denylist[name[:idx]]
+			rule = name[:idx]
+			entry, found = denylist[rule]
 		}
 	}
-	if !found {
+	if !found || !r.enabled(rule) {
 		return nil
 	}
 
 	// Always blocked (no flag condition)
 	if entry.Flags == nil {
-		return fmt.Errorf("blocked: %s (%s)", name, entry.Reason)
+		return &BlockedCommandError{Rule: entry.Reason, Match: nodeSource(command, call), Suggestion: entry.Suggestion}
 	}
 
 	// Check flags and arguments
@@ -113,13 +168,80 @@ func checkCallExpr(call *syntax.CallExpr) error {
 	args := extractArgValues(call.Args[1:])
 	for _, f := range entry.Flags {
 		if flags[f] || args["-"+f] {
-			return fmt.Errorf("blocked: %s with -%s (%s)", name, f, entry.Reason)
+			return &BlockedCommandError{
+				Rule:       fmt.Sprintf("%s (-%s)", entry.Reason, f),
+				Match:      nodeSource(command, call),
+				Suggestion: entry.Suggestion,
+			}
 		}
 	}
 
 	return nil
 }
 
+// validateCommandAllowlist parses a shell command and checks that the leading
+// binary of every simple command (including pipelines, lists and command
+// substitutions) is in the allowlist. Binaries are matched by exact name or
+// by base name (/usr/bin/ls → ls).
+func validateCommandAllowlist(command string, allowed map[string]bool) error {
+	prog, err := syntax.NewParser().Parse(strings.NewReader(command), "")
+	if err != nil {
+		return fmt.Errorf("unparseable command: %w", err)
+	}
+
+	var walkErr error
+	syntax.Walk(prog, func(node syntax.Node) bool {
+		if walkErr != nil {
+			return false
+		}
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		name, _, dynamic := leadingBinary(call.Args)
+		switch {
+		case dynamic:
+			walkErr = fmt.Errorf("blocked: dynamic command not allowed in allowlist mode")
+		case name != "" && !allowed[name] && !allowed[path.Base(name)]:
+			walkErr = fmt.Errorf("blocked: %q is not in the sandbox command allowlist", name)
+		}
+		return walkErr == nil
+	})
+	return walkErr
+}
+
+// leadingBinary returns the binary actually executed by a simple command,
+// skipping an `env` prefix with its options and VAR=value assignments
+// (e.g. `env -i FOO=bar cmd` → cmd). A bare `env` resolves to "env".
+// idx is the position of the binary in args; dynamic is true if the binary
+// cannot be resolved statically (expansions, even inside double quotes).
+func leadingBinary(args []*syntax.Word) (name string, idx int, dynamic bool) {
+	first := resolveWord(args[0])
+	if first == "" {
+		return "", 0, true
+	}
+	if path.Base(first) != "env" {
+		return first, 0, false
+	}
+
+	for i := 1; i < len(args); i++ {
+		w := resolveWord(args[i])
+		switch {
+		case w == "":
+			return "", i, true
+		case w == "-u" || w == "--unset" || w == "-C" || w == "--chdir":
+			i++ // option takes a value
+		case strings.HasPrefix(w, "-"):
+			// other env flags (-i, -0, --ignore-environment, ...)
+		case strings.Contains(w, "="):
+			// VAR=value assignment
+		default:
+			return w, i, false
+		}
+	}
+	return first, 0, false
+}
+
 // checkRedirect detects writes to raw device paths (>/dev/sd*).
 func checkRedirect(redir *syntax.Redirect) error {
 	if redir.Op != syntax.RdrOut && redir.Op != syntax.AppOut && redir.Op != syntax.RdrAll {
@@ -130,7 +252,11 @@ func checkRedirect(redir *syntax.Redirect) error {
 	}
 	target := resolveWord(redir.Word)
 	if strings.HasPrefix(target, "/dev/sd") || strings.HasPrefix(target, "/dev/nvme") {
-		return fmt.Errorf("blocked: raw device write to %s", target)
+		return &BlockedCommandError{
+			Rule:       "raw device write",
+			Match:      target,
+			Suggestion: "redirect the output to a file inside the work directory",
+		}
 	}
 	return nil
 }
@@ -151,7 +277,7 @@ func checkForkBomb(fn *syntax.FuncDecl) error {
 		return !found
 	})
 	if found {
-		return fmt.Errorf("blocked: fork bomb (self-recursive function %q)", fnName)
+		return &BlockedCommandError{Rule: "fork bomb (self-recursive function)", Match: fnName}
 	}
 	return nil
 }
@@ -202,6 +328,122 @@ func extractCommandPathsAST(command string) []string {
 	return paths
 }
 
+// extractSymlinkTargetsAST returns the targets of `ln -s` invocations, joined
+// with the directory of the link so relative targets (e.g. `ln -s .. up`) are
+// validated where they will actually resolve. When the link operand is an
+// existing directory the link's parent is used instead, which is stricter.
+// A leading ~ is expanded to home, as the shell does. An `ln -s` argument that
+// cannot be resolved statically ($HOME, "$X", ~user) is an error: its target
+// cannot be checked.
+func extractSymlinkTargetsAST(command, home string) ([]string, error) {
+	prog, err := syntax.NewParser().Parse(strings.NewReader(command), "")
+	if err != nil {
+		return nil, fmt.Errorf("unparseable command: %w", err)
+	}
+
+	var targets []string
+	var walkErr error
+	syntax.Walk(prog, func(node syntax.Node) bool {
+		if walkErr != nil {
+			return false
+		}
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		name, idx, _ := leadingBinary(call.Args)
+		if path.Base(name) != "ln" {
+			return true
+		}
+
+		// Resolve every argument up front: a dynamic one may be the -s flag,
+		// the target or the link, so none can be trusted.
+		words := make([]string, 0, len(call.Args)-idx-1)
+		for _, arg := range call.Args[idx+1:] {
+			w, ok := resolveSymlinkWord(arg, home)
+			if !ok {
+				walkErr = fmt.Errorf("symlink argument %q cannot be resolved statically; spell out the path", nodeSource(command, arg))
+				return false
+			}
+			words = append(words, w)
+		}
+
+		var operands []string
+		symbolic := false
+		targetDir := ""
+		for i := 0; i < len(words); i++ {
+			w := words[i]
+			switch {
+			case w == "--symbolic":
+				symbolic = true
+			case w == "-t" || w == "--target-directory":
+				if i+1 < len(words) {
+					i++
+					targetDir = words[i]
+				}
+			case strings.HasPrefix(w, "--target-directory="):
+				targetDir = strings.TrimPrefix(w, "--target-directory=")
+			case strings.HasPrefix(w, "--"):
+			case strings.HasPrefix(w, "-") && len(w) > 1:
+				if strings.Contains(w, "s") {
+					symbolic = true
+				}
+			default:
+				operands = append(operands, w)
+			}
+		}
+		if !symbolic || len(operands) == 0 {
+			return true
+		}
+
+		linkDir := targetDir
+		if linkDir == "" {
+			linkDir = "."
+			if len(operands) > 1 {
+				link := operands[len(operands)-1]
+				operands = operands[:len(operands)-1]
+				if strings.HasSuffix(link, "/") {
+					linkDir = link
+				} else {
+					linkDir = path.Dir(link)
+				}
+			}
+		}
+		for _, t := range operands {
+			if t == "" {
+				continue
+			}
+			if !path.IsAbs(t) {
+				t = path.Join(linkDir, t)
+			}
+			targets = append(targets, t)
+		}
+		return true
+	})
+	return targets, walkErr
+}
+
+// resolveSymlinkWord resolves an `ln` argument, expanding a leading ~ or ~/
+// to home. ok is false when the word has expansions or names another user's
+// home (~user), or when it needs home and home is unknown.
+func resolveSymlinkWord(w *syntax.Word, home string) (string, bool) {
+	s := resolveWord(w)
+	if s == "" {
+		// "" and '' are literal empty operands; anything else is dynamic
+		return "", !isDynamicWord(w)
+	}
+	if lit, ok := w.Parts[0].(*syntax.Lit); !ok || !strings.HasPrefix(lit.Value, "~") {
+		return s, true // quoted ~ is not expanded
+	}
+	switch {
+	case s == "~" && home != "":
+		return home, true
+	case strings.HasPrefix(s, "~/") && home != "":
+		return path.Join(home, s[2:]), true
+	}
+	return "", false
+}
+
 // extractAllBinariesAST parses a command and returns the binary name from each
 // simple command, descending into subshells and command substitutions.
 func extractAllBinariesAST(command string) []string {
@@ -282,14 +524,40 @@ func resolveWord(w *syntax.Word) string {
 	return sb.String()
 }
 
-// containsParamExp returns true if a Word contains parameter expansion ($var).
-func containsParamExp(w *syntax.Word) bool {
+// isDynamicWord returns true if a Word has any part that is not a literal
+// (expansions, substitutions, arithmetic, ...), including inside double quotes.
+func isDynamicWord(w *syntax.Word) bool {
 	for _, part := range w.Parts {
-		switch part.(type) {
-		case *syntax.ParamExp:
+		switch p := part.(type) {
+		case *syntax.Lit, *syntax.SglQuoted:
+		case *syntax.DblQuoted:
+			for _, dp := range p.Parts {
+				if _, ok := dp.(*syntax.Lit); !ok {
+					return true
+				}
+			}
+		default:
 			return true
-		case *syntax.CmdSubst:
+		}
+	}
+	return false
+}
+
+// containsParamExp returns true if a Word contains parameter expansion ($var)
+// or command substitution, including inside double quotes ("$var").
+func containsParamExp(w *syntax.Word) bool {
+	return partsContainParamExp(w.Parts)
+}
+
+func partsContainParamExp(parts []syntax.WordPart) bool {
+	for _, part := range parts {
+		switch p := part.(type) {
+		case *syntax.ParamExp, *syntax.CmdSubst:
 			return true
+		case *syntax.DblQuoted:
+			if partsContainParamExp(p.Parts) {
+				return true
+			}
 		}
 	}
 	return false
@@ -1,6 +1,8 @@
 package conscience
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -136,9 +138,37 @@ func TestValidateCommandAST_ForkBomb(t *testing.T) {
 }
 
 func TestValidateCommandAST_DynamicCommand(t *testing.T) {
-	err := validateCommandAST("$cmd args")
-	if err == nil {
-		t.Fatal("expected dynamic command to be blocked")
+	for _, cmd := range []string{"$cmd args", `"$cmd" args`, `"${cmd}" args`} {
+		if err := validateCommandAST(cmd); err == nil {
+			t.Errorf("expected dynamic command %q to be blocked", cmd)
+		}
+	}
+}
+
+func TestValidateCommandAST_Explanation(t *testing.T) {
+	err := validateCommandAST("cd build && rm -rf out && ls")
+	var blocked *BlockedCommandError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("expected *BlockedCommandError, got %v", err)
+	}
+	if blocked.Rule != "destructive remove (-r)" {
+		t.Errorf("Rule = %q", blocked.Rule)
+	}
+	if blocked.Match != "rm -rf out" {
+		t.Errorf("Match = %q, want the offending command only", blocked.Match)
+	}
+	if blocked.Suggestion == "" || !strings.Contains(err.Error(), blocked.Suggestion) {
+		t.Errorf("expected a suggestion in %q", err)
+	}
+
+	err = validateCommandAST("eval ls")
+	if err == nil || strings.Count(err.Error(), "instead") > 1 {
+		t.Errorf("message should read naturally: %v", err)
+	}
+
+	err = validateCommandAST("echo hi >/dev/sda")
+	if !errors.As(err, &blocked) || blocked.Match != "/dev/sda" {
+		t.Errorf("expected raw device write explanation, got %v", err)
 	}
 }
 
@@ -256,3 +286,91 @@ func TestContainsSubshellAST(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCommandAllowlist(t *testing.T) {
+	allowed := map[string]bool{"ls": true, "git": true, "grep": true}
+	tests := []struct {
+		cmd     string
+		blocked bool
+	}{
+		{"ls -la", false},
+		{"/usr/bin/ls", false},
+		{"git log | grep fix", false},
+		{"FOO=bar git status", false},
+		{"env FOO=bar git status", false},
+		{"env -i -u HOME FOO=bar ls", false},
+		{"env FOO=bar curl example.com", true},
+		{"env", true},
+		{"ls && curl example.com", true},
+		{"ls $(curl example.com)", true},
+		{"$CMD arg", true},
+		{`"$X" evil.com`, true},
+		{`"${X}" a`, true},
+		{`env "$X" evil.com`, true},
+		{`env FOO=bar "$(echo curl)" evil.com`, true},
+		{`"ls" -la`, false},
+		{"bash -c 'ls'", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.cmd, func(t *testing.T) {
+			err := validateCommandAllowlist(tc.cmd, allowed)
+			if tc.blocked && err == nil {
+				t.Fatalf("expected %q to be blocked", tc.cmd)
+			}
+			if !tc.blocked && err != nil {
+				t.Fatalf("expected %q to pass, got: %v", tc.cmd, err)
+			}
+		})
+	}
+}
+
+func TestExtractSymlinkTargetsAST(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"ln -s .. up", []string{".."}},
+		{"ln -s ../x sub/link", []string{"x"}},
+		{"ln -s /etc/passwd pw", []string{"/etc/passwd"}},
+		{"ln -s a b dir/", []string{"dir/a", "dir/b"}},
+		{"ln -t dir -s ../x", []string{"x"}},
+		{"ln -s only", []string{"only"}},
+		{"ln -s ~ h", []string{"/home/u"}},
+		{"ln -s ~/.ssh k", []string{"/home/u/.ssh"}},
+		{"ln -s '~' h", []string{"~"}},
+		{"ln a b", nil}, // hard link — covered by regular path checks
+		{"echo ln -s .. up", nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.cmd, func(t *testing.T) {
+			got, err := extractSymlinkTargetsAST(tc.cmd, "/home/u")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractSymlinkTargetsAST_Unresolvable(t *testing.T) {
+	cmds := []string{
+		"ln -s $HOME h",
+		`X=/etc; ln -s "$X" e`,
+		"ln -s x $DIR/link",
+		"ln $FLAGS /etc e",
+		"ln -s ~root r",
+		"ln -s $(pwd)/.. up",
+	}
+	for _, cmd := range cmds {
+		t.Run(cmd, func(t *testing.T) {
+			if _, err := extractSymlinkTargetsAST(cmd, "/home/u"); err == nil {
+				t.Fatalf("expected %q to be rejected", cmd)
+			}
+		})
+	}
+	if _, err := extractSymlinkTargetsAST("ln -s ~ h", ""); err == nil {
+		t.Error("expected ~ to be rejected when home is unknown")
+	}
+}
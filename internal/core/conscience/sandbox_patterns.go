@@ -104,28 +104,57 @@ func extractToolPaths(argsJSON string) []string {
 
 // collectPaths recursively walks a JSON value and collects path strings.
 func collectPaths(v any, paths *[]string) {
+	collectKeyed(v, pathKeys, arrayPathKeys, paths)
+}
+
+// collectKeyed recursively walks a JSON value and collects the string values
+// of scalar keys and the string items of array keys.
+func collectKeyed(v any, keys, arrayKeys map[string]bool, out *[]string) {
 	switch val := v.(type) {
 	case map[string]any:
 		for key, child := range val {
-			if pathKeys[key] {
+			if keys[key] {
 				if s, ok := child.(string); ok && s != "" {
-					*paths = append(*paths, s)
+					*out = append(*out, s)
 				}
-			} else if arrayPathKeys[key] {
+			} else if arrayKeys[key] {
 				if arr, ok := child.([]any); ok {
 					for _, item := range arr {
 						if s, ok := item.(string); ok && s != "" {
-							*paths = append(*paths, s)
+							*out = append(*out, s)
 						}
 					}
 				}
 			}
 			// Recurse into nested objects/arrays
-			collectPaths(child, paths)
+			collectKeyed(child, keys, arrayKeys, out)
 		}
 	case []any:
 		for _, item := range val {
-			collectPaths(item, paths)
+			collectKeyed(item, keys, arrayKeys, out)
 		}
 	}
 }
+
+// urlKeys are JSON field names that typically contain URLs.
+var urlKeys = map[string]bool{
+	"url":      true,
+	"endpoint": true,
+}
+
+// arrayURLKeys are JSON field names that typically contain arrays of URLs.
+var arrayURLKeys = map[string]bool{
+	"urls": true,
+}
+
+// extractToolURLs extracts URL-bearing fields ("url", "endpoint", "urls")
+// from a tool's JSON arguments, searching nested objects recursively.
+func extractToolURLs(argsJSON string) []string {
+	var raw any
+	if err := json.Unmarshal([]byte(argsJSON), &raw); err != nil {
+		return nil
+	}
+	var urls []string
+	collectKeyed(raw, urlKeys, arrayURLKeys, &urls)
+	return urls
+}
@@ -0,0 +1,88 @@
+package conscience
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// Names of the built-in rules not keyed by a binary in denylist.
+const (
+	RuleDD             = "dd"
+	RuleRawDevice      = "raw_device"
+	RuleForkBomb       = "fork_bomb"
+	RuleDynamicCommand = "dynamic_command"
+)
+
+// BuiltinRuleNames returns the names of the built-in denylist rules, as
+// accepted by NewCommandRules: the denylisted binaries plus the dd, raw
+// device, fork bomb and dynamic command rules.
+func BuiltinRuleNames() []string {
+	names := make([]string, 0, len(denylist)+4)
+	for name := range denylist {
+		names = append(names, name)
+	}
+	names = append(names, RuleDD, RuleRawDevice, RuleForkBomb, RuleDynamicCommand)
+	sort.Strings(names)
+	return names
+}
+
+// DenyRule is a custom denylist rule: commands matching Pattern are blocked.
+type DenyRule struct {
+	Pattern    *regexp.Regexp
+	Reason     string
+	Suggestion string
+}
+
+// NewDenyRule compiles a custom denylist rule.
+func NewDenyRule(pattern, reason, suggestion string) (DenyRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return DenyRule{}, fmt.Errorf("deny rule %q: %w", pattern, err)
+	}
+	if reason == "" {
+		reason = "custom rule " + pattern
+	}
+	return DenyRule{Pattern: re, Reason: reason, Suggestion: suggestion}, nil
+}
+
+// CommandRules is the denylist applied to exec commands in autonomous mode:
+// the built-in rules minus the disabled ones, plus custom regex rules.
+// A nil *CommandRules applies the built-in rules only.
+type CommandRules struct {
+	disabled map[string]bool
+	custom   []DenyRule
+}
+
+// NewCommandRules builds a denylist. disabled names built-in rules to skip
+// (see BuiltinRuleNames); unknown names are an error.
+func NewCommandRules(disabled []string, custom []DenyRule) (*CommandRules, error) {
+	known := BuiltinRuleNames()
+	r := &CommandRules{disabled: make(map[string]bool, len(disabled)), custom: custom}
+	for _, name := range disabled {
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown built-in sandbox rule %q", name)
+		}
+		r.disabled[name] = true
+	}
+	return r, nil
+}
+
+// enabled reports whether the built-in rule name applies.
+func (r *CommandRules) enabled(name string) bool {
+	return r == nil || !r.disabled[name]
+}
+
+// Validate returns a *BlockedCommandError if command matches a custom rule
+// or an enabled built-in rule. Unparseable commands are blocked.
+func (r *CommandRules) Validate(command string) error {
+	if r != nil {
+		for _, rule := range r.custom {
+			if m := rule.Pattern.FindString(command); m != "" {
+				return &BlockedCommandError{Rule: rule.Reason, Match: m, Suggestion: rule.Suggestion}
+			}
+		}
+	}
+	return r.validateAST(command)
+}
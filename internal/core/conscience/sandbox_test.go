@@ -176,6 +176,55 @@ func TestValidatePathInWorkDir_Symlink(t *testing.T) {
 	}
 }
 
+func TestValidatePathInWorkDir_SymlinkCreation(t *testing.T) {
+	workDir := t.TempDir()
+	outsideDir := t.TempDir()
+
+	// A dangling symlink inside workDir pointing at a not-yet-existing file outside
+	linkPath := filepath.Join(workDir, "dangling-link")
+	if err := os.Symlink(filepath.Join(outsideDir, "new-file"), linkPath); err != nil {
+		t.Skip("symlinks not supported")
+	}
+
+	// Writing through the link would create the file outside — should be blocked
+	if err := validatePathInWorkDir(workDir, linkPath, nil); err == nil {
+		t.Error("expected write through dangling symlink to be blocked")
+	}
+
+	// A dangling directory link followed by a non-existent leaf
+	dirLink := filepath.Join(workDir, "dangling-dir")
+	if err := os.Symlink(filepath.Join(outsideDir, "missing-dir"), dirLink); err != nil {
+		t.Fatal(err)
+	}
+	if err := validatePathInWorkDir(workDir, filepath.Join(dirLink, "file.txt"), nil); err == nil {
+		t.Error("expected write below dangling directory symlink to be blocked")
+	}
+
+	// A dangling link that stays inside workDir is fine
+	innerLink := filepath.Join(workDir, "inner-link")
+	if err := os.Symlink(filepath.Join(workDir, "later.txt"), innerLink); err != nil {
+		t.Fatal(err)
+	}
+	if err := validatePathInWorkDir(workDir, innerLink, nil); err != nil {
+		t.Errorf("expected dangling link inside workdir to pass, got: %v", err)
+	}
+}
+
+func TestEvalSymlinksExisting_Loop(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	if err := os.Symlink(b, a); err != nil {
+		t.Skip("symlinks not supported")
+	}
+	if err := os.Symlink(a, b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EvalSymlinksExisting(filepath.Join(a, "file")); err == nil {
+		t.Error("expected symlink loop to fail")
+	}
+}
+
 // --- SandboxGuard integration tests ---
 
 // fakeTool is a minimal brain.Tool for testing.
@@ -291,6 +340,24 @@ func TestSandboxGuard_FilesystemAllowed(t *testing.T) {
 	}
 }
 
+func TestSandboxGuard_PathListReload(t *testing.T) {
+	workDir := t.TempDir()
+	extra := t.TempDir()
+	paths := NewPathList(nil)
+	guard := WrapSandbox(&fakeTool{}, "write_file", SandboxFilesystem, false, nil, WithPathList(paths))
+
+	ctx := autonomousCtx(workDir)
+	args := `{"path":"` + filepath.Join(extra, "file.txt") + `","content":"ok"}`
+	if _, err := guard.Run(ctx, args); err == nil {
+		t.Fatal("expected write outside workdir to be blocked")
+	}
+
+	paths.Set([]string{extra})
+	if _, err := guard.Run(ctx, args); err != nil {
+		t.Fatalf("expected write to a newly allowed path to pass, got: %v", err)
+	}
+}
+
 func TestSandboxGuard_InteractivePassthrough(t *testing.T) {
 	inner := &fakeTool{}
 	guard := WrapSandbox(inner, "cmd", SandboxExec, false, nil)
@@ -404,3 +471,220 @@ func TestSandboxGuard_GitPathInsideWorkDir(t *testing.T) {
 		t.Error("inner tool should have been called")
 	}
 }
+
+func TestSandboxGuard_GitDiffPathOutsideWorkDir(t *testing.T) {
+	workDir := t.TempDir()
+	inner := &fakeTool{}
+	guard := WrapSandbox(inner, "git", SandboxExec, false, nil)
+
+	ctx := autonomousCtx(workDir)
+	args := `{"action":"diff","args":{"paths":["src/main.go","../../etc/shadow"]}}`
+	if _, err := guard.Run(ctx, args); err == nil {
+		t.Fatal("expected git diff path outside workdir to be blocked")
+	}
+	if inner.called {
+		t.Error("inner tool should not have been called")
+	}
+}
+
+func TestSandboxGuard_AllowedCommands(t *testing.T) {
+	guard := WrapSandbox(&fakeTool{}, "cmd", SandboxExec, false, nil,
+		WithAllowedCommands([]string{"ls", "git"}))
+	ctx := autonomousCtx("")
+
+	if _, err := guard.Run(ctx, `{"command":"git status && ls"}`); err != nil {
+		t.Fatalf("expected allowlisted commands to pass, got: %v", err)
+	}
+	// echo is not denylisted but also not allowlisted
+	if _, err := guard.Run(ctx, `{"command":"echo hello"}`); err == nil {
+		t.Fatal("expected command outside allowlist to be blocked")
+	}
+}
+
+func TestSandboxGuard_AllowlistsReplacedInPlace(t *testing.T) {
+	commands := NewAllowlist([]string{"ls"})
+	hosts := NewAllowlist([]string{"example.com"})
+	exec := WrapSandbox(&fakeTool{}, "cmd", SandboxExec, false, nil, WithCommandAllowlist(commands))
+	net := WrapSandbox(&fakeTool{}, "web_fetch", SandboxNetwork, false, nil, WithHostAllowlist(hosts))
+	ctx := autonomousCtx("")
+
+	if _, err := exec.Run(ctx, `{"command":"git status"}`); err == nil {
+		t.Fatal("expected git to be blocked before the reload")
+	}
+	if _, err := net.Run(ctx, `{"url":"https://other.org"}`); err == nil {
+		t.Fatal("expected other.org to be blocked before the reload")
+	}
+
+	commands.Set([]string{"ls", "git"})
+	hosts.Set(nil)
+	if _, err := exec.Run(ctx, `{"command":"git status"}`); err != nil {
+		t.Errorf("expected git to pass after the reload, got: %v", err)
+	}
+	if _, err := net.Run(ctx, `{"url":"https://other.org"}`); err != nil {
+		t.Errorf("expected an emptied host allowlist to lift the restriction, got: %v", err)
+	}
+}
+
+func TestSandboxGuard_AllowedCommandsInteractivePassthrough(t *testing.T) {
+	inner := &fakeTool{}
+	guard := WrapSandbox(inner, "cmd", SandboxExec, false, nil,
+		WithAllowedCommands([]string{"ls"}))
+
+	if _, err := guard.Run(context.Background(), `{"command":"echo hello"}`); err != nil {
+		t.Fatalf("expected interactive mode to pass through, got: %v", err)
+	}
+	if !inner.called {
+		t.Error("inner tool should have been called")
+	}
+}
+
+func TestSandboxGuard_ConfinedJailsPaths(t *testing.T) {
+	workDir := t.TempDir()
+	ctx := events.WithConfined(events.ContextWithWorkDir(context.Background(), workDir))
+
+	fs := WrapSandbox(&fakeTool{}, "write_file", SandboxFilesystem, false, nil)
+	if _, err := fs.Run(ctx, `{"path":"/etc/passwd","content":"x"}`); err == nil {
+		t.Fatal("expected write outside workdir to be blocked in a confined session")
+	}
+	if _, err := fs.Run(ctx, `{"path":"`+filepath.Join(workDir, "a.txt")+`","content":"x"}`); err != nil {
+		t.Fatalf("expected write inside workdir to pass, got: %v", err)
+	}
+
+	exec := WrapSandbox(&fakeTool{}, "cmd", SandboxExec, false, nil)
+	if _, err := exec.Run(ctx, `{"command":"cat /etc/passwd"}`); err == nil {
+		t.Fatal("expected command path outside workdir to be blocked in a confined session")
+	}
+	if _, err := exec.Run(ctx, `{"command":"ls","working_dir":"/etc"}`); err == nil {
+		t.Fatal("expected working_dir outside workdir to be blocked in a confined session")
+	}
+}
+
+func TestSandboxGuard_ConfinedWithoutWorkDir(t *testing.T) {
+	ctx := events.WithConfined(context.Background())
+	for _, tt := range []sandboxToolType{SandboxExec, SandboxFilesystem} {
+		guard := WrapSandbox(&fakeTool{}, "tool", tt, false, nil)
+		if _, err := guard.Run(ctx, `{"command":"ls","path":"notes.txt"}`); err == nil {
+			t.Errorf("%s: expected a confined session without root directory to fail closed", tt)
+		}
+	}
+}
+
+func TestSandboxGuard_ConfinedSkipsAutonomousChecks(t *testing.T) {
+	workDir := t.TempDir()
+	ctx := events.WithConfined(events.ContextWithWorkDir(context.Background(), workDir))
+
+	// Denylist, allowlist, elevated and network checks stay autonomous-only.
+	inner := &fakeTool{}
+	guard := WrapSandbox(inner, "run_command", SandboxExec, false, nil,
+		WithAllowedCommands([]string{"ls"}))
+	if _, err := guard.Run(ctx, `{"command":"rm -rf build","sudo":true}`); err != nil {
+		t.Fatalf("expected only the path jail in a confined session, got: %v", err)
+	}
+	if !inner.called {
+		t.Error("inner tool should have been called")
+	}
+
+	net := WrapSandbox(&fakeTool{}, "web_fetch", SandboxNetwork, false, nil,
+		WithAllowedHosts([]string{"example.com"}))
+	if _, err := net.Run(ctx, `{"url":"https://other.org"}`); err != nil {
+		t.Fatalf("expected network tools to pass in a confined session, got: %v", err)
+	}
+}
+
+func TestSandboxGuard_SymlinkCreation(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	guard := WrapSandbox(&fakeTool{}, "cmd", SandboxExec, false, nil)
+	ctx := autonomousCtx(workDir)
+	confined := events.WithConfined(events.ContextWithWorkDir(context.Background(), workDir))
+
+	blocked := []string{
+		"ln -s .. up",
+		"ln -sf ../../etc sub/etc",
+		"ln --symbolic /etc/passwd pw",
+		"ln -s -t sub ../../outside",
+		"env LC_ALL=C ln -s .. up",
+		"ln -s ~ h",
+		"ln -s $HOME h",
+		`X=/etc; ln -s \"$X\" e`,
+	}
+	for _, cmd := range blocked {
+		if _, err := guard.Run(ctx, `{"command":"`+cmd+`"}`); err == nil {
+			t.Errorf("expected %q to be blocked", cmd)
+		}
+		if _, err := guard.Run(confined, `{"command":"`+cmd+`"}`); err == nil {
+			t.Errorf("expected %q to be blocked in a confined session", cmd)
+		}
+	}
+
+	allowed := []string{
+		"ln -s target.txt link.txt",
+		"ln -s target.txt sub/link.txt",
+	}
+	for _, cmd := range allowed {
+		if _, err := guard.Run(ctx, `{"command":"`+cmd+`"}`); err != nil {
+			t.Errorf("expected %q to pass, got: %v", cmd, err)
+		}
+	}
+}
+
+func TestHostAllowed(t *testing.T) {
+	allowed := []string{"api.github.com", "*.golang.org"}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"api.github.com", true},
+		{"github.com", false},
+		{"pkg.golang.org", true},
+		{"golang.org", false},
+		{"evilgolang.org", false},
+		{"169.254.169.254", false},
+	}
+	for _, tc := range tests {
+		if got := hostAllowed(tc.host, allowed); got != tc.want {
+			t.Errorf("hostAllowed(%q) = %v, want %v", tc.host, got, tc.want)
+		}
+	}
+	if !hostAllowed("anything.example", []string{"*"}) {
+		t.Error("expected * to allow any host")
+	}
+}
+
+func TestSandboxGuard_NetworkAllowedHosts(t *testing.T) {
+	guard := WrapSandbox(&fakeTool{}, "web", SandboxNetwork, false, nil,
+		WithAllowedHosts([]string{"API.GitHub.com"}))
+	ctx := autonomousCtx("")
+
+	allowed := []string{
+		`{"url":"https://api.github.com/repos"}`,
+		`{"url":"api.github.com:443/repos"}`,
+		`{"query":"golang generics"}`,
+	}
+	for _, args := range allowed {
+		if _, err := guard.Run(ctx, args); err != nil {
+			t.Errorf("expected %s to pass, got: %v", args, err)
+		}
+	}
+
+	blocked := []string{
+		`{"url":"http://169.254.169.254/latest/meta-data"}`,
+		`{"url":"https://api.github.com.evil.io/"}`,
+		`{"url":"file:///etc/passwd"}`,
+		`{"requests":[{"urls":["https://api.github.com","http://localhost:8080"]}]}`,
+	}
+	for _, args := range blocked {
+		if _, err := guard.Run(ctx, args); err == nil {
+			t.Errorf("expected %s to be blocked", args)
+		}
+	}
+
+	// Interactive mode and empty allowlists are unrestricted
+	if _, err := guard.Run(context.Background(), blocked[0]); err != nil {
+		t.Errorf("expected interactive mode to pass through, got: %v", err)
+	}
+	open := WrapSandbox(&fakeTool{}, "web", SandboxNetwork, false, nil)
+	if _, err := open.Run(ctx, blocked[0]); err != nil {
+		t.Errorf("expected no allowlist to pass, got: %v", err)
+	}
+}
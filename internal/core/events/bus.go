@@ -5,7 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,14 +24,17 @@ const (
 	EventOutgoingMessage EventType = "outgoing.message"
 
 	// User → Agent
-	EventUserMessage EventType = "user.message"
+	EventUserMessage   EventType = "user.message"
+	EventUserInterrupt EventType = "user.interrupt"
 
 	// Agent → Client: Assistant
 	EventAssistantStream  EventType = "assistant.stream"
 	EventAssistantMessage EventType = "assistant.message"
 
 	// Agent → Client: Tools
-	EventToolCall EventType = "tool.call"
+	EventToolCall      EventType = "tool.call"
+	EventToolCallDelta EventType = "tool.call.delta"
+	EventToolRecovery  EventType = "tool.recovery"
 
 	// Agent ↔ Client: Prompts
 	EventPromptRequest  EventType = "prompt.request"
@@ -45,9 +48,10 @@ const (
 	EventSessionClosed  EventType = "session.closed"
 
 	// Scheduler
-	EventScheduleTrigger EventType = "schedule.trigger"
-	EventScheduleCreated EventType = "schedule.created"
-	EventScheduleRemoved EventType = "schedule.removed"
+	EventScheduleTrigger  EventType = "schedule.trigger"
+	EventScheduleCreated  EventType = "schedule.created"
+	EventScheduleRemoved  EventType = "schedule.removed"
+	EventScheduleDeferred EventType = "schedule.deferred"
 
 	// Skills
 	EventSkillStarted       EventType = "skill.started"
@@ -69,6 +73,7 @@ const (
 	EventTaskCreated      EventType = "task.created"
 	EventTaskStarted      EventType = "task.started"
 	EventTaskProgress     EventType = "task.progress"
+	EventTaskNarration    EventType = "task.narration"
 	EventTaskCompleted    EventType = "task.completed"
 	EventTaskFailed       EventType = "task.failed"
 	EventTaskCancelled    EventType = "task.cancelled"
@@ -92,13 +97,22 @@ const (
 
 // Event represents an event in the system.
 type Event struct {
-	ID           string         `json:"id"`
-	SessionID    string         `json:"session_id,omitempty"`
-	Type         EventType      `json:"type"`
-	Timestamp    time.Time      `json:"timestamp"`
-	Source       EventSource    `json:"source"`
-	Payload      map[string]any `json:"payload"`
-	typedPayload any            // original typed payload (unexported, for zero-alloc extraction)
+	ID            string         `json:"id"`
+	SessionID     string         `json:"session_id,omitempty"`
+	Type          EventType      `json:"type"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Source        EventSource    `json:"source"`
+	Payload       map[string]any `json:"payload"`
+	CorrelationID string         `json:"correlation_id,omitempty"` // pairs a response with its request (see Bus.Request)
+	typedPayload  any            // original typed payload (unexported, for zero-alloc extraction)
+}
+
+// NewResponse creates a response to a request event: it is routed to the same
+// session and carries the request's correlation ID.
+func NewResponse(request Event, source EventSource, payload EventPayload) Event {
+	e := NewTypedEventWithSession(source, payload, request.SessionID)
+	e.CorrelationID = request.CorrelationID
+	return e
 }
 
 // eventIDCounter is used to generate sequential event IDs.
@@ -138,14 +152,29 @@ type Subscriber func(Event)
 type subscription struct {
 	id         int
 	eventTypes []EventType
+	prefix     string // pattern subscriptions only
+	pattern    bool
 	handler    Subscriber
 }
 
+// MatchPattern reports whether an event type matches a subscription pattern:
+// "*" matches every type, "task.*" every type starting with "task.", and any
+// other pattern only the identical type.
+func MatchPattern(pattern string, t EventType) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(string(t), prefix)
+	}
+	return string(t) == pattern
+}
+
 // EventBus is the interface for publishing and subscribing to events.
 type EventBus interface {
 	Publish(event Event)
 	Subscribe(handler Subscriber, eventTypes ...EventType) func()
+	SubscribePattern(pattern string, handler Subscriber) func()
 	SubscribeChan(bufSize int, eventTypes ...EventType) (<-chan Event, func())
+	Request(ctx context.Context, request Event) (Event, error)
+	Stats() BusStats
 	History(limit int) []Event
 	HistoryFiltered(limit int, filterType EventType) []Event
 	Close()
@@ -157,22 +186,52 @@ var _ EventBus = (*Bus)(nil)
 type Bus struct {
 	mu          sync.RWMutex
 	subscribers map[int]*subscription
+	byType      map[EventType]map[int]*subscription // dispatch index: typed subscriptions
+	all         map[int]*subscription               // dispatch index: catch-all subscriptions
+	byPrefix    map[string]map[int]*subscription    // dispatch index: pattern subscriptions
+	reqMu       sync.Mutex
+	pending     map[string]*pendingRequest // correlation ID → in-flight Request
 	nextID      int
 	eventChan   chan Event
 	bufferSize  int
 	ringBuffer  *RingBuffer
 	closed      bool
 	done        chan struct{}
+
+	policy        OverflowPolicy
+	blockTimeout  time.Duration
+	critical      map[EventType]bool // never dropped
+	statsMu       sync.Mutex         // guards the counters and spill
+	delivered     uint64
+	dropped       uint64
+	droppedByType map[EventType]uint64
+	spilled       uint64
+	spill         []Event       // critical events that did not fit in eventChan
+	spillReady    chan struct{} // signals the dispatcher that spill is non-empty
 }
 
-// NewBus creates a new event bus.
-func NewBus(bufferSize int) *Bus {
+// NewBus creates a new event bus. By default a full buffer drops the newest
+// event, except for DefaultCriticalTypes; see WithOverflowPolicy.
+func NewBus(bufferSize int, opts ...BusOption) *Bus {
 	b := &Bus{
 		subscribers: make(map[int]*subscription),
+		byType:      make(map[EventType]map[int]*subscription),
+		all:         make(map[int]*subscription),
+		byPrefix:    make(map[string]map[int]*subscription),
+		pending:     make(map[string]*pendingRequest),
 		eventChan:   make(chan Event, bufferSize),
 		bufferSize:  bufferSize,
 		ringBuffer:  NewRingBuffer(bufferSize),
 		done:        make(chan struct{}),
+
+		policy:        OverflowDropNewest,
+		blockTimeout:  DefaultBlockTimeout,
+		droppedByType: make(map[EventType]uint64),
+		spillReady:    make(chan struct{}, 1),
+	}
+	WithCriticalTypes(DefaultCriticalTypes...)(b)
+	for _, opt := range opts {
+		opt(b)
 	}
 	go b.dispatch()
 	return b
@@ -182,38 +241,56 @@ func (b *Bus) dispatch() {
 	for {
 		select {
 		case event := <-b.eventChan:
-			b.ringBuffer.Add(event)
-			b.notifySubscribers(event)
+			b.deliver(event)
+		case <-b.spillReady:
+			// Events buffered before the spill go first.
+			for range len(b.eventChan) {
+				select {
+				case event := <-b.eventChan:
+					b.deliver(event)
+				default:
+				}
+			}
+			for _, event := range b.takeSpill() {
+				b.deliver(event)
+			}
 		case <-b.done:
 			return
 		}
 	}
 }
 
+func (b *Bus) deliver(event Event) {
+	b.statsMu.Lock()
+	b.delivered++
+	b.statsMu.Unlock()
+	b.ringBuffer.Add(event)
+	b.notifySubscribers(event)
+	b.resolvePending(event)
+}
+
 func (b *Bus) notifySubscribers(event Event) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	for _, sub := range b.subscribers {
-		if b.matches(sub, event) {
-			sub.handler(event)
-		}
+	for _, sub := range b.byType[event.Type] {
+		sub.handler(event)
 	}
-}
-
-func (b *Bus) matches(sub *subscription, event Event) bool {
-	if len(sub.eventTypes) == 0 {
-		return true
+	for _, sub := range b.all {
+		sub.handler(event)
 	}
-	for _, t := range sub.eventTypes {
-		if t == event.Type {
-			return true
+	for prefix, subs := range b.byPrefix {
+		if !strings.HasPrefix(string(event.Type), prefix) {
+			continue
+		}
+		for _, sub := range subs {
+			sub.handler(event)
 		}
 	}
-	return false
 }
 
-// Publish sends an event to the bus.
+// Publish sends an event to the bus without waiting (except under
+// OverflowBlock); a full buffer is handled by the overflow policy.
 func (b *Bus) Publish(event Event) {
 	b.mu.RLock()
 	closed := b.closed
@@ -226,7 +303,7 @@ func (b *Bus) Publish(event Event) {
 	select {
 	case b.eventChan <- event:
 	default:
-		slog.Warn("event dropped: channel full", "type", event.Type, "session", event.SessionID)
+		b.overflow(event)
 	}
 }
 
@@ -251,23 +328,142 @@ func (b *Bus) PublishAsync(ctx context.Context, event Event) error {
 // Subscribe registers a handler for specific event types.
 // Returns an unsubscribe function.
 func (b *Bus) Subscribe(handler Subscriber, eventTypes ...EventType) func() {
+	return b.add(&subscription{eventTypes: eventTypes, handler: handler})
+}
+
+// SubscribePattern registers a handler for every event type matching pattern
+// (see MatchPattern), e.g. "task.*". Returns an unsubscribe function.
+func (b *Bus) SubscribePattern(pattern string, handler Subscriber) func() {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return b.add(&subscription{prefix: prefix, pattern: true, handler: handler})
+	}
+	return b.add(&subscription{eventTypes: []EventType{EventType(pattern)}, handler: handler})
+}
+
+// add indexes a subscription and returns its unsubscribe function.
+func (b *Bus) add(sub *subscription) func() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	id := b.nextID
+	sub.id = b.nextID
 	b.nextID++
-
-	b.subscribers[id] = &subscription{
-		id:         id,
-		eventTypes: eventTypes,
-		handler:    handler,
+	b.subscribers[sub.id] = sub
+
+	switch {
+	case sub.pattern && sub.prefix == "":
+		b.all[sub.id] = sub
+	case sub.pattern:
+		addToIndex(b.byPrefix, sub.prefix, sub)
+	case len(sub.eventTypes) == 0:
+		b.all[sub.id] = sub
+	default:
+		for _, t := range sub.eventTypes {
+			addToIndex(b.byType, t, sub)
+		}
 	}
 
 	return func() {
 		b.mu.Lock()
 		defer b.mu.Unlock()
-		delete(b.subscribers, id)
+		b.remove(sub.id)
+	}
+}
+
+// remove drops a subscription from every index. Caller must hold b.mu.
+func (b *Bus) remove(id int) {
+	sub, ok := b.subscribers[id]
+	if !ok {
+		return
+	}
+	delete(b.subscribers, id)
+	delete(b.all, id)
+	if sub.pattern {
+		removeFromIndex(b.byPrefix, sub.prefix, id)
+	}
+	for _, t := range sub.eventTypes {
+		removeFromIndex(b.byType, t, id)
+	}
+}
+
+func addToIndex[K comparable](index map[K]map[int]*subscription, key K, sub *subscription) {
+	subs := index[key]
+	if subs == nil {
+		subs = make(map[int]*subscription)
+		index[key] = subs
+	}
+	subs[sub.id] = sub
+}
+
+func removeFromIndex[K comparable](index map[K]map[int]*subscription, key K, id int) {
+	subs := index[key]
+	delete(subs, id)
+	if len(subs) == 0 {
+		delete(index, key)
+	}
+}
+
+// pendingRequest is an in-flight Request waiting for its response.
+type pendingRequest struct {
+	requestID string
+	ch        chan Event // buffered (1): resolvePending never blocks dispatch
+}
+
+// Request publishes request and blocks until an event with the same
+// correlation ID arrives, ctx is done, or the bus closes. A correlation ID is
+// generated when the request has none; responders copy it with NewResponse.
+// Only the first response is returned. Request must not be called from a
+// subscriber: responses are delivered by the dispatch goroutine.
+func (b *Bus) Request(ctx context.Context, request Event) (Event, error) {
+	if request.CorrelationID == "" {
+		request.CorrelationID = "req-" + generateEventID()
+	}
+	id := request.CorrelationID
+	pr := &pendingRequest{requestID: request.ID, ch: make(chan Event, 1)}
+
+	b.reqMu.Lock()
+	if _, dup := b.pending[id]; dup {
+		b.reqMu.Unlock()
+		return Event{}, fmt.Errorf("request %s already in flight", id)
+	}
+	b.pending[id] = pr
+	b.reqMu.Unlock()
+
+	defer func() {
+		b.reqMu.Lock()
+		delete(b.pending, id)
+		b.reqMu.Unlock()
+	}()
+
+	// Registered before publishing, so an immediate response is not missed.
+	if err := b.PublishAsync(ctx, request); err != nil {
+		return Event{}, err
+	}
+
+	select {
+	case resp := <-pr.ch:
+		return resp, nil
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	case <-b.done:
+		return Event{}, ErrBusClosed
+	}
+}
+
+// resolvePending hands an event to the Request waiting on its correlation ID.
+func (b *Bus) resolvePending(event Event) {
+	if event.CorrelationID == "" {
+		return
+	}
+
+	b.reqMu.Lock()
+	defer b.reqMu.Unlock()
+
+	pr, ok := b.pending[event.CorrelationID]
+	if !ok || event.ID == pr.requestID {
+		return
 	}
+	delete(b.pending, event.CorrelationID)
+	pr.ch <- event
 }
 
 // SubscribeChan returns a channel that receives events.
@@ -1,6 +1,8 @@
 package events
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -92,3 +94,312 @@ func TestSubscribeChan(t *testing.T) {
 		t.Fatal("timeout waiting for event")
 	}
 }
+
+func TestSubscribePattern(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+
+	var mu sync.Mutex
+	var tasks, all []EventType
+	unsubTasks := bus.SubscribePattern("task.*", func(e Event) {
+		mu.Lock()
+		tasks = append(tasks, e.Type)
+		mu.Unlock()
+	})
+	bus.SubscribePattern("*", func(e Event) {
+		mu.Lock()
+		all = append(all, e.Type)
+		mu.Unlock()
+	})
+
+	bus.Publish(NewTypedEvent(EventSource("test"), TaskStartedPayload{TaskID: "t1"}))
+	bus.Publish(NewTypedEvent(EventSource("test"), UserMessagePayload{Content: "hello"}))
+	bus.Publish(NewTypedEvent(EventSource("test"), TaskCompletedPayload{TaskID: "t1"}))
+	time.Sleep(50 * time.Millisecond)
+
+	unsubTasks()
+	bus.Publish(NewTypedEvent(EventSource("test"), TaskFailedPayload{TaskID: "t2"}))
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(tasks) != 2 || tasks[0] != EventTaskStarted || tasks[1] != EventTaskCompleted {
+		t.Errorf("task.* received %v, want [task.started task.completed]", tasks)
+	}
+	if len(all) != 4 {
+		t.Errorf("* received %d events, want 4", len(all))
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		typ     EventType
+		want    bool
+	}{
+		{"*", EventUserMessage, true},
+		{"task.*", EventTaskCompleted, true},
+		{"task.*", EventToolCall, false},
+		{"task.completed", EventTaskCompleted, true},
+		{"task.completed", EventTaskFailed, false},
+		{"task", EventTaskCompleted, false},
+	}
+	for _, tt := range tests {
+		if got := MatchPattern(tt.pattern, tt.typ); got != tt.want {
+			t.Errorf("MatchPattern(%q, %q) = %v, want %v", tt.pattern, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestUnsubscribeTyped(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+
+	var mu sync.Mutex
+	count := 0
+	unsub := bus.Subscribe(func(Event) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}, EventUserMessage, EventAssistantMessage)
+	unsub()
+	unsub() // idempotent
+
+	bus.Publish(NewTypedEvent(EventSource("test"), UserMessagePayload{Content: "hello"}))
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count != 0 {
+		t.Errorf("received %d events after unsubscribe", count)
+	}
+	if len(bus.byType) != 0 || len(bus.subscribers) != 0 {
+		t.Errorf("indexes not cleaned up: %d types, %d subscribers", len(bus.byType), len(bus.subscribers))
+	}
+}
+
+func TestBusRequest(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+
+	// Responder answers prompt requests with the request's correlation ID.
+	bus.Subscribe(func(e Event) {
+		req, _ := GetPromptRequestPayload(e)
+		go bus.Publish(NewResponse(e, EventSource("test"), PromptResponsePayload{Value: "yes", Token: req.Token}))
+	}, EventPromptRequest)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := bus.Request(ctx, NewTypedEventWithSession(EventSource("test"), PromptRequestPayload{Token: "tok-1"}, "sess"))
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	p, ok := GetPromptResponsePayload(resp)
+	if !ok || p.Value != "yes" || resp.CorrelationID != "tok-1" || resp.SessionID != "sess" {
+		t.Errorf("response = %+v", resp)
+	}
+
+	bus.reqMu.Lock()
+	defer bus.reqMu.Unlock()
+	if len(bus.pending) != 0 {
+		t.Errorf("pending requests not cleaned up: %d", len(bus.pending))
+	}
+}
+
+func TestBusRequest_GeneratesCorrelationID(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+
+	bus.Subscribe(func(e Event) {
+		if e.CorrelationID == "" {
+			t.Error("request has no correlation ID")
+		}
+		go bus.Publish(NewResponse(e, EventSource("test"), AssistantMessagePayload{Content: "pong"}))
+	}, EventUserMessage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := bus.Request(ctx, NewTypedEvent(EventSource("test"), UserMessagePayload{Content: "ping"}))
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if resp.Type != EventAssistantMessage {
+		t.Errorf("response type = %s", resp.Type)
+	}
+}
+
+func TestBusRequest_Timeout(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := bus.Request(ctx, NewTypedEvent(EventSource("test"), PromptRequestPayload{Token: "tok-2"}))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// A late response is dropped without blocking the bus.
+	bus.Publish(NewTypedEvent(EventSource("test"), PromptResponsePayload{Token: "tok-2"}))
+	ch, unsub := bus.SubscribeChan(1, EventUserMessage)
+	defer unsub()
+	bus.Publish(NewTypedEvent(EventSource("test"), UserMessagePayload{Content: "still alive"}))
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("bus blocked after a late response")
+	}
+
+	bus.reqMu.Lock()
+	defer bus.reqMu.Unlock()
+	if len(bus.pending) != 0 {
+		t.Errorf("pending requests not cleaned up: %d", len(bus.pending))
+	}
+}
+
+// stallBus returns a bus whose dispatcher is blocked inside a handler, so its
+// buffer fills deterministically, and a function to let it run again.
+// Delivered events are recorded in the returned collector.
+func stallBus(t *testing.T, size int, opts ...BusOption) (*Bus, func(), func() []EventType) {
+	t.Helper()
+	bus := NewBus(size, opts...)
+	t.Cleanup(bus.Close)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	bus.Subscribe(func(Event) {
+		close(entered)
+		<-release
+	}, EventType("test.stall"))
+
+	var mu sync.Mutex
+	var delivered []EventType
+	bus.Subscribe(func(e Event) {
+		mu.Lock()
+		delivered = append(delivered, e.Type)
+		mu.Unlock()
+	})
+
+	bus.Publish(Event{ID: "stall", Type: EventType("test.stall")})
+	<-entered
+
+	collect := func() []EventType {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]EventType(nil), delivered...)
+	}
+	return bus, func() { close(release) }, collect
+}
+
+func waitDelivered(t *testing.T, collect func() []EventType, n int) []EventType {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got := collect(); len(got) >= n {
+			return got
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d deliveries, got %v", n, collect())
+	return nil
+}
+
+func TestBusOverflow_DropNewest(t *testing.T) {
+	bus, release, collect := stallBus(t, 2)
+
+	bus.Publish(Event{ID: "1", Type: EventUserMessage})
+	bus.Publish(Event{ID: "2", Type: EventUserMessage})
+	bus.Publish(Event{ID: "3", Type: EventAssistantStream}) // dropped
+	bus.Publish(Event{ID: "4", Type: EventTaskCompleted})   // critical: spilled
+	bus.Publish(Event{ID: "5", Type: EventToolCall})        // critical (audit log): spilled
+	release()
+
+	got := waitDelivered(t, collect, 5)
+	want := []EventType{"test.stall", EventUserMessage, EventUserMessage, EventTaskCompleted, EventToolCall}
+	if len(got) != len(want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("delivered %v, want %v", got, want)
+		}
+	}
+
+	stats := bus.Stats()
+	if stats.Dropped != 1 || stats.DroppedByType[EventAssistantStream] != 1 || stats.Spilled != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestBusOverflow_DropOldest(t *testing.T) {
+	bus, release, collect := stallBus(t, 2, WithOverflowPolicy(OverflowDropOldest, 0))
+
+	bus.Publish(Event{ID: "1", Type: EventAssistantStream}) // evicted
+	bus.Publish(Event{ID: "2", Type: EventUserMessage})
+	bus.Publish(Event{ID: "3", Type: EventAssistantStream})
+	release()
+
+	got := waitDelivered(t, collect, 3)
+	if got[1] != EventUserMessage || got[2] != EventAssistantStream {
+		t.Errorf("delivered %v", got)
+	}
+	if stats := bus.Stats(); stats.Dropped != 1 || stats.DroppedByType[EventAssistantStream] != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestBusOverflow_DropOldestKeepsCritical(t *testing.T) {
+	bus, release, collect := stallBus(t, 1, WithOverflowPolicy(OverflowDropOldest, 0))
+
+	bus.Publish(Event{ID: "1", Type: EventTaskFailed}) // evicted, then spilled
+	bus.Publish(Event{ID: "2", Type: EventUserMessage})
+	release()
+
+	got := waitDelivered(t, collect, 3)
+	if got[1] != EventUserMessage || got[2] != EventTaskFailed {
+		t.Errorf("delivered %v", got)
+	}
+	if stats := bus.Stats(); stats.Dropped != 0 || stats.Spilled != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestBusOverflow_Block(t *testing.T) {
+	bus, release, collect := stallBus(t, 1, WithOverflowPolicy(OverflowBlock, 20*time.Millisecond))
+
+	bus.Publish(Event{ID: "1", Type: EventUserMessage})
+	start := time.Now()
+	bus.Publish(Event{ID: "2", Type: EventAssistantStream}) // times out, dropped
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Publish returned after %v, expected to block", elapsed)
+	}
+	if stats := bus.Stats(); stats.Dropped != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Room freed before the timeout: the event is delivered.
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		release()
+	}()
+	bus.Publish(Event{ID: "3", Type: EventAssistantStream})
+	got := waitDelivered(t, collect, 3)
+	if got[2] != EventAssistantStream {
+		t.Errorf("delivered %v", got)
+	}
+	if stats := bus.Stats(); stats.Dropped != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	if p, err := ParseOverflowPolicy(""); err != nil || p != OverflowDropNewest {
+		t.Errorf("empty: %q, %v", p, err)
+	}
+	if p, err := ParseOverflowPolicy("block"); err != nil || p != OverflowBlock {
+		t.Errorf("block: %q, %v", p, err)
+	}
+	if _, err := ParseOverflowPolicy("drop_all"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
@@ -31,6 +31,21 @@ func IsAutonomousContext(ctx context.Context) bool {
 	return v
 }
 
+type confinedKey struct{}
+
+// WithConfined marks an interactive context as confined to its working
+// directory: sandboxed filesystem and exec tools are jailed to the WorkDir
+// as in autonomous mode, while the other autonomous restrictions don't apply.
+func WithConfined(ctx context.Context) context.Context {
+	return context.WithValue(ctx, confinedKey{}, true)
+}
+
+// IsConfinedContext returns true if the context is confined to its working directory.
+func IsConfinedContext(ctx context.Context) bool {
+	v, _ := ctx.Value(confinedKey{}).(bool)
+	return v
+}
+
 type workDirKey struct{}
 type taskEnvKey struct{}
 
@@ -185,3 +200,29 @@ func TaskIDFromContext(ctx context.Context) string {
 	id, _ := ctx.Value(taskIDKey{}).(string)
 	return id
 }
+
+type turnIDKey struct{}
+
+// ContextWithTurnID returns a context carrying the current conversation turn ID.
+func ContextWithTurnID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, turnIDKey{}, id)
+}
+
+// TurnIDFromContext extracts the turn ID from the context, or "" if absent.
+func TurnIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(turnIDKey{}).(string)
+	return id
+}
+
+type skillRunIDKey struct{}
+
+// ContextWithSkillRunID returns a context carrying the current skill run ID.
+func ContextWithSkillRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, skillRunIDKey{}, id)
+}
+
+// SkillRunIDFromContext extracts the skill run ID from the context, or "" if absent.
+func SkillRunIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(skillRunIDKey{}).(string)
+	return id
+}
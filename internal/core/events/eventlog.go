@@ -1,6 +1,7 @@
 package events
 
 import (
+	"bufio"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -32,8 +33,9 @@ func (el *EventLogger) Close() {
 }
 
 func (el *EventLogger) handleEvent(e Event) {
-	// Filter out stream deltas — too noisy, redundant with assistant.message.
-	if e.Type == EventAssistantStream {
+	// Filter out stream deltas — too noisy, redundant with assistant.message
+	// and tool.call.
+	if e.Type == EventAssistantStream || e.Type == EventToolCallDelta {
 		return
 	}
 	_ = el.writeEvent(e)
@@ -63,8 +65,38 @@ func (el *EventLogger) writeEvent(e Event) error {
 }
 
 func (el *EventLogger) logPath(sessionID string) string {
+	return eventLogPath(el.dir, sessionID)
+}
+
+func eventLogPath(dir, sessionID string) string {
 	if sessionID == "" {
-		return filepath.Join(el.dir, "_global.jsonl")
+		return filepath.Join(dir, "_global.jsonl")
+	}
+	return filepath.Join(dir, sessionID+".jsonl")
+}
+
+// ReadEventLog loads the events persisted by an EventLogger for a session
+// (empty sessionID = global log), oldest first. A missing log yields no
+// events; malformed lines are skipped.
+func ReadEventLog(dir, sessionID string) ([]Event, error) {
+	f, err := os.Open(eventLogPath(dir, sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var evts []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		evts = append(evts, e)
 	}
-	return filepath.Join(el.dir, sessionID+".jsonl")
+	return evts, scanner.Err()
 }
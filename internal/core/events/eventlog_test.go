@@ -179,3 +179,35 @@ func TestEventLogger_DirectoryAutoCreation(t *testing.T) {
 		t.Fatalf("directory not auto-created: %v", err)
 	}
 }
+
+func TestReadEventLog(t *testing.T) {
+	dir := t.TempDir()
+
+	// Missing log is not an error
+	evts, err := ReadEventLog(dir, "sess_missing")
+	if err != nil || len(evts) != 0 {
+		t.Fatalf("expected no events and no error, got %d, %v", len(evts), err)
+	}
+
+	el := &EventLogger{dir: dir}
+	for _, id := range []string{"evt-1", "evt-2"} {
+		if err := el.writeEvent(Event{ID: id, SessionID: "sess_1", Type: EventToolCall, Source: SourceAgent}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	// Append a corrupt line — should be skipped
+	f, err := os.OpenFile(filepath.Join(dir, "sess_1.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{not json\n")
+	f.Close()
+
+	evts, err = ReadEventLog(dir, "sess_1")
+	if err != nil {
+		t.Fatalf("ReadEventLog: %v", err)
+	}
+	if len(evts) != 2 || evts[0].ID != "evt-1" || evts[1].ID != "evt-2" {
+		t.Errorf("unexpected events: %+v", evts)
+	}
+}
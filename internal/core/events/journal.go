@@ -0,0 +1,217 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Journal offsets encode a segment (days since the Unix epoch, UTC) in the
+// high bits and a byte position within that segment in the low bits, so an
+// offset stays valid after the journal rotates to a new day.
+const (
+	journalPosBits    = 40
+	journalPosMask    = 1<<journalPosBits - 1
+	journalReadBatch  = 1000
+	journalDateLayout = "2006-01-02"
+)
+
+// Journal is an append-only, day-segmented log of bus events with monotonic
+// offsets. Unlike EventLogger it is meant for consumers that need to resume
+// processing across restarts: they persist the offset returned by ReadFrom
+// and pass it back on the next call.
+type Journal struct {
+	dir         string
+	now         func() time.Time
+	unsubscribe func()
+
+	mu   sync.Mutex
+	f    *os.File
+	day  int64 // segment of f
+	size int64 // bytes written to f
+}
+
+// NewJournal creates a Journal writing segments (YYYY-MM-DD.jsonl) to dir.
+// When bus is non-nil, every event except stream and tool-call deltas is
+// appended.
+func NewJournal(dir string, bus EventBus) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create journal dir: %w", err)
+	}
+	j := &Journal{dir: dir, now: time.Now}
+	if bus != nil {
+		j.unsubscribe = bus.Subscribe(func(e Event) {
+			if e.Type == EventAssistantStream || e.Type == EventToolCallDelta {
+				return
+			}
+			_, _ = j.Append(e)
+		})
+	}
+	return j, nil
+}
+
+// Close unsubscribes the journal from the bus and closes the open segment.
+func (j *Journal) Close() {
+	if j.unsubscribe != nil {
+		j.unsubscribe()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f != nil {
+		j.f.Close()
+		j.f = nil
+	}
+}
+
+// Append writes an event and returns its offset.
+func (j *Journal) Append(e Event) (int64, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return 0, err
+	}
+	data = append(data, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	day := j.now().UTC().Unix() / 86400
+	if j.f == nil || day != j.day {
+		if err := j.openSegment(day); err != nil {
+			return 0, err
+		}
+	}
+
+	offset := encodeJournalOffset(j.day, j.size)
+	n, err := j.f.Write(data)
+	j.size += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// openSegment switches to the segment of day. Caller must hold j.mu.
+func (j *Journal) openSegment(day int64) error {
+	if j.f != nil {
+		j.f.Close()
+		j.f = nil
+	}
+	f, err := os.OpenFile(j.segmentPath(day), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	j.f, j.day, j.size = f, day, info.Size()
+	return nil
+}
+
+// ReadFrom returns the events at or after offset (0 = the beginning of the
+// journal), at most journalReadBatch of them, and the offset to pass to the
+// next call. An empty result means the consumer has caught up.
+func (j *Journal) ReadFrom(offset int64) ([]Event, int64, error) {
+	if offset < 0 {
+		return nil, offset, fmt.Errorf("invalid journal offset %d", offset)
+	}
+	days, err := j.segments()
+	if err != nil {
+		return nil, offset, err
+	}
+
+	startDay, startPos := decodeJournalOffset(offset)
+	next := offset
+	var evts []Event
+	for _, day := range days {
+		if day < startDay {
+			continue
+		}
+		pos := int64(0)
+		if day == startDay {
+			pos = startPos
+		}
+		var done bool
+		evts, next, done, err = j.readSegment(day, pos, evts, next)
+		if err != nil || done {
+			return evts, next, err
+		}
+	}
+	return evts, next, nil
+}
+
+// readSegment appends the complete records of a segment from pos onwards to
+// evts. It reports done once the batch is full.
+func (j *Journal) readSegment(day, pos int64, evts []Event, next int64) ([]Event, int64, bool, error) {
+	f, err := os.Open(j.segmentPath(day))
+	if err != nil {
+		return evts, next, false, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(pos, io.SeekStart); err != nil {
+		return evts, next, false, err
+	}
+
+	r := bufio.NewReader(f)
+	for len(evts) < journalReadBatch {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return evts, next, false, nil // a partial line is a record still being written
+		}
+		if err != nil {
+			return evts, next, false, err
+		}
+		pos += int64(len(line))
+		next = encodeJournalOffset(day, pos)
+
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			continue // skip malformed records
+		}
+		evts = append(evts, e)
+	}
+	return evts, next, true, nil
+}
+
+// segments lists the days that have a segment file, oldest first.
+func (j *Journal) segments() ([]int64, error) {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+	var days []int64
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		t, err := time.Parse(journalDateLayout, name)
+		if err != nil {
+			continue
+		}
+		days = append(days, t.Unix()/86400)
+	}
+	sort.Slice(days, func(a, b int) bool { return days[a] < days[b] })
+	return days, nil
+}
+
+func (j *Journal) segmentPath(day int64) string {
+	return filepath.Join(j.dir, time.Unix(day*86400, 0).UTC().Format(journalDateLayout)+".jsonl")
+}
+
+func encodeJournalOffset(day, pos int64) int64 {
+	return day<<journalPosBits | pos&journalPosMask
+}
+
+func decodeJournalOffset(offset int64) (day, pos int64) {
+	return offset >> journalPosBits, offset & journalPosMask
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournal_AppendAndReadFrom(t *testing.T) {
+	j, err := NewJournal(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	var offsets []int64
+	for _, id := range []string{"a", "b", "c"} {
+		off, err := j.Append(Event{ID: id, Type: EventUserMessage})
+		if err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		offsets = append(offsets, off)
+	}
+	if offsets[0] >= offsets[1] || offsets[1] >= offsets[2] {
+		t.Fatalf("offsets not monotonic: %v", offsets)
+	}
+
+	evts, next, err := j.ReadFrom(0)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(evts) != 3 || evts[0].ID != "a" || evts[2].ID != "c" {
+		t.Fatalf("unexpected events: %+v", evts)
+	}
+
+	// Resuming from an event's offset includes it; from next, nothing new.
+	evts, _, _ = j.ReadFrom(offsets[1])
+	if len(evts) != 2 || evts[0].ID != "b" {
+		t.Fatalf("read from offset: %+v", evts)
+	}
+	evts, again, _ := j.ReadFrom(next)
+	if len(evts) != 0 || again != next {
+		t.Fatalf("expected caught up, got %d events, next %d→%d", len(evts), next, again)
+	}
+
+	if _, err := j.Append(Event{ID: "d", Type: EventUserMessage}); err != nil {
+		t.Fatal(err)
+	}
+	evts, _, _ = j.ReadFrom(next)
+	if len(evts) != 1 || evts[0].ID != "d" {
+		t.Fatalf("expected only the new event, got %+v", evts)
+	}
+}
+
+func TestJournal_SegmentsByDay(t *testing.T) {
+	dir := t.TempDir()
+	j, err := NewJournal(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	day1 := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	j.now = func() time.Time { return day1 }
+	first, _ := j.Append(Event{ID: "a"})
+	j.now = func() time.Time { return day1.Add(2 * time.Minute) }
+	second, _ := j.Append(Event{ID: "b"})
+
+	for _, name := range []string{"2026-03-01.jsonl", "2026-03-02.jsonl"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing segment %s: %v", name, err)
+		}
+	}
+	if second <= first {
+		t.Fatalf("offset did not grow across rotation: %d → %d", first, second)
+	}
+
+	evts, next, err := j.ReadFrom(0)
+	if err != nil || len(evts) != 2 {
+		t.Fatalf("read all: %d events, %v", len(evts), err)
+	}
+	evts, _, _ = j.ReadFrom(second)
+	if len(evts) != 1 || evts[0].ID != "b" {
+		t.Fatalf("read from second segment: %+v", evts)
+	}
+
+	// Offsets stay valid for a reopened journal.
+	j.Close()
+	j2, _ := NewJournal(dir, nil)
+	defer j2.Close()
+	j2.now = j.now
+	j2.Append(Event{ID: "c"})
+	evts, _, _ = j2.ReadFrom(next)
+	if len(evts) != 1 || evts[0].ID != "c" {
+		t.Fatalf("resume after reopen: %+v", evts)
+	}
+}
+
+func TestJournal_IgnoresPartialRecord(t *testing.T) {
+	dir := t.TempDir()
+	j, _ := NewJournal(dir, nil)
+	defer j.Close()
+
+	j.Append(Event{ID: "a"})
+	f, err := os.OpenFile(j.segmentPath(j.day), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"partial"`)
+	f.Close()
+
+	evts, next, err := j.ReadFrom(0)
+	if err != nil || len(evts) != 1 {
+		t.Fatalf("expected one complete event, got %d (%v)", len(evts), err)
+	}
+	if _, pos := decodeJournalOffset(next); pos == 0 {
+		t.Error("next offset should point past the first record")
+	}
+}
+
+func TestJournal_SubscribesToBus(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+	j, _ := NewJournal(t.TempDir(), bus)
+	defer j.Close()
+
+	bus.Publish(Event{ID: "delta", Type: EventAssistantStream})
+	bus.Publish(Event{ID: "args", Type: EventToolCallDelta})
+	bus.Publish(Event{ID: "msg", Type: EventAssistantMessage})
+	var evts []Event
+	var err error
+	for i := 0; i < 200 && len(evts) == 0; i++ {
+		time.Sleep(time.Millisecond)
+		evts, _, err = j.ReadFrom(0)
+	}
+	if err != nil || len(evts) != 1 || evts[0].ID != "msg" {
+		t.Fatalf("expected only the assistant message, got %+v (%v)", evts, err)
+	}
+}
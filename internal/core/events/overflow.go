@@ -0,0 +1,157 @@
+package events
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+)
+
+// OverflowPolicy decides what Publish does when the bus buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowDropNewest discards the event being published (default).
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowDropOldest evicts the oldest buffered event to make room.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowBlock waits up to the block timeout for room, then drops the event.
+	OverflowBlock OverflowPolicy = "block"
+)
+
+// DefaultBlockTimeout bounds how long Publish waits under OverflowBlock.
+const DefaultBlockTimeout = 500 * time.Millisecond
+
+// ParseOverflowPolicy validates a policy name. An empty name selects OverflowDropNewest.
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(s); p {
+	case "":
+		return OverflowDropNewest, nil
+	case OverflowDropNewest, OverflowDropOldest, OverflowBlock:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy %q (want drop_newest, drop_oldest or block)", s)
+	}
+}
+
+// DefaultCriticalTypes are never dropped by the bus: losing them leaves a
+// task or a pending prompt hanging from the client's point of view, or (tool
+// calls) a gap in the session audit log.
+var DefaultCriticalTypes = []EventType{
+	EventAssistantMessage,
+	EventPromptRequest,
+	EventPromptResponse,
+	EventTaskCompleted,
+	EventTaskFailed,
+	EventTaskCancelled,
+	EventToolCall,
+}
+
+// BusOption configures a Bus.
+type BusOption func(*Bus)
+
+// WithOverflowPolicy sets the overflow policy. blockTimeout only applies to
+// OverflowBlock; non-positive values fall back to DefaultBlockTimeout.
+func WithOverflowPolicy(policy OverflowPolicy, blockTimeout time.Duration) BusOption {
+	return func(b *Bus) {
+		b.policy = policy
+		if blockTimeout > 0 {
+			b.blockTimeout = blockTimeout
+		}
+	}
+}
+
+// WithCriticalTypes replaces the set of event types that are never dropped.
+func WithCriticalTypes(types ...EventType) BusOption {
+	return func(b *Bus) {
+		b.critical = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			b.critical[t] = true
+		}
+	}
+}
+
+// BusStats is a snapshot of the bus delivery counters.
+type BusStats struct {
+	Policy        OverflowPolicy       `json:"policy"`
+	Delivered     uint64               `json:"delivered"` // events dispatched to subscribers
+	Dropped       uint64               `json:"dropped"`
+	DroppedByType map[EventType]uint64 `json:"dropped_by_type,omitempty"`
+	Spilled       uint64               `json:"spilled"` // critical events delivered through the overflow queue
+}
+
+// Stats returns the current delivery counters.
+func (b *Bus) Stats() BusStats {
+	b.statsMu.Lock()
+	defer b.statsMu.Unlock()
+	return BusStats{
+		Policy:        b.policy,
+		Delivered:     b.delivered,
+		Dropped:       b.dropped,
+		DroppedByType: maps.Clone(b.droppedByType),
+		Spilled:       b.spilled,
+	}
+}
+
+// overflow handles an event that did not fit in the buffer.
+func (b *Bus) overflow(event Event) {
+	switch b.policy {
+	case OverflowBlock:
+		timer := time.NewTimer(b.blockTimeout)
+		defer timer.Stop()
+		select {
+		case b.eventChan <- event:
+			return
+		case <-b.done:
+			return
+		case <-timer.C:
+		}
+	case OverflowDropOldest:
+		// The dispatcher competes for the buffer, so a few attempts may be needed.
+		for range 3 {
+			select {
+			case old := <-b.eventChan:
+				b.discard(old)
+			default:
+			}
+			select {
+			case b.eventChan <- event:
+				return
+			default:
+			}
+		}
+	}
+	b.discard(event)
+}
+
+// discard drops an event, unless it is critical: critical events go to the
+// spill queue, which the dispatcher drains after the buffered events it
+// already holds (so they may arrive slightly out of order).
+func (b *Bus) discard(event Event) {
+	if b.critical[event.Type] {
+		b.statsMu.Lock()
+		b.spill = append(b.spill, event)
+		b.spilled++
+		b.statsMu.Unlock()
+		select {
+		case b.spillReady <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	b.statsMu.Lock()
+	b.dropped++
+	b.droppedByType[event.Type]++
+	b.statsMu.Unlock()
+	slog.Warn("event dropped: channel full", "type", event.Type, "session", event.SessionID, "policy", b.policy)
+}
+
+// takeSpill empties the spill queue.
+func (b *Bus) takeSpill() []Event {
+	b.statsMu.Lock()
+	defer b.statsMu.Unlock()
+	spill := b.spill
+	b.spill = nil
+	return spill
+}
@@ -20,6 +20,14 @@ type UserMessagePayload struct {
 
 func (UserMessagePayload) EventType() EventType { return EventUserMessage }
 
+// UserInterruptPayload stops the session's running turn. A non-empty Content
+// starts a new turn with it right away.
+type UserInterruptPayload struct {
+	Content string `json:"content,omitempty"`
+}
+
+func (UserInterruptPayload) EventType() EventType { return EventUserInterrupt }
+
 // =============================================================================
 // ASSISTANT EVENTS
 // =============================================================================
@@ -61,15 +69,44 @@ const (
 )
 
 type ToolCallPayload struct {
-	Status    ToolStatus     `json:"status"`
-	Name      string         `json:"name"`
-	Arguments map[string]any `json:"arguments,omitempty"`
-	Result    string         `json:"result,omitempty"`
-	Error     string         `json:"error,omitempty"`
+	Status     ToolStatus     `json:"status"`
+	Name       string         `json:"name"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Result     string         `json:"result,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	CallID     string         `json:"call_id,omitempty"`    // LLM tool-call ID, pairs started/completed events
+	TaskID     string         `json:"task_id,omitempty"`    // task that issued the call, if any
+	Autonomous bool           `json:"autonomous,omitempty"` // issued by an async task (no user in the loop)
+	// AutoApproved names the auto-approve rule that let a dangerous call skip confirmation.
+	AutoApproved string `json:"auto_approved,omitempty"`
 }
 
 func (ToolCallPayload) EventType() EventType { return EventToolCall }
 
+// ToolCallDeltaPayload carries a fragment of a tool call's arguments while the
+// model is still generating them. Fragments of one call share Index; their
+// Delta concatenation is the JSON arguments of the later tool.call.
+type ToolCallDeltaPayload struct {
+	Index  int    `json:"index"`
+	CallID string `json:"call_id,omitempty"`
+	Name   string `json:"name"`
+	Delta  string `json:"delta,omitempty"`
+}
+
+func (ToolCallDeltaPayload) EventType() EventType { return EventToolCallDelta }
+
+// ToolRecoveryPayload is emitted before a failing tool call is retried.
+type ToolRecoveryPayload struct {
+	ToolName    string `json:"tool_name"`
+	CallID      string `json:"call_id,omitempty"`
+	Attempt     int    `json:"attempt"`      // run about to start, 2..MaxAttempts
+	MaxAttempts int    `json:"max_attempts"` // runs allowed for the call, first included
+	Code        string `json:"code"`         // error code of the failed run
+	Error       string `json:"error"`
+}
+
+func (ToolRecoveryPayload) EventType() EventType { return EventToolRecovery }
+
 // =============================================================================
 // PROMPT EVENTS
 // =============================================================================
@@ -91,6 +128,8 @@ type PromptRequestPayload struct {
 
 func (PromptRequestPayload) EventType() EventType { return EventPromptRequest }
 
+func (p PromptRequestPayload) CorrelationToken() string { return p.Token }
+
 type PromptResponsePayload struct {
 	Value     any    `json:"value"`
 	Cancelled bool   `json:"cancelled"`
@@ -99,6 +138,8 @@ type PromptResponsePayload struct {
 
 func (PromptResponsePayload) EventType() EventType { return EventPromptResponse }
 
+func (p PromptResponsePayload) CorrelationToken() string { return p.Token }
+
 // =============================================================================
 // TOOL APPROVAL EVENTS
 // =============================================================================
@@ -128,6 +169,8 @@ type LLMCallPayload struct {
 	TokensReasoning int           `json:"tokens_reasoning,omitempty"`
 	Duration        time.Duration `json:"duration,omitempty"`
 	Error           string        `json:"error,omitempty"`
+	TaskID          string        `json:"task_id,omitempty"`      // task that issued the call (from context)
+	SkillRunID      string        `json:"skill_run_id,omitempty"` // skill run that issued the call (from context)
 }
 
 func (LLMCallPayload) EventType() EventType { return EventLLMCall }
@@ -155,27 +198,42 @@ func GetContextLayeredPayload(e Event) (ContextLayeredPayload, bool) {
 
 func NewTypedEvent(source EventSource, payload EventPayload) Event {
 	return Event{
-		ID:           generateEventID(),
-		Type:         payload.EventType(),
-		Timestamp:    time.Now(),
-		Source:       source,
-		Payload:      toMap(payload),
-		typedPayload: payload,
+		ID:            generateEventID(),
+		Type:          payload.EventType(),
+		Timestamp:     time.Now(),
+		Source:        source,
+		Payload:       toMap(payload),
+		CorrelationID: correlationOf(payload),
+		typedPayload:  payload,
 	}
 }
 
 func NewTypedEventWithSession(source EventSource, payload EventPayload, sessionID string) Event {
 	return Event{
-		ID:           generateEventID(),
-		SessionID:    sessionID,
-		Type:         payload.EventType(),
-		Timestamp:    time.Now(),
-		Source:       source,
-		Payload:      toMap(payload),
-		typedPayload: payload,
+		ID:            generateEventID(),
+		SessionID:     sessionID,
+		Type:          payload.EventType(),
+		Timestamp:     time.Now(),
+		Source:        source,
+		Payload:       toMap(payload),
+		CorrelationID: correlationOf(payload),
+		typedPayload:  payload,
 	}
 }
 
+// Correlated is implemented by payloads carrying their own correlation token
+// (e.g. prompt tokens); typed event constructors copy it to Event.CorrelationID.
+type Correlated interface {
+	CorrelationToken() string
+}
+
+func correlationOf(payload EventPayload) string {
+	if c, ok := payload.(Correlated); ok {
+		return c.CorrelationToken()
+	}
+	return ""
+}
+
 func toMap(v any) map[string]any {
 	var result map[string]any
 	data, err := json.Marshal(v)
@@ -214,6 +272,10 @@ func GetUserMessagePayload(e Event) (UserMessagePayload, bool) {
 	return ExtractPayload[UserMessagePayload](e)
 }
 
+func GetUserInterruptPayload(e Event) (UserInterruptPayload, bool) {
+	return ExtractPayload[UserInterruptPayload](e)
+}
+
 func GetAssistantStreamPayload(e Event) (AssistantStreamPayload, bool) {
 	return ExtractPayload[AssistantStreamPayload](e)
 }
@@ -226,6 +288,14 @@ func GetToolCallPayload(e Event) (ToolCallPayload, bool) {
 	return ExtractPayload[ToolCallPayload](e)
 }
 
+func GetToolCallDeltaPayload(e Event) (ToolCallDeltaPayload, bool) {
+	return ExtractPayload[ToolCallDeltaPayload](e)
+}
+
+func GetToolRecoveryPayload(e Event) (ToolRecoveryPayload, bool) {
+	return ExtractPayload[ToolRecoveryPayload](e)
+}
+
 func GetPromptRequestPayload(e Event) (PromptRequestPayload, bool) {
 	return ExtractPayload[PromptRequestPayload](e)
 }
@@ -244,6 +314,7 @@ func GetLLMCallPayload(e Event) (LLMCallPayload, bool) {
 
 type SkillStartedPayload struct {
 	SkillName string            `json:"skill_name"`
+	Version   string            `json:"version,omitempty"` // resolved skill version ("" = unversioned)
 	Type      string            `json:"type"`
 	Vars      map[string]string `json:"vars,omitempty"`
 }
@@ -251,12 +322,20 @@ type SkillStartedPayload struct {
 func (SkillStartedPayload) EventType() EventType { return EventSkillStarted }
 
 type SkillCompletedPayload struct {
-	SkillName string        `json:"skill_name"`
-	Output    string        `json:"output,omitempty"`
-	Error     string        `json:"error,omitempty"`
-	Duration  time.Duration `json:"duration,omitempty"`
+	SkillName    string        `json:"skill_name"`
+	Version      string        `json:"version,omitempty"` // resolved skill version ("" = unversioned)
+	Output       string        `json:"output,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	TokensInput  int           `json:"tokens_input,omitempty"`
+	TokensOutput int           `json:"tokens_output,omitempty"`
+	Chain        []string      `json:"chain,omitempty"` // skills completed before this run in a scheduler chain, oldest first
 }
 
+// SkillChainVar is the skill variable carrying the lineage of a chained run
+// (after_skill): the comma-separated skills completed before it, oldest first.
+const SkillChainVar = "trigger_chain"
+
 func (SkillCompletedPayload) EventType() EventType { return EventSkillCompleted }
 
 type SkillStepStartedPayload struct {
@@ -275,6 +354,8 @@ type SkillStepCompletedPayload struct {
 	Output    string        `json:"output,omitempty"`
 	Error     string        `json:"error,omitempty"`
 	Duration  time.Duration `json:"duration,omitempty"`
+	Attempts  int           `json:"attempts,omitempty"`  // runs of the step (on_failure: retry)
+	Continued bool          `json:"continued,omitempty"` // failed, but the workflow went on (on_failure: continue)
 }
 
 func (SkillStepCompletedPayload) EventType() EventType { return EventSkillStepCompleted }
@@ -327,6 +408,15 @@ type TaskProgressPayload struct {
 
 func (TaskProgressPayload) EventType() EventType { return EventTaskProgress }
 
+// TaskNarrationPayload carries interim sub-agent output of a verbose task.
+type TaskNarrationPayload struct {
+	TaskID string `json:"task_id"`
+	Title  string `json:"title,omitempty"`
+	Text   string `json:"text"`
+}
+
+func (TaskNarrationPayload) EventType() EventType { return EventTaskNarration }
+
 type TaskCompletedPayload struct {
 	TaskID        string        `json:"task_id"`
 	Title         string        `json:"title"`
@@ -371,6 +461,10 @@ func GetTaskProgressPayload(e Event) (TaskProgressPayload, bool) {
 	return ExtractPayload[TaskProgressPayload](e)
 }
 
+func GetTaskNarrationPayload(e Event) (TaskNarrationPayload, bool) {
+	return ExtractPayload[TaskNarrationPayload](e)
+}
+
 func GetTaskCompletedPayload(e Event) (TaskCompletedPayload, bool) {
 	return ExtractPayload[TaskCompletedPayload](e)
 }
@@ -425,6 +519,23 @@ func GetScheduleRemovedPayload(e Event) (ScheduleRemovedPayload, bool) {
 	return ExtractPayload[ScheduleRemovedPayload](e)
 }
 
+// ScheduleDeferredPayload reports autonomous work held back by quiet hours.
+// EntryID is set for scheduler triggers, TaskID for pending tasks in the pool.
+type ScheduleDeferredPayload struct {
+	EntryID string `json:"entry_id,omitempty"`
+	TaskID  string `json:"task_id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Trigger string `json:"trigger,omitempty"`
+	Action  string `json:"action"` // "deferred" | "skipped"
+	Until   string `json:"until"`  // RFC3339 end of the quiet window
+}
+
+func (ScheduleDeferredPayload) EventType() EventType { return EventScheduleDeferred }
+
+func GetScheduleDeferredPayload(e Event) (ScheduleDeferredPayload, bool) {
+	return ExtractPayload[ScheduleDeferredPayload](e)
+}
+
 // =============================================================================
 // VERIFICATION EVENTS
 // =============================================================================
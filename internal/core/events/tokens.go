@@ -0,0 +1,43 @@
+package events
+
+import "sync"
+
+// TokenTracker accumulates token usage from LLM response events that match
+// a correlation filter (e.g. a task or skill run ID).
+type TokenTracker struct {
+	mu     sync.Mutex
+	input  int
+	output int
+	unsub  func()
+}
+
+// TrackTokens subscribes to EventLLMCall and sums the tokens of every
+// "response" payload accepted by match. Call Stop when done.
+func TrackTokens(bus EventBus, match func(LLMCallPayload) bool) *TokenTracker {
+	t := &TokenTracker{}
+	t.unsub = bus.Subscribe(func(e Event) {
+		payload, ok := GetLLMCallPayload(e)
+		if !ok || payload.Phase != "response" || !match(payload) {
+			return
+		}
+		t.mu.Lock()
+		t.input += payload.TokensInput
+		t.output += payload.TokensOutput
+		t.mu.Unlock()
+	}, EventLLMCall)
+	return t
+}
+
+// Usage returns the tokens accumulated so far.
+func (t *TokenTracker) Usage() (input, output int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.input, t.output
+}
+
+// Stop unsubscribes the tracker from the bus.
+func (t *TokenTracker) Stop() {
+	if t.unsub != nil {
+		t.unsub()
+	}
+}
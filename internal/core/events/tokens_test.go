@@ -0,0 +1,37 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackTokens_FiltersByCorrelation(t *testing.T) {
+	bus := NewBus(64)
+	defer bus.Close()
+
+	tracker := TrackTokens(bus, func(p LLMCallPayload) bool { return p.TaskID == "task_a" })
+	defer tracker.Stop()
+
+	bus.Publish(NewTypedEvent(SourceAgent, LLMCallPayload{Phase: "response", TaskID: "task_a", TokensInput: 100, TokensOutput: 20}))
+	bus.Publish(NewTypedEvent(SourceAgent, LLMCallPayload{Phase: "response", TaskID: "task_a", TokensInput: 50, TokensOutput: 5}))
+	bus.Publish(NewTypedEvent(SourceAgent, LLMCallPayload{Phase: "request", TaskID: "task_a", TokensInput: 999}))
+	bus.Publish(NewTypedEvent(SourceAgent, LLMCallPayload{Phase: "response", TaskID: "task_b", TokensInput: 999}))
+	bus.Publish(NewTypedEvent(SourceAgent, LLMCallPayload{Phase: "response", TokensInput: 999}))
+
+	// Events are dispatched in order: once the sentinel is delivered, the
+	// tracker has seen every LLM call published before it.
+	delivered := make(chan struct{})
+	unsub := bus.Subscribe(func(Event) { close(delivered) }, "test.sentinel")
+	defer unsub()
+	bus.Publish(Event{Type: "test.sentinel"})
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("sentinel event not delivered")
+	}
+
+	in, out := tracker.Usage()
+	if in != 150 || out != 25 {
+		t.Errorf("got usage %d/%d, want 150/25", in, out)
+	}
+}
@@ -1,14 +1,83 @@
 package introspection
 
 import (
+	"context"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+// Log output formats.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
 )
 
-// SetupLogger configures the global slog logger with the given level.
-func SetupLogger(level slog.Level) {
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+// SetupLogger configures the global slog logger with the given level and
+// format ("text" or "json"; anything else falls back to text). Pass a
+// *slog.LevelVar to change the level at runtime.
+// Records logged with a context carry its correlation IDs (see ContextHandler).
+func SetupLogger(level slog.Leveler, format string) {
+	slog.SetDefault(slog.New(NewLogHandler(os.Stderr, level, format)))
+}
+
+// NewLogHandler builds the gateway log handler writing to w.
+func NewLogHandler(w io.Writer, level slog.Leveler, format string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(strings.TrimSpace(format), LogFormatJSON) {
+		return NewContextHandler(slog.NewJSONHandler(w, opts))
+	}
+	return NewContextHandler(slog.NewTextHandler(w, opts))
+}
+
+// ContextHandler adds the correlation IDs found in the record's context —
+// session_id, turn_id, task_id and skill_run_id — to every record logged with
+// slog.*Context, unless the call site already set the same key.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps h with context correlation IDs.
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+// Handle implements slog.Handler.
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	ids := [...]slog.Attr{
+		slog.String("session_id", events.SessionIDFromContext(ctx)),
+		slog.String("turn_id", events.TurnIDFromContext(ctx)),
+		slog.String("task_id", events.TaskIDFromContext(ctx)),
+		slog.String("skill_run_id", events.SkillRunIDFromContext(ctx)),
+	}
+	for _, id := range ids {
+		if id.Value.String() != "" && !hasAttr(r, id.Key) {
+			r.AddAttrs(id)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func hasAttr(r slog.Record, key string) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == key
+		return !found
+	})
+	return found
 }
 
 // ResolveLogLevel maps a config string ("debug", "info", "warn", "error") to slog.Level.
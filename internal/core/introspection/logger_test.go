@@ -1,8 +1,14 @@
 package introspection
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"log/slog"
+	"strings"
 	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
 func TestResolveLogLevel(t *testing.T) {
@@ -29,3 +35,53 @@ func TestResolveLogLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewTextHandler(&buf, nil))).With("component", "test")
+
+	ctx := events.ContextWithSessionID(context.Background(), "sess_1")
+	ctx = events.ContextWithTurnID(ctx, "t1")
+	logger.InfoContext(ctx, "hello", "task_id", "explicit")
+	logger.Info("no context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines: %q", len(lines), buf.String())
+	}
+	for _, want := range []string{"component=test", "session_id=sess_1", "turn_id=t1", "task_id=explicit"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("line %q missing %q", lines[0], want)
+		}
+	}
+	if strings.Count(lines[0], "task_id=") != 1 || strings.Contains(lines[0], "skill_run_id") {
+		t.Errorf("line %q: want the explicit task_id only and no empty IDs", lines[0])
+	}
+	if strings.Contains(lines[1], "session_id") {
+		t.Errorf("line %q: context-free record got correlation IDs", lines[1])
+	}
+}
+
+func TestNewLogHandler_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(&buf, slog.LevelInfo, "JSON"))
+
+	logger.Debug("filtered out")
+	logger.InfoContext(events.ContextWithTaskID(context.Background(), "task_1"), "hello")
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("not a single JSON record: %v (%q)", err, buf.String())
+	}
+	if rec["msg"] != "hello" || rec["task_id"] != "task_1" {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestNewLogHandler_DefaultsToText(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewLogHandler(&buf, slog.LevelInfo, "")).Info("hello")
+	if !strings.HasPrefix(buf.String(), "time=") {
+		t.Errorf("got %q, want text output", buf.String())
+	}
+}
@@ -13,20 +13,28 @@ import (
 //	~/.ozzie/sessions/sess_xxx/layered/index.json
 //	~/.ozzie/sessions/sess_xxx/layered/archives/archive_<id>.json
 type Store struct {
-	sessionsDir string // base directory containing session subdirectories
+	sessionDir func(sessionID string) string // locates a session's directory
 }
 
 // NewStore creates a Store rooted at the given sessions directory.
 func NewStore(sessionsDir string) *Store {
-	return &Store{sessionsDir: sessionsDir}
+	return NewStoreFunc(func(sessionID string) string {
+		return filepath.Join(sessionsDir, sessionID)
+	})
+}
+
+// NewStoreFunc creates a Store that locates each session's directory with
+// sessionDir, for session stores that nest sessions (e.g. per project).
+func NewStoreFunc(sessionDir func(sessionID string) string) *Store {
+	return &Store{sessionDir: sessionDir}
 }
 
 func (s *Store) layeredDir(sessionID string) string {
-	return filepath.Join(s.sessionsDir, sessionID, "layered")
+	return filepath.Join(s.sessionDir(sessionID), "layered")
 }
 
 func (s *Store) archivesDir(sessionID string) string {
-	return filepath.Join(s.sessionsDir, sessionID, "layered", "archives")
+	return filepath.Join(s.layeredDir(sessionID), "archives")
 }
 
 func (s *Store) indexPath(sessionID string) string {
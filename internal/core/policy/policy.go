@@ -33,7 +33,7 @@ func SupportPolicy() Policy {
 		SessionMode:   "ephemeral",
 		AllowedSkills: nil, // all
 		AllowedTools:  nil, // all (dangerous tools denied below)
-		DeniedTools:   []string{"run_command", "write_file", "edit_file"},
+		DeniedTools:   []string{"run_command", "write_file", "edit_file", "write_files"},
 		ApprovalMode:  "none",
 		ClientFacing:  true,
 		MaxConcurrent: 2,
@@ -141,6 +141,17 @@ Task: %s
 Output (truncated):
 %s`
 
+// TaskResultSummaryPrompt is the prompt template for summarizing a completed
+// task's output before storing it as a memory. Use with
+// fmt.Sprintf(prompt, title, output).
+const TaskResultSummaryPrompt = `Summarize what this task did and found in 3-6 sentences, for recall in future sessions.
+Keep concrete findings, names, numbers and decisions. Omit process details.
+
+Task: %s
+
+Output (truncated):
+%s`
+
 // SummarizeLayeredL0 is the prompt template for L0 abstract summarization
 // (1-2 sentences). Use with fmt.Sprintf(prompt, targetTokens, text).
 const SummarizeLayeredL0 = `Summarize the following conversation excerpt in 1-2 sentences (max %d tokens). Focus on the key topic and outcome.
@@ -158,6 +169,15 @@ const SummarizeLayeredL1 = `Produce a structured bullet-point summary of the fol
 // messages) are assembled by the Compressor.
 const SummarizeCompressorInstructions = `You are summarizing a conversation between a user and an AI assistant.`
 
+// SummarizeTaskContext is the prompt template for compacting the context
+// injected into a task instruction after it overflowed the model's context
+// window. Use with fmt.Sprintf(prompt, maxWords, text).
+const SummarizeTaskContext = `The following context for a background task is too long for the model. Condense it to under %d words.
+Preserve: results of prior tasks, file paths, names, numbers, decisions and errors. Drop repetition and boilerplate.
+Keep the section headings.
+
+%s`
+
 // DefaultRegistry is the pre-populated registry containing all built-in templates.
 var DefaultRegistry = newDefaultRegistry()
 
@@ -170,8 +190,10 @@ func newDefaultRegistry() *Registry {
 	r.Register("instructions.subagent", "Sub-agent instructions", SubAgentInstructions)
 	r.Register("instructions.subagent.compact", "Sub-agent instructions (compact)", SubAgentInstructionsCompact)
 	r.Register("extraction.lessons", "Task lesson extraction", ExtractionLessonsPrompt)
+	r.Register("summarize.task_result", "Remembered task result summary", TaskResultSummaryPrompt)
 	r.Register("summarize.layered.l0", "Layered context L0 abstract", SummarizeLayeredL0)
 	r.Register("summarize.layered.l1", "Layered context L1 summary", SummarizeLayeredL1)
 	r.Register("summarize.compressor", "Context compressor instructions", SummarizeCompressorInstructions)
+	r.Register("summarize.task_context", "Task context compaction", SummarizeTaskContext)
 	return r
 }
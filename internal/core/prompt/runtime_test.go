@@ -55,9 +55,11 @@ func TestDefaultRegistry_ContainsAllTemplates(t *testing.T) {
 		"instructions.subagent",
 		"instructions.subagent.compact",
 		"extraction.lessons",
+		"summarize.task_result",
 		"summarize.layered.l0",
 		"summarize.layered.l1",
 		"summarize.compressor",
+		"summarize.task_context",
 	}
 
 	all := DefaultRegistry.All()
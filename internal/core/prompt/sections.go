@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // ActorInfo describes a configured actor overlay for the planner prompt.
@@ -21,6 +22,14 @@ type MemoryInfo struct {
 	Content string
 }
 
+// TaskInfo holds a finished background task for prompt injection.
+type TaskInfo struct {
+	ID      string
+	Title   string
+	Status  string
+	Summary string // output excerpt or error message
+}
+
 // SystemTool describes a tool available in the runtime environment.
 type SystemTool struct {
 	Name    string `json:"name"`
@@ -167,6 +176,60 @@ func MemorySection(memories []MemoryInfo, contentMax int) string {
 	return sb.String()
 }
 
+// RecentTasksSection builds the "## Recent Background Tasks" block.
+// If summaryMax > 0, each task's summary is truncated to that length.
+func RecentTasksSection(tasks []TaskInfo, summaryMax int) string {
+	if len(tasks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Recent Background Tasks\n\n")
+	sb.WriteString("Tasks from this session that finished recently (use query_tasks for details):\n\n")
+	for _, t := range tasks {
+		sb.WriteString(fmt.Sprintf("- **%s** [%s] (%s)", t.Title, t.Status, t.ID))
+		summary := strings.Join(strings.Fields(t.Summary), " ")
+		if summaryMax > 0 && len(summary) > summaryMax {
+			summary = summary[:summaryMax] + "..."
+		}
+		if summary != "" {
+			sb.WriteString(": " + summary)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// SessionVarsSection builds the "## Session Variables" block, sorted by key.
+// If valueMax > 0, each value is truncated to that length.
+func SessionVarsSection(vars map[string]string, valueMax int) string {
+	if len(vars) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("## Session Variables\n\n")
+	sb.WriteString("Scratch values stored with set_var in this session:\n\n")
+	for _, k := range keys {
+		v := vars[k]
+		if valueMax > 0 && len(v) > valueMax {
+			cut := valueMax
+			for cut > 0 && !utf8.RuneStart(v[cut]) {
+				cut--
+			}
+			v = v[:cut] + "..."
+		}
+		sb.WriteString(fmt.Sprintf("- %s = %s\n", k, v))
+	}
+	return sb.String()
+}
+
 // LanguageSection builds the "## Language" block for a given language code.
 func LanguageSection(langCode string) string {
 	if langCode == "" {
@@ -184,6 +247,18 @@ func CustomInstructionSection(instructions string) string {
 	return "## Additional Instructions\n\n" + instructions + "\n\n"
 }
 
+// SessionPersonaSection builds the "## Session Persona" block from a
+// per-session instruction override (set_persona).
+func SessionPersonaSection(persona string) string {
+	if persona == "" {
+		return ""
+	}
+	return "## Session Persona\n\n" +
+		"For this session only, act according to the following instructions. " +
+		"They take precedence over your default persona where they conflict.\n\n" +
+		persona + "\n\n"
+}
+
 // RuntimeSection builds the "## Runtime Environment" prompt section.
 // Returns "" in local mode with no tools available.
 func RuntimeSection(environment string, tools []SystemTool) string {
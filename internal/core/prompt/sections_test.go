@@ -3,6 +3,7 @@ package prompt
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestToolSection_ActiveOnly(t *testing.T) {
@@ -157,6 +158,60 @@ func TestMemorySection_NoTruncation(t *testing.T) {
 	}
 }
 
+func TestSessionVarsSection_Empty(t *testing.T) {
+	if result := SessionVarsSection(nil, 0); result != "" {
+		t.Errorf("expected empty, got %q", result)
+	}
+}
+
+func TestSessionVarsSection_SortedAndTruncated(t *testing.T) {
+	result := SessionVarsSection(map[string]string{
+		"zeta":  "last",
+		"alpha": "a very long value",
+	}, 6)
+
+	if !strings.Contains(result, "## Session Variables") {
+		t.Error("expected section header")
+	}
+	if strings.Index(result, "alpha") > strings.Index(result, "zeta") {
+		t.Errorf("expected keys sorted, got %q", result)
+	}
+	if !strings.Contains(result, "alpha = a very...") {
+		t.Errorf("expected truncated value, got %q", result)
+	}
+}
+
+func TestSessionVarsSection_TruncatesOnRuneBoundary(t *testing.T) {
+	// "é" is two bytes: a 6-byte cut would split the third one.
+	result := SessionVarsSection(map[string]string{"city": "éééé"}, 5)
+	if !strings.Contains(result, "city = éé...") {
+		t.Errorf("expected the value cut before a split rune, got %q", result)
+	}
+	if !utf8.ValidString(result) {
+		t.Errorf("section is not valid UTF-8: %q", result)
+	}
+}
+
+func TestRecentTasksSection_Empty(t *testing.T) {
+	if result := RecentTasksSection(nil, 0); result != "" {
+		t.Errorf("expected empty, got %q", result)
+	}
+}
+
+func TestRecentTasksSection_WithTruncation(t *testing.T) {
+	result := RecentTasksSection([]TaskInfo{
+		{ID: "task_1", Title: "Build", Status: "completed", Summary: "all\n  tests passed"},
+		{ID: "task_2", Title: "Deploy", Status: "failed"},
+	}, 8)
+
+	if !strings.Contains(result, "- **Build** [completed] (task_1): all test...") {
+		t.Errorf("expected collapsed and truncated summary, got %q", result)
+	}
+	if !strings.Contains(result, "- **Deploy** [failed] (task_2)\n") {
+		t.Errorf("expected task without summary, got %q", result)
+	}
+}
+
 func TestLanguageSection_Empty(t *testing.T) {
 	result := LanguageSection("")
 	if result != "" {
@@ -185,6 +240,16 @@ func TestCustomInstructionSection_Empty(t *testing.T) {
 	}
 }
 
+func TestSessionPersonaSection(t *testing.T) {
+	if result := SessionPersonaSection(""); result != "" {
+		t.Errorf("expected empty, got %q", result)
+	}
+	result := SessionPersonaSection("You are a strict code reviewer.")
+	if !strings.Contains(result, "## Session Persona") || !strings.Contains(result, "strict code reviewer") {
+		t.Errorf("unexpected section: %q", result)
+	}
+}
+
 func TestCustomInstructionSection_WithContent(t *testing.T) {
 	result := CustomInstructionSection("Be concise.")
 	if !strings.Contains(result, "## Additional Instructions") {
@@ -71,6 +71,13 @@ func ParseSkillMD(data []byte) (*SkillMD, error) {
 		}
 	}
 
+	// Normalize version: YAML reads 1.2 as a number
+	if val, ok := raw["version"]; ok && val != nil {
+		if _, ok := val.(string); !ok {
+			raw["version"] = fmt.Sprint(val)
+		}
+	}
+
 	// Re-marshal and unmarshal into struct
 	normalized, err := yaml.Marshal(raw)
 	if err != nil {
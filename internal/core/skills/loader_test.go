@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestSplitFrontmatter(t *testing.T) {
@@ -153,6 +154,8 @@ steps:
     title: Build
     instruction: Build the thing.
     needs: [analyze]
+    timeout: 5m
+    on_failure: retry
 `
 	if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(workflow), 0o644); err != nil {
 		t.Fatal(err)
@@ -171,6 +174,9 @@ steps:
 	if skill.Workflow.Vars["task"].Required != true {
 		t.Error("expected task var to be required")
 	}
+	if build := skill.Workflow.Steps[1]; build.Timeout != 5*time.Minute || build.OnFailure != FailureRetry {
+		t.Errorf("build step timeout/on_failure = %v/%q, want 5m/retry", build.Timeout, build.OnFailure)
+	}
 }
 
 func TestLoadSkillDir_WithTriggers(t *testing.T) {
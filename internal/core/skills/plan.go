@@ -0,0 +1,41 @@
+package skills
+
+import "github.com/dohr-michael/ozzie/internal/core/brain"
+
+// ToPlanStep converts a workflow step to the shared plan representation.
+// Acceptance criteria are workflow-specific and not carried over.
+func (s Step) ToPlanStep() brain.PlanStep {
+	return brain.PlanStep{
+		ID:          s.ID,
+		Title:       s.Title,
+		Description: s.Instruction,
+		DependsOn:   s.Needs,
+		Tools:       s.Tools,
+		Model:       s.Model,
+	}
+}
+
+// PlanFromSteps builds a plan from workflow steps.
+func PlanFromSteps(title string, steps []Step) *brain.Plan {
+	p := &brain.Plan{Title: title, Steps: make([]brain.PlanStep, len(steps))}
+	for i, s := range steps {
+		p.Steps[i] = s.ToPlanStep()
+	}
+	return p
+}
+
+// StepsFromPlan converts a plan to workflow steps runnable by the DAG engine.
+func StepsFromPlan(p *brain.Plan) []Step {
+	steps := make([]Step, len(p.Steps))
+	for i, ps := range p.Steps {
+		steps[i] = Step{
+			ID:          ps.ID,
+			Title:       ps.Title,
+			Instruction: ps.Description,
+			Tools:       ps.Tools,
+			Model:       ps.Model,
+			Needs:       ps.DependsOn,
+		}
+	}
+	return steps
+}
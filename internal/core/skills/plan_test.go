@@ -0,0 +1,28 @@
+package skills
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanConversion_RoundTrip(t *testing.T) {
+	steps := []Step{
+		{ID: "fetch", Title: "Fetch", Instruction: "Fetch the page", Tools: []string{"web_fetch"}},
+		{ID: "summarize", Title: "Summarize", Instruction: "Summarize it", Model: "small", Needs: []string{"fetch"}},
+	}
+
+	p := PlanFromSteps("Digest", steps)
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if p.Steps[1].Description != "Summarize it" || p.Steps[1].DependsOn[0] != "fetch" {
+		t.Errorf("unexpected plan step: %+v", p.Steps[1])
+	}
+
+	if got := StepsFromPlan(p); !reflect.DeepEqual(got, steps) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, steps)
+	}
+	if _, err := NewDAG(StepsFromPlan(p)); err != nil {
+		t.Errorf("NewDAG: %v", err)
+	}
+}
@@ -1,9 +1,13 @@
 package skills
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/dohr-michael/ozzie/internal/core/brain"
@@ -21,7 +25,8 @@ func NewPoolSkillExecutor(registry *Registry, runCfg RunnerConfig) *PoolSkillExe
 	return &PoolSkillExecutor{registry: registry, runCfg: runCfg}
 }
 
-// RunSkill executes a skill by name with the given variables.
+// RunSkill executes a skill with the given variables. name is a skill
+// reference: a bare name runs the latest version, "name@version" a pinned one.
 func (e *PoolSkillExecutor) RunSkill(ctx context.Context, name string, vars map[string]string) (string, error) {
 	skill := e.registry.Get(name)
 	if skill == nil {
@@ -29,17 +34,28 @@ func (e *PoolSkillExecutor) RunSkill(ctx context.Context, name string, vars map[
 	}
 
 	sessionID := events.SessionIDFromContext(ctx)
+	slog.InfoContext(ctx, "running skill", "skill", skill.Name, "version", skill.Version, "requested", name)
 
 	// Emit skill started
 	skillType := "instruction"
 	if skill.HasWorkflow() {
 		skillType = "workflow"
 	}
-	e.runCfg.EventBus.Publish(events.NewTypedEventWithSession(events.SourceSkill, events.SkillStartedPayload{
+	started := events.NewTypedEventWithSession(events.SourceSkill, events.SkillStartedPayload{
 		SkillName: skill.Name,
+		Version:   skill.Version,
 		Type:      skillType,
 		Vars:      vars,
-	}, sessionID))
+	}, sessionID)
+	e.runCfg.EventBus.Publish(started)
+
+	// The started event ID doubles as the skill run ID for token accounting
+	runID := started.ID
+	ctx = events.ContextWithSkillRunID(ctx, runID)
+	tokens := events.TrackTokens(e.runCfg.EventBus, func(p events.LLMCallPayload) bool {
+		return p.SkillRunID == runID
+	})
+	defer tokens.Stop()
 
 	start := time.Now()
 
@@ -52,10 +68,17 @@ func (e *PoolSkillExecutor) RunSkill(ctx context.Context, name string, vars map[
 	}
 
 	// Emit skill completed
+	tokensIn, tokensOut := tokens.Usage()
 	payload := events.SkillCompletedPayload{
-		SkillName: skill.Name,
-		Output:    output,
-		Duration:  time.Since(start),
+		SkillName:    skill.Name,
+		Version:      skill.Version,
+		Output:       output,
+		Duration:     time.Since(start),
+		TokensInput:  tokensIn,
+		TokensOutput: tokensOut,
+	}
+	if chain := vars[events.SkillChainVar]; chain != "" {
+		payload.Chain = strings.Split(chain, ",")
 	}
 	if err != nil {
 		payload.Error = err.Error()
@@ -65,6 +88,48 @@ func (e *PoolSkillExecutor) RunSkill(ctx context.Context, name string, vars map[
 	return output, err
 }
 
+// PlanSkill describes the steps a skill would run with the given variables,
+// without running them (simulated tasks).
+func (e *PoolSkillExecutor) PlanSkill(name string, vars map[string]string) (string, error) {
+	skill := e.registry.Get(name)
+	if skill == nil {
+		return "", fmt.Errorf("skill not found: %s", name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Skill %s would run", brain.SkillRef(skill.Name, skill.Version))
+	if len(vars) > 0 {
+		b.WriteString(" with:\n")
+		for _, k := range slices.Sorted(maps.Keys(vars)) {
+			fmt.Fprintf(&b, "- %s: %s\n", k, vars[k])
+		}
+	} else {
+		b.WriteString(".\n")
+	}
+
+	if !skill.HasWorkflow() {
+		fmt.Fprintf(&b, "\nA single agent following the skill instructions, with tools: %s\n", toolList(skill.AllowedTools))
+		return b.String(), nil
+	}
+	b.WriteString("\nWorkflow steps:\n")
+	for i, step := range skill.Workflow.Steps {
+		fmt.Fprintf(&b, "%d. %s", i+1, cmp.Or(step.Title, step.ID))
+		if len(step.Needs) > 0 {
+			fmt.Fprintf(&b, " (after %s)", strings.Join(step.Needs, ", "))
+		}
+		fmt.Fprintf(&b, " — tools: %s\n", toolList(step.Tools))
+	}
+	return b.String(), nil
+}
+
+// toolList formats tool names for a skill plan.
+func toolList(tools []string) string {
+	if len(tools) == 0 {
+		return "none"
+	}
+	return strings.Join(tools, ", ")
+}
+
 // RunWorkflow executes a named skill's workflow (implements WorkflowExecutor interface for plugins).
 func (e *PoolSkillExecutor) RunWorkflow(ctx context.Context, skillName string, vars map[string]string) (string, error) {
 	skill := e.registry.Get(skillName)
@@ -5,23 +5,44 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 )
 
-// Registry manages loaded skill definitions.
+// latestVersion is the version alias resolving to the most recent version.
+const latestVersion = "latest"
+
+// Registry manages loaded skill definitions. Several versions of a skill can
+// be registered; a skill is referenced by name (latest version) or pinned
+// with "name@version" (see brain.SkillRef).
 type Registry struct {
-	skills map[string]*SkillMD
+	skills   map[string]*SkillMD   // name → latest version
+	versions map[string][]*SkillMD // name → every version, oldest first
 }
 
 // NewRegistry creates a new skill registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		skills: make(map[string]*SkillMD),
+		skills:   make(map[string]*SkillMD),
+		versions: make(map[string][]*SkillMD),
 	}
 }
 
 // LoadDir scans a directory for subdirectories containing SKILL.md files.
 func (r *Registry) LoadDir(dir string) error {
+	return r.loadDir(dir, "")
+}
+
+// LoadRemoteDir loads the cached copy of a remote library (see SyncRemote),
+// marking its skills with the library name. Local skills take precedence
+// over remote ones with the same name, whatever their versions.
+func (r *Registry) LoadRemoteDir(dir, source string) error {
+	return r.loadDir(dir, source)
+}
+
+func (r *Registry) loadDir(dir, source string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -47,6 +68,7 @@ func (r *Registry) LoadDir(dir string) error {
 			slog.Warn("failed to load skill", "dir", subDir, "error", err)
 			continue
 		}
+		skill.Source = source
 
 		if err := r.Register(skill); err != nil {
 			slog.Warn("failed to register skill", "name", skill.Name, "error", err)
@@ -57,21 +79,52 @@ func (r *Registry) LoadDir(dir string) error {
 	return nil
 }
 
-// Register adds a skill to the registry.
+// Register adds a skill version to the registry. A name defined locally is
+// never resolved to a remote skill: remote versions of it are refused, and
+// registering a local skill drops the remote versions already registered.
 func (r *Registry) Register(skill *SkillMD) error {
-	if _, exists := r.skills[skill.Name]; exists {
-		return fmt.Errorf("skill %q already registered", skill.Name)
+	versions := r.versions[skill.Name]
+	if skill.IsRemote() && slices.ContainsFunc(versions, func(s *SkillMD) bool { return !s.IsRemote() }) {
+		return fmt.Errorf("skill %q is defined locally, remote %s ignored", skill.Name, brain.SkillRef(skill.Name, skill.Version))
+	}
+	if !skill.IsRemote() {
+		versions = slices.DeleteFunc(slices.Clone(versions), (*SkillMD).IsRemote)
+	}
+	if slices.ContainsFunc(versions, func(s *SkillMD) bool { return compareVersions(s.Version, skill.Version) == 0 }) {
+		return fmt.Errorf("skill %q already registered", brain.SkillRef(skill.Name, skill.Version))
 	}
-	r.skills[skill.Name] = skill
+	versions = append(versions, skill)
+	slices.SortStableFunc(versions, func(a, b *SkillMD) int { return compareVersions(a.Version, b.Version) })
+	r.versions[skill.Name] = versions
+	r.skills[skill.Name] = versions[len(versions)-1]
 	return nil
 }
 
-// Get returns the skill with the given name, or nil.
-func (r *Registry) Get(name string) *SkillMD {
-	return r.skills[name]
+// Get returns the skill matching a reference: a name resolves to the latest
+// version, "name@version" to that version ("name@latest" to the latest).
+// Returns nil if there is no match.
+func (r *Registry) Get(ref string) *SkillMD {
+	name, version := brain.ParseSkillRef(ref)
+	if version == "" || version == latestVersion {
+		return r.skills[name]
+	}
+	if !versionPattern.MatchString(version) {
+		return nil
+	}
+	for _, s := range r.versions[name] {
+		if compareVersions(s.Version, version) == 0 {
+			return s
+		}
+	}
+	return nil
+}
+
+// Versions returns every registered version of a skill, oldest first.
+func (r *Registry) Versions(name string) []*SkillMD {
+	return slices.Clone(r.versions[name])
 }
 
-// All returns all registered skills sorted by name.
+// All returns the latest version of every registered skill, sorted by name.
 func (r *Registry) All() []*SkillMD {
 	result := make([]*SkillMD, 0, len(r.skills))
 	for _, s := range r.skills {
@@ -94,10 +147,14 @@ func (r *Registry) Names() []string {
 }
 
 // Catalog returns a map of skill name → description for progressive disclosure.
+// Descriptions of remote skills are prefixed with their library.
 func (r *Registry) Catalog() map[string]string {
 	result := make(map[string]string, len(r.skills))
 	for name, s := range r.skills {
 		result[name] = s.Description
+		if s.IsRemote() {
+			result[name] = "[remote:" + s.Source + "] " + s.Description
+		}
 	}
 	return result
 }
@@ -236,3 +236,57 @@ func TestRegistry_LoadDir_MissingDir(t *testing.T) {
 		t.Errorf("expected nil for missing dir, got: %v", err)
 	}
 }
+
+func TestRegistry_Versions(t *testing.T) {
+	r := NewRegistry()
+	for _, v := range []string{"1.9", "1.10.0", "1.2"} {
+		if err := r.Register(&SkillMD{Name: "deploy", Version: v, Description: "Deploy", Body: "v" + v}); err != nil {
+			t.Fatalf("register %s: %v", v, err)
+		}
+	}
+	if err := r.Register(&SkillMD{Name: "deploy", Version: "1.10", Description: "Deploy", Body: "dup"}); err == nil {
+		t.Error("expected error for an already registered version")
+	}
+
+	tests := []struct {
+		ref  string
+		want string // body, "" = not found
+	}{
+		{"deploy", "v1.10.0"},
+		{"deploy@latest", "v1.10.0"},
+		{"deploy@1.9", "v1.9"},
+		{"deploy@1.2.0", "v1.2"},
+		{"deploy@2", ""},
+		{"deploy@abc", ""},
+	}
+	for _, tt := range tests {
+		got := r.Get(tt.ref)
+		switch {
+		case tt.want == "" && got != nil:
+			t.Errorf("Get(%q) = %s, want nil", tt.ref, got.Body)
+		case tt.want != "" && (got == nil || got.Body != tt.want):
+			t.Errorf("Get(%q) = %+v, want %s", tt.ref, got, tt.want)
+		}
+	}
+
+	if all := r.All(); len(all) != 1 || all[0].Version != "1.10.0" {
+		t.Errorf("All should list the latest version only, got %+v", all)
+	}
+	if vs := r.Versions("deploy"); len(vs) != 3 || vs[0].Version != "1.2" {
+		t.Errorf("Versions should be sorted oldest first, got %d versions", len(vs))
+	}
+}
+
+func TestParseSkillMD_NumericVersion(t *testing.T) {
+	skill, err := ParseSkillMD([]byte("---\nname: deploy\nversion: 1.2\ndescription: Deploy\n---\nDo it.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skill.Version != "1.2" {
+		t.Errorf("version = %q, want 1.2", skill.Version)
+	}
+	skill.Version = "1.2-beta"
+	if err := skill.Validate(); err == nil {
+		t.Error("expected invalid version error")
+	}
+}
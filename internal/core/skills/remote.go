@@ -0,0 +1,177 @@
+package skills
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxRemoteFileSize bounds a single file downloaded from a remote library.
+const maxRemoteFileSize = 4 << 20
+
+// RemoteSource is a shared skill library served over HTTP. Its index lists
+// the skills and the SHA-256 checksum of each of their files.
+type RemoteSource struct {
+	Name        string // identifies the library (cache directory, "remote:<name>" marking)
+	URL         string // index URL
+	IndexSHA256 string // expected checksum of the index; empty = not pinned
+}
+
+// RemoteIndex is the JSON document served at a RemoteSource URL:
+//
+//	{"skills": [{"name": "deploy", "files": [
+//	  {"path": "SKILL.md", "url": "deploy/SKILL.md", "sha256": "9f86d0..."}]}]}
+//
+// File URLs are resolved against the index URL.
+type RemoteIndex struct {
+	Skills []RemoteSkill `json:"skills"`
+}
+
+// RemoteSkill is a skill of a remote library.
+type RemoteSkill struct {
+	Name  string       `json:"name"`
+	Files []RemoteFile `json:"files"`
+}
+
+// RemoteFile is a file of a remote skill, relative to the skill directory.
+type RemoteFile struct {
+	Path   string `json:"path"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// SyncRemote fetches a remote library into cacheDir/<source name>, replacing
+// the cached copy. A skill whose files cannot be fetched or fail checksum
+// verification keeps its previously cached copy, if any. When the index
+// itself cannot be fetched the cache is left untouched and an error returned:
+// LoadRemoteDir then serves the cached skills. So does an index that does
+// not match the pinned IndexSHA256.
+func SyncRemote(ctx context.Context, client *http.Client, src RemoteSource, cacheDir string) error {
+	base, err := url.Parse(src.URL)
+	if err != nil {
+		return fmt.Errorf("remote skills %s: parse url: %w", src.Name, err)
+	}
+	data, err := fetchRemote(ctx, client, src.URL)
+	if err != nil {
+		return fmt.Errorf("remote skills %s: fetch index: %w", src.Name, err)
+	}
+	if src.IndexSHA256 != "" {
+		if err := verifySHA256(data, src.IndexSHA256); err != nil {
+			return fmt.Errorf("remote skills %s: index: %w", src.Name, err)
+		}
+	}
+	var index RemoteIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("remote skills %s: parse index: %w", src.Name, err)
+	}
+
+	dir := filepath.Join(cacheDir, src.Name)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return fmt.Errorf("remote skills %s: create cache: %w", src.Name, err)
+	}
+	tmp, err := os.MkdirTemp(cacheDir, "."+src.Name+"-")
+	if err != nil {
+		return fmt.Errorf("remote skills %s: create cache: %w", src.Name, err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, sk := range index.Skills {
+		if !isPlainName(sk.Name) {
+			slog.Warn("remote skills: invalid skill name", "source", src.Name, "name", sk.Name)
+			continue
+		}
+		if err := fetchRemoteSkill(ctx, client, base, sk, filepath.Join(tmp, sk.Name)); err != nil {
+			slog.Warn("remote skills: fetch skill", "source", src.Name, "name", sk.Name, "error", err)
+			// Keep the previously cached copy rather than losing the skill.
+			_ = os.RemoveAll(filepath.Join(tmp, sk.Name))
+			_ = os.Rename(filepath.Join(dir, sk.Name), filepath.Join(tmp, sk.Name))
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remote skills %s: replace cache: %w", src.Name, err)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return fmt.Errorf("remote skills %s: replace cache: %w", src.Name, err)
+	}
+	return nil
+}
+
+// fetchRemoteSkill downloads and verifies every file of a skill into dir.
+func fetchRemoteSkill(ctx context.Context, client *http.Client, base *url.URL, sk RemoteSkill, dir string) error {
+	for _, f := range sk.Files {
+		if f.Path == "" || !filepath.IsLocal(f.Path) {
+			return fmt.Errorf("invalid file path %q", f.Path)
+		}
+		if f.SHA256 == "" {
+			return fmt.Errorf("%s: missing checksum", f.Path)
+		}
+		ref, err := url.Parse(f.URL)
+		if err != nil {
+			return fmt.Errorf("%s: parse url: %w", f.Path, err)
+		}
+		data, err := fetchRemote(ctx, client, base.ResolveReference(ref).String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+		if err := verifySHA256(data, f.SHA256); err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+
+		path := filepath.Join(dir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySHA256 checks data against a hex-encoded SHA-256 checksum.
+func verifySHA256(data []byte, want string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch (got %s, want %s)", got, want)
+	}
+	return nil
+}
+
+// fetchRemote GETs a URL, bounded by maxRemoteFileSize.
+func fetchRemote(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteFileSize {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", rawURL, maxRemoteFileSize)
+	}
+	return data, nil
+}
+
+// isPlainName reports whether name can be used as a single directory name.
+func isPlainName(name string) bool {
+	return name != "" && filepath.IsLocal(name) && !strings.ContainsAny(name, `/\`)
+}
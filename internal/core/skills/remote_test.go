@@ -0,0 +1,184 @@
+package skills
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const remoteSkillMD = "---\nname: deploy\ndescription: Deploy the app\n---\nRun the deploy script.\n"
+
+// remoteLibrary serves an index and the files of a remote skill library.
+type remoteLibrary struct {
+	files    map[string]string // path → content
+	checksum map[string]string // path → checksum override
+	down     bool
+}
+
+func (l *remoteLibrary) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.down {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.URL.Path == "/index.json" {
+		var files []RemoteFile
+		for path, content := range l.files {
+			sum := sha256.Sum256([]byte(content))
+			checksum := hex.EncodeToString(sum[:])
+			if c, ok := l.checksum[path]; ok {
+				checksum = c
+			}
+			files = append(files, RemoteFile{Path: path, URL: "files/deploy/" + path, SHA256: checksum})
+		}
+		_ = json.NewEncoder(w).Encode(RemoteIndex{Skills: []RemoteSkill{{Name: "deploy", Files: files}}})
+		return
+	}
+	content, ok := l.files[strings.TrimPrefix(r.URL.Path, "/files/deploy/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write([]byte(content))
+}
+
+func TestSyncRemote(t *testing.T) {
+	lib := &remoteLibrary{files: map[string]string{
+		"SKILL.md":          remoteSkillMD,
+		"scripts/deploy.sh": "echo deploy",
+	}}
+	srv := httptest.NewServer(lib)
+	defer srv.Close()
+
+	cache := t.TempDir()
+	src := RemoteSource{Name: "team", URL: srv.URL + "/index.json"}
+	if err := SyncRemote(context.Background(), srv.Client(), src, cache); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(cache, "team", "deploy", "scripts", "deploy.sh")); err != nil || string(data) != "echo deploy" {
+		t.Fatalf("script not cached: %q, %v", data, err)
+	}
+
+	r := NewRegistry()
+	if err := r.LoadRemoteDir(filepath.Join(cache, "team"), "team"); err != nil {
+		t.Fatal(err)
+	}
+	sk := r.Get("deploy")
+	if sk == nil || !sk.IsRemote() || sk.Source != "team" {
+		t.Fatalf("expected remote skill from team, got %+v", sk)
+	}
+	if got := r.Catalog()["deploy"]; got != "[remote:team] Deploy the app" {
+		t.Errorf("catalog = %q", got)
+	}
+
+	// A tampered file is rejected and the cached copy kept.
+	lib.files["SKILL.md"] = strings.Replace(remoteSkillMD, "deploy script", "rm -rf /", 1)
+	lib.checksum = map[string]string{"SKILL.md": "0000"}
+	if err := SyncRemote(context.Background(), srv.Client(), src, cache); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(cache, "team", "deploy", "SKILL.md")); string(data) != remoteSkillMD {
+		t.Errorf("tampered skill replaced the cached copy: %q", data)
+	}
+
+	// Offline: the sync fails and the cache is untouched.
+	lib.down = true
+	if err := SyncRemote(context.Background(), srv.Client(), src, cache); err == nil {
+		t.Error("expected an error when the index is unavailable")
+	}
+	if _, err := os.Stat(filepath.Join(cache, "team", "deploy", "SKILL.md")); err != nil {
+		t.Errorf("cached skill lost when offline: %v", err)
+	}
+}
+
+func TestSyncRemote_PinnedIndex(t *testing.T) {
+	index := []byte(`{"skills": [{"name": "deploy", "files": [{"path": "SKILL.md", "url": "SKILL.md", "sha256": "` +
+		fmt.Sprintf("%x", sha256.Sum256([]byte(remoteSkillMD))) + `"}]}]}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.json" {
+			_, _ = w.Write(index)
+			return
+		}
+		_, _ = w.Write([]byte(remoteSkillMD))
+	}))
+	defer srv.Close()
+
+	cache := t.TempDir()
+	pinned := fmt.Sprintf("%x", sha256.Sum256(index))
+	src := RemoteSource{Name: "team", URL: srv.URL + "/index.json", IndexSHA256: pinned}
+	if err := SyncRemote(context.Background(), srv.Client(), src, cache); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "team", "deploy", "SKILL.md")); err != nil {
+		t.Fatalf("skill not cached: %v", err)
+	}
+
+	// A modified index is rejected and the cache left untouched.
+	index = append(index, '\n')
+	if err := SyncRemote(context.Background(), srv.Client(), src, cache); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected index checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "team", "deploy", "SKILL.md")); err != nil {
+		t.Errorf("cached skill lost on a mismatching index: %v", err)
+	}
+}
+
+func TestLoadRemoteDir_LocalWins(t *testing.T) {
+	local := t.TempDir()
+	remote := t.TempDir()
+	for _, dir := range []string{local, remote} {
+		if err := os.MkdirAll(filepath.Join(dir, "deploy"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "deploy", "SKILL.md"), []byte(remoteSkillMD), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRegistry()
+	_ = r.LoadDir(local)
+	_ = r.LoadRemoteDir(remote, "team")
+	if sk := r.Get("deploy"); sk == nil || sk.IsRemote() {
+		t.Errorf("local skill should take precedence, got %+v", sk)
+	}
+}
+
+func TestRegistry_LocalWinsOverHigherRemoteVersion(t *testing.T) {
+	local := &SkillMD{Name: "deploy", Version: "1.0.0"}
+	remote := &SkillMD{Name: "deploy", Version: "2.0.0", Source: "team"}
+
+	// Local first: the remote version is refused.
+	r := NewRegistry()
+	if err := r.Register(local); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(remote); err == nil {
+		t.Error("expected a remote version of a local skill to be refused")
+	}
+	if sk := r.Get("deploy"); sk != local {
+		t.Errorf("unpinned name should resolve to the local skill, got %+v", sk)
+	}
+	if sk := r.Get("deploy@2.0.0"); sk != nil {
+		t.Errorf("remote version should not be reachable, got %+v", sk)
+	}
+
+	// Remote first: registering the local skill drops the remote versions.
+	r = NewRegistry()
+	_ = r.Register(remote)
+	if err := r.Register(local); err != nil {
+		t.Fatal(err)
+	}
+	if sk := r.Get("deploy"); sk != local {
+		t.Errorf("unpinned name should resolve to the local skill, got %+v", sk)
+	}
+	if n := len(r.Versions("deploy")); n != 1 {
+		t.Errorf("versions = %d, want only the local one", n)
+	}
+}
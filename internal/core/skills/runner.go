@@ -2,6 +2,7 @@ package skills
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -18,6 +19,10 @@ type RunnerConfig struct {
 	ToolLookup    brain.ToolLookup
 	EventBus      events.EventBus
 	Verifier      *Verifier
+
+	// Var sources (nil = unavailable: such vars resolve to empty)
+	Memory       brain.MemoryRetriever // memory_query sources
+	SessionField SessionFieldFunc      // session_field sources
 }
 
 // WorkflowRunner executes a workflow skill by running its DAG of steps.
@@ -39,6 +44,12 @@ func NewWorkflowRunnerFromDef(skill *SkillMD, cfg RunnerConfig) (*WorkflowRunner
 	steps := make([]Step, len(skill.Workflow.Steps))
 	for i, sd := range skill.Workflow.Steps {
 		steps[i] = sd.ToStep()
+		if steps[i].Timeout == 0 {
+			steps[i].Timeout = skill.Workflow.Timeout
+		}
+		if steps[i].OnFailure == "" {
+			steps[i].OnFailure = skill.Workflow.OnFailure
+		}
 	}
 
 	dag, err := NewDAG(steps)
@@ -58,6 +69,9 @@ func NewWorkflowRunnerFromDef(skill *SkillMD, cfg RunnerConfig) (*WorkflowRunner
 // Run executes the workflow, running steps in DAG order with parallel execution
 // where dependencies allow. Returns the output of the last step in topological order.
 func (wr *WorkflowRunner) Run(ctx context.Context, vars map[string]string) (string, error) {
+	if err := wr.resolveVarSources(ctx, vars); err != nil {
+		return "", err
+	}
 	if err := wr.validateVars(vars); err != nil {
 		return "", err
 	}
@@ -165,6 +179,49 @@ func (wr *WorkflowRunner) runStep(ctx context.Context, stepID string, vars map[s
 
 	start := time.Now()
 
+	// Apply the failure policy: retry once, or go on without the step
+	maxAttempts := 1
+	if step.OnFailure == FailureRetry {
+		maxAttempts = 2
+	}
+	var output string
+	var err error
+	attempt := 0
+	for attempt < maxAttempts {
+		attempt++
+		output, err = wr.execStep(ctx, step, modelName, vars, prevResults)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		if attempt < maxAttempts {
+			slog.WarnContext(ctx, "skill step failed, retrying", "skill", wr.skillName, "step", stepID, "error", err)
+		}
+	}
+
+	continued := err != nil && step.OnFailure == FailureContinue && ctx.Err() == nil
+	wr.emitStepCompleted(sessionID, events.SkillStepCompletedPayload{
+		StepID:    stepID,
+		StepTitle: step.Title,
+		Output:    output,
+		Attempts:  attempt,
+		Continued: continued,
+	}, err, start)
+
+	if continued {
+		slog.WarnContext(ctx, "skill step failed, continuing", "skill", wr.skillName, "step", stepID, "error", err)
+		return fmt.Sprintf("[step failed: %v]", err), nil
+	}
+	return output, err
+}
+
+// execStep runs one attempt of a step, bounded by the step's timeout.
+func (wr *WorkflowRunner) execStep(ctx context.Context, step *Step, modelName string, vars map[string]string, prevResults map[string]string) (string, error) {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
 	// Resolve tools for this step
 	stepTools := wr.resolveTools(step.Tools)
 
@@ -174,8 +231,7 @@ func (wr *WorkflowRunner) runStep(ctx context.Context, stepID string, vars map[s
 	// Create ephemeral agent via RunnerFactory
 	runner, err := wr.cfg.RunnerFactory.CreateRunner(ctx, modelName, instruction, stepTools)
 	if err != nil {
-		wr.emitStepCompleted(sessionID, stepID, step.Title, "", err, start)
-		return "", fmt.Errorf("create agent for step %q: %w", stepID, err)
+		return "", fmt.Errorf("create agent for step %q: %w", step.ID, err)
 	}
 
 	// Run the agent
@@ -190,7 +246,9 @@ func (wr *WorkflowRunner) runStep(ctx context.Context, stepID string, vars map[s
 		output, err = wr.verifyAndRetry(ctx, step, output, vars, prevResults)
 	}
 
-	wr.emitStepCompleted(sessionID, stepID, step.Title, output, err, start)
+	if err != nil && step.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s: %w", step.Timeout, err)
+	}
 	return output, err
 }
 
@@ -239,7 +297,7 @@ func (wr *WorkflowRunner) verifyAndRetry(ctx context.Context, step *Step, output
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		result, err := wr.cfg.Verifier.Verify(ctx, step.Acceptance, step.Title, currentOutput)
 		if err != nil {
-			slog.Warn("verification failed, treating as pass", "step", step.ID, "error", err)
+			slog.WarnContext(ctx, "verification failed, treating as pass", "step", step.ID, "error", err)
 			return currentOutput, nil
 		}
 
@@ -254,17 +312,17 @@ func (wr *WorkflowRunner) verifyAndRetry(ctx context.Context, step *Step, output
 		}, sessionID))
 
 		if result.Pass {
-			slog.Info("step verification passed", "step", step.ID, "score", result.Score, "attempt", attempt)
+			slog.InfoContext(ctx, "step verification passed", "step", step.ID, "score", result.Score, "attempt", attempt)
 			return currentOutput, nil
 		}
 
-		slog.Info("step verification failed, retrying", "step", step.ID, "score", result.Score, "attempt", attempt, "issues", result.Issues)
+		slog.InfoContext(ctx, "step verification failed, retrying", "step", step.ID, "score", result.Score, "attempt", attempt, "issues", result.Issues)
 
 		// Retry with feedback
 		if attempt < maxAttempts {
 			retryOutput, retryErr := wr.retryStep(ctx, step, currentOutput, result.Feedback, vars, prevResults)
 			if retryErr != nil {
-				slog.Warn("retry failed, using last output", "step", step.ID, "error", retryErr)
+				slog.WarnContext(ctx, "retry failed, using last output", "step", step.ID, "error", retryErr)
 				return currentOutput, nil
 			}
 			currentOutput = retryOutput
@@ -272,7 +330,7 @@ func (wr *WorkflowRunner) verifyAndRetry(ctx context.Context, step *Step, output
 	}
 
 	// All attempts exhausted — return last output
-	slog.Warn("max verification attempts reached", "step", step.ID, "max", maxAttempts)
+	slog.WarnContext(ctx, "max verification attempts reached", "step", step.ID, "max", maxAttempts)
 	return currentOutput, nil
 }
 
@@ -313,14 +371,9 @@ func (wr *WorkflowRunner) resolveTools(toolNames []string) []brain.Tool {
 	return tools
 }
 
-func (wr *WorkflowRunner) emitStepCompleted(sessionID, stepID, stepTitle, output string, err error, start time.Time) {
-	payload := events.SkillStepCompletedPayload{
-		SkillName: wr.skillName,
-		StepID:    stepID,
-		StepTitle: stepTitle,
-		Output:    output,
-		Duration:  time.Since(start),
-	}
+func (wr *WorkflowRunner) emitStepCompleted(sessionID string, payload events.SkillStepCompletedPayload, err error, start time.Time) {
+	payload.SkillName = wr.skillName
+	payload.Duration = time.Since(start)
 	if err != nil {
 		payload.Error = err.Error()
 	}
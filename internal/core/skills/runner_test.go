@@ -1,8 +1,15 @@
 package skills
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
 func TestValidateVars_RequiredPresent(t *testing.T) {
@@ -155,3 +162,138 @@ func TestBuildStepInstruction_Full(t *testing.T) {
 			instrIdx, varsIdx, prevIdx, accIdx)
 	}
 }
+
+// scriptedFactory creates runners whose outcome is decided per instruction
+// prefix; run is called with the run count of that prefix (1-based).
+type scriptedFactory struct {
+	mu   sync.Mutex
+	runs map[string]int
+	run  func(ctx context.Context, step string, n int) (string, error)
+}
+
+func (f *scriptedFactory) CreateRunner(_ context.Context, _ string, instruction string, _ []brain.Tool, _ ...brain.RunnerOption) (brain.Runner, error) {
+	step, _, _ := strings.Cut(instruction, ".")
+	return runnerFunc(func(ctx context.Context, _ []brain.Message) (string, error) {
+		f.mu.Lock()
+		if f.runs == nil {
+			f.runs = make(map[string]int)
+		}
+		f.runs[step]++
+		n := f.runs[step]
+		f.mu.Unlock()
+		return f.run(ctx, step, n)
+	}), nil
+}
+
+type runnerFunc func(ctx context.Context, messages []brain.Message) (string, error)
+
+func (r runnerFunc) Run(ctx context.Context, messages []brain.Message) (string, error) {
+	return r(ctx, messages)
+}
+
+func TestWorkflowRunner_FailurePolicy(t *testing.T) {
+	errBoom := errors.New("boom")
+	tests := []struct {
+		name      string
+		policy    FailurePolicy
+		timeout   time.Duration
+		run       func(ctx context.Context, step string, n int) (string, error)
+		wantErr   string
+		wantOut   string
+		wantFetch events.SkillStepCompletedPayload
+	}{
+		{
+			name:   "fail aborts",
+			policy: FailureFail,
+			run: func(_ context.Context, step string, _ int) (string, error) {
+				if step == "fetch" {
+					return "", errBoom
+				}
+				return "done", nil
+			},
+			wantErr:   "boom",
+			wantFetch: events.SkillStepCompletedPayload{Error: "boom", Attempts: 1},
+		},
+		{
+			name:   "continue goes on",
+			policy: FailureContinue,
+			run: func(_ context.Context, step string, _ int) (string, error) {
+				if step == "fetch" {
+					return "", errBoom
+				}
+				return "done", nil
+			},
+			wantOut:   "done",
+			wantFetch: events.SkillStepCompletedPayload{Error: "boom", Attempts: 1, Continued: true},
+		},
+		{
+			name:   "retry succeeds",
+			policy: FailureRetry,
+			run: func(_ context.Context, step string, n int) (string, error) {
+				if step == "fetch" && n == 1 {
+					return "", errBoom
+				}
+				return "done", nil
+			},
+			wantOut:   "done",
+			wantFetch: events.SkillStepCompletedPayload{Output: "done", Attempts: 2},
+		},
+		{
+			name:    "timeout",
+			timeout: 10 * time.Millisecond,
+			run: func(ctx context.Context, step string, _ int) (string, error) {
+				if step == "fetch" {
+					<-ctx.Done()
+					return "", ctx.Err()
+				}
+				return "done", nil
+			},
+			wantErr:   "timed out after 10ms",
+			wantFetch: events.SkillStepCompletedPayload{Error: "timed out after 10ms: context deadline exceeded", Attempts: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := events.NewBus(16)
+			defer bus.Close()
+			stepCh, unsub := bus.SubscribeChan(4, events.EventSkillStepCompleted)
+			defer unsub()
+
+			skill := &SkillMD{Name: "enrich", Workflow: &WorkflowDef{
+				Timeout: tt.timeout,
+				Steps: []StepDef{
+					{ID: "fetch", Instruction: "fetch.", OnFailure: tt.policy},
+					{ID: "write", Instruction: "write.", Needs: []string{"fetch"}},
+				},
+			}}
+			wr, err := NewWorkflowRunnerFromDef(skill, RunnerConfig{
+				RunnerFactory: &scriptedFactory{run: tt.run},
+				EventBus:      bus,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := wr.Run(context.Background(), map[string]string{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil || out != tt.wantOut {
+				t.Fatalf("Run = %q, %v; want %q", out, err, tt.wantOut)
+			}
+
+			select {
+			case e := <-stepCh:
+				p, _ := events.GetSkillStepCompletedPayload(e)
+				if p.StepID != "fetch" || p.Output != tt.wantFetch.Output || p.Error != tt.wantFetch.Error ||
+					p.Attempts != tt.wantFetch.Attempts || p.Continued != tt.wantFetch.Continued {
+					t.Errorf("fetch step completed = %+v, want %+v", p, tt.wantFetch)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("missing skill.step.completed for fetch")
+			}
+		})
+	}
+}
@@ -1,11 +1,21 @@
 package skills
 
-import "fmt"
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches skill versions: dot-separated numbers ("1", "1.2", "1.2.0").
+var versionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
 
 // SkillMD represents a skill loaded from the SKILL.md + optional YAML files format.
 // This follows the agentskills.io specification with hybrid extensions.
 type SkillMD struct {
 	Name          string            `yaml:"name"`
+	Version       string            `yaml:"version,omitempty"`
 	Description   string            `yaml:"description"`
 	License       string            `yaml:"license,omitempty"`
 	Compatibility string            `yaml:"compatibility,omitempty"`
@@ -13,6 +23,7 @@ type SkillMD struct {
 	AllowedTools  []string          `yaml:"allowed-tools,omitempty"`
 	Body          string            `yaml:"-"` // Markdown body (below frontmatter)
 	Dir           string            `yaml:"-"` // Directory containing the skill files
+	Source        string            `yaml:"-"` // Remote library the skill was fetched from ("" = local)
 
 	Workflow *WorkflowDef `yaml:"-"` // Optional: loaded from workflow.yaml
 	Triggers *TriggersDef `yaml:"-"` // Optional: loaded from triggers.yaml
@@ -23,14 +34,25 @@ func (s *SkillMD) HasWorkflow() bool {
 	return s.Workflow != nil && len(s.Workflow.Steps) > 0
 }
 
+// IsRemote returns true if the skill was loaded from a remote library.
+func (s *SkillMD) IsRemote() bool {
+	return s.Source != ""
+}
+
 // Validate checks the skill definition for consistency.
 func (s *SkillMD) Validate() error {
 	if s.Name == "" {
 		return fmt.Errorf("skill name is required")
 	}
+	if strings.Contains(s.Name, "@") {
+		return fmt.Errorf("skill %q: name must not contain @", s.Name)
+	}
 	if s.Description == "" {
 		return fmt.Errorf("skill %q: description is required", s.Name)
 	}
+	if s.Version != "" && !versionPattern.MatchString(s.Version) {
+		return fmt.Errorf("skill %q: invalid version %q (want dot-separated numbers, e.g. 1.2.0)", s.Name, s.Version)
+	}
 	if s.Body == "" && !s.HasWorkflow() {
 		return fmt.Errorf("skill %q: body or workflow is required", s.Name)
 	}
@@ -41,3 +63,26 @@ func (s *SkillMD) Validate() error {
 	}
 	return nil
 }
+
+// compareVersions compares two skill versions numerically, component by
+// component ("1.10" > "1.9", "1.2" == "1.2.0"). An empty version (an
+// unversioned skill) sorts first.
+func compareVersions(a, b string) int {
+	if a == "" || b == "" {
+		return strings.Compare(a, b)
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -6,9 +6,14 @@ type TriggersDef struct {
 	Cron       string        `yaml:"cron,omitempty"`
 	OnEvent    *EventTrigger `yaml:"on_event,omitempty"`
 	Keywords   []string      `yaml:"keywords,omitempty"`
+
+	// OnSkillCompleted names a skill whose successful completion fires this
+	// one, with the upstream output in the trigger_output var.
+	OnSkillCompleted string `yaml:"on_skill_completed,omitempty"`
 }
 
-// HasScheduleTrigger returns true if the triggers include a cron or event trigger.
+// HasScheduleTrigger returns true if the triggers include a cron, event or
+// skill-completion trigger.
 func (td *TriggersDef) HasScheduleTrigger() bool {
-	return td != nil && (td.Cron != "" || td.OnEvent != nil)
+	return td != nil && (td.Cron != "" || td.OnEvent != nil || td.OnSkillCompleted != "")
 }
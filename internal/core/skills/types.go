@@ -1,5 +1,7 @@
 package skills
 
+import "time"
+
 // Step describes a single step in a workflow DAG.
 // Used internally by the DAG engine and WorkflowRunner.
 type Step struct {
@@ -10,6 +12,8 @@ type Step struct {
 	Model       string              `json:"model"`
 	Needs       []string            `json:"needs"`
 	Acceptance  *AcceptanceCriteria `json:"acceptance,omitempty"`
+	Timeout     time.Duration       `json:"timeout,omitempty"`
+	OnFailure   FailurePolicy       `json:"on_failure,omitempty"`
 }
 
 // Var describes a skill input variable.
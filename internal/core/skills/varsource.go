@@ -0,0 +1,109 @@
+package skills
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+// DefaultVarMemoryLimit is the number of memories a memory_query source
+// includes when it sets no limit.
+const DefaultVarMemoryLimit = 5
+
+// VarSource tells the workflow runner where to find a var the caller did not
+// pass. Exactly one of Literal, MemoryQuery and SessionField is set.
+type VarSource struct {
+	Literal      string `yaml:"literal,omitempty"`       // fixed value
+	MemoryQuery  string `yaml:"memory_query,omitempty"`  // memories retrieved for this query, one per line
+	SessionField string `yaml:"session_field,omitempty"` // field of the calling session (see SessionFieldFunc)
+	Limit        int    `yaml:"limit,omitempty"`         // memories to include (memory_query, default DefaultVarMemoryLimit)
+}
+
+// SessionFieldFunc returns a field of a session: title, language, root_dir,
+// summary, or a "metadata.<key>" / "vars.<key>" entry. ok is false when the
+// session or the field does not exist.
+type SessionFieldFunc func(sessionID, field string) (value string, ok bool)
+
+func (s *VarSource) validate() error {
+	if s == nil {
+		return nil
+	}
+	set := 0
+	for _, v := range []string{s.Literal, s.MemoryQuery, s.SessionField} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("source needs exactly one of literal, memory_query, session_field")
+	}
+	if s.Limit < 0 {
+		return fmt.Errorf("source limit must not be negative")
+	}
+	return nil
+}
+
+// resolveVarSources fills the vars the caller did not pass from their
+// declared source. An optional var whose source yields nothing is left to its
+// default; a required one fails the run before any step starts.
+func (wr *WorkflowRunner) resolveVarSources(ctx context.Context, vars map[string]string) error {
+	for name, def := range wr.vars {
+		if def.Source == nil {
+			continue
+		}
+		if _, ok := vars[name]; ok {
+			continue
+		}
+		value, err := wr.resolveVarSource(ctx, def.Source)
+		if err != nil {
+			if def.Required {
+				return fmt.Errorf("skill %q: resolve variable %q: %w", wr.skillName, name, err)
+			}
+			continue
+		}
+		if value == "" {
+			if def.Required && def.Default == "" {
+				return fmt.Errorf("skill %q: required variable %q: source returned nothing", wr.skillName, name)
+			}
+			continue
+		}
+		vars[name] = value
+	}
+	return nil
+}
+
+func (wr *WorkflowRunner) resolveVarSource(ctx context.Context, src *VarSource) (string, error) {
+	switch {
+	case src.Literal != "":
+		return src.Literal, nil
+
+	case src.MemoryQuery != "":
+		if wr.cfg.Memory == nil {
+			return "", fmt.Errorf("memory is not available")
+		}
+		limit := src.Limit
+		if limit == 0 {
+			limit = DefaultVarMemoryLimit
+		}
+		memories, err := wr.cfg.Memory.Retrieve(ctx, src.MemoryQuery, nil, limit)
+		if err != nil {
+			return "", fmt.Errorf("memory query: %w", err)
+		}
+		lines := make([]string, 0, len(memories))
+		for _, m := range memories {
+			lines = append(lines, "- "+m.Content)
+		}
+		return strings.Join(lines, "\n"), nil
+
+	case src.SessionField != "":
+		sessionID := events.SessionIDFromContext(ctx)
+		if wr.cfg.SessionField == nil || sessionID == "" {
+			return "", fmt.Errorf("no session")
+		}
+		value, _ := wr.cfg.SessionField(sessionID, src.SessionField)
+		return value, nil
+	}
+	return "", nil
+}
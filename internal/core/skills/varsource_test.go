@@ -0,0 +1,98 @@
+package skills
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/pkg/memory"
+)
+
+type stubMemories []string
+
+func (s stubMemories) Retrieve(_ context.Context, _ string, _ []string, limit int) ([]memory.RetrievedMemory, error) {
+	var out []memory.RetrievedMemory
+	for _, c := range s[:min(limit, len(s))] {
+		out = append(out, memory.RetrievedMemory{Content: c})
+	}
+	return out, nil
+}
+
+func TestResolveVarSources(t *testing.T) {
+	wr := &WorkflowRunner{
+		skillName: "standup",
+		vars: map[string]VarDef{
+			"team":     {Source: &VarSource{Literal: "core"}},
+			"notes":    {Source: &VarSource{MemoryQuery: "standup notes", Limit: 2}},
+			"project":  {Source: &VarSource{SessionField: "metadata.project"}},
+			"language": {Source: &VarSource{SessionField: "language"}, Default: "en"},
+			"passed":   {Source: &VarSource{Literal: "ignored"}},
+		},
+		cfg: RunnerConfig{
+			Memory: stubMemories{"shipped the parser", "fixed CI", "unrelated"},
+			SessionField: func(sessionID, field string) (string, bool) {
+				if sessionID == "sess_1" && field == "metadata.project" {
+					return "ozzie", true
+				}
+				return "", false
+			},
+		},
+	}
+
+	vars := map[string]string{"passed": "by caller"}
+	ctx := events.ContextWithSessionID(context.Background(), "sess_1")
+	if err := wr.resolveVarSources(ctx, vars); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"team":    "core",
+		"notes":   "- shipped the parser\n- fixed CI",
+		"project": "ozzie",
+		"passed":  "by caller",
+	}
+	if len(vars) != len(want) {
+		t.Errorf("vars = %v, want %v", vars, want)
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("vars[%q] = %q, want %q", k, vars[k], v)
+		}
+	}
+}
+
+func TestResolveVarSources_RequiredMissing(t *testing.T) {
+	wr := &WorkflowRunner{
+		skillName: "standup",
+		vars: map[string]VarDef{
+			"notes": {Required: true, Source: &VarSource{MemoryQuery: "standup notes"}},
+		},
+	}
+
+	err := wr.resolveVarSources(context.Background(), map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "notes") {
+		t.Fatalf("expected error for unresolved required var, got %v", err)
+	}
+}
+
+func TestVarSource_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     *VarSource
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"one kind", &VarSource{MemoryQuery: "q"}, false},
+		{"none", &VarSource{}, true},
+		{"two kinds", &VarSource{Literal: "a", SessionField: "title"}, true},
+		{"negative limit", &VarSource{MemoryQuery: "q", Limit: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.src.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,19 +1,36 @@
 package skills
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// FailurePolicy tells a workflow what to do when one of its steps fails.
+type FailurePolicy string
+
+const (
+	FailureFail     FailurePolicy = "fail"     // abort the workflow (default)
+	FailureContinue FailurePolicy = "continue" // record the failure and go on; dependents see the error as the step's result
+	FailureRetry    FailurePolicy = "retry"    // run the step once more, then abort if it fails again
+)
 
 // WorkflowDef describes a structured DAG workflow loaded from workflow.yaml.
 type WorkflowDef struct {
-	Model string           `yaml:"model,omitempty"`
+	Model string            `yaml:"model,omitempty"`
 	Vars  map[string]VarDef `yaml:"vars,omitempty"`
-	Steps []StepDef        `yaml:"steps"`
+	Steps []StepDef         `yaml:"steps"`
+
+	// Timeout and OnFailure are the defaults of steps that set neither.
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
+	OnFailure FailurePolicy `yaml:"on_failure,omitempty"`
 }
 
 // VarDef describes a workflow input variable.
 type VarDef struct {
-	Description string `yaml:"description"`
-	Required    bool   `yaml:"required"`
-	Default     string `yaml:"default,omitempty"`
+	Description string     `yaml:"description"`
+	Required    bool       `yaml:"required"`
+	Default     string     `yaml:"default,omitempty"`
+	Source      *VarSource `yaml:"source,omitempty"` // resolves the var when the caller does not pass it
 }
 
 // StepDef describes a single step in a workflow.
@@ -25,6 +42,8 @@ type StepDef struct {
 	Model       string         `yaml:"model,omitempty"`
 	Needs       []string       `yaml:"needs,omitempty"`
 	Acceptance  *AcceptanceDef `yaml:"acceptance,omitempty"`
+	Timeout     time.Duration  `yaml:"timeout,omitempty"`    // per-run limit, e.g. "5m" (0 = none)
+	OnFailure   FailurePolicy  `yaml:"on_failure,omitempty"` // fail (default), continue or retry
 }
 
 // AcceptanceDef describes acceptance criteria for a workflow step.
@@ -48,7 +67,7 @@ func (a *AcceptanceDef) ToAcceptanceCriteria() *AcceptanceCriteria {
 
 // ToVar converts a VarDef to the existing Var type.
 func (v VarDef) ToVar() Var {
-	return Var(v)
+	return Var{Description: v.Description, Required: v.Required, Default: v.Default}
 }
 
 // ToStep converts a StepDef to the existing Step type used by the DAG engine.
@@ -61,9 +80,20 @@ func (s StepDef) ToStep() Step {
 		Model:       s.Model,
 		Needs:       s.Needs,
 		Acceptance:  s.Acceptance.ToAcceptanceCriteria(),
+		Timeout:     s.Timeout,
+		OnFailure:   s.OnFailure,
 	}
 }
 
+// validFailurePolicy reports whether p is empty or a known policy.
+func validFailurePolicy(p FailurePolicy) bool {
+	switch p {
+	case "", FailureFail, FailureContinue, FailureRetry:
+		return true
+	}
+	return false
+}
+
 // validateWorkflowDef checks a workflow definition for consistency.
 func validateWorkflowDef(skillName string, w *WorkflowDef) error {
 	if len(w.Steps) == 0 {
@@ -81,6 +111,12 @@ func validateWorkflowDef(skillName string, w *WorkflowDef) error {
 		ids[step.ID] = true
 	}
 
+	for name, v := range w.Vars {
+		if err := v.Source.validate(); err != nil {
+			return fmt.Errorf("skill %q: var %q: %w", skillName, name, err)
+		}
+	}
+
 	for _, step := range w.Steps {
 		for _, need := range step.Needs {
 			if !ids[need] {
@@ -96,6 +132,18 @@ func validateWorkflowDef(skillName string, w *WorkflowDef) error {
 		if step.Instruction == "" {
 			return fmt.Errorf("skill %q: step %q requires an instruction", skillName, step.ID)
 		}
+		if !validFailurePolicy(step.OnFailure) {
+			return fmt.Errorf("skill %q: step %q: unknown on_failure %q", skillName, step.ID, step.OnFailure)
+		}
+		if step.Timeout < 0 {
+			return fmt.Errorf("skill %q: step %q: negative timeout", skillName, step.ID)
+		}
+	}
+	if !validFailurePolicy(w.OnFailure) {
+		return fmt.Errorf("skill %q: unknown on_failure %q", skillName, w.OnFailure)
+	}
+	if w.Timeout < 0 {
+		return fmt.Errorf("skill %q: negative timeout", skillName)
 	}
 
 	return nil
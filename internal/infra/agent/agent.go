@@ -32,7 +32,9 @@ func LoadPersona() string {
 
 // AgentOptions configures optional agent behavior.
 type AgentOptions struct {
-	MaxIterations int // 0 = ADK default
+	MaxIterations int                        // 0 = ADK default
+	Model         model.ToolCallingChatModel // overrides the factory model (nil = default)
+	MaxParallel   int                        // tool calls of one assistant message run at once (0 = unbounded, 1 = sequential)
 }
 
 // NewAgent creates a ChatModelAgent with optional tools, middlewares, and streaming enabled.
@@ -58,6 +60,10 @@ func newAgent(ctx context.Context, chatModel model.ToolCallingChatModel, persona
 		opt = opts[0]
 	}
 
+	if opt.Model != nil {
+		chatModel = opt.Model
+	}
+
 	cfg := &adk.ChatModelAgentConfig{
 		Name:          "ozzie",
 		Description:   "Ozzie — personal AI assistant with the soul of an inventor and explorer",
@@ -69,11 +75,16 @@ func newAgent(ctx context.Context, chatModel model.ToolCallingChatModel, persona
 
 	// Register tools with the agent (enables ReAct loop in ADK)
 	if len(tools) > 0 {
+		if opt.MaxParallel > 1 {
+			tools = boundParallel(tools, opt.MaxParallel)
+		}
 		baseTools := make([]tool.BaseTool, len(tools))
 		for i, t := range tools {
 			baseTools[i] = t
 		}
 		cfg.ToolsConfig.Tools = baseTools
+		// The tool calls of one assistant message run in parallel by default
+		cfg.ToolsConfig.ExecuteSequentially = opt.MaxParallel == 1
 	}
 
 	agent, err := adk.NewChatModelAgent(ctx, cfg)
@@ -8,6 +8,7 @@ import (
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 	ub "github.com/cloudwego/eino/utils/callbacks"
 
@@ -40,6 +41,19 @@ func NewEventBusHandler(bus events.EventBus, source events.EventSource) callback
 	}
 
 	publishTyped := func(ctx context.Context, payload events.EventPayload) {
+		// Correlate LLM calls with the task / skill run that issued them (token accounting)
+		if p, ok := payload.(events.LLMCallPayload); ok {
+			p.TaskID = events.TaskIDFromContext(ctx)
+			p.SkillRunID = events.SkillRunIDFromContext(ctx)
+			payload = p
+		}
+		// Attribute tool calls for the audit log
+		if p, ok := payload.(events.ToolCallPayload); ok {
+			p.CallID = compose.GetToolCallID(ctx)
+			p.TaskID = events.TaskIDFromContext(ctx)
+			p.Autonomous = events.IsAutonomousContext(ctx)
+			payload = p
+		}
 		if sid := events.SessionIDFromContext(ctx); sid != "" {
 			bus.Publish(events.NewTypedEventWithSession(source, payload, sid))
 		} else {
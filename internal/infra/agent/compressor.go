@@ -8,6 +8,7 @@ import (
 
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/prompt"
 	"github.com/dohr-michael/ozzie/internal/infra/sessions"
 )
@@ -139,6 +140,49 @@ func (c *Compressor) Compress(
 	return result, nil
 }
 
+// CompactText condenses text to about maxChars with an LLM summary, for
+// context that overflowed the window outside a conversation (task
+// instructions). Input larger than the compression threshold is truncated
+// first so the summarization call itself fits.
+func (c *Compressor) CompactText(ctx context.Context, text string, maxChars int, summarize SummarizeFunc) (string, error) {
+	if len(text) <= maxChars {
+		return text, nil
+	}
+	if c.contextWindow > 0 {
+		if limit := int(float64(c.contextWindow)*c.threshold) * c.charsPerToken; len(text) > limit {
+			text = text[:limit]
+		}
+	}
+
+	// ~6 chars per word
+	summary, err := summarize(ctx, fmt.Sprintf(prompt.SummarizeTaskContext, max(maxChars/6, 50), text))
+	if err != nil {
+		return "", err
+	}
+	if len(summary) > maxChars {
+		summary = summary[:maxChars]
+	}
+	return summary, nil
+}
+
+// ContextCompactor adapts a Compressor to brain.ContextCompactor for the task runner.
+type ContextCompactor struct {
+	compressor *Compressor
+	summarize  SummarizeFunc
+}
+
+// NewContextCompactor creates a ContextCompactor summarizing with fn.
+func NewContextCompactor(compressor *Compressor, fn SummarizeFunc) *ContextCompactor {
+	return &ContextCompactor{compressor: compressor, summarize: fn}
+}
+
+// CompactContext implements brain.ContextCompactor.
+func (c *ContextCompactor) CompactContext(ctx context.Context, text string, maxChars int) (string, error) {
+	return c.compressor.CompactText(ctx, text, maxChars, c.summarize)
+}
+
+var _ brain.ContextCompactor = (*ContextCompactor)(nil)
+
 // applyExistingSummary injects the session's existing summary (if any) without
 // calling the LLM. Returns unmodified messages if no summary exists.
 func (c *Compressor) applyExistingSummary(session *sessions.Session, messages []*schema.Message) *CompressResult {
@@ -308,3 +308,37 @@ func TestCompress_PreserveMinOne(t *testing.T) {
 		t.Error("should always preserve at least 1 message")
 	}
 }
+
+func TestCompactText(t *testing.T) {
+	c := NewCompressor(CompressorConfig{ContextWindow: 1000})
+	var prompt string
+	summarize := func(_ context.Context, p string) (string, error) {
+		prompt = p
+		return strings.Repeat("s", 500), nil
+	}
+
+	// Short text is returned as is, without a summarization call.
+	got, err := c.CompactText(context.Background(), "short", 100, summarize)
+	if err != nil || got != "short" || prompt != "" {
+		t.Fatalf("short text: got %q, %v (prompt %q)", got, err, prompt)
+	}
+
+	// Input beyond the threshold (800 tokens ≈ 3200 chars) is truncated before summarizing.
+	got, err = c.CompactText(context.Background(), strings.Repeat("x", 10000), 200, summarize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, strings.Repeat("x", 3200)) || strings.Contains(prompt, strings.Repeat("x", 3201)) {
+		t.Error("input should be truncated to 3200 chars before summarizing")
+	}
+	if len(got) != 200 {
+		t.Errorf("summary length = %d, want capped at 200", len(got))
+	}
+
+	_, err = c.CompactText(context.Background(), strings.Repeat("x", 300), 200, func(context.Context, string) (string, error) {
+		return "", fmt.Errorf("llm down")
+	})
+	if err == nil {
+		t.Error("expected the summarization error")
+	}
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 	"github.com/google/uuid"
@@ -36,21 +38,34 @@ type EventRunner struct {
 	store      sessions.Store
 	compressor *Compressor
 	layered    *layeredctx.Manager
+	originals  *originalsCache // original content of redacted messages
 
 	pool            brain.CapacityPool // actor pool for capacity management (optional)
 	defaultProvider string             // default provider name for AcquireInteractive
+	sessionModel    SessionModelFunc   // resolves per-session model overrides (optional)
 	processTimeout  time.Duration
 	maxIterations   int
+	confine         bool // jail every session to its RootDir (config sandbox.confine_interactive)
+	toolIdleTurns   int  // deactivate activated tools unused for this many turns (0 = never)
+	maxParallel     int  // tool calls of one assistant message run at once (0 = unbounded)
+
+	streamFlushInterval time.Duration // coalescing window of stream deltas (0 = emit every chunk)
+	streamFlushRunes    int           // flush buffered deltas at this size (0 = on the timer only)
 
 	mu           sync.Mutex
-	running      map[string]bool          // per-session lock
-	streamSeqIdx map[string]*atomic.Int32 // per-session stream sequence counter
+	queues       map[string][]string                // per-session messages waiting for the running turn; key present = turn running
+	cancels      map[string]context.CancelCauseFunc // per-session cancel of the running turn (interrupt)
+	streamSeqIdx map[string]*atomic.Int32           // per-session stream sequence counter
 
 	ctx         context.Context
 	cancel      context.CancelFunc
 	unsubscribe func()
 }
 
+// SessionModelFunc returns the chat model for a provider selected on a session
+// (sessions.Session.Model), overriding the factory's default model.
+type SessionModelFunc func(provider string) model.ToolCallingChatModel
+
 // EventRunnerConfig contains configuration for the EventRunner.
 type EventRunnerConfig struct {
 	Factory         *AgentFactory
@@ -60,11 +75,23 @@ type EventRunnerConfig struct {
 	Store           sessions.Store
 	Pool            brain.CapacityPool  // actor pool for capacity management (optional)
 	DefaultProvider string              // default provider name for AcquireInteractive
+	SessionModel    SessionModelFunc    // per-session model override resolver (optional)
 	ContextWindow   int                 // total context window in tokens (for compression)
 	Tier            brain.ModelTier     // model tier for adaptive compression
 	Layered         *layeredctx.Manager // layered context manager (optional)
 	ProcessTimeout  time.Duration       // max time for a single processMessage call (default 5m)
 	MaxIterations   int                 // max ReAct iterations for main agent (default 25)
+	Confine         bool                // confine every session to its RootDir (otherwise per-session opt-in)
+	ToolIdleTurns   int                 // deactivate activated tools unused for N turns (0 = never)
+	MaxParallel     int                 // tool calls of one assistant message run at once (0 = unbounded, 1 = sequential)
+	// StreamFlushInterval batches the text deltas of a stream: accumulated
+	// chunks are emitted at most this often (0 = one event per model chunk).
+	StreamFlushInterval time.Duration
+	// StreamFlushRunes emits the batch early once it holds this many runes (0 = no limit).
+	StreamFlushRunes int
+	// Redactor is the store's message redactor (optional): the originals of
+	// redacted messages are kept in memory for the context of later turns.
+	Redactor *sessions.Redactor
 }
 
 // NewEventRunner creates a new event-driven runner.
@@ -95,18 +122,28 @@ func NewEventRunner(cfg EventRunnerConfig) *EventRunner {
 		store:           cfg.Store,
 		compressor:      NewCompressor(compCfg),
 		layered:         cfg.Layered,
+		originals:       newOriginalsCache(cfg.Redactor),
 		pool:            cfg.Pool,
 		defaultProvider: cfg.DefaultProvider,
+		sessionModel:    cfg.SessionModel,
 		processTimeout:  processTimeout,
 		maxIterations:   maxIter,
-		running:         make(map[string]bool),
+		confine:         cfg.Confine,
+		toolIdleTurns:   cfg.ToolIdleTurns,
+		maxParallel:     cfg.MaxParallel,
+		queues:          make(map[string][]string),
+		cancels:         make(map[string]context.CancelCauseFunc),
 		streamSeqIdx:    make(map[string]*atomic.Int32),
 		ctx:             ctx,
 		cancel:          cancel,
+
+		streamFlushInterval: cfg.StreamFlushInterval,
+		streamFlushRunes:    cfg.StreamFlushRunes,
 	}
 
 	er.unsubscribe = cfg.EventBus.Subscribe(er.handleEvent,
 		events.EventUserMessage,
+		events.EventUserInterrupt,
 		events.EventTaskCompleted,
 		events.EventToolCall,
 	)
@@ -118,7 +155,11 @@ func (er *EventRunner) handleEvent(event events.Event) {
 	switch event.Type {
 	case events.EventUserMessage:
 		if payload, ok := events.GetUserMessagePayload(event); ok && payload.Content != "" {
-			go er.processMessage(event.SessionID, payload.Content)
+			er.enqueueMessage(event.SessionID, payload.Content)
+		}
+	case events.EventUserInterrupt:
+		if payload, ok := events.GetUserInterruptPayload(event); ok && event.SessionID != "" {
+			er.interruptTurn(event.SessionID, payload.Content)
 		}
 	case events.EventTaskCompleted:
 		if payload, ok := events.GetTaskCompletedPayload(event); ok && event.SessionID != "" {
@@ -126,37 +167,140 @@ func (er *EventRunner) handleEvent(event events.Event) {
 		}
 	case events.EventToolCall:
 		if payload, ok := events.GetToolCallPayload(event); ok && event.SessionID != "" {
+			if payload.Status == events.ToolStatusStarted {
+				er.toolSet.MarkUsed(event.SessionID, payload.Name)
+			}
 			er.persistToolLog(event.SessionID, payload)
 		}
 	}
 }
 
-func (er *EventRunner) processMessage(sessionID string, content string) {
+// maxQueuedMessages bounds the messages waiting behind a running turn.
+const maxQueuedMessages = 32
+
+// enqueueMessage queues a user message behind the session's running turn, or
+// starts a turn right away when the session is idle. It is called from the
+// bus handler so that messages keep their arrival order.
+func (er *EventRunner) enqueueMessage(sessionID string, content string) {
 	er.mu.Lock()
-	if er.running[sessionID] {
+	queue, running := er.queues[sessionID]
+	if !running {
+		er.queues[sessionID] = nil
 		er.mu.Unlock()
+		go er.drainQueue(sessionID, content)
 		return
 	}
-	er.running[sessionID] = true
-	er.streamSeqIdx[sessionID] = &atomic.Int32{}
+	if len(queue) >= maxQueuedMessages {
+		er.mu.Unlock()
+		slog.Warn("message queue full, message rejected", "session_id", sessionID)
+		er.emitError(sessionID, "Too many messages are waiting for the current turn. Please wait for it to finish.")
+		return
+	}
+	er.queues[sessionID] = append(queue, content)
 	er.mu.Unlock()
+}
 
-	defer func() {
+// drainQueue runs one turn per message, starting with content, until the
+// session's queue is empty or the runner is closed.
+func (er *EventRunner) drainQueue(sessionID string, content string) {
+	for {
 		er.mu.Lock()
-		delete(er.running, sessionID)
-		delete(er.streamSeqIdx, sessionID)
+		er.streamSeqIdx[sessionID] = &atomic.Int32{}
 		er.mu.Unlock()
-	}()
 
+		er.processMessage(sessionID, content)
+
+		er.mu.Lock()
+		queue := er.queues[sessionID]
+		if len(queue) == 0 || er.ctx.Err() != nil {
+			delete(er.queues, sessionID)
+			delete(er.streamSeqIdx, sessionID)
+			er.mu.Unlock()
+			return
+		}
+		content = queue[0]
+		er.queues[sessionID] = queue[1:]
+		er.mu.Unlock()
+	}
+}
+
+// errTurnInterrupted is the cancel cause of a turn stopped by the user.
+var errTurnInterrupted = errors.New("turn interrupted by the user")
+
+// interruptTurn cancels the session's running turn. A non-empty content is
+// processed next, ahead of the messages already queued.
+func (er *EventRunner) interruptTurn(sessionID string, content string) {
+	er.mu.Lock()
+	queue, running := er.queues[sessionID]
+	if running && content != "" {
+		er.queues[sessionID] = append([]string{content}, queue...)
+	}
+	cancel := er.cancels[sessionID]
+	er.mu.Unlock()
+
+	if !running {
+		if content != "" {
+			er.enqueueMessage(sessionID, content)
+		}
+		return
+	}
+	if cancel != nil {
+		slog.Info("interrupting turn", "session_id", sessionID)
+		cancel(errTurnInterrupted)
+	}
+}
+
+// isInterrupted reports whether the turn of ctx was stopped by interruptTurn.
+func isInterrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errTurnInterrupted)
+}
+
+// interruptedContent marks a partial answer so the next turn knows it was cut.
+func interruptedContent(content string) string {
+	if content == "" {
+		return ""
+	}
+	return content + "\n\n[interrupted by the user]"
+}
+
+// processMessage runs a single turn for a user message. Turns of a session
+// are serialized by drainQueue.
+func (er *EventRunner) processMessage(sessionID string, content string) {
 	// Apply process timeout
 	ctx, cancel := context.WithTimeout(er.ctx, er.processTimeout)
 	defer cancel()
 
+	// Register the turn so that it can be interrupted
+	ctx, interrupt := context.WithCancelCause(ctx)
+	defer interrupt(nil)
+	er.mu.Lock()
+	er.cancels[sessionID] = interrupt
+	er.mu.Unlock()
+	defer func() {
+		er.mu.Lock()
+		delete(er.cancels, sessionID)
+		er.mu.Unlock()
+	}()
+
+	// Correlation IDs for every log line of the turn
+	ctx = events.ContextWithSessionID(ctx, sessionID)
+	ctx = events.ContextWithTurnID(ctx, uuid.New().String()[:8])
+	slog.DebugContext(ctx, "turn started")
+
+	// Drop activated tools left idle, keeping the prompt's tool list short
+	if idle := er.toolSet.BeginTurn(sessionID, er.toolIdleTurns); len(idle) > 0 {
+		slog.InfoContext(ctx, "deactivated idle tools", "tools", idle, "idle_turns", er.toolIdleTurns)
+	}
+
+	// Per-session model override (set via set_model)
+	provider, chatModel := er.resolveSessionModel(sessionID)
+	agentOpts := AgentOptions{MaxIterations: er.maxIterations, MaxParallel: er.maxParallel, Model: chatModel}
+
 	// Acquire a capacity slot from the actor pool (if configured)
 	if er.pool != nil {
-		slot, err := er.pool.AcquireInteractive(er.defaultProvider)
+		slot, err := er.pool.AcquireInteractive(provider)
 		if err != nil {
-			slog.Error("acquire interactive slot", "error", err, "session_id", sessionID)
+			slog.ErrorContext(ctx, "acquire interactive slot", "error", err)
 			er.emitError(sessionID, "All LLM capacity is currently in use. Please try again shortly.")
 			return
 		}
@@ -166,16 +310,19 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 	// Persist user message
 	userMsg := sessions.Message{Role: string(schema.User), Content: content, Ts: time.Now()}
 	if err := er.store.AppendMessage(sessionID, userMsg); err != nil {
-		slog.Error("persist user message", "error", err, "session_id", sessionID)
+		slog.ErrorContext(ctx, "persist user message", "error", err)
 	}
+	er.originals.remember(sessionID, userMsg)
 
 	// Load full history
 	history, err := er.store.LoadMessages(sessionID)
 	if err != nil {
-		slog.Error("load messages", "error", err, "session_id", sessionID)
+		slog.ErrorContext(ctx, "load messages", "error", err)
 		er.emitError(sessionID, "failed to load session history")
 		return
 	}
+	// The persisted copy may be redacted: the context sees the originals.
+	er.originals.restore(sessionID, history)
 
 	messages := make([]*schema.Message, 0, len(history))
 	for _, m := range history {
@@ -226,7 +373,7 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 				}, sessionID))
 			}
 		} else {
-			slog.Warn("layered context failed, falling back to compressor", "error", layeredErr)
+			slog.WarnContext(ctx, "layered context failed, falling back to compressor", "error", layeredErr)
 		}
 	}
 	if !compressed {
@@ -238,7 +385,7 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 
 			result, compErr := er.compressor.Compress(ctx, session, messages, sysEstimate, er.summarize)
 			if compErr != nil {
-				slog.Error("context compression", "error", compErr)
+				slog.ErrorContext(ctx, "context compression", "error", compErr)
 			} else {
 				messages = result.Messages
 				if result.Compressed {
@@ -259,21 +406,24 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 
 		// First attempt with inactive tools: run buffered (non-streaming) to detect activation
 		if attempt == 0 && er.toolSet.HasInactiveTools(sessionID) {
-			runner, err := er.factory.CreateRunnerBuffered(ctx, tools, AgentOptions{MaxIterations: er.maxIterations})
+			runner, err := er.factory.CreateRunnerBuffered(ctx, tools, agentOpts)
 			if err != nil {
-				slog.Error("create runner (buffered)", "error", err, "session_id", sessionID)
+				slog.ErrorContext(ctx, "create runner (buffered)", "error", err)
 				er.emitError(sessionID, "failed to create agent runner")
 				return
 			}
 
 			content, runErr := er.runAgentBuffered(ctx, sessionID, runner, messages)
+			if isInterrupted(ctx) {
+				er.persistAndEmitResponse(sessionID, interruptedContent(content))
+				return
+			}
 
 			if er.toolSet.ActivatedDuringTurn(sessionID) {
 				// Tools were activated — retry with expanded tool set (streamed).
 				// Any error from the buffered run is expected (the newly activated
 				// tool wasn't in the frozen Eino graph yet).
-				slog.Info("tools activated, retrying with expanded set",
-					"session_id", sessionID)
+				slog.InfoContext(ctx, "tools activated, retrying with expanded set")
 				continue
 			}
 
@@ -284,9 +434,8 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 				if toolName := extractMissingToolName(runErr.Error()); toolName != "" {
 					if er.toolSet.IsKnown(toolName) {
 						er.toolSet.Activate(sessionID, toolName)
-						slog.Info("auto-activated tool called by LLM",
-							"tool", toolName,
-							"session_id", sessionID)
+						slog.InfoContext(ctx, "auto-activated tool called by LLM",
+							"tool", toolName)
 						continue
 					}
 					// Unknown tool hallucinated by LLM — inject feedback and let it self-correct
@@ -296,8 +445,8 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 						Role:    schema.User,
 						Content: errMsg,
 					})
-					slog.Warn("LLM called unknown tool, retrying with feedback",
-						"tool", toolName, "session_id", sessionID)
+					slog.WarnContext(ctx, "LLM called unknown tool, retrying with feedback",
+						"tool", toolName)
 					continue
 				}
 				er.emitError(sessionID, runErr.Error())
@@ -307,8 +456,7 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 			// Empty response from buffered run — retry with streaming.
 			// Some models return empty in buffered mode; streaming often succeeds.
 			if content == "" {
-				slog.Warn("empty buffered response, retrying with streaming",
-					"session_id", sessionID)
+				slog.WarnContext(ctx, "empty buffered response, retrying with streaming")
 				continue
 			}
 
@@ -321,9 +469,9 @@ func (er *EventRunner) processMessage(sessionID string, content string) {
 		}
 
 		// All tools active OR retry: stream normally
-		runner, err := er.factory.CreateRunner(ctx, tools, AgentOptions{MaxIterations: er.maxIterations})
+		runner, err := er.factory.CreateRunner(ctx, tools, agentOpts)
 		if err != nil {
-			slog.Error("create runner", "error", err, "session_id", sessionID)
+			slog.ErrorContext(ctx, "create runner", "error", err)
 			er.emitError(sessionID, "failed to create agent runner")
 			return
 		}
@@ -339,7 +487,7 @@ func (er *EventRunner) runAgent(ctx context.Context, sessionID string, runner *a
 	ctx = er.withSessionWorkDir(ctx, sessionID)
 	checkpointID := uuid.New().String()
 	iter := runner.Run(ctx, messages, adk.WithCheckPointID(checkpointID))
-	er.consumeIterator(sessionID, iter)
+	er.consumeIterator(ctx, sessionID, iter)
 }
 
 func (er *EventRunner) runAgentBuffered(ctx context.Context, sessionID string, runner *adk.Runner, messages []*schema.Message) (string, error) {
@@ -350,8 +498,24 @@ func (er *EventRunner) runAgentBuffered(ctx context.Context, sessionID string, r
 	return er.consumeIteratorBuffered(sessionID, iter)
 }
 
-// withSessionContext propagates the session's RootDir as WorkDir and
-// ToolConstraints into the context.
+// resolveSessionModel returns the provider a session's turn runs on and, when
+// the session overrides the default (set_model), the chat model to use
+// instead of the factory's (nil = default).
+func (er *EventRunner) resolveSessionModel(sessionID string) (string, model.ToolCallingChatModel) {
+	if er.sessionModel == nil {
+		return er.defaultProvider, nil
+	}
+	session, err := er.store.Get(sessionID)
+	if err != nil || session.Model == "" {
+		return er.defaultProvider, nil
+	}
+	return session.Model, er.sessionModel(session.Model)
+}
+
+// withSessionWorkDir propagates the session's RootDir as WorkDir, its
+// confinement and ToolConstraints into the context. A confined session
+// without a RootDir has no root to jail to: it fails closed, the sandbox
+// refusing its path-bearing tools.
 func (er *EventRunner) withSessionWorkDir(ctx context.Context, sessionID string) context.Context {
 	sess, err := er.store.Get(sessionID)
 	if err != nil {
@@ -360,21 +524,52 @@ func (er *EventRunner) withSessionWorkDir(ctx context.Context, sessionID string)
 	if sess.RootDir != "" {
 		ctx = events.ContextWithWorkDir(ctx, sess.RootDir)
 	}
+	if er.confine || sess.Confined {
+		ctx = events.WithConfined(ctx)
+	}
 	if len(sess.ToolConstraints) > 0 {
 		ctx = events.ContextWithToolConstraints(ctx, sess.ToolConstraints)
 	}
 	return ctx
 }
 
-func (er *EventRunner) consumeIterator(sessionID string, iter *adk.AsyncIterator[*adk.AgentEvent]) {
+func (er *EventRunner) consumeIterator(ctx context.Context, sessionID string, iter *adk.AsyncIterator[*adk.AgentEvent]) {
+	// Text deltas are batched; the batch is flushed before any later event.
+	deltas := newStreamCoalescer(er.streamFlushInterval, er.streamFlushRunes, func(chunk string) {
+		er.emitStreamDelta(sessionID, chunk)
+	})
+	defer deltas.Flush()
+
 	content, _ := ConsumeIterator(iter, IterCallbacks{
-		OnStreamChunk: func(chunk string) { er.emitStreamDelta(sessionID, chunk) },
-		OnStreamDone:  func() { er.emitStreamEnd(sessionID) },
+		OnStreamChunk: deltas.Add,
+		OnStreamDone: func() {
+			deltas.Flush()
+			er.emitStreamEnd(sessionID)
+		},
+		OnToolCallDelta: func(d ToolCallDelta) {
+			deltas.Flush()
+			er.bus.Publish(events.NewTypedEventWithSession(events.SourceAgent, events.ToolCallDeltaPayload{
+				Index:  d.Index,
+				CallID: d.CallID,
+				Name:   d.Name,
+				Delta:  d.Args,
+			}, sessionID))
+		},
 		OnError: func(err error) {
-			slog.Error("agent error", "error", err)
+			deltas.Flush()
+			// An interrupted turn ends with a cancellation error: not a failure.
+			if isInterrupted(ctx) {
+				return
+			}
+			err = maxIterationsError(err, er.maxIterations)
+			slog.ErrorContext(ctx, "agent error", "error", err)
 			er.emitError(sessionID, err.Error())
 		},
 	})
+	deltas.Flush()
+	if isInterrupted(ctx) {
+		content = interruptedContent(content)
+	}
 
 	// Always emit StreamEnd to match the StreamStart emitted before runAgent.
 	// This ensures the TUI resets its streaming state even on empty responses.
@@ -386,7 +581,8 @@ func (er *EventRunner) consumeIterator(sessionID string, iter *adk.AsyncIterator
 }
 
 func (er *EventRunner) consumeIteratorBuffered(_ string, iter *adk.AsyncIterator[*adk.AgentEvent]) (string, error) {
-	return ConsumeIterator(iter, IterCallbacks{})
+	content, err := ConsumeIterator(iter, IterCallbacks{})
+	return content, maxIterationsError(err, er.maxIterations)
 }
 
 func (er *EventRunner) persistAndEmitResponse(sessionID string, content string) {
@@ -395,6 +591,7 @@ func (er *EventRunner) persistAndEmitResponse(sessionID string, content string)
 		if err := er.store.AppendMessage(sessionID, assistantMsg); err != nil {
 			slog.Error("persist assistant message", "error", err, "session_id", sessionID)
 		}
+		er.originals.remember(sessionID, assistantMsg)
 	}
 
 	er.emitResponse(sessionID, content)
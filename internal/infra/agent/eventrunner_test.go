@@ -0,0 +1,398 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+func TestWithSessionWorkDir_Confinement(t *testing.T) {
+	store := sessions.NewFileStore(t.TempDir())
+	root := t.TempDir()
+
+	tests := []struct {
+		name         string
+		confineAll   bool
+		rootDir      string
+		confined     bool
+		wantConfined bool
+	}{
+		{"unconfined", false, root, false, false},
+		{"session opt-in", false, root, true, true},
+		{"confine_interactive", true, root, false, true},
+		{"confine_interactive without root fails closed", true, "", false, true},
+		{"opt-in without root fails closed", false, "", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := store.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.RootDir = tt.rootDir
+			s.Confined = tt.confined
+			if err := store.UpdateMeta(s); err != nil {
+				t.Fatal(err)
+			}
+
+			er := &EventRunner{store: store, confine: tt.confineAll}
+			ctx := er.withSessionWorkDir(context.Background(), s.ID)
+			if got := events.IsConfinedContext(ctx); got != tt.wantConfined {
+				t.Errorf("confined = %v, want %v", got, tt.wantConfined)
+			}
+			if got := events.WorkDirFromContext(ctx); got != tt.rootDir {
+				t.Errorf("workdir = %q, want %q", got, tt.rootDir)
+			}
+		})
+	}
+}
+
+func TestResolveSessionModel(t *testing.T) {
+	store := sessions.NewFileStore(t.TempDir())
+	override, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	override.Model = "local"
+	if err := store.UpdateMeta(override); err != nil {
+		t.Fatal(err)
+	}
+	plain, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resolved []string
+	resolve := func(provider string) model.ToolCallingChatModel {
+		resolved = append(resolved, provider)
+		return nil
+	}
+
+	tests := []struct {
+		name         string
+		sessionModel SessionModelFunc
+		sessionID    string
+		wantProvider string
+		wantResolved []string
+	}{
+		{"override", resolve, override.ID, "local", []string{"local"}},
+		{"no override", resolve, plain.ID, "claude", nil},
+		{"unknown session", resolve, "missing", "claude", nil},
+		{"overrides disabled", nil, override.ID, "claude", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved = nil
+			er := &EventRunner{store: store, defaultProvider: "claude", sessionModel: tt.sessionModel}
+			provider, _ := er.resolveSessionModel(tt.sessionID)
+			if provider != tt.wantProvider {
+				t.Errorf("provider = %q, want %q", provider, tt.wantProvider)
+			}
+			if !slices.Equal(resolved, tt.wantResolved) {
+				t.Errorf("resolved models = %v, want %v", resolved, tt.wantResolved)
+			}
+		})
+	}
+}
+
+// turnModel is a streaming chat model whose turns block until released. Each
+// turn reports the user message it answers on turns, streams "partial" and
+// ends with " answer" on release, or with the context error when cancelled.
+type turnModel struct {
+	turns   chan string
+	causes  chan error // cancel cause of each cancelled turn
+	release chan struct{}
+}
+
+func newTurnModel() *turnModel {
+	return &turnModel{turns: make(chan string, 8), causes: make(chan error, 8), release: make(chan struct{})}
+}
+
+func (m *turnModel) Generate(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+	return &schema.Message{Role: schema.Assistant, Content: "summary"}, nil
+}
+
+func (m *turnModel) Stream(ctx context.Context, input []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	for i := len(input) - 1; i >= 0; i-- {
+		if input[i].Role == schema.User {
+			m.turns <- input[i].Content
+			break
+		}
+	}
+	r, w := schema.Pipe[*schema.Message](4)
+	go func() {
+		defer w.Close()
+		w.Send(&schema.Message{Role: schema.Assistant, Content: "partial"}, nil)
+		select {
+		case <-m.release:
+			w.Send(&schema.Message{Role: schema.Assistant, Content: " answer"}, nil)
+		case <-ctx.Done():
+			m.causes <- context.Cause(ctx)
+			w.Send(nil, ctx.Err())
+		}
+	}()
+	return r, nil
+}
+
+func (m *turnModel) WithTools([]*schema.ToolInfo) (model.ToolCallingChatModel, error) { return m, nil }
+
+// nextTurn returns the user message of the next turn started on m.
+func (m *turnModel) nextTurn(t *testing.T) string {
+	t.Helper()
+	select {
+	case content := <-m.turns:
+		return content
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for a turn")
+		return ""
+	}
+}
+
+type noTools struct{}
+
+func (noTools) ToolsByNames([]string) []tool.InvokableTool { return nil }
+
+// turnFixture wires an EventRunner to a turnModel on a fresh bus and session.
+type turnFixture struct {
+	bus     *events.Bus
+	runner  *EventRunner
+	store   *sessions.FileStore
+	model   *turnModel
+	session string
+	replies <-chan events.Event // assistant messages
+}
+
+func newTurnFixture(t *testing.T) *turnFixture {
+	t.Helper()
+	bus := events.NewBus(64)
+	t.Cleanup(bus.Close)
+	store := sessions.NewFileStore(t.TempDir())
+	s, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTurnModel()
+	er := NewEventRunner(EventRunnerConfig{
+		Factory:       NewAgentFactory(m, "", nil, ToolRecoveryConfig{}),
+		ToolSet:       brain.NewToolSet(nil, nil),
+		Registry:      noTools{},
+		EventBus:      bus,
+		Store:         store,
+		ContextWindow: 100000,
+	})
+	t.Cleanup(er.Close)
+	replies, unsub := bus.SubscribeChan(16, events.EventAssistantMessage)
+	t.Cleanup(unsub)
+	return &turnFixture{bus: bus, runner: er, store: store, model: m, session: s.ID, replies: replies}
+}
+
+func (f *turnFixture) send(content string) {
+	f.bus.Publish(events.NewTypedEventWithSession(events.SourceHub, events.UserMessagePayload{Content: content}, f.session))
+}
+
+// sync returns once the runner has handled every event published before.
+func (f *turnFixture) sync(t *testing.T) {
+	t.Helper()
+	sentinel, unsub := f.bus.SubscribeChan(1, "test.sentinel")
+	defer unsub()
+	// Events are delivered in order: once the sentinel arrives, the runner
+	// has queued every message published before it.
+	f.bus.Publish(events.NewEvent("test.sentinel", events.SourceHub, nil))
+	select {
+	case <-sentinel:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the sentinel event")
+	}
+}
+
+// reply returns the content of the next assistant message.
+func (f *turnFixture) reply(t *testing.T) string {
+	t.Helper()
+	select {
+	case e := <-f.replies:
+		p, _ := events.GetAssistantMessagePayload(e)
+		if p.Error != "" {
+			t.Fatalf("turn failed: %s", p.Error)
+		}
+		return p.Content
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the assistant reply")
+		return ""
+	}
+}
+
+func TestEventRunner_QueuedMessagesRunInOrder(t *testing.T) {
+	f := newTurnFixture(t)
+
+	f.send("one")
+	if got := f.model.nextTurn(t); got != "one" {
+		t.Fatalf("first turn answers %q, want one", got)
+	}
+	// Sent while "one" runs: queued behind it, in arrival order.
+	f.send("two")
+	f.send("three")
+	f.sync(t)
+
+	for _, want := range []string{"two", "three"} {
+		f.model.release <- struct{}{}
+		if got := f.reply(t); got != "partial answer" {
+			t.Fatalf("reply = %q", got)
+		}
+		if got := f.model.nextTurn(t); got != want {
+			t.Fatalf("next turn answers %q, want %q", got, want)
+		}
+	}
+	f.model.release <- struct{}{}
+	f.reply(t)
+
+	// The queue is drained: a later message starts a fresh turn.
+	f.send("four")
+	if got := f.model.nextTurn(t); got != "four" {
+		t.Fatalf("turn after the drain answers %q, want four", got)
+	}
+	f.model.release <- struct{}{}
+	f.reply(t)
+
+	history, err := f.store.LoadMessages(f.session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []string
+	for _, m := range history {
+		if m.Role == string(schema.User) {
+			users = append(users, m.Content)
+		}
+	}
+	if want := []string{"one", "two", "three", "four"}; !slices.Equal(users, want) {
+		t.Errorf("user messages persisted as %v, want %v", users, want)
+	}
+}
+
+func TestEventRunner_QueueFull(t *testing.T) {
+	f := newTurnFixture(t)
+
+	f.send("running")
+	f.model.nextTurn(t)
+	for i := range maxQueuedMessages + 1 {
+		f.send(string(rune('a' + i%26)))
+	}
+	f.sync(t)
+
+	// Only the message past the limit is rejected, with an error reply.
+	select {
+	case e := <-f.replies:
+		if p, _ := events.GetAssistantMessagePayload(e); p.Error == "" {
+			t.Fatalf("expected a queue-full error, got %+v", p)
+		}
+	default:
+		t.Fatal("expected the message past the limit to be rejected")
+	}
+
+	// Closing the runner cancels the running turn and drops the queue.
+	f.runner.Close()
+	for {
+		select {
+		case e := <-f.replies:
+			if p, _ := events.GetAssistantMessagePayload(e); p.Error == "" {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the cancelled turn to end")
+		}
+	}
+}
+
+func TestEventRunner_InterruptTurn(t *testing.T) {
+	f := newTurnFixture(t)
+	deltas, unsub := f.bus.SubscribeChan(16, events.EventAssistantStream)
+	defer unsub()
+
+	f.send("one")
+	if got := f.model.nextTurn(t); got != "one" {
+		t.Fatalf("first turn answers %q, want one", got)
+	}
+	// Interrupt only once the partial answer has been streamed.
+	for streamed := false; !streamed; {
+		select {
+		case e := <-deltas:
+			p, _ := events.GetAssistantStreamPayload(e)
+			streamed = p.Phase == events.StreamPhaseDelta && p.Content == "partial"
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the partial answer")
+		}
+	}
+	f.send("two")
+	f.bus.Publish(events.NewTypedEventWithSession(events.SourceHub, events.UserInterruptPayload{Content: "instead"}, f.session))
+
+	select {
+	case cause := <-f.model.causes:
+		if !errors.Is(cause, errTurnInterrupted) {
+			t.Fatalf("turn cancelled with cause %v, want %v", cause, errTurnInterrupted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the turn to be cancelled")
+	}
+	// An interrupted turn is not a failure: its partial answer is the reply.
+	interrupted := interruptedContent("partial")
+	if got := f.reply(t); got != interrupted {
+		t.Fatalf("reply = %q, want %q", got, interrupted)
+	}
+
+	// The interrupt's message runs next, ahead of the queued one.
+	for _, want := range []string{"instead", "two"} {
+		if got := f.model.nextTurn(t); got != want {
+			t.Fatalf("next turn answers %q, want %q", got, want)
+		}
+		f.model.release <- struct{}{}
+		f.reply(t)
+	}
+
+	history, err := f.store.LoadMessages(f.session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range history {
+		got = append(got, m.Role+": "+m.Content)
+	}
+	want := []string{
+		"user: one",
+		"assistant: " + interrupted,
+		"user: instead",
+		"assistant: partial answer",
+		"user: two",
+		"assistant: partial answer",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("history = %q, want %q", got, want)
+	}
+}
+
+func TestEventRunner_InterruptIdleSession(t *testing.T) {
+	f := newTurnFixture(t)
+
+	// Nothing to cancel: an interrupt with content is a plain message.
+	f.bus.Publish(events.NewTypedEventWithSession(events.SourceHub, events.UserInterruptPayload{Content: "hello"}, f.session))
+	if got := f.model.nextTurn(t); got != "hello" {
+		t.Fatalf("turn answers %q, want hello", got)
+	}
+	f.model.release <- struct{}{}
+	if got := f.reply(t); got != "partial answer" {
+		t.Fatalf("reply = %q", got)
+	}
+	select {
+	case cause := <-f.model.causes:
+		t.Fatalf("no turn should have been cancelled, got cause %v", cause)
+	default:
+	}
+}
@@ -16,14 +16,16 @@ type AgentFactory struct {
 	chatModel   model.ToolCallingChatModel
 	persona     string
 	middlewares []adk.AgentMiddleware // base middlewares (without recovery)
+	recovery    ToolRecoveryConfig
 }
 
 // NewAgentFactory creates a new AgentFactory.
-func NewAgentFactory(chatModel model.ToolCallingChatModel, persona string, middlewares []adk.AgentMiddleware) *AgentFactory {
+func NewAgentFactory(chatModel model.ToolCallingChatModel, persona string, middlewares []adk.AgentMiddleware, recovery ToolRecoveryConfig) *AgentFactory {
 	return &AgentFactory{
 		chatModel:   chatModel,
 		persona:     persona,
 		middlewares: middlewares,
+		recovery:    recovery,
 	}
 }
 
@@ -32,7 +34,7 @@ func NewAgentFactory(chatModel model.ToolCallingChatModel, persona string, middl
 // error counters are isolated per turn/session.
 func (f *AgentFactory) buildMiddlewares() []adk.AgentMiddleware {
 	recoveryMw := adk.AgentMiddleware{
-		WrapToolCall: NewToolRecoveryMiddleware(ToolRecoveryConfig{}),
+		WrapToolCall: NewToolRecoveryMiddleware(f.recovery),
 	}
 	mws := make([]adk.AgentMiddleware, 0, 1+len(f.middlewares))
 	mws = append(mws, recoveryMw)
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/compose"
+
+	"github.com/dohr-michael/ozzie/pkg/editor"
+)
+
+// diffTools are the filesystem tools whose results get a unified diff appended.
+var diffTools = map[string]bool{
+	"write_file": true,
+	"edit_file":  true,
+}
+
+// NewFileDiffMiddleware returns an Eino ToolMiddleware that snapshots the target
+// file before write_file/edit_file runs and appends a unified diff of the change
+// to the tool result. Missing files diff against empty content.
+func NewFileDiffMiddleware(backend editor.Backend) compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				if !diffTools[input.Name] {
+					return next(ctx, input)
+				}
+
+				var args struct {
+					FilePath string `json:"file_path"`
+				}
+				if err := json.Unmarshal([]byte(input.Arguments), &args); err != nil || args.FilePath == "" {
+					return next(ctx, input)
+				}
+
+				before, _ := backend.ReadFile(ctx, args.FilePath)
+
+				out, err := next(ctx, input)
+				if err != nil || out == nil {
+					return out, err
+				}
+
+				after, readErr := backend.ReadFile(ctx, args.FilePath)
+				if readErr != nil {
+					return out, nil
+				}
+				if diff := editor.UnifiedDiff(args.FilePath, before, after); diff != "" {
+					out.Result += "\n\n" + diff
+				}
+				return out, nil
+			}
+		},
+	}
+}
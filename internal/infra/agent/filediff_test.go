@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/compose"
+
+	"github.com/dohr-michael/ozzie/pkg/editor"
+)
+
+func TestFileDiff_AppendsDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("alpha\nbeta\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mw := NewFileDiffMiddleware(editor.LocalBackend{})
+	wrapped := mw.Invokable(func(_ context.Context, _ *compose.ToolInput) (*compose.ToolOutput, error) {
+		if err := os.WriteFile(path, []byte("alpha\ngamma\n"), 0o644); err != nil {
+			return nil, err
+		}
+		return &compose.ToolOutput{Result: "Updated file " + path}, nil
+	})
+
+	out, err := wrapped(context.Background(), &compose.ToolInput{
+		Name:      "write_file",
+		Arguments: `{"file_path":"` + path + `","content":"alpha\ngamma\n"}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.Result, "Updated file ") {
+		t.Errorf("original result lost: %q", out.Result)
+	}
+	if !strings.Contains(out.Result, "-beta\n+gamma\n") {
+		t.Errorf("expected diff in result, got:\n%s", out.Result)
+	}
+}
+
+func TestFileDiff_NewFileAndOtherTools(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.txt")
+	mw := NewFileDiffMiddleware(editor.LocalBackend{})
+	wrapped := mw.Invokable(func(_ context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+		if in.Name == "write_file" {
+			if err := os.WriteFile(path, []byte("hello\n"), 0o644); err != nil {
+				return nil, err
+			}
+		}
+		return &compose.ToolOutput{Result: "done"}, nil
+	})
+
+	out, _ := wrapped(context.Background(), &compose.ToolInput{Name: "write_file", Arguments: `{"file_path":"` + path + `"}`})
+	if !strings.Contains(out.Result, "--- /dev/null") || !strings.Contains(out.Result, "+hello") {
+		t.Errorf("expected new-file diff, got:\n%s", out.Result)
+	}
+
+	out, _ = wrapped(context.Background(), &compose.ToolInput{Name: "read_file", Arguments: `{"file_path":"` + path + `"}`})
+	if out.Result != "done" {
+		t.Errorf("non-diff tool result changed: %q", out.Result)
+	}
+}
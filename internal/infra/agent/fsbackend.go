@@ -2,9 +2,10 @@ package agent
 
 import (
 	"bufio"
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -152,8 +153,8 @@ func (b *OzzieBackend) validateWritePath(ctx context.Context, path string) error
 		return nil
 	}
 
-	// Outside sandbox — autonomous mode: hard block
-	if events.IsAutonomousContext(ctx) {
+	// Outside sandbox — autonomous mode or confined session: hard block
+	if events.IsAutonomousContext(ctx) || events.IsConfinedContext(ctx) {
 		return fmt.Errorf("write blocked: path %q is outside allowed write directories", path)
 	}
 
@@ -178,33 +179,24 @@ func (b *OzzieBackend) confirmWrite(ctx context.Context, path string) error {
 
 	token := uuid.New().String()
 
-	b.bus.Publish(events.NewTypedEvent(events.SourcePlugin, events.PromptRequestPayload{
+	ctx, cancel := context.WithTimeout(ctx, writeConfirmTimeout)
+	defer cancel()
+
+	resp, err := b.bus.Request(ctx, events.NewTypedEvent(events.SourcePlugin, events.PromptRequestPayload{
 		Type:  events.PromptTypeConfirm,
 		Label: fmt.Sprintf("Write outside sandbox: %q — allow?", path),
 		Token: token,
 	}))
-
-	ch, unsub := b.bus.SubscribeChan(1, events.EventPromptResponse)
-	defer unsub()
-
-	ctx, cancel := context.WithTimeout(ctx, writeConfirmTimeout)
-	defer cancel()
-
-	for {
-		select {
-		case event := <-ch:
-			payload, ok := events.GetPromptResponsePayload(event)
-			if !ok || payload.Token != token {
-				continue
-			}
-			if payload.Cancelled {
-				return fmt.Errorf("write denied by user: path %q is outside sandbox", path)
-			}
-			return nil // user approved
-		case <-ctx.Done():
-			return fmt.Errorf("write confirmation timed out for path %q", path)
-		}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("write confirmation timed out for path %q", path)
+	}
+	if err != nil {
+		return fmt.Errorf("write confirmation for path %q: %w", path, err)
 	}
+	if payload, ok := events.GetPromptResponsePayload(resp); !ok || payload.Cancelled {
+		return fmt.Errorf("write denied by user: path %q is outside sandbox", path)
+	}
+	return nil // user approved
 }
 
 func (b *OzzieBackend) resolvePath(ctx context.Context, path string) string {
@@ -243,6 +235,7 @@ func (b *OzzieBackend) LsInfo(ctx context.Context, req *filesystem.LsInfoRequest
 }
 
 // Read reads file content with support for line-based offset and limit.
+// The file is streamed: reading stops at the last requested line.
 func (b *OzzieBackend) Read(ctx context.Context, req *filesystem.ReadRequest) (string, error) {
 	if err := b.validateReadPath(ctx, req.FilePath); err != nil {
 		return "", err
@@ -257,12 +250,11 @@ func (b *OzzieBackend) Read(ctx context.Context, req *filesystem.ReadRequest) (s
 		return "", fmt.Errorf("read: %s is a directory, not a file — use ls to list its contents", path)
 	}
 
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
 	if err != nil {
 		return "", fmt.Errorf("read: %w", err)
 	}
-
-	lines := bytes.Split(data, []byte("\n"))
+	defer f.Close()
 
 	offset := req.Offset
 	if offset < 0 {
@@ -274,18 +266,21 @@ func (b *OzzieBackend) Read(ctx context.Context, req *filesystem.ReadRequest) (s
 		limit = 200
 	}
 
-	if offset >= len(lines) {
-		return "", nil
-	}
-
-	end := offset + limit
-	if end > len(lines) {
-		end = len(lines)
-	}
-
-	parts := make([]string, 0, end-offset)
-	for _, l := range lines[offset:end] {
-		parts = append(parts, string(l))
+	// Lines are split on "\n" like bytes.Split: a trailing newline yields a
+	// last empty line.
+	r := bufio.NewReader(f)
+	var parts []string
+	for n := 0; n < offset+limit; n++ {
+		line, err := r.ReadString('\n')
+		if n >= offset {
+			parts = append(parts, strings.TrimSuffix(line, "\n"))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("read: %w", err)
+		}
 	}
 
 	return strings.Join(parts, "\n"), nil
@@ -0,0 +1,181 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileWrite is one file of an atomic multi-file write.
+type FileWrite struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// stagedWrite tracks one file through the stage → commit → cleanup phases.
+type stagedWrite struct {
+	target    string // absolute destination path
+	temp      string // staged content, renamed onto target at commit
+	backup    string // previous content of target moved aside at commit ("" = target did not exist)
+	committed bool
+}
+
+// WriteFiles writes all files or none: every path is validated up front,
+// contents are staged in temp files next to their targets, then renamed into
+// place. If any step fails, the files already renamed are restored (or
+// removed when they did not exist) and the directories created for them are
+// removed. It returns the bytes written per file, in order.
+func (b *OzzieBackend) WriteFiles(ctx context.Context, files []FileWrite) ([]int, error) {
+	seen := make(map[string]bool, len(files))
+	targets := make([]string, len(files))
+	for i, f := range files {
+		if f.Path == "" {
+			return nil, fmt.Errorf("file %d: path is required", i)
+		}
+		if err := b.validateWritePath(ctx, f.Path); err != nil {
+			return nil, err
+		}
+		abs, err := filepath.Abs(b.resolvePath(ctx, f.Path))
+		if err != nil {
+			return nil, fmt.Errorf("resolve path %q: %w", f.Path, err)
+		}
+		if seen[abs] {
+			return nil, fmt.Errorf("path %q is listed more than once", f.Path)
+		}
+		seen[abs] = true
+		targets[i] = abs
+	}
+
+	var createdDirs []string
+	staged := make([]*stagedWrite, 0, len(files))
+	rollback := func() {
+		for i := len(staged) - 1; i >= 0; i-- {
+			s := staged[i]
+			if s.committed {
+				os.Remove(s.target)
+				if s.backup != "" {
+					os.Rename(s.backup, s.target)
+				}
+			} else {
+				os.Remove(s.temp)
+			}
+		}
+		for i := len(createdDirs) - 1; i >= 0; i-- {
+			os.Remove(createdDirs[i]) // only succeeds while empty
+		}
+	}
+
+	// Stage: write every content to a temp file in the target directory, so
+	// the commit renames never cross filesystems.
+	sizes := make([]int, len(files))
+	for i, f := range files {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return nil, err
+		}
+		dirs, err := mkdirAllTracked(filepath.Dir(targets[i]))
+		createdDirs = append(createdDirs, dirs...)
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("create dirs for %q: %w", f.Path, err)
+		}
+		temp, err := writeTemp(filepath.Dir(targets[i]), f.Content)
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("stage %q: %w", f.Path, err)
+		}
+		staged = append(staged, &stagedWrite{target: targets[i], temp: temp})
+		sizes[i] = len(f.Content)
+	}
+
+	// Commit: move existing files aside, then rename the staged contents into place.
+	for i, s := range staged {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return nil, err
+		}
+		if err := s.commit(); err != nil {
+			rollback()
+			return nil, fmt.Errorf("write %q: %w", files[i].Path, err)
+		}
+	}
+
+	for _, s := range staged {
+		if s.backup != "" {
+			os.Remove(s.backup)
+		}
+	}
+	return sizes, nil
+}
+
+func (s *stagedWrite) commit() error {
+	if info, err := os.Lstat(s.target); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("target is a directory")
+		}
+		s.backup = s.temp + ".bak"
+		if err := os.Rename(s.target, s.backup); err != nil {
+			s.backup = ""
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Rename(s.temp, s.target); err != nil {
+		if s.backup != "" {
+			os.Rename(s.backup, s.target)
+			s.backup = ""
+		}
+		return err
+	}
+	s.committed = true
+	return nil
+}
+
+// writeTemp writes content to a new hidden temp file in dir.
+func writeTemp(dir, content string) (string, error) {
+	f, err := os.CreateTemp(dir, ".ozzie-write-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// mkdirAllTracked creates dir and its missing parents, returning the
+// directories it created, outermost first.
+func mkdirAllTracked(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append(missing, d)
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	var created []string
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := os.Mkdir(missing[i], 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+			return created, err
+		}
+		created = append(created, missing[i])
+	}
+	return created, nil
+}
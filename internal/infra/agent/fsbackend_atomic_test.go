@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteFiles_Success(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("old"), 0o644)
+
+	b := NewOzzieBackend(nil, nil)
+	sizes, err := b.WriteFiles(autonomousCtx(dir), []FileWrite{
+		{Path: "existing.txt", Content: "new content"},
+		{Path: "pkg/sub/main.go", Content: "package sub\n"},
+	})
+	if err != nil {
+		t.Fatalf("WriteFiles: %v", err)
+	}
+	if !slices.Equal(sizes, []int{11, 12}) {
+		t.Errorf("sizes = %v, want [11 12]", sizes)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "existing.txt")); string(data) != "new content" {
+		t.Errorf("existing.txt = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "pkg/sub/main.go")); string(data) != "package sub\n" {
+		t.Errorf("main.go = %q", data)
+	}
+	assertNoStagingLeftovers(t, dir)
+}
+
+func TestWriteFiles_RollbackOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("original"), 0o644)
+	os.Mkdir(filepath.Join(dir, "taken"), 0o755) // a directory cannot be overwritten by a file
+
+	b := NewOzzieBackend(nil, nil)
+	_, err := b.WriteFiles(autonomousCtx(dir), []FileWrite{
+		{Path: "a.txt", Content: "changed"},
+		{Path: "new/dir/b.txt", Content: "b"},
+		{Path: "taken", Content: "boom"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "original" {
+		t.Errorf("a.txt = %q, want the original content restored", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new")); !os.IsNotExist(err) {
+		t.Errorf("directories created for the write should be removed, stat err = %v", err)
+	}
+	assertNoStagingLeftovers(t, dir)
+}
+
+func TestWriteFiles_ValidatesAllPathsFirst(t *testing.T) {
+	dir := t.TempDir()
+	b := NewOzzieBackend(nil, nil)
+
+	_, err := b.WriteFiles(autonomousCtx(dir), []FileWrite{
+		{Path: "ok.txt", Content: "ok"},
+		{Path: "/etc/ozzie-should-not-exist", Content: "x"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "outside allowed write directories") {
+		t.Fatalf("expected a sandbox error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ok.txt")); !os.IsNotExist(err) {
+		t.Error("no file should be written when a path is rejected")
+	}
+
+	if _, err := b.WriteFiles(autonomousCtx(dir), []FileWrite{
+		{Path: "same.txt", Content: "1"},
+		{Path: filepath.Join(dir, "same.txt"), Content: "2"},
+	}); err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Errorf("expected a duplicate path error, got %v", err)
+	}
+}
+
+// assertNoStagingLeftovers fails if temp or backup files remain under dir.
+func assertNoStagingLeftovers(t *testing.T, dir string) {
+	t.Helper()
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && strings.HasPrefix(d.Name(), ".ozzie-write-") {
+			t.Errorf("staging file left behind: %s", path)
+		}
+		return nil
+	})
+}
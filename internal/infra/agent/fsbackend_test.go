@@ -75,6 +75,38 @@ func TestOzzieBackend_Read_OffsetLimit(t *testing.T) {
 	}
 }
 
+func TestOzzieBackend_Read_Lines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+	os.WriteFile(path, []byte("a\nb\nc\n"), 0o644)
+
+	tests := []struct {
+		offset, limit int
+		want          string
+	}{
+		{0, 0, "a\nb\nc\n"},
+		{0, 2, "a\nb"},
+		{2, 5, "c\n"},
+		{3, 1, ""},
+		{4, 1, ""},
+	}
+
+	b := NewOzzieBackend(nil, nil)
+	for _, tt := range tests {
+		content, err := b.Read(ctxWithWorkDir(dir), &filesystem.ReadRequest{
+			FilePath: path,
+			Offset:   tt.offset,
+			Limit:    tt.limit,
+		})
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		if content != tt.want {
+			t.Errorf("Read(offset=%d, limit=%d) = %q, want %q", tt.offset, tt.limit, content, tt.want)
+		}
+	}
+}
+
 func TestOzzieBackend_GrepRaw(t *testing.T) {
 	dir := t.TempDir()
 	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello world\nfoo bar\n"), 0o644)
@@ -227,6 +259,25 @@ func TestWriteGuard_AutonomousBlocked(t *testing.T) {
 	}
 }
 
+func TestWriteGuard_ConfinedBlocked(t *testing.T) {
+	workDir := t.TempDir()
+	outsideDir := t.TempDir()
+
+	// A confined interactive session gets the hard block instead of a confirmation prompt.
+	ctx := events.WithConfined(events.ContextWithWorkDir(context.Background(), workDir))
+	b := NewOzzieBackend(nil, nil)
+	err := b.Write(ctx, &filesystem.WriteRequest{
+		FilePath: filepath.Join(outsideDir, "evil.txt"),
+		Content:  "should be blocked",
+	})
+	if err == nil || !strings.Contains(err.Error(), "outside allowed write directories") {
+		t.Fatalf("expected write outside workDir to be blocked, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "no confirmation bus") {
+		t.Errorf("confined write should not ask for confirmation, got: %v", err)
+	}
+}
+
 func TestWriteGuard_AutonomousAllowedWorkDir(t *testing.T) {
 	workDir := t.TempDir()
 
@@ -21,10 +21,27 @@ type IterCallbacks struct {
 	// OnStreamDone is called when a streaming message is fully consumed.
 	OnStreamDone func()
 
+	// OnToolCallDelta is called for each streamed tool-call fragment, while
+	// the model is still generating the call's arguments.
+	OnToolCallDelta func(ToolCallDelta)
+
+	// OnToolResult is called with each tool result (intermediate ReAct step).
+	OnToolResult func(toolName, content string)
+
 	// OnError is called on agent errors. If nil, the error is returned directly.
 	OnError func(error)
 }
 
+// ToolCallDelta is a fragment of a tool call streamed by the model. Providers
+// only send the call ID and name with the first fragment of a call; they are
+// repeated here on every fragment.
+type ToolCallDelta struct {
+	Index  int    // position of the call in the model response
+	CallID string // LLM tool-call ID
+	Name   string // tool name
+	Args   string // arguments fragment (JSON text, possibly empty)
+}
+
 // ErrIterPreempted is returned when ShouldPreempt fires during iteration.
 var ErrIterPreempted = errIterPreempted{}
 
@@ -63,7 +80,11 @@ func ConsumeIterator(iter *adk.AsyncIterator[*adk.AgentEvent], cb IterCallbacks)
 
 		// Tool results (intermediate ReAct steps) — consume streams to avoid leaks.
 		if mv.Role == schema.Tool {
-			if mv.IsStreaming && mv.MessageStream != nil {
+			if cb.OnToolResult != nil {
+				if msg, err := mv.GetMessage(); err == nil && msg != nil {
+					cb.OnToolResult(mv.ToolName, msg.Content)
+				}
+			} else if mv.IsStreaming && mv.MessageStream != nil {
 				mv.MessageStream.Close()
 			}
 			continue
@@ -71,7 +92,7 @@ func ConsumeIterator(iter *adk.AsyncIterator[*adk.AgentEvent], cb IterCallbacks)
 
 		// Assistant message — streaming or buffered.
 		if mv.IsStreaming && mv.MessageStream != nil {
-			text := consumeStreamGeneric(mv.MessageStream, cb.OnStreamChunk)
+			text := consumeStreamGeneric(mv.MessageStream, cb.OnStreamChunk, cb.OnToolCallDelta)
 			if text != "" {
 				content = text
 				if cb.OnStreamDone != nil {
@@ -99,9 +120,11 @@ func ConsumeIterator(iter *adk.AsyncIterator[*adk.AgentEvent], cb IterCallbacks)
 }
 
 // consumeStreamGeneric reads all chunks from a streaming message.
-// If onChunk is non-nil, each chunk is forwarded to it.
-func consumeStreamGeneric(stream *schema.StreamReader[*schema.Message], onChunk func(string)) string {
+// If onChunk is non-nil, each chunk is forwarded to it; likewise tool-call
+// fragments to onToolCall.
+func consumeStreamGeneric(stream *schema.StreamReader[*schema.Message], onChunk func(string), onToolCall func(ToolCallDelta)) string {
 	var sb strings.Builder
+	calls := make(map[int]ToolCallDelta) // call header by index, from its first fragment
 
 	for {
 		chunk, err := stream.Recv()
@@ -117,7 +140,34 @@ func consumeStreamGeneric(stream *schema.StreamReader[*schema.Message], onChunk
 				onChunk(chunk.Content)
 			}
 		}
+		if chunk != nil && onToolCall != nil {
+			for i, tc := range chunk.ToolCalls {
+				onToolCall(toolCallDelta(calls, i, tc))
+			}
+		}
 	}
 
 	return sb.String()
 }
+
+// toolCallDelta builds the delta of a streamed tool-call fragment, recording
+// the call's ID and name in calls when the fragment carries them. Fragments
+// without an Index are positioned by their order in the chunk.
+func toolCallDelta(calls map[int]ToolCallDelta, pos int, tc schema.ToolCall) ToolCallDelta {
+	idx := pos
+	if tc.Index != nil {
+		idx = *tc.Index
+	}
+	d := calls[idx]
+	if tc.ID != "" {
+		d.CallID = tc.ID
+	}
+	if tc.Function.Name != "" {
+		d.Name = tc.Function.Name
+	}
+	d.Index = idx
+	calls[idx] = d
+
+	d.Args = tc.Function.Arguments
+	return d
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestConsumeStreamGeneric_ToolCallDeltas(t *testing.T) {
+	idx := func(i int) *int { return &i }
+	call := func(i *int, id, name, args string) *schema.Message {
+		return &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{
+			Index: i, ID: id, Function: schema.FunctionCall{Name: name, Arguments: args},
+		}}}
+	}
+	stream := schema.StreamReaderFromArray([]*schema.Message{
+		{Role: schema.Assistant, Content: "Writing it."},
+		call(idx(0), "call_1", "write_file", ""),
+		call(idx(0), "", "", `{"path":`),
+		call(idx(1), "call_2", "run_command", `{"cmd"`),
+		call(idx(0), "", "", `"a.txt"}`),
+	})
+
+	var deltas []ToolCallDelta
+	text := consumeStreamGeneric(stream, nil, func(d ToolCallDelta) { deltas = append(deltas, d) })
+
+	if text != "Writing it." {
+		t.Errorf("text = %q", text)
+	}
+	want := []ToolCallDelta{
+		{Index: 0, CallID: "call_1", Name: "write_file"},
+		{Index: 0, CallID: "call_1", Name: "write_file", Args: `{"path":`},
+		{Index: 1, CallID: "call_2", Name: "run_command", Args: `{"cmd"`},
+		{Index: 0, CallID: "call_1", Name: "write_file", Args: `"a.txt"}`},
+	}
+	if len(deltas) != len(want) {
+		t.Fatalf("got %d deltas, want %d: %+v", len(deltas), len(want), deltas)
+	}
+	for i := range want {
+		if deltas[i] != want[i] {
+			t.Errorf("delta %d = %+v, want %+v", i, deltas[i], want[i])
+		}
+	}
+}
+
+func TestConsumeIterator_ToolResults(t *testing.T) {
+	iter, gen := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	gen.Send(adk.EventFromMessage(schema.ToolMessage("42 files", "call-1"), nil, schema.Tool, "run_command"))
+	stream := schema.StreamReaderFromArray([]*schema.Message{schema.ToolMessage("build ", "call-2"), schema.ToolMessage("ok", "call-2")})
+	gen.Send(adk.EventFromMessage(nil, stream, schema.Tool, "make"))
+	gen.Send(adk.EventFromMessage(schema.AssistantMessage("done", nil), nil, schema.Assistant, ""))
+	gen.Close()
+
+	var results []string
+	content, err := ConsumeIterator(iter, IterCallbacks{
+		OnToolResult: func(tool, result string) { results = append(results, tool+": "+result) },
+	})
+	if err != nil || content != "done" {
+		t.Fatalf("content = %q, err = %v", content, err)
+	}
+	if len(results) != 2 || results[0] != "run_command: 42 files" || results[1] != "make: build ok" {
+		t.Errorf("tool results = %v", results)
+	}
+}
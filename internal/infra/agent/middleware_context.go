@@ -11,6 +11,7 @@ import (
 
 	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
+	layeredctx "github.com/dohr-michael/ozzie/internal/core/layered"
 	"github.com/dohr-michael/ozzie/internal/core/prompt"
 	"github.com/dohr-michael/ozzie/internal/infra/sessions"
 	"github.com/dohr-michael/ozzie/pkg/memory"
@@ -37,8 +38,15 @@ type ContextMiddlewareConfig struct {
 	Retriever           MemoryRetriever     // Layer 6: memory retrieval (optional)
 	Tier                brain.ModelTier     // Model tier for prompt adaptation
 	ActorDescriptions   []prompt.ActorInfo  // Layer 3c: available actors for delegation
+	IncludeSessionVars  bool                // Layer 4b: inject set_var scratch variables
+	TaskStore           brain.TaskStore     // Layer 5: recent background tasks (optional)
+	RecentTasks         int                 // Layer 5: max finished tasks to inject (0 = disabled)
+	RecentTasksTokens   int                 // Layer 5: token budget for the task section (0 = default)
 }
 
+// defaultRecentTasksTokens is the Layer 5 token budget when none is configured.
+const defaultRecentTasksTokens = 500
+
 // NewContextMiddleware builds an AgentMiddleware that injects dynamic context
 // (custom instructions, tool descriptions, session context, memories) before
 // each chat model call.
@@ -60,11 +68,24 @@ func NewContextMiddleware(cfg ContextMiddlewareConfig) adk.AgentMiddleware {
 		mw.AdditionalInstruction = s
 	}
 
-	// BeforeChatModel: Layers 3 (dynamic tools), 4 (session), 6 (memories)
+	// BeforeChatModel: Layers 0 (session persona), 3 (dynamic tools), 4 (session), 5 (tasks), 6 (memories)
 	mw.BeforeChatModel = func(ctx context.Context, state *adk.ChatModelAgentState) error {
 		sessionID := events.SessionIDFromContext(ctx)
 		dynComposer := prompt.NewComposer()
 
+		var sess *sessions.Session
+		if cfg.Store != nil && sessionID != "" {
+			if s, err := cfg.Store.Get(sessionID); err == nil {
+				sess = s
+			}
+		}
+
+		// Layer 0: Per-session persona override. The dynamic context message is
+		// placed ahead of the agent instruction, so it precedes the global persona.
+		if sess != nil {
+			dynComposer.AddSection("Session Persona", prompt.SessionPersonaSection(sess.Persona))
+		}
+
 		// Layer 3: Active/inactive tools (dynamic per session via ToolSet)
 		if cfg.ToolSet != nil && sessionID != "" {
 			activeNames := cfg.ToolSet.ActiveToolNames(sessionID)
@@ -74,11 +95,32 @@ func NewContextMiddleware(cfg ContextMiddlewareConfig) adk.AgentMiddleware {
 		}
 
 		// Layer 4: Session context
-		if cfg.Store != nil && sessionID != "" {
-			if sess, err := cfg.Store.Get(sessionID); err == nil {
-				section := prompt.SessionSection(sess.RootDir, sess.Language, sess.Title, len(state.Messages))
-				dynComposer.AddSection("Session Context", section)
+		if sess != nil {
+			section := prompt.SessionSection(sess.RootDir, sess.Language, sess.Title, len(state.Messages))
+			dynComposer.AddSection("Session Context", section)
+
+			// Layer 4b: Session scratch variables
+			if cfg.IncludeSessionVars {
+				varMax := 0
+				if compact {
+					varMax = 100
+				}
+				dynComposer.AddSection("Session Variables", prompt.SessionVarsSection(sess.Vars, varMax))
+			}
+		}
+
+		// Layer 5: Recently finished background tasks
+		if cfg.TaskStore != nil && cfg.RecentTasks > 0 && sessionID != "" {
+			summaryMax := 200
+			if compact {
+				summaryMax = 100
 			}
+			budget := cfg.RecentTasksTokens
+			if budget <= 0 {
+				budget = defaultRecentTasksTokens
+			}
+			infos := recentTaskInfos(cfg.TaskStore, sessionID, cfg.RecentTasks)
+			dynComposer.AddSection("Recent Tasks", recentTasksWithinBudget(infos, summaryMax, budget))
 		}
 
 		// Layer 6: Relevant memories (enriched with session context)
@@ -89,11 +131,9 @@ func NewContextMiddleware(cfg ContextMiddlewareConfig) adk.AgentMiddleware {
 				var tags []string
 
 				// Enrich query with session context
-				if cfg.Store != nil && sessionID != "" {
-					if sess, err := cfg.Store.Get(sessionID); err == nil {
-						query = enrichQueryWithSession(lastMsg, sess)
-						tags = extractSessionTags(sess)
-					}
+				if sess != nil {
+					query = enrichQueryWithSession(lastMsg, sess)
+					tags = extractSessionTags(sess)
 				}
 
 				// Add recent user context for broader semantic match
@@ -157,6 +197,53 @@ func NewContextMiddleware(cfg ContextMiddlewareConfig) adk.AgentMiddleware {
 	return mw
 }
 
+// recentTaskInfos returns up to limit completed or failed tasks of a session,
+// most recently updated first.
+func recentTaskInfos(store brain.TaskStore, sessionID string, limit int) []prompt.TaskInfo {
+	all, err := store.List(brain.ListFilter{SessionID: sessionID})
+	if err != nil {
+		slog.Debug("recent tasks: list failed", "session_id", sessionID, "error", err)
+		return nil
+	}
+
+	var infos []prompt.TaskInfo
+	for _, t := range all { // List is sorted by UpdatedAt descending
+		if len(infos) >= limit {
+			break
+		}
+		var summary string
+		switch t.Status {
+		case brain.TaskCompleted:
+			summary, _ = store.ReadOutput(t.ID)
+		case brain.TaskFailed:
+			if t.Result != nil {
+				summary = t.Result.Error
+			}
+		default:
+			continue
+		}
+		infos = append(infos, prompt.TaskInfo{
+			ID:      t.ID,
+			Title:   t.Title,
+			Status:  string(t.Status),
+			Summary: summary,
+		})
+	}
+	return infos
+}
+
+// recentTasksWithinBudget renders the task section, dropping the oldest tasks
+// until it fits within maxTokens.
+func recentTasksWithinBudget(infos []prompt.TaskInfo, summaryMax, maxTokens int) string {
+	for n := len(infos); n > 0; n-- {
+		section := prompt.RecentTasksSection(infos[:n], summaryMax)
+		if layeredctx.EstimateTokens(section) <= maxTokens {
+			return section
+		}
+	}
+	return ""
+}
+
 // lastUserMessageContent returns the content of the last user message.
 func lastUserMessageContent(messages []*schema.Message) string {
 	for i := len(messages) - 1; i >= 0; i-- {
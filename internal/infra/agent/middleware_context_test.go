@@ -1,10 +1,13 @@
 package agent
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/prompt"
 	"github.com/dohr-michael/ozzie/internal/infra/sessions"
 )
 
@@ -162,3 +165,70 @@ func TestRecentUserContext(t *testing.T) {
 		})
 	}
 }
+
+// fakeTaskStore serves a fixed task list; unused methods panic via the nil embed.
+type fakeTaskStore struct {
+	brain.TaskStore
+	tasks   []*brain.Task
+	outputs map[string]string
+}
+
+func (f *fakeTaskStore) List(filter brain.ListFilter) ([]*brain.Task, error) {
+	var out []*brain.Task
+	for _, t := range f.tasks {
+		if filter.SessionID == "" || t.SessionID == filter.SessionID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTaskStore) ReadOutput(id string) (string, error) {
+	return f.outputs[id], nil
+}
+
+func TestRecentTaskInfos(t *testing.T) {
+	store := &fakeTaskStore{
+		tasks: []*brain.Task{
+			{ID: "t1", SessionID: "s1", Title: "Running", Status: brain.TaskRunning},
+			{ID: "t2", SessionID: "s1", Title: "Build", Status: brain.TaskCompleted},
+			{ID: "t3", SessionID: "s2", Title: "Other session", Status: brain.TaskCompleted},
+			{ID: "t4", SessionID: "s1", Title: "Deploy", Status: brain.TaskFailed,
+				Result: &brain.TaskResult{Error: "permission denied"}},
+			{ID: "t5", SessionID: "s1", Title: "Old", Status: brain.TaskCompleted},
+		},
+		outputs: map[string]string{"t2": "build ok"},
+	}
+
+	infos := recentTaskInfos(store, "s1", 2)
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 tasks, got %d: %+v", len(infos), infos)
+	}
+	if infos[0].ID != "t2" || infos[0].Summary != "build ok" {
+		t.Errorf("expected completed task with output first, got %+v", infos[0])
+	}
+	if infos[1].ID != "t4" || infos[1].Summary != "permission denied" {
+		t.Errorf("expected failed task with error second, got %+v", infos[1])
+	}
+}
+
+func TestRecentTasksWithinBudget(t *testing.T) {
+	infos := []prompt.TaskInfo{
+		{ID: "t1", Title: "Newest", Status: "completed", Summary: strings.Repeat("a", 200)},
+		{ID: "t2", Title: "Oldest", Status: "completed", Summary: strings.Repeat("b", 200)},
+	}
+
+	full := recentTasksWithinBudget(infos, 0, 10000)
+	if !strings.Contains(full, "Newest") || !strings.Contains(full, "Oldest") {
+		t.Fatalf("expected both tasks with a large budget, got %q", full)
+	}
+
+	trimmed := recentTasksWithinBudget(infos, 0, 100)
+	if !strings.Contains(trimmed, "Newest") || strings.Contains(trimmed, "Oldest") {
+		t.Errorf("expected oldest task dropped, got %q", trimmed)
+	}
+
+	if got := recentTasksWithinBudget(infos, 0, 10); got != "" {
+		t.Errorf("expected empty section when nothing fits, got %q", got)
+	}
+}
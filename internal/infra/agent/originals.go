@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// maxOriginalsPerSession bounds the originals kept for one session; older
+// messages fall back to their redacted copy.
+const maxOriginalsPerSession = 256
+
+// originalsCache keeps, in memory, the original content of the messages whose
+// persisted copy was redacted, so every turn rebuilds its context from what
+// was actually written. After a restart the redacted history is used.
+type originalsCache struct {
+	redactor *sessions.Redactor // nil = nothing is redacted, nothing is cached
+
+	mu       sync.Mutex
+	sessions map[string]*sessionOriginals
+}
+
+// sessionOriginals maps a message timestamp (UnixNano) to its original content.
+type sessionOriginals struct {
+	byTs  map[int64]string
+	order []int64 // insertion order, for eviction
+}
+
+func newOriginalsCache(r *sessions.Redactor) *originalsCache {
+	return &originalsCache{redactor: r, sessions: make(map[string]*sessionOriginals)}
+}
+
+// remember records msg's original content when the store persists it redacted.
+func (c *originalsCache) remember(sessionID string, msg sessions.Message) {
+	if c.redactor == nil || c.redactor.Redact(msg.Content) == msg.Content {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	so := c.sessions[sessionID]
+	if so == nil {
+		so = &sessionOriginals{byTs: make(map[int64]string)}
+		c.sessions[sessionID] = so
+	}
+	ts := msg.Ts.UnixNano()
+	if _, ok := so.byTs[ts]; !ok {
+		so.order = append(so.order, ts)
+	}
+	so.byTs[ts] = msg.Content
+	if len(so.order) > maxOriginalsPerSession {
+		delete(so.byTs, so.order[0])
+		so.order = so.order[1:]
+	}
+}
+
+// restore replaces, in place, the redacted content of history messages
+// with their cached originals.
+func (c *originalsCache) restore(sessionID string, history []sessions.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	so := c.sessions[sessionID]
+	if so == nil {
+		return
+	}
+	for i, m := range history {
+		if orig, ok := so.byTs[m.Ts.UnixNano()]; ok {
+			history[i].Content = orig
+		}
+	}
+}
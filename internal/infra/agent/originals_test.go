@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+func TestOriginalsCache_RestoresRedactedHistory(t *testing.T) {
+	redactor, err := sessions.NewRedactor(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := sessions.NewFileStore(t.TempDir())
+	store.SetRedactor(redactor)
+	s, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newOriginalsCache(redactor)
+
+	now := time.Now()
+	msgs := []sessions.Message{
+		{Role: "user", Content: "my key is api_key=hunter2hunter2", Ts: now},
+		{Role: "assistant", Content: "noted", Ts: now.Add(time.Millisecond)},
+		{Role: "user", Content: "what was it?", Ts: now.Add(2 * time.Millisecond)},
+	}
+	for _, m := range msgs {
+		if err := store.AppendMessage(s.ID, m); err != nil {
+			t.Fatal(err)
+		}
+		c.remember(s.ID, m)
+	}
+
+	history, err := store.LoadMessages(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history[0].Content == msgs[0].Content {
+		t.Fatal("expected the persisted copy to be redacted")
+	}
+	c.restore(s.ID, history)
+	for i, m := range history {
+		if m.Content != msgs[i].Content {
+			t.Errorf("history[%d] = %q, want %q", i, m.Content, msgs[i].Content)
+		}
+	}
+	if n := len(c.sessions[s.ID].byTs); n != 1 {
+		t.Errorf("cached %d originals, want only the redacted message", n)
+	}
+}
+
+func TestOriginalsCache_Bounded(t *testing.T) {
+	redactor, err := sessions.NewRedactor(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newOriginalsCache(redactor)
+	now := time.Now()
+	for i := range maxOriginalsPerSession + 10 {
+		c.remember("s", sessions.Message{Content: "password=hunter2hunter2", Ts: now.Add(time.Duration(i))})
+	}
+	so := c.sessions["s"]
+	if len(so.byTs) != maxOriginalsPerSession || len(so.order) != maxOriginalsPerSession {
+		t.Fatalf("cache holds %d/%d entries, want %d", len(so.byTs), len(so.order), maxOriginalsPerSession)
+	}
+	if _, ok := so.byTs[now.UnixNano()]; ok {
+		t.Error("oldest entry should have been evicted")
+	}
+}
+
+func TestOriginalsCache_NoRedactor(t *testing.T) {
+	c := newOriginalsCache(nil)
+	c.remember("s", sessions.Message{Content: "password=hunter2hunter2", Ts: time.Now()})
+	if len(c.sessions) != 0 {
+		t.Error("nothing should be cached without a redactor")
+	}
+}
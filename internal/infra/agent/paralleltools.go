@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// boundParallel gates tools with a shared semaphore so that at most n of the
+// tool calls emitted in one assistant message run at once. The ADK tools node
+// runs those calls concurrently and returns their results in call order;
+// the gate only bounds the fan-out. Per-tool limits (hands.ToolLimiter) still
+// apply inside each call.
+func boundParallel(tools []tool.InvokableTool, n int) []tool.InvokableTool {
+	slots := make(chan struct{}, n)
+	gated := make([]tool.InvokableTool, len(tools))
+	for i, t := range tools {
+		gated[i] = &parallelGate{inner: t, slots: slots}
+	}
+	return gated
+}
+
+// parallelGate runs the inner tool once a slot of the agent's semaphore is free.
+type parallelGate struct {
+	inner tool.InvokableTool
+	slots chan struct{}
+}
+
+// Info delegates to the inner tool.
+func (g *parallelGate) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return g.inner.Info(ctx)
+}
+
+// InvokableRun waits for a free slot, then delegates to the inner tool.
+func (g *parallelGate) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	select {
+	case g.slots <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-g.slots }()
+	return g.inner.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+var _ tool.InvokableTool = (*parallelGate)(nil)
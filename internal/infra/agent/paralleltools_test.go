@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// slowTool sleeps on every call and tracks its peak concurrency.
+type slowTool struct {
+	running, peak atomic.Int32
+}
+
+func (t *slowTool) Info(context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "read_file", Desc: "read a file"}, nil
+}
+
+func (t *slowTool) InvokableRun(_ context.Context, args string, _ ...tool.Option) (string, error) {
+	n := t.running.Add(1)
+	defer t.running.Add(-1)
+	for {
+		p := t.peak.Load()
+		if n <= p || t.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return "content of " + args, nil
+}
+
+func TestBoundParallel(t *testing.T) {
+	inner := &slowTool{}
+	tools := boundParallel([]tool.InvokableTool{inner}, 2)
+	node, err := compose.NewToolNode(context.Background(), &compose.ToolsNodeConfig{
+		Tools: []tool.BaseTool{tools[0]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []schema.ToolCall
+	for i := range 5 {
+		calls = append(calls, schema.ToolCall{
+			ID:       fmt.Sprintf("call-%d", i),
+			Function: schema.FunctionCall{Name: "read_file", Arguments: fmt.Sprintf("f%d", i)},
+		})
+	}
+	msgs, err := node.Invoke(context.Background(), schema.AssistantMessage("", calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := inner.peak.Load(); got != 2 {
+		t.Errorf("peak concurrency = %d, want 2", got)
+	}
+	if len(msgs) != len(calls) {
+		t.Fatalf("got %d results, want %d", len(msgs), len(calls))
+	}
+	for i, m := range msgs {
+		if m.ToolCallID != calls[i].ID || m.Content != "content of "+calls[i].Function.Arguments {
+			t.Errorf("result %d = %s %q, want results in call order", i, m.ToolCallID, m.Content)
+		}
+	}
+}
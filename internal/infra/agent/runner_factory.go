@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/components/model"
@@ -16,12 +17,15 @@ import (
 
 // EinoRunnerFactory implements brain.RunnerFactory using the Eino ADK.
 type EinoRunnerFactory struct {
-	models *models.Registry
+	models      *models.Registry
+	maxParallel int
 }
 
 // NewRunnerFactory creates a RunnerFactory backed by the model registry.
-func NewRunnerFactory(models *models.Registry) brain.RunnerFactory {
-	return &EinoRunnerFactory{models: models}
+// maxParallel bounds the tool calls of one assistant message run at once
+// (0 = unbounded, 1 = sequential).
+func NewRunnerFactory(models *models.Registry, maxParallel int) brain.RunnerFactory {
+	return &EinoRunnerFactory{models: models, maxParallel: maxParallel}
 }
 
 // CreateRunner creates an ephemeral agent runner for the given model, instruction, and tools.
@@ -58,6 +62,12 @@ func (f *EinoRunnerFactory) CreateRunner(ctx context.Context, modelName string,
 
 	// Build middlewares: always prepend tool recovery, then add opaque ones
 	var middlewares []adk.AgentMiddleware
+	// Interception first: it covers every tool, middleware-injected ones included
+	if o.InterceptTool != nil {
+		middlewares = append(middlewares, adk.AgentMiddleware{
+			WrapToolCall: NewToolInterceptMiddleware(o.InterceptTool),
+		})
+	}
 	middlewares = append(middlewares, adk.AgentMiddleware{
 		WrapToolCall: NewToolRecoveryMiddleware(ToolRecoveryConfig{}),
 	})
@@ -67,7 +77,7 @@ func (f *EinoRunnerFactory) CreateRunner(ctx context.Context, modelName string,
 		}
 	}
 
-	var agentOpts AgentOptions
+	agentOpts := AgentOptions{MaxParallel: f.maxParallel}
 	if o.MaxIterations > 0 {
 		agentOpts.MaxIterations = o.MaxIterations
 	}
@@ -77,13 +87,22 @@ func (f *EinoRunnerFactory) CreateRunner(ctx context.Context, modelName string,
 		return nil, fmt.Errorf("create agent: %w", err)
 	}
 
-	return &einoRunner{runner: runner, preemptionCheck: o.PreemptionCheck}, nil
+	return &einoRunner{
+		runner:          runner,
+		preemptionCheck: o.PreemptionCheck,
+		onAssistantText: o.OnAssistantText,
+		onToolResult:    o.OnToolResult,
+		maxIterations:   agentOpts.MaxIterations,
+	}, nil
 }
 
 // einoRunner wraps an adk.Runner into a brain.Runner.
 type einoRunner struct {
 	runner          *adk.Runner
 	preemptionCheck func() bool
+	onAssistantText func(string)
+	onToolResult    func(tool, result string)
+	maxIterations   int // reported when the cap is reached (0 = ADK default)
 }
 
 // Run executes the agent and returns the concatenated text output.
@@ -100,20 +119,41 @@ func (r *einoRunner) Run(ctx context.Context, messages []brain.Message) (string,
 	checkpointID := uuid.New().String()
 	iter := r.runner.Run(ctx, einoMsgs, adk.WithCheckPointID(checkpointID))
 
-	content, err := ConsumeIterator(iter, IterCallbacks{
-		ShouldPreempt: r.preemptionCheck,
-	})
+	cb := IterCallbacks{ShouldPreempt: r.preemptionCheck, OnToolResult: r.onToolResult}
+	if r.onAssistantText != nil {
+		// Hand each assistant message over whole once its stream is consumed.
+		var sb strings.Builder
+		cb.OnStreamChunk = func(chunk string) { sb.WriteString(chunk) }
+		cb.OnStreamDone = func() {
+			r.onAssistantText(sb.String())
+			sb.Reset()
+		}
+	}
+
+	content, err := ConsumeIterator(iter, cb)
 	if errors.Is(err, ErrIterPreempted) {
 		return content, brain.ErrRunnerPreempted
 	}
-	// Convert model unavailable errors
+	// Convert model unavailable and context overflow errors
 	if err != nil {
 		var unavail *models.ErrModelUnavailable
 		if errors.As(err, &unavail) {
 			return content, &brain.ErrModelUnavailable{Provider: unavail.Provider, Cause: unavail.Cause}
 		}
+		if models.IsContextOverflow(err) {
+			return content, &brain.ErrContextOverflow{Cause: err}
+		}
+	}
+	return content, maxIterationsError(err, r.maxIterations)
+}
+
+// maxIterationsError converts the ADK iteration cap error into a
+// brain.ErrMaxIterations; other errors are returned unchanged.
+func maxIterationsError(err error, limit int) error {
+	if errors.Is(err, adk.ErrExceedMaxIterations) {
+		return &brain.ErrMaxIterations{Limit: limit}
 	}
-	return content, err
+	return err
 }
 
 var _ brain.RunnerFactory = (*EinoRunnerFactory)(nil)
@@ -0,0 +1,30 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+func TestMaxIterationsError(t *testing.T) {
+	err := maxIterationsError(fmt.Errorf("run: %w", adk.ErrExceedMaxIterations), 30)
+	var maxIter *brain.ErrMaxIterations
+	if !errors.As(err, &maxIter) || maxIter.Limit != 30 {
+		t.Fatalf("got %v, want brain.ErrMaxIterations{Limit: 30}", err)
+	}
+	if got := err.Error(); got != "max iterations reached (30): the run stopped before finishing" {
+		t.Errorf("message = %q", got)
+	}
+
+	other := errors.New("boom")
+	if got := maxIterationsError(other, 30); got != other {
+		t.Errorf("other errors should pass through, got %v", got)
+	}
+	if maxIterationsError(nil, 30) != nil {
+		t.Error("nil should stay nil")
+	}
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// streamCoalescer accumulates the text chunks of a model stream and emits
+// them in batches: once interval has elapsed since the first buffered chunk,
+// or as soon as maxRunes are buffered. Fast providers produce many tiny
+// chunks; batching them bounds the event (and WS frame) rate of a stream.
+//
+// Emission happens under the coalescer lock, so batches leave in order.
+type streamCoalescer struct {
+	interval time.Duration // 0 = no coalescing: every chunk is emitted at once
+	maxRunes int           // 0 = flush on the timer only
+	emit     func(string)
+
+	mu    sync.Mutex
+	buf   strings.Builder
+	runes int
+	timer *time.Timer
+}
+
+func newStreamCoalescer(interval time.Duration, maxRunes int, emit func(string)) *streamCoalescer {
+	return &streamCoalescer{interval: interval, maxRunes: maxRunes, emit: emit}
+}
+
+// Add buffers a chunk, flushing when the rune threshold is reached.
+func (c *streamCoalescer) Add(chunk string) {
+	if chunk == "" {
+		return
+	}
+	if c.interval <= 0 {
+		c.emit(chunk)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buf.WriteString(chunk)
+	c.runes += utf8.RuneCountInString(chunk)
+	if c.maxRunes > 0 && c.runes >= c.maxRunes {
+		c.flushLocked()
+		return
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.interval, c.Flush)
+	}
+}
+
+// Flush emits the buffered content, if any. Called at the end of a stream
+// (and before any event that must follow the text emitted so far).
+func (c *streamCoalescer) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushLocked()
+}
+
+func (c *streamCoalescer) flushLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.buf.Len() == 0 {
+		return
+	}
+	content := c.buf.String()
+	c.buf.Reset()
+	c.runes = 0
+	c.emit(content)
+}
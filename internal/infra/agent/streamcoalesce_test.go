@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// emitted collects the batches a coalescer emits.
+type emitted struct {
+	mu      sync.Mutex
+	batches []string
+}
+
+func (e *emitted) emit(s string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, s)
+}
+
+func (e *emitted) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.batches...)
+}
+
+func TestStreamCoalescer_Disabled(t *testing.T) {
+	out := &emitted{}
+	c := newStreamCoalescer(0, 0, out.emit)
+	for _, chunk := range []string{"a", "b", "c"} {
+		c.Add(chunk)
+	}
+	c.Flush()
+	if got := out.get(); len(got) != 3 {
+		t.Errorf("batches = %q, want one per chunk", got)
+	}
+}
+
+func TestStreamCoalescer_FlushesOnRunes(t *testing.T) {
+	out := &emitted{}
+	c := newStreamCoalescer(time.Hour, 4, out.emit)
+	for _, chunk := range []string{"hé", "llo", " wo", "rld"} {
+		c.Add(chunk)
+	}
+	if got := out.get(); len(got) != 2 || got[0] != "héllo" || got[1] != " world" {
+		t.Fatalf("batches = %q, want flushes at 4 runes", got)
+	}
+	c.Add("!")
+	c.Flush() // stream end: the remainder is not lost
+	if got := out.get(); len(got) != 3 || got[2] != "!" {
+		t.Errorf("batches = %q, want the remainder flushed", got)
+	}
+}
+
+func TestStreamCoalescer_FlushesOnTimer(t *testing.T) {
+	out := &emitted{}
+	c := newStreamCoalescer(10*time.Millisecond, 0, out.emit)
+	c.Add("tic ")
+	c.Add("tac")
+
+	deadline := time.Now().Add(time.Second)
+	for len(out.get()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := out.get(); len(got) != 1 || got[0] != "tic tac" {
+		t.Fatalf("batches = %q, want one timed flush", got)
+	}
+
+	c.Flush()
+	if got := out.get(); len(got) != 1 {
+		t.Errorf("empty flush emitted: %q", got)
+	}
+}
+
+func TestStreamCoalescer_KeepsOrder(t *testing.T) {
+	out := &emitted{}
+	c := newStreamCoalescer(time.Millisecond, 7, out.emit)
+	var want strings.Builder
+	for i := range 200 {
+		chunk := string(rune('a' + i%26))
+		want.WriteString(chunk)
+		c.Add(chunk)
+		if i%50 == 0 {
+			time.Sleep(2 * time.Millisecond) // let the timer flush mid-stream
+		}
+	}
+	c.Flush()
+	if got := strings.Join(out.get(), ""); got != want.String() {
+		t.Errorf("content reordered or lost:\n got %q\nwant %q", got, want.String())
+	}
+}
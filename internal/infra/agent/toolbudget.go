@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/adk/middlewares/reduction"
+	"github.com/cloudwego/eino/schema"
+)
+
+// summarizedToolResultPrefix marks a tool result already replaced by a summary.
+const summarizedToolResultPrefix = "[Tool result summarized"
+
+// ToolCallBudgetConfig configures the per-turn tool call budget middleware.
+type ToolCallBudgetConfig struct {
+	// MaxToolCalls is the number of tool results a turn may accumulate before
+	// older ones are summarized. Required (> 0).
+	MaxToolCalls int
+	// KeepRecent is the number of most recent tool results kept intact (default 3).
+	KeepRecent int
+	// ExcerptChars is the size of the excerpt kept in a summary, in runes (default 300).
+	ExcerptChars int
+	// Backend stores the full content of summarized results (the reduction backend).
+	Backend reduction.Backend
+	// Dir is where full results are written; they stay readable via read_file.
+	Dir string
+}
+
+// NewToolCallBudgetMiddleware returns an ADK middleware that bounds the context
+// of tool-heavy turns. Once a turn holds more than MaxToolCalls tool results,
+// every result but the KeepRecent latest is offloaded to the backend and
+// replaced in the conversation by a short excerpt pointing at the saved file.
+func NewToolCallBudgetMiddleware(cfg ToolCallBudgetConfig) adk.AgentMiddleware {
+	if cfg.KeepRecent <= 0 {
+		cfg.KeepRecent = 3
+	}
+	if cfg.ExcerptChars <= 0 {
+		cfg.ExcerptChars = 300
+	}
+	return adk.AgentMiddleware{
+		BeforeChatModel: func(ctx context.Context, state *adk.ChatModelAgentState) error {
+			summarizeToolResults(ctx, state.Messages, cfg)
+			return nil
+		},
+	}
+}
+
+// summarizeToolResults applies the budget to msgs in place.
+func summarizeToolResults(ctx context.Context, msgs []*schema.Message, cfg ToolCallBudgetConfig) {
+	var toolIdx []int
+	for i, m := range msgs {
+		if m.Role == schema.Tool {
+			toolIdx = append(toolIdx, i)
+		}
+	}
+	if len(toolIdx) <= cfg.MaxToolCalls {
+		return
+	}
+
+	keep := min(cfg.KeepRecent, len(toolIdx))
+	summarized := 0
+	for _, i := range toolIdx[:len(toolIdx)-keep] {
+		m := msgs[i]
+		if strings.HasPrefix(m.Content, summarizedToolResultPrefix) || len([]rune(m.Content)) <= cfg.ExcerptChars {
+			continue
+		}
+
+		id := m.ToolCallID
+		if id == "" {
+			id = fmt.Sprintf("msg%d", i)
+		}
+		path := filepath.Join(cfg.Dir, fmt.Sprintf("tool_result_%s.txt", id))
+		if err := cfg.Backend.Write(ctx, &filesystem.WriteRequest{FilePath: path, Content: m.Content}); err != nil {
+			slog.Warn("tool call budget: offload tool result", "tool", m.ToolName, "error", err)
+			continue
+		}
+
+		m.Content = fmt.Sprintf("%s to keep the turn's context bounded (%d chars). Excerpt:\n%s\n"+
+			"Full result saved at %s — use read_file if you need it again.]",
+			summarizedToolResultPrefix, len(m.Content), truncate(m.Content, cfg.ExcerptChars), path)
+		summarized++
+	}
+
+	if summarized > 0 {
+		slog.Debug("tool call budget: summarized tool results",
+			"tool_results", len(toolIdx), "summarized", summarized)
+	}
+}
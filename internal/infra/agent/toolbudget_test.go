@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/adk/filesystem"
+	"github.com/cloudwego/eino/schema"
+)
+
+// memBackend records written files.
+type memBackend struct {
+	files map[string]string
+}
+
+func (b *memBackend) Write(_ context.Context, req *filesystem.WriteRequest) error {
+	b.files[req.FilePath] = req.Content
+	return nil
+}
+
+func toolTurn(n int, size int) []*schema.Message {
+	msgs := []*schema.Message{schema.UserMessage("do many things")}
+	for i := 0; i < n; i++ {
+		msgs = append(msgs, schema.ToolMessage(strings.Repeat(fmt.Sprint(i), size), fmt.Sprintf("call_%d", i)))
+	}
+	return msgs
+}
+
+func TestToolCallBudget_UnderThreshold(t *testing.T) {
+	backend := &memBackend{files: map[string]string{}}
+	mw := NewToolCallBudgetMiddleware(ToolCallBudgetConfig{MaxToolCalls: 5, Backend: backend, Dir: "/tmp/ozzie"})
+
+	state := &adk.ChatModelAgentState{Messages: toolTurn(5, 1000)}
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.files) != 0 {
+		t.Fatalf("expected no offloading under threshold, got %d files", len(backend.files))
+	}
+}
+
+func TestToolCallBudget_SummarizesOlderResults(t *testing.T) {
+	backend := &memBackend{files: map[string]string{}}
+	mw := NewToolCallBudgetMiddleware(ToolCallBudgetConfig{MaxToolCalls: 4, KeepRecent: 2, Backend: backend, Dir: "/tmp/ozzie"})
+
+	msgs := toolTurn(6, 1000)
+	msgs[2].Content = "short" // under the excerpt size — left as is
+	original := msgs[1].Content
+	state := &adk.ChatModelAgentState{Messages: msgs}
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+
+	// Results 0,2,3 summarized (1 is short), 4,5 kept.
+	if len(backend.files) != 3 {
+		t.Fatalf("expected 3 offloaded results, got %d", len(backend.files))
+	}
+	if got := backend.files["/tmp/ozzie/tool_result_call_0.txt"]; got != original {
+		t.Error("full result not offloaded")
+	}
+	if !strings.HasPrefix(msgs[1].Content, summarizedToolResultPrefix) ||
+		!strings.Contains(msgs[1].Content, "/tmp/ozzie/tool_result_call_0.txt") {
+		t.Errorf("unexpected summary: %q", msgs[1].Content)
+	}
+	if msgs[2].Content != "short" {
+		t.Errorf("short result should be kept, got %q", msgs[2].Content)
+	}
+	for _, m := range msgs[5:] {
+		if strings.HasPrefix(m.Content, summarizedToolResultPrefix) {
+			t.Errorf("recent result %s should be kept intact", m.ToolCallID)
+		}
+	}
+
+	// A second pass does not re-offload summarized results.
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.files) != 3 {
+		t.Fatalf("expected no re-offloading, got %d files", len(backend.files))
+	}
+}
@@ -0,0 +1,34 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+// NewToolInterceptMiddleware returns an Eino ToolMiddleware consulting fn
+// before every tool call, streaming ones included: an intercepted call never
+// reaches the tool (nor the middlewares wrapped inside this one).
+func NewToolInterceptMiddleware(fn brain.ToolCallInterceptor) compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				if result, ok := fn(ctx, input.Name, input.Arguments); ok {
+					return &compose.ToolOutput{Result: result}, nil
+				}
+				return next(ctx, input)
+			}
+		},
+		Streamable: func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				if result, ok := fn(ctx, input.Name, input.Arguments); ok {
+					return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray([]string{result})}, nil
+				}
+				return next(ctx, input)
+			}
+		},
+	}
+}
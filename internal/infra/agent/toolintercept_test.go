@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino/adk/middlewares/filesystem"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// TestToolInterceptMiddleware_CoversMiddlewareTools checks that tools injected
+// by the filesystem middleware (not part of the agent's own tool list) go
+// through the interceptor: an intercepted write_file creates no file.
+func TestToolInterceptMiddleware_CoversMiddlewareTools(t *testing.T) {
+	dir := t.TempDir()
+	ctx := autonomousCtx(dir)
+	fsMw, err := filesystem.NewMiddleware(ctx, &filesystem.Config{
+		Backend:                          NewOzzieBackend(nil, nil),
+		WithoutLargeToolResultOffloading: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var intercepted []string
+	intercept := func(_ context.Context, tool, _ string) (string, bool) {
+		if tool == "read_file" || tool == "ls" {
+			return "", false
+		}
+		intercepted = append(intercepted, tool)
+		return "[simulated] " + tool, true
+	}
+
+	run := func(mws []compose.ToolMiddleware, name, args string) string {
+		t.Helper()
+		node, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{
+			Tools:               fsMw.AdditionalTools,
+			ToolCallMiddlewares: mws,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs, err := node.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{{
+			ID: "call_1", Function: schema.FunctionCall{Name: name, Arguments: args},
+		}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msgs[0].Content
+	}
+
+	path := filepath.Join(dir, "created.txt")
+	write := fmt.Sprintf(`{"file_path": %q, "content": "hello"}`, path)
+	mws := []compose.ToolMiddleware{NewToolInterceptMiddleware(intercept)}
+
+	if got := run(mws, "write_file", write); got != "[simulated] write_file" {
+		t.Errorf("write_file result = %q", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("intercepted write_file created the file (stat err: %v)", err)
+	}
+
+	// Read-only tools still run.
+	if err := os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(mws, "read_file", fmt.Sprintf(`{"file_path": %q}`, filepath.Join(dir, "existing.txt"))); got == "" || got[0] == '[' {
+		t.Errorf("read_file should run for real, got %q", got)
+	}
+
+	// Control: without the interceptor the same call writes the file.
+	run(nil, "write_file", write)
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("control write failed: %v", err)
+	}
+	if len(intercepted) != 1 || intercepted[0] != "write_file" {
+		t.Errorf("intercepted = %v", intercepted)
+	}
+}
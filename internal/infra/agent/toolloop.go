@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// toolLoopMarker marks a tool result already carrying a loop nudge.
+const toolLoopMarker = "[Repeated tool call:"
+
+// ErrToolLoop is returned when a turn repeats the same tool call MaxRepeats times.
+var ErrToolLoop = errors.New("tool call loop")
+
+// ToolLoopConfig configures the detection of repeated identical tool calls.
+type ToolLoopConfig struct {
+	// Threshold is the number of identical calls (same tool, same arguments)
+	// within a turn after which the agent is nudged to change approach (default 3).
+	Threshold int
+	// MaxRepeats stops the run once a call is repeated that many times (0 = never).
+	MaxRepeats int
+}
+
+// NewToolLoopMiddleware returns an ADK middleware that detects an agent
+// thrashing on the same tool call. Once a turn has called a tool with the same
+// arguments Threshold times, the latest result of that call is followed by a
+// note asking the model to try something else; at MaxRepeats the run fails
+// with ErrToolLoop. Distinct from the iteration cap, which bounds all calls.
+func NewToolLoopMiddleware(cfg ToolLoopConfig) adk.AgentMiddleware {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 3
+	}
+	return adk.AgentMiddleware{
+		BeforeChatModel: func(ctx context.Context, state *adk.ChatModelAgentState) error {
+			return detectToolLoop(ctx, state.Messages, cfg)
+		},
+	}
+}
+
+// repeatedCall tracks the occurrences of one tool call signature in a turn.
+type repeatedCall struct {
+	name   string
+	count  int
+	lastID string // tool call ID of the latest occurrence
+}
+
+// detectToolLoop nudges (in place) or stops the repeated tool calls of the
+// current turn: the messages after the last user message.
+func detectToolLoop(ctx context.Context, msgs []*schema.Message, cfg ToolLoopConfig) error {
+	start := 0
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == schema.User {
+			start = i + 1
+			break
+		}
+	}
+
+	calls := make(map[string]*repeatedCall)
+	var order []string
+	for _, m := range msgs[start:] {
+		if m.Role != schema.Assistant {
+			continue
+		}
+		for _, tc := range m.ToolCalls {
+			sig := tc.Function.Name + "\x00" + normalizeToolArgs(tc.Function.Arguments)
+			rc, ok := calls[sig]
+			if !ok {
+				rc = &repeatedCall{name: tc.Function.Name}
+				calls[sig] = rc
+				order = append(order, sig)
+			}
+			rc.count++
+			rc.lastID = tc.ID
+		}
+	}
+
+	for _, sig := range order {
+		rc := calls[sig]
+		if rc.count < cfg.Threshold {
+			continue
+		}
+		if cfg.MaxRepeats > 0 && rc.count >= cfg.MaxRepeats {
+			slog.WarnContext(ctx, "tool loop: stopping run", "tool", rc.name, "repeats", rc.count)
+			return fmt.Errorf("%w: %s called %d times with the same arguments", ErrToolLoop, rc.name, rc.count)
+		}
+		for _, m := range msgs[start:] {
+			if m.Role != schema.Tool || m.ToolCallID != rc.lastID || strings.Contains(m.Content, toolLoopMarker) {
+				continue
+			}
+			m.Content += fmt.Sprintf("\n\n%s you have called %s with these exact arguments %d times in this turn. "+
+				"Calling it again will not give a different result: try a different approach (other arguments, "+
+				"another tool), or answer with what you already know.]", toolLoopMarker, rc.name, rc.count)
+			slog.InfoContext(ctx, "tool loop: nudged agent", "tool", rc.name, "repeats", rc.count)
+		}
+	}
+	return nil
+}
+
+// normalizeToolArgs returns a canonical form of JSON arguments (sorted keys,
+// no whitespace) so formatting differences do not hide a repeated call.
+func normalizeToolArgs(args string) string {
+	var v any
+	if err := json.Unmarshal([]byte(args), &v); err != nil {
+		return strings.TrimSpace(args)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return strings.TrimSpace(args)
+	}
+	return string(data)
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// loopTurn builds a turn calling read_file with args n times, each followed by its result.
+func loopTurn(history []*schema.Message, args ...string) []*schema.Message {
+	msgs := append(history, schema.UserMessage("find the config key"))
+	for i, a := range args {
+		id := fmt.Sprintf("call_%d", i)
+		msgs = append(msgs,
+			schema.AssistantMessage("", []schema.ToolCall{{ID: id, Function: schema.FunctionCall{Name: "read_file", Arguments: a}}}),
+			schema.ToolMessage("no such key here", id),
+		)
+	}
+	return msgs
+}
+
+func nudged(msgs []*schema.Message) []string {
+	var ids []string
+	for _, m := range msgs {
+		if m.Role == schema.Tool && strings.Contains(m.Content, toolLoopMarker) {
+			ids = append(ids, m.ToolCallID)
+		}
+	}
+	return ids
+}
+
+func TestToolLoop_NudgesRepeatedCall(t *testing.T) {
+	mw := NewToolLoopMiddleware(ToolLoopConfig{})
+
+	// Same arguments, formatted differently: still the same call.
+	state := &adk.ChatModelAgentState{Messages: loopTurn(nil,
+		`{"path": "a.yaml"}`, `{"path":"a.yaml"}`, `{"path":"b.yaml"}`, ` {"path":"a.yaml"} `)}
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	if got := nudged(state.Messages); len(got) != 1 || got[0] != "call_3" {
+		t.Fatalf("nudged %v, want the latest repeated call only", got)
+	}
+	if last := state.Messages[len(state.Messages)-1].Content; !strings.Contains(last, "read_file with these exact arguments 3 times") {
+		t.Errorf("unexpected nudge: %q", last)
+	}
+
+	// Running again before the next call does not nudge twice.
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(state.Messages[len(state.Messages)-1].Content, toolLoopMarker); n != 1 {
+		t.Errorf("nudge appended %d times", n)
+	}
+}
+
+func TestToolLoop_CountsCurrentTurnOnly(t *testing.T) {
+	mw := NewToolLoopMiddleware(ToolLoopConfig{Threshold: 3})
+
+	previous := loopTurn(nil, `{"path":"a.yaml"}`, `{"path":"a.yaml"}`)
+	state := &adk.ChatModelAgentState{Messages: loopTurn(previous, `{"path":"a.yaml"}`, `{"path":"a.yaml"}`)}
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	if got := nudged(state.Messages); len(got) != 0 {
+		t.Errorf("calls of a previous turn should not count, nudged %v", got)
+	}
+}
+
+func TestToolLoop_StopsAtMaxRepeats(t *testing.T) {
+	mw := NewToolLoopMiddleware(ToolLoopConfig{Threshold: 2, MaxRepeats: 4})
+
+	args := []string{`{"path":"a.yaml"}`, `{"path":"a.yaml"}`, `{"path":"a.yaml"}`}
+	state := &adk.ChatModelAgentState{Messages: loopTurn(nil, args...)}
+	if err := mw.BeforeChatModel(context.Background(), state); err != nil {
+		t.Fatalf("3 repeats should only nudge, got %v", err)
+	}
+
+	state = &adk.ChatModelAgentState{Messages: loopTurn(nil, append(args, `{"path":"a.yaml"}`)...)}
+	err := mw.BeforeChatModel(context.Background(), state)
+	if !errors.Is(err, ErrToolLoop) || !strings.Contains(err.Error(), "read_file called 4 times") {
+		t.Errorf("expected ErrToolLoop at max_repeats, got %v", err)
+	}
+}
@@ -4,26 +4,92 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/cloudwego/eino/compose"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+const (
+	// DefaultToolRecoveryAttempts is the default number of runs of a failing
+	// tool call, the first one included.
+	DefaultToolRecoveryAttempts = 3
+	// DefaultToolRecoveryBackoff is the default delay before the first retry.
+	DefaultToolRecoveryBackoff = 500 * time.Millisecond
 )
 
+// DefaultIdempotentTools are the tools whose calls only read state, so
+// re-running one after a timeout or a matching error has no side effect.
+var DefaultIdempotentTools = []string{
+	"read_file", "ls", "glob", "grep",
+	"web_fetch", "web_search",
+	"query_memories", "query_tasks", "get_var", "list_schedules",
+}
+
 // ToolRecoveryConfig configures the tool-call error recovery middleware.
-// Currently a marker type — all errors are converted to textual results
-// unconditionally. The runner's maxIterations (20) guards against infinite
-// retry loops.
-type ToolRecoveryConfig struct{}
+// Recoverable errors are retried in place, up to MaxAttempts runs with a
+// jittered exponential backoff; every other error — and the last one of a
+// recoverable call — surfaces to the model as a textual result. The runner's
+// maxIterations (20) still bounds how often the model itself retries.
+//
+// An error is recoverable when the tool tagged it ToolErrTransient itself.
+// Errors classified transient from their cause (a context deadline, a network
+// timeout) or matching Recoverable are only retried for idempotent tools: a
+// timed-out command may have run, and running it again is not safe.
+type ToolRecoveryConfig struct {
+	MaxAttempts   int              // runs per tool call, first included (0 = DefaultToolRecoveryAttempts, 1 = no retry)
+	Backoff       time.Duration    // delay before the first retry, doubled each time, +0–50% jitter (0 = DefaultToolRecoveryBackoff)
+	Recoverable   []*regexp.Regexp // error patterns retried (idempotent tools only) in addition to transient errors
+	Idempotent    []string         // tools safe to re-run (nil = DefaultIdempotentTools)
+	OmitErrorText bool             // keep the error message out of the model's context (code and guidance only)
+	Bus           events.EventBus  // receives a ToolRecoveryPayload before each retry (nil = no events)
+}
+
+// recoveryHints tells the LLM, per error code, whether self-correction is
+// worthwhile.
+var recoveryHints = map[brain.ToolErrorCode]string{
+	brain.ToolErrInvalidInput:     "Fix the arguments and retry.",
+	brain.ToolErrNotFound:         "Check that the referenced item exists, then retry with a correct reference or inform the user.",
+	brain.ToolErrPermissionDenied: "This action is not allowed. Do not retry it; inform the user or take another approach.",
+	brain.ToolErrTransient:        "This is likely temporary. You can retry the same call once, then inform the user if it still fails.",
+	brain.ToolErrInternal:         "Retrying is unlikely to help. Inform the user about the issue.",
+}
 
 // NewToolRecoveryMiddleware returns an Eino ToolMiddleware that intercepts tool
 // errors and converts them into textual results so the LLM can decide whether to
-// retry with different parameters or inform the user.
+// retry with different parameters or inform the user. The error code
+// (brain.ToolErrorCodeOf) selects the guidance appended to the message.
 // Errors are never propagated — they are always returned as text to avoid
 // crashing the session via event.Err in consumeIterator.
-func NewToolRecoveryMiddleware(_ ToolRecoveryConfig) compose.ToolMiddleware {
+func NewToolRecoveryMiddleware(cfg ToolRecoveryConfig) compose.ToolMiddleware {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultToolRecoveryAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultToolRecoveryBackoff
+	}
+	if cfg.Idempotent == nil {
+		cfg.Idempotent = DefaultIdempotentTools
+	}
+
 	return compose.ToolMiddleware{
 		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
 			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
 				out, err := next(ctx, input)
+				attempt := 1
+				for err != nil && attempt < cfg.MaxAttempts && cfg.recoverable(input.Name, err) {
+					attempt++
+					cfg.publish(ctx, input, attempt, err)
+					if !sleepCtx(ctx, cfg.delay(attempt)) {
+						break
+					}
+					out, err = next(ctx, input)
+				}
 				if err == nil {
 					// Guard against empty tool results — OpenAI/Ollama APIs
 					// reject tool_result messages with empty content.
@@ -33,14 +99,79 @@ func NewToolRecoveryMiddleware(_ ToolRecoveryConfig) compose.ToolMiddleware {
 					return out, nil
 				}
 
+				code := brain.ToolErrorCodeOf(err)
 				slog.Warn("tool error recovery: converting error to result",
-					"tool", input.Name, "error", err)
-				msg := fmt.Sprintf(
-					"[TOOL_ERROR] Tool %q failed: %s\n"+
-						"You can retry with different parameters, or inform the user about the issue.",
-					input.Name, err)
+					"tool", input.Name, "code", code, "attempts", attempt, "error", err)
+				detail := fmt.Sprintf(": %s", err)
+				if cfg.OmitErrorText {
+					detail = ""
+				}
+				if attempt > 1 {
+					detail = fmt.Sprintf(" after %d attempts%s", attempt, detail)
+				}
+				msg := fmt.Sprintf("[TOOL_ERROR] Tool %q failed (%s)%s\n%s",
+					input.Name, code, detail, recoveryHints[code])
 				return &compose.ToolOutput{Result: msg}, nil
 			}
 		},
 	}
 }
+
+// recoverable reports whether a failed call of tool is worth retrying in
+// place: errors the tool tagged transient always are; for idempotent tools,
+// errors classified transient or matching a configured pattern too.
+func (c ToolRecoveryConfig) recoverable(tool string, err error) bool {
+	if brain.TaggedToolErrorCode(err) == brain.ToolErrTransient {
+		return true
+	}
+	if !slices.Contains(c.Idempotent, tool) {
+		return false
+	}
+	if brain.ToolErrorCodeOf(err) == brain.ToolErrTransient {
+		return true
+	}
+	for _, re := range c.Recoverable {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the backoff before the given attempt (2 = first retry),
+// with up to 50% random jitter so concurrent retries spread out.
+func (c ToolRecoveryConfig) delay(attempt int) time.Duration {
+	d := c.Backoff << (attempt - 2)
+	return d + rand.N(d/2+1)
+}
+
+func (c ToolRecoveryConfig) publish(ctx context.Context, input *compose.ToolInput, attempt int, err error) {
+	if c.Bus == nil {
+		return
+	}
+	payload := events.ToolRecoveryPayload{
+		ToolName:    input.Name,
+		CallID:      input.CallID,
+		Attempt:     attempt,
+		MaxAttempts: c.MaxAttempts,
+		Code:        string(brain.ToolErrorCodeOf(err)),
+		Error:       err.Error(),
+	}
+	if sid := events.SessionIDFromContext(ctx); sid != "" {
+		c.Bus.Publish(events.NewTypedEventWithSession(events.SourceAgent, payload, sid))
+	} else {
+		c.Bus.Publish(events.NewTypedEvent(events.SourceAgent, payload))
+	}
+}
+
+// sleepCtx waits for d, returning false if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/cloudwego/eino/compose"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
 // fakeEndpoint returns a canned result.
@@ -119,3 +125,155 @@ func TestToolRecovery_ErrorMessageContainsToolName(t *testing.T) {
 		t.Fatalf("expected tool name in error message, got: %s", out.Result)
 	}
 }
+
+func TestToolRecovery_GuidanceFollowsErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"invalid input", brain.ToolErrorf(brain.ToolErrInvalidInput, "title is required"), "(invalid_input)"},
+		{"permission denied", fmt.Errorf("node: %w", brain.ToolErrorf(brain.ToolErrPermissionDenied, "denied by user")), "Do not retry"},
+		{"transient", brain.ToolErrorf(brain.ToolErrTransient, "timeout"), "retry the same call"},
+		{"untagged", errors.New("boom"), "(internal)"},
+	}
+
+	mw := NewToolRecoveryMiddleware(ToolRecoveryConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := mw.Invokable(failingEndpoint(tt.err))(context.Background(), &compose.ToolInput{Name: "submit_task"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(out.Result, tt.want) {
+				t.Fatalf("expected %q in result, got: %s", tt.want, out.Result)
+			}
+		})
+	}
+}
+
+// countingEndpoint fails with errs in order, then succeeds with "ok".
+func countingEndpoint(calls *int, errs ...error) compose.InvokableToolEndpoint {
+	return func(_ context.Context, _ *compose.ToolInput) (*compose.ToolOutput, error) {
+		*calls++
+		if *calls <= len(errs) {
+			return nil, errs[*calls-1]
+		}
+		return &compose.ToolOutput{Result: "ok"}, nil
+	}
+}
+
+func TestToolRecovery_NonRecoverableSurfacesImmediately(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	recoveries, unsub := bus.SubscribeChan(4, events.EventToolRecovery)
+	defer unsub()
+
+	calls := 0
+	mw := NewToolRecoveryMiddleware(ToolRecoveryConfig{Backoff: time.Millisecond, Bus: bus})
+	out, err := mw.Invokable(countingEndpoint(&calls, brain.ToolErrorf(brain.ToolErrInvalidInput, "title is required")))(
+		context.Background(), &compose.ToolInput{Name: "submit_task"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single call, got %d", calls)
+	}
+	if !strings.Contains(out.Result, "title is required") {
+		t.Fatalf("expected error text in result, got: %s", out.Result)
+	}
+	select {
+	case e := <-recoveries:
+		t.Fatalf("unexpected recovery event: %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestToolRecovery_RetriesTransientUpToCap(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	recoveries, unsub := bus.SubscribeChan(4, events.EventToolRecovery)
+	defer unsub()
+
+	calls := 0
+	timeout := brain.ToolErrorf(brain.ToolErrTransient, "timeout")
+	mw := NewToolRecoveryMiddleware(ToolRecoveryConfig{MaxAttempts: 3, Backoff: time.Millisecond, Bus: bus})
+	ctx := events.ContextWithSessionID(context.Background(), "sess1")
+	out, err := mw.Invokable(countingEndpoint(&calls, timeout, timeout, timeout, timeout))(ctx, &compose.ToolInput{Name: "web_fetch"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+	if !strings.Contains(out.Result, "after 3 attempts") {
+		t.Fatalf("expected attempt count in result, got: %s", out.Result)
+	}
+
+	for _, want := range []int{2, 3} {
+		select {
+		case e := <-recoveries:
+			p, ok := events.GetToolRecoveryPayload(e)
+			if !ok || p.Attempt != want || p.MaxAttempts != 3 || p.ToolName != "web_fetch" || e.SessionID != "sess1" {
+				t.Fatalf("recovery event = %+v (session %q), want attempt %d/3", p, e.SessionID, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing recovery event for attempt %d", want)
+		}
+	}
+}
+
+func TestToolRecovery_RecoverablePatternAndOmitErrorText(t *testing.T) {
+	calls := 0
+	mw := NewToolRecoveryMiddleware(ToolRecoveryConfig{
+		Backoff:       time.Millisecond,
+		Recoverable:   []*regexp.Regexp{regexp.MustCompile(`rate limit`)},
+		OmitErrorText: true,
+	})
+
+	out, err := mw.Invokable(countingEndpoint(&calls, errors.New("rate limit reached")))(
+		context.Background(), &compose.ToolInput{Name: "web_search"})
+	if err != nil || out.Result != "ok" || calls != 2 {
+		t.Fatalf("expected success on retry, got result=%q err=%v calls=%d", out.Result, err, calls)
+	}
+
+	calls = 0
+	out, _ = mw.Invokable(countingEndpoint(&calls, errors.New("secret token leaked")))(
+		context.Background(), &compose.ToolInput{Name: "web_search"})
+	if calls != 1 || strings.Contains(out.Result, "secret") || !strings.Contains(out.Result, "(internal)") {
+		t.Fatalf("expected code-only result after one call, got %q (calls=%d)", out.Result, calls)
+	}
+}
+
+func TestToolRecovery_RetriesOnlyIdempotentOrTagged(t *testing.T) {
+	timeout := fmt.Errorf("run: %w", context.DeadlineExceeded)
+	tests := []struct {
+		name  string
+		tool  string
+		err   error
+		calls int
+	}{
+		{"exec timeout", "run_command", timeout, 1},
+		{"read-only timeout", "read_file", timeout, 2},
+		{"tagged transient", "run_command", brain.ToolErrorf(brain.ToolErrTransient, "backend busy"), 2},
+		{"pattern on side-effecting tool", "run_command", errors.New("rate limit reached"), 1},
+		{"pattern on idempotent tool", "web_search", errors.New("rate limit reached"), 2},
+		{"configured idempotent tool", "lint", timeout, 2},
+	}
+	mw := NewToolRecoveryMiddleware(ToolRecoveryConfig{
+		Backoff:     time.Millisecond,
+		Recoverable: []*regexp.Regexp{regexp.MustCompile(`rate limit`)},
+		Idempotent:  append(slices.Clone(DefaultIdempotentTools), "lint"),
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			if _, err := mw.Invokable(countingEndpoint(&calls, tt.err))(context.Background(), &compose.ToolInput{Name: tt.tool}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls != tt.calls {
+				t.Errorf("%s ran %d times, want %d", tt.tool, calls, tt.calls)
+			}
+		})
+	}
+}
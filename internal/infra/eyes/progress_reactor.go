@@ -173,7 +173,7 @@ func toolReaction(toolName string) connector.ReactionType {
 		return connector.ReactionSchedule
 	case "activate":
 		return connector.ReactionActivate
-	case "query_tasks", "cancel_task":
+	case "query_tasks", "cancel_task", "save_task_template":
 		return connector.ReactionTask
 	default:
 		return connector.ReactionTool
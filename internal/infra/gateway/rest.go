@@ -0,0 +1,198 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// REST mirrors of the main WS methods, for scripting with plain HTTP clients
+// (CI, cron, webhooks). WS remains the primary real-time path.
+
+// eventJSON is the HTTP representation of a bus event.
+type eventJSON struct {
+	ID        string             `json:"id"`
+	SessionID string             `json:"session_id,omitempty"`
+	Type      string             `json:"type"`
+	Timestamp string             `json:"timestamp"`
+	Source    events.EventSource `json:"source"`
+	Payload   map[string]any     `json:"payload"`
+}
+
+func toEventJSON(e events.Event) eventJSON {
+	return eventJSON{
+		ID:        e.ID,
+		SessionID: e.SessionID,
+		Type:      string(e.Type),
+		Timestamp: e.Timestamp.Format(time.RFC3339Nano),
+		Source:    e.Source,
+		Payload:   e.Payload,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeBody decodes an optional JSON request body into v.
+func decodeBody(r *http.Request, v any) error {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// handleCreateSession mirrors open_session without session_id.
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		RootDir  string `json:"root_dir"`
+		Confined bool   `json:"confined"`
+		Project  string `json:"project"`
+	}
+	if err := decodeBody(r, &params); err != nil {
+		http.Error(w, "invalid params", http.StatusBadRequest)
+		return
+	}
+	if (params.Confined || s.confineAll) && params.RootDir == "" {
+		http.Error(w, sessions.ErrConfinedWithoutRoot.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sess, err := s.store.Create()
+	if err != nil {
+		http.Error(w, "create session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if params.Project == "" {
+		params.Project = sessions.ProjectFromRootDir(params.RootDir)
+	}
+	if params.RootDir != "" || params.Confined || params.Project != "" {
+		sess.RootDir = params.RootDir
+		sess.Confined = params.Confined
+		sess.Project = params.Project
+		_ = s.store.UpdateMeta(sess)
+	}
+
+	s.bus.Publish(events.NewEventWithSession(
+		events.EventSessionCreated, events.SourceHub,
+		map[string]any{"session_id": sess.ID}, sess.ID,
+	))
+
+	writeJSON(w, http.StatusCreated, map[string]string{"session_id": sess.ID, "status": "created"})
+}
+
+// handleSendMessage mirrors send_message. With "Accept: text/event-stream"
+// the session's events are streamed as SSE until the assistant replies;
+// otherwise the message is queued and 202 is returned.
+func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	if _, err := s.store.Get(sessionID); err != nil {
+		http.Error(w, "session not found: "+sessionID, http.StatusNotFound)
+		return
+	}
+
+	var params struct {
+		Content string `json:"content"`
+	}
+	if err := decodeBody(r, &params); err != nil || params.Content == "" {
+		http.Error(w, "invalid params: content required", http.StatusBadRequest)
+		return
+	}
+
+	msg := events.NewTypedEventWithSession(events.SourceWS, events.UserMessagePayload{
+		Content: params.Content,
+	}, sessionID)
+
+	if !wantsSSE(r) {
+		s.bus.Publish(msg)
+		writeJSON(w, http.StatusAccepted, map[string]string{"session_id": sessionID, "status": "sent"})
+		return
+	}
+
+	// Subscribe before publishing so the reply cannot be missed.
+	ch, unsub := s.subscribeSSE()
+	defer unsub()
+	s.bus.Publish(msg)
+
+	streamSSE(w, r, ch, func(e events.Event) bool {
+		return e.SessionID == sessionID && e.Type != events.EventUserMessage
+	}, func(e events.Event) bool {
+		return e.Type == events.EventAssistantMessage
+	})
+}
+
+// handleSubmitTask mirrors submit_task.
+func (s *Server) handleSubmitTask(w http.ResponseWriter, r *http.Request) {
+	if s.taskHandler == nil {
+		http.Error(w, "task system not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var params struct {
+		SessionID      string   `json:"session_id"`
+		Title          string   `json:"title"`
+		Description    string   `json:"description"`
+		Tools          []string `json:"tools"`
+		Priority       string   `json:"priority"`
+		IdempotencyKey string   `json:"idempotency_key"`
+	}
+	if err := decodeBody(r, &params); err != nil {
+		http.Error(w, "invalid params", http.StatusBadRequest)
+		return
+	}
+
+	taskID, err := s.taskHandler.Submit(params.SessionID, params.Title, params.Description, params.Tools, params.Priority, params.IdempotencyKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{"task_id": taskID, "status": "submitted"})
+}
+
+// handleGetTask mirrors query_tasks with a task_id.
+func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	if s.taskHandler == nil {
+		http.Error(w, "task system not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	result, err := s.taskHandler.QueryTasks(chi.URLParam(r, "id"), "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleCancelTask mirrors cancel_task.
+func (s *Server) handleCancelTask(w http.ResponseWriter, r *http.Request) {
+	if s.taskHandler == nil {
+		http.Error(w, "task system not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var params struct {
+		Reason string `json:"reason"`
+	}
+	if err := decodeBody(r, &params); err != nil {
+		http.Error(w, "invalid params", http.StatusBadRequest)
+		return
+	}
+
+	taskID := chi.URLParam(r, "id")
+	if err := s.taskHandler.Cancel(taskID, params.Reason); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"task_id": taskID, "status": "cancelled"})
+}
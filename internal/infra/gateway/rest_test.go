@@ -0,0 +1,133 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+func TestREST_CreateSessionAndSendMessage(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.hub.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(`{"root_dir":"/tmp"}`))
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create session: status %d, body %s", w.Code, w.Body)
+	}
+	var created struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := srv.store.Get(created.SessionID); err != nil || s.RootDir != "/tmp" {
+		t.Fatalf("stored session = %+v, %v", s, err)
+	}
+
+	msgs, unsub := srv.bus.SubscribeChan(4, events.EventUserMessage)
+	defer unsub()
+
+	req = httptest.NewRequest(http.MethodPost, "/api/sessions/"+created.SessionID+"/messages", strings.NewReader(`{"content":"hi"}`))
+	w = httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("send message: status %d, body %s", w.Code, w.Body)
+	}
+
+	e := <-msgs
+	p, _ := events.GetUserMessagePayload(e)
+	if e.SessionID != created.SessionID || p.Content != "hi" {
+		t.Errorf("user message = %q in %q", p.Content, e.SessionID)
+	}
+}
+
+func TestREST_CreateSession_ConfinedWithoutRoot(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.hub.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(`{"confined":true}`))
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status %d, want 400", w.Code)
+	}
+	if list, _ := srv.store.List(); len(list) != 0 {
+		t.Errorf("no session should be created, got %d", len(list))
+	}
+
+	srv.SetConfineInteractive(true)
+	req = httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(`{}`))
+	w = httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("confine_interactive: status %d, want 400", w.Code)
+	}
+}
+
+func TestREST_SendMessage_UnknownSession(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.hub.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/nope/messages", strings.NewReader(`{"content":"hi"}`))
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status %d, want 404", w.Code)
+	}
+}
+
+func TestREST_SendMessage_SSE(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.hub.Close()
+
+	s, err := srv.store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake agent: answer every user message of the session.
+	msgs, unsub := srv.bus.SubscribeChan(4, events.EventUserMessage)
+	defer unsub()
+	go func() {
+		for e := range msgs {
+			srv.bus.Publish(events.NewTypedEventWithSession(events.SourceAgent,
+				events.AssistantMessagePayload{Content: "hello"}, e.SessionID))
+		}
+	}()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/"+s.ID+"/messages", strings.NewReader(`{"content":"hi"}`))
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "event: assistant.message\n") || !strings.Contains(body, `"content":"hello"`) {
+		t.Errorf("SSE body = %q", body)
+	}
+	if strings.Contains(body, "event: user.message") {
+		t.Errorf("SSE body echoes the user message: %q", body)
+	}
+}
+
+func TestREST_TasksUnavailable(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.hub.Close()
+
+	for _, path := range []string{"/api/tasks", "/api/tasks/t1/cancel"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("POST %s: status %d, want 503", path, w.Code)
+		}
+	}
+}
@@ -28,6 +28,11 @@ type Server struct {
 	bus         events.EventBus
 	store       sessions.Store
 	taskHandler *WSTaskHandler
+	admin       ws.AdminHandler
+	mcp         http.Handler
+	metrics     http.Handler
+	requireAuth func(http.Handler) http.Handler
+	confineAll  bool // every session is confined (see SetConfineInteractive)
 	host        string
 	port        int
 }
@@ -42,23 +47,31 @@ func NewServer(bus events.EventBus, store sessions.Store, host string, port int,
 	r.Use(middleware.RealIP)
 
 	s := &Server{
-		hub:   hub,
-		bus:   bus,
-		store: store,
-		host:  host,
-		port:  port,
+		hub:         hub,
+		bus:         bus,
+		store:       store,
+		host:        host,
+		port:        port,
+		requireAuth: auth.Middleware(authenticator),
 	}
 
-	// Health always public
+	// Health always public; metrics authenticated unless made public (see SetMetricsHandler)
 	r.Get("/api/health", s.handleHealth)
+	r.Get("/metrics", s.handleMetrics)
 
 	// Auth middleware on all other routes
 	r.Group(func(r chi.Router) {
-		r.Use(auth.Middleware(authenticator))
+		r.Use(s.requireAuth)
 		r.Get("/api/ws", hub.ServeWS)
 		r.Get("/api/events", s.handleEvents)
 		r.Get("/api/sessions", s.handleSessions)
+		r.Post("/api/sessions", s.handleCreateSession)
+		r.Post("/api/sessions/{id}/messages", s.handleSendMessage)
 		r.Get("/api/tasks", s.handleTasks)
+		r.Post("/api/tasks", s.handleSubmitTask)
+		r.Get("/api/tasks/{id}", s.handleGetTask)
+		r.Post("/api/tasks/{id}/cancel", s.handleCancelTask)
+		r.Handle("/api/mcp", http.HandlerFunc(s.handleMCP))
 	})
 
 	s.httpServer = &http.Server{
@@ -87,10 +100,19 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	health := map[string]any{"status": "ok", "events": s.bus.Stats()}
+	if s.admin != nil {
+		health["tools"] = s.admin.ToolStats()
+	}
+	json.NewEncoder(w).Encode(health)
 }
 
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if wantsSSE(r) {
+		s.handleEventStream(w, r)
+		return
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	limit := 50
 	if limitStr != "" {
@@ -109,29 +131,12 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	// Format timestamps nicely
-	type eventJSON struct {
-		ID        string             `json:"id"`
-		SessionID string             `json:"session_id,omitempty"`
-		Type      string             `json:"type"`
-		Timestamp string             `json:"timestamp"`
-		Source    events.EventSource `json:"source"`
-		Payload   map[string]any     `json:"payload"`
-	}
-
 	result := make([]eventJSON, 0, len(history))
 	for _, e := range history {
 		if sessionFilter != "" && e.SessionID != sessionFilter {
 			continue
 		}
-		result = append(result, eventJSON{
-			ID:        e.ID,
-			SessionID: e.SessionID,
-			Type:      string(e.Type),
-			Timestamp: e.Timestamp.Format(time.RFC3339Nano),
-			Source:    e.Source,
-			Payload:   e.Payload,
-		})
+		result = append(result, toEventJSON(e))
 	}
 
 	json.NewEncoder(w).Encode(result)
@@ -143,6 +148,7 @@ func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	list = sessions.FilterByProject(list, r.URL.Query().Get("project"))
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(list)
@@ -171,7 +177,71 @@ func (s *Server) SetTaskHandler(th *WSTaskHandler) {
 	s.hub.SetTaskHandler(th)
 }
 
+// SetAdminHandler configures the handler for WS admin methods.
+func (s *Server) SetAdminHandler(ah ws.AdminHandler) {
+	s.admin = ah
+	s.hub.SetAdminHandler(ah)
+}
+
+// SetModelHandler configures the handler for per-session model selection.
+func (s *Server) SetModelHandler(mh ws.ModelHandler) {
+	s.hub.SetModelHandler(mh)
+}
+
+// SetSessionSearcher configures the searcher used by search_sessions.
+func (s *Server) SetSessionSearcher(ss ws.SessionSearcher) {
+	s.hub.SetSessionSearcher(ss)
+}
+
+// SetConfineInteractive confines every session to its root_dir
+// (sandbox.confine_interactive); sessions without one are rejected.
+func (s *Server) SetConfineInteractive(on bool) {
+	s.confineAll = on
+	s.hub.SetConfineInteractive(on)
+}
+
 // SetSecretEncryptor enables encryption for password prompt responses.
 func (s *Server) SetSecretEncryptor(r *age.X25519Recipient) {
 	s.hub.SetSecretEncryptor(r)
 }
+
+// SetMCPHandler exposes an MCP endpoint at /api/mcp. Must be called before Start.
+func (s *Server) SetMCPHandler(h http.Handler) {
+	s.mcp = h
+}
+
+func (s *Server) handleMCP(w http.ResponseWriter, r *http.Request) {
+	if s.mcp == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.mcp.ServeHTTP(w, r)
+}
+
+// SetMetricsHandler exposes Prometheus metrics at /metrics, behind gateway
+// auth unless public. Must be called before Start.
+func (s *Server) SetMetricsHandler(h http.Handler, public bool) {
+	if !public {
+		h = s.requireAuth(h)
+	}
+	s.metrics = h
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.metrics == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.metrics.ServeHTTP(w, r)
+}
+
+// WSClientCount returns the number of connected WS clients.
+func (s *Server) WSClientCount() int {
+	return s.hub.ClientCount()
+}
+
+// StartIdleReaper auto-closes sessions idle for longer than timeout until
+// ctx is done.
+func (s *Server) StartIdleReaper(ctx context.Context, timeout time.Duration) {
+	s.hub.StartIdleReaper(ctx, timeout)
+}
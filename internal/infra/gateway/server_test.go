@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/auth"
 	"github.com/dohr-michael/ozzie/internal/infra/sessions"
 )
 
@@ -44,12 +45,18 @@ func TestHandleHealth(t *testing.T) {
 		t.Fatalf("expected status 200, got %d", w.Code)
 	}
 
-	var body map[string]string
+	var body struct {
+		Status string          `json:"status"`
+		Events events.BusStats `json:"events"`
+	}
 	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
 		t.Fatalf("decode body: %v", err)
 	}
-	if body["status"] != "ok" {
-		t.Fatalf("expected status %q, got %q", "ok", body["status"])
+	if body.Status != "ok" {
+		t.Fatalf("expected status %q, got %q", "ok", body.Status)
+	}
+	if body.Events.Policy != events.OverflowDropNewest {
+		t.Fatalf("expected default overflow policy, got %q", body.Events.Policy)
 	}
 }
 
@@ -213,3 +220,51 @@ func TestHandleSessions_WithSessions(t *testing.T) {
 		t.Fatalf("expected 2 sessions, got %d", len(body))
 	}
 }
+
+// tokenAuth accepts requests carrying its bearer token.
+type tokenAuth string
+
+func (a tokenAuth) AuthenticateHTTP(r *http.Request) (string, error) {
+	if r.Header.Get("Authorization") != "Bearer "+string(a) {
+		return "", auth.ErrUnauthorized
+	}
+	return auth.DeviceLocal, nil
+}
+
+func (a tokenAuth) AuthenticateWS(r *http.Request) (string, error) { return a.AuthenticateHTTP(r) }
+
+func TestHandleMetrics_Auth(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ozzie_up 1\n"))
+	})
+
+	tests := []struct {
+		name   string
+		public bool
+		token  string
+		want   int
+	}{
+		{"no token", false, "", http.StatusUnauthorized},
+		{"valid token", false, "secret", http.StatusOK},
+		{"public", true, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := events.NewBus(64)
+			t.Cleanup(bus.Close)
+			srv := NewServer(bus, sessions.NewFileStore(t.TempDir()), "localhost", 0, nil, tokenAuth("secret"))
+			defer srv.hub.Close()
+			srv.SetMetricsHandler(metrics, tt.public)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			srv.httpServer.Handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
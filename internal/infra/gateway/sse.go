@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/gateway/ws"
+)
+
+// sseBuffer bounds the events queued for a slow SSE client; overflow is
+// dropped so a stalled reader never blocks the bus.
+const sseBuffer = 256
+
+// wantsSSE reports whether the client asked for an event stream.
+func wantsSSE(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// subscribeSSE subscribes to the bus (all events when types is empty) with
+// a non-blocking, bounded queue.
+func (s *Server) subscribeSSE(types ...events.EventType) (<-chan events.Event, func()) {
+	ch := make(chan events.Event, sseBuffer)
+	unsub := s.bus.Subscribe(func(e events.Event) {
+		select {
+		case ch <- e:
+		default:
+			slog.Debug("sse client too slow, event dropped", "type", e.Type)
+		}
+	}, types...)
+	return ch, unsub
+}
+
+// streamSSE writes the events accepted by keep as SSE frames until the
+// client disconnects or an event satisfies last (nil = never). Each frame
+// carries the event type and the same JSON frame WS clients receive.
+func streamSSE(w http.ResponseWriter, r *http.Request, ch <-chan events.Event, keep, last func(events.Event) bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			if !keep(e) {
+				continue
+			}
+			frame, err := ws.NewEventFrame(string(e.Type), e.SessionID, e)
+			if err != nil {
+				slog.Error("marshal sse frame", "error", err)
+				continue
+			}
+			data, err := ws.MarshalFrame(frame)
+			if err != nil {
+				slog.Error("marshal sse frame", "error", err)
+				continue
+			}
+			if _, err := w.Write([]byte("event: " + string(e.Type) + "\ndata: " + string(data) + "\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+			if last != nil && last(e) {
+				return
+			}
+		}
+	}
+}
+
+// handleEventStream streams live events as SSE, optionally filtered by
+// session (?session=ID) and event types (?types=task.created,task.completed).
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	sessionFilter := r.URL.Query().Get("session")
+
+	var types []events.EventType
+	for _, t := range strings.Split(r.URL.Query().Get("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, events.EventType(t))
+		}
+	}
+
+	ch, unsub := s.subscribeSSE(types...)
+	defer unsub()
+
+	streamSSE(w, r, ch, func(e events.Event) bool {
+		return sessionFilter == "" || e.SessionID == sessionFilter
+	}, nil)
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+func TestHandleEvents_SSE(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.hub.Close()
+	ts := httptest.NewServer(srv.httpServer.Handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/events?session=s1&types=task.created,task.completed", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	// The response headers are flushed once the subscription is live.
+	srv.bus.Publish(events.NewEventWithSession(events.EventTaskStarted, events.SourceTask, nil, "s1"))
+	srv.bus.Publish(events.NewEventWithSession(events.EventTaskCreated, events.SourceTask, nil, "s2"))
+	srv.bus.Publish(events.NewEventWithSession(events.EventTaskCreated, events.SourceTask, nil, "s1"))
+
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+
+	var got []string
+	timeout := time.After(2 * time.Second)
+	for len(got) < 2 {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				t.Fatalf("stream closed early, got %v", got)
+			}
+			if l != "" {
+				got = append(got, l)
+			}
+		case <-timeout:
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+
+	if got[0] != "event: task.created" {
+		t.Errorf("first line = %q, want the s1 task.created event only", got[0])
+	}
+	if !strings.HasPrefix(got[1], "data: ") || !strings.Contains(got[1], `"session_id":"s1"`) || !strings.Contains(got[1], `"type":"event"`) {
+		t.Errorf("data line = %q, want an s1 event frame", got[1])
+	}
+}
@@ -15,14 +15,22 @@ func NewWSTaskHandler(pool tasks.TaskSubmitter) *WSTaskHandler {
 }
 
 type taskSummary struct {
-	ID       string             `json:"id"`
-	Title    string             `json:"title"`
-	Status   tasks.TaskStatus   `json:"status"`
-	Progress tasks.TaskProgress `json:"progress"`
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Status    tasks.TaskStatus   `json:"status"`
+	Progress  tasks.TaskProgress `json:"progress"`
+	Artifacts []string           `json:"artifacts,omitempty"` // single-task lookup only
 }
 
-// Submit creates a new task via the pool.
-func (h *WSTaskHandler) Submit(sessionID, title, description string, tools []string, priority string) (string, error) {
+// taskArtifact is an artifact with its content, returned by TaskArtifacts.
+type taskArtifact struct {
+	Artifact tasks.Artifact `json:"artifact"`
+	Content  []byte         `json:"content"` // base64 in JSON
+}
+
+// Submit creates a new task via the pool, or returns the active task
+// submitted with the same idempotency key.
+func (h *WSTaskHandler) Submit(sessionID, title, description string, tools []string, priority, idempotencyKey string) (string, error) {
 	p := tasks.PriorityNormal
 	if priority != "" {
 		p = tasks.TaskPriority(priority)
@@ -41,6 +49,7 @@ func (h *WSTaskHandler) Submit(sessionID, title, description string, tools []str
 		Config: tasks.TaskConfig{
 			Tools: tools,
 		},
+		IdempotencyKey: idempotencyKey,
 	}
 
 	if err := h.pool.Submit(t); err != nil {
@@ -56,12 +65,18 @@ func (h *WSTaskHandler) QueryTasks(taskID, sessionID string) (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		return taskSummary{
+		summary := taskSummary{
 			ID:       t.ID,
 			Title:    t.Title,
 			Status:   t.Status,
 			Progress: t.Progress,
-		}, nil
+		}
+		if artifacts, _ := h.pool.Store().ListArtifacts(t.ID); len(artifacts) > 0 {
+			for _, a := range artifacts {
+				summary.Artifacts = append(summary.Artifacts, a.Name)
+			}
+		}
+		return summary, nil
 	}
 
 	filter := tasks.ListFilter{}
@@ -93,3 +108,50 @@ func (h *WSTaskHandler) Cancel(taskID string, reason string) error {
 	}
 	return h.pool.Cancel(taskID, reason)
 }
+
+// CancelSession cancels every active task of a session, descendants included.
+func (h *WSTaskHandler) CancelSession(sessionID string, reason string) (any, error) {
+	if reason == "" {
+		reason = "session tasks cancelled via WS"
+	}
+	return h.pool.CancelSession(sessionID, reason)
+}
+
+// HasActiveTasks reports whether a session has pending (incl. preempted) or
+// running tasks.
+func (h *WSTaskHandler) HasActiveTasks(sessionID string) bool {
+	for _, status := range []tasks.TaskStatus{tasks.TaskPending, tasks.TaskRunning} {
+		list, err := h.pool.Store().List(tasks.ListFilter{SessionID: sessionID, Status: status})
+		if err != nil || len(list) > 0 {
+			// Err on the side of keeping the session open.
+			return true
+		}
+	}
+	return false
+}
+
+// TaskGraph returns the parent-child and dependency graph of a session's tasks.
+func (h *WSTaskHandler) TaskGraph(sessionID string) (any, error) {
+	return tasks.BuildGraph(h.pool.Store(), sessionID)
+}
+
+// TaskArtifacts lists a task's artifacts, or returns one artifact with its
+// content when name is set.
+func (h *WSTaskHandler) TaskArtifacts(taskID, name string) (any, error) {
+	if name == "" {
+		artifacts, err := h.pool.Store().ListArtifacts(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if artifacts == nil {
+			artifacts = []tasks.Artifact{}
+		}
+		return artifacts, nil
+	}
+
+	a, content, err := h.pool.Store().ReadArtifact(taskID, name)
+	if err != nil {
+		return nil, err
+	}
+	return taskArtifact{Artifact: *a, Content: content}, nil
+}
@@ -3,9 +3,13 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"filippo.io/age"
 	"github.com/coder/websocket"
@@ -18,17 +22,89 @@ import (
 
 // Client represents a connected WebSocket client.
 type Client struct {
-	conn      *websocket.Conn
-	send      chan []byte
-	hub       *Hub
+	conn     *websocket.Conn
+	send     chan []byte
+	hub      *Hub
+	encoding Encoding // negotiated at handshake
+
+	// sessionID is the bound session. Guarded by sessionMu: the client's
+	// goroutines and the idle reaper both access it.
+	sessionMu sync.RWMutex
 	sessionID string
+
+	// subscriptions are the event patterns pushed to the client
+	// (nil = every event). Guarded by hub.mu.
+	subscriptions []string
+}
+
+// session returns the session the client is bound to ("" = none).
+func (c *Client) session() string {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+	return c.sessionID
+}
+
+// bindSession binds the client to a session.
+func (c *Client) bindSession(id string) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.sessionID = id
+}
+
+// unbindSession unbinds the client if it is bound to id, reporting whether it was.
+func (c *Client) unbindSession(id string) bool {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	if c.sessionID != id {
+		return false
+	}
+	c.sessionID = ""
+	return true
 }
 
 // TaskHandler provides task operations for WS methods.
 type TaskHandler interface {
-	Submit(sessionID string, title, description string, tools []string, priority string) (string, error)
+	// Submit creates a task. A non-empty idempotencyKey returns the pending or
+	// running task of the session submitted with the same key, if any.
+	Submit(sessionID string, title, description string, tools []string, priority, idempotencyKey string) (string, error)
 	QueryTasks(taskID, sessionID string) (any, error)
 	Cancel(taskID string, reason string) error
+	// CancelSession cancels all pending/running tasks of a session and returns
+	// a summary of the cancelled tasks.
+	CancelSession(sessionID string, reason string) (any, error)
+	// TaskGraph returns the dependency/parent-child graph of a session's tasks.
+	TaskGraph(sessionID string) (any, error)
+	// TaskArtifacts lists a task's artifacts, or returns one with its content
+	// when name is set.
+	TaskArtifacts(taskID, name string) (any, error)
+	// HasActiveTasks reports whether a session has pending (incl. preempted)
+	// or running tasks.
+	HasActiveTasks(sessionID string) bool
+}
+
+// AdminHandler provides operational methods for WS admin requests.
+type AdminHandler interface {
+	// ReloadAuth re-reads .env and config and rebuilds the model clients whose
+	// credentials changed. Returns the rebuilt provider names.
+	ReloadAuth() ([]string, error)
+	// ToolStats returns per-tool invocation metrics.
+	ToolStats() any
+}
+
+// ModelHandler exposes the model registry for per-session model selection.
+type ModelHandler interface {
+	// ModelNames returns the configured provider names, sorted.
+	ModelNames() []string
+	// DefaultModel returns the default provider name.
+	DefaultModel() string
+	// ModelInfo returns the driver, model ID and endpoint of a provider.
+	ModelInfo(name string) (driver, model, endpoint string)
+}
+
+// SessionSearcher ranks sessions by content beyond the store's substring
+// search (e.g. semantic match when embeddings are enabled).
+type SessionSearcher interface {
+	Search(ctx context.Context, query string, limit int) ([]sessions.SearchResult, error)
 }
 
 // Hub manages WebSocket clients and bridges them to the event bus.
@@ -38,21 +114,29 @@ type Hub struct {
 	bus            events.EventBus
 	store          sessions.Store
 	tasks          TaskHandler
+	admin          AdminHandler
+	models         ModelHandler
+	searcher       SessionSearcher
 	perms          *conscience.ToolPermissions
 	unsubscribe    func()
 	recipient      *age.X25519Recipient // nil = encryption disabled
 	passwordTokens sync.Map             // token → bool
 	insecure       bool                 // skip origin check (dev mode)
+	confineAll     bool                 // sandbox.confine_interactive: every session is confined
+
+	activityMu   sync.Mutex
+	lastActivity map[string]time.Time // sessionID → last user message / task event
 }
 
 // NewHub creates a new WebSocket hub connected to an event bus.
 func NewHub(bus events.EventBus, store sessions.Store, perms *conscience.ToolPermissions, insecure bool) *Hub {
 	h := &Hub{
-		clients:  make(map[*Client]struct{}),
-		bus:      bus,
-		store:    store,
-		perms:    perms,
-		insecure: insecure,
+		clients:      make(map[*Client]struct{}),
+		bus:          bus,
+		store:        store,
+		perms:        perms,
+		insecure:     insecure,
+		lastActivity: make(map[string]time.Time),
 	}
 
 	// Track password prompt tokens for encryption
@@ -84,21 +168,19 @@ func NewHub(bus events.EventBus, store sessions.Store, perms *conscience.ToolPer
 
 	// Subscribe to all events and bridge to WS clients
 	h.unsubscribe = bus.Subscribe(func(e events.Event) {
+		h.trackActivity(e)
+
 		frame, err := NewEventFrame(string(e.Type), e.SessionID, e)
 		if err != nil {
 			slog.Error("marshal event frame", "error", err)
 			return
 		}
-		data, err := MarshalFrame(frame)
-		if err != nil {
-			slog.Error("marshal frame", "error", err)
-			return
-		}
+		out := newOutboundFrame(frame)
 
 		if e.SessionID != "" {
-			h.sendToSession(e.SessionID, data)
+			h.sendToSession(e.SessionID, out)
 		} else {
-			h.broadcast(data)
+			h.broadcast(out)
 		}
 	})
 
@@ -112,6 +194,43 @@ func (h *Hub) SetTaskHandler(th TaskHandler) {
 	h.tasks = th
 }
 
+// SetAdminHandler sets the optional handler for WS admin methods.
+func (h *Hub) SetAdminHandler(ah AdminHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.admin = ah
+}
+
+// SetModelHandler sets the optional handler for set_model.
+func (h *Hub) SetModelHandler(mh ModelHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.models = mh
+}
+
+// SetConfineInteractive makes every session confined to its root_dir
+// (sandbox.confine_interactive): sessions without one are rejected.
+func (h *Hub) SetConfineInteractive(on bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.confineAll = on
+}
+
+// requiresRoot reports whether a session opened with confined needs a root_dir.
+func (h *Hub) requiresRoot(confined bool) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return confined || h.confineAll
+}
+
+// SetSessionSearcher sets the optional searcher used by search_sessions.
+// Without one, sessions are searched by substring match.
+func (h *Hub) SetSessionSearcher(ss SessionSearcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.searcher = ss
+}
+
 // SetSecretEncryptor enables encryption for password prompt responses.
 func (h *Hub) SetSecretEncryptor(r *age.X25519Recipient) {
 	h.mu.Lock()
@@ -126,6 +245,27 @@ func (h *Hub) taskHandler() TaskHandler {
 	return h.tasks
 }
 
+// adminHandler returns the current admin handler (thread-safe).
+func (h *Hub) adminHandler() AdminHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.admin
+}
+
+// modelHandler returns the current model handler (thread-safe).
+func (h *Hub) modelHandler() ModelHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.models
+}
+
+// sessionSearcher returns the current session searcher (thread-safe).
+func (h *Hub) sessionSearcher() SessionSearcher {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.searcher
+}
+
 // secretRecipient returns the current encryption recipient (thread-safe).
 func (h *Hub) secretRecipient() *age.X25519Recipient {
 	h.mu.RLock()
@@ -133,12 +273,42 @@ func (h *Hub) secretRecipient() *age.X25519Recipient {
 	return h.recipient
 }
 
-// broadcast sends data to all connected clients.
-func (h *Hub) broadcast(data []byte) {
+// outboundFrame encodes a frame lazily, at most once per wire encoding.
+type outboundFrame struct {
+	frame   Frame
+	encoded map[Encoding][]byte
+}
+
+func newOutboundFrame(f Frame) *outboundFrame {
+	return &outboundFrame{frame: f, encoded: make(map[Encoding][]byte, 2)}
+}
+
+// bytes returns the frame encoded for enc, or nil if encoding failed.
+func (o *outboundFrame) bytes(enc Encoding) []byte {
+	if data, ok := o.encoded[enc]; ok {
+		return data
+	}
+	data, err := EncodeFrame(o.frame, enc)
+	if err != nil {
+		slog.Error("encode frame", "error", err, "encoding", enc)
+	}
+	o.encoded[enc] = data
+	return data
+}
+
+// broadcast sends a frame to all connected clients.
+func (h *Hub) broadcast(out *outboundFrame) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
 	for c := range h.clients {
+		if !c.wants(out.frame.Event) {
+			continue
+		}
+		data := out.bytes(c.encoding)
+		if data == nil {
+			continue
+		}
 		select {
 		case c.send <- data:
 		default:
@@ -147,13 +317,17 @@ func (h *Hub) broadcast(data []byte) {
 	}
 }
 
-// sendToSession sends data only to clients in a specific session.
-func (h *Hub) sendToSession(sessionID string, data []byte) {
+// sendToSession sends a frame only to clients in a specific session.
+func (h *Hub) sendToSession(sessionID string, out *outboundFrame) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
 	for c := range h.clients {
-		if c.sessionID == sessionID {
+		if c.session() == sessionID && c.wants(out.frame.Event) {
+			data := out.bytes(c.encoding)
+			if data == nil {
+				continue
+			}
 			select {
 			case c.send <- data:
 			default:
@@ -162,6 +336,13 @@ func (h *Hub) sendToSession(sessionID string, data []byte) {
 	}
 }
 
+// ClientCount returns the number of connected WS clients.
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
 // register adds a client to the hub.
 func (h *Hub) register(c *Client) {
 	h.mu.Lock()
@@ -179,7 +360,7 @@ func (h *Hub) unregisterClient(c *Client) {
 		return
 	}
 
-	sessionID := c.sessionID
+	sessionID := c.session()
 	delete(h.clients, c)
 	close(c.send)
 	slog.Info("ws client disconnected", "clients", len(h.clients), "session_id", sessionID)
@@ -188,7 +369,7 @@ func (h *Hub) unregisterClient(c *Client) {
 	if sessionID != "" {
 		lastClient := true
 		for other := range h.clients {
-			if other.sessionID == sessionID {
+			if other.session() == sessionID {
 				lastClient = false
 				break
 			}
@@ -213,7 +394,9 @@ func (h *Hub) unregisterClient(c *Client) {
 }
 
 // handleOpenSession creates or resumes a session for the client.
-func (h *Hub) handleOpenSession(c *Client, frameID string, sessionID string, rootDir string) {
+// confined opts the session into the RootDir jail; it is never lifted on resume.
+// project (default: derived from rootDir) only applies to new sessions.
+func (h *Hub) handleOpenSession(c *Client, frameID string, sessionID string, rootDir string, confined bool, project string) {
 	ctx := context.Background()
 
 	if sessionID != "" {
@@ -223,14 +406,27 @@ func (h *Hub) handleOpenSession(c *Client, frameID string, sessionID string, roo
 			c.sendError(ctx, frameID, "session not found: "+sessionID)
 			return
 		}
-		c.sessionID = s.ID
+		if (h.requiresRoot(confined) || s.Confined) && rootDir == "" && s.RootDir == "" {
+			c.sendError(ctx, frameID, sessions.ErrConfinedWithoutRoot.Error())
+			return
+		}
+		c.bindSession(s.ID)
+		h.touch(s.ID, time.Time{})
 
 		// Restore persisted tool approvals
 		h.restoreApprovedTools(s)
 
 		// Update root_dir if provided (client may have changed directory)
+		changed := false
 		if rootDir != "" && rootDir != s.RootDir {
 			s.RootDir = rootDir
+			changed = true
+		}
+		if confined && !s.Confined {
+			s.Confined = true
+			changed = true
+		}
+		if changed {
 			_ = h.store.UpdateMeta(s)
 		}
 
@@ -242,18 +438,29 @@ func (h *Hub) handleOpenSession(c *Client, frameID string, sessionID string, roo
 	}
 
 	// Create new session
+	if h.requiresRoot(confined) && rootDir == "" {
+		c.sendError(ctx, frameID, sessions.ErrConfinedWithoutRoot.Error())
+		return
+	}
 	s, err := h.store.Create()
 	if err != nil {
 		c.sendError(ctx, frameID, "create session: "+err.Error())
 		return
 	}
 
-	c.sessionID = s.ID
+	c.bindSession(s.ID)
 
-	// Store root_dir if provided
-	if rootDir != "" {
+	// Store root_dir and project if provided
+	if project == "" {
+		project = sessions.ProjectFromRootDir(rootDir)
+	}
+	if rootDir != "" || confined || project != "" {
 		s.RootDir = rootDir
-		_ = h.store.UpdateMeta(s)
+		s.Confined = confined
+		s.Project = project
+		if err := h.store.UpdateMeta(s); err != nil {
+			slog.Warn("open session: update meta", "session_id", s.ID, "error", err)
+		}
 	}
 
 	h.bus.Publish(events.NewEventWithSession(
@@ -279,7 +486,7 @@ func (h *Hub) restoreApprovedTools(s *sessions.Session) {
 
 // ensureSession auto-creates a session for a client if it doesn't have one.
 func (h *Hub) ensureSession(c *Client) {
-	if c.sessionID != "" {
+	if c.session() != "" {
 		return
 	}
 
@@ -289,7 +496,7 @@ func (h *Hub) ensureSession(c *Client) {
 		return
 	}
 
-	c.sessionID = s.ID
+	c.bindSession(s.ID)
 	slog.Info("auto-created session", "session_id", s.ID)
 
 	h.bus.Publish(events.NewEventWithSession(
@@ -300,7 +507,7 @@ func (h *Hub) ensureSession(c *Client) {
 
 // ServeWS handles a WebSocket upgrade and manages the client lifecycle.
 func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request) {
-	opts := &websocket.AcceptOptions{}
+	opts := &websocket.AcceptOptions{Subprotocols: Subprotocols}
 	if h.insecure {
 		opts.InsecureSkipVerify = true
 	} else {
@@ -313,9 +520,10 @@ func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &Client{
-		conn: conn,
-		send: make(chan []byte, 256),
-		hub:  h,
+		conn:     conn,
+		send:     make(chan []byte, 256),
+		hub:      h,
+		encoding: EncodingForSubprotocol(conn.Subprotocol()),
 	}
 
 	h.register(client)
@@ -333,7 +541,7 @@ func (c *Client) readPump(ctx context.Context) {
 	}()
 
 	for {
-		_, data, err := c.conn.Read(ctx)
+		typ, data, err := c.conn.Read(ctx)
 		if err != nil {
 			if websocket.CloseStatus(err) != -1 {
 				slog.Debug("ws read closed", "status", websocket.CloseStatus(err))
@@ -343,7 +551,7 @@ func (c *Client) readPump(ctx context.Context) {
 			return
 		}
 
-		frame, err := UnmarshalFrame(data)
+		frame, err := DecodeMessage(typ, data)
 		if err != nil {
 			slog.Error("ws unmarshal frame", "error", err)
 			continue
@@ -370,6 +578,8 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 		var params struct {
 			SessionID string `json:"session_id"`
 			RootDir   string `json:"root_dir"`
+			Confined  bool   `json:"confined"`
+			Project   string `json:"project"`
 		}
 		if frame.Params != nil {
 			if err := json.Unmarshal(frame.Params, &params); err != nil {
@@ -377,7 +587,7 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 				return
 			}
 		}
-		c.hub.handleOpenSession(c, frame.ID, params.SessionID, params.RootDir)
+		c.hub.handleOpenSession(c, frame.ID, params.SessionID, params.RootDir, params.Confined, params.Project)
 
 	case MethodSendMessage:
 		var params struct {
@@ -393,8 +603,23 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 
 		c.hub.bus.Publish(events.NewTypedEventWithSession(events.SourceWS, events.UserMessagePayload{
 			Content: params.Content,
-		}, c.sessionID))
+		}, c.session()))
+
+		c.sendOK(ctx, frame.ID, map[string]string{"status": "sent"})
 
+	case MethodInterrupt:
+		var params events.UserInterruptPayload
+		if frame.Params != nil {
+			if err := json.Unmarshal(frame.Params, &params); err != nil {
+				c.sendError(ctx, frame.ID, "invalid params")
+				return
+			}
+		}
+		if c.session() == "" {
+			c.sendError(ctx, frame.ID, "no session open")
+			return
+		}
+		c.hub.bus.Publish(events.NewTypedEventWithSession(events.SourceWS, params, c.session()))
 		c.sendOK(ctx, frame.ID, map[string]string{"status": "sent"})
 
 	case MethodPromptResponse:
@@ -417,7 +642,7 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 			}
 		}
 
-		c.hub.bus.Publish(events.NewTypedEventWithSession(events.SourceWS, params, c.sessionID))
+		c.hub.bus.Publish(events.NewTypedEventWithSession(events.SourceWS, params, c.session()))
 		c.sendOK(ctx, frame.ID, map[string]string{"status": "sent"})
 
 	case MethodSubmitTask:
@@ -429,10 +654,19 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 	case MethodCancelTask:
 		c.handleCancelTask(ctx, frame)
 
+	case MethodCancelSessionTasks:
+		c.handleCancelSessionTasks(ctx, frame)
+
+	case MethodTaskGraph:
+		c.handleTaskGraph(ctx, frame)
+
+	case MethodGetTaskArtifacts:
+		c.handleGetTaskArtifacts(ctx, frame)
+
 	case MethodAcceptAllTools:
 		c.hub.ensureSession(c)
-		if c.hub.perms != nil && c.sessionID != "" {
-			c.hub.perms.AllowAllForSession(c.sessionID)
+		if c.hub.perms != nil && c.session() != "" {
+			c.hub.perms.AllowAllForSession(c.session())
 		}
 		c.sendOK(ctx, frame.ID, map[string]string{"status": "accepted"})
 
@@ -446,11 +680,11 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 		if params.Limit <= 0 || params.Limit > 50 {
 			params.Limit = 10
 		}
-		if c.sessionID == "" {
+		if c.session() == "" {
 			c.sendError(ctx, frame.ID, "no session open")
 			return
 		}
-		msgs, err := c.hub.store.LoadMessages(c.sessionID)
+		msgs, err := c.hub.store.LoadMessages(c.session())
 		if err != nil {
 			c.sendError(ctx, frame.ID, "load messages: "+err.Error())
 			return
@@ -460,11 +694,255 @@ func (c *Client) handleRequest(ctx context.Context, frame Frame) {
 		}
 		c.sendOK(ctx, frame.ID, msgs)
 
+	case MethodGetAuditLog:
+		c.handleGetAuditLog(ctx, frame)
+
+	case MethodReloadAuth:
+		ah := c.hub.adminHandler()
+		if ah == nil {
+			c.sendError(ctx, frame.ID, "admin methods not available")
+			return
+		}
+		rotated, err := ah.ReloadAuth()
+		if err != nil {
+			c.sendError(ctx, frame.ID, "reload auth: "+err.Error())
+			return
+		}
+		if rotated == nil {
+			rotated = []string{}
+		}
+		c.sendOK(ctx, frame.ID, map[string]any{"status": "reloaded", "rotated": rotated})
+
+	case MethodToolStats:
+		ah := c.hub.adminHandler()
+		if ah == nil {
+			c.sendError(ctx, frame.ID, "admin methods not available")
+			return
+		}
+		c.sendOK(ctx, frame.ID, ah.ToolStats())
+
+	case MethodSetModel:
+		c.handleSetModel(ctx, frame)
+
+	case MethodSetPersona:
+		c.handleSetPersona(ctx, frame)
+
+	case MethodSearchSessions:
+		c.handleSearchSessions(ctx, frame)
+
+	case MethodSubscribe:
+		c.handleSubscribe(ctx, frame)
+
 	default:
 		c.sendError(ctx, frame.ID, "unknown method: "+frame.Method)
 	}
 }
 
+// Audit log page size bounds for get_audit_log.
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+func (c *Client) handleGetAuditLog(ctx context.Context, frame Frame) {
+	var params struct {
+		SessionID string `json:"session_id"`
+		Limit     int    `json:"limit"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+	if params.Limit <= 0 || params.Limit > maxAuditLimit {
+		params.Limit = defaultAuditLimit
+	}
+	if params.SessionID == "" {
+		params.SessionID = c.session()
+	}
+	if params.SessionID == "" {
+		c.sendError(ctx, frame.ID, "no session open")
+		return
+	}
+
+	entries, err := c.hub.store.LoadAudit(params.SessionID)
+	if err != nil {
+		c.sendError(ctx, frame.ID, "load audit log: "+err.Error())
+		return
+	}
+	if len(entries) > params.Limit {
+		entries = entries[len(entries)-params.Limit:]
+	}
+	if entries == nil {
+		entries = []sessions.AuditEntry{}
+	}
+	c.sendOK(ctx, frame.ID, entries)
+}
+
+// Result count bounds for search_sessions.
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 100
+)
+
+// handleSearchSessions finds past sessions by content, best matches first.
+func (c *Client) handleSearchSessions(ctx context.Context, frame Frame) {
+	var params struct {
+		Query   string `json:"query"`
+		Limit   int    `json:"limit"`
+		Project string `json:"project"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		c.sendError(ctx, frame.ID, "query is required")
+		return
+	}
+	if params.Limit <= 0 || params.Limit > maxSearchLimit {
+		params.Limit = defaultSearchLimit
+	}
+
+	var (
+		results []sessions.SearchResult
+		err     error
+	)
+	if ss := c.hub.sessionSearcher(); ss != nil {
+		results, err = ss.Search(ctx, params.Query, 0)
+	} else {
+		results, err = c.hub.store.Search(params.Query)
+	}
+	if err != nil {
+		c.sendError(ctx, frame.ID, "search sessions: "+err.Error())
+		return
+	}
+
+	out := []sessions.SearchResult{}
+	project := ""
+	if params.Project != "" {
+		if project = sessions.NormalizeProject(params.Project); project == "" {
+			project = sessions.DefaultProject
+		}
+	}
+	for _, r := range results {
+		if project != "" && r.Project != project {
+			continue
+		}
+		out = append(out, r)
+		if len(out) == params.Limit {
+			break
+		}
+	}
+	c.sendOK(ctx, frame.ID, out)
+}
+
+// handleSetModel switches the model used by the client's session. Without a
+// model name it only reports the current selection and available providers.
+func (c *Client) handleSetModel(ctx context.Context, frame Frame) {
+	mh := c.hub.modelHandler()
+	if mh == nil {
+		c.sendError(ctx, frame.ID, "model selection not available")
+		return
+	}
+
+	var params struct {
+		Model string `json:"model"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+
+	c.hub.ensureSession(c)
+	sess, err := c.hub.store.Get(c.session())
+	if err != nil {
+		c.sendError(ctx, frame.ID, "load session: "+err.Error())
+		return
+	}
+
+	names := mh.ModelNames()
+	defaultModel := mh.DefaultModel()
+
+	if params.Model != "" {
+		if !slices.Contains(names, params.Model) {
+			c.sendError(ctx, frame.ID, fmt.Sprintf("unknown model %q (available: %s)", params.Model, strings.Join(names, ", ")))
+			return
+		}
+		sess.Model = params.Model
+		if params.Model == defaultModel {
+			sess.Model = "" // back to the default: follow future default changes
+		}
+		if err := c.hub.store.UpdateMeta(sess); err != nil {
+			c.sendError(ctx, frame.ID, "update session: "+err.Error())
+			return
+		}
+	}
+
+	current := sess.Model
+	if current == "" {
+		current = defaultModel
+	}
+	driver, modelID, endpoint := mh.ModelInfo(current)
+	c.sendOK(ctx, frame.ID, map[string]any{
+		"model":    current,
+		"driver":   driver,
+		"model_id": modelID,
+		"endpoint": endpoint,
+		"default":  defaultModel,
+		"models":   names,
+	})
+}
+
+// maxPersonaLen bounds a session persona override (bytes).
+const maxPersonaLen = 8000
+
+// handleSetPersona sets or clears the per-session persona override. A missing
+// persona param only reports the current override; an empty one clears it.
+func (c *Client) handleSetPersona(ctx context.Context, frame Frame) {
+	var params struct {
+		Persona *string `json:"persona"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+
+	c.hub.ensureSession(c)
+	sess, err := c.hub.store.Get(c.session())
+	if err != nil {
+		c.sendError(ctx, frame.ID, "load session: "+err.Error())
+		return
+	}
+
+	status := "unchanged"
+	if params.Persona != nil {
+		persona := strings.TrimSpace(*params.Persona)
+		if len(persona) > maxPersonaLen {
+			c.sendError(ctx, frame.ID, fmt.Sprintf("persona too long (max %d bytes)", maxPersonaLen))
+			return
+		}
+		sess.Persona = persona
+		if err := c.hub.store.UpdateMeta(sess); err != nil {
+			c.sendError(ctx, frame.ID, "update session: "+err.Error())
+			return
+		}
+		status = "set"
+		if persona == "" {
+			status = "cleared"
+		}
+	}
+
+	c.sendOK(ctx, frame.ID, map[string]string{"status": status, "persona": sess.Persona})
+}
+
 func (c *Client) handleSubmitTask(ctx context.Context, frame Frame) {
 	th := c.hub.taskHandler()
 	if th == nil {
@@ -473,10 +951,11 @@ func (c *Client) handleSubmitTask(ctx context.Context, frame Frame) {
 	}
 
 	var params struct {
-		Title       string   `json:"title"`
-		Description string   `json:"description"`
-		Tools       []string `json:"tools"`
-		Priority    string   `json:"priority"`
+		Title          string   `json:"title"`
+		Description    string   `json:"description"`
+		Tools          []string `json:"tools"`
+		Priority       string   `json:"priority"`
+		IdempotencyKey string   `json:"idempotency_key"`
 	}
 	if err := json.Unmarshal(frame.Params, &params); err != nil {
 		c.sendError(ctx, frame.ID, "invalid params")
@@ -485,7 +964,7 @@ func (c *Client) handleSubmitTask(ctx context.Context, frame Frame) {
 
 	c.hub.ensureSession(c)
 
-	taskID, err := th.Submit(c.sessionID, params.Title, params.Description, params.Tools, params.Priority)
+	taskID, err := th.Submit(c.session(), params.Title, params.Description, params.Tools, params.Priority, params.IdempotencyKey)
 	if err != nil {
 		c.sendError(ctx, frame.ID, err.Error())
 		return
@@ -519,7 +998,7 @@ func (c *Client) handleQueryTasks(ctx context.Context, frame Frame) {
 	}
 	// Backward compat: legacy list_tasks uses client session
 	if Method(frame.Method) == MethodListTasks && params.SessionID == "" {
-		params.SessionID = c.sessionID
+		params.SessionID = c.session()
 	}
 
 	result, err := th.QueryTasks(params.TaskID, params.SessionID)
@@ -555,6 +1034,96 @@ func (c *Client) handleCancelTask(ctx context.Context, frame Frame) {
 	c.sendOK(ctx, frame.ID, map[string]string{"task_id": params.TaskID, "status": "cancelled"})
 }
 
+func (c *Client) handleCancelSessionTasks(ctx context.Context, frame Frame) {
+	th := c.hub.taskHandler()
+	if th == nil {
+		c.sendError(ctx, frame.ID, "task system not available")
+		return
+	}
+
+	var params struct {
+		SessionID string `json:"session_id"`
+		Reason    string `json:"reason"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+	if params.SessionID == "" {
+		c.hub.ensureSession(c)
+		params.SessionID = c.session()
+	}
+
+	cancelled, err := th.CancelSession(params.SessionID, params.Reason)
+	if err != nil {
+		c.sendError(ctx, frame.ID, err.Error())
+		return
+	}
+
+	c.sendOK(ctx, frame.ID, map[string]any{"session_id": params.SessionID, "cancelled": cancelled})
+}
+
+func (c *Client) handleTaskGraph(ctx context.Context, frame Frame) {
+	th := c.hub.taskHandler()
+	if th == nil {
+		c.sendError(ctx, frame.ID, "task system not available")
+		return
+	}
+
+	var params struct {
+		SessionID string `json:"session_id"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+	if params.SessionID == "" {
+		c.hub.ensureSession(c)
+		params.SessionID = c.session()
+	}
+
+	graph, err := th.TaskGraph(params.SessionID)
+	if err != nil {
+		c.sendError(ctx, frame.ID, err.Error())
+		return
+	}
+
+	c.sendOK(ctx, frame.ID, graph)
+}
+
+func (c *Client) handleGetTaskArtifacts(ctx context.Context, frame Frame) {
+	th := c.hub.taskHandler()
+	if th == nil {
+		c.sendError(ctx, frame.ID, "task system not available")
+		return
+	}
+
+	var params struct {
+		TaskID string `json:"task_id"`
+		Name   string `json:"name"`
+	}
+	if err := json.Unmarshal(frame.Params, &params); err != nil {
+		c.sendError(ctx, frame.ID, "invalid params")
+		return
+	}
+	if params.TaskID == "" {
+		c.sendError(ctx, frame.ID, "task_id required")
+		return
+	}
+
+	result, err := th.TaskArtifacts(params.TaskID, params.Name)
+	if err != nil {
+		c.sendError(ctx, frame.ID, err.Error())
+		return
+	}
+
+	c.sendOK(ctx, frame.ID, result)
+}
+
 // writePump writes queued messages to the WS connection.
 func (c *Client) writePump(ctx context.Context) {
 	for {
@@ -563,7 +1132,7 @@ func (c *Client) writePump(ctx context.Context) {
 			if !ok {
 				return
 			}
-			if err := c.conn.Write(ctx, websocket.MessageText, msg); err != nil {
+			if err := c.conn.Write(ctx, c.encoding.MessageType(), msg); err != nil {
 				return
 			}
 		case <-ctx.Done():
@@ -577,7 +1146,7 @@ func (c *Client) sendOK(ctx context.Context, id string, payload any) {
 	if err != nil {
 		return
 	}
-	data, err := MarshalFrame(f)
+	data, err := EncodeFrame(f, c.encoding)
 	if err != nil {
 		return
 	}
@@ -592,7 +1161,7 @@ func (c *Client) sendError(ctx context.Context, id string, errMsg string) {
 	if err != nil {
 		return
 	}
-	data, err := MarshalFrame(f)
+	data, err := EncodeFrame(f, c.encoding)
 	if err != nil {
 		return
 	}
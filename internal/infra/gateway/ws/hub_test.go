@@ -0,0 +1,178 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// lastResponse decodes the latest frame queued for a client.
+func lastResponse(t *testing.T, c *Client) Frame {
+	t.Helper()
+	var f Frame
+	for {
+		select {
+		case data := <-c.send:
+			var err error
+			if f, err = DecodeFrame(data, c.encoding); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			return f
+		}
+	}
+}
+
+func TestHub_OpenSession_ConfinedWithoutRoot(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+	store := sessions.NewFileStore(t.TempDir())
+	h := NewHub(bus, store, conscience.NewToolPermissions(nil), true)
+	defer h.Close()
+	c := &Client{send: make(chan []byte, 16), hub: h, encoding: EncodingJSON}
+
+	h.handleOpenSession(c, "1", "", "", true, "")
+	if f := lastResponse(t, c); f.OK == nil || *f.OK || f.Error != sessions.ErrConfinedWithoutRoot.Error() {
+		t.Fatalf("expected confined session without root_dir to be rejected, got %+v", f)
+	}
+	if list, _ := store.List(); len(list) != 0 {
+		t.Fatalf("no session should be created, got %d", len(list))
+	}
+
+	// Confining an existing session without a root_dir is rejected too.
+	s, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.handleOpenSession(c, "2", s.ID, "", true, "")
+	if f := lastResponse(t, c); f.OK == nil || *f.OK {
+		t.Fatalf("expected resume with confined and no root_dir to be rejected, got %+v", f)
+	}
+
+	h.handleOpenSession(c, "3", "", t.TempDir(), true, "")
+	if f := lastResponse(t, c); f.OK == nil || !*f.OK {
+		t.Fatalf("expected confined session with root_dir to open, got %+v", f)
+	}
+}
+
+func TestHub_OpenSession_ConfineInteractiveRequiresRoot(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+	store := sessions.NewFileStore(t.TempDir())
+	h := NewHub(bus, store, conscience.NewToolPermissions(nil), true)
+	defer h.Close()
+	h.SetConfineInteractive(true)
+	c := &Client{send: make(chan []byte, 16), hub: h, encoding: EncodingJSON}
+
+	h.handleOpenSession(c, "1", "", "", false, "")
+	if f := lastResponse(t, c); f.OK == nil || *f.OK || f.Error != sessions.ErrConfinedWithoutRoot.Error() {
+		t.Fatalf("expected session without root_dir to be rejected, got %+v", f)
+	}
+
+	s, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.handleOpenSession(c, "2", s.ID, "", false, "")
+	if f := lastResponse(t, c); f.OK == nil || *f.OK {
+		t.Fatalf("expected resume without root_dir to be rejected, got %+v", f)
+	}
+	if id := c.session(); id != "" {
+		t.Fatalf("rejected resume must not bind the client, got %q", id)
+	}
+
+	h.handleOpenSession(c, "3", s.ID, t.TempDir(), false, "")
+	if f := lastResponse(t, c); f.OK == nil || !*f.OK {
+		t.Fatalf("expected resume with root_dir to open, got %+v", f)
+	}
+}
+
+// modelsStub is a static ModelHandler.
+type modelsStub struct{}
+
+func (modelsStub) ModelNames() []string { return []string{"claude", "local"} }
+func (modelsStub) DefaultModel() string { return "claude" }
+func (modelsStub) ModelInfo(name string) (string, string, string) {
+	if name == "local" {
+		return "ollama", "llama3.1", "http://localhost:11434"
+	}
+	return "anthropic", "claude-sonnet-4", ""
+}
+
+func TestHub_SetModel(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+	store := sessions.NewFileStore(t.TempDir())
+	h := NewHub(bus, store, conscience.NewToolPermissions(nil), true)
+	defer h.Close()
+	h.SetModelHandler(modelsStub{})
+	s, err := store.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{send: make(chan []byte, 16), hub: h, encoding: EncodingJSON}
+	c.bindSession(s.ID)
+
+	tests := []struct {
+		name       string
+		params     string
+		wantErr    bool
+		wantModel  string
+		wantDriver string
+		wantStored string
+	}{
+		{"report default", `{}`, false, "claude", "anthropic", ""},
+		{"switch", `{"model":"local"}`, false, "local", "ollama", "local"},
+		{"report override", `{}`, false, "local", "ollama", "local"},
+		{"unknown model", `{"model":"gpt"}`, true, "", "", "local"},
+		{"back to default clears override", `{"model":"claude"}`, false, "claude", "anthropic", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.handleSetModel(context.Background(), Frame{ID: "1", Params: json.RawMessage(tt.params)})
+			f := lastResponse(t, c)
+			if f.OK == nil || *f.OK == tt.wantErr {
+				t.Fatalf("unexpected response %+v", f)
+			}
+			if !tt.wantErr {
+				var sel struct {
+					Model    string   `json:"model"`
+					Driver   string   `json:"driver"`
+					ModelID  string   `json:"model_id"`
+					Endpoint string   `json:"endpoint"`
+					Models   []string `json:"models"`
+				}
+				if err := json.Unmarshal(f.Payload, &sel); err != nil {
+					t.Fatal(err)
+				}
+				if sel.Model != tt.wantModel || sel.Driver != tt.wantDriver || sel.ModelID == "" || len(sel.Models) != 2 {
+					t.Errorf("selection = %+v, want model %q driver %q", sel, tt.wantModel, tt.wantDriver)
+				}
+			}
+			got, err := store.Get(s.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Model != tt.wantStored {
+				t.Errorf("stored override = %q, want %q", got.Model, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestHub_SetModelUnavailable(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+	h := NewHub(bus, sessions.NewFileStore(t.TempDir()), conscience.NewToolPermissions(nil), true)
+	defer h.Close()
+	c := &Client{send: make(chan []byte, 16), hub: h, encoding: EncodingJSON}
+
+	c.handleSetModel(context.Background(), Frame{ID: "1"})
+	if f := lastResponse(t, c); f.OK == nil || *f.OK {
+		t.Fatalf("expected an error without a model handler, got %+v", f)
+	}
+}
@@ -0,0 +1,120 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// trackActivity records the last activity of a session: user messages,
+// session creation and task events. A closed session is forgotten.
+func (h *Hub) trackActivity(e events.Event) {
+	if e.SessionID == "" {
+		return
+	}
+	switch {
+	case e.Type == events.EventSessionClosed:
+		h.activityMu.Lock()
+		delete(h.lastActivity, e.SessionID)
+		h.activityMu.Unlock()
+	case e.Type == events.EventUserMessage, e.Type == events.EventSessionCreated,
+		strings.HasPrefix(string(e.Type), "task."):
+		h.touch(e.SessionID, e.Timestamp)
+	}
+}
+
+// touch marks a session active at t (now when zero).
+func (h *Hub) touch(sessionID string, t time.Time) {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	h.activityMu.Lock()
+	defer h.activityMu.Unlock()
+	if t.After(h.lastActivity[sessionID]) {
+		h.lastActivity[sessionID] = t
+	}
+}
+
+// StartIdleReaper closes sessions idle for longer than timeout until ctx is
+// done. Sessions already open in the store start from their last update.
+func (h *Hub) StartIdleReaper(ctx context.Context, timeout time.Duration) {
+	if list, err := h.store.List(); err == nil {
+		for _, s := range list {
+			if s.Status == sessions.SessionActive {
+				h.touch(s.ID, s.UpdatedAt)
+			}
+		}
+	}
+
+	interval := min(max(timeout/4, time.Second), time.Minute)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				h.reapIdle(now, timeout)
+			}
+		}
+	}()
+}
+
+// reapIdle closes the sessions whose last activity is older than timeout.
+// Sessions with pending or running tasks are exempt and count as active.
+func (h *Hub) reapIdle(now time.Time, timeout time.Duration) {
+	var idle []string
+	h.activityMu.Lock()
+	for id, last := range h.lastActivity {
+		if now.Sub(last) >= timeout {
+			idle = append(idle, id)
+		}
+	}
+	h.activityMu.Unlock()
+
+	th := h.taskHandler()
+	for _, id := range idle {
+		if th != nil && th.HasActiveTasks(id) {
+			h.touch(id, now)
+			continue
+		}
+		h.closeIdleSession(id)
+	}
+}
+
+// closeIdleSession disconnects the session's clients, closes it in the store
+// and releases its tool permissions. No task can be using them any more.
+func (h *Hub) closeIdleSession(sessionID string) {
+	h.mu.Lock()
+	for c := range h.clients {
+		// Unbind first so unregisterClient does not close it again.
+		if c.unbindSession(sessionID) {
+			go c.conn.Close(websocket.StatusNormalClosure, "session closed: idle timeout")
+		}
+	}
+	h.mu.Unlock()
+
+	h.activityMu.Lock()
+	delete(h.lastActivity, sessionID)
+	h.activityMu.Unlock()
+
+	if err := h.store.Close(sessionID); err != nil {
+		slog.Error("close idle session", "error", err, "session_id", sessionID)
+		return
+	}
+	if h.perms != nil {
+		h.perms.CleanupSession(sessionID)
+	}
+	slog.Info("idle session closed", "session_id", sessionID)
+	h.bus.Publish(events.NewEventWithSession(
+		events.EventSessionClosed, events.SourceHub,
+		map[string]any{"session_id": sessionID, "reason": "idle"}, sessionID,
+	))
+}
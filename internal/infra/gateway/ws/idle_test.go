@@ -0,0 +1,102 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// stubTasks reports a fixed HasActiveTasks answer; other methods are unused.
+type stubTasks struct {
+	TaskHandler
+	active bool
+}
+
+func (s stubTasks) HasActiveTasks(string) bool { return s.active }
+
+func TestHub_ReapIdle(t *testing.T) {
+	tests := []struct {
+		name       string
+		age        time.Duration
+		active     bool
+		wantClosed bool
+	}{
+		{"idle", 2 * time.Hour, false, true},
+		{"recent", time.Minute, false, false},
+		{"idle with active tasks", 2 * time.Hour, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := events.NewBus(64)
+			defer bus.Close()
+			store := sessions.NewFileStore(t.TempDir())
+			perms := conscience.NewToolPermissions(nil)
+			h := NewHub(bus, store, perms, true)
+			defer h.Close()
+			h.SetTaskHandler(stubTasks{active: tt.active})
+
+			closed, unsub := bus.SubscribeChan(4, events.EventSessionClosed)
+			defer unsub()
+
+			s, err := store.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+			perms.AllowAllForSession(s.ID)
+			now := time.Now()
+			h.touch(s.ID, now.Add(-tt.age))
+
+			h.reapIdle(now, time.Hour)
+
+			got, err := store.Get(s.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isClosed := got.Status == sessions.SessionClosed; isClosed != tt.wantClosed {
+				t.Fatalf("session closed = %v, want %v", isClosed, tt.wantClosed)
+			}
+			if perms.IsSessionAcceptAll(s.ID) == tt.wantClosed {
+				t.Errorf("permissions released = %v, want %v", !perms.IsSessionAcceptAll(s.ID), tt.wantClosed)
+			}
+			if tt.wantClosed {
+				select {
+				case e := <-closed:
+					if e.SessionID != s.ID {
+						t.Errorf("session.closed for %q, want %q", e.SessionID, s.ID)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("missing session.closed event")
+				}
+			}
+		})
+	}
+}
+
+// The reaper unbinds clients while their goroutines read the session:
+// run with -race.
+func TestClient_UnbindSessionConcurrent(t *testing.T) {
+	c := &Client{}
+	c.bindSession("s1")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 1000 {
+			_ = c.session()
+		}
+	}()
+	if c.unbindSession("other") {
+		t.Error("unbinding another session should be a no-op")
+	}
+	if !c.unbindSession("s1") {
+		t.Error("expected the client to be unbound")
+	}
+	<-done
+	if id := c.session(); id != "" {
+		t.Errorf("session = %q after unbind", id)
+	}
+}
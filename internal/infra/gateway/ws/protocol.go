@@ -1,6 +1,12 @@
 package ws
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coder/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
 
 // FrameType represents the type of WebSocket frame.
 type FrameType string
@@ -16,17 +22,28 @@ type Method string
 
 const (
 	MethodSendMessage    Method = "send_message"
+	MethodInterrupt      Method = "interrupt"
 	MethodOpenSession    Method = "open_session"
 	MethodPromptResponse Method = "prompt_response"
 	MethodSubmitTask     Method = "submit_task"
 	MethodQueryTasks     Method = "query_tasks"
 	MethodCancelTask     Method = "cancel_task"
+	MethodCancelSessionTasks Method = "cancel_session_tasks"
+	MethodTaskGraph          Method = "task_graph"
+	MethodGetTaskArtifacts   Method = "get_task_artifacts"
 
 	// Deprecated: kept for backward compatibility with older clients.
 	MethodCheckTask Method = "check_task"
 	MethodListTasks Method = "list_tasks"
 	MethodAcceptAllTools Method = "accept_all_tools"
 	MethodLoadMessages   Method = "load_messages"
+	MethodGetAuditLog    Method = "get_audit_log"
+	MethodReloadAuth     Method = "reload_auth"
+	MethodToolStats      Method = "tool_stats"
+	MethodSetModel       Method = "set_model"
+	MethodSetPersona     Method = "set_persona"
+	MethodSearchSessions Method = "search_sessions"
+	MethodSubscribe      Method = "subscribe"
 )
 
 // Frame is the WebSocket protocol envelope.
@@ -54,6 +71,135 @@ func UnmarshalFrame(data []byte) (Frame, error) {
 	return f, err
 }
 
+// Encoding identifies the wire encoding of frames on a connection.
+type Encoding string
+
+const (
+	// EncodingJSON is the default encoding: one JSON object per text message.
+	EncodingJSON Encoding = "json"
+	// EncodingMsgpack is a compact binary encoding: one MessagePack array per
+	// binary message. Params and payloads are carried as raw JSON bytes so
+	// they can be skipped without scanning.
+	EncodingMsgpack Encoding = "msgpack"
+)
+
+// WebSocket subprotocols used to negotiate the frame encoding at handshake.
+const (
+	SubprotocolJSON    = "ozzie.json.v1"
+	SubprotocolMsgpack = "ozzie.msgpack.v1"
+)
+
+// Subprotocols lists the subprotocols accepted by the gateway, by preference.
+var Subprotocols = []string{SubprotocolMsgpack, SubprotocolJSON}
+
+// ParseEncoding converts a user-supplied name into an Encoding.
+func ParseEncoding(name string) (Encoding, error) {
+	switch Encoding(name) {
+	case "", EncodingJSON:
+		return EncodingJSON, nil
+	case EncodingMsgpack:
+		return EncodingMsgpack, nil
+	default:
+		return "", fmt.Errorf("unknown frame encoding %q (want json or msgpack)", name)
+	}
+}
+
+// EncodingForSubprotocol maps a negotiated subprotocol to its encoding.
+// An empty or unknown subprotocol falls back to JSON.
+func EncodingForSubprotocol(subprotocol string) Encoding {
+	if subprotocol == SubprotocolMsgpack {
+		return EncodingMsgpack
+	}
+	return EncodingJSON
+}
+
+// Subprotocol returns the WebSocket subprotocol advertising this encoding.
+func (e Encoding) Subprotocol() string {
+	if e == EncodingMsgpack {
+		return SubprotocolMsgpack
+	}
+	return SubprotocolJSON
+}
+
+// MessageType returns the WebSocket message type carrying this encoding.
+func (e Encoding) MessageType() websocket.MessageType {
+	if e == EncodingMsgpack {
+		return websocket.MessageBinary
+	}
+	return websocket.MessageText
+}
+
+// msgpackFrame is the binary wire form of a Frame, encoded as a fixed-order
+// array. New fields must only be appended.
+type msgpackFrame struct {
+	_msgpack  struct{} `msgpack:",as_array"`
+	Type      FrameType
+	ID        string
+	Method    string
+	Params    []byte
+	OK        *bool
+	Payload   []byte
+	Error     string
+	Event     string
+	SessionID string
+}
+
+// EncodeFrame serializes a Frame using the given encoding.
+func EncodeFrame(f Frame, enc Encoding) ([]byte, error) {
+	if enc != EncodingMsgpack {
+		return MarshalFrame(f)
+	}
+	return msgpack.Marshal(&msgpackFrame{
+		Type:      f.Type,
+		ID:        f.ID,
+		Method:    f.Method,
+		Params:    f.Params,
+		OK:        f.OK,
+		Payload:   f.Payload,
+		Error:     f.Error,
+		Event:     f.Event,
+		SessionID: f.SessionID,
+	})
+}
+
+// DecodeFrame deserializes bytes produced by EncodeFrame with the same encoding.
+func DecodeFrame(data []byte, enc Encoding) (Frame, error) {
+	if enc != EncodingMsgpack {
+		return UnmarshalFrame(data)
+	}
+	var mf msgpackFrame
+	if err := msgpack.Unmarshal(data, &mf); err != nil {
+		return Frame{}, err
+	}
+	return Frame{
+		Type:      mf.Type,
+		ID:        mf.ID,
+		Method:    mf.Method,
+		Params:    rawJSON(mf.Params),
+		OK:        mf.OK,
+		Payload:   rawJSON(mf.Payload),
+		Error:     mf.Error,
+		Event:     mf.Event,
+		SessionID: mf.SessionID,
+	}, nil
+}
+
+// DecodeMessage decodes a frame based on the WebSocket message type:
+// binary messages are MessagePack, text messages are JSON.
+func DecodeMessage(typ websocket.MessageType, data []byte) (Frame, error) {
+	if typ == websocket.MessageBinary {
+		return DecodeFrame(data, EncodingMsgpack)
+	}
+	return DecodeFrame(data, EncodingJSON)
+}
+
+func rawJSON(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.RawMessage(b)
+}
+
 // NewEventFrame creates a Frame for broadcasting an event.
 func NewEventFrame(event string, sessionID string, payload any) (Frame, error) {
 	data, err := json.Marshal(payload)
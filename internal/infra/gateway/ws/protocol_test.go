@@ -3,6 +3,8 @@ package ws
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/coder/websocket"
 )
 
 func TestMarshalUnmarshal_RequestFrame(t *testing.T) {
@@ -167,3 +169,125 @@ func TestNewResponseFrame_Error(t *testing.T) {
 		t.Fatalf("expected nil payload, got %s", string(f.Payload))
 	}
 }
+
+func TestEncodeDecodeFrame_Msgpack(t *testing.T) {
+	ok := false
+	params, _ := json.Marshal(map[string]string{"content": "hello"})
+	orig := Frame{
+		Type:      FrameTypeResponse,
+		ID:        "req-7",
+		Method:    string(MethodSendMessage),
+		Params:    params,
+		OK:        &ok,
+		Error:     "boom",
+		Event:     "assistant.stream",
+		SessionID: "sess_abc",
+	}
+
+	data, err := EncodeFrame(orig, EncodingMsgpack)
+	if err != nil {
+		t.Fatalf("EncodeFrame: %v", err)
+	}
+
+	got, err := DecodeMessage(websocket.MessageBinary, data)
+	if err != nil {
+		t.Fatalf("DecodeMessage: %v", err)
+	}
+
+	if got.Type != orig.Type || got.ID != orig.ID || got.Method != orig.Method {
+		t.Fatalf("envelope mismatch: %+v", got)
+	}
+	if got.Error != "boom" || got.Event != "assistant.stream" || got.SessionID != "sess_abc" {
+		t.Fatalf("envelope mismatch: %+v", got)
+	}
+	if got.OK == nil || *got.OK {
+		t.Fatal("expected ok=false")
+	}
+	if string(got.Params) != string(params) {
+		t.Fatalf("expected params %s, got %s", params, got.Params)
+	}
+	if got.Payload != nil {
+		t.Fatalf("expected nil payload, got %s", string(got.Payload))
+	}
+}
+
+func TestDecodeMessage_TextIsJSON(t *testing.T) {
+	data, err := EncodeFrame(Frame{Type: FrameTypeEvent, Event: "user.message"}, EncodingJSON)
+	if err != nil {
+		t.Fatalf("EncodeFrame: %v", err)
+	}
+
+	got, err := DecodeMessage(websocket.MessageText, data)
+	if err != nil {
+		t.Fatalf("DecodeMessage: %v", err)
+	}
+	if got.Event != "user.message" {
+		t.Fatalf("expected event %q, got %q", "user.message", got.Event)
+	}
+}
+
+func TestEncodingForSubprotocol(t *testing.T) {
+	tests := []struct {
+		subprotocol string
+		want        Encoding
+	}{
+		{"", EncodingJSON},
+		{SubprotocolJSON, EncodingJSON},
+		{SubprotocolMsgpack, EncodingMsgpack},
+		{"unknown", EncodingJSON},
+	}
+	for _, tt := range tests {
+		if got := EncodingForSubprotocol(tt.subprotocol); got != tt.want {
+			t.Errorf("EncodingForSubprotocol(%q) = %q, want %q", tt.subprotocol, got, tt.want)
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	if enc, err := ParseEncoding(""); err != nil || enc != EncodingJSON {
+		t.Fatalf("expected json default, got %q (%v)", enc, err)
+	}
+	if enc, err := ParseEncoding("msgpack"); err != nil || enc != EncodingMsgpack {
+		t.Fatalf("expected msgpack, got %q (%v)", enc, err)
+	}
+	if _, err := ParseEncoding("cbor"); err == nil {
+		t.Fatal("expected error for unknown encoding")
+	}
+}
+
+// streamDeltaFrame mirrors what the hub sends for each streamed token.
+func streamDeltaFrame(b *testing.B) Frame {
+	b.Helper()
+	f, err := NewEventFrame("assistant.stream", "sess_cosmic_asimov", map[string]any{
+		"type":       "assistant.stream",
+		"source":     "agent",
+		"session_id": "sess_cosmic_asimov",
+		"timestamp":  "2026-01-01T12:00:00.000000000Z",
+		"payload":    map[string]any{"phase": "delta", "content": "Hello", "index": 42},
+	})
+	if err != nil {
+		b.Fatalf("NewEventFrame: %v", err)
+	}
+	return f
+}
+
+func benchmarkStreamDelta(b *testing.B, enc Encoding) {
+	f := streamDeltaFrame(b)
+	typ := enc.MessageType()
+	b.ReportAllocs()
+	var size int
+	for b.Loop() {
+		data, err := EncodeFrame(f, enc)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := DecodeMessage(typ, data); err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.ReportMetric(float64(size), "bytes/frame")
+}
+
+func BenchmarkStreamDelta_JSON(b *testing.B)    { benchmarkStreamDelta(b, EncodingJSON) }
+func BenchmarkStreamDelta_Msgpack(b *testing.B) { benchmarkStreamDelta(b, EncodingMsgpack) }
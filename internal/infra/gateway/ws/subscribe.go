@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+// maxSubscriptions bounds the event patterns a client can subscribe to.
+const maxSubscriptions = 64
+
+// wants reports whether the client subscribed to an event type. A client
+// that never called subscribe receives every event. Callers hold h.mu.
+func (c *Client) wants(eventType string) bool {
+	if c.subscriptions == nil {
+		return true
+	}
+	for _, p := range c.subscriptions {
+		if events.MatchPattern(p, events.EventType(eventType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSubscribe replaces the event types pushed to the client. Patterns
+// follow events.MatchPattern ("task.*", "*"); an empty list or "*" restores
+// the default of receiving every event.
+func (c *Client) handleSubscribe(ctx context.Context, frame Frame) {
+	var params struct {
+		Events []string `json:"events"`
+	}
+	if frame.Params != nil {
+		if err := json.Unmarshal(frame.Params, &params); err != nil {
+			c.sendError(ctx, frame.ID, "invalid params")
+			return
+		}
+	}
+	if len(params.Events) > maxSubscriptions {
+		c.sendError(ctx, frame.ID, fmt.Sprintf("too many event patterns (max %d)", maxSubscriptions))
+		return
+	}
+
+	var subs []string
+	for _, p := range params.Events {
+		if p == "" {
+			c.sendError(ctx, frame.ID, "empty event pattern")
+			return
+		}
+		if p == "*" {
+			subs = nil
+			break
+		}
+		if !slices.Contains(subs, p) {
+			subs = append(subs, p)
+		}
+	}
+
+	c.hub.mu.Lock()
+	c.subscriptions = subs
+	c.hub.mu.Unlock()
+
+	if subs == nil {
+		subs = []string{"*"}
+	}
+	c.sendOK(ctx, frame.ID, map[string]any{"events": subs})
+}
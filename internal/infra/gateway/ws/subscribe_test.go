@@ -0,0 +1,72 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// receivedEvents drains the event frames queued for a client.
+func receivedEvents(t *testing.T, c *Client) []string {
+	t.Helper()
+	var got []string
+	for {
+		select {
+		case data := <-c.send:
+			f, err := DecodeFrame(data, c.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.Type == FrameTypeEvent {
+				got = append(got, f.Event)
+			}
+		default:
+			return got
+		}
+	}
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+	h := NewHub(bus, sessions.NewFileStore(t.TempDir()), conscience.NewToolPermissions(nil), true)
+	defer h.Close()
+
+	tui := &Client{send: make(chan []byte, 16), hub: h, sessionID: "s1", encoding: EncodingJSON}
+	dashboard := &Client{send: make(chan []byte, 16), hub: h, sessionID: "s1", encoding: EncodingJSON}
+	h.register(tui)
+	h.register(dashboard)
+	// The clients have no connection: remove them before the hub closes it.
+	defer h.unregisterClient(dashboard)
+	defer h.unregisterClient(tui)
+
+	params, _ := json.Marshal(map[string][]string{"events": {"task.*", "tool.recovery"}})
+	dashboard.handleSubscribe(context.Background(), Frame{ID: "1", Params: params})
+	<-dashboard.send // subscribe response
+
+	for _, typ := range []string{"assistant.stream", "task.completed", "tool.recovery"} {
+		frame, _ := NewEventFrame(typ, "s1", nil)
+		h.sendToSession("s1", newOutboundFrame(frame))
+	}
+
+	if got := receivedEvents(t, tui); len(got) != 3 {
+		t.Errorf("unsubscribed client should receive every event, got %v", got)
+	}
+	if got := receivedEvents(t, dashboard); len(got) != 2 || got[0] != "task.completed" || got[1] != "tool.recovery" {
+		t.Errorf("subscribed client got %v, want task.completed and tool.recovery", got)
+	}
+
+	// "*" restores all events.
+	params, _ = json.Marshal(map[string][]string{"events": {"*"}})
+	dashboard.handleSubscribe(context.Background(), Frame{ID: "2", Params: params})
+	<-dashboard.send
+	frame, _ := NewEventFrame("assistant.stream", "", nil)
+	h.broadcast(newOutboundFrame(frame))
+	if got := receivedEvents(t, dashboard); len(got) != 1 {
+		t.Errorf("client should receive every event again, got %v", got)
+	}
+}
@@ -3,6 +3,9 @@ package hands
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	extism "github.com/extism/go-sdk"
 
@@ -19,9 +22,11 @@ import (
 type PluginCapabilities struct {
 	HTTP       bool                `json:"http,omitempty"`
 	KV         bool                `json:"kv,omitempty"`
+	KVOptions  *KVCapabilityIntent `json:"-"` // set by the object form of "kv"; nil = in-memory, shared, unbounded
 	Log        bool                `json:"log,omitempty"`
 	Filesystem *FSCapabilityIntent `json:"filesystem,omitempty"` // nil = not needed
 	Secrets    []string            `json:"secrets,omitempty"`
+	Env        []string            `json:"env,omitempty"` // config names the guest reads with pdk.GetConfig
 	Exec       bool                `json:"exec,omitempty"`
 	Elevated   bool                `json:"elevated,omitempty"`
 }
@@ -31,16 +36,28 @@ type FSCapabilityIntent struct {
 	ReadOnly bool `json:"read_only,omitempty"` // intrinsic constraint of the plugin
 }
 
+// KVCapabilityIntent configures a plugin's key-value store, used by the
+// ozzie.kv_* host functions; the Extism vars (pdk.GetVar/SetVar) are loaded
+// from it before each call and their changes merged back after.
+type KVCapabilityIntent struct {
+	Persistent    bool `json:"persistent,omitempty"`      // survive gateway restarts ($OZZIE_PATH/plugin_data/<plugin>/kv.json)
+	PerTool       bool `json:"per_tool,omitempty"`        // one namespace per tool instead of one per plugin
+	MaxKeys       int  `json:"max_keys,omitempty"`        // per namespace; 0 = unlimited
+	MaxValueBytes int  `json:"max_value_bytes,omitempty"` // 0 = unlimited
+}
+
 // UnmarshalJSON supports both `"filesystem": true` (shorthand for read-write)
-// and `"filesystem": {"read_only": true}` (explicit).
+// and `"filesystem": {"read_only": true}` (explicit), and likewise
+// `"kv": true` and `"kv": {"persistent": true, ...}`.
 func (c *PluginCapabilities) UnmarshalJSON(data []byte) error {
 	// Use an alias to avoid infinite recursion.
 	type alias struct {
 		HTTP       bool            `json:"http,omitempty"`
-		KV         bool            `json:"kv,omitempty"`
+		KV         json.RawMessage `json:"kv,omitempty"`
 		Log        bool            `json:"log,omitempty"`
 		Filesystem json.RawMessage `json:"filesystem,omitempty"`
 		Secrets    []string        `json:"secrets,omitempty"`
+		Env        []string        `json:"env,omitempty"`
 		Exec       bool            `json:"exec,omitempty"`
 		Elevated   bool            `json:"elevated,omitempty"`
 	}
@@ -51,12 +68,29 @@ func (c *PluginCapabilities) UnmarshalJSON(data []byte) error {
 	}
 
 	c.HTTP = a.HTTP
-	c.KV = a.KV
 	c.Log = a.Log
 	c.Secrets = a.Secrets
+	c.Env = a.Env
 	c.Exec = a.Exec
 	c.Elevated = a.Elevated
 
+	if len(a.KV) > 0 {
+		var boolVal bool
+		if err := json.Unmarshal(a.KV, &boolVal); err == nil {
+			c.KV = boolVal
+		} else {
+			var intent KVCapabilityIntent
+			if err := json.Unmarshal(a.KV, &intent); err != nil {
+				return fmt.Errorf("capabilities.kv: expected bool or object: %w", err)
+			}
+			if intent.MaxKeys < 0 || intent.MaxValueBytes < 0 {
+				return fmt.Errorf("capabilities.kv: max_keys and max_value_bytes must not be negative")
+			}
+			c.KV = true
+			c.KVOptions = &intent
+		}
+	}
+
 	if len(a.Filesystem) > 0 {
 		// Try bool first
 		var boolVal bool
@@ -77,17 +111,44 @@ func (c *PluginCapabilities) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Has reports whether the plugin declares the named capability, using the
+// manifest keys ("http", "kv", "log", "filesystem", "secrets", "env", "exec",
+// "elevated").
+func (c PluginCapabilities) Has(name string) bool {
+	switch strings.ToLower(name) {
+	case "http":
+		return c.HTTP
+	case "kv":
+		return c.KV
+	case "log":
+		return c.Log
+	case "filesystem":
+		return c.Filesystem != nil
+	case "secrets":
+		return len(c.Secrets) > 0
+	case "env":
+		return len(c.Env) > 0
+	case "exec":
+		return c.Exec
+	case "elevated":
+		return c.Elevated
+	default:
+		return false
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Plugin Authorization — what Ozzie allows (user-side config)
 // ---------------------------------------------------------------------------
 
 // PluginAuthorization defines what Ozzie authorizes for a specific plugin.
 type PluginAuthorization struct {
-	HTTP       *HTTPAuth       `json:"http,omitempty"`
-	Filesystem *FSAuth         `json:"filesystem,omitempty"`
-	Secrets    *SecretsAuth    `json:"secrets,omitempty"`
-	Deny       []string        `json:"deny,omitempty"` // capabilities to deny (e.g. "exec", "kv")
-	Resources  *ResourceLimits `json:"resources,omitempty"`
+	HTTP       *HTTPAuth         `json:"http,omitempty"`
+	Filesystem *FSAuth           `json:"filesystem,omitempty"`
+	Secrets    *SecretsAuth      `json:"secrets,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`  // plugin-visible name → value
+	Deny       []string          `json:"deny,omitempty"` // capabilities to deny (e.g. "exec", "kv")
+	Resources  *ResourceLimits   `json:"resources,omitempty"`
 }
 
 // HTTPAuth authorizes HTTP access to specific hosts.
@@ -130,9 +191,11 @@ type MemoryLimit struct {
 type ResolvedCapabilities struct {
 	HTTP       *HTTPAuth
 	KV         bool
+	KVOptions  *KVCapabilityIntent // nil when KV is denied or has no options
 	Log        bool
 	Filesystem *ResolvedFS
 	Secrets    []string
+	Env        map[string]string // declared names with an authorized value
 	Exec       bool
 	Elevated   bool
 	Resources  ResourceLimits
@@ -150,6 +213,7 @@ type ResolvedFS struct {
 func ResolveCapabilities(caps PluginCapabilities, auth *PluginAuthorization, limits ResourceLimits) ResolvedCapabilities {
 	resolved := ResolvedCapabilities{
 		KV:        caps.KV,
+		KVOptions: caps.KVOptions,
 		Log:       caps.Log,
 		Exec:      caps.Exec,
 		Elevated:  caps.Elevated,
@@ -189,6 +253,18 @@ func ResolveCapabilities(caps PluginCapabilities, auth *PluginAuthorization, lim
 		}
 	}
 
+	// Env: names declared by the plugin AND given a value by the user
+	if len(caps.Env) > 0 && auth != nil && len(auth.Env) > 0 {
+		for _, name := range caps.Env {
+			if value, ok := auth.Env[name]; ok {
+				if resolved.Env == nil {
+					resolved.Env = make(map[string]string)
+				}
+				resolved.Env[name] = value
+			}
+		}
+	}
+
 	// Deny overrides: disable binary capabilities
 	if auth != nil {
 		denySet := make(map[string]bool, len(auth.Deny))
@@ -203,6 +279,7 @@ func ResolveCapabilities(caps PluginCapabilities, auth *PluginAuthorization, lim
 		}
 		if denySet["kv"] {
 			resolved.KV = false
+			resolved.KVOptions = nil
 		}
 		if denySet["log"] {
 			resolved.Log = false
@@ -213,6 +290,9 @@ func ResolveCapabilities(caps PluginCapabilities, auth *PluginAuthorization, lim
 		if denySet["filesystem"] {
 			resolved.Filesystem = nil
 		}
+		if denySet["env"] {
+			resolved.Env = nil
+		}
 	}
 
 	// Override resources from auth if provided
@@ -245,6 +325,11 @@ func ValidateAuthorization(pluginName string, caps PluginCapabilities, auth *Plu
 	if auth.Secrets != nil && len(caps.Secrets) == 0 {
 		warnings = append(warnings, fmt.Sprintf("plugin %q: authorization grants secrets but plugin does not request any", pluginName))
 	}
+	for _, name := range slices.Sorted(maps.Keys(auth.Env)) {
+		if !slices.Contains(caps.Env, name) {
+			warnings = append(warnings, fmt.Sprintf("plugin %q: authorization sets env %q but plugin does not declare it", pluginName, name))
+		}
+	}
 
 	return warnings
 }
@@ -274,6 +359,7 @@ func AuthFromConfig(cfg *config.PluginAuthorizationConfig) *PluginAuthorization
 	if cfg.Secrets != nil {
 		auth.Secrets = &SecretsAuth{Allowed: cfg.Secrets.Allowed}
 	}
+	auth.Env = cfg.Env
 	if cfg.Resources != nil {
 		auth.Resources = &ResourceLimits{
 			Timeout: cfg.Resources.Timeout,
@@ -296,7 +382,7 @@ func BuildExtismManifest(m *PluginManifest) extism.Manifest {
 		Wasm: []extism.Wasm{
 			extism.WasmFile{Path: m.WasmPath},
 		},
-		Config: m.Config,
+		Config: pluginConfig(m),
 	}
 
 	resolved := m.Resolved
@@ -328,3 +414,15 @@ func BuildExtismManifest(m *PluginManifest) extism.Manifest {
 
 	return em
 }
+
+// pluginConfig returns the config visible to the guest (pdk.GetConfig,
+// ozzie.get_config): the manifest config, overridden by the resolved env.
+func pluginConfig(m *PluginManifest) map[string]string {
+	if m.Resolved == nil || len(m.Resolved.Env) == 0 {
+		return m.Config
+	}
+	cfg := make(map[string]string, len(m.Config)+len(m.Resolved.Env))
+	maps.Copy(cfg, m.Config)
+	maps.Copy(cfg, m.Resolved.Env)
+	return cfg
+}
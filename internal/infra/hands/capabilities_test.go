@@ -2,6 +2,8 @@ package hands
 
 import (
 	"encoding/json"
+	"maps"
+	"strings"
 	"testing"
 
 	"github.com/dohr-michael/ozzie/internal/config"
@@ -56,6 +58,30 @@ func TestResolveCapabilities_DenyOverride(t *testing.T) {
 	}
 }
 
+func TestResolveCapabilities_Env(t *testing.T) {
+	caps := PluginCapabilities{Env: []string{"API_ENDPOINT", "API_TOKEN", "UNSET"}}
+	auth := &PluginAuthorization{Env: map[string]string{
+		"API_ENDPOINT": "https://api.example.com",
+		"API_TOKEN":    "s3cret",
+		"UNDECLARED":   "ignored",
+	}}
+	resolved := ResolveCapabilities(caps, auth, ResourceLimits{})
+
+	want := map[string]string{"API_ENDPOINT": "https://api.example.com", "API_TOKEN": "s3cret"}
+	if !maps.Equal(resolved.Env, want) {
+		t.Errorf("Env = %v, want %v (declared AND authorized only)", resolved.Env, want)
+	}
+
+	auth.Deny = []string{"env"}
+	if resolved := ResolveCapabilities(caps, auth, ResourceLimits{}); resolved.Env != nil {
+		t.Errorf("Env = %v, want nil (denied)", resolved.Env)
+	}
+
+	if resolved := ResolveCapabilities(caps, nil, ResourceLimits{}); resolved.Env != nil {
+		t.Errorf("Env = %v, want nil without authorization", resolved.Env)
+	}
+}
+
 func TestResolveCapabilities_ReadOnlyCannotUpgrade(t *testing.T) {
 	// Plugin declares read_only=true, auth tries read-write → stays read_only
 	caps := PluginCapabilities{
@@ -202,6 +228,17 @@ func TestValidateAuthorization_Warnings(t *testing.T) {
 	}
 }
 
+func TestValidateAuthorization_UndeclaredEnv(t *testing.T) {
+	caps := PluginCapabilities{Env: []string{"API_ENDPOINT"}}
+	auth := &PluginAuthorization{Env: map[string]string{"API_ENDPOINT": "x", "API_TOKEN": "y"}}
+
+	warnings := ValidateAuthorization("test_plugin", caps, auth)
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "API_TOKEN") {
+		t.Errorf("warnings = %v, want one about API_TOKEN", warnings)
+	}
+}
+
 func TestValidateAuthorization_NoWarnings(t *testing.T) {
 	caps := PluginCapabilities{
 		HTTP:       true,
@@ -326,6 +363,41 @@ func TestPluginCapabilities_UnmarshalJSON_FalseFilesystem(t *testing.T) {
 	}
 }
 
+func TestPluginCapabilities_UnmarshalJSON_ObjectKV(t *testing.T) {
+	data := []byte(`{"kv": {"persistent": true, "per_tool": true, "max_keys": 10, "max_value_bytes": 1024}}`)
+	var caps PluginCapabilities
+	if err := json.Unmarshal(data, &caps); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !caps.KV {
+		t.Error("KV = false, want true (object form enables kv)")
+	}
+	want := KVCapabilityIntent{Persistent: true, PerTool: true, MaxKeys: 10, MaxValueBytes: 1024}
+	if caps.KVOptions == nil || *caps.KVOptions != want {
+		t.Errorf("KVOptions = %+v, want %+v", caps.KVOptions, want)
+	}
+
+	resolved := ResolveCapabilities(caps, &PluginAuthorization{Deny: []string{"kv"}}, ResourceLimits{})
+	if resolved.KV || resolved.KVOptions != nil {
+		t.Error("denying kv should drop the kv options")
+	}
+}
+
+func TestPluginCapabilities_UnmarshalJSON_BoolKV(t *testing.T) {
+	var caps PluginCapabilities
+	if err := json.Unmarshal([]byte(`{"kv": true}`), &caps); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !caps.KV || caps.KVOptions != nil {
+		t.Errorf("KV = %v, KVOptions = %+v, want true and nil", caps.KV, caps.KVOptions)
+	}
+
+	if err := json.Unmarshal([]byte(`{"kv": {"max_keys": -1}}`), &caps); err == nil {
+		t.Error("expected an error for a negative quota")
+	}
+}
+
 func TestPluginCapabilities_UnmarshalJSON_Empty(t *testing.T) {
 	data := []byte(`{}`)
 	var caps PluginCapabilities
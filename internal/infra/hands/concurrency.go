@@ -0,0 +1,104 @@
+package hands
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+// ToolLimiter bounds how many calls of a tool run at once, across every
+// session and task. Limits come from ToolSpec.MaxConcurrent, overridable by
+// name (e.g. from config). Excess calls queue until a slot frees up or their
+// context ends.
+type ToolLimiter struct {
+	overrides map[string]int
+
+	mu      sync.Mutex
+	slots   map[string]chan struct{} // tool name → semaphore
+	wrapped map[string]bool          // tools already gated by WrapRegistryConcurrency
+}
+
+// NewToolLimiter creates a limiter. overrides maps tool names to a maximum
+// number of concurrent calls, replacing the manifest value (0 = unlimited).
+func NewToolLimiter(overrides map[string]int) *ToolLimiter {
+	return &ToolLimiter{
+		overrides: overrides,
+		slots:     make(map[string]chan struct{}),
+		wrapped:   make(map[string]bool),
+	}
+}
+
+// limit returns the concurrency limit of a tool (0 = unlimited).
+func (l *ToolLimiter) limit(name string, spec *ToolSpec) int {
+	if n, ok := l.overrides[name]; ok {
+		return n
+	}
+	if spec != nil {
+		return spec.MaxConcurrent
+	}
+	return 0
+}
+
+// WrapRegistryConcurrency gates every tool with a concurrency limit that is
+// not gated yet, so it can be called again after late registrations. Call it
+// right after WrapRegistryMetrics so queueing time is neither metered nor
+// spent holding a slot while waiting for approval:
+// DangerousToolWrapper → ... → ConcurrencyGate → MeteredTool → inner tool.
+func WrapRegistryConcurrency(registry *ToolRegistry, limiter *ToolLimiter) {
+	for _, name := range registry.ToolNames() {
+		n := limiter.limit(name, registry.ToolSpec(name))
+		if n <= 0 {
+			continue
+		}
+
+		limiter.mu.Lock()
+		done := limiter.wrapped[name]
+		limiter.wrapped[name] = true
+		slots, ok := limiter.slots[name]
+		if !ok {
+			slots = make(chan struct{}, n)
+			limiter.slots[name] = slots
+		}
+		limiter.mu.Unlock()
+		if done {
+			continue
+		}
+
+		wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
+			return &ConcurrencyGate{inner: t, name: name, slots: slots}
+		})
+	}
+}
+
+// ConcurrencyGate runs the inner tool once a slot of its semaphore is free.
+type ConcurrencyGate struct {
+	inner brain.Tool
+	name  string
+	slots chan struct{}
+}
+
+// Info delegates to the inner tool.
+func (g *ConcurrencyGate) Info(ctx context.Context) (*brain.ToolInfo, error) {
+	return g.inner.Info(ctx)
+}
+
+// Run waits for a free slot, then delegates to the inner tool.
+func (g *ConcurrencyGate) Run(ctx context.Context, argumentsInJSON string) (string, error) {
+	select {
+	case g.slots <- struct{}{}:
+	default:
+		slog.Info("tool call queued: concurrency limit reached", "tool", g.name, "max_concurrent", cap(g.slots))
+		select {
+		case g.slots <- struct{}{}:
+		case <-ctx.Done():
+			return "", brain.ToolErrorf(brain.ToolErrTransient,
+				"%s: %d concurrent calls already running, gave up waiting: %w", g.name, cap(g.slots), ctx.Err())
+		}
+	}
+	defer func() { <-g.slots }()
+	return g.inner.Run(ctx, argumentsInJSON)
+}
+
+var _ brain.Tool = (*ConcurrencyGate)(nil)
@@ -0,0 +1,124 @@
+package hands
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+// blockingTool records how many calls run at once, signals each start on
+// started and blocks until it receives from release (or release closes).
+type blockingTool struct {
+	running, peak atomic.Int32
+	started       chan struct{}
+	release       chan struct{}
+}
+
+func newBlockingTool() *blockingTool {
+	return &blockingTool{started: make(chan struct{}, 8), release: make(chan struct{})}
+}
+
+func (t *blockingTool) Info(context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "heavy"}, nil
+}
+
+func (t *blockingTool) InvokableRun(context.Context, string, ...tool.Option) (string, error) {
+	n := t.running.Add(1)
+	t.started <- struct{}{}
+	for {
+		p := t.peak.Load()
+		if n <= p || t.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	<-t.release
+	t.running.Add(-1)
+	return "done", nil
+}
+
+func heavyManifest(maxConcurrent int) *PluginManifest {
+	return &PluginManifest{
+		Name:     "heavy",
+		Provider: "native",
+		Tools:    []ToolSpec{{Name: "heavy", MaxConcurrent: maxConcurrent}},
+	}
+}
+
+func TestWrapRegistryConcurrency(t *testing.T) {
+	inner := newBlockingTool()
+	registry := NewToolRegistry(nil)
+	if err := registry.RegisterNative("heavy", inner, heavyManifest(2)); err != nil {
+		t.Fatal(err)
+	}
+	limiter := NewToolLimiter(nil)
+	WrapRegistryConcurrency(registry, limiter)
+	WrapRegistryConcurrency(registry, limiter) // already gated: no double wrapping
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := registry.Tool("heavy").InvokableRun(context.Background(), `{}`); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// Release the calls one at a time: each release frees a slot for the
+	// next waiting call, never more than two running together.
+	<-inner.started
+	<-inner.started
+	for range 5 {
+		inner.release <- struct{}{}
+	}
+	wg.Wait()
+
+	if peak := inner.peak.Load(); peak != 2 {
+		t.Errorf("peak concurrent calls = %d, want 2", peak)
+	}
+}
+
+func TestConcurrencyGate_ContextCancelled(t *testing.T) {
+	inner := newBlockingTool()
+	defer close(inner.release)
+	registry := NewToolRegistry(nil)
+	if err := registry.RegisterNative("heavy", inner, heavyManifest(1)); err != nil {
+		t.Fatal(err)
+	}
+	WrapRegistryConcurrency(registry, NewToolLimiter(nil))
+
+	go func() { _, _ = registry.Tool("heavy").InvokableRun(context.Background(), `{}`) }()
+	<-inner.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := registry.Tool("heavy").InvokableRun(ctx, `{}`)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want deadline exceeded", err)
+	}
+	if code := brain.ToolErrorCodeOf(err); code != brain.ToolErrTransient {
+		t.Errorf("code = %q, want %q", code, brain.ToolErrTransient)
+	}
+}
+
+func TestToolLimiter_Overrides(t *testing.T) {
+	l := NewToolLimiter(map[string]int{"heavy": 0, "run_command": 3})
+	spec := &ToolSpec{MaxConcurrent: 2}
+	if n := l.limit("heavy", spec); n != 0 {
+		t.Errorf("override to unlimited: got %d", n)
+	}
+	if n := l.limit("run_command", nil); n != 3 {
+		t.Errorf("override for a tool without limit: got %d", n)
+	}
+	if n := l.limit("other", spec); n != 2 {
+		t.Errorf("manifest limit: got %d", n)
+	}
+}
@@ -2,9 +2,10 @@ package hands
 
 // Tool name constants for unified tools.
 const (
-	ToolQueryTasks = "query_tasks"
-	ToolActivate   = "activate"
-	ToolWeb        = "web"
+	ToolQueryTasks    = "query_tasks"
+	ToolActivate      = "activate"
+	ToolListAvailable = "list_available_tools"
+	ToolWeb           = "web"
 )
 
 // DefaultTaskTools is the default set of tools available to tasks
@@ -8,6 +8,7 @@ func StrReplaceEditorManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   true,
+		Tags:        []string{"files"},
 		Capabilities: PluginCapabilities{
 			Filesystem: &FSCapabilityIntent{ReadOnly: false},
 		},
@@ -0,0 +1,197 @@
+package hands
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+
+	"github.com/dohr-michael/ozzie/internal/config"
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+	"github.com/dohr-michael/ozzie/pkg/editor"
+	editortools "github.com/dohr-michael/ozzie/pkg/editor/tools"
+	memtools "github.com/dohr-michael/ozzie/pkg/memory/tools"
+)
+
+// harnessTool describes one native tool under the sandbox harness.
+// dangerous and required are golden values: changing a tool's danger
+// classification or required params must be a deliberate test update.
+type harnessTool struct {
+	name      string
+	tool      tool.InvokableTool
+	manifest  *PluginManifest
+	dangerous bool
+	required  []string
+}
+
+// harnessPool is an empty task pool: no actors, submissions rejected.
+type harnessPool struct{}
+
+func (harnessPool) Submit(*brain.Task) error    { return errors.New("harness: submit") }
+func (harnessPool) Cancel(string, string) error { return errors.New("harness: cancel") }
+func (harnessPool) CancelSession(string, string) ([]brain.CancelledTask, error) {
+	return nil, errors.New("harness: cancel session")
+}
+func (harnessPool) Store() brain.TaskStore             { return nil }
+func (harnessPool) AvailableActors() []brain.ActorInfo { return nil }
+
+// nativeHarnessTools builds every native tool with nil dependencies. Tools must
+// validate their input before touching a dependency, so nil is enough here.
+func nativeHarnessTools(registry *ToolRegistry) []harnessTool {
+	fetch := NewWebFetchTool(config.WebFetchConfig{})
+	return []harnessTool{
+		{"run_command", NewExecuteTool(), ExecuteManifest(), true, []string{"command"}},
+		{"git", NewGitTool(), GitManifest(), true, []string{"action"}},
+		{"web_fetch", fetch, WebFetchManifest(), true, []string{"url"}},
+		{ToolWeb, NewWebTool(nil, fetch), WebManifest(), true, nil},
+		{"str_replace_editor", editortools.NewStrReplaceEditorTool(editor.New(editor.LocalBackend{})), StrReplaceEditorManifest(), true, []string{"command", "path"}},
+		{"write_files", NewWriteFilesTool(nil), WriteFilesManifest(), true, []string{"files"}},
+		{"store_memory", memtools.NewStoreMemoryTool(nil, nil), StoreMemoryManifest(), false, []string{"content", "title", "type"}},
+		{"query_memories", memtools.NewQueryMemoriesTool(nil), QueryMemoriesManifest(), false, []string{"query"}},
+		{"forget_memory", memtools.NewForgetMemoryTool(nil, nil), ForgetMemoryManifest(), false, []string{"id"}},
+		{"set_var", NewSetVarTool(nil), SetVarManifest(), false, []string{"key"}},
+		{"get_var", NewGetVarTool(nil), GetVarManifest(), false, nil},
+		{"update_session", NewUpdateSessionTool(nil), UpdateSessionManifest(), false, nil},
+		{"submit_task", NewSubmitTaskTool(harnessPool{}, registry, nil, nil, nil, nil, config.LimitsConfig{}), SubmitTaskManifest(), false, nil},
+		{"save_task_template", NewSaveTaskTemplateTool(nil, nil), SaveTaskTemplateManifest(), false, []string{"name", "task_id"}},
+		{ToolQueryTasks, NewQueryTasksTool(nil, config.LimitsConfig{}), QueryTasksManifest(), false, nil},
+		{"cancel_task", NewCancelTaskTool(harnessPool{}), CancelTaskManifest(), false, []string{"task_id"}},
+		{tasks.RegisterArtifactTool, NewRegisterArtifactTool(nil), RegisterArtifactManifest(), false, []string{"path"}},
+		{"explain_error", NewExplainErrorTool(nil, ""), ExplainErrorManifest(), false, nil},
+		{"schedule_task", NewScheduleTaskTool(nil, nil, registry, nil), ScheduleTaskManifest(), false, []string{"description", "title"}},
+		{"unschedule_task", NewUnscheduleTaskTool(nil, nil), UnscheduleTaskManifest(), false, []string{"entry_id"}},
+		{"list_schedules", NewListSchedulesTool(nil), ListSchedulesManifest(), false, nil},
+		{"trigger_schedule", NewTriggerScheduleTool(nil), TriggerScheduleManifest(), false, []string{"entry_id"}},
+		{"approve_pairing", NewApprovePairingTool(nil, nil, nil), ApprovePairingManifest(), false, []string{"platform", "policy_name", "user_id"}},
+		{ToolActivate, NewActivateTool(nil, registry, nil), ActivateManifest(), false, nil},
+		{ToolListAvailable, NewListAvailableToolsTool(nil, registry), ListAvailableToolsManifest(), false, nil},
+		{"run_workflow", NewRunWorkflowTool(nil), RunWorkflowManifest(), false, []string{"skill_name"}},
+	}
+}
+
+func TestNativeToolHarness(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	registry := NewToolRegistry(bus)
+
+	for _, ht := range nativeHarnessTools(registry) {
+		if err := registry.RegisterNative(ht.name, ht.tool, resolvedNativeManifest(ht.manifest)); err != nil {
+			t.Fatalf("register %s: %v", ht.name, err)
+		}
+	}
+
+	for _, ht := range nativeHarnessTools(registry) {
+		t.Run(ht.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// Schema: Info() is well-formed and matches the manifest.
+			info, err := ht.tool.Info(ctx)
+			if err != nil || info == nil {
+				t.Fatalf("Info() = %v, %v", info, err)
+			}
+			if info.Name != ht.name {
+				t.Errorf("Info().Name = %q, want %q", info.Name, ht.name)
+			}
+			if strings.TrimSpace(info.Desc) == "" {
+				t.Error("Info().Desc is empty")
+			}
+
+			var schemaRequired []string
+			schemaProps := map[string]bool{}
+			if info.ParamsOneOf != nil {
+				js, err := info.ParamsOneOf.ToJSONSchema()
+				if err != nil {
+					t.Fatalf("ToJSONSchema: %v", err)
+				}
+				if js.Type != "object" {
+					t.Errorf("schema type = %q, want object", js.Type)
+				}
+				schemaRequired = slices.Clone(js.Required)
+				if js.Properties != nil {
+					for pair := js.Properties.Oldest(); pair != nil; pair = pair.Next() {
+						schemaProps[pair.Key] = true
+						if pair.Value.Description == "" {
+							t.Errorf("param %q has no description", pair.Key)
+						}
+					}
+				}
+			}
+			slices.Sort(schemaRequired)
+			if !slices.Equal(schemaRequired, ht.required) {
+				t.Errorf("schema required = %v, want %v", schemaRequired, ht.required)
+			}
+
+			// Manifest: the registered spec agrees with Info() and the golden values.
+			spec := registry.ToolSpec(ht.name)
+			if spec == nil {
+				t.Fatal("no ToolSpec registered under the tool name")
+			}
+			var specRequired []string
+			for name, p := range spec.Parameters {
+				if !schemaProps[name] {
+					t.Errorf("manifest param %q missing from Info() schema", name)
+				}
+				if p.Required {
+					specRequired = append(specRequired, name)
+				}
+			}
+			slices.Sort(specRequired)
+			if !slices.Equal(specRequired, ht.required) {
+				t.Errorf("manifest required = %v, want %v", specRequired, ht.required)
+			}
+
+			// Danger classification: the per-tool flag drives confirmation wrapping.
+			if spec.Dangerous != ht.dangerous {
+				t.Errorf("spec dangerous = %v, want %v", spec.Dangerous, ht.dangerous)
+			}
+			if ht.manifest.Dangerous != spec.Dangerous {
+				t.Errorf("manifest dangerous = %v but tool spec dangerous = %v", ht.manifest.Dangerous, spec.Dangerous)
+			}
+
+			// Error taxonomy: bad input fails with an error prefixed by the tool name.
+			inputs := []string{`{not json`}
+			if len(ht.required) > 0 {
+				inputs = append(inputs, `{}`)
+			}
+			for _, in := range inputs {
+				out, err := ht.tool.InvokableRun(ctx, in)
+				if err == nil {
+					t.Errorf("InvokableRun(%s) = %q, want error", in, out)
+					continue
+				}
+				if !strings.HasPrefix(err.Error(), ht.name+": ") {
+					t.Errorf("InvokableRun(%s) error %q not prefixed with %q", in, err, ht.name+": ")
+				}
+			}
+		})
+	}
+}
+
+// TestNativeToolHarness_CoversSetup ensures every tool registered by
+// SetupToolRegistry is also exercised by the harness.
+func TestNativeToolHarness_CoversSetup(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+
+	cfg := &config.Config{}
+	cfg.Plugins.Dir = t.TempDir()
+	registry, err := SetupToolRegistry(context.Background(), cfg, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	covered := map[string]bool{"web_search": true} // provider-backed, schema owned by the provider
+	for _, ht := range nativeHarnessTools(registry) {
+		covered[ht.name] = true
+	}
+	for _, name := range registry.NativeToolNames() {
+		if !covered[name] {
+			t.Errorf("native tool %q is not covered by the harness", name)
+		}
+	}
+}
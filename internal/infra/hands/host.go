@@ -3,37 +3,25 @@ package hands
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
-	"sync"
 
 	extism "github.com/extism/go-sdk"
 
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
-// KVStore is a per-plugin in-memory key-value store.
-type KVStore struct {
-	mu   sync.RWMutex
-	data map[string][]byte
-}
-
-// NewKVStore creates a new empty KV store.
-func NewKVStore() *KVStore {
-	return &KVStore{data: make(map[string][]byte)}
-}
+type kvNamespaceKey struct{}
 
-// Get returns the value for a key, or nil if not found.
-func (s *KVStore) Get(key string) []byte {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.data[key]
+// withKVNamespace tells the KV host functions which namespace the calling
+// tool uses ("" = the plugin-wide namespace).
+func withKVNamespace(ctx context.Context, ns string) context.Context {
+	return context.WithValue(ctx, kvNamespaceKey{}, ns)
 }
 
-// Set stores a value for a key.
-func (s *KVStore) Set(key string, value []byte) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.data[key] = value
+func kvNamespaceFromContext(ctx context.Context) string {
+	ns, _ := ctx.Value(kvNamespaceKey{}).(string)
+	return ns
 }
 
 // hostLogMessage is the JSON structure for ozzie.log calls.
@@ -42,7 +30,7 @@ type hostLogMessage struct {
 	Message string `json:"message"`
 }
 
-// hostKVRequest is the JSON structure for ozzie.kv_set calls.
+// hostKVRequest is the JSON structure for ozzie.kv_set and kv_set_checked calls.
 type hostKVRequest struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -93,14 +81,14 @@ func NewHostFunctions(bus events.EventBus, kv *KVStore, pluginConfig map[string]
 	// ozzie.kv_get — read from per-plugin KV store
 	kvGetFn := extism.NewHostFunctionWithStack(
 		"kv_get",
-		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
+		func(ctx context.Context, p *extism.CurrentPlugin, stack []uint64) {
 			key, err := p.ReadString(stack[0])
 			if err != nil {
 				slog.Error("host: kv_get read key", "error", err)
 				stack[0] = 0
 				return
 			}
-			value := kv.Get(key)
+			value := kv.Get(kvNamespaceFromContext(ctx), key)
 			if value == nil {
 				value = []byte("{}")
 			}
@@ -118,21 +106,14 @@ func NewHostFunctions(bus events.EventBus, kv *KVStore, pluginConfig map[string]
 	kvGetFn.SetNamespace("ozzie")
 	fns = append(fns, kvGetFn)
 
-	// ozzie.kv_set — write to per-plugin KV store
+	// ozzie.kv_set — write to per-plugin KV store. Void, so errors (e.g. a
+	// quota exceeded) are only logged; see kv_set_checked.
 	kvSetFn := extism.NewHostFunctionWithStack(
 		"kv_set",
-		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
-			input, err := p.ReadBytes(stack[0])
-			if err != nil {
-				slog.Error("host: kv_set read input", "error", err)
-				return
+		func(ctx context.Context, p *extism.CurrentPlugin, stack []uint64) {
+			if err := hostKVSet(ctx, p, kv, stack[0]); err != nil {
+				slog.Warn("host: kv_set", "error", err)
 			}
-			var req hostKVRequest
-			if err := json.Unmarshal(input, &req); err != nil {
-				slog.Error("host: kv_set parse", "error", err)
-				return
-			}
-			kv.Set(req.Key, []byte(req.Value))
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		nil,
@@ -140,6 +121,30 @@ func NewHostFunctions(bus events.EventBus, kv *KVStore, pluginConfig map[string]
 	kvSetFn.SetNamespace("ozzie")
 	fns = append(fns, kvSetFn)
 
+	// ozzie.kv_set_checked — like kv_set, but returns 0 on success or a
+	// pointer to the error message.
+	kvSetCheckedFn := extism.NewHostFunctionWithStack(
+		"kv_set_checked",
+		func(ctx context.Context, p *extism.CurrentPlugin, stack []uint64) {
+			err := hostKVSet(ctx, p, kv, stack[0])
+			if err == nil {
+				stack[0] = 0
+				return
+			}
+			slog.Warn("host: kv_set_checked", "error", err)
+			offset, writeErr := p.WriteString(err.Error())
+			if writeErr != nil {
+				slog.Error("host: kv_set_checked write error", "error", writeErr)
+				offset = 0
+			}
+			stack[0] = offset
+		},
+		[]extism.ValueType{extism.ValueTypePTR},
+		[]extism.ValueType{extism.ValueTypePTR},
+	)
+	kvSetCheckedFn.SetNamespace("ozzie")
+	fns = append(fns, kvSetCheckedFn)
+
 	// ozzie.emit_event — publish an event on the bus
 	emitFn := extism.NewHostFunctionWithStack(
 		"emit_event",
@@ -189,3 +194,16 @@ func NewHostFunctions(bus events.EventBus, kv *KVStore, pluginConfig map[string]
 
 	return fns
 }
+
+// hostKVSet handles an ozzie.kv_set or kv_set_checked call.
+func hostKVSet(ctx context.Context, p *extism.CurrentPlugin, kv *KVStore, input uint64) error {
+	data, err := p.ReadBytes(input)
+	if err != nil {
+		return fmt.Errorf("read input: %w", err)
+	}
+	var req hostKVRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	return kv.Set(kvNamespaceFromContext(ctx), req.Key, []byte(req.Value))
+}
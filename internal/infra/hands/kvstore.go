@@ -0,0 +1,184 @@
+package hands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrKVQuota is returned when a write would exceed a KV store quota.
+var ErrKVQuota = errors.New("kv quota exceeded")
+
+// KVStore is a per-plugin key-value store. Keys live in namespaces: one per
+// tool when the plugin asks for it, "" otherwise. Quotas apply to each
+// namespace. A store opened with a path is saved to it on every change.
+type KVStore struct {
+	mu            sync.RWMutex
+	data          map[string]map[string][]byte // namespace → key → value
+	path          string                       // "" = in-memory
+	maxKeys       int                          // 0 = unlimited
+	maxValueBytes int                          // 0 = unlimited
+}
+
+// NewKVStore creates a new empty in-memory KV store without quotas.
+func NewKVStore() *KVStore {
+	return &KVStore{data: make(map[string]map[string][]byte)}
+}
+
+// OpenKVStore creates a KV store with the given quotas (0 = unlimited).
+// When path is set, the store is loaded from that file if it exists and
+// persisted to it on every change.
+func OpenKVStore(path string, maxKeys, maxValueBytes int) (*KVStore, error) {
+	s := NewKVStore()
+	s.path = path
+	s.maxKeys = maxKeys
+	s.maxValueBytes = maxValueBytes
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read kv store %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return nil, fmt.Errorf("parse kv store %s: %w", path, err)
+	}
+	if s.data == nil {
+		s.data = make(map[string]map[string][]byte)
+	}
+	return s, nil
+}
+
+// Get returns the value for a key, or nil if not found.
+func (s *KVStore) Get(ns, key string) []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data[ns][key]
+}
+
+// Set stores a value for a key. It fails with ErrKVQuota when the value is
+// too large or the namespace is full, and leaves the store unchanged when
+// it cannot be persisted.
+func (s *KVStore) Set(ns, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkValue(key, value); err != nil {
+		return err
+	}
+	values := s.data[ns]
+	prev, exists := values[key]
+	if !exists && s.maxKeys > 0 && len(values) >= s.maxKeys {
+		return fmt.Errorf("%w: namespace holds the maximum of %d keys", ErrKVQuota, s.maxKeys)
+	}
+
+	if values == nil {
+		values = make(map[string][]byte)
+		s.data[ns] = values
+	}
+	values[key] = value
+	if err := s.save(); err != nil {
+		if exists {
+			values[key] = prev
+		} else {
+			delete(values, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the values of a namespace.
+func (s *KVStore) Snapshot(ns string) map[string][]byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string][]byte, len(s.data[ns]))
+	maps.Copy(out, s.data[ns])
+	return out
+}
+
+// Merge applies to a namespace the changes a plugin call made to its Extism
+// vars: keys added or modified in after (compared to before, the snapshot the
+// call started from) are set, keys removed from it are deleted. Other keys,
+// e.g. written by ozzie.kv_set during the call, are kept. Quotas apply to the
+// result as a whole; on error the namespace is left unchanged.
+func (s *KVStore) Merge(ns string, before, after map[string][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := maps.Clone(s.data[ns])
+	if merged == nil {
+		merged = make(map[string][]byte)
+	}
+	changed := false
+	for k, v := range after {
+		if prev, ok := before[k]; ok && bytes.Equal(prev, v) {
+			continue
+		}
+		if err := s.checkValue(k, v); err != nil {
+			return err
+		}
+		merged[k] = v
+		changed = true
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			delete(merged, k)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if s.maxKeys > 0 && len(merged) > s.maxKeys {
+		return fmt.Errorf("%w: %d keys, max %d", ErrKVQuota, len(merged), s.maxKeys)
+	}
+
+	prev := s.data[ns]
+	s.data[ns] = merged
+	if err := s.save(); err != nil {
+		s.data[ns] = prev
+		return err
+	}
+	return nil
+}
+
+func (s *KVStore) checkValue(key string, value []byte) error {
+	if s.maxValueBytes > 0 && len(value) > s.maxValueBytes {
+		return fmt.Errorf("%w: value of %q is %d bytes, max %d", ErrKVQuota, key, len(value), s.maxValueBytes)
+	}
+	return nil
+}
+
+// save writes the store to its file through a temp file + rename.
+// It is a no-op for in-memory stores. Callers hold the write lock.
+func (s *KVStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("encode kv store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("save kv store: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("save kv store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("save kv store: %w", err)
+	}
+	return nil
+}
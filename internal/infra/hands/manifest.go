@@ -9,8 +9,19 @@ import (
 	"github.com/tailscale/hujson"
 )
 
+// ManifestSchemaVersion is the manifest format version this binary understands.
+const ManifestSchemaVersion = 1
+
+// manifestMigrations upgrade a parsed manifest by one schema version:
+// manifestMigrations[v] turns a version v manifest into version v+1.
+var manifestMigrations = []func(m *PluginManifest){
+	// 0 → 1: manifests predating schema_version use the version 1 format as is.
+	func(m *PluginManifest) {},
+}
+
 // PluginManifest describes a plugin's metadata, capabilities, and tools.
 type PluginManifest struct {
+	SchemaVersion  int                `json:"schema_version,omitempty"` // absent = 1 (pre-versioning manifests)
 	Name           string             `json:"name"`
 	Description    string             `json:"description"`
 	Level          string             `json:"level"`     // "tool" or "communication"
@@ -21,17 +32,20 @@ type PluginManifest struct {
 	ResourceLimits ResourceLimits     `json:"resource_limits,omitempty"`
 	Tools          []ToolSpec         `json:"tools"` // 1..N tools per plugin
 	Config         map[string]string  `json:"config"`
+	Tags           []string           `json:"tags,omitempty"` // discovery tags for all tools (e.g. "web")
 
 	Resolved *ResolvedCapabilities `json:"-"` // computed at load, not serialized
 }
 
 // ToolSpec describes a single tool interface exposed by a plugin.
 type ToolSpec struct {
-	Name        string               `json:"name"`
-	Description string               `json:"description"`
-	Parameters  map[string]ParamSpec `json:"parameters"`
-	Func        string               `json:"func,omitempty"` // WASM export name (default: "handle")
-	Dangerous   bool                 `json:"dangerous"`      // per-tool override
+	Name          string               `json:"name"`
+	Description   string               `json:"description"`
+	Parameters    map[string]ParamSpec `json:"parameters"`
+	Func          string               `json:"func,omitempty"`           // WASM export name (default: "handle")
+	Dangerous     bool                 `json:"dangerous"`                // per-tool override
+	Tags          []string             `json:"tags,omitempty"`           // added to the plugin's tags
+	MaxConcurrent int                  `json:"max_concurrent,omitempty"` // calls running at once across sessions (0 = unlimited)
 }
 
 // ParamSpec describes a single tool parameter.
@@ -56,10 +70,27 @@ func LoadManifest(path string) (*PluginManifest, error) {
 	if err != nil {
 		return nil, fmt.Errorf("standardize manifest %s: %w", path, err)
 	}
+	// Check the version before decoding the rest: a newer format may not
+	// even parse as this one.
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(standardized, &header); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+	switch {
+	case header.SchemaVersion < 0:
+		return nil, fmt.Errorf("manifest %s: invalid schema_version %d", path, header.SchemaVersion)
+	case header.SchemaVersion > ManifestSchemaVersion:
+		return nil, fmt.Errorf("manifest %s: schema_version %d is newer than the supported version %d, upgrade ozzie to load this plugin",
+			path, header.SchemaVersion, ManifestSchemaVersion)
+	}
+
 	var m PluginManifest
 	if err := json.Unmarshal(standardized, &m); err != nil {
 		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
 	}
+	migrateManifest(&m)
 
 	if m.Name == "" {
 		return nil, fmt.Errorf("manifest %s: name is required", path)
@@ -89,3 +120,12 @@ func LoadManifest(path string) (*PluginManifest, error) {
 
 	return &m, nil
 }
+
+// migrateManifest upgrades m to ManifestSchemaVersion. A missing version is
+// treated as 0, which migrates to 1 unchanged.
+func migrateManifest(m *PluginManifest) {
+	for v := m.SchemaVersion; v < ManifestSchemaVersion; v++ {
+		manifestMigrations[v](m)
+	}
+	m.SchemaVersion = ManifestSchemaVersion
+}
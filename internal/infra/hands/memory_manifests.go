@@ -8,6 +8,7 @@ func StoreMemoryManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"memory"},
 		Tools: []ToolSpec{
 			{
 				Name:        "store_memory",
@@ -52,6 +53,7 @@ func QueryMemoriesManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"memory"},
 		Tools: []ToolSpec{
 			{
 				Name:        "query_memories",
@@ -84,6 +86,7 @@ func ForgetMemoryManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"memory"},
 		Tools: []ToolSpec{
 			{
 				Name:        "forget_memory",
@@ -0,0 +1,173 @@
+package hands
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+const (
+	// metricsWindow is the number of recent durations kept per tool for percentiles.
+	metricsWindow = 200
+	// slowToolMinCalls avoids flagging a tool as slow on its first few calls.
+	slowToolMinCalls = 5
+)
+
+// ToolStat summarizes the invocations of one tool. Percentiles cover the
+// most recent calls only.
+type ToolStat struct {
+	Name      string  `json:"name"`
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	TotalMs   int64   `json:"total_ms"`
+	MedianMs  int64   `json:"median_ms"`
+	P95Ms     int64   `json:"p95_ms"`
+}
+
+// ToolMetrics records per-tool invocation counts, durations and errors, and
+// logs a warning when a tool's median duration exceeds the slow threshold.
+type ToolMetrics struct {
+	slowThreshold time.Duration // 0 disables slow-tool warnings
+
+	mu      sync.Mutex
+	tools   map[string]*toolSamples
+	wrapped map[string]bool // tools already decorated by WrapRegistryMetrics
+}
+
+type toolSamples struct {
+	calls  int
+	errors int
+	total  time.Duration
+	recent []time.Duration // ring buffer of the last metricsWindow durations
+	next   int
+	slow   bool // a slow warning was logged and the tool has not recovered since
+}
+
+// NewToolMetrics creates an empty recorder.
+func NewToolMetrics(slowThreshold time.Duration) *ToolMetrics {
+	return &ToolMetrics{
+		slowThreshold: slowThreshold,
+		tools:         make(map[string]*toolSamples),
+		wrapped:       make(map[string]bool),
+	}
+}
+
+// Record adds one invocation of a tool.
+func (m *ToolMetrics) Record(name string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.tools[name]
+	if !ok {
+		s = &toolSamples{}
+		m.tools[name] = s
+	}
+	s.calls++
+	s.total += d
+	if err != nil {
+		s.errors++
+	}
+	if len(s.recent) < metricsWindow {
+		s.recent = append(s.recent, d)
+	} else {
+		s.recent[s.next] = d
+		s.next = (s.next + 1) % metricsWindow
+	}
+
+	if m.slowThreshold <= 0 || s.calls < slowToolMinCalls {
+		return
+	}
+	median := percentile(sortedDurations(s.recent), 0.5)
+	switch {
+	case median > m.slowThreshold && !s.slow:
+		s.slow = true
+		slog.Warn("slow tool", "tool", name, "median", median, "threshold", m.slowThreshold, "calls", s.calls)
+	case median <= m.slowThreshold:
+		s.slow = false
+	}
+}
+
+// Stats returns a snapshot of every recorded tool, sorted by name.
+func (m *ToolMetrics) Stats() []ToolStat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]ToolStat, 0, len(m.tools))
+	for name, s := range m.tools {
+		sorted := sortedDurations(s.recent)
+		stats = append(stats, ToolStat{
+			Name:      name,
+			Calls:     s.calls,
+			Errors:    s.errors,
+			ErrorRate: float64(s.errors) / float64(s.calls),
+			TotalMs:   s.total.Milliseconds(),
+			MedianMs:  percentile(sorted, 0.5).Milliseconds(),
+			P95Ms:     percentile(sorted, 0.95).Milliseconds(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+func sortedDurations(ds []time.Duration) []time.Duration {
+	sorted := slices.Clone(ds)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// percentile returns the nearest-rank percentile p (0..1) of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
+
+// WrapRegistryMetrics decorates every tool of the registry that is not
+// metered yet, so it can be called again after late registrations. Call it
+// BEFORE the other wrappers so durations exclude approval waits:
+// DangerousToolWrapper → ... → MeteredTool → inner tool.
+func WrapRegistryMetrics(registry *ToolRegistry, metrics *ToolMetrics) {
+	for _, name := range registry.ToolNames() {
+		metrics.mu.Lock()
+		done := metrics.wrapped[name]
+		metrics.wrapped[name] = true
+		metrics.mu.Unlock()
+		if done {
+			continue
+		}
+		wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
+			return &MeteredTool{inner: t, name: name, metrics: metrics}
+		})
+	}
+}
+
+// MeteredTool records the duration and outcome of every call to the inner tool.
+type MeteredTool struct {
+	inner   brain.Tool
+	name    string
+	metrics *ToolMetrics
+}
+
+// Info delegates to the inner tool.
+func (t *MeteredTool) Info(ctx context.Context) (*brain.ToolInfo, error) {
+	return t.inner.Info(ctx)
+}
+
+// Run times the inner tool.
+func (t *MeteredTool) Run(ctx context.Context, argumentsInJSON string) (string, error) {
+	start := time.Now()
+	out, err := t.inner.Run(ctx, argumentsInJSON)
+	t.metrics.Record(t.name, time.Since(start), err)
+	return out, err
+}
+
+var _ brain.Tool = (*MeteredTool)(nil)
@@ -0,0 +1,68 @@
+package hands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestToolMetrics_Stats(t *testing.T) {
+	m := NewToolMetrics(0)
+	for i := 1; i <= 20; i++ {
+		var err error
+		if i%5 == 0 {
+			err = errors.New("boom")
+		}
+		m.Record("run_command", time.Duration(i)*time.Millisecond, err)
+	}
+	m.Record("git", 3*time.Millisecond, nil)
+
+	stats := m.Stats()
+	if len(stats) != 2 || stats[0].Name != "git" || stats[1].Name != "run_command" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	rc := stats[1]
+	if rc.Calls != 20 || rc.Errors != 4 || rc.ErrorRate != 0.2 {
+		t.Errorf("counts = %+v", rc)
+	}
+	if rc.TotalMs != 210 || rc.MedianMs != 10 || rc.P95Ms != 19 {
+		t.Errorf("durations = %+v", rc)
+	}
+}
+
+func TestToolMetrics_SlowFlag(t *testing.T) {
+	m := NewToolMetrics(50 * time.Millisecond)
+	for range slowToolMinCalls {
+		m.Record("web_fetch", 100*time.Millisecond, nil)
+	}
+	if !m.tools["web_fetch"].slow {
+		t.Fatal("expected web_fetch to be flagged slow")
+	}
+	for range slowToolMinCalls + 1 {
+		m.Record("web_fetch", time.Millisecond, nil)
+	}
+	if m.tools["web_fetch"].slow {
+		t.Error("expected web_fetch to recover")
+	}
+}
+
+func TestWrapRegistryMetrics(t *testing.T) {
+	registry := NewToolRegistry(nil)
+	if err := registry.RegisterNative("get_var", NewGetVarTool(nil), GetVarManifest()); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewToolMetrics(0)
+	WrapRegistryMetrics(registry, m)
+	WrapRegistryMetrics(registry, m) // already metered: no double wrapping
+
+	// Invalid input fails before touching the (nil) store.
+	if _, err := registry.Tool("get_var").InvokableRun(context.Background(), `{`); err == nil {
+		t.Fatal("expected error")
+	}
+	stats := m.Stats()
+	if len(stats) != 1 || stats[0].Calls != 1 || stats[0].Errors != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
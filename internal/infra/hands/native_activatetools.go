@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
@@ -18,6 +22,7 @@ import (
 type ToolActivator interface {
 	Activate(sessionID, toolName string) bool
 	IsKnown(toolName string) bool
+	IsActive(sessionID, toolName string) bool
 }
 
 // =============================================================================
@@ -51,12 +56,20 @@ func ActivateManifest() *PluginManifest {
 		Tools: []ToolSpec{
 			{
 				Name:        ToolActivate,
-				Description: "Activate additional tools or skills to make them available. For tools: activates them for use on the next message. For skills: loads the skill's instructions and activates its allowed tools. Skills with a workflow will also activate run_workflow.",
+				Description: "Activate additional tools or skills to make them available. For tools: activates them for use on the next message. For skills: loads the skill's instructions and activates its allowed tools. Skills with a workflow will also activate run_workflow. Use tag or capability to activate every matching tool (see list_available_tools).",
 				Parameters: map[string]ParamSpec{
 					"names": {
 						Type:        "array",
 						Description: "List of tool or skill names to activate (e.g. [\"docker_build\", \"deploy\"])",
-						Required:    true,
+						Items:       &ParamSpec{Type: "string"},
+					},
+					"tag": {
+						Type:        "string",
+						Description: "Activate every tool carrying this tag (e.g. \"web\")",
+					},
+					"capability": {
+						Type:        "string",
+						Description: "Activate every plugin tool declaring this capability (e.g. \"http\")",
 					},
 				},
 			},
@@ -65,7 +78,9 @@ func ActivateManifest() *PluginManifest {
 }
 
 type activateInput struct {
-	Names []string `json:"names"`
+	Names      []string `json:"names"`
+	Tag        string   `json:"tag,omitempty"`
+	Capability string   `json:"capability,omitempty"`
 }
 
 type activateOutput struct {
@@ -97,15 +112,32 @@ func (t *ActivateTool) InvokableRun(ctx context.Context, argumentsInJSON string,
 
 	var input activateInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("activate: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "activate: parse input: %w", err)
 	}
 
-	if len(input.Names) == 0 {
-		return "", fmt.Errorf("activate: names list is empty")
+	if len(input.Names) == 0 && input.Tag == "" && input.Capability == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "activate: one of names, tag or capability is required")
 	}
 
 	var out activateOutput
-	for _, name := range input.Names {
+	names := input.Names
+	if input.Tag != "" || input.Capability != "" {
+		matched := 0
+		for _, name := range t.registry.ToolsMatching(input.Tag, input.Capability) {
+			if !t.activator.IsKnown(name) || t.activator.IsActive(sessionID, name) {
+				continue
+			}
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+			matched++
+		}
+		if matched == 0 {
+			out.Errors = append(out.Errors, fmt.Sprintf("no inactive tools match %s", describeFilter(input.Tag, input.Capability)))
+		}
+	}
+
+	for _, name := range names {
 		// Try tool first
 		if t.activator.IsKnown(name) {
 			if ok := t.activator.Activate(sessionID, name); !ok {
@@ -169,6 +201,120 @@ func (t *ActivateTool) InvokableRun(ctx context.Context, argumentsInJSON string,
 
 var _ tool.InvokableTool = (*ActivateTool)(nil)
 
+// describeFilter renders a tag/capability filter for messages.
+func describeFilter(tag, capability string) string {
+	var parts []string
+	if tag != "" {
+		parts = append(parts, fmt.Sprintf("tag %q", tag))
+	}
+	if capability != "" {
+		parts = append(parts, fmt.Sprintf("capability %q", capability))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// =============================================================================
+// list_available_tools
+// =============================================================================
+
+// ListAvailableToolsTool lists the tools that can be activated for the
+// current session, so the agent can discover them without knowing names.
+type ListAvailableToolsTool struct {
+	activator ToolActivator
+	registry  *ToolRegistry
+}
+
+// NewListAvailableToolsTool creates a new list_available_tools tool.
+func NewListAvailableToolsTool(activator ToolActivator, registry *ToolRegistry) *ListAvailableToolsTool {
+	return &ListAvailableToolsTool{activator: activator, registry: registry}
+}
+
+// ListAvailableToolsManifest returns the plugin manifest for the list_available_tools tool.
+func ListAvailableToolsManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        ToolListAvailable,
+		Description: "List inactive tools that can be activated",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   false,
+		Tools: []ToolSpec{
+			{
+				Name:        ToolListAvailable,
+				Description: "List the tools not yet active in this session, with their descriptions and tags. Activate them with activate (by name, tag or capability).",
+				Parameters: map[string]ParamSpec{
+					"tag": {
+						Type:        "string",
+						Description: "Only list tools carrying this tag",
+					},
+					"capability": {
+						Type:        "string",
+						Description: "Only list plugin tools declaring this capability",
+					},
+				},
+			},
+		},
+	}
+}
+
+type listAvailableToolsInput struct {
+	Tag        string `json:"tag,omitempty"`
+	Capability string `json:"capability,omitempty"`
+}
+
+type availableToolEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *ListAvailableToolsTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&ListAvailableToolsManifest().Tools[0]), nil
+}
+
+// InvokableRun lists the inactive tools, optionally filtered by tag or capability.
+func (t *ListAvailableToolsTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	sessionID := events.SessionIDFromContext(ctx)
+	if sessionID == "" {
+		return "", fmt.Errorf("list_available_tools: no session in context")
+	}
+
+	var input listAvailableToolsInput
+	if argumentsInJSON != "" {
+		if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+			return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "list_available_tools: parse input: %w", err)
+		}
+	}
+
+	var names []string
+	if input.Tag != "" || input.Capability != "" {
+		names = t.registry.ToolsMatching(input.Tag, input.Capability)
+	} else {
+		names = t.registry.ToolNames()
+		sort.Strings(names)
+	}
+
+	entries := []availableToolEntry{}
+	for _, name := range names {
+		if !t.activator.IsKnown(name) || t.activator.IsActive(sessionID, name) {
+			continue
+		}
+		entry := availableToolEntry{Name: name, Tags: t.registry.ToolTags(name)}
+		if spec := t.registry.ToolSpec(name); spec != nil {
+			entry.Description = spec.Description
+		}
+		entries = append(entries, entry)
+	}
+
+	result, err := json.Marshal(map[string]any{"tools": entries})
+	if err != nil {
+		return "", fmt.Errorf("list_available_tools: marshal result: %w", err)
+	}
+	return string(result), nil
+}
+
+var _ tool.InvokableTool = (*ListAvailableToolsTool)(nil)
+
 // listResources scans optional subdirectories for resources.
 func listResources(dir string) []string {
 	if dir == "" {
@@ -0,0 +1,148 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+)
+
+// maxArtifactSize bounds the size of a registered artifact (10 MiB).
+const maxArtifactSize = 10 << 20
+
+// RegisterArtifactTool copies a file produced by a task into the task
+// directory and records it in the task result.
+type RegisterArtifactTool struct {
+	store tasks.Store
+}
+
+// NewRegisterArtifactTool creates a new register_artifact tool.
+func NewRegisterArtifactTool(store tasks.Store) *RegisterArtifactTool {
+	return &RegisterArtifactTool{store: store}
+}
+
+// RegisterArtifactManifest returns the plugin manifest for the register_artifact tool.
+func RegisterArtifactManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        tasks.RegisterArtifactTool,
+		Description: "Register a file produced by the current task as a task artifact",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   false,
+		Tags:        []string{"files"},
+		Tools: []ToolSpec{
+			{
+				Name: tasks.RegisterArtifactTool,
+				Description: "Register a file you produced (generated code, report, data) as an artifact of the current task. " +
+					"The file is copied into the task's storage so the user can list and download it after the task ends. " +
+					"Only available inside an async task.",
+				Parameters: map[string]ParamSpec{
+					"path": {
+						Type:        "string",
+						Description: "Path of the file to register (relative paths resolve against the task work dir)",
+						Required:    true,
+					},
+					"description": {
+						Type:        "string",
+						Description: "Short description of what the artifact contains",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Artifact file name (default: the file's base name); replaces an artifact of the same name",
+					},
+				},
+			},
+		},
+	}
+}
+
+type registerArtifactInput struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Name        string `json:"name"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *RegisterArtifactTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&RegisterArtifactManifest().Tools[0]), nil
+}
+
+// InvokableRun copies the file into the task directory and records it.
+func (t *RegisterArtifactTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	var input registerArtifactInput
+	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "register_artifact: parse input: %w", err)
+	}
+	if input.Path == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "register_artifact: path is required")
+	}
+
+	taskID := events.TaskIDFromContext(ctx)
+	if taskID == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "register_artifact: only available inside an async task")
+	}
+
+	path := input.Path
+	if !filepath.IsAbs(path) {
+		if wd := events.WorkDirFromContext(ctx); wd != "" {
+			path = filepath.Join(wd, path)
+		}
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("register_artifact: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("register_artifact: %w", err)
+	}
+	if info.IsDir() {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "register_artifact: %s is a directory", input.Path)
+	}
+	if info.Size() > maxArtifactSize {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "register_artifact: %s is too large (%d bytes, max %d)", input.Path, info.Size(), maxArtifactSize)
+	}
+
+	name := input.Name
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	if err := tasks.ValidateArtifactName(name); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "register_artifact: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("register_artifact: %w", err)
+	}
+
+	artifact := tasks.Artifact{
+		Name:        name,
+		Description: input.Description,
+		SourcePath:  path,
+		CreatedAt:   time.Now(),
+	}
+	if err := t.store.WriteArtifact(taskID, artifact, content); err != nil {
+		return "", fmt.Errorf("register_artifact: %w", err)
+	}
+
+	result, _ := json.Marshal(map[string]any{
+		"task_id": taskID,
+		"name":    name,
+		"size":    len(content),
+		"status":  "registered",
+	})
+	return string(result), nil
+}
+
+var _ tool.InvokableTool = (*RegisterArtifactTool)(nil)
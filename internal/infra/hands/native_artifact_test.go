@@ -0,0 +1,70 @@
+package hands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+)
+
+func TestRegisterArtifactTool(t *testing.T) {
+	store := tasks.NewFileStore(t.TempDir())
+	task := &tasks.Task{Title: "report", Status: tasks.TaskRunning}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "report.md"), []byte("# Report\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewRegisterArtifactTool(store)
+	ctx := events.ContextWithWorkDir(events.ContextWithTaskID(context.Background(), task.ID), workDir)
+
+	if _, err := tool.InvokableRun(ctx, `{"path":"report.md","description":"Final report"}`); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	a, content, err := store.ReadArtifact(task.ID, "report.md")
+	if err != nil {
+		t.Fatalf("ReadArtifact: %v", err)
+	}
+	if string(content) != "# Report\n" || a.Description != "Final report" || a.Size != 9 {
+		t.Errorf("artifact = %+v, content %q", a, content)
+	}
+	if a.SourcePath != filepath.Join(workDir, "report.md") {
+		t.Errorf("source path = %q", a.SourcePath)
+	}
+
+	// Re-registering under the same name replaces the artifact.
+	if _, err := tool.InvokableRun(ctx, `{"path":"report.md","name":"report.md","description":"v2"}`); err != nil {
+		t.Fatalf("re-register: %v", err)
+	}
+	if list, _ := store.ListArtifacts(task.ID); len(list) != 1 || list[0].Description != "v2" {
+		t.Errorf("artifacts after replace = %+v", list)
+	}
+
+	for _, tc := range []struct {
+		ctx           context.Context
+		args, wantErr string
+		wantCode      brain.ToolErrorCode
+	}{
+		{context.Background(), `{"path":"report.md"}`, "only available inside an async task", brain.ToolErrInvalidInput},
+		{ctx, `{"path":"missing.md"}`, "no such file", brain.ToolErrNotFound},
+		{ctx, `{"path":"."}`, "is a directory", brain.ToolErrInvalidInput},
+		{ctx, `{"path":"report.md","name":"../escape"}`, "invalid artifact name", brain.ToolErrInvalidInput},
+	} {
+		_, err := tool.InvokableRun(tc.ctx, tc.args)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("InvokableRun(%s) error = %v, want %q", tc.args, err, tc.wantErr)
+		}
+		if code := brain.ToolErrorCodeOf(err); code != tc.wantCode {
+			t.Errorf("InvokableRun(%s) code = %q, want %q", tc.args, code, tc.wantCode)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
@@ -56,6 +57,7 @@ func ExecuteManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   true,
+		Tags:        []string{"shell"},
 		Capabilities: PluginCapabilities{
 			Exec: true,
 		},
@@ -110,10 +112,10 @@ func (t *ExecuteTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *ExecuteTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input executeInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("run_command: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "run_command: parse input: %w", err)
 	}
 	if input.Command == "" {
-		return "", fmt.Errorf("run_command: command is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "run_command: command is required")
 	}
 
 	timeout := defaultExecuteTimeout
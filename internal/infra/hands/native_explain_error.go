@@ -0,0 +1,325 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+)
+
+// Explain-error output bounds — enough context to debug, small enough for a prompt.
+const (
+	explainMaxEvents      = 20
+	explainMaxCheckpoints = 10
+	explainOutputTail     = 1500
+	explainSummaryMax     = 300
+)
+
+// ExplainErrorTool gathers the full context of a failed task or tool call
+// (error, checkpoints, output, correlated log events) for the agent to analyze.
+type ExplainErrorTool struct {
+	store   tasks.Store
+	logsDir string // EventLogger directory (one JSONL per session)
+}
+
+// NewExplainErrorTool creates a new explain_error tool.
+func NewExplainErrorTool(store tasks.Store, logsDir string) *ExplainErrorTool {
+	return &ExplainErrorTool{store: store, logsDir: logsDir}
+}
+
+// ExplainErrorManifest returns the plugin manifest for the explain_error tool.
+func ExplainErrorManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        "explain_error",
+		Description: "Retrieve the full context of a failed task or tool call",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   false,
+		Tags:        []string{"diagnostics"},
+		Tools: []ToolSpec{
+			{
+				Name: "explain_error",
+				Description: "Retrieve the full error context of a failed task or tool call: error message, arguments, " +
+					"checkpoints, output tail and correlated log events. Use it when the user asks why something failed. " +
+					"Without an id, explains the most recent failure in the current session.",
+				Parameters: map[string]ParamSpec{
+					"id": {
+						Type:        "string",
+						Description: "Task ID or tool-call event ID (optional, defaults to the last failure)",
+					},
+				},
+			},
+		},
+	}
+}
+
+type explainErrorInput struct {
+	ID string `json:"id"`
+}
+
+// explainEvent is a compact view of a logged event.
+type explainEvent struct {
+	ID      string `json:"id"`
+	Ts      string `json:"ts"`
+	Type    string `json:"type"`
+	Summary string `json:"summary"`
+}
+
+type explainErrorOutput struct {
+	Kind          string             `json:"kind"` // "task" or "tool_call"
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Status        string             `json:"status,omitempty"`
+	Error         string             `json:"error,omitempty"`
+	Arguments     string             `json:"arguments,omitempty"`
+	RetryCount    int                `json:"retry_count,omitempty"`
+	Checkpoints   []tasks.Checkpoint `json:"checkpoints,omitempty"`
+	OutputTail    string             `json:"output_tail,omitempty"`
+	RelatedEvents []explainEvent     `json:"related_events,omitempty"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *ExplainErrorTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&ExplainErrorManifest().Tools[0]), nil
+}
+
+// InvokableRun resolves the id to a task or tool-call event and returns its context.
+func (t *ExplainErrorTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	var input explainErrorInput
+	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "explain_error: parse input: %w", err)
+	}
+	sessionID := events.SessionIDFromContext(ctx)
+
+	var out *explainErrorOutput
+	var err error
+	switch {
+	case input.ID == "":
+		out, err = t.explainLastFailure(sessionID)
+	default:
+		if task, getErr := t.store.Get(input.ID); getErr == nil {
+			out, err = t.explainTask(task)
+		} else {
+			out, err = t.explainToolCall(sessionID, input.ID)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("explain_error: %w", err)
+	}
+
+	result, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("explain_error: marshal: %w", err)
+	}
+	return string(result), nil
+}
+
+// explainLastFailure finds the most recent failed tool call or task in the session log.
+func (t *ExplainErrorTool) explainLastFailure(sessionID string) (*explainErrorOutput, error) {
+	if sessionID == "" {
+		return nil, brain.ToolErrorf(brain.ToolErrInvalidInput, "no session in context, an id is required")
+	}
+	evts, err := events.ReadEventLog(t.logsDir, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+	for i := len(evts) - 1; i >= 0; i-- {
+		e := evts[i]
+		switch e.Type {
+		case events.EventTaskFailed:
+			p, ok := events.GetTaskFailedPayload(e)
+			if !ok {
+				continue
+			}
+			task, err := t.store.Get(p.TaskID)
+			if err != nil {
+				return nil, fmt.Errorf("task %s: %w", p.TaskID, err)
+			}
+			return t.explainTaskWithEvents(task, evts), nil
+		case events.EventToolCall:
+			if p, ok := events.GetToolCallPayload(e); ok && p.Status == events.ToolStatusFailed {
+				return explainToolCallAt(evts, i, p), nil
+			}
+		}
+	}
+	return nil, brain.ToolErrorf(brain.ToolErrNotFound, "no failure found in session %s", sessionID)
+}
+
+// explainTask builds the context of a task from its store entry and session log.
+func (t *ExplainErrorTool) explainTask(task *tasks.Task) (*explainErrorOutput, error) {
+	var evts []events.Event
+	if task.SessionID != "" {
+		var err error
+		if evts, err = events.ReadEventLog(t.logsDir, task.SessionID); err != nil {
+			return nil, fmt.Errorf("read event log: %w", err)
+		}
+	}
+	return t.explainTaskWithEvents(task, evts), nil
+}
+
+func (t *ExplainErrorTool) explainTaskWithEvents(task *tasks.Task, evts []events.Event) *explainErrorOutput {
+	out := &explainErrorOutput{
+		Kind:       "task",
+		ID:         task.ID,
+		Name:       task.Title,
+		Status:     string(task.Status),
+		RetryCount: task.RetryCount,
+	}
+	if task.Result != nil {
+		out.Error = task.Result.Error
+	}
+
+	if cps, err := t.store.LoadCheckpoints(task.ID); err == nil {
+		if len(cps) > explainMaxCheckpoints {
+			cps = cps[len(cps)-explainMaxCheckpoints:]
+		}
+		out.Checkpoints = cps
+	}
+	if output, err := t.store.ReadOutput(task.ID); err == nil && output != "" {
+		if len(output) > explainOutputTail {
+			output = "..." + output[len(output)-explainOutputTail:]
+		}
+		out.OutputTail = output
+	}
+
+	// Correlate: task lifecycle events for this task, plus failures logged
+	// in the session while the task was running.
+	start := task.CreatedAt
+	if task.StartedAt != nil {
+		start = *task.StartedAt
+	}
+	end := task.UpdatedAt
+	if task.CompletedAt != nil {
+		end = *task.CompletedAt
+	}
+	end = end.Add(time.Second)
+
+	var related []explainEvent
+	for _, e := range evts {
+		if taskID, _ := e.Payload["task_id"].(string); taskID == task.ID {
+			related = append(related, summarizeEvent(e))
+			continue
+		}
+		if e.Timestamp.Before(start) || e.Timestamp.After(end) {
+			continue
+		}
+		if isFailureEvent(e) {
+			related = append(related, summarizeEvent(e))
+		}
+	}
+	out.RelatedEvents = lastEvents(related, explainMaxEvents)
+	return out
+}
+
+// explainToolCall locates a tool-call event by ID in the session log.
+func (t *ExplainErrorTool) explainToolCall(sessionID, eventID string) (*explainErrorOutput, error) {
+	if sessionID == "" {
+		return nil, brain.ToolErrorf(brain.ToolErrNotFound, "no task with id %q and no session to search for a tool call", eventID)
+	}
+	evts, err := events.ReadEventLog(t.logsDir, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+	for i, e := range evts {
+		if e.ID != eventID {
+			continue
+		}
+		p, ok := events.GetToolCallPayload(e)
+		if e.Type != events.EventToolCall || !ok {
+			return nil, brain.ToolErrorf(brain.ToolErrInvalidInput, "event %s is a %s event, not a tool call", eventID, e.Type)
+		}
+		return explainToolCallAt(evts, i, p), nil
+	}
+	return nil, brain.ToolErrorf(brain.ToolErrNotFound, "no task or tool call with id %q", eventID)
+}
+
+// explainToolCallAt builds the context of the tool call at evts[idx]: its
+// arguments (from the matching "started" event) and the events leading up to it.
+func explainToolCallAt(evts []events.Event, idx int, p events.ToolCallPayload) *explainErrorOutput {
+	out := &explainErrorOutput{
+		Kind:   "tool_call",
+		ID:     evts[idx].ID,
+		Name:   p.Name,
+		Status: string(p.Status),
+		Error:  p.Error,
+	}
+
+	for i := idx - 1; i >= 0; i-- {
+		if evts[i].Type != events.EventToolCall {
+			continue
+		}
+		if sp, ok := events.GetToolCallPayload(evts[i]); ok && sp.Name == p.Name && sp.Status == events.ToolStatusStarted {
+			if raw, ok := sp.Arguments["raw"].(string); ok {
+				out.Arguments = raw
+			}
+			break
+		}
+	}
+
+	var related []explainEvent
+	for _, e := range evts[:idx] {
+		related = append(related, summarizeEvent(e))
+	}
+	out.RelatedEvents = lastEvents(related, explainMaxEvents)
+	return out
+}
+
+// isFailureEvent reports whether an event records an error.
+func isFailureEvent(e events.Event) bool {
+	switch e.Type {
+	case events.EventToolCall:
+		p, ok := events.GetToolCallPayload(e)
+		return ok && p.Status == events.ToolStatusFailed
+	case events.EventTaskFailed:
+		return true
+	default:
+		errMsg, _ := e.Payload["error"].(string)
+		return errMsg != ""
+	}
+}
+
+// summarizeEvent renders a one-line summary of an event.
+func summarizeEvent(e events.Event) explainEvent {
+	var summary string
+	switch e.Type {
+	case events.EventToolCall:
+		if p, ok := events.GetToolCallPayload(e); ok {
+			summary = p.Name + " " + string(p.Status)
+			if p.Error != "" {
+				summary += ": " + p.Error
+			}
+		}
+	default:
+		if errMsg, _ := e.Payload["error"].(string); errMsg != "" {
+			summary = "error: " + errMsg
+		} else if data, err := json.Marshal(e.Payload); err == nil {
+			summary = string(data)
+		}
+	}
+	if len(summary) > explainSummaryMax {
+		summary = summary[:explainSummaryMax] + "..."
+	}
+	return explainEvent{
+		ID:      e.ID,
+		Ts:      e.Timestamp.Format(time.RFC3339),
+		Type:    string(e.Type),
+		Summary: summary,
+	}
+}
+
+// lastEvents keeps the n most recent entries.
+func lastEvents(evts []explainEvent, n int) []explainEvent {
+	if len(evts) > n {
+		return evts[len(evts)-n:]
+	}
+	return evts
+}
+
+var _ tool.InvokableTool = (*ExplainErrorTool)(nil)
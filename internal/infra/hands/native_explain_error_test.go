@@ -0,0 +1,139 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+)
+
+// writeEventLog writes events as the EventLogger would for a session.
+func writeEventLog(t *testing.T, dir, sessionID string, evts ...events.Event) {
+	t.Helper()
+	var sb strings.Builder
+	for _, e := range evts {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.Write(data)
+		sb.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(dir, sessionID+".jsonl"), []byte(sb.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runExplainError(t *testing.T, tool *ExplainErrorTool, ctx context.Context, args string) explainErrorOutput {
+	t.Helper()
+	result, err := tool.InvokableRun(ctx, args)
+	if err != nil {
+		t.Fatalf("explain_error: %v", err)
+	}
+	var out explainErrorOutput
+	if err := json.Unmarshal([]byte(result), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestExplainError_ToolCall(t *testing.T) {
+	logsDir := t.TempDir()
+	store := tasks.NewFileStore(t.TempDir())
+	ctx := events.ContextWithSessionID(context.Background(), "sess_1")
+
+	started := events.NewTypedEventWithSession(events.SourceAgent, events.ToolCallPayload{
+		Status:    events.ToolStatusStarted,
+		Name:      "run_command",
+		Arguments: map[string]any{"raw": `{"command":"make test"}`},
+	}, "sess_1")
+	failed := events.NewTypedEventWithSession(events.SourceAgent, events.ToolCallPayload{
+		Status: events.ToolStatusFailed,
+		Name:   "run_command",
+		Error:  "exit status 2: undefined: foo",
+	}, "sess_1")
+	writeEventLog(t, logsDir, "sess_1", started, failed)
+
+	tool := NewExplainErrorTool(store, logsDir)
+
+	// By event ID
+	out := runExplainError(t, tool, ctx, `{"id":"`+failed.ID+`"}`)
+	if out.Kind != "tool_call" || out.Name != "run_command" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if out.Error != "exit status 2: undefined: foo" {
+		t.Errorf("expected full error, got %q", out.Error)
+	}
+	if out.Arguments != `{"command":"make test"}` {
+		t.Errorf("expected arguments from started event, got %q", out.Arguments)
+	}
+
+	// Without ID: last failure
+	out = runExplainError(t, tool, ctx, `{}`)
+	if out.ID != failed.ID {
+		t.Errorf("expected last failure %s, got %s", failed.ID, out.ID)
+	}
+}
+
+func TestExplainError_Task(t *testing.T) {
+	logsDir := t.TempDir()
+	store := tasks.NewFileStore(t.TempDir())
+	ctx := events.ContextWithSessionID(context.Background(), "sess_1")
+
+	task := &tasks.Task{Title: "Build release", SessionID: "sess_1", Status: tasks.TaskPending}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.AppendCheckpoint(task.ID, tasks.Checkpoint{Ts: time.Now(), Type: "step", Summary: "compiling"})
+	_ = store.WriteOutput(task.ID, "partial build log")
+
+	task.Status = tasks.TaskFailed
+	task.Result = &tasks.TaskResult{Error: "compile failed"}
+	if err := store.Update(task); err != nil {
+		t.Fatal(err)
+	}
+
+	toolFailed := events.NewTypedEventWithSession(events.SourceAgent, events.ToolCallPayload{
+		Status: events.ToolStatusFailed,
+		Name:   "run_command",
+		Error:  "go build: missing module",
+	}, "sess_1")
+	taskFailed := events.NewTypedEventWithSession(events.SourceTask, events.TaskFailedPayload{
+		TaskID: task.ID,
+		Title:  task.Title,
+		Error:  "compile failed",
+	}, "sess_1")
+	writeEventLog(t, logsDir, "sess_1", toolFailed, taskFailed)
+
+	tool := NewExplainErrorTool(store, logsDir)
+	for _, args := range []string{`{"id":"` + task.ID + `"}`, `{}`} {
+		out := runExplainError(t, tool, ctx, args)
+		if out.Kind != "task" || out.ID != task.ID || out.Error != "compile failed" {
+			t.Fatalf("%s: unexpected output: %+v", args, out)
+		}
+		if len(out.Checkpoints) != 1 || out.OutputTail != "partial build log" {
+			t.Errorf("%s: expected checkpoints and output, got %+v", args, out)
+		}
+		if len(out.RelatedEvents) != 2 || !strings.Contains(out.RelatedEvents[0].Summary, "missing module") {
+			t.Errorf("%s: expected correlated tool failure, got %+v", args, out.RelatedEvents)
+		}
+	}
+}
+
+func TestExplainError_NotFound(t *testing.T) {
+	tool := NewExplainErrorTool(tasks.NewFileStore(t.TempDir()), t.TempDir())
+	ctx := events.ContextWithSessionID(context.Background(), "sess_1")
+
+	if _, err := tool.InvokableRun(ctx, `{"id":"nope"}`); err == nil {
+		t.Error("expected error for unknown id")
+	}
+	if _, err := tool.InvokableRun(ctx, `{}`); err == nil {
+		t.Error("expected error when the session has no failures")
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"os/exec"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
@@ -32,6 +34,7 @@ func GitManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   true,
+		Tags:        []string{"vcs"},
 		Capabilities: PluginCapabilities{
 			Exec: true,
 		},
@@ -48,7 +51,7 @@ func GitManifest() *PluginManifest {
 					},
 					"args": {
 						Type:        "object",
-						Description: "Action-specific arguments (JSON object)",
+						Description: "Action-specific arguments (JSON object): status {path}, diff {paths, staged}, log {path, max (default 10, max 100)}, add {paths}, commit {message}, branch {name, list}, checkout {ref}. diff also returns per-file line counts and log returns structured commits.",
 					},
 				},
 				Dangerous: true,
@@ -63,8 +66,26 @@ type gitInput struct {
 }
 
 type gitResult struct {
-	Output   string `json:"output"`
-	ExitCode int    `json:"exit_code"`
+	Output   string        `json:"output"`
+	ExitCode int           `json:"exit_code"`
+	Files    []gitDiffFile `json:"files,omitempty"`   // diff only
+	Commits  []gitLogEntry `json:"commits,omitempty"` // log only
+}
+
+// gitDiffFile is one file of a diff, from --numstat.
+type gitDiffFile struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary,omitempty"`
+}
+
+// gitLogEntry is one commit of a log.
+type gitLogEntry struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"` // ISO 8601
+	Subject string `json:"subject"`
 }
 
 // Action-specific arg structs
@@ -73,8 +94,9 @@ type gitStatusArgs struct {
 }
 
 type gitDiffArgs struct {
-	Path   string `json:"path"`
-	Staged bool   `json:"staged"`
+	Path   string   `json:"path"` // single path, kept for compatibility with paths
+	Paths  []string `json:"paths"`
+	Staged bool     `json:"staged"`
 }
 
 type gitLogArgs struct {
@@ -108,10 +130,10 @@ func (t *GitTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *GitTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input gitInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("git: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "git: parse input: %w", err)
 	}
 	if input.Action == "" {
-		return "", fmt.Errorf("git: action is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "git: action is required")
 	}
 
 	workDir := events.WorkDirFromContext(ctx)
@@ -135,7 +157,7 @@ func (t *GitTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ..
 	case "checkout":
 		result, err = gitCheckout(ctx, workDir, input.Args)
 	default:
-		return "", fmt.Errorf("git: unknown action %q", input.Action)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "git: unknown action %q", input.Action)
 	}
 	if err != nil {
 		return "", err
@@ -152,7 +174,7 @@ func gitStatus(ctx context.Context, dir string, rawArgs json.RawMessage) (gitRes
 	var args gitStatusArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git status: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git status: parse args: %w", err)
 		}
 	}
 	cmdArgs := []string{"status", "--porcelain"}
@@ -166,24 +188,64 @@ func gitDiff(ctx context.Context, dir string, rawArgs json.RawMessage) (gitResul
 	var args gitDiffArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git diff: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git diff: parse args: %w", err)
 		}
 	}
-	cmdArgs := []string{"diff"}
-	if args.Staged {
-		cmdArgs = append(cmdArgs, "--staged")
-	}
+	paths := args.Paths
 	if args.Path != "" {
-		cmdArgs = append(cmdArgs, args.Path)
+		paths = append(paths, args.Path)
 	}
-	return execGit(ctx, dir, cmdArgs...)
+	diffArgs := func(extra ...string) []string {
+		cmdArgs := append([]string{"diff"}, extra...)
+		if args.Staged {
+			cmdArgs = append(cmdArgs, "--staged")
+		}
+		if len(paths) > 0 {
+			cmdArgs = append(append(cmdArgs, "--"), paths...)
+		}
+		return cmdArgs
+	}
+
+	result, err := execGit(ctx, dir, diffArgs()...)
+	if err != nil || result.ExitCode != 0 {
+		return result, err
+	}
+	stat, err := execGit(ctx, dir, diffArgs("--numstat")...)
+	if err != nil {
+		return gitResult{}, err
+	}
+	if stat.ExitCode == 0 {
+		result.Files = parseGitNumstat(stat.Output)
+	}
+	return result, nil
+}
+
+// parseGitNumstat parses `git diff --numstat` lines ("added\tdeleted\tpath";
+// binary files report "-" counts).
+func parseGitNumstat(out string) []gitDiffFile {
+	var files []gitDiffFile
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := gitDiffFile{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			f.Binary = true
+		} else {
+			f.Additions, _ = strconv.Atoi(fields[0])
+			f.Deletions, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, f)
+	}
+	return files
 }
 
 func gitLog(ctx context.Context, dir string, rawArgs json.RawMessage) (gitResult, error) {
 	var args gitLogArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git log: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git log: parse args: %w", err)
 		}
 	}
 	max := args.Max
@@ -193,22 +255,50 @@ func gitLog(ctx context.Context, dir string, rawArgs json.RawMessage) (gitResult
 	if max > 100 {
 		max = 100
 	}
-	cmdArgs := []string{"log", "--oneline", "-" + strconv.Itoa(max)}
+	cmdArgs := []string{"log", "--format=" + gitLogFormat, "-" + strconv.Itoa(max)}
 	if args.Path != "" {
 		cmdArgs = append(cmdArgs, "--", args.Path)
 	}
-	return execGit(ctx, dir, cmdArgs...)
+	result, err := execGit(ctx, dir, cmdArgs...)
+	if err != nil || result.ExitCode != 0 {
+		return result, err
+	}
+	result.Commits = parseGitLog(result.Output)
+	result.Output = ""
+	return result, nil
+}
+
+// gitLogFormat separates fields with US (0x1f) and records with RS (0x1e),
+// which cannot appear in commit metadata.
+const gitLogFormat = "%H%x1f%an%x1f%aI%x1f%s%x1e"
+
+// parseGitLog parses `git log --format=gitLogFormat` output.
+func parseGitLog(out string) []gitLogEntry {
+	var commits []gitLogEntry
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, gitLogEntry{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    fields[2],
+			Subject: fields[3],
+		})
+	}
+	return commits
 }
 
 func gitAdd(ctx context.Context, dir string, rawArgs json.RawMessage) (gitResult, error) {
 	var args gitAddArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git add: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git add: parse args: %w", err)
 		}
 	}
 	if len(args.Paths) == 0 {
-		return gitResult{}, fmt.Errorf("git add: paths are required")
+		return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git add: paths are required")
 	}
 	cmdArgs := append([]string{"add"}, args.Paths...)
 	return execGit(ctx, dir, cmdArgs...)
@@ -218,11 +308,11 @@ func gitCommit(ctx context.Context, dir string, rawArgs json.RawMessage) (gitRes
 	var args gitCommitArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git commit: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git commit: parse args: %w", err)
 		}
 	}
 	if args.Message == "" {
-		return gitResult{}, fmt.Errorf("git commit: message is required")
+		return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git commit: message is required")
 	}
 	return execGit(ctx, dir, "commit", "-m", args.Message)
 }
@@ -231,7 +321,7 @@ func gitBranch(ctx context.Context, dir string, rawArgs json.RawMessage) (gitRes
 	var args gitBranchArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git branch: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git branch: parse args: %w", err)
 		}
 	}
 	if args.List || args.Name == "" {
@@ -244,11 +334,11 @@ func gitCheckout(ctx context.Context, dir string, rawArgs json.RawMessage) (gitR
 	var args gitCheckoutArgs
 	if len(rawArgs) > 0 {
 		if err := json.Unmarshal(rawArgs, &args); err != nil {
-			return gitResult{}, fmt.Errorf("git checkout: parse args: %w", err)
+			return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git checkout: parse args: %w", err)
 		}
 	}
 	if args.Ref == "" {
-		return gitResult{}, fmt.Errorf("git checkout: ref is required")
+		return gitResult{}, brain.ToolErrorf(brain.ToolErrInvalidInput, "git checkout: ref is required")
 	}
 	return execGit(ctx, dir, "checkout", args.Ref)
 }
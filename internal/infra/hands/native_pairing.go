@@ -8,6 +8,7 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/core/policy"
 )
@@ -36,6 +37,7 @@ func ApprovePairingManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"pairing"},
 		Tools: []ToolSpec{
 			{
 				Name:        "approve_pairing",
@@ -89,22 +91,22 @@ func (t *ApprovePairingTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *ApprovePairingTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input approvePairingInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("approve_pairing: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "approve_pairing: parse input: %w", err)
 	}
 
 	if input.Platform == "" {
-		return "", fmt.Errorf("approve_pairing: platform is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "approve_pairing: platform is required")
 	}
 	if input.UserID == "" {
-		return "", fmt.Errorf("approve_pairing: user_id is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "approve_pairing: user_id is required")
 	}
 	if input.PolicyName == "" {
-		return "", fmt.Errorf("approve_pairing: policy_name is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "approve_pairing: policy_name is required")
 	}
 
 	// Validate policy exists
 	if _, ok := t.resolver.Resolve(input.PolicyName); !ok {
-		return "", fmt.Errorf("approve_pairing: unknown policy %q (available: %v)", input.PolicyName, t.resolver.Names())
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "approve_pairing: unknown policy %q (available: %v)", input.PolicyName, t.resolver.Names())
 	}
 
 	// Default wildcards
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/conscience"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/scheduler"
@@ -39,6 +40,7 @@ func ScheduleTaskManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"schedule"},
 		Tools: []ToolSpec{
 			{
 				Name:        "schedule_task",
@@ -64,7 +66,7 @@ func ScheduleTaskManifest() *PluginManifest {
 					},
 					"on_event": {
 						Type:        "string",
-						Description: "Event type to trigger on (e.g. \"task.completed\"). Mutually exclusive with cron and interval.",
+						Description: "Event type to trigger on (e.g. \"task.completed\"), or a prefix pattern such as \"task.*\". Mutually exclusive with cron and interval.",
 					},
 					"tools": {
 						Type:        "array",
@@ -122,13 +124,13 @@ func (t *ScheduleTaskTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *ScheduleTaskTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input scheduleTaskInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("schedule_task: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: parse input: %w", err)
 	}
 	if input.Title == "" {
-		return "", fmt.Errorf("schedule_task: title is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: title is required")
 	}
 	if input.Description == "" {
-		return "", fmt.Errorf("schedule_task: description is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: description is required")
 	}
 
 	// Exactly one trigger type required
@@ -143,10 +145,10 @@ func (t *ScheduleTaskTool) InvokableRun(ctx context.Context, argumentsInJSON str
 		triggerCount++
 	}
 	if triggerCount == 0 {
-		return "", fmt.Errorf("schedule_task: one of cron, interval, or on_event is required. Example: {\"cron\": \"0 12 * * *\"} for daily at noon, {\"interval\": \"1h\"} for every hour, {\"on_event\": \"task.completed\"} for event-driven")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: one of cron, interval, or on_event is required. Example: {\"cron\": \"0 12 * * *\"} for daily at noon, {\"interval\": \"1h\"} for every hour, {\"on_event\": \"task.completed\"} for event-driven")
 	}
 	if triggerCount > 1 {
-		return "", fmt.Errorf("schedule_task: cron, interval, and on_event are mutually exclusive")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: cron, interval, and on_event are mutually exclusive")
 	}
 
 	// Resolve relative work_dir to absolute so sub-agents find the directory
@@ -180,7 +182,7 @@ func (t *ScheduleTaskTool) InvokableRun(ctx context.Context, argumentsInJSON str
 	if input.Interval != "" {
 		d, err := time.ParseDuration(input.Interval)
 		if err != nil {
-			return "", fmt.Errorf("schedule_task: invalid interval %q: %w", input.Interval, err)
+			return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: invalid interval %q: %w", input.Interval, err)
 		}
 		entry.IntervalSec = int(d.Seconds())
 	}
@@ -192,7 +194,7 @@ func (t *ScheduleTaskTool) InvokableRun(ctx context.Context, argumentsInJSON str
 	if input.Cooldown != "" {
 		d, err := time.ParseDuration(input.Cooldown)
 		if err != nil {
-			return "", fmt.Errorf("schedule_task: invalid cooldown %q: %w", input.Cooldown, err)
+			return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "schedule_task: invalid cooldown %q: %w", input.Cooldown, err)
 		}
 		entry.CooldownSec = int(d.Seconds())
 	}
@@ -202,7 +204,7 @@ func (t *ScheduleTaskTool) InvokableRun(ctx context.Context, argumentsInJSON str
 		sessionID := events.SessionIDFromContext(ctx)
 		approvedTools, err := t.preApproveDangerousTools(ctx, sessionID, input.Tools)
 		if err != nil {
-			return "", fmt.Errorf("schedule_task: %w", err)
+			return "", brain.ToolErrorf(brain.ToolErrPermissionDenied, "schedule_task: %w", err)
 		}
 		if len(approvedTools) > 0 {
 			entry.TaskTemplate.ApprovedTools = approvedTools
@@ -284,6 +286,7 @@ func UnscheduleTaskManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"schedule"},
 		Tools: []ToolSpec{
 			{
 				Name:        "unschedule_task",
@@ -313,19 +316,19 @@ func (t *UnscheduleTaskTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *UnscheduleTaskTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input unscheduleTaskInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("unschedule_task: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "unschedule_task: parse input: %w", err)
 	}
 	if input.EntryID == "" {
-		return "", fmt.Errorf("unschedule_task: entry_id is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "unschedule_task: entry_id is required")
 	}
 
 	// Check if it's a skill entry
 	entry, ok := t.sched.GetEntry(input.EntryID)
 	if !ok {
-		return "", fmt.Errorf("unschedule_task: entry not found: %s", input.EntryID)
+		return "", brain.ToolErrorf(brain.ToolErrNotFound, "unschedule_task: entry not found: %s", input.EntryID)
 	}
 	if entry.Source == "skill" {
-		return "", fmt.Errorf("unschedule_task: cannot remove skill-based schedule %q (managed by skill registry)", input.EntryID)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "unschedule_task: cannot remove skill-based schedule %q (managed by skill registry)", input.EntryID)
 	}
 
 	title := entry.Title
@@ -371,6 +374,7 @@ func ListSchedulesManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"schedule"},
 		Tools: []ToolSpec{
 			{
 				Name:        "list_schedules",
@@ -412,7 +416,9 @@ func (t *ListSchedulesTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *ListSchedulesTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input listSchedulesInput
 	if argumentsInJSON != "" {
-		_ = json.Unmarshal([]byte(argumentsInJSON), &input)
+		if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+			return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "list_schedules: parse input: %w", err)
+		}
 	}
 
 	entries := t.sched.ListEntries()
@@ -474,6 +480,7 @@ func TriggerScheduleManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"schedule"},
 		Tools: []ToolSpec{
 			{
 				Name:        "trigger_schedule",
@@ -503,10 +510,10 @@ func (t *TriggerScheduleTool) Info(_ context.Context) (*schema.ToolInfo, error)
 func (t *TriggerScheduleTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input triggerScheduleInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("trigger_schedule: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "trigger_schedule: parse input: %w", err)
 	}
 	if input.EntryID == "" {
-		return "", fmt.Errorf("trigger_schedule: entry_id is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "trigger_schedule: entry_id is required")
 	}
 
 	taskID, err := t.sched.TriggerEntry(input.EntryID)
@@ -0,0 +1,217 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+// Session variable bounds. Vars are scratch state, not a document store.
+const (
+	MaxSessionVars         = 32
+	MaxSessionVarKeyLen    = 64
+	MaxSessionVarValueSize = 2048 // bytes
+)
+
+// SetVarTool stores a scratch variable in the current session.
+type SetVarTool struct {
+	store sessions.Store
+	locks sync.Map // sessionID → *sync.Mutex, serializing read-modify-write of the vars
+}
+
+// NewSetVarTool creates a new set_var tool.
+func NewSetVarTool(store sessions.Store) *SetVarTool {
+	return &SetVarTool{store: store}
+}
+
+// SetVarManifest returns the plugin manifest for the set_var tool.
+func SetVarManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        "set_var",
+		Description: "Set a session-scoped scratch variable",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   false,
+		Tags:        []string{"session"},
+		Tools: []ToolSpec{
+			{
+				Name: "set_var",
+				Description: fmt.Sprintf("Store a value in the current session's working memory (not long-term memory). "+
+					"Use it for intermediate results or preferences that only matter for this conversation. "+
+					"An empty value deletes the variable. Limits: %d variables, keys up to %d chars, values up to %d bytes.",
+					MaxSessionVars, MaxSessionVarKeyLen, MaxSessionVarValueSize),
+				Parameters: map[string]ParamSpec{
+					"key": {
+						Type:        "string",
+						Description: "Variable name",
+						Required:    true,
+					},
+					"value": {
+						Type:        "string",
+						Description: "Value to store (empty to delete)",
+					},
+				},
+			},
+		},
+	}
+}
+
+type setVarInput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *SetVarTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&SetVarManifest().Tools[0]), nil
+}
+
+// InvokableRun sets or deletes a session variable.
+func (t *SetVarTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	sessionID := events.SessionIDFromContext(ctx)
+	if sessionID == "" {
+		return "", fmt.Errorf("set_var: no session in context")
+	}
+
+	var input setVarInput
+	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "set_var: parse input: %w", err)
+	}
+	if input.Key == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "set_var: key is required")
+	}
+	if len(input.Key) > MaxSessionVarKeyLen {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "set_var: key exceeds %d characters", MaxSessionVarKeyLen)
+	}
+	if len(input.Value) > MaxSessionVarValueSize {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "set_var: value exceeds %d bytes", MaxSessionVarValueSize)
+	}
+
+	// Parallel set_var calls would otherwise overwrite each other's update.
+	mu, _ := t.locks.LoadOrStore(sessionID, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	s, err := t.store.Get(sessionID)
+	if err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrNotFound, "set_var: %w", err)
+	}
+
+	status := "set"
+	if input.Value == "" {
+		delete(s.Vars, input.Key)
+		status = "deleted"
+	} else {
+		if _, exists := s.Vars[input.Key]; !exists && len(s.Vars) >= MaxSessionVars {
+			return "", fmt.Errorf("set_var: session already has %d variables, delete one first", MaxSessionVars)
+		}
+		if s.Vars == nil {
+			s.Vars = make(map[string]string)
+		}
+		s.Vars[input.Key] = input.Value
+	}
+
+	s.UpdatedAt = time.Now()
+	if err := t.store.UpdateMeta(s); err != nil {
+		return "", fmt.Errorf("set_var: save: %w", err)
+	}
+
+	out, _ := json.Marshal(map[string]any{
+		"key":    input.Key,
+		"status": status,
+		"count":  len(s.Vars),
+	})
+	return string(out), nil
+}
+
+// GetVarTool reads scratch variables from the current session.
+type GetVarTool struct {
+	store sessions.Store
+}
+
+// NewGetVarTool creates a new get_var tool.
+func NewGetVarTool(store sessions.Store) *GetVarTool {
+	return &GetVarTool{store: store}
+}
+
+// GetVarManifest returns the plugin manifest for the get_var tool.
+func GetVarManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        "get_var",
+		Description: "Read session-scoped scratch variables",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   false,
+		Tags:        []string{"session"},
+		Tools: []ToolSpec{
+			{
+				Name:        "get_var",
+				Description: "Read a variable previously stored with set_var in this session. Omit key to list all variables.",
+				Parameters: map[string]ParamSpec{
+					"key": {
+						Type:        "string",
+						Description: "Variable name (omit to list all)",
+					},
+				},
+			},
+		},
+	}
+}
+
+type getVarInput struct {
+	Key string `json:"key,omitempty"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *GetVarTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&GetVarManifest().Tools[0]), nil
+}
+
+// InvokableRun returns one variable, or all of them when no key is given.
+func (t *GetVarTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	sessionID := events.SessionIDFromContext(ctx)
+	if sessionID == "" {
+		return "", fmt.Errorf("get_var: no session in context")
+	}
+
+	var input getVarInput
+	if argumentsInJSON != "" {
+		if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+			return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "get_var: parse input: %w", err)
+		}
+	}
+
+	s, err := t.store.Get(sessionID)
+	if err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrNotFound, "get_var: %w", err)
+	}
+
+	if input.Key == "" {
+		keys := make([]string, 0, len(s.Vars))
+		for k := range s.Vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out, _ := json.Marshal(map[string]any{"keys": keys, "vars": s.Vars})
+		return string(out), nil
+	}
+
+	value, ok := s.Vars[input.Key]
+	out, _ := json.Marshal(map[string]any{"key": input.Key, "value": value, "found": ok})
+	return string(out), nil
+}
+
+var (
+	_ tool.InvokableTool = (*SetVarTool)(nil)
+	_ tool.InvokableTool = (*GetVarTool)(nil)
+)
@@ -0,0 +1,149 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/sessions"
+)
+
+func newSessionVarsTestDeps(t *testing.T) (sessions.Store, context.Context) {
+	t.Helper()
+	store := sessions.NewFileStore(t.TempDir())
+	s, err := store.Create()
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return store, events.ContextWithSessionID(context.Background(), s.ID)
+}
+
+func TestSessionVars_SetGetDelete(t *testing.T) {
+	store, ctx := newSessionVarsTestDeps(t)
+	setTool := NewSetVarTool(store)
+	getTool := NewGetVarTool(store)
+
+	if _, err := setTool.InvokableRun(ctx, `{"key":"target","value":"v1.2.0"}`); err != nil {
+		t.Fatalf("set_var: %v", err)
+	}
+
+	result, err := getTool.InvokableRun(ctx, `{"key":"target"}`)
+	if err != nil {
+		t.Fatalf("get_var: %v", err)
+	}
+	var out struct {
+		Value string `json:"value"`
+		Found bool   `json:"found"`
+	}
+	if err := json.Unmarshal([]byte(result), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !out.Found || out.Value != "v1.2.0" {
+		t.Fatalf("expected target=v1.2.0, got %+v", out)
+	}
+
+	// Empty value deletes
+	if _, err := setTool.InvokableRun(ctx, `{"key":"target","value":""}`); err != nil {
+		t.Fatalf("set_var delete: %v", err)
+	}
+	sessionID := events.SessionIDFromContext(ctx)
+	s, _ := store.Get(sessionID)
+	if _, ok := s.Vars["target"]; ok {
+		t.Fatal("expected target to be deleted")
+	}
+}
+
+func TestSessionVars_ListAll(t *testing.T) {
+	store, ctx := newSessionVarsTestDeps(t)
+	setTool := NewSetVarTool(store)
+
+	for _, k := range []string{"b", "a"} {
+		if _, err := setTool.InvokableRun(ctx, fmt.Sprintf(`{"key":%q,"value":"x"}`, k)); err != nil {
+			t.Fatalf("set_var: %v", err)
+		}
+	}
+
+	result, err := NewGetVarTool(store).InvokableRun(ctx, `{}`)
+	if err != nil {
+		t.Fatalf("get_var: %v", err)
+	}
+	if !strings.Contains(result, `"keys":["a","b"]`) {
+		t.Fatalf("expected sorted keys, got %s", result)
+	}
+}
+
+func TestSetVar_Bounds(t *testing.T) {
+	store, ctx := newSessionVarsTestDeps(t)
+	setTool := NewSetVarTool(store)
+
+	longKey := strings.Repeat("k", MaxSessionVarKeyLen+1)
+	if _, err := setTool.InvokableRun(ctx, fmt.Sprintf(`{"key":%q,"value":"x"}`, longKey)); err == nil {
+		t.Fatal("expected error for oversized key")
+	}
+
+	bigValue := strings.Repeat("v", MaxSessionVarValueSize+1)
+	if _, err := setTool.InvokableRun(ctx, fmt.Sprintf(`{"key":"k","value":%q}`, bigValue)); err == nil {
+		t.Fatal("expected error for oversized value")
+	}
+
+	for i := range MaxSessionVars {
+		if _, err := setTool.InvokableRun(ctx, fmt.Sprintf(`{"key":"k%d","value":"x"}`, i)); err != nil {
+			t.Fatalf("set_var %d: %v", i, err)
+		}
+	}
+	if _, err := setTool.InvokableRun(ctx, `{"key":"overflow","value":"x"}`); err == nil {
+		t.Fatal("expected error when exceeding max variables")
+	}
+	// Overwriting an existing key is still allowed at the limit.
+	if _, err := setTool.InvokableRun(ctx, `{"key":"k0","value":"y"}`); err != nil {
+		t.Fatalf("overwrite at limit: %v", err)
+	}
+}
+
+func TestSetVar_NoSession(t *testing.T) {
+	store := sessions.NewFileStore(t.TempDir())
+	if _, err := NewSetVarTool(store).InvokableRun(context.Background(), `{"key":"a","value":"b"}`); err == nil {
+		t.Fatal("expected error without session")
+	}
+}
+
+// yieldingStore yields after every read, so that unserialized
+// read-modify-write sequences interleave.
+type yieldingStore struct{ sessions.Store }
+
+func (s yieldingStore) Get(id string) (*sessions.Session, error) {
+	sess, err := s.Store.Get(id)
+	runtime.Gosched()
+	return sess, err
+}
+
+func TestSessionVars_ConcurrentSetsKeepEveryVar(t *testing.T) {
+	store, ctx := newSessionVarsTestDeps(t)
+	setTool := NewSetVarTool(yieldingStore{store})
+
+	const n = 16
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := setTool.InvokableRun(ctx, fmt.Sprintf(`{"key":"k%d","value":"v"}`, i)); err != nil {
+				t.Errorf("set_var: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s, err := store.Get(events.SessionIDFromContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Vars) != n {
+		t.Errorf("vars = %d, want %d: concurrent updates were lost", len(s.Vars), n)
+	}
+}
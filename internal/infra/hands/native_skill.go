@@ -8,6 +8,8 @@ import (
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 )
 
 // SkillCatalog provides read-only access to skills for the native tools.
@@ -43,6 +45,7 @@ func RunWorkflowManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"skills"},
 		Tools: []ToolSpec{
 			{
 				Name:        "run_workflow",
@@ -77,11 +80,11 @@ func (t *RunWorkflowTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *RunWorkflowTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input runWorkflowInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("run_workflow: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "run_workflow: parse input: %w", err)
 	}
 
 	if input.SkillName == "" {
-		return "", fmt.Errorf("run_workflow: skill_name is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "run_workflow: skill_name is required")
 	}
 
 	vars := input.Vars
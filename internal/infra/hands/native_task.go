@@ -1,6 +1,7 @@
 package hands
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/config"
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/conscience"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/tasks"
@@ -21,19 +24,25 @@ import (
 
 // SubmitTaskTool submits a new async task to the actor pool.
 type SubmitTaskTool struct {
-	pool     tasks.TaskSubmitter
-	registry *ToolRegistry    // for looking up tool specs (dangerous flag)
-	perms    *conscience.ToolPermissions // for checking/setting approvals
-	bus      events.EventBus  // for emitting approval prompts
+	pool      tasks.TaskSubmitter
+	registry  *ToolRegistry               // for looking up tool specs (dangerous flag)
+	perms     *conscience.ToolPermissions // for checking/setting approvals
+	bus       events.EventBus             // for emitting approval prompts
+	templates *tasks.TemplateStore        // for from_template (optional)
+	guard     *tasks.SubmissionGuard      // depth and rate limits (optional)
+	limits    config.LimitsConfig
 }
 
 // NewSubmitTaskTool creates a new submit_task tool.
-func NewSubmitTaskTool(pool tasks.TaskSubmitter, registry *ToolRegistry, perms *conscience.ToolPermissions, bus events.EventBus) *SubmitTaskTool {
+func NewSubmitTaskTool(pool tasks.TaskSubmitter, registry *ToolRegistry, perms *conscience.ToolPermissions, bus events.EventBus, templates *tasks.TemplateStore, guard *tasks.SubmissionGuard, limits config.LimitsConfig) *SubmitTaskTool {
 	return &SubmitTaskTool{
-		pool:     pool,
-		registry: registry,
-		perms:    perms,
-		bus:      bus,
+		pool:      pool,
+		registry:  registry,
+		perms:     perms,
+		bus:       bus,
+		templates: templates,
+		guard:     guard,
+		limits:    limits.WithDefaults(),
 	}
 }
 
@@ -45,6 +54,7 @@ func SubmitTaskManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"tasks"},
 		Tools: []ToolSpec{
 			{
 				Name:        "submit_task",
@@ -52,12 +62,11 @@ func SubmitTaskManifest() *PluginManifest {
 				Parameters: map[string]ParamSpec{
 					"title": {
 						Type:        "string",
-						Description: "Short title for the task (required for single task, optional plan title for steps)",
-						Required:    true,
+						Description: "Short title for the task (required for single task unless from_template provides one, optional plan title for steps)",
 					},
 					"description": {
 						Type:        "string",
-						Description: "Detailed description of what the task should accomplish (required for single task, ignored when steps is provided)",
+						Description: "Detailed description of what the task should accomplish (required for single task unless from_template provides one, ignored when steps is provided)",
 					},
 					"tools": {
 						Type:        "array",
@@ -82,7 +91,7 @@ func SubmitTaskManifest() *PluginManifest {
 					},
 					"skill": {
 						Type:        "string",
-						Description: "Name of a skill to execute directly (bypasses agent reasoning)",
+						Description: "Name of a skill to execute directly (bypasses agent reasoning). Runs the latest version; pin one with name@version (e.g. deploy@1.2.0)",
 					},
 					"actor_tags": {
 						Type:        "array",
@@ -96,6 +105,42 @@ func SubmitTaskManifest() *PluginManifest {
 						Type:        "object",
 						Description: "Per-tool argument constraints. Map of tool name to constraint object with fields: allowed_commands, allowed_patterns, blocked_patterns, allowed_paths, allowed_domains.",
 					},
+					"from_template": {
+						Type:        "string",
+						Description: "Name of a task template (see save_task_template) that pre-fills title, description, tools, work_dir, env and constraints. Explicit parameters override the template.",
+					},
+					"template_vars": {
+						Type:        "object",
+						Description: "Values for the template's ${var} placeholders. Example: {\"service\": \"billing\"}",
+					},
+					"model": {
+						Type:        "string",
+						Description: "Provider to run the task on, e.g. a small model for classification or formatting and a large one for code generation (default: any available actor). In a plan, the default for steps without their own model.",
+					},
+					"verbose": {
+						Type:        "boolean",
+						Description: "Stream throttled narration of the task agent's reasoning to this session while it runs (default: false)",
+					},
+					"git_context": {
+						Type:        "boolean",
+						Description: "Include the work_dir repository state (branch, changed files, last commit) in the task instructions (default: false)",
+					},
+					"remember": {
+						Type:        "boolean",
+						Description: "On completion, store a summary of the task output in long-term memory so future sessions can recall what it found (default: false; requires semantic memory)",
+					},
+					"simulate": {
+						Type:        "boolean",
+						Description: "Dry run: the task explores with read-only tools but its side-effecting tool calls (commands, file writes, git...) are recorded instead of executed; the output ends with a report of the planned calls. Skill tasks describe their steps without running them (default: false)",
+					},
+					"max_iterations": {
+						Type:        "integer",
+						Description: "Maximum ReAct iterations (model calls) of the task agent before it stops with a 'max iterations reached' error (default: agent.max_iterations.task; higher values are clamped to agent.max_iterations.task_limit). In a plan, applies to every step.",
+					},
+					"idempotency_key": {
+						Type:        "string",
+						Description: "Deduplication key: while a pending or running task of this session has the same key, its task_id is returned instead of submitting a duplicate (e.g. when retrying a submission)",
+					},
 					"steps": {
 						Type:        "array",
 						Description: "Multi-step plan: ordered list of steps with dependencies. Steps with no depends_on run in parallel. When provided, this creates multiple sub-tasks instead of a single task.",
@@ -132,6 +177,10 @@ func SubmitTaskManifest() *PluginManifest {
 									Description: "Required model capabilities for this step.",
 									Items:       &ParamSpec{Type: "string"},
 								},
+								"model": {
+									Type:        "string",
+									Description: "Provider to run this step on (overrides the plan-level model).",
+								},
 							},
 						},
 					},
@@ -153,6 +202,15 @@ type submitTaskInput struct {
 	ActorTags            []string                          `json:"actor_tags,omitempty"`
 	RequiredCapabilities []string                          `json:"required_capabilities,omitempty"`
 	ToolConstraints      map[string]*events.ToolConstraint `json:"tool_constraints,omitempty"`
+	Model                string                            `json:"model,omitempty"`
+	Verbose              bool                              `json:"verbose,omitempty"`
+	GitContext           bool                              `json:"git_context,omitempty"`
+	Remember             bool                              `json:"remember,omitempty"`
+	Simulate             bool                              `json:"simulate,omitempty"`
+	MaxIterations        int                               `json:"max_iterations,omitempty"`
+	IdempotencyKey       string                            `json:"idempotency_key,omitempty"`
+	FromTemplate         string                            `json:"from_template,omitempty"`
+	TemplateVars         map[string]string                 `json:"template_vars,omitempty"`
 	Steps                []planStep                        `json:"steps,omitempty"`
 }
 
@@ -164,6 +222,7 @@ type planStep struct {
 	DependsOn            []int    `json:"depends_on,omitempty"`
 	ActorTags            []string `json:"actor_tags,omitempty"`
 	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
+	Model                string   `json:"model,omitempty"`
 }
 
 // Info returns the tool info for Eino registration.
@@ -185,10 +244,18 @@ func (t *SubmitTaskTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *SubmitTaskTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input submitTaskInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("submit_task: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "submit_task: parse input: %w", err)
+	}
+	if input.FromTemplate != "" {
+		if err := t.applyTemplate(&input); err != nil {
+			return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "submit_task: %w", err)
+		}
 	}
 	if input.Title == "" {
-		return "", fmt.Errorf("submit_task: title is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "submit_task: title is required")
+	}
+	if input.MaxIterations < 0 {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "submit_task: max_iterations must not be negative")
 	}
 
 	// Sanitize actor_tags: strip unknown tags to prevent hallucinated tags from
@@ -198,15 +265,25 @@ func (t *SubmitTaskTool) InvokableRun(ctx context.Context, argumentsInJSON strin
 		input.Steps[i].ActorTags = t.sanitizeActorTags(input.Steps[i].ActorTags)
 	}
 
+	if len(input.Steps) == 0 && input.Description == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "submit_task: description is required")
+	}
+
+	// A task submitting tasks becomes their parent; the guard bounds how deep
+	// and how fast such trees can grow.
+	parentID := events.TaskIDFromContext(ctx)
+	if t.guard != nil {
+		count := max(len(input.Steps), 1)
+		if err := t.guard.Admit(events.SessionIDFromContext(ctx), parentID, count); err != nil {
+			return "", brain.ToolErrorf(brain.ToolErrPermissionDenied, "submit_task: %w", err)
+		}
+	}
+
 	// Multi-step plan mode
 	if len(input.Steps) > 0 {
 		return t.runPlan(ctx, input)
 	}
 
-	if input.Description == "" {
-		return "", fmt.Errorf("submit_task: description is required")
-	}
-
 	return t.runSingle(ctx, input)
 }
 
@@ -251,9 +328,10 @@ func (t *SubmitTaskTool) runSingle(ctx context.Context, input submitTaskInput) (
 		tools = DefaultTaskTools
 	}
 
-	if t.registry != nil && t.perms != nil && t.bus != nil {
+	// A simulated task never runs its dangerous tools: nothing to approve.
+	if t.registry != nil && t.perms != nil && t.bus != nil && !input.Simulate {
 		if err := t.preApproveDangerousTools(ctx, sessionID, tools); err != nil {
-			return "", fmt.Errorf("submit_task: %w", err)
+			return "", brain.ToolErrorf(brain.ToolErrPermissionDenied, "submit_task: %w", err)
 		}
 	}
 
@@ -271,12 +349,13 @@ func (t *SubmitTaskTool) runSingle(ctx context.Context, input submitTaskInput) (
 	taskConstraints := events.MergeToolConstraints(sessionConstraints, input.ToolConstraints)
 
 	task := &tasks.Task{
-		SessionID:   sessionID,
-		Title:       input.Title,
-		Description: input.Description,
-		Priority:    priority,
-		DependsOn:   input.DependsOn,
-		Tags:        input.ActorTags,
+		SessionID:    sessionID,
+		ParentTaskID: events.TaskIDFromContext(ctx),
+		Title:        input.Title,
+		Description:  input.Description,
+		Priority:     priority,
+		DependsOn:    input.DependsOn,
+		Tags:         input.ActorTags,
 		Config: tasks.TaskConfig{
 			Tools:                tools,
 			WorkDir:              workDir,
@@ -285,7 +364,14 @@ func (t *SubmitTaskTool) runSingle(ctx context.Context, input submitTaskInput) (
 			RequiredTags:         input.ActorTags,
 			RequiredCapabilities: input.RequiredCapabilities,
 			ToolConstraints:      taskConstraints,
+			Model:                input.Model,
+			Verbose:              input.Verbose,
+			GitContext:           input.GitContext,
+			Remember:             input.Remember,
+			Simulate:             input.Simulate,
+			MaxIterations:        input.MaxIterations,
 		},
+		IdempotencyKey: input.IdempotencyKey,
 	}
 
 	if inliner, ok := t.pool.(tasks.InlineExecutor); ok && inliner.ShouldInline() {
@@ -298,8 +384,8 @@ func (t *SubmitTaskTool) runSingle(ctx context.Context, input submitTaskInput) (
 			})
 			return string(result), nil
 		}
-		if len(output) > 2000 {
-			output = output[:2000] + "..."
+		if len(output) > t.limits.InlineTaskOutputChars {
+			output = output[:t.limits.InlineTaskOutputChars] + "..."
 		}
 		result, _ := json.Marshal(map[string]any{
 			"task_id": task.ID,
@@ -356,7 +442,7 @@ func (t *SubmitTaskTool) runPlan(ctx context.Context, input submitTaskInput) (st
 	for i, step := range input.Steps {
 		for _, dep := range step.DependsOn {
 			if dep < 0 || dep >= i {
-				return "", fmt.Errorf("submit_task: step %d has invalid depends_on index %d (must be 0..%d)", i, dep, i-1)
+				return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "submit_task: step %d has invalid depends_on index %d (must be 0..%d)", i, dep, i-1)
 			}
 		}
 	}
@@ -378,7 +464,7 @@ func (t *SubmitTaskTool) runPlan(ctx context.Context, input submitTaskInput) (st
 		return t.runPlanInline(ctx, inliner, input, sessionID, taskConstraints)
 	}
 
-	return t.runPlanAsync(input, sessionID, taskConstraints)
+	return t.runPlanAsync(input, sessionID, events.TaskIDFromContext(ctx), taskConstraints)
 }
 
 func (t *SubmitTaskTool) runPlanInline(ctx context.Context, inliner tasks.InlineExecutor, input submitTaskInput, sessionID string, taskConstraints map[string]*events.ToolConstraint) (string, error) {
@@ -398,11 +484,12 @@ func (t *SubmitTaskTool) runPlanInline(ctx context.Context, inliner tasks.Inline
 		}
 
 		task := &tasks.Task{
-			SessionID:   sessionID,
-			Title:       step.Title,
-			Description: step.Description,
-			DependsOn:   deps,
-			Tags:        step.ActorTags,
+			SessionID:    sessionID,
+			ParentTaskID: events.TaskIDFromContext(ctx),
+			Title:        step.Title,
+			Description:  step.Description,
+			DependsOn:    deps,
+			Tags:         step.ActorTags,
 			Config: tasks.TaskConfig{
 				Tools:                tools,
 				WorkDir:              input.WorkDir,
@@ -410,6 +497,12 @@ func (t *SubmitTaskTool) runPlanInline(ctx context.Context, inliner tasks.Inline
 				RequiredTags:         step.ActorTags,
 				RequiredCapabilities: step.RequiredCapabilities,
 				ToolConstraints:      taskConstraints,
+				Model:                cmp.Or(step.Model, input.Model),
+				Verbose:              input.Verbose,
+				GitContext:           input.GitContext,
+				Remember:             input.Remember,
+				Simulate:             input.Simulate,
+				MaxIterations:        input.MaxIterations,
 			},
 		}
 
@@ -431,8 +524,8 @@ func (t *SubmitTaskTool) runPlanInline(ctx context.Context, inliner tasks.Inline
 			break
 		}
 
-		if len(output) > 2000 {
-			output = output[:2000] + "..."
+		if len(output) > t.limits.InlineTaskOutputChars {
+			output = output[:t.limits.InlineTaskOutputChars] + "..."
 		}
 		entry.Status = "completed"
 		entry.Output = output
@@ -453,7 +546,7 @@ func (t *SubmitTaskTool) runPlanInline(ctx context.Context, inliner tasks.Inline
 	return string(result), nil
 }
 
-func (t *SubmitTaskTool) runPlanAsync(input submitTaskInput, sessionID string, taskConstraints map[string]*events.ToolConstraint) (string, error) {
+func (t *SubmitTaskTool) runPlanAsync(input submitTaskInput, sessionID, parentID string, taskConstraints map[string]*events.ToolConstraint) (string, error) {
 	taskIDs := make([]string, len(input.Steps))
 	entries := make([]planTaskEntry, len(input.Steps))
 
@@ -469,11 +562,12 @@ func (t *SubmitTaskTool) runPlanAsync(input submitTaskInput, sessionID string, t
 		}
 
 		task := &tasks.Task{
-			SessionID:   sessionID,
-			Title:       step.Title,
-			Description: step.Description,
-			DependsOn:   deps,
-			Tags:        step.ActorTags,
+			SessionID:    sessionID,
+			ParentTaskID: parentID,
+			Title:        step.Title,
+			Description:  step.Description,
+			DependsOn:    deps,
+			Tags:         step.ActorTags,
 			Config: tasks.TaskConfig{
 				Tools:                tools,
 				WorkDir:              input.WorkDir,
@@ -481,8 +575,17 @@ func (t *SubmitTaskTool) runPlanAsync(input submitTaskInput, sessionID string, t
 				RequiredTags:         step.ActorTags,
 				RequiredCapabilities: step.RequiredCapabilities,
 				ToolConstraints:      taskConstraints,
+				Model:                cmp.Or(step.Model, input.Model),
+				Verbose:              input.Verbose,
+				GitContext:           input.GitContext,
+				Remember:             input.Remember,
+				Simulate:             input.Simulate,
+				MaxIterations:        input.MaxIterations,
 			},
 		}
+		if input.IdempotencyKey != "" {
+			task.IdempotencyKey = fmt.Sprintf("%s#%d", input.IdempotencyKey, i)
+		}
 
 		if err := t.pool.Submit(task); err != nil {
 			return "", fmt.Errorf("submit_task: submit step %d: %w", i, err)
@@ -539,12 +642,13 @@ var _ tool.InvokableTool = (*SubmitTaskTool)(nil)
 
 // QueryTasksTool retrieves task status (by ID) or lists tasks (by filter).
 type QueryTasksTool struct {
-	store tasks.Store
+	store  tasks.Store
+	limits config.LimitsConfig
 }
 
-// NewQueryTasksTool creates a new query_tasks tool.
-func NewQueryTasksTool(store tasks.Store) *QueryTasksTool {
-	return &QueryTasksTool{store: store}
+// NewQueryTasksTool creates a new query_tasks tool. Zero limits select the defaults.
+func NewQueryTasksTool(store tasks.Store, limits config.LimitsConfig) *QueryTasksTool {
+	return &QueryTasksTool{store: store, limits: limits.WithDefaults()}
 }
 
 // QueryTasksManifest returns the plugin manifest for the query_tasks tool.
@@ -555,10 +659,11 @@ func QueryTasksManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"tasks"},
 		Tools: []ToolSpec{
 			{
 				Name:        ToolQueryTasks,
-				Description: "Query tasks. If task_id is provided, returns detailed status for that task. Otherwise lists tasks with optional filters (max 20, sorted by date).",
+				Description: "Query tasks. If task_id is provided, returns detailed status for that task. Otherwise lists the most recent tasks with optional filters.",
 				Parameters: map[string]ParamSpec{
 					"task_id": {
 						Type:        "string",
@@ -595,6 +700,7 @@ type queryTaskDetailOutput struct {
 	ProviderName string             `json:"provider_name,omitempty"`
 	Output       string             `json:"output,omitempty"`
 	Error        string             `json:"error,omitempty"`
+	Artifacts    []string           `json:"artifacts,omitempty"`
 }
 
 // queryTaskListEntry is an entry in the task list output.
@@ -616,14 +722,14 @@ func (t *QueryTasksTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *QueryTasksTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input queryTasksInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("query_tasks: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "query_tasks: parse input: %w", err)
 	}
 
 	// Single task detail mode
 	if input.TaskID != "" {
 		task, err := t.store.Get(input.TaskID)
 		if err != nil {
-			return "", fmt.Errorf("query_tasks: %w", err)
+			return "", brain.ToolErrorf(brain.ToolErrNotFound, "query_tasks: %w", err)
 		}
 
 		out := queryTaskDetailOutput{
@@ -637,8 +743,8 @@ func (t *QueryTasksTool) InvokableRun(_ context.Context, argumentsInJSON string,
 
 		if task.Status == tasks.TaskCompleted {
 			output, _ := t.store.ReadOutput(task.ID)
-			if len(output) > 500 {
-				output = output[:500] + "..."
+			if len(output) > t.limits.TaskOutputChars {
+				output = output[:t.limits.TaskOutputChars] + "..."
 			}
 			out.Output = output
 		}
@@ -646,6 +752,7 @@ func (t *QueryTasksTool) InvokableRun(_ context.Context, argumentsInJSON string,
 		if task.Result != nil && task.Result.Error != "" {
 			out.Error = task.Result.Error
 		}
+		out.Artifacts = artifactNames(t.store, task.ID)
 
 		result, err := json.Marshal(out)
 		if err != nil {
@@ -665,8 +772,8 @@ func (t *QueryTasksTool) InvokableRun(_ context.Context, argumentsInJSON string,
 		return "", fmt.Errorf("query_tasks: %w", err)
 	}
 
-	if len(all) > 20 {
-		all = all[:20]
+	if len(all) > t.limits.TaskListSize {
+		all = all[:t.limits.TaskListSize]
 	}
 
 	entries := make([]queryTaskListEntry, len(all))
@@ -688,6 +795,16 @@ func (t *QueryTasksTool) InvokableRun(_ context.Context, argumentsInJSON string,
 	return string(result), nil
 }
 
+// artifactNames lists the names of a task's artifacts (best effort).
+func artifactNames(store tasks.Store, taskID string) []string {
+	artifacts, _ := store.ListArtifacts(taskID)
+	names := make([]string, len(artifacts))
+	for i, a := range artifacts {
+		names[i] = a.Name
+	}
+	return names
+}
+
 var _ tool.InvokableTool = (*QueryTasksTool)(nil)
 
 // =============================================================================
@@ -712,6 +829,7 @@ func CancelTaskManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"tasks"},
 		Tools: []ToolSpec{
 			{
 				Name:        "cancel_task",
@@ -746,10 +864,10 @@ func (t *CancelTaskTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *CancelTaskTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input cancelTaskInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("cancel_task: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "cancel_task: parse input: %w", err)
 	}
 	if input.TaskID == "" {
-		return "", fmt.Errorf("cancel_task: task_id is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "cancel_task: task_id is required")
 	}
 
 	reason := input.Reason
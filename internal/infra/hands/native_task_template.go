@@ -0,0 +1,189 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+)
+
+// =============================================================================
+// save_task_template
+// =============================================================================
+
+// SaveTaskTemplateTool saves the definition of an existing task as a named
+// template that submit_task can reuse via from_template.
+type SaveTaskTemplateTool struct {
+	store     tasks.Store
+	templates *tasks.TemplateStore
+}
+
+// NewSaveTaskTemplateTool creates a new save_task_template tool.
+func NewSaveTaskTemplateTool(store tasks.Store, templates *tasks.TemplateStore) *SaveTaskTemplateTool {
+	return &SaveTaskTemplateTool{store: store, templates: templates}
+}
+
+// SaveTaskTemplateManifest returns the plugin manifest for the save_task_template tool.
+func SaveTaskTemplateManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        "save_task_template",
+		Description: "Save an existing task as a reusable task template",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   false,
+		Tags:        []string{"tasks"},
+		Tools: []ToolSpec{
+			{
+				Name: "save_task_template",
+				Description: "Save the definition of an existing task (tools, work_dir, env, constraints, description) as a named template. " +
+					"Submit it again later with submit_task's from_template parameter. " +
+					"Use ${var} placeholders in the description to fill values at submit time via template_vars.",
+				Parameters: map[string]ParamSpec{
+					"name": {
+						Type:        "string",
+						Description: "Template name: lowercase letters, digits, '-' and '_' (replaces a template of the same name)",
+						Required:    true,
+					},
+					"task_id": {
+						Type:        "string",
+						Description: "ID of the task to copy the definition from",
+						Required:    true,
+					},
+					"title": {
+						Type:        "string",
+						Description: "Title for tasks created from the template (default: the task's title). May contain ${var} placeholders.",
+					},
+					"description": {
+						Type:        "string",
+						Description: "Description skeleton replacing the task's description, e.g. \"Deploy ${service} to staging\"",
+					},
+				},
+			},
+		},
+	}
+}
+
+type saveTaskTemplateInput struct {
+	Name        string `json:"name"`
+	TaskID      string `json:"task_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *SaveTaskTemplateTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&SaveTaskTemplateManifest().Tools[0]), nil
+}
+
+// InvokableRun saves the template.
+func (t *SaveTaskTemplateTool) InvokableRun(_ context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	var input saveTaskTemplateInput
+	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "save_task_template: parse input: %w", err)
+	}
+	if input.Name == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "save_task_template: name is required")
+	}
+	if input.TaskID == "" {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "save_task_template: task_id is required")
+	}
+	if err := tasks.ValidateTemplateName(input.Name); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "save_task_template: %w", err)
+	}
+
+	task, err := t.store.Get(input.TaskID)
+	if err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrNotFound, "save_task_template: %w", err)
+	}
+
+	tpl := tasks.TemplateFromTask(input.Name, task)
+	if input.Title != "" {
+		tpl.Title = input.Title
+	}
+	if input.Description != "" {
+		tpl.Description = input.Description
+	}
+	if err := t.templates.Save(tpl); err != nil {
+		return "", fmt.Errorf("save_task_template: %w", err)
+	}
+
+	result, _ := json.Marshal(map[string]any{
+		"name":   tpl.Name,
+		"vars":   tpl.Vars(),
+		"status": "saved",
+	})
+	return string(result), nil
+}
+
+var _ tool.InvokableTool = (*SaveTaskTemplateTool)(nil)
+
+// applyTemplate pre-fills input from the template named by input.FromTemplate.
+// Explicit input fields win; env and tool_constraints are merged per key.
+func (t *SubmitTaskTool) applyTemplate(input *submitTaskInput) error {
+	if t.templates == nil {
+		return fmt.Errorf("task templates are not available")
+	}
+	tpl, err := t.templates.Get(input.FromTemplate)
+	if err != nil {
+		return err
+	}
+
+	if input.Title == "" {
+		if input.Title, err = tasks.ExpandPlaceholders(tpl.Title, input.TemplateVars); err != nil {
+			return fmt.Errorf("template %s: %w", tpl.Name, err)
+		}
+		if input.Title == "" {
+			input.Title = tpl.Name
+		}
+	}
+	if input.Description == "" && len(input.Steps) == 0 {
+		if input.Description, err = tasks.ExpandPlaceholders(tpl.Description, input.TemplateVars); err != nil {
+			return fmt.Errorf("template %s: %w", tpl.Name, err)
+		}
+	}
+
+	cfg := tpl.Config
+	if len(input.Tools) == 0 {
+		input.Tools = cfg.Tools
+	}
+	if input.WorkDir == "" {
+		input.WorkDir = cfg.WorkDir
+	}
+	if input.Skill == "" {
+		input.Skill = cfg.Skill
+	}
+	if len(input.ActorTags) == 0 {
+		input.ActorTags = cfg.RequiredTags
+	}
+	if len(input.RequiredCapabilities) == 0 {
+		input.RequiredCapabilities = cfg.RequiredCapabilities
+	}
+	if input.Priority == "" {
+		input.Priority = string(tpl.Priority)
+	}
+	if input.Model == "" {
+		input.Model = cfg.Model
+	}
+	input.Verbose = input.Verbose || cfg.Verbose
+	input.GitContext = input.GitContext || cfg.GitContext
+	input.Env = mergeMaps(cfg.Env, input.Env)
+	input.ToolConstraints = mergeMaps(cfg.ToolConstraints, input.ToolConstraints)
+	return nil
+}
+
+// mergeMaps returns base overlaid with override, or nil when both are empty.
+func mergeMaps[V any](base, override map[string]V) map[string]V {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]V, len(base)+len(override))
+	maps.Copy(merged, base)
+	maps.Copy(merged, override)
+	return merged
+}
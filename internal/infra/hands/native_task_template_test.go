@@ -0,0 +1,99 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/config"
+	"github.com/dohr-michael/ozzie/internal/infra/tasks"
+)
+
+func TestSaveTaskTemplateTool(t *testing.T) {
+	store := tasks.NewFileStore(t.TempDir())
+	templates := tasks.NewTemplateStore(t.TempDir())
+	task := &tasks.Task{
+		Title:       "Deploy billing",
+		Description: "deploy billing to staging",
+		Config:      tasks.TaskConfig{Tools: []string{"run_command"}, ApprovedTools: []string{"run_command"}},
+	}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewSaveTaskTemplateTool(store, templates)
+	out, err := tool.InvokableRun(context.Background(),
+		`{"name":"deploy","task_id":"`+task.ID+`","title":"Deploy ${service}","description":"deploy ${service} to ${env}"}`)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	var res struct {
+		Vars []string `json:"vars"`
+	}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.Vars, []string{"service", "env"}) {
+		t.Errorf("vars = %v", res.Vars)
+	}
+
+	tpl, err := templates.Get("deploy")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if tpl.SourceTaskID != task.ID || len(tpl.Config.ApprovedTools) != 0 {
+		t.Errorf("template = %+v", tpl)
+	}
+
+	if _, err := tool.InvokableRun(context.Background(), `{"name":"Bad Name","task_id":"`+task.ID+`"}`); err == nil {
+		t.Error("expected error for invalid name")
+	}
+}
+
+func TestSubmitTaskTool_ApplyTemplate(t *testing.T) {
+	templates := tasks.NewTemplateStore(t.TempDir())
+	if err := templates.Save(&tasks.TaskTemplate{
+		Name:        "deploy",
+		Title:       "Deploy ${service}",
+		Description: "deploy ${service} with $HOME/bin/deploy",
+		Priority:    tasks.PriorityHigh,
+		Config: tasks.TaskConfig{
+			Tools:   []string{"run_command"},
+			WorkDir: "/srv",
+			Model:   "small",
+			Env:     map[string]string{"STAGE": "staging", "REGION": "eu"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewSubmitTaskTool(harnessPool{}, nil, nil, nil, templates, nil, config.LimitsConfig{})
+
+	input := submitTaskInput{
+		FromTemplate: "deploy",
+		TemplateVars: map[string]string{"service": "billing"},
+		WorkDir:      "/opt",
+		Env:          map[string]string{"STAGE": "prod"},
+	}
+	if err := tool.applyTemplate(&input); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if input.Title != "Deploy billing" || input.Description != "deploy billing with $HOME/bin/deploy" {
+		t.Errorf("title/description = %q / %q", input.Title, input.Description)
+	}
+	if input.WorkDir != "/opt" || !slices.Equal(input.Tools, []string{"run_command"}) || input.Priority != "high" || input.Model != "small" {
+		t.Errorf("config = %+v", input)
+	}
+	if input.Env["STAGE"] != "prod" || input.Env["REGION"] != "eu" {
+		t.Errorf("env = %v", input.Env)
+	}
+
+	missing := submitTaskInput{FromTemplate: "deploy"}
+	if err := tool.applyTemplate(&missing); err == nil || !strings.Contains(err.Error(), "service") {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+	if err := tool.applyTemplate(&submitTaskInput{FromTemplate: "nope"}); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/sessions"
 )
@@ -31,6 +32,7 @@ func UpdateSessionManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   false,
+		Tags:        []string{"session"},
 		Tools: []ToolSpec{
 			{
 				Name:        "update_session",
@@ -48,6 +50,10 @@ func UpdateSessionManifest() *PluginManifest {
 						Type:        "string",
 						Description: "Session title",
 					},
+					"project": {
+						Type:        "string",
+						Description: "Project the session belongs to (\"default\" for none)",
+					},
 					"metadata": {
 						Type:        "object",
 						Description: "Arbitrary key-value metadata to merge into the session",
@@ -62,6 +68,7 @@ type updateSessionInput struct {
 	RootDir  string            `json:"root_dir,omitempty"`
 	Language string            `json:"language,omitempty"`
 	Title    string            `json:"title,omitempty"`
+	Project  string            `json:"project,omitempty"`
 	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
@@ -79,12 +86,12 @@ func (t *UpdateSessionTool) InvokableRun(ctx context.Context, argumentsInJSON st
 
 	var input updateSessionInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("update_session: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "update_session: parse input: %w", err)
 	}
 
 	s, err := t.store.Get(sessionID)
 	if err != nil {
-		return "", fmt.Errorf("update_session: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrNotFound, "update_session: %w", err)
 	}
 
 	// Update only non-empty fields
@@ -97,6 +104,9 @@ func (t *UpdateSessionTool) InvokableRun(ctx context.Context, argumentsInJSON st
 	if input.Title != "" {
 		s.Title = input.Title
 	}
+	if input.Project != "" {
+		s.Project = sessions.NormalizeProject(input.Project)
+	}
 
 	// Merge metadata entries
 	if len(input.Metadata) > 0 {
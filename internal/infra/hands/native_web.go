@@ -14,6 +14,8 @@ import (
 	"github.com/cloudwego/eino/schema"
 
 	"github.com/dohr-michael/ozzie/internal/config"
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
 	"github.com/dohr-michael/ozzie/pkg/htmltext"
 )
 
@@ -71,6 +73,7 @@ func WebSearchManifest() *PluginManifest {
 		Description: "Search the web for information using the configured search provider",
 		Level:       "tool",
 		Provider:    "native",
+		Tags:        []string{"web"},
 		Capabilities: PluginCapabilities{
 			HTTP: true,
 		},
@@ -121,12 +124,24 @@ func NewWebFetchTool(cfg config.WebFetchConfig) *WebFetchTool {
 	}
 
 	return &WebFetchTool{
-		client:    &http.Client{Timeout: timeout},
+		client:    &http.Client{Timeout: timeout, CheckRedirect: checkFetchRedirect},
 		maxBodyKB: maxBody,
 		userAgent: ua,
 	}
 }
 
+// maxFetchRedirects mirrors the default redirect limit of net/http.
+const maxFetchRedirects = 10
+
+// checkFetchRedirect validates every redirect hop against the sandbox host
+// allowlist: in autonomous mode the guard only saw the first URL.
+func checkFetchRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxFetchRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxFetchRedirects)
+	}
+	return conscience.ValidateRedirect(req.Context(), req.URL)
+}
+
 type webFetchInput struct {
 	URL    string `json:"url"`
 	Prompt string `json:"prompt,omitempty"`
@@ -147,10 +162,10 @@ func (t *WebFetchTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *WebFetchTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
 	var input webFetchInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("web_fetch: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "web_fetch: parse input: %w", err)
 	}
 	if input.URL == "" {
-		return "", fmt.Errorf("web_fetch: url is required")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "web_fetch: url is required")
 	}
 
 	// Upgrade http to https
@@ -168,7 +183,7 @@ func (t *WebFetchTool) InvokableRun(ctx context.Context, argumentsInJSON string,
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("web_fetch: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrTransient, "web_fetch: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -208,6 +223,7 @@ func WebFetchManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   true,
+		Tags:        []string{"web"},
 		Capabilities: PluginCapabilities{
 			HTTP: true,
 		},
@@ -255,6 +271,7 @@ func WebManifest() *PluginManifest {
 		Level:       "tool",
 		Provider:    "native",
 		Dangerous:   true,
+		Tags:        []string{"web"},
 		Capabilities: PluginCapabilities{
 			HTTP: true,
 		},
@@ -297,17 +314,17 @@ func (t *WebTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 func (t *WebTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
 	var input webInput
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
-		return "", fmt.Errorf("web: parse input: %w", err)
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "web: parse input: %w", err)
 	}
 
 	hasURL := input.URL != ""
 	hasQuery := input.Query != ""
 
 	if hasURL && hasQuery {
-		return "", fmt.Errorf("web: provide exactly one of url or query, not both")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "web: provide exactly one of url or query, not both")
 	}
 	if !hasURL && !hasQuery {
-		return "", fmt.Errorf("web: provide either url or query")
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "web: provide either url or query")
 	}
 
 	if hasURL {
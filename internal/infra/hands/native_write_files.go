@@ -0,0 +1,117 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/infra/agent"
+)
+
+// maxWriteFiles bounds the number of files of a single write_files call.
+const maxWriteFiles = 100
+
+// AtomicFileWriter writes a set of files all-or-nothing.
+type AtomicFileWriter interface {
+	WriteFiles(ctx context.Context, files []agent.FileWrite) ([]int, error)
+}
+
+// WriteFilesTool writes several related files atomically.
+type WriteFilesTool struct {
+	writer AtomicFileWriter
+}
+
+// NewWriteFilesTool creates a new write_files tool.
+func NewWriteFilesTool(writer AtomicFileWriter) *WriteFilesTool {
+	return &WriteFilesTool{writer: writer}
+}
+
+// WriteFilesManifest returns the plugin manifest for the write_files tool.
+func WriteFilesManifest() *PluginManifest {
+	return &PluginManifest{
+		Name:        "write_files",
+		Description: "Write several files atomically",
+		Level:       "tool",
+		Provider:    "native",
+		Dangerous:   true,
+		Tags:        []string{"files"},
+		Capabilities: PluginCapabilities{
+			Filesystem: &FSCapabilityIntent{ReadOnly: false},
+		},
+		Tools: []ToolSpec{
+			{
+				Name: "write_files",
+				Description: "Create or overwrite several related files in one all-or-nothing operation (e.g. a project scaffold). " +
+					"Either every file is written or none is: on any failure, files already written are restored. " +
+					"Returns the bytes written per file.",
+				Dangerous: true,
+				Parameters: map[string]ParamSpec{
+					"files": {
+						Type:        "array",
+						Description: fmt.Sprintf("Files to write (at most %d); each path may appear once", maxWriteFiles),
+						Required:    true,
+						Items: &ParamSpec{
+							Type: "object",
+							Properties: map[string]ParamSpec{
+								"path": {
+									Type:        "string",
+									Description: "Path of the file (relative paths resolve against the work dir)",
+									Required:    true,
+								},
+								"content": {
+									Type:        "string",
+									Description: "Full content of the file",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type writeFilesInput struct {
+	Files []agent.FileWrite `json:"files"`
+}
+
+type writtenFile struct {
+	Path  string `json:"path"`
+	Bytes int    `json:"bytes"`
+}
+
+// Info returns the tool info for Eino registration.
+func (t *WriteFilesTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return toolSpecToToolInfo(&WriteFilesManifest().Tools[0]), nil
+}
+
+// InvokableRun writes every file or none.
+func (t *WriteFilesTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	var input writeFilesInput
+	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "write_files: parse input: %w", err)
+	}
+	if len(input.Files) == 0 {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "write_files: files is required")
+	}
+	if len(input.Files) > maxWriteFiles {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "write_files: too many files (%d, max %d)", len(input.Files), maxWriteFiles)
+	}
+
+	sizes, err := t.writer.WriteFiles(ctx, input.Files)
+	if err != nil {
+		return "", fmt.Errorf("write_files: nothing written: %w", err)
+	}
+
+	written := make([]writtenFile, len(input.Files))
+	for i, f := range input.Files {
+		written[i] = writtenFile{Path: f.Path, Bytes: sizes[i]}
+	}
+	out, _ := json.Marshal(map[string]any{"files": written})
+	return string(out), nil
+}
@@ -2,11 +2,16 @@ package hands
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
@@ -121,6 +126,43 @@ func TestLoadManifest_MissingTools(t *testing.T) {
 	}
 }
 
+func TestLoadManifest_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"absent", `{"name": "p", "tools": [{"name": "t"}]}`, ""},
+		{"current", `{"schema_version": 1, "name": "p", "tools": [{"name": "t"}]}`, ""},
+		{"newer", `{"schema_version": 99, "name": "p", "tools": [{"name": "t"}]}`, "newer than the supported version"},
+		// A newer format that no longer parses still reports the version
+		{"newer incompatible", `{"schema_version": 2, "name": "p", "tools": {"t": {}}}`, "newer than the supported version"},
+		{"negative", `{"schema_version": -1, "name": "p", "tools": [{"name": "t"}]}`, "invalid schema_version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestPath := filepath.Join(t.TempDir(), "manifest.jsonc")
+			if err := os.WriteFile(manifestPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := LoadManifest(manifestPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadManifest: %v", err)
+			}
+			if m.SchemaVersion != ManifestSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", m.SchemaVersion, ManifestSchemaVersion)
+			}
+		})
+	}
+}
+
 func TestLoadManifest_DefaultToolName(t *testing.T) {
 	dir := t.TempDir()
 	manifestPath := filepath.Join(dir, "manifest.jsonc")
@@ -285,25 +327,147 @@ func TestBuildExtismManifest_WithResolved(t *testing.T) {
 	}
 }
 
+func TestBuildExtismManifest_EnvInConfig(t *testing.T) {
+	m := &PluginManifest{
+		Name:     "test",
+		WasmPath: "/tmp/test.wasm",
+		Tools:    []ToolSpec{{Name: "test", Func: "handle"}},
+		Config:   map[string]string{"API_ENDPOINT": "https://default", "MODE": "fast"},
+		Resolved: &ResolvedCapabilities{Env: map[string]string{"API_ENDPOINT": "https://custom"}},
+	}
+
+	em := BuildExtismManifest(m)
+
+	if em.Config["API_ENDPOINT"] != "https://custom" || em.Config["MODE"] != "fast" {
+		t.Errorf("Config = %v, want env to override the manifest config", em.Config)
+	}
+	if m.Config["API_ENDPOINT"] != "https://default" {
+		t.Error("the manifest config should not be modified")
+	}
+}
+
 func TestKVStore(t *testing.T) {
 	kv := NewKVStore()
 
 	// Get non-existent key
-	if v := kv.Get("missing"); v != nil {
+	if v := kv.Get("", "missing"); v != nil {
 		t.Errorf("Get(missing) = %v, want nil", v)
 	}
 
 	// Set and get
-	kv.Set("key1", []byte("value1"))
-	if v := kv.Get("key1"); string(v) != "value1" {
+	if err := kv.Set("", "key1", []byte("value1")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v := kv.Get("", "key1"); string(v) != "value1" {
 		t.Errorf("Get(key1) = %q, want %q", string(v), "value1")
 	}
 
 	// Overwrite
-	kv.Set("key1", []byte("value2"))
-	if v := kv.Get("key1"); string(v) != "value2" {
+	if err := kv.Set("", "key1", []byte("value2")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v := kv.Get("", "key1"); string(v) != "value2" {
 		t.Errorf("Get(key1) after overwrite = %q, want %q", string(v), "value2")
 	}
+
+	// Namespaces are isolated
+	if v := kv.Get("other_tool", "key1"); v != nil {
+		t.Errorf("Get(other_tool, key1) = %q, want nil", string(v))
+	}
+}
+
+func TestKVStore_Quotas(t *testing.T) {
+	kv, err := OpenKVStore("", 2, 4)
+	if err != nil {
+		t.Fatalf("OpenKVStore: %v", err)
+	}
+
+	if err := kv.Set("", "big", []byte("12345")); !errors.Is(err, ErrKVQuota) {
+		t.Errorf("oversized value: err = %v, want ErrKVQuota", err)
+	}
+	for _, k := range []string{"a", "b"} {
+		if err := kv.Set("", k, []byte("1")); err != nil {
+			t.Fatalf("Set(%s): %v", k, err)
+		}
+	}
+	if err := kv.Set("", "c", []byte("1")); !errors.Is(err, ErrKVQuota) {
+		t.Errorf("key over max_keys: err = %v, want ErrKVQuota", err)
+	}
+	if err := kv.Set("", "a", []byte("2")); err != nil {
+		t.Errorf("overwriting an existing key should not count as a new key: %v", err)
+	}
+	if err := kv.Set("tool", "c", []byte("1")); err != nil {
+		t.Errorf("quotas apply per namespace: %v", err)
+	}
+
+	before := kv.Snapshot("")
+	err = kv.Merge("", before, map[string][]byte{"a": []byte("1"), "b": []byte("1"), "c": []byte("1")})
+	if !errors.Is(err, ErrKVQuota) {
+		t.Errorf("Merge over max_keys: err = %v, want ErrKVQuota", err)
+	}
+	if got := kv.Snapshot(""); string(got["a"]) != "2" || len(got) != 2 {
+		t.Errorf("failed Merge should leave the namespace unchanged, got %v", got)
+	}
+}
+
+func TestKVStore_MergeKeepsConcurrentWrites(t *testing.T) {
+	kv := NewKVStore()
+	for k, v := range map[string]string{"cursor": "1", "stale": "x"} {
+		if err := kv.Set("", k, []byte(v)); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	// A plugin call starts from a snapshot of the vars...
+	before := kv.Snapshot("")
+	after := maps.Clone(before)
+	after["cursor"] = []byte("2")
+	delete(after, "stale")
+	after["added"] = []byte("y")
+	// ...while the same call writes through ozzie.kv_set.
+	if err := kv.Set("", "todos", []byte("[1]")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	if err := kv.Merge("", before, after); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	got := kv.Snapshot("")
+	want := map[string]string{"cursor": "2", "added": "y", "todos": "[1]"}
+	if len(got) != len(want) {
+		t.Fatalf("namespace = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if string(got[k]) != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestKVStore_Persistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo", "kv.json")
+
+	kv, err := OpenKVStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenKVStore: %v", err)
+	}
+	if err := kv.Set("", "todos", []byte(`{"items":[]}`)); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := kv.Merge("list", nil, map[string][]byte{"cursor": []byte("3")}); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	reopened, err := OpenKVStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if v := reopened.Get("", "todos"); string(v) != `{"items":[]}` {
+		t.Errorf("Get(todos) after reopen = %q", string(v))
+	}
+	if v := reopened.Get("list", "cursor"); string(v) != "3" {
+		t.Errorf("Get(list, cursor) after reopen = %q", string(v))
+	}
 }
 
 func TestToolSpecToToolInfo(t *testing.T) {
@@ -523,6 +687,81 @@ func TestGitTool_InvokableRun_Status(t *testing.T) {
 	}
 }
 
+// initGitRepo creates a repository with one commit of a.txt and returns its path.
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=Ada", "-c", "user.email=ada@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.txt")
+	run("commit", "-q", "-m", "add a")
+	return dir
+}
+
+func TestGitTool_InvokableRun_Log(t *testing.T) {
+	dir := initGitRepo(t)
+	ctx := events.ContextWithWorkDir(context.Background(), dir)
+
+	out, err := NewGitTool().InvokableRun(ctx, `{"action": "log", "args": {"max": 5}}`)
+	if err != nil {
+		t.Fatalf("InvokableRun: %v", err)
+	}
+	var result gitResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(result.Commits) != 1 {
+		t.Fatalf("commits = %+v, want 1", result.Commits)
+	}
+	c := result.Commits[0]
+	if len(c.Hash) != 40 || c.Author != "Ada" || c.Subject != "add a" || c.Date == "" {
+		t.Errorf("unexpected commit %+v", c)
+	}
+}
+
+func TestGitTool_InvokableRun_Diff(t *testing.T) {
+	dir := initGitRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := events.ContextWithWorkDir(context.Background(), dir)
+	tool := NewGitTool()
+
+	out, err := tool.InvokableRun(ctx, `{"action": "diff", "args": {"paths": ["a.txt"]}}`)
+	if err != nil {
+		t.Fatalf("InvokableRun: %v", err)
+	}
+	var result gitResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !strings.Contains(result.Output, "+two") {
+		t.Errorf("expected the patch in output, got %q", result.Output)
+	}
+	want := []gitDiffFile{{Path: "a.txt", Additions: 2}}
+	if !slices.Equal(result.Files, want) {
+		t.Errorf("files = %+v, want %+v", result.Files, want)
+	}
+
+	// Nothing is staged yet.
+	out, err = tool.InvokableRun(ctx, `{"action": "diff", "args": {"staged": true}}`)
+	if err != nil {
+		t.Fatalf("InvokableRun staged: %v", err)
+	}
+	if strings.Contains(out, "files") {
+		t.Errorf("expected an empty staged diff, got %s", out)
+	}
+}
+
 func TestGitTool_InvokableRun_InvalidAction(t *testing.T) {
 	tool := NewGitTool()
 	_, err := tool.InvokableRun(context.Background(), `{"action": "invalid_action"}`)
@@ -559,6 +798,9 @@ func newMockActivator(known []string) *mockActivator {
 }
 
 func (m *mockActivator) IsKnown(name string) bool { return m.known[name] }
+func (m *mockActivator) IsActive(sessionID, name string) bool {
+	return slices.Contains(m.activated[sessionID], name)
+}
 func (m *mockActivator) Activate(sessionID, name string) bool {
 	if !m.known[name] {
 		return false
@@ -659,3 +901,127 @@ func TestActivateTool_Registry_EmptyNames(t *testing.T) {
 		t.Error("expected error for empty names")
 	}
 }
+
+func TestActivateTool_Registry_ByTag(t *testing.T) {
+	activator := newMockActivator([]string{"web_search", "web_fetch", "git"})
+	registry := NewToolRegistry(nil)
+	for name, m := range map[string]*PluginManifest{
+		"web_search": WebSearchManifest(),
+		"web_fetch":  WebFetchManifest(),
+		"git":        GitManifest(),
+	} {
+		if err := registry.RegisterNative(name, NewGetVarTool(nil), m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	at := NewActivateTool(activator, registry, nil)
+	ctx := events.ContextWithSessionID(context.Background(), "sess1")
+
+	if _, err := at.InvokableRun(ctx, `{"tag": "WEB"}`); err != nil {
+		t.Fatalf("InvokableRun: %v", err)
+	}
+	if got := activator.activated["sess1"]; !slices.Equal(got, []string{"web_fetch", "web_search"}) {
+		t.Errorf("activated = %v, want [web_fetch web_search]", got)
+	}
+
+	// Already active: nothing left to match.
+	result, err := at.InvokableRun(ctx, `{"tag": "web"}`)
+	if err != nil {
+		t.Fatalf("InvokableRun: %v", err)
+	}
+	if !strings.Contains(result, `no inactive tools match tag \"web\"`) {
+		t.Errorf("result = %s", result)
+	}
+
+	lt := NewListAvailableToolsTool(activator, registry)
+	result, err = lt.InvokableRun(ctx, `{}`)
+	if err != nil {
+		t.Fatalf("list_available_tools: %v", err)
+	}
+	if !strings.Contains(result, `"name":"git"`) || strings.Contains(result, "web_search") {
+		t.Errorf("list_available_tools = %s, want only git", result)
+	}
+	if !strings.Contains(result, `"tags":["vcs"]`) {
+		t.Errorf("list_available_tools = %s, want vcs tag", result)
+	}
+}
+
+func TestToolRegistry_ToolsMatching_Capability(t *testing.T) {
+	registry := NewToolRegistry(nil)
+	m := &PluginManifest{
+		Name:         "fetcher",
+		Provider:     "native",
+		Capabilities: PluginCapabilities{HTTP: true},
+		Tools:        []ToolSpec{{Name: "fetch", Tags: []string{"web"}}},
+	}
+	if err := registry.RegisterNative("fetch", NewGetVarTool(nil), m); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterNative("git", NewGetVarTool(nil), GitManifest()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := registry.ToolsMatching("", "http"); !slices.Equal(got, []string{"fetch"}) {
+		t.Errorf("capability http = %v", got)
+	}
+	if got := registry.ToolsMatching("web", "exec"); len(got) != 0 {
+		t.Errorf("tag web + capability exec = %v, want none", got)
+	}
+	if got := registry.ToolsMatching("", ""); got != nil {
+		t.Errorf("empty filter = %v, want nil", got)
+	}
+}
+
+// spinWasm is a module exporting "handle", which loops forever, and "ok",
+// which returns 0:
+//
+//	(module
+//	  (func (export "handle") (result i32) (loop (br 0)) (i32.const 0))
+//	  (func (export "ok") (result i32) (i32.const 0)))
+var spinWasm = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // header
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f, // type: () -> i32
+	0x03, 0x03, 0x02, 0x00, 0x00, // functions
+	0x07, 0x0f, 0x02, // exports
+	0x06, 'h', 'a', 'n', 'd', 'l', 'e', 0x00, 0x00,
+	0x02, 'o', 'k', 0x00, 0x01,
+	0x0a, 0x10, 0x02, // code
+	0x09, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b,
+	0x04, 0x00, 0x41, 0x00, 0x0b,
+}
+
+func TestWasmTool_Cancellation(t *testing.T) {
+	wasmPath := filepath.Join(t.TempDir(), "spin.wasm")
+	if err := os.WriteFile(wasmPath, spinWasm, 0644); err != nil {
+		t.Fatal(err)
+	}
+	rt := NewExtismRuntime(events.NewBus(16))
+	defer rt.Close(context.Background())
+
+	tools, err := rt.Load(context.Background(), &PluginManifest{
+		Name:     "spin",
+		Provider: "extism",
+		WasmPath: wasmPath,
+		Tools:    []ToolSpec{{Name: "spin", Func: "handle"}, {Name: "spin_ok", Func: "ok"}},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = tools[0].InvokableRun(ctx, `{}`)
+	if err == nil || !strings.Contains(err.Error(), "plugin cancelled") {
+		t.Fatalf("err = %v, want plugin cancelled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("cancelled call returned after %v", elapsed)
+	}
+
+	// The aborted guest closed its instance; the next call gets a fresh one.
+	if _, err := tools[1].InvokableRun(context.Background(), `{}`); err != nil {
+		t.Fatalf("call after cancellation: %v", err)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/cloudwego/eino/components/tool"
@@ -178,6 +181,55 @@ func (r *ToolRegistry) ToolsByNames(names []string) []tool.InvokableTool {
 	return result
 }
 
+// ToolTags returns the sorted discovery tags of a tool: its plugin's tags
+// plus its own.
+func (r *ToolRegistry) ToolTags(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.toolTagsLocked(name)
+}
+
+func (r *ToolRegistry) toolTagsLocked(name string) []string {
+	var tags []string
+	if m := r.manifests[name]; m != nil {
+		tags = append(tags, m.Tags...)
+	}
+	if spec := r.specs[name]; spec != nil {
+		tags = append(tags, spec.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// ToolsMatching returns the sorted names of tools carrying the given tag
+// and/or whose plugin declares the given capability. Empty filters are
+// ignored; with both empty nothing matches. Comparison is case-insensitive.
+func (r *ToolRegistry) ToolsMatching(tag, capability string) []string {
+	if tag == "" && capability == "" {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for name := range r.tools {
+		if tag != "" && !slices.ContainsFunc(r.toolTagsLocked(name), func(t string) bool {
+			return strings.EqualFold(t, tag)
+		}) {
+			continue
+		}
+		if capability != "" {
+			m := r.manifests[name]
+			if m == nil || !m.Capabilities.Has(capability) {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // AllToolDescriptions returns a map of tool name → description for every
 // registered tool. Tools without a ToolSpec get an empty description.
 func (r *ToolRegistry) AllToolDescriptions() map[string]string {
@@ -2,11 +2,17 @@ package hands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
+	"sync"
 
 	extism "github.com/extism/go-sdk"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/sys"
 
+	"github.com/dohr-michael/ozzie/internal/config"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
@@ -14,19 +20,72 @@ import (
 type ExtismRuntime struct {
 	bus     events.EventBus
 	plugins map[string]*loadedPlugin
+	dataDir string // persistent plugin state, one subdirectory per plugin
 }
 
 type loadedPlugin struct {
 	manifest *PluginManifest
-	plugin   *extism.Plugin
+	instance *wasmInstance
 	kv       *KVStore
 }
 
+// wasmInstance is the plugin instance shared by the tools of a plugin.
+// Extism instances are not thread-safe, so calls are serialized. A call that
+// makes the guest exit (cancellation, timeout, proc_exit) closes the
+// instance; the next call replaces it with a fresh one.
+type wasmInstance struct {
+	mu       sync.Mutex
+	compiled *extism.CompiledPlugin
+	plugin   *extism.Plugin // nil after the guest exited
+}
+
+// acquire locks the instance and returns a live plugin, instantiating a new
+// one when the previous call closed it. On success, the caller must call release.
+func (w *wasmInstance) acquire(ctx context.Context) (*extism.Plugin, error) {
+	w.mu.Lock()
+	if w.plugin == nil {
+		p, err := w.compiled.Instance(context.WithoutCancel(ctx), extism.PluginInstanceConfig{})
+		if err != nil {
+			w.mu.Unlock()
+			return nil, fmt.Errorf("instantiate: %w", err)
+		}
+		w.plugin = p
+	}
+	return w.plugin, nil
+}
+
+// release unlocks the instance.
+func (w *wasmInstance) release() {
+	w.mu.Unlock()
+}
+
+// checkExit drops the plugin when callErr shows the guest exited: wazero has
+// closed the module at that point. The caller holds the instance.
+func (w *wasmInstance) checkExit(ctx context.Context, callErr error) {
+	var exitErr *sys.ExitError
+	if errors.As(callErr, &exitErr) {
+		_ = w.plugin.Close(context.WithoutCancel(ctx))
+		w.plugin = nil
+	}
+}
+
+// close releases the instance and the compiled module.
+func (w *wasmInstance) close(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.plugin != nil {
+		_ = w.plugin.Close(ctx)
+		w.plugin = nil
+	}
+	return w.compiled.Close(ctx)
+}
+
 // NewExtismRuntime creates a new runtime for loading WASM plugins.
 func NewExtismRuntime(bus events.EventBus) *ExtismRuntime {
 	return &ExtismRuntime{
 		bus:     bus,
 		plugins: make(map[string]*loadedPlugin),
+		dataDir: filepath.Join(config.OzziePath(), "plugin_data"),
 	}
 }
 
@@ -43,32 +102,44 @@ func (r *ExtismRuntime) Load(ctx context.Context, manifest *PluginManifest) ([]*
 	em := BuildExtismManifest(manifest)
 
 	// Per-plugin KV store
-	kv := NewKVStore()
+	kvOpts := kvOptions(manifest)
+	kv, err := r.openKVStore(manifest.Name, kvOpts)
+	if err != nil {
+		return nil, fmt.Errorf("runtime: plugin %q: %w", manifest.Name, err)
+	}
 
 	// Host functions
-	hostFns := NewHostFunctions(r.bus, kv, manifest.Config)
+	hostFns := NewHostFunctions(r.bus, kv, pluginConfig(manifest))
 
-	// Create plugin
+	// Create plugin. Calls are aborted as soon as their context is done,
+	// not only when the manifest timeout expires.
 	config := extism.PluginConfig{
-		EnableWasi: true,
+		EnableWasi:    true,
+		RuntimeConfig: wazero.NewRuntimeConfig().WithCloseOnContextDone(true),
 	}
 
-	plugin, err := extism.NewPlugin(ctx, em, config, hostFns)
+	compiled, err := extism.NewCompiledPlugin(ctx, em, config, hostFns)
 	if err != nil {
 		return nil, fmt.Errorf("runtime: load plugin %q: %w", manifest.Name, err)
 	}
+	plugin, err := compiled.Instance(ctx, extism.PluginInstanceConfig{})
+	if err != nil {
+		compiled.Close(ctx)
+		return nil, fmt.Errorf("runtime: load plugin %q: %w", manifest.Name, err)
+	}
+	instance := &wasmInstance{compiled: compiled, plugin: plugin}
 
 	// Verify that each tool's Func export exists
 	for _, ts := range manifest.Tools {
 		if !plugin.FunctionExists(ts.Func) {
-			plugin.Close(ctx)
+			instance.close(ctx)
 			return nil, fmt.Errorf("runtime: plugin %q missing required %q export", manifest.Name, ts.Func)
 		}
 	}
 
 	r.plugins[manifest.Name] = &loadedPlugin{
 		manifest: manifest,
-		plugin:   plugin,
+		instance: instance,
 		kv:       kv,
 	}
 
@@ -79,17 +150,46 @@ func (r *ExtismRuntime) Load(ctx context.Context, manifest *PluginManifest) ([]*
 	for i := range manifest.Tools {
 		tools[i] = &WasmTool{
 			spec:       &manifest.Tools[i],
-			plugin:     plugin,
+			instance:   instance,
 			pluginName: manifest.Name,
 		}
+		// With KV options, the Extism vars are backed by the KV store too
+		if kvOpts != nil {
+			tools[i].kv = kv
+			if kvOpts.PerTool {
+				tools[i].kvNamespace = manifest.Tools[i].Name
+			}
+		}
 	}
 	return tools, nil
 }
 
+// kvOptions returns the KV options of a plugin: the resolved ones when
+// capabilities were resolved (nil if KV is denied), the declared ones otherwise.
+func kvOptions(m *PluginManifest) *KVCapabilityIntent {
+	if m.Resolved != nil {
+		return m.Resolved.KVOptions
+	}
+	return m.Capabilities.KVOptions
+}
+
+// openKVStore opens a plugin's KV store: in memory, unless the plugin asks
+// for persistence.
+func (r *ExtismRuntime) openKVStore(plugin string, opts *KVCapabilityIntent) (*KVStore, error) {
+	if opts == nil {
+		return NewKVStore(), nil
+	}
+	var path string
+	if opts.Persistent {
+		path = filepath.Join(r.dataDir, plugin, "kv.json")
+	}
+	return OpenKVStore(path, opts.MaxKeys, opts.MaxValueBytes)
+}
+
 // Close releases all loaded plugins.
 func (r *ExtismRuntime) Close(ctx context.Context) {
 	for name, lp := range r.plugins {
-		if err := lp.plugin.Close(ctx); err != nil {
+		if err := lp.instance.close(ctx); err != nil {
 			slog.Warn("runtime: close plugin", "name", name, "error", err)
 		}
 	}
@@ -0,0 +1,263 @@
+package hands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+// SchemaGuard validates tool arguments against the tool's declared ParamSpec
+// schema (types, enums, required fields) before invoking it, so malformed
+// calls get a precise error instead of each tool re-checking its input.
+// Unambiguous type slips (see CoerceArgs) are fixed up rather than rejected.
+type SchemaGuard struct {
+	inner  brain.Tool
+	name   string
+	params map[string]ParamSpec
+}
+
+// WrapSchema wraps a tool with schema validation.
+func WrapSchema(t brain.Tool, name string, params map[string]ParamSpec) brain.Tool {
+	return &SchemaGuard{inner: t, name: name, params: params}
+}
+
+// Info delegates to the inner tool.
+func (g *SchemaGuard) Info(ctx context.Context) (*brain.ToolInfo, error) {
+	return g.inner.Info(ctx)
+}
+
+// Run coerces and validates the arguments before delegating to the inner tool.
+func (g *SchemaGuard) Run(ctx context.Context, argumentsInJSON string) (string, error) {
+	if coerced, applied := CoerceArgs(g.params, argumentsInJSON); len(applied) > 0 {
+		slog.Info("tool args coerced", "tool", g.name, "coercions", applied)
+		argumentsInJSON = coerced
+	}
+	if err := ValidateArgs(g.params, argumentsInJSON); err != nil {
+		return "", brain.ToolErrorf(brain.ToolErrInvalidInput, "%s: %w", g.name, err)
+	}
+	return g.inner.Run(ctx, argumentsInJSON)
+}
+
+// ValidateArgs checks JSON tool arguments against a parameter schema.
+// Unknown arguments are ignored; an explicit null counts as absent.
+func ValidateArgs(params map[string]ParamSpec, argumentsInJSON string) error {
+	args := map[string]any{}
+	if s := strings.TrimSpace(argumentsInJSON); s != "" {
+		if err := json.Unmarshal([]byte(s), &args); err != nil {
+			return fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+	}
+	return validateProperties("", params, args)
+}
+
+// CoerceArgs rewrites JSON tool arguments whose values unambiguously encode
+// the declared type: numeric strings for numbers and integers, "true"/"false"
+// for booleans, and a lone scalar where an array is expected. It returns the
+// rewritten arguments and one note per coercion; when nothing was coerced (or
+// the arguments do not parse) the input is returned unchanged with no notes.
+func CoerceArgs(params map[string]ParamSpec, argumentsInJSON string) (string, []string) {
+	s := strings.TrimSpace(argumentsInJSON)
+	if s == "" {
+		return argumentsInJSON, nil
+	}
+	// Numbers stay json.Number so re-encoding does not round large
+	// integers (IDs, timestamps) through float64.
+	args := map[string]any{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&args); err != nil || dec.InputOffset() != int64(len(s)) {
+		return argumentsInJSON, nil
+	}
+	var applied []string
+	coerceProperties("", params, args, &applied)
+	if len(applied) == 0 {
+		return argumentsInJSON, nil
+	}
+	sort.Strings(applied)
+	out, err := json.Marshal(args)
+	if err != nil {
+		return argumentsInJSON, nil
+	}
+	return string(out), applied
+}
+
+func coerceProperties(prefix string, params map[string]ParamSpec, args map[string]any, applied *[]string) {
+	for name, spec := range params {
+		if value, ok := args[name]; ok && value != nil {
+			args[name] = coerceValue(prefix+name, spec, value, applied)
+		}
+	}
+}
+
+func coerceValue(path string, spec ParamSpec, value any, applied *[]string) any {
+	switch spec.Type {
+	case "integer", "number":
+		str, ok := value.(string)
+		if !ok {
+			break
+		}
+		str = strings.TrimSpace(str)
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) || (spec.Type == "integer" && f != math.Trunc(f)) {
+			break
+		}
+		*applied = append(*applied, fmt.Sprintf("%s: string to %s", path, spec.Type))
+		// Keep the digits as written when they are a JSON number literal.
+		var n json.Number
+		if json.Unmarshal([]byte(str), &n) == nil && n.String() == str {
+			return n
+		}
+		return f
+	case "boolean":
+		str, ok := value.(string)
+		if !ok {
+			break
+		}
+		switch strings.ToLower(strings.TrimSpace(str)) {
+		case "true":
+			*applied = append(*applied, path+": string to boolean")
+			return true
+		case "false":
+			*applied = append(*applied, path+": string to boolean")
+			return false
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			if _, isObject := value.(map[string]any); isObject {
+				break
+			}
+			*applied = append(*applied, path+": scalar to array")
+			items = []any{value}
+		}
+		if spec.Items != nil {
+			for i, item := range items {
+				if item != nil {
+					items[i] = coerceValue(fmt.Sprintf("%s[%d]", path, i), *spec.Items, item, applied)
+				}
+			}
+		}
+		return items
+	case "object":
+		if obj, ok := value.(map[string]any); ok && len(spec.Properties) > 0 {
+			coerceProperties(path+".", spec.Properties, obj, applied)
+		}
+	}
+	return value
+}
+
+func validateProperties(prefix string, params map[string]ParamSpec, args map[string]any) error {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec := params[name]
+		value, ok := args[name]
+		if !ok || value == nil {
+			if spec.Required {
+				return fmt.Errorf("parameter %q: required", prefix+name)
+			}
+			continue
+		}
+		if err := validateValue(prefix+name, spec, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateValue(path string, spec ParamSpec, value any) error {
+	if got := jsonTypeOf(value); !typeMatches(spec.Type, value) {
+		return fmt.Errorf("parameter %q: expected %s, got %s", path, spec.Type, got)
+	}
+
+	if len(spec.Enum) > 0 {
+		s, isString := value.(string)
+		if !isString {
+			s = fmt.Sprint(value)
+		}
+		if !slices.Contains(spec.Enum, s) {
+			return fmt.Errorf("parameter %q: expected one of %s, got %q", path, strings.Join(spec.Enum, ", "), s)
+		}
+	}
+
+	switch v := value.(type) {
+	case []any:
+		if spec.Items != nil {
+			for i, item := range v {
+				if item == nil {
+					continue
+				}
+				if err := validateValue(fmt.Sprintf("%s[%d]", path, i), *spec.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		if len(spec.Properties) > 0 {
+			return validateProperties(path+".", spec.Properties, v)
+		}
+	}
+	return nil
+}
+
+// typeMatches reports whether value has the JSON type declared by typ.
+// Unknown or empty types accept anything.
+func typeMatches(typ string, value any) bool {
+	switch typ {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// jsonTypeOf names the JSON type of a decoded value.
+func jsonTypeOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+var _ brain.Tool = (*SchemaGuard)(nil)
@@ -0,0 +1,114 @@
+package hands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+func TestValidateArgs(t *testing.T) {
+	params := map[string]ParamSpec{
+		"title":    {Type: "string", Required: true},
+		"count":    {Type: "integer"},
+		"priority": {Type: "string", Enum: []string{"low", "normal", "high"}},
+		"tags":     {Type: "array", Items: &ParamSpec{Type: "string"}},
+		"opts": {Type: "object", Properties: map[string]ParamSpec{
+			"depth": {Type: "integer", Required: true},
+		}},
+	}
+
+	tests := []struct {
+		name string
+		args string
+		want string // substring of the error, empty for success
+	}{
+		{"valid", `{"title":"a","count":3,"priority":"high","tags":["x"],"opts":{"depth":1}}`, ""},
+		{"empty args", ``, `parameter "title": required`},
+		{"null counts as absent", `{"title":null}`, `parameter "title": required`},
+		{"wrong type", `{"title":"a","count":"3"}`, `parameter "count": expected integer, got string`},
+		{"fractional integer", `{"title":"a","count":1.5}`, `parameter "count": expected integer, got number`},
+		{"bad enum", `{"title":"a","priority":"urgent"}`, `parameter "priority": expected one of low, normal, high, got "urgent"`},
+		{"bad item", `{"title":"a","tags":["x",2]}`, `parameter "tags[1]": expected string, got integer`},
+		{"nested required", `{"title":"a","opts":{}}`, `parameter "opts.depth": required`},
+		{"unknown ignored", `{"title":"a","extra":true}`, ""},
+		{"not an object", `[1]`, "arguments must be a JSON object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateArgs(params, tt.args)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapRegistrySchema(t *testing.T) {
+	registry := NewToolRegistry(nil)
+	if err := registry.RegisterNative("get_var", NewGetVarTool(nil), GetVarManifest()); err != nil {
+		t.Fatal(err)
+	}
+	WrapRegistrySchema(registry)
+
+	// Rejected by the guard before reaching the (nil) store.
+	_, err := registry.Tool("get_var").InvokableRun(context.Background(), `{"key":42}`)
+	if err == nil || !strings.Contains(err.Error(), "expected string, got integer") {
+		t.Fatalf("error = %v", err)
+	}
+	if code := brain.ToolErrorCodeOf(err); code != brain.ToolErrInvalidInput {
+		t.Errorf("code = %q, want %q", code, brain.ToolErrInvalidInput)
+	}
+}
+
+func TestCoerceArgs(t *testing.T) {
+	params := map[string]ParamSpec{
+		"timeout": {Type: "integer"},
+		"ratio":   {Type: "number"},
+		"force":   {Type: "boolean"},
+		"names":   {Type: "array", Items: &ParamSpec{Type: "string"}},
+		"ids":     {Type: "array", Items: &ParamSpec{Type: "integer"}},
+		"label":   {Type: "string"},
+	}
+
+	tests := []struct {
+		name    string
+		args    string
+		want    string
+		applied int
+	}{
+		{"numeric string", `{"timeout":"5"}`, `{"timeout":5}`, 1},
+		{"float string", `{"ratio":" 0.5 "}`, `{"ratio":0.5}`, 1},
+		{"bool string", `{"force":"True"}`, `{"force":true}`, 1},
+		{"scalar to array", `{"names":"search"}`, `{"names":["search"]}`, 1},
+		{"scalar to array with item coercion", `{"ids":"7"}`, `{"ids":[7]}`, 2},
+		{"fractional integer left alone", `{"timeout":"1.5"}`, `{"timeout":"1.5"}`, 0},
+		{"ambiguous bool left alone", `{"force":"yes"}`, `{"force":"yes"}`, 0},
+		{"strings untouched", `{"label":"5"}`, `{"label":"5"}`, 0},
+		{"already valid", `{"timeout":5,"names":["a"]}`, `{"timeout":5,"names":["a"]}`, 0},
+		{"invalid json", `{`, `{`, 0},
+		{"trailing data", `{"timeout":"5"} {}`, `{"timeout":"5"} {}`, 0},
+		{"large integers kept exact", `{"ids":[9007199254740993],"timeout":"5"}`, `{"ids":[9007199254740993],"timeout":5}`, 1},
+		{"large integer string", `{"timeout":"9007199254740993"}`, `{"timeout":9007199254740993}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, applied := CoerceArgs(params, tt.args)
+			if got != tt.want || len(applied) != tt.applied {
+				t.Fatalf("CoerceArgs = %s %v, want %s with %d coercions", got, applied, tt.want, tt.applied)
+			}
+			if tt.applied > 0 {
+				if err := ValidateArgs(params, got); err != nil {
+					t.Errorf("coerced args still invalid: %v", err)
+				}
+			}
+		})
+	}
+}
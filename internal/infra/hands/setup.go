@@ -96,7 +96,7 @@ func RegisterWebTools(ctx context.Context, cfg *config.Config, registry *ToolReg
 	}
 }
 
-// RegisterFilesystemTools registers filesystem-based native tools (str_replace_editor).
+// RegisterFilesystemTools registers filesystem-based native tools (str_replace_editor, write_files).
 // backend implements both filesystem.Backend (Eino) and editor.Backend.
 func RegisterFilesystemTools(registry *ToolRegistry, backend *agent.OzzieBackend) {
 	editorTool := editortools.NewStrReplaceEditorTool(editor.New(backend))
@@ -104,6 +104,10 @@ func RegisterFilesystemTools(registry *ToolRegistry, backend *agent.OzzieBackend
 		resolvedNativeManifest(StrReplaceEditorManifest())); err != nil {
 		slog.Warn("failed to register str_replace_editor tool", "error", err)
 	}
+	if err := registry.RegisterNative("write_files", NewWriteFilesTool(backend),
+		resolvedNativeManifest(WriteFilesManifest())); err != nil {
+		slog.Warn("failed to register write_files tool", "error", err)
+	}
 }
 
 // wrapToolDomain converts a registry tool to a domain tool, applies a wrapper,
@@ -116,10 +120,11 @@ func wrapToolDomain(registry *ToolRegistry, name string, wrapFn func(brain.Tool)
 	registry.tools[name] = agent.UnwrapToEino(wrapped, einoInfo)
 }
 
-// WrapRegistrySandbox wraps exec and filesystem tools with sandbox validation.
+// WrapRegistrySandbox wraps exec, filesystem and network tools with sandbox validation.
 // Must be called BEFORE WrapRegistryDangerous so the chain is:
 // DangerousToolWrapper → SandboxGuard → inner tool.
-func WrapRegistrySandbox(registry *ToolRegistry, allowedPaths []string) {
+// opts are forwarded to every guard (e.g. conscience.WithAllowedCommands).
+func WrapRegistrySandbox(registry *ToolRegistry, allowedPaths []string, opts ...conscience.SandboxOption) {
 	for _, name := range registry.ToolNames() {
 		manifest := registry.Manifest(name)
 		if manifest == nil || manifest.Resolved == nil {
@@ -131,17 +136,22 @@ func WrapRegistrySandbox(registry *ToolRegistry, allowedPaths []string) {
 		case resolved.Elevated:
 			// root_cmd — blocked unconditionally in autonomous mode
 			wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
-				return conscience.WrapSandbox(t, name, conscience.SandboxExec, true, allowedPaths)
+				return conscience.WrapSandbox(t, name, conscience.SandboxExec, true, allowedPaths, opts...)
 			})
 		case resolved.Exec:
 			wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
-				return conscience.WrapSandbox(t, name, conscience.SandboxExec, false, allowedPaths)
+				return conscience.WrapSandbox(t, name, conscience.SandboxExec, false, allowedPaths, opts...)
 			})
 		case resolved.Filesystem != nil && !resolved.Filesystem.ReadOnly:
 			// Read-only filesystem tools (read_file, list_dir, search) are not sandboxed —
 			// sub-agents may need to read reference files outside their WorkDir.
 			wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
-				return conscience.WrapSandbox(t, name, conscience.SandboxFilesystem, false, allowedPaths)
+				return conscience.WrapSandbox(t, name, conscience.SandboxFilesystem, false, allowedPaths, opts...)
+			})
+		case manifest.Capabilities.HTTP:
+			// Outbound HTTP (declared capability — native tools resolve without auth)
+			wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
+				return conscience.WrapSandbox(t, name, conscience.SandboxNetwork, false, allowedPaths, opts...)
 			})
 		}
 	}
@@ -160,6 +170,25 @@ func WrapRegistryDangerous(registry *ToolRegistry, bus events.EventBus, perms *c
 	}
 }
 
+// WrapRegistrySchema wraps every tool that declares parameters with schema
+// validation. MCP tools are skipped: their server validates its own input.
+// Call it once, after every tool is registered.
+func WrapRegistrySchema(registry *ToolRegistry) {
+	for _, name := range registry.ToolNames() {
+		spec := registry.ToolSpec(name)
+		if spec == nil || len(spec.Parameters) == 0 {
+			continue
+		}
+		if m := registry.Manifest(name); m != nil && m.Provider == "mcp" {
+			continue
+		}
+		params := spec.Parameters
+		wrapToolDomain(registry, name, func(t brain.Tool) brain.Tool {
+			return WrapSchema(t, name, params)
+		})
+	}
+}
+
 // WrapRegistryConstraints wraps all tools in the registry with constraint validation.
 func WrapRegistryConstraints(registry *ToolRegistry) {
 	for _, name := range registry.ToolNames() {
@@ -2,11 +2,18 @@ package hands
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
 
+	"github.com/dohr-michael/ozzie/internal/config"
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 )
 
@@ -71,3 +78,69 @@ func TestWrapRegistrySandbox_SkipsReadOnly(t *testing.T) {
 		t.Error("write_file should be wrapped by SandboxGuard")
 	}
 }
+
+func TestWrapRegistrySandbox_NetworkHosts(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	registry := NewToolRegistry(bus)
+
+	fetchTool := &fakeTool{}
+	_ = registry.RegisterNative("web_fetch", fetchTool, resolvedNativeManifest(WebFetchManifest()))
+
+	WrapRegistrySandbox(registry, nil, conscience.WithAllowedHosts([]string{"*.golang.org"}))
+
+	if registry.tools["web_fetch"] == fetchTool {
+		t.Fatal("web_fetch should be wrapped by SandboxGuard")
+	}
+
+	ctx := events.WithAutonomous(context.Background())
+	if _, err := registry.tools["web_fetch"].(tool.InvokableTool).InvokableRun(ctx, `{"url":"https://pkg.golang.org/x"}`); err != nil {
+		t.Errorf("expected allowed host to pass, got: %v", err)
+	}
+	if _, err := registry.tools["web_fetch"].(tool.InvokableTool).InvokableRun(ctx, `{"url":"http://169.254.169.254/latest"}`); err == nil {
+		t.Error("expected disallowed host to be blocked")
+	}
+}
+
+func TestWrapRegistrySandbox_NetworkRedirects(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, "https://evil.test/final", http.StatusFound)
+		case "/stay":
+			http.Redirect(w, r, "https://allowed.test/final", http.StatusFound)
+		default:
+			fmt.Fprintf(w, "reached %s", r.Host)
+		}
+	}))
+	defer srv.Close()
+
+	// Route every host to the test server
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	fetch := NewWebFetchTool(config.WebFetchConfig{})
+	fetch.client.Transport = transport
+
+	bus := events.NewBus(16)
+	defer bus.Close()
+	registry := NewToolRegistry(bus)
+	_ = registry.RegisterNative("web_fetch", fetch, resolvedNativeManifest(WebFetchManifest()))
+	WrapRegistrySandbox(registry, nil, conscience.WithAllowedHosts([]string{"allowed.test"}))
+	run := registry.tools["web_fetch"].(tool.InvokableTool).InvokableRun
+
+	ctx := events.WithAutonomous(context.Background())
+	if _, err := run(ctx, `{"url":"https://allowed.test/away"}`); err == nil || !strings.Contains(err.Error(), "evil.test") {
+		t.Errorf("expected redirect to a disallowed host to be blocked, got: %v", err)
+	}
+	out, err := run(ctx, `{"url":"https://allowed.test/stay"}`)
+	if err != nil || !strings.Contains(out, "reached allowed.test") {
+		t.Errorf("expected redirect to an allowed host to pass, got %q, %v", out, err)
+	}
+	out, err = run(context.Background(), `{"url":"https://allowed.test/away"}`)
+	if err != nil || !strings.Contains(out, "reached evil.test") {
+		t.Errorf("expected interactive redirects to be unrestricted, got %q, %v", out, err)
+	}
+}
@@ -3,12 +3,12 @@ package hands
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/schema"
-	extism "github.com/extism/go-sdk"
 
 	"github.com/dohr-michael/ozzie/internal/infra/tasks"
 )
@@ -17,9 +17,11 @@ import (
 // Each WasmTool references a specific ToolSpec; multiple WasmTools may share the
 // same extism.Plugin when a plugin exports multiple functions.
 type WasmTool struct {
-	spec       *ToolSpec      // the specific tool this adapter wraps
-	plugin     *extism.Plugin // shared WASM plugin instance
-	pluginName string         // plugin name (for error messages)
+	spec        *ToolSpec     // the specific tool this adapter wraps
+	instance    *wasmInstance // shared WASM plugin instance
+	pluginName  string        // plugin name (for error messages)
+	kv          *KVStore      // backs the Extism vars (nil = vars stay in the plugin instance)
+	kvNamespace string        // KV namespace of this tool ("" = plugin-wide)
 }
 
 // Info returns the ToolInfo for Eino registration.
@@ -27,12 +29,57 @@ func (t *WasmTool) Info(_ context.Context) (*schema.ToolInfo, error) {
 	return toolSpecToToolInfo(t.spec), nil
 }
 
-// InvokableRun calls the WASM export named in spec.Func.
+// InvokableRun calls the WASM export named in spec.Func. It returns as soon
+// as ctx is done, with a "plugin cancelled" error; the guest is aborted in
+// the background.
 func (t *WasmTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
-	_, output, err := t.plugin.Call(t.spec.Func, []byte(argumentsInJSON))
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		output, err := t.call(ctx, argumentsInJSON)
+		done <- result{output, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.output, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("plugin %q func %q: plugin cancelled: %w", t.pluginName, t.spec.Func, context.Cause(ctx))
+	}
+}
+
+// call runs the export on the shared instance. When the vars are backed by
+// the KV store, they are loaded from the tool's namespace before the call and
+// the changes are merged back after a successful, uncancelled one, keeping
+// kv_set writes made during the call.
+func (t *WasmTool) call(ctx context.Context, argumentsInJSON string) (string, error) {
+	plugin, err := t.instance.acquire(ctx)
+	if err != nil {
+		return "", fmt.Errorf("plugin %q: %w", t.pluginName, err)
+	}
+	defer t.instance.release()
+
+	var before map[string][]byte
+	if t.kv != nil {
+		before = t.kv.Snapshot(t.kvNamespace)
+		plugin.Var = maps.Clone(before) // the guest writes into plugin.Var
+	}
+	_, output, err := plugin.CallWithContext(withKVNamespace(ctx, t.kvNamespace), t.spec.Func, []byte(argumentsInJSON))
+	t.instance.checkExit(ctx, err)
 	if err != nil {
 		return "", fmt.Errorf("plugin %q func %q: %w", t.pluginName, t.spec.Func, err)
 	}
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("plugin %q func %q: plugin cancelled: %w", t.pluginName, t.spec.Func, context.Cause(ctx))
+	}
+	if t.kv != nil {
+		if err := t.kv.Merge(t.kvNamespace, before, plugin.Var); err != nil {
+			return "", fmt.Errorf("plugin %q func %q: save vars: %w", t.pluginName, t.spec.Func, err)
+		}
+	}
 	return string(output), nil
 }
 
@@ -96,7 +143,7 @@ func paramTypeToDataType(t string) schema.DataType {
 
 var _ tool.InvokableTool = (*WasmTool)(nil)
 
-// enrichActorParamDescriptions rewrites the "actor_tags" and "required_capabilities"
+// enrichActorParamDescriptions rewrites the "actor_tags", "required_capabilities" and "model"
 // parameter descriptions in-place, injecting available actors so the LLM knows
 // which values are valid. The spec is mutated (callers pass a fresh copy).
 func enrichActorParamDescriptions(spec *ToolSpec, actors []tasks.ActorInfo) {
@@ -154,6 +201,15 @@ func enrichActorParamDescriptions(spec *ToolSpec, actors []tasks.ActorInfo) {
 		p.Description = desc.String()
 		spec.Parameters["required_capabilities"] = p
 	}
+
+	if p, ok := spec.Parameters["model"]; ok {
+		providers := make([]string, len(actors))
+		for i, a := range actors {
+			providers[i] = a.ProviderName
+		}
+		p.Description += " Available: [" + strings.Join(providers, ", ") + "]."
+		spec.Parameters["model"] = p
+	}
 }
 
 // sortedKeys returns the keys of a set sorted alphabetically.
@@ -3,8 +3,12 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/hands"
 )
@@ -176,3 +180,56 @@ func TestMatchesFilter(t *testing.T) {
 		t.Error("matchesFilter(run_command, other) = true, want false")
 	}
 }
+
+func TestNewMCPServer_DangerousGate(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowed   []string
+		wantError bool
+	}{
+		{"not pre-approved", nil, true},
+		{"globally allowed", []string{"run_command"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := events.NewBus(16)
+			defer bus.Close()
+
+			registry := hands.NewToolRegistry(bus)
+			defer registry.Close(context.Background())
+
+			if err := registry.RegisterNative("run_command", hands.NewExecuteTool(), hands.ExecuteManifest()); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			server := NewMCPServer(registry, "", WithDangerousGate(conscience.NewToolPermissions(tt.allowed)))
+			serverT, clientT := mcpsdk.NewInMemoryTransports()
+			if _, err := server.Connect(ctx, serverT, nil); err != nil {
+				t.Fatal(err)
+			}
+			client := mcpsdk.NewClient(&mcpsdk.Implementation{Name: "test", Version: "0"}, nil)
+			cs, err := client.Connect(ctx, clientT, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cs.Close()
+
+			res, err := cs.CallTool(ctx, &mcpsdk.CallToolParams{
+				Name:      "run_command",
+				Arguments: map[string]any{"command": "echo hello"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v", res.IsError, tt.wantError)
+			}
+			text := res.Content[0].(*mcpsdk.TextContent).Text
+			if tt.wantError && !strings.Contains(text, "requires approval") {
+				t.Errorf("error text = %q, want approval rejection", text)
+			}
+		})
+	}
+}
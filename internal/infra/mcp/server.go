@@ -2,17 +2,43 @@ package mcp
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/dohr-michael/ozzie/internal/core/conscience"
 	"github.com/dohr-michael/ozzie/internal/infra/hands"
 )
 
+// ServerOption configures NewMCPServer.
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	perms      *conscience.ToolPermissions
+	gateDanger bool
+}
+
+// WithDangerousGate rejects calls to dangerous tools unless they are
+// pre-approved: globally allowed (tools.allowed_dangerous) or covered by an
+// auto-approve rule. MCP clients cannot answer confirmation prompts, so an
+// unapproved call would otherwise block forever.
+func WithDangerousGate(perms *conscience.ToolPermissions) ServerOption {
+	return func(o *serverOptions) {
+		o.perms = perms
+		o.gateDanger = true
+	}
+}
+
 // NewMCPServer creates an MCP server exposing tools from the registry.
 // If filter is non-empty, only tools matching the filter (by tool name or
 // plugin name) are exposed.
-func NewMCPServer(registry *hands.ToolRegistry, filter string) *mcpsdk.Server {
+func NewMCPServer(registry *hands.ToolRegistry, filter string, opts ...ServerOption) *mcpsdk.Server {
+	var o serverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	server := mcpsdk.NewServer(&mcpsdk.Implementation{
 		Name:    "ozzie",
 		Version: "0.1.0",
@@ -33,9 +59,18 @@ func NewMCPServer(registry *hands.ToolRegistry, filter string) *mcpsdk.Server {
 		// Capture tool in closure
 		invokable := registry.Tool(name)
 		toolName := name
+		gated := o.gateDanger && spec.Dangerous
 
 		server.AddTool(mcpTool, func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
 			args := string(req.Params.Arguments)
+			if gated && !o.preApproved(toolName, args) {
+				slog.Info("mcp dangerous tool rejected", "tool", toolName)
+				return &mcpsdk.CallToolResult{
+					IsError: true,
+					Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: fmt.Sprintf(
+						"tool %q requires approval and is not pre-approved for MCP (see tools.allowed_dangerous)", toolName)}},
+				}, nil
+			}
 			result, err := invokable.InvokableRun(ctx, args)
 			if err != nil {
 				slog.Debug("mcp tool error", "tool", toolName, "error", err)
@@ -55,6 +90,18 @@ func NewMCPServer(registry *hands.ToolRegistry, filter string) *mcpsdk.Server {
 	return server
 }
 
+// preApproved reports whether a dangerous tool call may run without a prompt.
+func (o serverOptions) preApproved(toolName, args string) bool {
+	if o.perms == nil {
+		return false
+	}
+	if o.perms.IsAllowed("", toolName) {
+		return true
+	}
+	_, ok := o.perms.MatchAutoApprove(toolName, args)
+	return ok
+}
+
 // matchesFilter checks if a tool name matches the filter.
 // The filter can be a tool name or a plugin name (exposing all tools of that plugin).
 func matchesFilter(registry *hands.ToolRegistry, toolName, filter string) bool {
@@ -2,6 +2,7 @@ package membridge
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/dohr-michael/ozzie/internal/config"
 	"github.com/dohr-michael/ozzie/internal/infra/secrets"
+	"github.com/dohr-michael/ozzie/pkg/memory"
 )
 
 // EmbeddingFingerprint returns a unique string identifying the embedding config.
@@ -39,6 +41,22 @@ func NewEmbedder(ctx context.Context, cfg config.EmbeddingConfig, kr *secrets.Ke
 	}
 }
 
+// NewVectorStore creates the configured embedder and opens the SQLite vector
+// store on db. It fails with memory.ErrEmbeddingModelChanged if the store was
+// indexed with a different model or dimension; callers must then reset and
+// reindex (see memory.ResetSQLiteVectorStore).
+func NewVectorStore(ctx context.Context, db *sql.DB, cfg config.EmbeddingConfig, kr *secrets.KeyRing) (*memory.SQLiteVectorStore, error) {
+	embedder, err := NewEmbedder(ctx, cfg, kr)
+	if err != nil {
+		return nil, fmt.Errorf("create embedder: %w", err)
+	}
+	vs, err := memory.NewSQLiteVectorStore(db, embedder, cfg.Model, cfg.Dims)
+	if err != nil {
+		return nil, fmt.Errorf("create vector store: %w", err)
+	}
+	return vs, nil
+}
+
 func newOpenAIEmbedder(ctx context.Context, cfg config.EmbeddingConfig, kr *secrets.KeyRing) (embedding.Embedder, error) {
 	apiKey := resolveEmbeddingAuth(cfg, kr)
 	if apiKey == "" {
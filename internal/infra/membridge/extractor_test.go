@@ -2,6 +2,7 @@ package membridge
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -104,26 +105,44 @@ func (m *mockDedupRetriever) Retrieve(_ context.Context, _ string, _ []string, _
 	}, nil
 }
 
-// memoryStoreStub is a simple in-memory Store for testing.
+// memoryStoreStub is a simple in-memory Store for testing. It is written by
+// background goroutines: read it through its methods.
 type memoryStoreStub struct {
+	mu       sync.Mutex
 	entries  []*memory.MemoryEntry
 	contents map[string]string
+	created  chan string // receives the ID of every created entry
 }
 
 func newMemoryStoreStub() *memoryStoreStub {
-	return &memoryStoreStub{contents: make(map[string]string)}
+	return &memoryStoreStub{contents: make(map[string]string), created: make(chan string, 16)}
 }
 
 func (s *memoryStoreStub) Create(entry *memory.MemoryEntry, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if entry.ID == "" {
 		entry.ID = "mem_stub"
 	}
 	s.entries = append(s.entries, entry)
 	s.contents[entry.ID] = content
+	select {
+	case s.created <- entry.ID:
+	default:
+	}
 	return nil
 }
 
+// content returns the stored content of an entry.
+func (s *memoryStoreStub) content(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.contents[id]
+}
+
 func (s *memoryStoreStub) Get(id string) (*memory.MemoryEntry, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, e := range s.entries {
 		if e.ID == id {
 			return e, s.contents[id], nil
@@ -133,6 +152,8 @@ func (s *memoryStoreStub) Get(id string) (*memory.MemoryEntry, string, error) {
 }
 
 func (s *memoryStoreStub) Update(entry *memory.MemoryEntry, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for i, e := range s.entries {
 		if e.ID == entry.ID {
 			s.entries[i] = entry
@@ -144,6 +165,8 @@ func (s *memoryStoreStub) Update(entry *memory.MemoryEntry, content string) erro
 }
 
 func (s *memoryStoreStub) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for i, e := range s.entries {
 		if e.ID == id {
 			s.entries = append(s.entries[:i], s.entries[i+1:]...)
@@ -155,6 +178,8 @@ func (s *memoryStoreStub) Delete(id string) error {
 }
 
 func (s *memoryStoreStub) List() ([]*memory.MemoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	result := make([]*memory.MemoryEntry, len(s.entries))
 	copy(result, s.entries)
 	return result, nil
@@ -356,7 +381,7 @@ func TestExtractor_ExtractsAndStoresLessons(t *testing.T) {
 	if entry.Source != "task:task-123" {
 		t.Errorf("unexpected source: %s", entry.Source)
 	}
-	content := store.contents[entry.ID]
+	content := store.content(entry.ID)
 	if content != "Use bcrypt cost=12 for password hashing" {
 		t.Errorf("unexpected content: %s", content)
 	}
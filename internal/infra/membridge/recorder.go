@@ -0,0 +1,146 @@
+package membridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/core/prompt"
+	"github.com/dohr-michael/ozzie/pkg/memory"
+)
+
+// TaskSource reads tasks and their output.
+// tasks.Store satisfies this interface.
+type TaskSource interface {
+	TaskOutputReader
+	Get(id string) (*brain.Task, error)
+}
+
+// RecorderConfig configures the task result recorder.
+type RecorderConfig struct {
+	Store      memory.Store
+	Pipeline   *memory.Pipeline
+	Tasks      TaskSource
+	Summarizer memory.LLMSummarizer // optional: raw output is stored truncated without it
+	Bus        events.EventBus
+}
+
+// Recorder listens for task.completed events and stores a summary of the
+// output of tasks submitted with remember, so future sessions can recall
+// what they found.
+type Recorder struct {
+	store       memory.Store
+	pipeline    *memory.Pipeline
+	tasks       TaskSource
+	summarizer  memory.LLMSummarizer
+	bus         events.EventBus
+	ctx         context.Context
+	cancel      context.CancelFunc
+	unsubscribe func()
+	wg          sync.WaitGroup
+}
+
+// NewRecorder creates a new task result recorder.
+func NewRecorder(cfg RecorderConfig) *Recorder {
+	return &Recorder{
+		store:      cfg.Store,
+		pipeline:   cfg.Pipeline,
+		tasks:      cfg.Tasks,
+		summarizer: cfg.Summarizer,
+		bus:        cfg.Bus,
+	}
+}
+
+// Start subscribes to task.completed events.
+func (r *Recorder) Start() {
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.unsubscribe = r.bus.Subscribe(r.handleEvent, events.EventTaskCompleted)
+	slog.Info("task result recorder started")
+}
+
+// Stop cancels pending summaries, waits for in-flight goroutines, and unsubscribes.
+func (r *Recorder) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.unsubscribe != nil {
+		r.unsubscribe()
+	}
+	r.wg.Wait()
+	slog.Info("task result recorder stopped")
+}
+
+func (r *Recorder) handleEvent(ev events.Event) {
+	payload, ok := events.GetTaskCompletedPayload(ev)
+	if !ok {
+		return
+	}
+	task, err := r.tasks.Get(payload.TaskID)
+	if err != nil || !task.Config.Remember {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.remember(task)
+	}()
+}
+
+// maxRememberedLen caps the stored content when no summary is available.
+const maxRememberedLen = 1500
+
+func (r *Recorder) remember(task *brain.Task) {
+	output, err := r.tasks.ReadOutput(task.ID)
+	if err != nil || strings.TrimSpace(output) == "" {
+		return
+	}
+	if len(output) > maxOutputLen {
+		output = output[:maxOutputLen]
+	}
+
+	content := r.summarize(task, output)
+	now := time.Now()
+	entry := &memory.MemoryEntry{
+		Title:      "Task result: " + task.Title,
+		Type:       memory.MemoryContext,
+		Source:     "task:" + task.ID,
+		Tags:       []string{"task-result", task.ID},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		LastUsedAt: now,
+		Confidence: 0.8,
+	}
+	if err := r.store.Create(entry, content); err != nil {
+		slog.Debug("task result recorder: store failed", "task_id", task.ID, "error", err)
+		return
+	}
+	if r.pipeline != nil {
+		r.pipeline.Enqueue(memory.EmbedJob{
+			ID:      entry.ID,
+			Content: memory.BuildEmbedText(entry, content),
+			Meta:    memory.BuildEmbedMeta(entry),
+		})
+	}
+	slog.Info("task result recorder: stored result", "task_id", task.ID, "memory_id", entry.ID)
+}
+
+// summarize condenses the task output with the LLM, falling back to the
+// truncated output when no summarizer is configured or it fails.
+func (r *Recorder) summarize(task *brain.Task, output string) string {
+	if r.summarizer != nil {
+		resp, err := r.summarizer.Summarize(r.ctx, fmt.Sprintf(prompt.TaskResultSummaryPrompt, task.Title, output))
+		if err == nil && strings.TrimSpace(resp) != "" {
+			return strings.TrimSpace(resp)
+		}
+		slog.Debug("task result recorder: summarize failed, storing raw output", "task_id", task.ID, "error", err)
+	}
+	if len(output) > maxRememberedLen {
+		output = output[:maxRememberedLen] + "..."
+	}
+	return output
+}
@@ -0,0 +1,105 @@
+package membridge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+type mockTaskSource struct {
+	mockTaskReader
+	tasks map[string]*brain.Task
+}
+
+func (m *mockTaskSource) Get(id string) (*brain.Task, error) {
+	if t, ok := m.tasks[id]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("task %q not found", id)
+}
+
+func newRecorderFixture(t *testing.T, summarizer *mockSummarizer) (*memoryStoreStub, events.EventBus, *Recorder) {
+	t.Helper()
+	store := newMemoryStoreStub()
+	bus := events.NewBus(16)
+	t.Cleanup(bus.Close)
+
+	source := &mockTaskSource{
+		mockTaskReader: mockTaskReader{outputs: map[string]string{
+			"task-1": "Deploy found 3 failing health checks on billing.",
+			"task-2": "Nothing to remember.",
+		}},
+		tasks: map[string]*brain.Task{
+			"task-1": {ID: "task-1", Title: "Deploy", Config: brain.TaskConfig{Remember: true}},
+			"task-2": {ID: "task-2", Title: "Lint"},
+		},
+	}
+	cfg := RecorderConfig{Store: store, Tasks: source, Bus: bus}
+	if summarizer != nil {
+		cfg.Summarizer = summarizer
+	}
+	recorder := NewRecorder(cfg)
+	recorder.Start()
+	t.Cleanup(recorder.Stop)
+	return store, bus, recorder
+}
+
+// waitCreated waits for the stub store to receive an entry.
+func waitCreated(t *testing.T, store *memoryStoreStub) string {
+	t.Helper()
+	select {
+	case id := <-store.created:
+		return id
+	case <-time.After(5 * time.Second):
+		t.Fatal("no memory stored")
+		return ""
+	}
+}
+
+func TestRecorder_StoresRememberedTasks(t *testing.T) {
+	summarizer := &mockSummarizer{response: "The deploy found 3 failing health checks."}
+	store, bus, recorder := newRecorderFixture(t, summarizer)
+
+	bus.Publish(events.NewTypedEvent(events.SourceTask, events.TaskCompletedPayload{TaskID: "task-2", Title: "Lint"}))
+	bus.Publish(events.NewTypedEvent(events.SourceTask, events.TaskCompletedPayload{TaskID: "task-1", Title: "Deploy"}))
+
+	waitCreated(t, store)
+	// Events are handled in order: task-2 was handled before task-1. Stop
+	// waits for any write still in flight before the negative check.
+	recorder.Stop()
+	select {
+	case id := <-store.created:
+		t.Fatalf("a task without remember must not be stored, got %q", id)
+	default:
+	}
+
+	entries, _ := store.List()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 stored memory, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Source != "task:task-1" || !strings.Contains(e.Title, "Deploy") {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if len(e.Tags) != 2 || e.Tags[1] != "task-1" {
+		t.Errorf("entry should be tagged with the task ID, got %v", e.Tags)
+	}
+	if got := store.content(e.ID); got != summarizer.response {
+		t.Errorf("content = %q, want the summary", got)
+	}
+}
+
+func TestRecorder_FallsBackToOutput(t *testing.T) {
+	store, bus, _ := newRecorderFixture(t, nil)
+
+	bus.Publish(events.NewTypedEvent(events.SourceTask, events.TaskCompletedPayload{TaskID: "task-1", Title: "Deploy"}))
+
+	id := waitCreated(t, store)
+	if got := store.content(id); !strings.Contains(got, "3 failing health checks") {
+		t.Errorf("content = %q, want the raw output", got)
+	}
+}
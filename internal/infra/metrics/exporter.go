@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/dohr-michael/ozzie/internal/core/actors"
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/hands"
+)
+
+// Sources are the stats accessors read at scrape time. Nil sources are
+// skipped; Bus is required.
+type Sources struct {
+	Bus       events.EventBus
+	Tools     func() []hands.ToolStat
+	Actors    func() []actors.ProviderLoad
+	Tasks     brain.TaskStore
+	WSClients func() int
+}
+
+// llmKey identifies the LLM counters of one model.
+type llmKey struct {
+	model    string
+	provider string
+}
+
+type llmCounters struct {
+	calls, errors            uint64
+	input, output, reasoning uint64
+}
+
+// Exporter serves the gateway metrics. LLM token usage is accumulated from
+// internal.llm.call events; everything else is read from Sources on scrape.
+type Exporter struct {
+	src   Sources
+	unsub func()
+
+	mu  sync.Mutex
+	llm map[llmKey]*llmCounters
+}
+
+// NewExporter creates an exporter and starts counting LLM calls.
+func NewExporter(src Sources) *Exporter {
+	e := &Exporter{src: src, llm: make(map[llmKey]*llmCounters)}
+	e.unsub = src.Bus.Subscribe(e.recordLLMCall, events.EventLLMCall)
+	return e
+}
+
+// Close stops counting LLM calls.
+func (e *Exporter) Close() {
+	e.unsub()
+}
+
+func (e *Exporter) recordLLMCall(ev events.Event) {
+	p, ok := events.GetLLMCallPayload(ev)
+	if !ok || p.Phase == "request" {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	k := llmKey{model: p.Model, provider: p.Provider}
+	c, ok := e.llm[k]
+	if !ok {
+		c = &llmCounters{}
+		e.llm[k] = c
+	}
+	c.calls++
+	if p.Phase == "error" {
+		c.errors++
+	}
+	c.input += uint64(p.TokensInput)
+	c.output += uint64(p.TokensOutput)
+	c.reasoning += uint64(p.TokensReasoning)
+}
+
+// ServeHTTP writes every metric family.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	pw := NewWriter(w)
+	e.writeLLM(pw)
+	e.writeTools(pw)
+	e.writeTasks(pw)
+	e.writeActors(pw)
+	e.writeBus(pw)
+	if e.src.WSClients != nil {
+		pw.Family("ozzie_ws_clients", Gauge, "Connected WebSocket clients.")
+		pw.Sample("ozzie_ws_clients", float64(e.src.WSClients()))
+	}
+	if err := pw.Err(); err != nil {
+		slog.Debug("metrics scrape aborted", "error", err)
+	}
+}
+
+func (e *Exporter) writeLLM(pw *Writer) {
+	e.mu.Lock()
+	keys := make([]llmKey, 0, len(e.llm))
+	snap := make(map[llmKey]llmCounters, len(e.llm))
+	for k, c := range e.llm {
+		keys = append(keys, k)
+		snap[k] = *c
+	}
+	e.mu.Unlock()
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].model != keys[j].model {
+			return keys[i].model < keys[j].model
+		}
+		return keys[i].provider < keys[j].provider
+	})
+
+	pw.Family("ozzie_llm_calls_total", Counter, "LLM calls by model and outcome.")
+	for _, k := range keys {
+		c := snap[k]
+		pw.Sample("ozzie_llm_calls_total", float64(c.calls-c.errors), "model", k.model, "provider", k.provider, "outcome", "ok")
+		pw.Sample("ozzie_llm_calls_total", float64(c.errors), "model", k.model, "provider", k.provider, "outcome", "error")
+	}
+	pw.Family("ozzie_llm_tokens_total", Counter, "LLM tokens by model and direction.")
+	for _, k := range keys {
+		c := snap[k]
+		pw.Sample("ozzie_llm_tokens_total", float64(c.input), "model", k.model, "provider", k.provider, "direction", "input")
+		pw.Sample("ozzie_llm_tokens_total", float64(c.output), "model", k.model, "provider", k.provider, "direction", "output")
+		pw.Sample("ozzie_llm_tokens_total", float64(c.reasoning), "model", k.model, "provider", k.provider, "direction", "reasoning")
+	}
+}
+
+func (e *Exporter) writeTools(pw *Writer) {
+	if e.src.Tools == nil {
+		return
+	}
+	stats := e.src.Tools()
+
+	pw.Family("ozzie_tool_calls_total", Counter, "Tool invocations.")
+	for _, s := range stats {
+		pw.Sample("ozzie_tool_calls_total", float64(s.Calls), "tool", s.Name)
+	}
+	pw.Family("ozzie_tool_errors_total", Counter, "Failed tool invocations.")
+	for _, s := range stats {
+		pw.Sample("ozzie_tool_errors_total", float64(s.Errors), "tool", s.Name)
+	}
+	// Quantiles cover the most recent calls of each tool only.
+	pw.Family("ozzie_tool_duration_seconds", Summary, "Tool invocation latency.")
+	for _, s := range stats {
+		pw.Sample("ozzie_tool_duration_seconds", float64(s.MedianMs)/1000, "tool", s.Name, "quantile", "0.5")
+		pw.Sample("ozzie_tool_duration_seconds", float64(s.P95Ms)/1000, "tool", s.Name, "quantile", "0.95")
+		pw.Sample("ozzie_tool_duration_seconds_sum", float64(s.TotalMs)/1000, "tool", s.Name)
+		pw.Sample("ozzie_tool_duration_seconds_count", float64(s.Calls), "tool", s.Name)
+	}
+}
+
+func (e *Exporter) writeTasks(pw *Writer) {
+	if e.src.Tasks == nil {
+		return
+	}
+	list, err := e.src.Tasks.List(brain.ListFilter{})
+	if err != nil {
+		slog.Warn("metrics: list tasks", "error", err)
+		return
+	}
+	counts := make(map[brain.TaskStatus]int)
+	for _, t := range list {
+		counts[t.Status]++
+	}
+
+	pw.Family("ozzie_tasks", Gauge, "Tasks by status.")
+	for _, status := range []brain.TaskStatus{brain.TaskPending, brain.TaskRunning, brain.TaskCompleted, brain.TaskFailed, brain.TaskCancelled} {
+		pw.Sample("ozzie_tasks", float64(counts[status]), "status", string(status))
+	}
+}
+
+func (e *Exporter) writeActors(pw *Writer) {
+	if e.src.Actors == nil {
+		return
+	}
+	loads := e.src.Actors()
+
+	pw.Family("ozzie_actors", Gauge, "Actor pool capacity slots per provider.")
+	for _, l := range loads {
+		pw.Sample("ozzie_actors", float64(l.Total), "provider", l.Provider)
+	}
+	pw.Family("ozzie_actors_busy", Gauge, "Busy actor pool slots per provider.")
+	for _, l := range loads {
+		pw.Sample("ozzie_actors_busy", float64(l.Busy), "provider", l.Provider)
+	}
+}
+
+func (e *Exporter) writeBus(pw *Writer) {
+	stats := e.src.Bus.Stats()
+
+	pw.Family("ozzie_events_delivered_total", Counter, "Events dispatched by the event bus.")
+	pw.Sample("ozzie_events_delivered_total", float64(stats.Delivered))
+
+	pw.Family("ozzie_events_dropped_total", Counter, "Events dropped by the event bus overflow policy.")
+	types := make([]string, 0, len(stats.DroppedByType))
+	for t := range stats.DroppedByType {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		pw.Sample("ozzie_events_dropped_total", float64(stats.DroppedByType[events.EventType(t)]), "type", t)
+	}
+
+	pw.Family("ozzie_events_spilled_total", Counter, "Critical events delivered through the overflow queue.")
+	pw.Sample("ozzie_events_spilled_total", float64(stats.Spilled))
+}
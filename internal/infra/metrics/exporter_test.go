@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/actors"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+	"github.com/dohr-michael/ozzie/internal/infra/hands"
+)
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+
+	e := NewExporter(Sources{
+		Bus: bus,
+		Tools: func() []hands.ToolStat {
+			return []hands.ToolStat{{Name: "run_command", Calls: 4, Errors: 1, TotalMs: 2000, MedianMs: 400, P95Ms: 900}}
+		},
+		Actors:    func() []actors.ProviderLoad { return []actors.ProviderLoad{{Provider: "claude", Busy: 1, Total: 2}} },
+		WSClients: func() int { return 3 },
+	})
+	defer e.Close()
+
+	bus.Publish(events.NewTypedEvent(events.SourceAgent, events.LLMCallPayload{Phase: "request", Model: "sonnet"}))
+	bus.Publish(events.NewTypedEvent(events.SourceAgent, events.LLMCallPayload{
+		Phase: "response", Model: "sonnet", TokensInput: 120, TokensOutput: 30,
+	}))
+	bus.Publish(events.NewTypedEvent(events.SourceAgent, events.LLMCallPayload{Phase: "error", Model: "sonnet", Error: "boom"}))
+	deadline := time.Now().Add(time.Second)
+	for !e.llmCalls(2) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	for _, want := range []string{
+		"# TYPE ozzie_llm_tokens_total counter\n",
+		`ozzie_llm_tokens_total{model="sonnet",provider="",direction="input"} 120` + "\n",
+		`ozzie_llm_tokens_total{model="sonnet",provider="",direction="output"} 30` + "\n",
+		`ozzie_llm_calls_total{model="sonnet",provider="",outcome="ok"} 1` + "\n",
+		`ozzie_llm_calls_total{model="sonnet",provider="",outcome="error"} 1` + "\n",
+		`ozzie_tool_errors_total{tool="run_command"} 1` + "\n",
+		`ozzie_tool_duration_seconds{tool="run_command",quantile="0.95"} 0.9` + "\n",
+		`ozzie_tool_duration_seconds_sum{tool="run_command"} 2` + "\n",
+		`ozzie_actors_busy{provider="claude"} 1` + "\n",
+		"ozzie_events_delivered_total 3\n",
+		"ozzie_ws_clients 3\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "ozzie_tasks") {
+		t.Error("tasks family written without a task store")
+	}
+}
+
+func TestWriter_EscapesLabels(t *testing.T) {
+	var b strings.Builder
+	w := NewWriter(&b)
+	w.Sample("m", 1, "path", "a\"b\\c\nd")
+	if got, want := b.String(), `m{path="a\"b\\c\nd"} 1`+"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// llmCalls reports whether n LLM calls (requests excluded) were recorded.
+func (e *Exporter) llmCalls(n uint64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var total uint64
+	for _, c := range e.llm {
+		total += c.calls
+	}
+	return total >= n
+}
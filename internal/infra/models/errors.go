@@ -80,6 +80,17 @@ func IsRetryable(err error) bool {
 	return false
 }
 
+// IsContextOverflow returns true if the error indicates the request exceeded
+// the model's context window.
+func IsContextOverflow(err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := strings.ToLower(err.Error())
+	return containsAny(errStr, "context length", "context window", "context too long",
+		"too many tokens", "token limit", "prompt is too long", "maximum context")
+}
+
 // IsRateLimit returns true if the error indicates a rate limit (429).
 // Rate-limited requests use a longer minimum backoff.
 func IsRateLimit(err error) bool {
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// LiveModel delegates each call to the registry's current model for a provider,
+// so a rebuilt client (rotated key, reloaded config) is used without recreating
+// the agents that hold it.
+type LiveModel struct {
+	registry *Registry
+	name     string             // empty = registry default at call time
+	tools    []*schema.ToolInfo // bound via WithTools, applied per call
+}
+
+// resolve returns the current model for the provider, with tools bound.
+func (m *LiveModel) resolve(ctx context.Context) (model.ToolCallingChatModel, error) {
+	var inner model.ToolCallingChatModel
+	var err error
+	if m.name == "" {
+		inner, err = m.registry.Default(ctx)
+	} else {
+		inner, err = m.registry.Get(ctx, m.name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.tools != nil {
+		return inner.WithTools(m.tools)
+	}
+	return inner, nil
+}
+
+func (m *LiveModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	inner, err := m.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return inner.Generate(ctx, input, opts...)
+}
+
+func (m *LiveModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	inner, err := m.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return inner.Stream(ctx, input, opts...)
+}
+
+func (m *LiveModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &LiveModel{registry: m.registry, name: m.name, tools: tools}, nil
+}
+
+var _ model.ToolCallingChatModel = (*LiveModel)(nil)
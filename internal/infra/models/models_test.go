@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -158,6 +159,21 @@ func TestRegistry_DefaultName(t *testing.T) {
 	}
 }
 
+func TestRegistry_Names(t *testing.T) {
+	cfg := config.ModelsConfig{
+		Default: "main",
+		Providers: map[string]config.ProviderConfig{
+			"main":  {Driver: "anthropic"},
+			"local": {Driver: "ollama"},
+		},
+	}
+	reg := NewRegistry(cfg, nil)
+
+	if got := reg.Names(); !slices.Equal(got, []string{"local", "main"}) {
+		t.Fatalf("Names() = %v, want [local main]", got)
+	}
+}
+
 func TestCreateModel_UnknownDriver(t *testing.T) {
 	cfg := config.ProviderConfig{Driver: "unknown-driver"}
 	_, err := CreateModel(context.Background(), cfg, nil)
@@ -168,3 +184,41 @@ func TestCreateModel_UnknownDriver(t *testing.T) {
 		t.Fatalf("expected 'unknown driver' error, got %v", err)
 	}
 }
+
+func TestRegistry_ReloadAuth(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	os.Unsetenv("ANTHROPIC_API_KEY")
+
+	cfg := config.ModelsConfig{
+		Default: "claude",
+		Providers: map[string]config.ProviderConfig{
+			"claude": {Driver: "anthropic", Model: "claude-sonnet-4"},
+			"local":  {Driver: "openai-like", Model: "llama", BaseURL: "http://localhost:8080/v1"},
+		},
+	}
+	reg := NewRegistry(cfg, nil)
+	live := reg.Live("")
+
+	// No key yet: init fails and the error is cached.
+	if _, err := reg.Get(context.Background(), "claude"); err == nil {
+		t.Fatal("expected init error without a key")
+	}
+	if rotated := reg.ReloadAuth(); len(rotated) != 0 {
+		t.Fatalf("nothing changed, got rotated %v", rotated)
+	}
+
+	// Key rotated in the environment: only the affected provider is rebuilt.
+	t.Setenv("ANTHROPIC_API_KEY", "rotated-key")
+	if rotated := reg.ReloadAuth(); len(rotated) != 1 || rotated[0] != "claude" {
+		t.Fatalf("expected [claude] rotated, got %v", rotated)
+	}
+	if _, err := reg.Get(context.Background(), "claude"); err != nil {
+		t.Fatalf("expected rebuilt model, got %v", err)
+	}
+	if _, err := live.resolve(context.Background()); err != nil {
+		t.Fatalf("live model should resolve the rebuilt provider: %v", err)
+	}
+	if rotated := reg.ReloadAuth(); len(rotated) != 0 {
+		t.Fatalf("second reload should be a no-op, got %v", rotated)
+	}
+}
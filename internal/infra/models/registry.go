@@ -2,8 +2,10 @@ package models
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 
@@ -43,11 +45,27 @@ const fallbackContextWindow = 100000
 
 // ProviderEntry holds a lazily-initialized model instance.
 type ProviderEntry struct {
-	Config config.ProviderConfig
-	model  model.ToolCallingChatModel
-	once   sync.Once
-	err    error
-	cb     *CircuitBreaker // nil when no resilience configured
+	Config  config.ProviderConfig
+	model   model.ToolCallingChatModel
+	once    sync.Once
+	err     error
+	cb      *CircuitBreaker // nil when no resilience configured
+	authSum [32]byte        // fingerprint of the credentials resolved at entry creation
+}
+
+// newProviderEntry creates an uninitialized entry, fingerprinting its current credentials.
+func (r *Registry) newProviderEntry(cfg config.ProviderConfig) *ProviderEntry {
+	return &ProviderEntry{Config: cfg, authSum: r.authFingerprint(cfg)}
+}
+
+// authFingerprint hashes the credentials a provider would use right now
+// (resolution errors included, so a key appearing later counts as a change).
+func (r *Registry) authFingerprint(cfg config.ProviderConfig) [32]byte {
+	auth, err := ResolveAuth(cfg, r.kr)
+	if err != nil {
+		return sha256.Sum256([]byte("error:" + err.Error()))
+	}
+	return sha256.Sum256([]byte(fmt.Sprintf("%d:%s", auth.Kind, auth.Value)))
 }
 
 // Registry manages named model providers with lazy initialization.
@@ -68,7 +86,7 @@ func NewRegistry(cfg config.ModelsConfig, kr *secrets.KeyRing) *Registry {
 	}
 
 	for name, provCfg := range cfg.Providers {
-		r.providers[name] = &ProviderEntry{Config: provCfg}
+		r.providers[name] = r.newProviderEntry(provCfg)
 	}
 
 	return r
@@ -131,6 +149,13 @@ func (r *Registry) Get(ctx context.Context, name string) (model.ToolCallingChatM
 	return m, nil
 }
 
+// Live returns a model that resolves the named provider (empty = the current
+// default) from the registry on every call. Long-lived holders such as agents
+// use it so that clients rebuilt by ReloadAuth or a config reload take effect.
+func (r *Registry) Live(name string) *LiveModel {
+	return &LiveModel{registry: r, name: name}
+}
+
 // Default returns the default model.
 func (r *Registry) Default(ctx context.Context) (model.ToolCallingChatModel, error) {
 	if r.defaultName == "" {
@@ -144,6 +169,32 @@ func (r *Registry) DefaultName() string {
 	return r.defaultName
 }
 
+// Names returns the configured provider names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ProviderInfo returns the driver, model ID and base URL of the named
+// provider (empty when unknown).
+func (r *Registry) ProviderInfo(name string) (driver, model, baseURL string) {
+	r.mu.RLock()
+	entry, ok := r.providers[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", "", ""
+	}
+	return entry.Config.Driver, entry.Config.Model, entry.Config.BaseURL
+}
+
 // DefaultContextWindow returns the context window size for the default provider.
 func (r *Registry) DefaultContextWindow() int {
 	return r.ContextWindow(r.defaultName)
@@ -191,12 +242,32 @@ func (r *Registry) UpdateProviders(cfg config.ModelsConfig) {
 		if existing, ok := r.providers[name]; ok && existing.Config.Equal(provCfg) {
 			newProviders[name] = existing // unchanged — keep cached model
 		} else {
-			newProviders[name] = &ProviderEntry{Config: provCfg}
+			newProviders[name] = r.newProviderEntry(provCfg)
 		}
 	}
 	r.providers = newProviders
 }
 
+// ReloadAuth re-resolves the credentials of every provider (env vars, .env,
+// encrypted values) and drops the cached client of each provider whose
+// credentials changed, so the next call rebuilds it with the rotated key.
+// In-flight calls finish on the old client. Returns the rebuilt provider names.
+func (r *Registry) ReloadAuth() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rotated []string
+	for name, entry := range r.providers {
+		if r.authFingerprint(entry.Config) == entry.authSum {
+			continue
+		}
+		r.providers[name] = r.newProviderEntry(entry.Config)
+		rotated = append(rotated, name)
+	}
+	slices.Sort(rotated)
+	return rotated
+}
+
 // resolveContextWindow determines context window: explicit config > model prefix > driver default > fallback.
 func resolveContextWindow(cfg config.ProviderConfig) int {
 	if cfg.ContextWindow > 0 {
@@ -47,8 +47,10 @@ type TaskTemplate struct {
 // SkillScheduleInfo carries the scheduling-relevant data from a skill definition.
 // Used to decouple the scheduler package from the skills package.
 type SkillScheduleInfo struct {
-	Name    string
-	Cron    string
-	OnEvent *EventTrigger
+	Name       string
+	Version    string // version declaring the triggers; its runs are pinned to it
+	Cron       string
+	OnEvent    *EventTrigger
+	AfterSkill string // fire when this skill completes successfully
 }
 
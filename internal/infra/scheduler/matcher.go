@@ -16,8 +16,8 @@ func MatchEvent(e events.Event, trigger *EventTrigger) bool {
 		return false
 	}
 
-	// Event type must match
-	if string(e.Type) != trigger.Event {
+	// Event type must match ("task.*" matches every task event)
+	if !events.MatchPattern(trigger.Event, e.Type) {
 		return false
 	}
 
@@ -3,9 +3,12 @@ package scheduler
 import (
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/tasks"
 	"github.com/dohr-michael/ozzie/pkg/names"
@@ -20,6 +23,7 @@ type Config struct {
 	Bus    events.EventBus
 	Skills []SkillScheduleInfo // skill-based schedule entries (converted by caller)
 	Store  *ScheduleStore      // nil-safe: dynamic entries are not persisted without a store
+	Quiet  *brain.QuietHours   // nil = no quiet hours
 }
 
 // Entry represents a scheduled skill trigger (legacy, kept for Entries() compat).
@@ -39,15 +43,19 @@ type runtimeEntry struct {
 	title       string
 	description string
 	skillName   string
+	skillVer    string // pinned skill version (skill entries; "" = latest)
 	cron        *CronExpr
 	intervalSec int
 	onEvent     *EventTrigger
+	afterSkill  string // fire when this skill completes successfully
 	tmpl        *TaskTemplate
 	cooldown    time.Duration
 	maxRuns     int
 	runCount    int
 	enabled     bool
 	lastRun     time.Time
+	deferred    string            // trigger held back by quiet hours, fired when the window ends
+	deferVars   map[string]string // skill vars of the deferred trigger
 }
 
 // toEntry converts to the legacy Entry type for backward compat.
@@ -67,13 +75,21 @@ type Scheduler struct {
 	bus    events.EventBus
 	skills []SkillScheduleInfo
 	store  *ScheduleStore
+	quiet  *brain.QuietHours
 
 	mu      sync.Mutex
 	entries map[string]*runtimeEntry
 
-	done        chan struct{}
-	wg          sync.WaitGroup
-	unsubscribe func()
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	// Event subscriptions, one per on_event pattern not covered by another
+	// (see coveringPatterns), so handleEvent sees each event some entry
+	// listens to exactly once. Guarded by subMu (not mu: the bus calls
+	// handleEvent, which takes mu, while holding its own lock).
+	subMu   sync.Mutex
+	started bool
+	subs    map[string]func()
 }
 
 // New creates a new Scheduler.
@@ -83,8 +99,10 @@ func New(cfg Config) *Scheduler {
 		bus:     cfg.Bus,
 		skills:  cfg.Skills,
 		store:   cfg.Store,
+		quiet:   cfg.Quiet,
 		entries: make(map[string]*runtimeEntry),
 		done:    make(chan struct{}),
+		subs:    make(map[string]func()),
 	}
 }
 
@@ -97,8 +115,11 @@ func (s *Scheduler) Start() {
 
 	slog.Info("scheduler started", "entries", len(s.entries))
 
-	// Always start loops and event subscription — entries can be added dynamically.
-	s.unsubscribe = s.bus.Subscribe(s.handleEvent)
+	// Always start loops and event subscriptions — entries can be added dynamically.
+	s.subMu.Lock()
+	s.started = true
+	s.subMu.Unlock()
+	s.syncSubscriptions()
 	s.wg.Add(2)
 	go s.cronLoop()
 	go s.intervalLoop()
@@ -107,9 +128,13 @@ func (s *Scheduler) Start() {
 // Stop halts the scheduler and waits for goroutines to finish.
 func (s *Scheduler) Stop() {
 	close(s.done)
-	if s.unsubscribe != nil {
-		s.unsubscribe()
+	s.subMu.Lock()
+	s.started = false
+	for pattern, unsubscribe := range s.subs {
+		unsubscribe()
+		delete(s.subs, pattern)
 	}
+	s.subMu.Unlock()
 	s.wg.Wait()
 	slog.Info("scheduler stopped")
 }
@@ -178,6 +203,7 @@ func (s *Scheduler) AddEntry(se *ScheduleEntry) error {
 	s.mu.Lock()
 	s.entries[se.ID] = re
 	s.mu.Unlock()
+	s.syncSubscriptions()
 
 	slog.Info("scheduler: added entry", "id", se.ID, "title", se.Title, "source", se.Source)
 	return nil
@@ -193,6 +219,7 @@ func (s *Scheduler) RemoveEntry(id string) error {
 	}
 	delete(s.entries, id)
 	s.mu.Unlock()
+	s.syncSubscriptions()
 
 	// Remove from persistent store
 	if s.store != nil && re.source == "dynamic" {
@@ -260,13 +287,15 @@ func (s *Scheduler) loadSkillEntries() {
 	for _, sk := range s.skills {
 		id := "skill_" + sk.Name
 		re := &runtimeEntry{
-			id:        id,
-			source:    "skill",
-			title:     sk.Name,
-			skillName: sk.Name,
-			onEvent:   sk.OnEvent,
-			cooldown:  DefaultCooldown,
-			enabled:   true,
+			id:         id,
+			source:     "skill",
+			title:      sk.Name,
+			skillName:  sk.Name,
+			skillVer:   sk.Version,
+			onEvent:    sk.OnEvent,
+			afterSkill: sk.AfterSkill,
+			cooldown:   DefaultCooldown,
+			enabled:    true,
 		}
 
 		if sk.Cron != "" {
@@ -281,7 +310,7 @@ func (s *Scheduler) loadSkillEntries() {
 		s.entries[id] = re
 
 		slog.Info("scheduler: registered skill entry", "skill", sk.Name,
-			"cron", sk.Cron, "has_event", sk.OnEvent != nil)
+			"cron", sk.Cron, "has_event", sk.OnEvent != nil, "after_skill", sk.AfterSkill)
 	}
 }
 
@@ -371,7 +400,7 @@ func (s *Scheduler) checkMissedRuns(now time.Time) {
 
 		if missed {
 			slog.Info("scheduler: catch-up trigger", "id", entry.id, "last_run", entry.lastRun)
-			s.triggerEntry(entry, "catch-up")
+			s.fireOrDefer(entry, "catch-up", now, nil)
 		}
 	}
 }
@@ -402,6 +431,7 @@ func (s *Scheduler) intervalLoop() {
 			return
 		case now := <-ticker.C:
 			s.checkIntervals(now)
+			s.checkDeferred(now)
 		}
 	}
 }
@@ -421,7 +451,7 @@ func (s *Scheduler) checkCron(now time.Time) {
 			continue
 		}
 
-		s.triggerEntry(entry, "cron")
+		s.fireOrDefer(entry, "cron", now, nil)
 	}
 }
 
@@ -438,7 +468,7 @@ func (s *Scheduler) checkIntervals(now time.Time) {
 			continue
 		}
 
-		s.triggerEntry(entry, "interval")
+		s.fireOrDefer(entry, "interval", now, nil)
 	}
 }
 
@@ -447,6 +477,9 @@ func (s *Scheduler) handleEvent(e events.Event) {
 	defer s.mu.Unlock()
 
 	now := time.Now()
+	if e.Type == events.EventSkillCompleted {
+		s.handleSkillCompleted(e, now)
+	}
 	for _, entry := range s.entries {
 		if entry.onEvent == nil || !entry.enabled {
 			continue
@@ -458,10 +491,147 @@ func (s *Scheduler) handleEvent(e events.Event) {
 			continue
 		}
 
-		s.triggerEntry(entry, "event:"+string(e.Type))
+		s.fireOrDefer(entry, "event:"+string(e.Type), now, nil)
+	}
+}
+
+// handleSkillCompleted fires the entries chained after the completed skill,
+// passing its output and the chain lineage on. Failed runs do not chain, and
+// an entry whose skill already ran in the chain is a cycle: it is dropped.
+// Caller must hold s.mu.
+func (s *Scheduler) handleSkillCompleted(e events.Event, now time.Time) {
+	payload, ok := events.GetSkillCompletedPayload(e)
+	if !ok || payload.Error != "" {
+		return
+	}
+	lineage := append(slices.Clone(payload.Chain), payload.SkillName)
+	for _, entry := range s.entries {
+		if entry.afterSkill != payload.SkillName || !entry.enabled {
+			continue
+		}
+		if slices.Contains(lineage, entry.skillName) {
+			slog.Warn("scheduler: skill chain cycle, dropped", "skill", entry.skillName, "chain", lineage)
+			continue
+		}
+		s.fireOrDefer(entry, "skill:"+payload.SkillName, now, map[string]string{
+			"trigger_skill":      payload.SkillName,
+			"trigger_output":     payload.Output,
+			events.SkillChainVar: strings.Join(lineage, ","),
+		})
 	}
 }
 
+// syncSubscriptions subscribes to the event patterns used by on_event entries
+// and drops subscriptions no entry needs anymore. Overlapping patterns share
+// one subscription, so an event is never dispatched twice. No-op until Start.
+func (s *Scheduler) syncSubscriptions() {
+	s.mu.Lock()
+	wanted := make(map[string]bool)
+	for _, entry := range s.entries {
+		if entry.onEvent != nil && entry.onEvent.Event != "" {
+			wanted[entry.onEvent.Event] = true
+		}
+		if entry.afterSkill != "" {
+			wanted[string(events.EventSkillCompleted)] = true
+		}
+	}
+	s.mu.Unlock()
+	wanted = coveringPatterns(wanted)
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if !s.started {
+		return
+	}
+	for pattern, unsubscribe := range s.subs {
+		if !wanted[pattern] {
+			unsubscribe()
+			delete(s.subs, pattern)
+		}
+	}
+	for pattern := range wanted {
+		if _, ok := s.subs[pattern]; !ok {
+			s.subs[pattern] = s.bus.SubscribePattern(pattern, s.handleEvent)
+		}
+	}
+}
+
+// coveringPatterns drops the patterns matched by another wildcard pattern of
+// the set ("task.completed" and "task.f*" under "task.*", everything under
+// "*"): subscribing to the rest delivers each event once. handleEvent still
+// matches every entry's own pattern.
+func coveringPatterns(patterns map[string]bool) map[string]bool {
+	covering := make(map[string]bool, len(patterns))
+	for p := range patterns {
+		covered := false
+		for q := range patterns {
+			prefix, wildcard := strings.CutSuffix(q, "*")
+			if wildcard && q != p && strings.HasPrefix(strings.TrimSuffix(p, "*"), prefix) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			covering[p] = true
+		}
+	}
+	return covering
+}
+
+// checkDeferred fires the triggers held back by quiet hours once the window has ended.
+func (s *Scheduler) checkDeferred(now time.Time) {
+	if s.quiet.Active(now) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.entries {
+		if entry.deferred == "" {
+			continue
+		}
+		trigger, vars := entry.deferred, entry.deferVars
+		entry.deferred, entry.deferVars = "", nil
+		if entry.enabled {
+			s.triggerEntry(entry, "deferred:"+trigger, vars)
+		}
+	}
+}
+
+// fireOrDefer triggers the entry unless quiet hours are active, in which case
+// the trigger is deferred or skipped according to the quiet-hours policy.
+// skillVars are passed to the run (see submit). Caller must hold s.mu.
+func (s *Scheduler) fireOrDefer(re *runtimeEntry, trigger string, now time.Time, skillVars map[string]string) {
+	if !s.quiet.Active(now) {
+		s.triggerEntry(re, trigger, skillVars)
+		return
+	}
+
+	action := "deferred"
+	if s.quiet.Policy() == brain.QuietSkip {
+		action = "skipped"
+		// Consume the occurrence so intervals and cooldowns don't re-fire every tick.
+		re.lastRun = now
+	} else {
+		if re.deferred != "" {
+			return // already waiting for the window to end
+		}
+		re.deferred, re.deferVars = trigger, skillVars
+	}
+
+	until := s.quiet.Until(now)
+	s.bus.Publish(events.NewTypedEvent(events.SourceScheduler, events.ScheduleDeferredPayload{
+		EntryID: re.id,
+		Title:   re.title,
+		Trigger: trigger,
+		Action:  action,
+		Until:   until.Format(time.RFC3339),
+	}))
+
+	slog.Info("scheduler: quiet hours", "id", re.id, "trigger", trigger, "action", action, "until", until)
+}
+
 // TriggerEntry manually triggers a schedule entry by ID, bypassing cooldown and
 // cron/interval checks. Returns the created task ID.
 func (s *Scheduler) TriggerEntry(id string) (string, error) {
@@ -476,14 +646,20 @@ func (s *Scheduler) TriggerEntry(id string) (string, error) {
 		return "", fmt.Errorf("schedule entry is disabled: %s", id)
 	}
 
-	taskID := s.triggerEntry(re, "manual")
+	taskID := s.triggerEntry(re, "manual", nil)
 	return taskID, nil
 }
 
 // triggerEntry submits a task for the given entry. Caller must hold s.mu.
 // Returns the created task ID.
-func (s *Scheduler) triggerEntry(re *runtimeEntry, trigger string) string {
+func (s *Scheduler) triggerEntry(re *runtimeEntry, trigger string, skillVars map[string]string) string {
 	re.lastRun = time.Now()
+	return s.submit(re, trigger, skillVars)
+}
+
+// submit creates and submits the entry's task; skillVars are passed to a
+// skill entry's run. Caller must hold s.mu. Returns the created task ID.
+func (s *Scheduler) submit(re *runtimeEntry, trigger string, skillVars map[string]string) string {
 	re.runCount++
 
 	var task *tasks.Task
@@ -508,11 +684,19 @@ func (s *Scheduler) triggerEntry(re *runtimeEntry, trigger string) string {
 			Title:       "scheduled: " + re.skillName,
 			Description: "Triggered by scheduler (" + trigger + ")",
 			Config: tasks.TaskConfig{
-				Skill: re.skillName,
+				Skill:     brain.SkillRef(re.skillName, re.skillVer),
+				SkillVars: skillVars,
 			},
 		}
 	}
 
+	// Overlapping triggers (catch-up, quiet-hours release, cron and event at
+	// once) reuse the entry's task while it is pending or running. Event data
+	// passed as skill vars makes each run distinct.
+	if len(skillVars) == 0 {
+		task.IdempotencyKey = "schedule:" + re.id
+	}
+
 	if err := s.pool.Submit(task); err != nil {
 		slog.Error("scheduler: submit task", "id", re.id, "error", err)
 		return ""
@@ -1,10 +1,13 @@
 package scheduler
 
 import (
+	"maps"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/dohr-michael/ozzie/internal/core/actors"
+	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/internal/infra/tasks"
 )
@@ -107,6 +110,126 @@ func TestScheduler_EventTrigger(t *testing.T) {
 	}
 }
 
+func TestScheduler_EventPatternTrigger(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	pool := newTestPool(t, bus)
+	pool.Start()
+	defer pool.Stop()
+
+	s := New(Config{Pool: pool, Bus: bus})
+	s.Start()
+	defer s.Stop()
+
+	if err := s.AddEntry(&ScheduleEntry{
+		Source:      "dynamic",
+		Title:       "on any task event",
+		Description: "react",
+		OnEvent:     &EventTrigger{Event: "task.*"},
+		Enabled:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.subMu.Lock()
+	_, subscribed := s.subs["task.*"]
+	s.subMu.Unlock()
+	if !subscribed {
+		t.Fatal("expected a task.* subscription")
+	}
+
+	triggerCh, unsub := bus.SubscribeChan(4, events.EventScheduleTrigger)
+	defer unsub()
+
+	bus.Publish(events.NewTypedEvent(events.SourceTask, events.TaskFailedPayload{TaskID: "task_abc"}))
+
+	select {
+	case <-triggerCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for schedule trigger event")
+	}
+
+	// Removing the last entry using the pattern drops the subscription.
+	for _, e := range s.ListEntries() {
+		if err := s.RemoveEntry(e.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if len(s.subs) != 0 {
+		t.Errorf("expected no subscriptions, got %d", len(s.subs))
+	}
+}
+
+func TestCoveringPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"disjoint", []string{"task.*", "skill.completed"}, []string{"skill.completed", "task.*"}},
+		{"exact under wildcard", []string{"task.*", "task.completed"}, []string{"task.*"}},
+		{"narrower wildcard", []string{"task.*", "task.f*"}, []string{"task.*"}},
+		{"everything", []string{"*", "task.*", "skill.completed"}, []string{"*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make(map[string]bool)
+			for _, p := range tt.in {
+				in[p] = true
+			}
+			got := slices.Sorted(maps.Keys(coveringPatterns(in)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("coveringPatterns(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_OverlappingPatternsDispatchOnce(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	pool := newTestPool(t, bus)
+	s := New(Config{Pool: pool, Bus: bus, Skills: []SkillScheduleInfo{
+		{Name: "summarize", AfterSkill: "fetch"},
+	}})
+	s.Start()
+	defer s.Stop()
+
+	// skill.* overlaps the skill.completed subscription of the chained entry.
+	if err := s.AddEntry(&ScheduleEntry{
+		Source:      "dynamic",
+		Title:       "on any skill event",
+		Description: "react",
+		OnEvent:     &EventTrigger{Event: "skill.*"},
+		Enabled:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sentinel, unsub := bus.SubscribeChan(1, "test.sentinel")
+	defer unsub()
+	bus.Publish(events.NewTypedEvent(events.SourceSkill, events.SkillCompletedPayload{SkillName: "fetch"}))
+	// Events are delivered in order: once the sentinel arrives, every
+	// handler of the completion has returned.
+	bus.Publish(events.NewEvent("test.sentinel", events.SourceSkill, nil))
+	select {
+	case <-sentinel:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for the sentinel event")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, re := range s.entries {
+		if re.runCount != 1 {
+			t.Errorf("entry %q ran %d times, want once", re.title, re.runCount)
+		}
+	}
+}
+
 func TestScheduler_CooldownPreventsDoubleTrigger(t *testing.T) {
 	bus := newTestBus()
 	defer bus.Close()
@@ -605,3 +728,191 @@ func TestScheduler_NoStoreBackwardsCompat(t *testing.T) {
 		t.Fatal("expected 0 entries with no skills and no store")
 	}
 }
+
+func TestScheduler_QuietHours(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantAction string
+		wantFire   bool // trigger fires once the window ends
+	}{
+		{policy: "defer", wantAction: "deferred", wantFire: true},
+		{policy: "skip", wantAction: "skipped"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.policy, func(t *testing.T) {
+			bus := newTestBus()
+			defer bus.Close()
+
+			pool := newTestPool(t, bus)
+
+			deferredCh, unsubDeferred := bus.SubscribeChan(4, events.EventScheduleDeferred)
+			defer unsubDeferred()
+			triggerCh, unsubTrigger := bus.SubscribeChan(4, events.EventScheduleTrigger)
+			defer unsubTrigger()
+
+			quiet, err := brain.ParseQuietHours([]string{"22:00-07:00"}, "UTC", tc.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := New(Config{Pool: pool, Bus: bus, Quiet: quiet})
+			s.entries["sched_night"] = &runtimeEntry{
+				id:          "sched_night",
+				source:      "dynamic",
+				title:       "nightly",
+				intervalSec: 60,
+				cooldown:    DefaultCooldown,
+				enabled:     true,
+				tmpl:        &TaskTemplate{Title: "nightly task", Description: "test"},
+			}
+
+			night := time.Date(2026, 1, 15, 23, 0, 0, 0, time.UTC)
+			s.checkIntervals(night)
+			s.checkIntervals(night.Add(time.Second)) // must not report twice
+
+			select {
+			case e := <-deferredCh:
+				p, ok := events.GetScheduleDeferredPayload(e)
+				if !ok {
+					t.Fatal("failed to extract deferred payload")
+				}
+				if p.EntryID != "sched_night" || p.Action != tc.wantAction || p.Trigger != "interval" {
+					t.Fatalf("unexpected payload: %+v", p)
+				}
+				if p.Until != "2026-01-16T07:00:00Z" {
+					t.Errorf("until = %q, want end of window", p.Until)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("timeout waiting for deferred event")
+			}
+			select {
+			case e := <-deferredCh:
+				t.Fatalf("unexpected second deferral: %+v", e.Payload)
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			// Still quiet: nothing fires.
+			s.checkDeferred(night.Add(time.Hour))
+			// Window over: a deferred trigger fires once.
+			s.checkDeferred(time.Date(2026, 1, 16, 7, 0, 1, 0, time.UTC))
+
+			select {
+			case e := <-triggerCh:
+				if !tc.wantFire {
+					t.Fatalf("skipped trigger must not fire, got %+v", e.Payload)
+				}
+				p, _ := events.GetScheduleTriggerPayload(e)
+				if p.Trigger != "deferred:interval" {
+					t.Errorf("trigger = %q, want %q", p.Trigger, "deferred:interval")
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tc.wantFire {
+					t.Fatal("expected deferred trigger to fire after quiet hours")
+				}
+			}
+		})
+	}
+}
+
+func TestScheduler_SkillChain(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	pool := newTestPool(t, bus)
+	triggerCh, unsub := bus.SubscribeChan(8, events.EventScheduleTrigger)
+	defer unsub()
+
+	s := New(Config{Pool: pool, Bus: bus, Skills: []SkillScheduleInfo{
+		{Name: "summarize", AfterSkill: "fetch"},
+		{Name: "fetch", AfterSkill: "summarize"},
+	}})
+	s.loadSkillEntries()
+
+	completed := func(skill, errMsg string, chain ...string) events.Event {
+		return events.NewTypedEvent(events.SourceSkill, events.SkillCompletedPayload{
+			SkillName: skill, Output: skill + " output", Error: errMsg, Chain: chain,
+		})
+	}
+
+	s.handleEvent(completed("fetch", "boom"))          // failed runs do not chain
+	s.handleEvent(completed("fetch", ""))              // fetch → summarize
+	s.handleEvent(completed("summarize", "", "fetch")) // summarize → fetch: fetch already ran in the chain, dropped
+	s.handleEvent(completed("fetch", ""))              // a new fetch run chains again
+
+	var fired []events.ScheduleTriggerPayload
+	timeout := time.After(200 * time.Millisecond)
+collect:
+	for {
+		select {
+		case e := <-triggerCh:
+			p, _ := events.GetScheduleTriggerPayload(e)
+			fired = append(fired, p)
+		case <-timeout:
+			break collect
+		}
+	}
+	if len(fired) != 2 {
+		t.Fatalf("expected 2 chained triggers, got %+v", fired)
+	}
+	for _, f := range fired {
+		if f.SkillName != "summarize" || f.Trigger != "skill:fetch" {
+			t.Errorf("trigger = %+v, want summarize after fetch", f)
+		}
+	}
+
+	task, err := pool.Store().Get(fired[0].TaskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := task.Config.SkillVars
+	if vars["trigger_skill"] != "fetch" || vars["trigger_output"] != "fetch output" || vars[events.SkillChainVar] != "fetch" {
+		t.Errorf("skill vars = %v, want the upstream skill, output and chain", vars)
+	}
+}
+
+func TestScheduler_SkillChainQuietHours(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	pool := newTestPool(t, bus)
+	triggerCh, unsub := bus.SubscribeChan(4, events.EventScheduleTrigger)
+	defer unsub()
+
+	quiet, err := brain.ParseQuietHours([]string{"22:00-07:00"}, "UTC", "defer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(Config{Pool: pool, Bus: bus, Quiet: quiet, Skills: []SkillScheduleInfo{
+		{Name: "summarize", AfterSkill: "fetch"},
+	}})
+	s.loadSkillEntries()
+
+	night := time.Date(2026, 1, 15, 23, 0, 0, 0, time.UTC)
+	s.mu.Lock()
+	s.handleSkillCompleted(events.NewTypedEvent(events.SourceSkill, events.SkillCompletedPayload{
+		SkillName: "fetch", Output: "fetch output",
+	}), night)
+	for _, re := range s.entries {
+		if re.deferred != "skill:fetch" || re.runCount != 0 {
+			t.Fatalf("chained run must wait for the end of quiet hours, got deferred=%q runs=%d", re.deferred, re.runCount)
+		}
+	}
+	s.mu.Unlock()
+
+	s.checkDeferred(time.Date(2026, 1, 16, 7, 0, 1, 0, time.UTC))
+	select {
+	case e := <-triggerCh:
+		p, _ := events.GetScheduleTriggerPayload(e)
+		if p.Trigger != "deferred:skill:fetch" {
+			t.Errorf("trigger = %q, want %q", p.Trigger, "deferred:skill:fetch")
+		}
+		task, err := pool.Store().Get(p.TaskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.Config.SkillVars["trigger_output"] != "fetch output" {
+			t.Errorf("skill vars = %v, want the upstream output kept", task.Config.SkillVars)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the deferred chained run to fire after quiet hours")
+	}
+}
@@ -0,0 +1,182 @@
+package sessions
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+// auditResultMax bounds the tool result kept in an audit entry (runes).
+const auditResultMax = 1000
+
+// auditPendingTTL is how long a started invocation waits for its completion
+// before it is logged as incomplete (e.g. the run was cancelled mid-call).
+const auditPendingTTL = time.Hour
+
+// AuditEntry is one tool invocation in the audit log. The schema is stable:
+// fields may be added, never renamed or removed.
+type AuditEntry struct {
+	Ts         time.Time `json:"ts"` // invocation start
+	SessionID  string    `json:"session_id,omitempty"`
+	TaskID     string    `json:"task_id,omitempty"`
+	CallID     string    `json:"call_id,omitempty"`
+	Tool       string    `json:"tool"`
+	Arguments  string    `json:"arguments,omitempty"`
+	Result     string    `json:"result,omitempty"` // truncated
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	Autonomous bool      `json:"autonomous"`
+	DurationMs int64     `json:"duration_ms"`
+	// AutoApproved names the auto-approve rule that skipped confirmation, if any.
+	AutoApproved string `json:"auto_approved,omitempty"`
+	// Incomplete marks an invocation whose completion was never seen.
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// AuditLogger subscribes to tool call events and appends one AuditEntry per
+// finished invocation to the session's audit log. Unlike the event logger it
+// records only tool calls, with a stable schema, in the session directory.
+type AuditLogger struct {
+	mu          sync.Mutex
+	store       Store
+	pending     map[string]*AuditEntry // started, not yet finished
+	unsubscribe func()
+}
+
+// NewAuditLogger creates an AuditLogger listening for tool call events.
+func NewAuditLogger(bus events.EventBus, store Store) *AuditLogger {
+	al := &AuditLogger{
+		store:   store,
+		pending: make(map[string]*AuditEntry),
+	}
+	al.unsubscribe = bus.Subscribe(al.handleEvent, events.EventToolCall)
+	return al
+}
+
+// Close unsubscribes the logger from the event bus and logs the invocations
+// still in flight as incomplete.
+func (al *AuditLogger) Close() {
+	if al.unsubscribe != nil {
+		al.unsubscribe()
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	for key, entry := range al.pending {
+		delete(al.pending, key)
+		al.appendIncomplete(entry, time.Now())
+	}
+}
+
+// auditKey pairs the started and finished events of one invocation. Without a
+// call ID, invocations of the same tool in a session are assumed sequential.
+func auditKey(sessionID string, p events.ToolCallPayload) string {
+	return sessionID + "\x00" + p.Name + "\x00" + p.CallID
+}
+
+func (al *AuditLogger) handleEvent(e events.Event) {
+	p, ok := events.GetToolCallPayload(e)
+	if !ok {
+		return
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	al.evictStale(e.Timestamp)
+
+	switch p.Status {
+	case events.ToolStatusStarted:
+		// Approval wrappers re-announce a call already started (without call ID),
+		// possibly carrying the auto-approve rule that let it through.
+		if p.CallID == "" {
+			if entry := al.findPending(e.SessionID, p.Name); entry != nil {
+				if p.AutoApproved != "" {
+					entry.AutoApproved = p.AutoApproved
+				}
+				return
+			}
+		}
+		entry := &AuditEntry{
+			Ts:         e.Timestamp,
+			SessionID:  e.SessionID,
+			TaskID:     p.TaskID,
+			CallID:     p.CallID,
+			Tool:       p.Name,
+			Autonomous: p.Autonomous,
+		}
+		entry.AutoApproved = p.AutoApproved
+		if raw, ok := p.Arguments["raw"].(string); ok {
+			entry.Arguments = raw
+		}
+		al.pending[auditKey(e.SessionID, p)] = entry
+
+	case events.ToolStatusCompleted, events.ToolStatusFailed:
+		key := auditKey(e.SessionID, p)
+		entry, ok := al.pending[key]
+		if ok {
+			delete(al.pending, key)
+		} else {
+			// Start not seen (e.g. gateway restarted mid-call): log what we have.
+			entry = &AuditEntry{
+				Ts:         e.Timestamp,
+				SessionID:  e.SessionID,
+				TaskID:     p.TaskID,
+				CallID:     p.CallID,
+				Tool:       p.Name,
+				Autonomous: p.Autonomous,
+			}
+		}
+		entry.Success = p.Status == events.ToolStatusCompleted
+		entry.Result = truncateRunes(p.Result, auditResultMax)
+		entry.Error = p.Error
+		entry.DurationMs = e.Timestamp.Sub(entry.Ts).Milliseconds()
+
+		if err := al.store.AppendAudit(e.SessionID, *entry); err != nil {
+			slog.Error("audit log: append", "session_id", e.SessionID, "tool", p.Name, "error", err)
+		}
+	}
+}
+
+// evictStale logs the pending invocations started more than auditPendingTTL
+// before now as incomplete, so calls that never finish do not pile up.
+// Caller must hold al.mu.
+func (al *AuditLogger) evictStale(now time.Time) {
+	for key, entry := range al.pending {
+		if now.Sub(entry.Ts) > auditPendingTTL {
+			delete(al.pending, key)
+			al.appendIncomplete(entry, now)
+		}
+	}
+}
+
+// appendIncomplete logs an invocation whose completion was never seen.
+func (al *AuditLogger) appendIncomplete(entry *AuditEntry, now time.Time) {
+	entry.Incomplete = true
+	entry.Error = "incomplete: no completion recorded"
+	entry.DurationMs = now.Sub(entry.Ts).Milliseconds()
+	if err := al.store.AppendAudit(entry.SessionID, *entry); err != nil {
+		slog.Error("audit log: append", "session_id", entry.SessionID, "tool", entry.Tool, "error", err)
+	}
+}
+
+// findPending returns an invocation of tool in flight in the session, or nil.
+// Caller must hold al.mu.
+func (al *AuditLogger) findPending(sessionID, tool string) *AuditEntry {
+	for _, entry := range al.pending {
+		if entry.SessionID == sessionID && entry.Tool == tool {
+			return entry
+		}
+	}
+	return nil
+}
+
+func truncateRunes(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen]) + "..."
+}
@@ -0,0 +1,211 @@
+package sessions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+func publishToolEvent(bus *events.Bus, sessionID string, p events.ToolCallPayload) {
+	bus.Publish(events.NewTypedEventWithSession(events.SourceAgent, p, sessionID))
+}
+
+func waitAudit(t *testing.T, store *FileStore, sessionID string, n int) []AuditEntry {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entries, err := store.LoadAudit(sessionID)
+		if err != nil {
+			t.Fatalf("load audit: %v", err)
+		}
+		if len(entries) >= n || time.Now().After(deadline) {
+			return entries
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAuditLogger_PairsStartAndEnd(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+
+	store := NewFileStore(t.TempDir())
+	sess, err := store.Create()
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	al := NewAuditLogger(bus, store)
+	defer al.Close()
+
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusStarted, Name: "run_command", CallID: "call_1", TaskID: "task_1", Autonomous: true,
+		Arguments: map[string]any{"raw": `{"command":"ls"}`},
+	})
+	// Approval wrapper re-announces the same call without an ID — ignored.
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusStarted, Name: "run_command",
+		Arguments: map[string]any{"raw": `{"command":"ls"}`},
+	})
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusCompleted, Name: "run_command", CallID: "call_1",
+		Result: strings.Repeat("x", auditResultMax+10),
+	})
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusStarted, Name: "read_file", CallID: "call_2",
+		Arguments: map[string]any{"raw": `{"path":"missing"}`},
+	})
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusFailed, Name: "read_file", CallID: "call_2", Error: "no such file",
+	})
+
+	entries := waitAudit(t, store, sess.ID, 2)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d: %+v", len(entries), entries)
+	}
+
+	run := entries[0]
+	if run.Tool != "run_command" || !run.Success || !run.Autonomous || run.TaskID != "task_1" {
+		t.Errorf("unexpected entry: %+v", run)
+	}
+	if run.Arguments != `{"command":"ls"}` {
+		t.Errorf("arguments = %q", run.Arguments)
+	}
+	if got := len([]rune(run.Result)); got != auditResultMax+3 {
+		t.Errorf("result length = %d, want truncated to %d+...", got, auditResultMax)
+	}
+
+	read := entries[1]
+	if read.Tool != "read_file" || read.Success || read.Error != "no such file" || read.Autonomous {
+		t.Errorf("unexpected entry: %+v", read)
+	}
+	if read.Arguments != `{"path":"missing"}` {
+		t.Errorf("arguments = %q", read.Arguments)
+	}
+}
+
+func TestAuditLogger_NoSession(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+
+	store := NewFileStore(t.TempDir())
+	al := NewAuditLogger(bus, store)
+	defer al.Close()
+
+	publishToolEvent(bus, "", events.ToolCallPayload{Status: events.ToolStatusStarted, Name: "web_fetch"})
+	publishToolEvent(bus, "", events.ToolCallPayload{Status: events.ToolStatusCompleted, Name: "web_fetch", Result: "ok"})
+
+	entries := waitAudit(t, store, "", 1)
+	if len(entries) != 1 || entries[0].Tool != "web_fetch" || !entries[0].Success {
+		t.Fatalf("unexpected sessionless audit: %+v", entries)
+	}
+
+	// Sessionless log must not show up as a session.
+	if list, _ := store.List(); len(list) != 0 {
+		t.Errorf("expected no sessions, got %d", len(list))
+	}
+}
+
+func TestAuditLogger_AutoApproved(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+
+	store := NewFileStore(t.TempDir())
+	sess, err := store.Create()
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	al := NewAuditLogger(bus, store)
+	defer al.Close()
+
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusStarted, Name: "run_command", CallID: "call_1",
+		Arguments: map[string]any{"raw": `{"command":"git status"}`},
+	})
+	// The dangerous wrapper re-announces the call with the matching rule.
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusStarted, Name: "run_command", AutoApproved: "run_command command =~ git status",
+	})
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{
+		Status: events.ToolStatusCompleted, Name: "run_command", CallID: "call_1", Result: "clean",
+	})
+
+	entries := waitAudit(t, store, sess.ID, 1)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d: %+v", len(entries), entries)
+	}
+	if got := entries[0].AutoApproved; got != "run_command command =~ git status" {
+		t.Errorf("auto_approved = %q", got)
+	}
+}
+
+func TestAuditLogger_StaleCallLoggedIncomplete(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+
+	store := NewFileStore(t.TempDir())
+	sess, err := store.Create()
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	al := NewAuditLogger(bus, store)
+	defer al.Close()
+
+	// A call that never finishes (e.g. its turn was cancelled).
+	stale := events.NewTypedEventWithSession(events.SourceAgent, events.ToolCallPayload{
+		Status: events.ToolStatusStarted, Name: "run_command", CallID: "call_1",
+	}, sess.ID)
+	stale.Timestamp = time.Now().Add(-2 * auditPendingTTL)
+	bus.Publish(stale)
+
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{Status: events.ToolStatusStarted, Name: "read_file", CallID: "call_2"})
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{Status: events.ToolStatusCompleted, Name: "read_file", CallID: "call_2"})
+
+	entries := waitAudit(t, store, sess.ID, 2)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Tool != "run_command" || !e.Incomplete || e.Success || e.Error == "" {
+		t.Errorf("expected the stale call logged as incomplete, got %+v", e)
+	}
+	if e := entries[1]; e.Tool != "read_file" || e.Incomplete || !e.Success {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	al.mu.Lock()
+	n := len(al.pending)
+	al.mu.Unlock()
+	if n != 0 {
+		t.Errorf("expected no pending calls, got %d", n)
+	}
+}
+
+func TestAuditLogger_CloseFlushesPending(t *testing.T) {
+	bus := events.NewBus(64)
+	defer bus.Close()
+
+	store := NewFileStore(t.TempDir())
+	sess, err := store.Create()
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	al := NewAuditLogger(bus, store)
+	publishToolEvent(bus, sess.ID, events.ToolCallPayload{Status: events.ToolStatusStarted, Name: "run_command", CallID: "call_1"})
+	// A sessionless completion, handled after the start, marks it as seen.
+	publishToolEvent(bus, "", events.ToolCallPayload{Status: events.ToolStatusCompleted, Name: "web_fetch"})
+	waitAudit(t, store, "", 1)
+	al.Close()
+
+	entries, err := store.LoadAudit(sess.ID)
+	if err != nil {
+		t.Fatalf("load audit: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Tool != "run_command" || !entries[0].Incomplete {
+		t.Fatalf("expected the in-flight call flushed as incomplete, got %+v", entries)
+	}
+}
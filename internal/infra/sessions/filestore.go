@@ -3,6 +3,7 @@ package sessions
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 
@@ -11,8 +12,12 @@ import (
 )
 
 // FileStore persists sessions as directories with meta.json + messages.jsonl.
+// Sessions of the default project live at the root of the base directory;
+// the others under <baseDir>/<project>/. A root subdirectory without
+// meta.json is a project directory.
 type FileStore struct {
-	ds *dirstore.DirStore
+	ds       *dirstore.DirStore
+	redactor *Redactor // nil = messages persisted verbatim
 }
 
 // NewFileStore creates a FileStore rooted at baseDir.
@@ -20,6 +25,21 @@ func NewFileStore(baseDir string) *FileStore {
 	return &FileStore{ds: dirstore.NewDirStore(baseDir, "session")}
 }
 
+// SetRedactor masks secrets in messages before they are written to disk.
+// Callers keep the original content; only the persisted copy is redacted.
+func (fs *FileStore) SetRedactor(r *Redactor) {
+	fs.ds.Lock()
+	defer fs.ds.Unlock()
+	fs.redactor = r
+}
+
+// Redactor returns the redactor set by SetRedactor (nil when none).
+func (fs *FileStore) Redactor() *Redactor {
+	fs.ds.Lock()
+	defer fs.ds.Unlock()
+	return fs.redactor
+}
+
 // Create initialises a new session directory with meta.json.
 func (fs *FileStore) Create() (*Session, error) {
 	fs.ds.Lock()
@@ -27,7 +47,7 @@ func (fs *FileStore) Create() (*Session, error) {
 
 	now := time.Now()
 	id := names.GenerateID("sess", func(candidate string) bool {
-		_, err := os.Stat(fs.ds.Dir(candidate))
+		_, err := fs.resolve(candidate)
 		return err == nil
 	})
 	s := &Session{
@@ -53,7 +73,7 @@ func (fs *FileStore) Get(ref string) (*Session, error) {
 	fs.ds.RLock()
 	defer fs.ds.RUnlock()
 
-	dir, err := fs.ds.Resolve(ref)
+	dir, err := fs.resolve(ref)
 	if err != nil {
 		return nil, err
 	}
@@ -65,20 +85,20 @@ func (fs *FileStore) Get(ref string) (*Session, error) {
 	return &s, nil
 }
 
-// List returns all sessions sorted by UpdatedAt descending.
+// List returns all sessions, of every project, sorted by UpdatedAt descending.
 func (fs *FileStore) List() ([]*Session, error) {
 	fs.ds.RLock()
 	defer fs.ds.RUnlock()
 
-	dirs, err := fs.ds.ListDirs()
+	dirs, err := fs.sessionDirs()
 	if err != nil {
 		return nil, err
 	}
 
 	var sessions []*Session
-	for _, name := range dirs {
+	for _, dir := range dirs {
 		var s Session
-		if err := fs.ds.ReadMeta(name, &s); err != nil {
+		if err := fs.ds.ReadMeta(dir, &s); err != nil {
 			continue // skip corrupted sessions
 		}
 		sessions = append(sessions, &s)
@@ -91,16 +111,124 @@ func (fs *FileStore) List() ([]*Session, error) {
 	return sessions, nil
 }
 
-// UpdateMeta atomically rewrites a session's meta.json.
+// UpdateMeta atomically rewrites a session's meta.json. When the project
+// changes, the session directory moves to the project's directory. Secrets in
+// the summary are masked in the persisted copy when a redactor is set.
 func (fs *FileStore) UpdateMeta(s *Session) error {
 	fs.ds.Lock()
 	defer fs.ds.Unlock()
 
-	dir, err := fs.ds.Resolve(s.ID)
+	dir, err := fs.resolve(s.ID)
 	if err != nil {
 		return err
 	}
-	return fs.ds.WriteMeta(dir, s)
+
+	s.Project = NormalizeProject(s.Project)
+	if want := filepath.Join(s.Project, filepath.Base(dir)); want != dir {
+		if err := fs.move(dir, want, s.Project); err != nil {
+			return err
+		}
+		dir = want
+	}
+	persisted := *s
+	persisted.Summary = fs.redactor.Redact(s.Summary)
+	return fs.ds.WriteMeta(dir, &persisted)
+}
+
+// SessionDir returns the directory of a session, or where it would be in the
+// default project when it does not exist.
+func (fs *FileStore) SessionDir(ref string) string {
+	fs.ds.RLock()
+	defer fs.ds.RUnlock()
+
+	if dir, err := fs.resolve(ref); err == nil {
+		return fs.ds.Dir(dir)
+	}
+	return fs.ds.Dir(ref)
+}
+
+// move relocates a session directory into the project directory. Caller
+// must hold the write lock.
+func (fs *FileStore) move(from, to, project string) error {
+	if project != "" {
+		if fs.isSessionDir(project) {
+			return fmt.Errorf("project %q collides with a session", project)
+		}
+		if err := fs.ds.EnsureDir(project); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(fs.ds.Dir(from), fs.ds.Dir(to)); err != nil {
+		return fmt.Errorf("move session to project %q: %w", project, err)
+	}
+	return nil
+}
+
+// resolve maps a session reference (see dirstore.DirStore.Resolve) to its
+// directory relative to the base directory: "<id>" in the default project,
+// "<project>/<id>" otherwise. Caller must hold at least an RLock.
+func (fs *FileStore) resolve(ref string) (string, error) {
+	if dir, err := fs.ds.Resolve(ref); err == nil && fs.isSessionDir(dir) {
+		return dir, nil
+	}
+	projects, err := fs.projectDirs()
+	if err != nil {
+		return "", err
+	}
+	for _, project := range projects {
+		ds := dirstore.NewDirStore(fs.ds.Dir(project), "session")
+		if dir, err := ds.Resolve(ref); err == nil {
+			if rel := filepath.Join(project, dir); fs.isSessionDir(rel) {
+				return rel, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("session not found: %s", ref)
+}
+
+// sessionDirs lists every session directory, relative to the base directory.
+func (fs *FileStore) sessionDirs() ([]string, error) {
+	root, err := fs.ds.ListDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, name := range root {
+		if fs.isSessionDir(name) {
+			dirs = append(dirs, name)
+			continue
+		}
+		sub, err := dirstore.NewDirStore(fs.ds.Dir(name), "session").ListDirs()
+		if err != nil {
+			continue
+		}
+		for _, s := range sub {
+			dirs = append(dirs, filepath.Join(name, s))
+		}
+	}
+	return dirs, nil
+}
+
+// projectDirs lists the project directories at the root of the base directory.
+func (fs *FileStore) projectDirs() ([]string, error) {
+	root, err := fs.ds.ListDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, name := range root {
+		if !fs.isSessionDir(name) {
+			projects = append(projects, name)
+		}
+	}
+	return projects, nil
+}
+
+func (fs *FileStore) isSessionDir(dir string) bool {
+	_, err := os.Stat(fs.ds.FilePath(dir, "meta.json"))
+	return err == nil
 }
 
 // Close marks a session as closed.
@@ -108,7 +236,7 @@ func (fs *FileStore) Close(ref string) error {
 	fs.ds.Lock()
 	defer fs.ds.Unlock()
 
-	dir, err := fs.ds.Resolve(ref)
+	dir, err := fs.resolve(ref)
 	if err != nil {
 		return err
 	}
@@ -123,16 +251,19 @@ func (fs *FileStore) Close(ref string) error {
 	return fs.ds.WriteMeta(dir, &s)
 }
 
-// AppendMessage appends a message to the session's JSONL file and updates meta.
+// AppendMessage appends a message to the session's JSONL file and updates
+// meta. Secrets are masked first when a redactor is set.
 func (fs *FileStore) AppendMessage(sessionID string, msg Message) error {
 	fs.ds.Lock()
 	defer fs.ds.Unlock()
 
-	dir, err := fs.ds.Resolve(sessionID)
+	dir, err := fs.resolve(sessionID)
 	if err != nil {
 		return fmt.Errorf("resolve session: %w", err)
 	}
 
+	msg.Content = fs.redactor.Redact(msg.Content)
+
 	if err := fs.ds.AppendJSONL(dir, "messages.jsonl", msg); err != nil {
 		return fmt.Errorf("append message: %w", err)
 	}
@@ -151,9 +282,54 @@ func (fs *FileStore) LoadMessages(sessionID string) ([]Message, error) {
 	fs.ds.RLock()
 	defer fs.ds.RUnlock()
 
-	dir, err := fs.ds.Resolve(sessionID)
+	dir, err := fs.resolve(sessionID)
 	if err != nil {
 		return nil, err
 	}
 	return dirstore.LoadJSONL[Message](fs.ds, dir, "messages.jsonl")
 }
+
+// auditFile is the append-only tool audit log, stored per session directory.
+// Calls outside any session are logged at the root of the sessions directory.
+const auditFile = "audit.jsonl"
+
+// AppendAudit appends an entry to the session's tool audit log. Secrets in
+// the arguments, result and error are masked first when a redactor is set.
+func (fs *FileStore) AppendAudit(sessionID string, entry AuditEntry) error {
+	fs.ds.Lock()
+	defer fs.ds.Unlock()
+
+	dir := ""
+	if sessionID != "" {
+		var err error
+		if dir, err = fs.resolve(sessionID); err != nil {
+			return fmt.Errorf("resolve session: %w", err)
+		}
+	} else if err := fs.ds.EnsureDir(""); err != nil {
+		return err
+	}
+
+	entry.Arguments = fs.redactor.Redact(entry.Arguments)
+	entry.Result = fs.redactor.Redact(entry.Result)
+	entry.Error = fs.redactor.Redact(entry.Error)
+
+	if err := fs.ds.AppendJSONL(dir, auditFile, entry); err != nil {
+		return fmt.Errorf("append audit: %w", err)
+	}
+	return nil
+}
+
+// LoadAudit reads the tool audit log of a session (empty = calls outside any session).
+func (fs *FileStore) LoadAudit(sessionID string) ([]AuditEntry, error) {
+	fs.ds.RLock()
+	defer fs.ds.RUnlock()
+
+	dir := ""
+	if sessionID != "" {
+		var err error
+		if dir, err = fs.resolve(sessionID); err != nil {
+			return nil, err
+		}
+	}
+	return dirstore.LoadJSONL[AuditEntry](fs.ds, dir, auditFile)
+}
@@ -1,6 +1,8 @@
 package sessions
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -173,3 +175,87 @@ func TestLoadMessagesEmpty(t *testing.T) {
 		t.Errorf("expected 0 messages, got %d", len(msgs))
 	}
 }
+
+func TestProjectSessions(t *testing.T) {
+	base := t.TempDir()
+	store := NewFileStore(base)
+
+	flat, err := store.Create()
+	if err != nil {
+		t.Fatalf("Create flat: %v", err)
+	}
+	s, err := store.Create()
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.AppendMessage(s.ID, Message{Role: "user", Content: "hello", Ts: time.Now()}); err != nil {
+		t.Fatalf("AppendMessage: %v", err)
+	}
+
+	// Joining a project moves the session directory under sessions/<project>/.
+	if s, err = store.Get(s.ID); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	s.Project = "Ozzie Chess"
+	if err := store.UpdateMeta(s); err != nil {
+		t.Fatalf("UpdateMeta: %v", err)
+	}
+	if s.Project != "Ozzie-Chess" {
+		t.Errorf("Project = %q, want normalized Ozzie-Chess", s.Project)
+	}
+	if want := filepath.Join(base, "Ozzie-Chess", s.ID); store.SessionDir(s.ID) != want {
+		t.Errorf("SessionDir = %q, want %q", store.SessionDir(s.ID), want)
+	}
+	if _, err := os.Stat(filepath.Join(base, s.ID)); !os.IsNotExist(err) {
+		t.Errorf("flat session dir still present: %v", err)
+	}
+
+	// Lookups follow the session, by ID or by name.
+	msgs, err := store.LoadMessages(strings.TrimPrefix(s.ID, "sess_"))
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("LoadMessages = %v, %v; want 1 message", msgs, err)
+	}
+	got, err := store.Get(s.ID)
+	if err != nil || got.MessageCount != 1 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	list, err := store.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List = %d sessions, %v; want 2", len(list), err)
+	}
+	if p := FilterByProject(list, "ozzie chess"); len(p) != 0 {
+		t.Errorf("project filter is case-sensitive, got %d", len(p))
+	}
+	if p := FilterByProject(list, "Ozzie Chess"); len(p) != 1 || p[0].ID != s.ID {
+		t.Errorf("FilterByProject(Ozzie Chess) = %v", p)
+	}
+	if p := FilterByProject(list, DefaultProject); len(p) != 1 || p[0].ID != flat.ID {
+		t.Errorf("FilterByProject(default) = %v", p)
+	}
+
+	// Back to the default project.
+	got.Project = DefaultProject
+	if err := store.UpdateMeta(got); err != nil {
+		t.Fatalf("UpdateMeta: %v", err)
+	}
+	if want := filepath.Join(base, s.ID); store.SessionDir(s.ID) != want {
+		t.Errorf("SessionDir = %q, want %q", store.SessionDir(s.ID), want)
+	}
+}
+
+func TestProjectFromRootDir(t *testing.T) {
+	tests := map[string]string{
+		"":                     "",
+		"/":                    "",
+		"/home/me/ozzie-chess": "ozzie-chess",
+		"/home/me/My App/":     "My-App",
+		"/srv/default":         "",
+		"/tmp/...":             "",
+	}
+	for in, want := range tests {
+		if got := ProjectFromRootDir(in); got != want {
+			t.Errorf("ProjectFromRootDir(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
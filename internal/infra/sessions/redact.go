@@ -0,0 +1,174 @@
+package sessions
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// RedactedMask replaces secrets in persisted messages.
+const RedactedMask = "[REDACTED]"
+
+// defaultRedactPatterns match common secrets. When a pattern has a capture
+// group, only the first group is masked (e.g. the value of "api_key=...").
+var defaultRedactPatterns = []string{
+	// Private key blocks (PEM, OpenSSH).
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	// AWS access key IDs and secret keys.
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	`(?i)aws_secret_access_key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`,
+	// Authorization headers.
+	`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]{16,}=*)`,
+	// Provider tokens: Anthropic/OpenAI, GitHub, Slack, Google, age.
+	`\bsk-(?:ant-)?[A-Za-z0-9_\-]{20,}`,
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	`\bxox[abprs]-[A-Za-z0-9\-]{10,}`,
+	`\bAIza[0-9A-Za-z_\-]{35}\b`,
+	`AGE-SECRET-KEY-1[0-9A-Z]{58}`,
+	// JSON web tokens.
+	`\beyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`,
+}
+
+// secretAssignmentRe matches assignments to secret-looking keys: api_key=...,
+// "client_secret": "...". Group 1 is a quoted value, group 2 an unquoted one
+// and group 3 an opening parenthesis right after it (a call).
+var secretAssignmentRe = regexp.MustCompile(`(?i)\b[\w-]*(?:api[_-]?key|access[_-]?token|auth[_-]?token|secret|password|passwd)[\w-]*["']?\s*(?::?=|:)\s*(?:["']([^\s"']{8,})["']|([^\s"'(),;=]{8,})(\(?))`)
+
+// codeIdentRe matches a dotted or camelCase identifier without digits, as in
+// "password = cfg.Password" or "secret = defaultSecret".
+var codeIdentRe = regexp.MustCompile(`^[A-Za-z_]+(?:\.[A-Za-z_]+)+$|^[a-z_]+[A-Z][A-Za-z_]*$`)
+
+// High-entropy token detection: long runs of token characters mixing upper
+// case, lower case and digits, with a Shannon entropy above the threshold.
+// Hex strings (hashes, UUIDs) stay below it.
+var entropyTokenRe = regexp.MustCompile(`[A-Za-z0-9+=_\-]{32,}`)
+
+const minSecretEntropy = 4.3 // bits per character
+
+// Redactor masks secrets in text before it is persisted.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	entropy  bool
+}
+
+// NewRedactor creates a Redactor with the built-in secret patterns plus
+// extra Go regexps. When entropy is true, high-entropy tokens are masked too.
+func NewRedactor(extra []string, entropy bool) (*Redactor, error) {
+	r := &Redactor{entropy: entropy}
+	for _, p := range append(append([]string{}, defaultRedactPatterns...), extra...) {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact returns text with every secret replaced by RedactedMask.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, re := range r.patterns {
+		text = maskMatches(re, text)
+	}
+	text = maskAssignments(text)
+	if r.entropy {
+		text = entropyTokenRe.ReplaceAllStringFunc(text, func(tok string) string {
+			if looksLikeSecret(tok) {
+				return RedactedMask
+			}
+			return tok
+		})
+	}
+	return text
+}
+
+// maskMatches masks the first capture group of each match of re, or the
+// whole match when re has no group (or the group did not participate).
+func maskMatches(re *regexp.Regexp, text string) string {
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(RedactedMask)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// maskAssignments masks the values assigned to secret-looking keys. Quoted
+// values are always masked; unquoted ones are kept when they read as code
+// (a call, or an identifier such as cfg.Password), not as a literal.
+func maskAssignments(text string) string {
+	matches := secretAssignmentRe.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2], m[3]
+		if start < 0 {
+			start, end = m[4], m[5]
+			if m[7] > m[6] || codeIdentRe.MatchString(text[start:end]) {
+				continue
+			}
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(RedactedMask)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// looksLikeSecret reports whether tok mixes character classes and has a
+// high Shannon entropy.
+func looksLikeSecret(tok string) bool {
+	var upper, lower, digit bool
+	for _, c := range tok {
+		switch {
+		case unicode.IsUpper(c):
+			upper = true
+		case unicode.IsLower(c):
+			lower = true
+		case unicode.IsDigit(c):
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+	return shannonEntropy(tok) >= minSecretEntropy
+}
+
+// shannonEntropy returns the entropy of s in bits per byte.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var h float64
+	n := float64(len(s))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
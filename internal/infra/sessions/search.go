@@ -0,0 +1,445 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/dohr-michael/ozzie/pkg/memory"
+)
+
+// SearchResult is a session matching a search query.
+type SearchResult struct {
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	Score     float64   `json:"score"`
+	Snippet   string    `json:"snippet,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+const (
+	// searchRecentMessages bounds how many trailing messages of a session are
+	// searched; older history is covered by the session summary.
+	searchRecentMessages = 50
+	// snippetRadius is the number of characters kept around a match.
+	snippetRadius = 60
+	// titleWeight favours matches in the session title.
+	titleWeight = 3
+)
+
+// Search returns the sessions whose title, summary or recent messages contain
+// every term of query (case-insensitive), best matches first.
+func (fs *FileStore) Search(query string) ([]SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	list, err := fs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, s := range list {
+		msgs, err := fs.LoadMessages(s.ID)
+		if err != nil {
+			continue // skip unreadable histories
+		}
+		if r, ok := matchSession(s, msgs, terms); ok {
+			results = append(results, r)
+		}
+	}
+	sortResults(results)
+	return results, nil
+}
+
+// searchFields returns the searchable texts of a session: its summary then
+// its recent messages, most recent last. Tool logs are skipped.
+func searchFields(s *Session, msgs []Message) []string {
+	if len(msgs) > searchRecentMessages {
+		msgs = msgs[len(msgs)-searchRecentMessages:]
+	}
+	fields := make([]string, 0, len(msgs)+1)
+	if s.Summary != "" {
+		fields = append(fields, s.Summary)
+	}
+	for _, m := range msgs {
+		if m.Role == RoleToolLog || m.Content == "" {
+			continue
+		}
+		fields = append(fields, m.Content)
+	}
+	return fields
+}
+
+// matchSession scores a session against lowercased query terms. Every term
+// must appear in the title or in one of the searched fields.
+func matchSession(s *Session, msgs []Message, terms []string) (SearchResult, bool) {
+	title := strings.ToLower(s.Title)
+	fields := searchFields(s, msgs)
+	lower := make([]string, len(fields))
+	for i, f := range fields {
+		lower[i] = strings.ToLower(f)
+	}
+
+	score := 0
+	for _, term := range terms {
+		hits := titleWeight * strings.Count(title, term)
+		for _, f := range lower {
+			hits += strings.Count(f, term)
+		}
+		if hits == 0 {
+			return SearchResult{}, false
+		}
+		score += hits
+	}
+
+	// Snippet from the most recent field holding the whole query, else the
+	// first term.
+	phrase := strings.Join(terms, " ")
+	snippet := ""
+	for _, needle := range []string{phrase, terms[0]} {
+		for i := len(lower) - 1; i >= 0 && snippet == ""; i-- {
+			if idx := strings.Index(lower[i], needle); idx >= 0 {
+				snippet = makeSnippet(fields[i], idx, len(needle))
+			}
+		}
+		if snippet != "" {
+			break
+		}
+	}
+
+	return newResult(s, float64(score), snippet), true
+}
+
+// makeSnippet extracts the text around text[idx:idx+n] on a single line.
+func makeSnippet(text string, idx, n int) string {
+	start := max(idx-snippetRadius, 0)
+	end := min(idx+n+snippetRadius, len(text))
+	// Do not cut multi-byte runes.
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
+
+func newResult(s *Session, score float64, snippet string) SearchResult {
+	return SearchResult{
+		SessionID: s.ID,
+		Title:     s.Title,
+		Project:   s.ProjectName(),
+		Score:     score,
+		Snippet:   snippet,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// sortResults orders results by score, most recently updated first on ties.
+func sortResults(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].UpdatedAt.After(results[j].UpdatedAt)
+	})
+}
+
+// minSemanticSimilarity is the cosine similarity below which a session is not
+// considered related to the query.
+const minSemanticSimilarity = 0.35
+
+const (
+	// maxSessionDocRunes bounds the text embedded for a session: its title,
+	// summary and as many of its most recent messages as fit.
+	maxSessionDocRunes = 8000
+	// embedBatchSize bounds the sessions embedded per EmbedStrings call.
+	embedBatchSize = 16
+)
+
+// VectorCache persists session embeddings across restarts (implemented by
+// memory.SQLiteVectorStore).
+type VectorCache interface {
+	LoadSessionVectors(ctx context.Context) (map[string]memory.StoredVector, error)
+	SaveSessionVector(ctx context.Context, id, version string, vec []float64) error
+}
+
+// SemanticSearcher ranks sessions by embedding similarity with the query,
+// on top of the store's substring search. Session embeddings are cached in
+// memory and recomputed when a session is updated; with a VectorCache they
+// are also persisted, so a restart does not re-embed every session.
+type SemanticSearcher struct {
+	store Store
+
+	mu       sync.Mutex
+	embedder embedding.Embedder // nil = substring search only
+	vectors  VectorCache        // nil = in-memory cache only
+	loaded   bool               // persisted vectors read into cache
+	cache    map[string]sessionVector
+}
+
+type sessionVector struct {
+	version string // session UpdatedAt the vector was computed from
+	vec     []float64
+}
+
+// NewSemanticSearcher creates a searcher embedding sessions with embedder.
+// vectors may be nil, in which case embeddings are only cached in memory.
+func NewSemanticSearcher(store Store, embedder embedding.Embedder, vectors VectorCache) *SemanticSearcher {
+	return &SemanticSearcher{
+		store:    store,
+		embedder: embedder,
+		vectors:  vectors,
+		cache:    make(map[string]sessionVector),
+	}
+}
+
+// Swap replaces the embedder and vector cache, e.g. after the embedding
+// config changed. Cached vectors are dropped: they belong to the old model.
+// A nil embedder disables semantic ranking.
+func (ss *SemanticSearcher) Swap(embedder embedding.Embedder, vectors VectorCache) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.embedder = embedder
+	ss.vectors = vectors
+	ss.loaded = false
+	ss.cache = make(map[string]sessionVector)
+}
+
+// Search returns at most limit sessions related to query (0 = no limit).
+// Substring matches rank first, followed by semantically similar sessions.
+// If embedding fails, only substring matches are returned.
+func (ss *SemanticSearcher) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	results, err := ss.store.Search(query)
+	if err != nil {
+		return nil, err
+	}
+
+	semantic, err := ss.semantic(ctx, query)
+	if err != nil {
+		slog.Warn("semantic session search failed, using substring match", "error", err)
+		semantic = nil
+	}
+
+	// Substring matches outrank any similarity (which is at most 1).
+	seen := make(map[string]bool, len(results))
+	for i := range results {
+		seen[results[i].SessionID] = true
+		results[i].Score += 1
+	}
+	for _, r := range semantic {
+		if !seen[r.SessionID] {
+			results = append(results, r)
+		}
+	}
+	sortResults(results)
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// semantic returns the sessions whose embedding is similar enough to the query.
+func (ss *SemanticSearcher) semantic(ctx context.Context, query string) ([]SearchResult, error) {
+	ss.mu.Lock()
+	embedder, vectors := ss.embedder, ss.vectors
+	ss.mu.Unlock()
+	if embedder == nil {
+		return nil, nil
+	}
+
+	list, err := ss.store.List()
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.refresh(ctx, embedder, vectors, list); err != nil {
+		return nil, err
+	}
+
+	qv, err := embedder.EmbedStrings(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(qv) != 1 {
+		return nil, fmt.Errorf("embed query: got %d vectors", len(qv))
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	var results []SearchResult
+	for _, s := range list {
+		sv, ok := ss.cache[s.ID]
+		if !ok {
+			continue
+		}
+		sim := cosine(qv[0], sv.vec)
+		if sim < minSemanticSimilarity {
+			continue
+		}
+		snippet := s.Summary
+		if snippet == "" {
+			snippet = s.Title
+		}
+		if snippet != "" {
+			snippet = makeSnippet(snippet, 0, snippetRadius)
+		}
+		results = append(results, newResult(s, sim, snippet))
+	}
+	return results, nil
+}
+
+// refresh embeds the sessions that are new or updated since last embedded,
+// in batches, after loading the persisted vectors on first use.
+func (ss *SemanticSearcher) refresh(ctx context.Context, embedder embedding.Embedder, vectors VectorCache, list []*Session) error {
+	ss.mu.Lock()
+	if !ss.loaded && vectors != nil {
+		stored, err := vectors.LoadSessionVectors(ctx)
+		if err != nil {
+			slog.Warn("load session embeddings", "error", err)
+		}
+		for id, v := range stored {
+			vec := make([]float64, len(v.Vector))
+			for i, f := range v.Vector {
+				vec[i] = float64(f)
+			}
+			ss.cache[id] = sessionVector{version: v.Version, vec: vec}
+		}
+	}
+	ss.loaded = true
+	var stale []*Session
+	for _, s := range list {
+		if sv, ok := ss.cache[s.ID]; !ok || sv.version != sessionVersion(s) {
+			stale = append(stale, s)
+		}
+	}
+	ss.mu.Unlock()
+
+	for len(stale) > 0 {
+		n := min(len(stale), embedBatchSize)
+		if err := ss.embedBatch(ctx, embedder, vectors, stale[:n]); err != nil {
+			return err
+		}
+		stale = stale[n:]
+	}
+	return nil
+}
+
+// embedBatch embeds a batch of sessions and caches their vectors.
+func (ss *SemanticSearcher) embedBatch(ctx context.Context, embedder embedding.Embedder, vectors VectorCache, batch []*Session) error {
+	var (
+		docs    []string
+		pending []*Session
+	)
+	for _, s := range batch {
+		msgs, err := ss.store.LoadMessages(s.ID)
+		if err != nil {
+			continue
+		}
+		doc := sessionDocument(s, msgs)
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		docs = append(docs, doc)
+		pending = append(pending, s)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	vecs, err := embedder.EmbedStrings(ctx, docs)
+	if err != nil {
+		return fmt.Errorf("embed sessions: %w", err)
+	}
+	if len(vecs) != len(docs) {
+		return fmt.Errorf("embed sessions: got %d vectors for %d sessions", len(vecs), len(docs))
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for i, s := range pending {
+		version := sessionVersion(s)
+		ss.cache[s.ID] = sessionVector{version: version, vec: vecs[i]}
+		if vectors != nil {
+			if err := vectors.SaveSessionVector(ctx, s.ID, version, vecs[i]); err != nil {
+				slog.Warn("save session embedding", "session_id", s.ID, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// sessionVersion identifies the state of a session a vector was computed from.
+func sessionVersion(s *Session) string {
+	return s.UpdatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// sessionDocument returns the text embedded for a session: its title and
+// summary, then its most recent messages, within maxSessionDocRunes.
+func sessionDocument(s *Session, msgs []Message) string {
+	fields := searchFields(s, msgs)
+	head := []string{s.Title}
+	if s.Summary != "" {
+		head = append(head, fields[0])
+		fields = fields[1:]
+	}
+
+	budget := maxSessionDocRunes
+	for i, h := range head {
+		if r := []rune(h); len(r) > budget {
+			head[i] = string(r[:budget])
+		}
+		budget -= utf8.RuneCountInString(head[i]) + 1
+	}
+	// Newest messages first until the budget is spent, kept in order.
+	first := len(fields)
+	for first > 0 && budget > 0 {
+		n := utf8.RuneCountInString(fields[first-1]) + 1
+		if n > budget {
+			break
+		}
+		budget -= n
+		first--
+	}
+	return strings.Join(append(head, fields[first:]...), "\n")
+}
+
+// cosine computes the cosine similarity of two vectors (0 if either is null
+// or their dimensions differ).
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
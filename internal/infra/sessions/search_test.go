@@ -0,0 +1,223 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"github.com/dohr-michael/ozzie/pkg/memory"
+)
+
+// newSearchSession creates a session with a title and messages.
+func newSearchSession(t *testing.T, store *FileStore, title string, contents ...string) *Session {
+	t.Helper()
+	s, err := store.Create()
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, c := range contents {
+		if err := store.AppendMessage(s.ID, Message{Role: "user", Content: c, Ts: time.Now()}); err != nil {
+			t.Fatalf("AppendMessage: %v", err)
+		}
+	}
+	s, err = store.Get(s.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	s.Title = title
+	if err := store.UpdateMeta(s); err != nil {
+		t.Fatalf("UpdateMeta: %v", err)
+	}
+	return s
+}
+
+func TestFileStoreSearch(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ws := newSearchSession(t, store, "Debug WS reconnect",
+		"the client never retries the WS reconnect after a 1006 close")
+	other := newSearchSession(t, store, "Groceries", "buy milk", "the reconnect of the bike chain")
+	if err := store.AppendMessage(other.ID, Message{Role: RoleToolLog, Content: "ws probe"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"all terms required", "ws reconnect", []string{ws.ID}},
+		{"title weighs more", "reconnect", []string{ws.ID, other.ID}},
+		{"case-insensitive", "MILK", []string{other.ID}},
+		{"tool logs skipped", "probe", nil},
+		{"no match", "kubernetes", nil},
+		{"empty query", "  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.SessionID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	results, _ := store.Search("ws reconnect")
+	if len(results) == 0 || !strings.Contains(results[0].Snippet, "WS reconnect after a 1006") {
+		t.Errorf("snippet = %+v", results)
+	}
+}
+
+func TestMakeSnippet(t *testing.T) {
+	text := strings.Repeat("a", 100) + " needle " + strings.Repeat("b", 100)
+	got := makeSnippet(text, strings.Index(text, "needle"), len("needle"))
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "needle") {
+		t.Errorf("snippet = %q", got)
+	}
+	if got := makeSnippet("short\nneedle", 6, 6); got != "short needle" {
+		t.Errorf("snippet = %q, want whole text on one line", got)
+	}
+}
+
+// keywordEmbedder embeds texts as keyword presence vectors.
+type keywordEmbedder struct {
+	keywords []string
+	err      error
+	batches  []int // size of each EmbedStrings call
+}
+
+func (e *keywordEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.batches = append(e.batches, len(texts))
+	out := make([][]float64, len(texts))
+	for i, text := range texts {
+		vec := make([]float64, len(e.keywords))
+		for j, k := range e.keywords {
+			if strings.Contains(strings.ToLower(text), k) {
+				vec[j] = 1
+			}
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func TestSemanticSearcher(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	exact := newSearchSession(t, store, "Reconnect", "websocket reconnect loop")
+	related := newSearchSession(t, store, "Network", "the websocket drops every minute")
+	newSearchSession(t, store, "Cooking", "pasta recipe")
+
+	embedder := &keywordEmbedder{keywords: []string{"websocket", "reconnect", "pasta"}}
+	ss := NewSemanticSearcher(store, embedder, nil)
+
+	results, err := ss.Search(context.Background(), "reconnect websocket", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].SessionID != exact.ID || results[1].SessionID != related.ID {
+		t.Fatalf("results = %+v, want exact then related match", results)
+	}
+
+	// Limit applies after ranking.
+	results, _ = ss.Search(context.Background(), "reconnect websocket", 1)
+	if len(results) != 1 || results[0].SessionID != exact.ID {
+		t.Errorf("limited results = %+v", results)
+	}
+
+	// Embedding failures fall back to substring matches.
+	embedder.err = errors.New("embedder down")
+	ss = NewSemanticSearcher(store, embedder, nil)
+	results, err = ss.Search(context.Background(), "reconnect websocket", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].SessionID != exact.ID {
+		t.Errorf("fallback results = %+v", results)
+	}
+}
+
+// mapVectorCache is an in-memory VectorCache.
+type mapVectorCache map[string]memory.StoredVector
+
+func (c mapVectorCache) LoadSessionVectors(context.Context) (map[string]memory.StoredVector, error) {
+	return maps.Clone(c), nil
+}
+
+func (c mapVectorCache) SaveSessionVector(_ context.Context, id, version string, vec []float64) error {
+	v := make([]float32, len(vec))
+	for i, f := range vec {
+		v[i] = float32(f)
+	}
+	c[id] = memory.StoredVector{Version: version, Vector: v}
+	return nil
+}
+
+func TestSemanticSearcher_BatchesAndPersists(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	for i := range embedBatchSize + 1 {
+		newSearchSession(t, store, fmt.Sprintf("Session %d", i), "websocket drops")
+	}
+	ctx := context.Background()
+	cache := mapVectorCache{}
+
+	embedder := &keywordEmbedder{keywords: []string{"websocket", "pasta"}}
+	if _, err := NewSemanticSearcher(store, embedder, cache).Search(ctx, "websocket", 0); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	// Two session batches, then the query.
+	if want := []int{embedBatchSize, 1, 1}; !slices.Equal(embedder.batches, want) {
+		t.Errorf("EmbedStrings batches = %v, want %v", embedder.batches, want)
+	}
+	if len(cache) != embedBatchSize+1 {
+		t.Fatalf("persisted %d vectors, want %d", len(cache), embedBatchSize+1)
+	}
+
+	// A new searcher (e.g. after a restart) reuses the persisted vectors.
+	embedder = &keywordEmbedder{keywords: []string{"websocket", "pasta"}}
+	results, err := NewSemanticSearcher(store, embedder, cache).Search(ctx, "pasta websocket", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if !slices.Equal(embedder.batches, []int{1}) {
+		t.Errorf("EmbedStrings batches = %v, want the query only", embedder.batches)
+	}
+	if len(results) != embedBatchSize+1 {
+		t.Errorf("got %d results from persisted vectors, want %d", len(results), embedBatchSize+1)
+	}
+}
+
+func TestSessionDocument(t *testing.T) {
+	s := &Session{Title: "Deploy", Summary: "deploying the app"}
+	old := Message{Role: "user", Content: "old " + strings.Repeat("x", maxSessionDocRunes)}
+	recent := Message{Role: "assistant", Content: "recent message"}
+
+	doc := sessionDocument(s, []Message{old, recent})
+	if n := utf8.RuneCountInString(doc); n > maxSessionDocRunes {
+		t.Errorf("document has %d runes, want at most %d", n, maxSessionDocRunes)
+	}
+	if want := "Deploy\ndeploying the app\nrecent message"; doc != want {
+		t.Errorf("document = %q, want %q", doc, want)
+	}
+
+	s.Summary = strings.Repeat("é", 2*maxSessionDocRunes)
+	if n := utf8.RuneCountInString(sessionDocument(s, []Message{recent})); n > maxSessionDocRunes {
+		t.Errorf("document with a long summary has %d runes", n)
+	}
+}
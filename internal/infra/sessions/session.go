@@ -2,7 +2,11 @@
 package sessions
 
 import (
+	"errors"
+	"path/filepath"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/dohr-michael/ozzie/internal/core/brain"
 	"github.com/dohr-michael/ozzie/internal/core/events"
@@ -35,6 +39,7 @@ type Session struct {
 	MessageCount    int                               `json:"message_count"`
 	TokenUsage      TokenUsage                        `json:"token_usage"`
 	RootDir         string                            `json:"root_dir,omitempty"`
+	Confined        bool                              `json:"confined,omitempty"` // jail filesystem/exec tools to RootDir
 	Language        string                            `json:"language,omitempty"`
 	Summary         string                            `json:"summary,omitempty"`       // compressed context from older messages
 	SummaryUpTo     int                               `json:"summary_up_to,omitempty"` // index (exclusive) of last summarized message
@@ -42,6 +47,75 @@ type Session struct {
 	ApprovedTools   []string                          `json:"approved_tools,omitempty"`   // dangerous tools approved for this session
 	ToolConstraints map[string]*events.ToolConstraint `json:"tool_constraints,omitempty"` // per-tool argument constraints
 	PolicyName      string                            `json:"policy_name,omitempty"`      // policy applied to this session
+	Vars            map[string]string                 `json:"vars,omitempty"`             // agent scratch variables (set_var / get_var)
+	Persona         string                            `json:"persona,omitempty"`          // per-session instruction override (set_persona)
+	Project         string                            `json:"project,omitempty"`          // empty = DefaultProject
+}
+
+// ErrConfinedWithoutRoot rejects a confined session that has no RootDir to be
+// confined to: it would otherwise run unjailed.
+var ErrConfinedWithoutRoot = errors.New("confined session requires a root_dir")
+
+// DefaultProject names the project of sessions without one. Its sessions are
+// stored flat at the root of the sessions directory.
+const DefaultProject = "default"
+
+// ProjectName returns the session's project, DefaultProject when unset.
+func (s *Session) ProjectName() string {
+	if s.Project == "" {
+		return DefaultProject
+	}
+	return s.Project
+}
+
+// NormalizeProject turns a project name into a directory-safe one: runs of
+// characters other than letters, digits, '-', '_' and '.' become '-'.
+// DefaultProject and names without any letter or digit normalize to "".
+func NormalizeProject(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.':
+			b.WriteRune(r)
+			dash = false
+		case !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	p := strings.Trim(b.String(), "-.")
+	if p == DefaultProject || !strings.ContainsFunc(p, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) {
+		return ""
+	}
+	return p
+}
+
+// ProjectFromRootDir derives a project name from a session's working
+// directory: its base name, normalized. Empty for no or a root directory.
+func ProjectFromRootDir(rootDir string) string {
+	if rootDir == "" {
+		return ""
+	}
+	return NormalizeProject(filepath.Base(filepath.Clean(rootDir)))
+}
+
+// FilterByProject keeps the sessions of the given project (DefaultProject
+// for sessions without one). An empty project keeps every session.
+func FilterByProject(list []*Session, project string) []*Session {
+	if project == "" {
+		return list
+	}
+	want := NormalizeProject(project)
+	var out []*Session
+	for _, s := range list {
+		if s.Project == want {
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
 // Message is a single turn in a conversation, serializable to JSONL.
@@ -60,4 +134,7 @@ type Store interface {
 	Close(id string) error
 	AppendMessage(sessionID string, msg Message) error
 	LoadMessages(sessionID string) ([]Message, error)
+	AppendAudit(sessionID string, entry AuditEntry) error
+	LoadAudit(sessionID string) ([]AuditEntry, error)
+	Search(query string) ([]SearchResult, error)
 }
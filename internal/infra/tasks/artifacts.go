@@ -0,0 +1,120 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// RegisterArtifactTool is the tool sub-agents use to register task artifacts.
+// It is offered to every task agent in addition to its configured tools.
+const RegisterArtifactTool = "register_artifact"
+
+const (
+	artifactsDir  = "artifacts"
+	artifactsMeta = "artifacts.json"
+)
+
+// ValidateArtifactName checks that name is a plain file name, usable as-is
+// inside the task's artifacts directory.
+func ValidateArtifactName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid artifact name %q: must be a plain file name", name)
+	}
+	return nil
+}
+
+// WriteArtifact stores the artifact content under artifacts/<name> in the task
+// directory and records its metadata, replacing an artifact of the same name.
+func (fs *FileStore) WriteArtifact(taskID string, a Artifact, content []byte) error {
+	if err := ValidateArtifactName(a.Name); err != nil {
+		return err
+	}
+
+	fs.ds.Lock()
+	defer fs.ds.Unlock()
+
+	dir, err := fs.ds.Resolve(taskID)
+	if err != nil {
+		return err
+	}
+	if err := fs.ds.EnsureDir(filepath.Join(dir, artifactsDir)); err != nil {
+		return err
+	}
+
+	a.Path = path.Join(artifactsDir, a.Name)
+	a.Size = int64(len(content))
+	if err := fs.ds.WriteFileAtomic(dir, a.Path, content); err != nil {
+		return err
+	}
+
+	list, err := fs.loadArtifacts(dir)
+	if err != nil {
+		return err
+	}
+	list = slices.DeleteFunc(list, func(existing Artifact) bool { return existing.Name == a.Name })
+	list = append(list, a)
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal artifacts: %w", err)
+	}
+	return fs.ds.WriteFileAtomic(dir, artifactsMeta, data)
+}
+
+// ListArtifacts returns the artifacts registered for a task, oldest first.
+func (fs *FileStore) ListArtifacts(taskID string) ([]Artifact, error) {
+	fs.ds.RLock()
+	defer fs.ds.RUnlock()
+
+	dir, err := fs.ds.Resolve(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return fs.loadArtifacts(dir)
+}
+
+// ReadArtifact returns an artifact's metadata and content.
+func (fs *FileStore) ReadArtifact(taskID, name string) (*Artifact, []byte, error) {
+	fs.ds.RLock()
+	defer fs.ds.RUnlock()
+
+	dir, err := fs.ds.Resolve(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	list, err := fs.loadArtifacts(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	i := slices.IndexFunc(list, func(a Artifact) bool { return a.Name == name })
+	if i < 0 {
+		return nil, nil, fmt.Errorf("artifact %q not found for task %s", name, taskID)
+	}
+
+	content, err := fs.ds.ReadFileContent(dir, list[i].Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if content == nil {
+		return nil, nil, fmt.Errorf("artifact %q of task %s: content missing", name, taskID)
+	}
+	return &list[i], content, nil
+}
+
+// loadArtifacts reads the artifact metadata of a task directory.
+// Caller must hold at least an RLock.
+func (fs *FileStore) loadArtifacts(dir string) ([]Artifact, error) {
+	data, err := fs.ds.ReadFileContent(dir, artifactsMeta)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var list []Artifact
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse artifacts: %w", err)
+	}
+	return list, nil
+}
@@ -1,7 +1,9 @@
 package tasks
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 
@@ -9,14 +11,37 @@ import (
 	"github.com/dohr-michael/ozzie/pkg/names"
 )
 
-// FileStore persists tasks as directories with meta.json + checkpoints.jsonl + output.md.
+// DefaultOutputFile is the name of the task output file.
+const DefaultOutputFile = "output.md"
+
+// FileStore persists tasks as directories with meta.json + checkpoints.jsonl + output.md,
+// plus registered artifacts (artifacts.json + artifacts/).
 type FileStore struct {
-	ds *dirstore.DirStore
+	ds         *dirstore.DirStore
+	outputFile string
 }
 
 // NewFileStore creates a FileStore rooted at baseDir.
 func NewFileStore(baseDir string) *FileStore {
-	return &FileStore{ds: dirstore.NewDirStore(baseDir, "task")}
+	return &FileStore{ds: dirstore.NewDirStore(baseDir, "task"), outputFile: DefaultOutputFile}
+}
+
+// SetOutputFile sets the name of the output file written in new task
+// directories. Tasks record the name in their result, so outputs written
+// under a previous name stay readable.
+func (fs *FileStore) SetOutputFile(name string) {
+	fs.ds.Lock()
+	defer fs.ds.Unlock()
+	if name != "" {
+		fs.outputFile = name
+	}
+}
+
+// OutputFile returns the name of the output file written in task directories.
+func (fs *FileStore) OutputFile() string {
+	fs.ds.RLock()
+	defer fs.ds.RUnlock()
+	return fs.outputFile
 }
 
 // Create persists a new task to disk.
@@ -86,6 +111,9 @@ func (fs *FileStore) List(filter ListFilter) ([]*Task, error) {
 		if filter.ParentID != "" && t.ParentTaskID != filter.ParentID {
 			continue
 		}
+		if filter.IdempotencyKey != "" && t.IdempotencyKey != filter.IdempotencyKey {
+			continue
+		}
 
 		tasks = append(tasks, &t)
 	}
@@ -157,7 +185,34 @@ func (fs *FileStore) WriteOutput(taskID string, content string) error {
 	if err != nil {
 		return err
 	}
-	return fs.ds.WriteFileAtomic(dir, "output.md", []byte(content))
+	return fs.ds.WriteFileAtomic(dir, fs.outputFileOf(dir), []byte(content))
+}
+
+// DeleteOutput removes the task output file, keeping the task itself.
+func (fs *FileStore) DeleteOutput(taskID string) error {
+	fs.ds.Lock()
+	defer fs.ds.Unlock()
+
+	dir, err := fs.ds.Resolve(taskID)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(fs.ds.FilePath(dir, fs.outputFileOf(dir)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// outputFileOf returns the output file name recorded in the task result,
+// falling back to the configured name.
+// Caller must hold the lock.
+func (fs *FileStore) outputFileOf(dir string) string {
+	var t Task
+	if err := fs.ds.ReadMeta(dir, &t); err == nil && t.Result != nil && t.Result.OutputPath != "" {
+		return filepath.Base(t.Result.OutputPath)
+	}
+	return fs.outputFile
 }
 
 // ReadOutput reads the task output file.
@@ -169,7 +224,7 @@ func (fs *FileStore) ReadOutput(taskID string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	data, err := fs.ds.ReadFileContent(dir, "output.md")
+	data, err := fs.ds.ReadFileContent(dir, fs.outputFileOf(dir))
 	if err != nil {
 		return "", err
 	}
@@ -195,3 +195,44 @@ func TestFileStoreLoadCheckpointsEmpty(t *testing.T) {
 		t.Fatal("expected error for LoadCheckpoints of nonexistent task")
 	}
 }
+
+func TestFileStoreArtifacts(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	task := &Task{Title: "gen", Status: TaskRunning}
+	if err := store.Create(task); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if list, err := store.ListArtifacts(task.ID); err != nil || len(list) != 0 {
+		t.Fatalf("ListArtifacts on new task = %v, %v", list, err)
+	}
+
+	if err := store.WriteArtifact(task.ID, Artifact{Name: "main.go", Description: "entry point"}, []byte("package main\n")); err != nil {
+		t.Fatalf("WriteArtifact: %v", err)
+	}
+	if err := store.WriteArtifact(task.ID, Artifact{Name: "README.md"}, []byte("# gen\n")); err != nil {
+		t.Fatalf("WriteArtifact: %v", err)
+	}
+	if err := store.WriteArtifact(task.ID, Artifact{Name: "../meta.json"}, []byte("{}")); err == nil {
+		t.Error("expected error for path-like artifact name")
+	}
+
+	list, err := store.ListArtifacts(task.ID)
+	if err != nil {
+		t.Fatalf("ListArtifacts: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "main.go" || list[0].Path != "artifacts/main.go" || list[0].Size != 13 {
+		t.Fatalf("unexpected artifacts: %+v", list)
+	}
+
+	a, content, err := store.ReadArtifact(task.ID, "README.md")
+	if err != nil {
+		t.Fatalf("ReadArtifact: %v", err)
+	}
+	if a.Name != "README.md" || string(content) != "# gen\n" {
+		t.Errorf("ReadArtifact = %+v, %q", a, content)
+	}
+	if _, _, err := store.ReadArtifact(task.ID, "nope"); err == nil {
+		t.Error("expected error for unknown artifact")
+	}
+}
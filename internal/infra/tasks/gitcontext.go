@@ -0,0 +1,86 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitContextTimeout bounds the git reads done while building a task instruction.
+const gitContextTimeout = 2 * time.Second
+
+// formatGitContext summarizes the repository containing cfg.WorkDir (branch,
+// dirty file count, last commit) when cfg.GitContext is set. It returns ""
+// when the flag is off, git is unavailable or the directory is not in a repo.
+func formatGitContext(ctx context.Context, cfg TaskConfig) string {
+	if !cfg.GitContext || cfg.WorkDir == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitContextTimeout)
+	defer cancel()
+
+	status, err := readGit(ctx, cfg.WorkDir, "status", "--porcelain=v1", "--branch")
+	if err != nil {
+		return ""
+	}
+	branch, dirty := parseGitStatus(status)
+
+	var b strings.Builder
+	b.WriteString("\n\n## Git Repository\n")
+	fmt.Fprintf(&b, "Branch: %s\n", branch)
+	if dirty == 0 {
+		b.WriteString("Working tree: clean\n")
+	} else {
+		fmt.Fprintf(&b, "Working tree: %d changed file(s)\n", dirty)
+	}
+	if last, err := readGit(ctx, cfg.WorkDir, "log", "-1", "--format=%h %s (%cr)"); err == nil {
+		if last = strings.TrimSpace(last); last != "" {
+			fmt.Fprintf(&b, "Last commit: %s\n", last)
+		}
+	}
+	return b.String()
+}
+
+// readGit runs a read-only git command in dir and returns its stdout.
+func readGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	// Don't take index.lock for the stat refresh: a task's own git commands may run concurrently.
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// parseGitStatus extracts the branch and the number of changed files from
+// `git status --porcelain=v1 --branch` output.
+func parseGitStatus(status string) (branch string, dirty int) {
+	branch = "unknown"
+	for _, line := range strings.Split(status, "\n") {
+		header, ok := strings.CutPrefix(line, "## ")
+		switch {
+		case ok:
+			branch = parseGitBranch(header)
+		case strings.TrimSpace(line) != "":
+			dirty++
+		}
+	}
+	return branch, dirty
+}
+
+// parseGitBranch reads the branch from a status header such as
+// "main...origin/main [ahead 1]", "No commits yet on main" or "HEAD (no branch)".
+func parseGitBranch(header string) string {
+	if rest, ok := strings.CutPrefix(header, "No commits yet on "); ok {
+		return rest
+	}
+	if strings.HasPrefix(header, "HEAD (no branch)") {
+		return "detached HEAD"
+	}
+	if i := strings.Index(header, "..."); i >= 0 {
+		return header[:i]
+	}
+	branch, _, _ := strings.Cut(header, " ")
+	return branch
+}
@@ -0,0 +1,70 @@
+package tasks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGitStatus(t *testing.T) {
+	tests := []struct {
+		status     string
+		wantBranch string
+		wantDirty  int
+	}{
+		{"## main...origin/main [ahead 1]\n M a.go\n?? b.go\n", "main", 2},
+		{"## feature\n", "feature", 0},
+		{"## No commits yet on main\n?? README.md\n", "main", 1},
+		{"## HEAD (no branch)\n", "detached HEAD", 0},
+	}
+	for _, tt := range tests {
+		branch, dirty := parseGitStatus(tt.status)
+		if branch != tt.wantBranch || dirty != tt.wantDirty {
+			t.Errorf("parseGitStatus(%q) = (%q, %d), want (%q, %d)", tt.status, branch, dirty, tt.wantBranch, tt.wantDirty)
+		}
+	}
+}
+
+func TestFormatGitContext_Disabled(t *testing.T) {
+	if got := formatGitContext(t.Context(), TaskConfig{WorkDir: t.TempDir()}); got != "" {
+		t.Errorf("expected no git context without the flag, got %q", got)
+	}
+}
+
+func TestFormatGitContext_NotARepo(t *testing.T) {
+	if got := formatGitContext(t.Context(), TaskConfig{WorkDir: t.TempDir(), GitContext: true}); got != "" {
+		t.Errorf("expected no git context outside a repo, got %q", got)
+	}
+}
+
+func TestFormatGitContext_Repo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "trunk")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "a.txt")
+	git("commit", "-q", "-m", "initial commit")
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := formatGitContext(t.Context(), TaskConfig{WorkDir: dir, GitContext: true})
+	for _, want := range []string{"## Git Repository", "Branch: trunk", "1 changed file(s)", "initial commit"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in %q", want, got)
+		}
+	}
+}
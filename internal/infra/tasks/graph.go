@@ -0,0 +1,145 @@
+package tasks
+
+import "slices"
+
+// Edge kinds of a task graph.
+const (
+	EdgeParent    = "parent"     // From is the parent task of To
+	EdgeDependsOn = "depends_on" // To depends on From (From must complete first)
+)
+
+// GraphNode is a task in a session's task graph.
+type GraphNode struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title,omitempty"`
+	Status   TaskStatus `json:"status,omitempty"`
+	External bool       `json:"external,omitempty"` // referenced task from another session
+	Missing  bool       `json:"missing,omitempty"`  // referenced task not found in the store
+	// WaitingOn lists the dependencies of a pending task that have not completed.
+	WaitingOn []string `json:"waiting_on,omitempty"`
+	InCycle   bool     `json:"in_cycle,omitempty"`
+}
+
+// GraphEdge links two tasks of a graph.
+type GraphEdge struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Kind    string `json:"kind"` // EdgeParent or EdgeDependsOn
+	InCycle bool   `json:"in_cycle,omitempty"`
+}
+
+// Graph is the parent-child and dependency DAG of a session's tasks.
+// Cycles, which make a DAG invalid, are reported rather than rejected.
+type Graph struct {
+	SessionID string      `json:"session_id"`
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+	Cycles    [][]string  `json:"cycles,omitempty"`
+}
+
+// BuildGraph builds the task graph of a session from store.List. Tasks of
+// other sessions referenced as parent or dependency are included as external
+// nodes; references that no longer resolve are included as missing nodes.
+func BuildGraph(store Store, sessionID string) (*Graph, error) {
+	list, err := store.List(ListFilter{SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{SessionID: sessionID, Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	index := make(map[string]int, len(list))
+	for _, t := range list {
+		index[t.ID] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, GraphNode{ID: t.ID, Title: t.Title, Status: t.Status})
+	}
+
+	// resolve returns the node of a referenced task, adding it when it lies
+	// outside the session.
+	resolve := func(id string) *GraphNode {
+		if i, ok := index[id]; ok {
+			return &g.Nodes[i]
+		}
+		n := GraphNode{ID: id, Missing: true}
+		if t, err := store.Get(id); err == nil {
+			n = GraphNode{ID: t.ID, Title: t.Title, Status: t.Status, External: true}
+		}
+		index[id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, n)
+		return &g.Nodes[len(g.Nodes)-1]
+	}
+
+	for _, t := range list {
+		if t.ParentTaskID != "" {
+			resolve(t.ParentTaskID)
+			g.Edges = append(g.Edges, GraphEdge{From: t.ParentTaskID, To: t.ID, Kind: EdgeParent})
+		}
+		var waiting []string
+		for _, dep := range t.DependsOn {
+			if dep == "" {
+				continue
+			}
+			if d := resolve(dep); d.Status != TaskCompleted {
+				waiting = append(waiting, dep)
+			}
+			g.Edges = append(g.Edges, GraphEdge{From: dep, To: t.ID, Kind: EdgeDependsOn})
+		}
+		if t.Status == TaskPending {
+			g.Nodes[index[t.ID]].WaitingOn = waiting
+		}
+	}
+
+	g.markCycles(index)
+	return g, nil
+}
+
+// markCycles finds the cycles of the graph with a depth-first search and
+// flags their nodes and edges.
+func (g *Graph) markCycles(index map[string]int) {
+	out := make(map[string][]int, len(g.Nodes))
+	for i, e := range g.Edges {
+		out[e.From] = append(out[e.From], i)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(g.Nodes))
+	var path []string   // nodes on the current DFS path
+	var pathEdges []int // edges on the current DFS path
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		path = append(path, id)
+		for _, ei := range out[id] {
+			to := g.Edges[ei].To
+			switch state[to] {
+			case unvisited:
+				pathEdges = append(pathEdges, ei)
+				visit(to)
+				pathEdges = pathEdges[:len(pathEdges)-1]
+			case visiting:
+				start := slices.Index(path, to)
+				cycle := slices.Clone(path[start:])
+				g.Cycles = append(g.Cycles, cycle)
+				for _, n := range cycle {
+					g.Nodes[index[n]].InCycle = true
+				}
+				for _, pe := range pathEdges[start:] {
+					g.Edges[pe].InCycle = true
+				}
+				g.Edges[ei].InCycle = true
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+	}
+
+	for _, n := range g.Nodes {
+		if state[n.ID] == unvisited {
+			visit(n.ID)
+		}
+	}
+}
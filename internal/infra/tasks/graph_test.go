@@ -0,0 +1,99 @@
+package tasks
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildGraph(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+
+	create := func(task *Task) *Task {
+		t.Helper()
+		if err := store.Create(task); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return task
+	}
+
+	external := create(&Task{SessionID: "other", Title: "external", Status: TaskCompleted})
+	parent := create(&Task{SessionID: "s1", Title: "parent", Status: TaskRunning})
+	build := create(&Task{SessionID: "s1", Title: "build", Status: TaskRunning, ParentTaskID: parent.ID})
+	deploy := create(&Task{SessionID: "s1", Title: "deploy", Status: TaskPending, ParentTaskID: parent.ID,
+		DependsOn: []string{build.ID, external.ID, "task_gone"}})
+
+	g, err := BuildGraph(store, "s1")
+	if err != nil {
+		t.Fatalf("BuildGraph: %v", err)
+	}
+
+	if len(g.Nodes) != 5 {
+		t.Fatalf("nodes: got %d, want 5 (%+v)", len(g.Nodes), g.Nodes)
+	}
+	if len(g.Edges) != 5 {
+		t.Errorf("edges: got %d, want 5 (%+v)", len(g.Edges), g.Edges)
+	}
+	if len(g.Cycles) != 0 {
+		t.Errorf("unexpected cycles: %v", g.Cycles)
+	}
+
+	nodes := map[string]GraphNode{}
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	if n := nodes[external.ID]; !n.External || n.Status != TaskCompleted {
+		t.Errorf("external node = %+v", n)
+	}
+	if n := nodes["task_gone"]; !n.Missing {
+		t.Errorf("missing node = %+v", n)
+	}
+	if got, want := nodes[deploy.ID].WaitingOn, []string{build.ID, "task_gone"}; !slices.Equal(got, want) {
+		t.Errorf("deploy waiting on %v, want %v", got, want)
+	}
+	if !slices.Contains(g.Edges, GraphEdge{From: build.ID, To: deploy.ID, Kind: EdgeDependsOn}) {
+		t.Errorf("missing depends_on edge build -> deploy: %+v", g.Edges)
+	}
+	if !slices.Contains(g.Edges, GraphEdge{From: parent.ID, To: build.ID, Kind: EdgeParent}) {
+		t.Errorf("missing parent edge parent -> build: %+v", g.Edges)
+	}
+}
+
+func TestBuildGraph_Cycle(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+
+	a := &Task{SessionID: "s1", Title: "a", Status: TaskPending}
+	b := &Task{SessionID: "s1", Title: "b", Status: TaskPending}
+	c := &Task{SessionID: "s1", Title: "c", Status: TaskPending}
+	for _, task := range []*Task{a, b, c} {
+		if err := store.Create(task); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	// a -> b -> a is a cycle; c depends on a but is not part of it.
+	a.DependsOn = []string{b.ID}
+	b.DependsOn = []string{a.ID}
+	c.DependsOn = []string{a.ID}
+	for _, task := range []*Task{a, b, c} {
+		if err := store.Update(task); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	g, err := BuildGraph(store, "s1")
+	if err != nil {
+		t.Fatalf("BuildGraph: %v", err)
+	}
+	if len(g.Cycles) != 1 || len(g.Cycles[0]) != 2 {
+		t.Fatalf("cycles: got %v, want one cycle of 2", g.Cycles)
+	}
+	for _, n := range g.Nodes {
+		if want := n.ID != c.ID; n.InCycle != want {
+			t.Errorf("%s InCycle = %v, want %v", n.Title, n.InCycle, want)
+		}
+	}
+	for _, e := range g.Edges {
+		if want := e.To != c.ID; e.InCycle != want {
+			t.Errorf("edge %s -> %s InCycle = %v, want %v", e.From, e.To, e.InCycle, want)
+		}
+	}
+}
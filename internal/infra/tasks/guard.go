@@ -0,0 +1,114 @@
+package tasks
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default submission limits.
+const (
+	DefaultMaxTaskDepth      = 5
+	DefaultMaxTasksPerMinute = 10
+)
+
+// SubmissionGuard stops runaway task creation: it bounds the depth of task
+// trees (a task submitting a task submitting a task...) and the number of
+// tasks a session may create per minute.
+type SubmissionGuard struct {
+	store Store
+	now   func() time.Time
+
+	mu           sync.Mutex
+	maxDepth     int
+	maxPerMinute int
+	recent       map[string][]time.Time // session ID → creation times within the window
+}
+
+// NewSubmissionGuard creates a guard. Non-positive limits fall back to the defaults.
+func NewSubmissionGuard(store Store, maxDepth, maxPerMinute int) *SubmissionGuard {
+	g := &SubmissionGuard{
+		store:  store,
+		now:    time.Now,
+		recent: make(map[string][]time.Time),
+	}
+	g.SetLimits(maxDepth, maxPerMinute)
+	return g
+}
+
+// SetLimits replaces the depth and rate limits (config reload). Non-positive
+// limits fall back to the defaults. Recorded creations are kept.
+func (g *SubmissionGuard) SetLimits(maxDepth, maxPerMinute int) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxTaskDepth
+	}
+	if maxPerMinute <= 0 {
+		maxPerMinute = DefaultMaxTasksPerMinute
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.maxDepth = maxDepth
+	g.maxPerMinute = maxPerMinute
+}
+
+// Admit checks that count tasks may be created in sessionID as children of
+// parentID (empty for top-level tasks) and records them on success.
+func (g *SubmissionGuard) Admit(sessionID, parentID string, count int) error {
+	g.mu.Lock()
+	maxDepth := g.maxDepth
+	g.mu.Unlock()
+
+	if parentID != "" {
+		depth, err := g.Depth(parentID)
+		if err != nil {
+			return err
+		}
+		if depth+1 > maxDepth {
+			return fmt.Errorf("task tree depth limit reached: task %s is at depth %d (max %d); finish the work in this task instead of submitting another", parentID, depth, maxDepth)
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	cutoff := now.Add(-time.Minute)
+	recent := g.recent[sessionID]
+	i := 0
+	for i < len(recent) && !recent[i].After(cutoff) {
+		i++
+	}
+	recent = recent[i:]
+
+	if len(recent)+count > g.maxPerMinute {
+		g.recent[sessionID] = recent
+		return fmt.Errorf("task creation rate limit reached: %d task(s) created in the last minute (max %d per minute)", len(recent), g.maxPerMinute)
+	}
+	for range count {
+		recent = append(recent, now)
+	}
+	g.recent[sessionID] = recent
+	return nil
+}
+
+// Depth returns the depth of a task in its tree: 1 for a top-level task.
+// Parents missing from the store end the walk.
+func (g *SubmissionGuard) Depth(taskID string) (int, error) {
+	depth := 0
+	seen := make(map[string]bool)
+	for id := taskID; id != ""; {
+		if seen[id] {
+			return 0, fmt.Errorf("task %s has a parent cycle", taskID)
+		}
+		seen[id] = true
+		depth++
+
+		t, err := g.store.Get(id)
+		if err != nil {
+			break
+		}
+		id = t.ParentTaskID
+	}
+	return depth, nil
+}
@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubmissionGuard_Depth(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	parent := ""
+	var ids []string
+	for i := 0; i < 3; i++ {
+		task := &Task{Title: "t", ParentTaskID: parent}
+		if err := store.Create(task); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.ID)
+		parent = task.ID
+	}
+
+	g := NewSubmissionGuard(store, 3, 100)
+	if d, err := g.Depth(ids[2]); err != nil || d != 3 {
+		t.Fatalf("Depth = %d, %v; want 3", d, err)
+	}
+	if err := g.Admit("sess", ids[1], 1); err != nil {
+		t.Errorf("admit child of depth-2 task: %v", err)
+	}
+	err := g.Admit("sess", ids[2], 1)
+	if err == nil || !strings.Contains(err.Error(), "depth limit") {
+		t.Errorf("expected depth limit error, got %v", err)
+	}
+	if err := g.Admit("sess", "", 1); err != nil {
+		t.Errorf("admit top-level task: %v", err)
+	}
+}
+
+func TestSubmissionGuard_RateLimit(t *testing.T) {
+	g := NewSubmissionGuard(NewFileStore(t.TempDir()), 0, 3)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	if err := g.Admit("a", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Admit("a", "", 2); err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if err := g.Admit("a", "", 1); err != nil {
+		t.Fatalf("third task within limit: %v", err)
+	}
+	// Limits are per session.
+	if err := g.Admit("b", "", 3); err != nil {
+		t.Fatalf("other session: %v", err)
+	}
+
+	now = now.Add(61 * time.Second)
+	if err := g.Admit("a", "", 3); err != nil {
+		t.Fatalf("after window: %v", err)
+	}
+}
+
+func TestSubmissionGuard_SetLimits(t *testing.T) {
+	g := NewSubmissionGuard(NewFileStore(t.TempDir()), 0, 2)
+	if err := g.Admit("a", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Admit("a", "", 1); err == nil {
+		t.Fatal("expected rate limit error")
+	}
+
+	g.SetLimits(0, 5)
+	if err := g.Admit("a", "", 3); err != nil {
+		t.Fatalf("after raising the limit: %v", err)
+	}
+	if err := g.Admit("a", "", 1); err == nil {
+		t.Fatal("expected recorded creations to count against the new limit")
+	}
+}
@@ -0,0 +1,160 @@
+package tasks
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultJanitorInterval is how often the janitor sweeps when no interval is set.
+const defaultJanitorInterval = time.Hour
+
+// RetentionPolicy bounds how long finished (completed, failed or cancelled)
+// tasks stay on disk.
+type RetentionPolicy struct {
+	MaxAge        time.Duration // finished tasks last updated longer ago are deleted (0 = no age limit)
+	MaxPerSession int           // finished tasks kept per session, most recent first (0 = no limit)
+	KeepOutputs   bool          // false deletes the output of finished tasks, keeping the tasks
+}
+
+// Enabled reports whether the policy prunes anything.
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxPerSession > 0 || !p.KeepOutputs
+}
+
+// Janitor periodically prunes finished tasks according to a RetentionPolicy.
+// Pending and running tasks, and the tasks they depend on or descend from,
+// are never pruned.
+type Janitor struct {
+	store    *FileStore
+	policy   RetentionPolicy
+	interval time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJanitor creates a janitor sweeping store every interval (default: 1h).
+func NewJanitor(store *FileStore, policy RetentionPolicy, interval time.Duration) *Janitor {
+	if interval <= 0 {
+		interval = defaultJanitorInterval
+	}
+	return &Janitor{store: store, policy: policy, interval: interval}
+}
+
+// Start sweeps immediately, then every interval in a background goroutine.
+func (j *Janitor) Start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.cancel != nil {
+		return // already running
+	}
+
+	j.done = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.sweep()
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background sweeps.
+func (j *Janitor) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.cancel == nil {
+		return
+	}
+
+	j.cancel()
+	<-j.done
+	j.cancel = nil
+}
+
+func (j *Janitor) sweep() {
+	pruned, outputs, err := j.Sweep(time.Now())
+	if err != nil {
+		slog.Warn("task janitor sweep", "error", err)
+		return
+	}
+	if pruned > 0 || outputs > 0 {
+		slog.Info("task janitor pruned finished tasks", "tasks", pruned, "outputs", outputs)
+	}
+}
+
+// Sweep applies the retention policy once. It returns the number of tasks
+// deleted and the number of outputs deleted from retained tasks.
+func (j *Janitor) Sweep(now time.Time) (pruned, outputs int, err error) {
+	all, err := j.store.List(ListFilter{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Tasks still needed by active work are exempt.
+	exempt := make(map[string]bool)
+	for _, t := range all {
+		if isFinished(t.Status) {
+			continue
+		}
+		exempt[t.ID] = true
+		for _, dep := range t.DependsOn {
+			exempt[dep] = true
+		}
+		if t.ParentTaskID != "" {
+			exempt[t.ParentTaskID] = true
+		}
+	}
+
+	// List is sorted by UpdatedAt descending: the first tasks of a session
+	// are the ones it keeps.
+	kept := make(map[string]int)
+	for _, t := range all {
+		if exempt[t.ID] || !isFinished(t.Status) {
+			continue
+		}
+		kept[t.SessionID]++
+
+		expired := j.policy.MaxAge > 0 && now.Sub(t.UpdatedAt) > j.policy.MaxAge
+		overflow := j.policy.MaxPerSession > 0 && kept[t.SessionID] > j.policy.MaxPerSession
+		if expired || overflow {
+			if err := j.store.Delete(t.ID); err != nil {
+				slog.Warn("task janitor: delete task", "task_id", t.ID, "error", err)
+				continue
+			}
+			pruned++
+			continue
+		}
+
+		if !j.policy.KeepOutputs {
+			if out, _ := j.store.ReadOutput(t.ID); out != "" {
+				if err := j.store.DeleteOutput(t.ID); err != nil {
+					slog.Warn("task janitor: delete output", "task_id", t.ID, "error", err)
+					continue
+				}
+				outputs++
+			}
+		}
+	}
+	return pruned, outputs, nil
+}
+
+// isFinished reports whether a task reached a terminal status.
+func isFinished(s TaskStatus) bool {
+	return s == TaskCompleted || s == TaskFailed || s == TaskCancelled
+}
@@ -0,0 +1,115 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+// createAged creates a task whose UpdatedAt is age in the past.
+func createAged(t *testing.T, store *FileStore, task *Task, age time.Duration) *Task {
+	t.Helper()
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+	task.UpdatedAt = time.Now().Add(-age)
+	store.ds.Lock()
+	defer store.ds.Unlock()
+	if err := store.ds.WriteMeta(task.ID, task); err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func exists(store *FileStore, id string) bool {
+	_, err := store.Get(id)
+	return err == nil
+}
+
+func TestJanitor_MaxAge(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	old := createAged(t, store, &Task{Title: "old", Status: TaskCompleted}, 48*time.Hour)
+	recent := createAged(t, store, &Task{Title: "recent", Status: TaskFailed}, time.Hour)
+	running := createAged(t, store, &Task{Title: "running", Status: TaskRunning}, 48*time.Hour)
+	dep := createAged(t, store, &Task{Title: "dependency", Status: TaskCompleted}, 48*time.Hour)
+	createAged(t, store, &Task{Title: "waiting", Status: TaskPending, DependsOn: []string{dep.ID}}, 48*time.Hour)
+
+	j := NewJanitor(store, RetentionPolicy{MaxAge: 24 * time.Hour, KeepOutputs: true}, 0)
+	pruned, _, err := j.Sweep(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 || exists(store, old.ID) {
+		t.Errorf("pruned %d tasks, want only the old finished one", pruned)
+	}
+	for _, task := range []*Task{recent, running, dep} {
+		if !exists(store, task.ID) {
+			t.Errorf("task %q should be kept", task.Title)
+		}
+	}
+}
+
+func TestJanitor_MaxPerSession(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	var ids []string
+	for i := range 4 {
+		task := createAged(t, store, &Task{Title: "t", SessionID: "s1", Status: TaskCompleted}, time.Duration(i)*time.Hour)
+		ids = append(ids, task.ID)
+	}
+	other := createAged(t, store, &Task{Title: "other", SessionID: "s2", Status: TaskCompleted}, 10*time.Hour)
+
+	j := NewJanitor(store, RetentionPolicy{MaxPerSession: 2, KeepOutputs: true}, 0)
+	if pruned, _, err := j.Sweep(time.Now()); err != nil || pruned != 2 {
+		t.Fatalf("pruned %d (%v), want 2", pruned, err)
+	}
+	if !exists(store, ids[0]) || !exists(store, ids[1]) || exists(store, ids[2]) || exists(store, ids[3]) {
+		t.Error("the two most recent tasks of the session should be kept")
+	}
+	if !exists(store, other.ID) {
+		t.Error("other sessions have their own quota")
+	}
+}
+
+func TestJanitor_DropsOutputs(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	done := createAged(t, store, &Task{Title: "done", Status: TaskCompleted}, time.Hour)
+	active := createAged(t, store, &Task{Title: "active", Status: TaskRunning}, time.Hour)
+	for _, task := range []*Task{done, active} {
+		if err := store.WriteOutput(task.ID, "result"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	j := NewJanitor(store, RetentionPolicy{}, 0)
+	if _, outputs, err := j.Sweep(time.Now()); err != nil || outputs != 1 {
+		t.Fatalf("deleted %d outputs (%v), want 1", outputs, err)
+	}
+	if out, _ := store.ReadOutput(done.ID); out != "" || !exists(store, done.ID) {
+		t.Error("finished task should be kept without its output")
+	}
+	if out, _ := store.ReadOutput(active.ID); out != "result" {
+		t.Error("running task output should be kept")
+	}
+}
+
+func TestFileStore_OutputFile(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	old := &Task{Title: "old", Result: &TaskResult{OutputPath: DefaultOutputFile}}
+	if err := store.Create(old); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.WriteOutput(old.ID, "old output")
+
+	store.SetOutputFile("result.txt")
+	task := &Task{Title: "new"}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.WriteOutput(task.ID, "new output")
+
+	if data, _ := store.ds.ReadFileContent(task.ID, "result.txt"); string(data) != "new output" {
+		t.Errorf("output should be written to the configured file, got %q", data)
+	}
+	if out, _ := store.ReadOutput(old.ID); out != "old output" {
+		t.Errorf("output recorded under the previous name should stay readable, got %q", out)
+	}
+}
@@ -0,0 +1,114 @@
+package tasks
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+const (
+	// narrationInterval is the minimum delay between two narration events of a task.
+	narrationInterval = 3 * time.Second
+	// narrationMaxRunes bounds the text of a narration event.
+	narrationMaxRunes = 300
+)
+
+// narrator publishes the interim assistant output of a verbose task to its
+// originating session as task.narration events. Events are throttled: within
+// an interval only the latest message is kept and sent when it elapses.
+type narrator struct {
+	bus       events.EventBus
+	sessionID string
+	taskID    string
+	title     string
+	interval  time.Duration
+
+	mu      sync.Mutex
+	last    time.Time
+	pending string
+	timer   *time.Timer
+	stopped bool
+}
+
+func newNarrator(bus events.EventBus, task *Task, interval time.Duration) *narrator {
+	return &narrator{
+		bus:       bus,
+		sessionID: task.SessionID,
+		taskID:    task.ID,
+		title:     task.Title,
+		interval:  interval,
+	}
+}
+
+// Add records an assistant message, publishing it now or once the throttle
+// interval has elapsed.
+func (n *narrator) Add(text string) {
+	text = summarizeNarration(text)
+	if text == "" {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopped {
+		return
+	}
+
+	if wait := n.interval - time.Since(n.last); wait > 0 {
+		n.pending = text
+		if n.timer == nil {
+			n.timer = time.AfterFunc(wait, n.flush)
+		}
+		return
+	}
+	n.publishLocked(text)
+}
+
+// Stop publishes any pending message and disables further narration.
+func (n *narrator) Stop() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopped {
+		return
+	}
+	n.stopped = true
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	if n.pending != "" {
+		n.publishLocked(n.pending)
+	}
+}
+
+func (n *narrator) flush() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.timer = nil
+	if n.stopped || n.pending == "" {
+		return
+	}
+	n.publishLocked(n.pending)
+}
+
+// publishLocked emits a narration event. Caller must hold n.mu.
+func (n *narrator) publishLocked(text string) {
+	n.pending = ""
+	n.last = time.Now()
+	n.bus.Publish(events.NewTypedEventWithSession(events.SourceTask, events.TaskNarrationPayload{
+		TaskID: n.taskID,
+		Title:  n.title,
+		Text:   text,
+	}, n.sessionID))
+}
+
+// summarizeNarration collapses whitespace and keeps the head of a message.
+func summarizeNarration(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > narrationMaxRunes {
+		text = string(r[:narrationMaxRunes]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+func receiveNarration(t *testing.T, ch <-chan events.Event) events.TaskNarrationPayload {
+	t.Helper()
+	select {
+	case evt := <-ch:
+		p, ok := events.GetTaskNarrationPayload(evt)
+		if !ok {
+			t.Fatalf("unexpected event %s", evt.Type)
+		}
+		if evt.SessionID != "sess_1" {
+			t.Errorf("session = %q, want sess_1", evt.SessionID)
+		}
+		return p
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for narration")
+	}
+	return events.TaskNarrationPayload{}
+}
+
+func TestNarrator_Throttles(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	ch, unsub := bus.SubscribeChan(16, events.EventTaskNarration)
+	defer unsub()
+
+	n := newNarrator(bus, &Task{ID: "task_1", SessionID: "sess_1", Title: "Build"}, 50*time.Millisecond)
+	n.Add("first")
+	n.Add("second")
+	n.Add("third")
+
+	if p := receiveNarration(t, ch); p.Text != "first" || p.TaskID != "task_1" || p.Title != "Build" {
+		t.Errorf("first narration = %+v", p)
+	}
+	// Within the interval only the latest message is kept.
+	if p := receiveNarration(t, ch); p.Text != "third" {
+		t.Errorf("throttled narration = %q, want third", p.Text)
+	}
+
+	n.Stop()
+	n.Add("after stop")
+	select {
+	case evt := <-ch:
+		t.Errorf("unexpected event after stop: %+v", evt.Payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNarrator_StopFlushesPending(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	ch, unsub := bus.SubscribeChan(16, events.EventTaskNarration)
+	defer unsub()
+
+	n := newNarrator(bus, &Task{ID: "task_1", SessionID: "sess_1"}, time.Hour)
+	n.Add("first")
+	n.Add("  last\n\nwords  ")
+	n.Stop()
+
+	receiveNarration(t, ch)
+	if p := receiveNarration(t, ch); p.Text != "last words" {
+		t.Errorf("flushed narration = %q, want %q", p.Text, "last words")
+	}
+}
+
+func TestSummarizeNarration_Truncates(t *testing.T) {
+	got := summarizeNarration(strings.Repeat("é", narrationMaxRunes+10))
+	if n := len([]rune(got)); n != narrationMaxRunes+1 {
+		t.Errorf("rune count = %d, want %d", n, narrationMaxRunes+1)
+	}
+	if summarizeNarration(" \n ") != "" {
+		t.Error("blank text should summarize to empty")
+	}
+}
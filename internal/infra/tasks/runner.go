@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -8,7 +9,6 @@ import (
 	"maps"
 	"slices"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/dohr-michael/ozzie/internal/core/brain"
@@ -36,9 +36,12 @@ type TaskRunner struct {
 	perms           ToolPermissionsSeeder // for seeding pre-approved tools (optional)
 	clientFacing    bool                  // inject persona into sub-agent instruction
 	persona         string                // persona text (from LoadPersona)
+	limits          ContextLimits
+	compactor       brain.ContextCompactor // shrinks context after an overflow (optional)
+	maxIterations   int                    // ReAct iteration cap (0 = taskMaxIterations)
+	maxIterLimit    int                    // highest cap a task may set (0 = maxIterations)
 
-	tokenMu    sync.Mutex
-	tokenUsage brain.TokenUsage
+	tokens *events.TokenTracker // set for the duration of Run
 }
 
 // TaskRunnerConfig holds dependencies for creating a TaskRunner.
@@ -46,17 +49,28 @@ type TaskRunnerConfig struct {
 	Store           Store
 	Bus             events.EventBus
 	RunnerFactory   brain.RunnerFactory
-	ModelName       string                // provider/model name for CreateRunner
+	ModelName       string // provider/model name for CreateRunner
 	ToolLookup      brain.ToolLookup
 	SkillRunner     SkillExecutor
 	PreemptionCheck func() bool
-	Middlewares     []any                 // opaque middlewares for sub-agents (e.g. filesystem, reduction)
-	Retriever       brain.MemoryRetriever // pre-task memory retrieval (optional)
-	Tier            brain.ModelTier       // model tier for prompt adaptation
-	PromptPrefix    string                // overlay-specific prompt prefix (optional)
-	Perms           ToolPermissionsSeeder // for seeding pre-approved tools (optional)
-	ClientFacing    bool                  // inject persona into sub-agent instruction
-	Persona         string                // persona text (from LoadPersona)
+	Middlewares     []any                  // opaque middlewares for sub-agents (e.g. filesystem, reduction)
+	Retriever       brain.MemoryRetriever  // pre-task memory retrieval (optional)
+	Tier            brain.ModelTier        // model tier for prompt adaptation
+	PromptPrefix    string                 // overlay-specific prompt prefix (optional)
+	Perms           ToolPermissionsSeeder  // for seeding pre-approved tools (optional)
+	ClientFacing    bool                   // inject persona into sub-agent instruction
+	Persona         string                 // persona text (from LoadPersona)
+	Limits          ContextLimits          // context injection limits (zero = defaults)
+	Compactor       brain.ContextCompactor // shrinks context after an overflow (optional: truncation)
+	MaxIterations   int                    // ReAct iteration cap, unless the task sets its own (0 = taskMaxIterations)
+	MaxIterLimit    int                    // highest cap a task may set; higher ones are clamped (0 = MaxIterations)
+}
+
+// ContextLimits bounds the prior output injected into a task's instruction.
+// Zero values select the defaults.
+type ContextLimits struct {
+	DependencyOutputChars int // per dependency (default: maxDependencyOutputLen)
+	MemoryContextChars    int // memory block (default: maxMemoryContextLen)
 }
 
 // NewTaskRunner creates a runner for a specific task.
@@ -77,12 +91,33 @@ func NewTaskRunner(task *Task, cfg TaskRunnerConfig) *TaskRunner {
 		perms:           cfg.Perms,
 		clientFacing:    cfg.ClientFacing,
 		persona:         cfg.Persona,
+		limits:          cfg.Limits,
+		compactor:       cfg.Compactor,
+		maxIterations:   cfg.MaxIterations,
+		maxIterLimit:    cfg.MaxIterLimit,
 	}
 }
 
-// taskMaxIterations gives async tasks more room for ReAct loops than the default (20).
+// taskMaxIterations is the default ReAct iteration cap of tasks: more room
+// than the ADK default (20) for autonomous work.
 const taskMaxIterations = 30
 
+// iterationCap returns the ReAct iteration cap of a task: its own
+// max_iterations clamped to the limit, or the runner's cap.
+func (r *TaskRunner) iterationCap(ctx context.Context, task *Task) int {
+	capped := cmp.Or(r.maxIterations, taskMaxIterations)
+	if task.Config.MaxIterations == 0 {
+		return capped
+	}
+	limit := cmp.Or(r.maxIterLimit, capped)
+	if task.Config.MaxIterations > limit {
+		slog.WarnContext(ctx, "task max_iterations clamped", "task_id", task.ID,
+			"requested", task.Config.MaxIterations, "limit", limit)
+		return limit
+	}
+	return task.Config.MaxIterations
+}
+
 // Run executes the task to completion or failure.
 func (r *TaskRunner) Run(ctx context.Context) error {
 	// Mark context as autonomous + carry session ID for tool permissions
@@ -126,11 +161,14 @@ func (r *TaskRunner) Run(ctx context.Context) error {
 	}, task.SessionID))
 
 	// Track token usage from LLM call events
-	unsub := r.trackTokens()
-	defer unsub()
+	r.tokens = r.trackTokens()
+	defer r.tokens.Stop()
 
 	// Skill shortcut: execute directly without agent reasoning
 	if task.Config.Skill != "" && r.skillRunner != nil {
+		if task.Config.Simulate {
+			return r.runSimulatedSkillStep(ctx, task, startedAt)
+		}
 		return r.runSkillStep(ctx, task, startedAt)
 	}
 
@@ -142,30 +180,24 @@ func (r *TaskRunner) isPreempted() bool {
 	return r.preemptionCheck != nil && r.preemptionCheck()
 }
 
-// trackTokens subscribes to LLM call events and accumulates token usage.
-// Returns an unsubscribe function that must be deferred.
-func (r *TaskRunner) trackTokens() func() {
-	return r.bus.Subscribe(func(e events.Event) {
-		// Match by session ID — task agents run in their own session context.
-		if e.SessionID == "" || e.SessionID != r.task.SessionID {
-			return
-		}
-		payload, ok := events.GetLLMCallPayload(e)
-		if !ok || payload.Phase != "response" {
-			return
-		}
-		r.tokenMu.Lock()
-		r.tokenUsage.Input += payload.TokensInput
-		r.tokenUsage.Output += payload.TokensOutput
-		r.tokenMu.Unlock()
-	}, events.EventLLMCall)
+// trackTokens accumulates token usage of LLM calls issued by this task.
+// Calls are correlated by task ID (set in Run's context), so concurrent tasks
+// and the interactive conversation of the same session are not mixed in.
+// The returned tracker must be stopped.
+func (r *TaskRunner) trackTokens() *events.TokenTracker {
+	taskID := r.task.ID
+	return events.TrackTokens(r.bus, func(p events.LLMCallPayload) bool {
+		return p.TaskID == taskID
+	})
 }
 
 // getTokenUsage returns a snapshot of accumulated token usage.
 func (r *TaskRunner) getTokenUsage() brain.TokenUsage {
-	r.tokenMu.Lock()
-	defer r.tokenMu.Unlock()
-	return r.tokenUsage
+	if r.tokens == nil {
+		return brain.TokenUsage{}
+	}
+	in, out := r.tokens.Usage()
+	return brain.TokenUsage{Input: in, Output: out}
 }
 
 // preemptTask re-queues a preempted task as pending.
@@ -188,16 +220,14 @@ func (r *TaskRunner) preemptTask(task *Task) error {
 func (r *TaskRunner) runSingleStep(ctx context.Context, task *Task, startedAt time.Time) error {
 	var tools []brain.Tool
 	if len(task.Config.Tools) > 0 {
-		tools = r.toolLookup.ToolsByNames(task.Config.Tools)
+		tools = r.toolLookup.ToolsByNames(append(slices.Clone(task.Config.Tools), RegisterArtifactTool))
 	}
 
-	depContext := buildDependencyContext(r.store, task.DependsOn)
+	depContext := buildDependencyContextWithLimit(r.store, task.DependsOn,
+		cmp.Or(r.limits.DependencyOutputChars, maxDependencyOutputLen))
 	memoryContext := r.buildMemoryContext(ctx)
-	instruction := r.prefixedInstruction(fmt.Sprintf("Execute the following task.\n\nTitle: %s\nDescription: %s%s%s%s",
-		task.Title, task.Description, formatContextBlock(task.Config), depContext, memoryContext))
-	if r.clientFacing && r.persona != "" {
-		instruction = r.persona + "\n\n" + instruction
-	}
+	contextBlocks := formatContextBlock(task.Config) + formatGitContext(ctx, task.Config) + depContext + memoryContext
+	instruction := r.taskInstruction(task, contextBlocks)
 
 	toolNames := make([]string, len(tools))
 	for i, t := range tools {
@@ -205,51 +235,149 @@ func (r *TaskRunner) runSingleStep(ctx context.Context, task *Task, startedAt ti
 			toolNames[i] = info.Name
 		}
 	}
-	slog.Info("task agent setup",
-		"task_id", task.ID,
+	slog.InfoContext(ctx, "task agent setup",
 		"actor_id", task.ActorID,
 		"provider", task.ProviderName,
 		"tools", toolNames,
 		"instruction_len", len(instruction),
+		"simulate", task.Config.Simulate,
 	)
 
-	runner, err := r.runnerFactory.CreateRunner(ctx, r.modelName, instruction, tools,
-		brain.WithMaxIterations(taskMaxIterations),
+	runnerOpts := []brain.RunnerOption{
+		brain.WithMaxIterations(r.iterationCap(ctx, task)),
 		brain.WithMiddlewares(r.middlewares),
 		brain.WithPreemptionCheck(r.isPreempted),
+	}
+	// Checkpoint the step's progress so an interrupted task can resume it.
+	steps := &stepCheckpointer{store: r.store, taskID: task.ID}
+	onAssistantText := steps.AssistantText
+	// Verbose tasks narrate their interim output to the originating session.
+	var narr *narrator
+	if task.Config.Verbose && task.SessionID != "" {
+		narr = newNarrator(r.bus, task, narrationInterval)
+		onAssistantText = func(text string) {
+			steps.AssistantText(text)
+			narr.Add(text)
+		}
+	}
+	runnerOpts = append(runnerOpts,
+		brain.WithAssistantText(onAssistantText),
+		brain.WithToolResult(steps.ToolResult),
 	)
+	// Dry run: calls to tools that are not read-only are recorded, not executed.
+	var sim *simulation
+	if task.Config.Simulate {
+		sim = &simulation{}
+		runnerOpts = append(runnerOpts, brain.WithToolCallInterceptor(sim.intercept))
+	}
+
+	output, err := r.runAgent(ctx, task, instruction, tools, runnerOpts, r.initialMessages(task))
+	// Context overflow: resume once from the step checkpoints, with the run's
+	// history and the injected context compacted, instead of starting over.
+	var overflow *brain.ErrContextOverflow
+	if errors.As(err, &overflow) {
+		if compacted, ok := r.compactContext(ctx, task, contextBlocks); ok {
+			instruction = r.taskInstruction(task, compacted)
+		}
+		output, err = r.runAgent(ctx, task, instruction, tools, runnerOpts, r.overflowMessages(ctx, task))
+	}
+	if narr != nil {
+		narr.Stop()
+	}
 	if err != nil {
 		// Model unavailable: don't fail task, let actor pool handle retry
 		var unavail *brain.ErrModelUnavailable
 		if errors.As(err, &unavail) {
 			return err
 		}
-		return r.failTask(task, startedAt, fmt.Errorf("create agent: %w", err))
+		if errors.Is(err, brain.ErrRunnerPreempted) {
+			return r.preemptTask(task)
+		}
+		// Cap reached: keep what the agent produced so far for review.
+		var maxIter *brain.ErrMaxIterations
+		if errors.As(err, &maxIter) && output != "" {
+			_ = r.store.WriteOutput(task.ID, output)
+		}
+		return r.failTask(task, startedAt, err)
+	}
+	if sim != nil {
+		output += sim.Report()
 	}
 
-	messages := []brain.Message{
-		{Role: brain.RoleUser, Content: task.Description},
+	return r.completeTask(task, startedAt, output)
+}
+
+// taskInstruction builds the sub-agent instruction around the injected context blocks.
+func (r *TaskRunner) taskInstruction(task *Task, contextBlocks string) string {
+	instruction := r.prefixedInstruction(fmt.Sprintf("Execute the following task.\n\nTitle: %s\nDescription: %s%s",
+		task.Title, task.Description, contextBlocks))
+	if task.Config.Simulate {
+		instruction += simulateInstruction
 	}
+	if r.clientFacing && r.persona != "" {
+		instruction = r.persona + "\n\n" + instruction
+	}
+	return instruction
+}
 
-	output, err := runner.Run(ctx, messages)
+// runAgent creates an ephemeral agent for the instruction and runs it on messages.
+func (r *TaskRunner) runAgent(ctx context.Context, task *Task, instruction string, tools []brain.Tool, opts []brain.RunnerOption, messages []brain.Message) (string, error) {
+	runner, err := r.runnerFactory.CreateRunner(ctx, r.modelName, instruction, tools, opts...)
 	if err != nil {
-		// Model unavailable: don't fail task, let actor pool handle retry
 		var unavail *brain.ErrModelUnavailable
 		if errors.As(err, &unavail) {
-			return err
+			return "", err
 		}
-		if errors.Is(err, brain.ErrRunnerPreempted) {
-			return r.preemptTask(task)
+		return "", fmt.Errorf("create agent: %w", err)
+	}
+
+	return runner.Run(ctx, messages)
+}
+
+// minCompactableContext is the injected context size below which compacting
+// cannot recover from an overflow.
+const minCompactableContext = 1000
+
+// compactContext shrinks the context blocks of an instruction that overflowed
+// the model's context window to half their size: summarized by the compactor
+// when one is configured, truncated otherwise. Returns false when the context
+// is too small for compaction to help (the run's history is compacted anyway,
+// see overflowMessages).
+func (r *TaskRunner) compactContext(ctx context.Context, task *Task, contextBlocks string) (string, bool) {
+	if len(contextBlocks) < minCompactableContext {
+		return "", false
+	}
+	target := len(contextBlocks) / 2
+
+	var compacted string
+	if r.compactor != nil {
+		summary, err := r.compactor.CompactContext(ctx, contextBlocks, target)
+		if err != nil {
+			slog.WarnContext(ctx, "task context compaction failed, truncating", "task_id", task.ID, "error", err)
+		} else {
+			compacted = "\n\n" + strings.TrimSpace(summary)
 		}
-		return r.failTask(task, startedAt, err)
+	}
+	if compacted == "" {
+		compacted = truncate(contextBlocks, target)
 	}
 
-	return r.completeTask(task, startedAt, output)
+	slog.WarnContext(ctx, "task context overflowed the model window, retrying compacted",
+		"task_id", task.ID, "context_len", len(contextBlocks), "compacted_len", len(compacted))
+	_ = r.store.AppendCheckpoint(task.ID, Checkpoint{
+		Ts:      time.Now(),
+		Type:    "context_compacted",
+		Summary: fmt.Sprintf("Context overflowed the model window: compacted from %d to %d chars", len(contextBlocks), len(compacted)),
+	})
+	return compacted, true
 }
 
 // runSkillStep executes a skill directly, bypassing agent reasoning.
 func (r *TaskRunner) runSkillStep(ctx context.Context, task *Task, startedAt time.Time) error {
 	vars := map[string]string{"request": task.Description}
+	for k, v := range task.Config.SkillVars {
+		vars[k] = v
+	}
 	output, err := r.skillRunner.RunSkill(ctx, task.Config.Skill, vars)
 	if err != nil {
 		return r.failTask(task, startedAt, fmt.Errorf("skill %s: %w", task.Config.Skill, err))
@@ -269,8 +397,9 @@ func (r *TaskRunner) completeTask(task *Task, startedAt time.Time, output string
 	task.CompletedAt = &now
 	task.Progress.Percentage = 100
 	task.Result = &TaskResult{
-		OutputPath: "output.md",
+		OutputPath: outputFile(r.store),
 		TokenUsage: usage,
+		Artifacts:  r.listArtifacts(task.ID),
 	}
 	if err := r.store.Update(task); err != nil {
 		return fmt.Errorf("update task completed: %w", err)
@@ -294,6 +423,23 @@ func (r *TaskRunner) completeTask(task *Task, startedAt time.Time, output string
 	return nil
 }
 
+// outputFile returns the name of the output file the store writes.
+func outputFile(store Store) string {
+	if s, ok := store.(interface{ OutputFile() string }); ok {
+		return s.OutputFile()
+	}
+	return DefaultOutputFile
+}
+
+// listArtifacts returns the artifacts registered by the task so far (best effort).
+func (r *TaskRunner) listArtifacts(taskID string) []Artifact {
+	artifacts, err := r.store.ListArtifacts(taskID)
+	if err != nil {
+		slog.Warn("list task artifacts", "task_id", taskID, "error", err)
+	}
+	return artifacts
+}
+
 func (r *TaskRunner) failTask(task *Task, startedAt time.Time, taskErr error) error {
 	now := time.Now()
 	task.Status = TaskFailed
@@ -301,6 +447,7 @@ func (r *TaskRunner) failTask(task *Task, startedAt time.Time, taskErr error) er
 	task.Result = &TaskResult{
 		Error:      taskErr.Error(),
 		TokenUsage: r.getTokenUsage(),
+		Artifacts:  r.listArtifacts(task.ID),
 	}
 	willRetry := task.RetryCount < task.MaxRetries
 
@@ -345,7 +492,7 @@ func formatContextBlock(cfg TaskConfig) string {
 	return b.String()
 }
 
-// maxMemoryContextLen is the maximum total length of the memory context block.
+// maxMemoryContextLen is the default maximum total length of the memory context block.
 const maxMemoryContextLen = 2000
 
 // buildMemoryContext retrieves relevant memories for the task and formats them
@@ -364,10 +511,10 @@ func (r *TaskRunner) buildMemoryContext(ctx context.Context) string {
 	}
 
 	limit := 5
-	maxLen := maxMemoryContextLen
+	maxLen := cmp.Or(r.limits.MemoryContextChars, maxMemoryContextLen)
 	if r.tier == brain.TierSmall {
 		limit = 2
-		maxLen = 800
+		maxLen = min(maxLen, 800)
 	}
 
 	memories, err := r.retriever.Retrieve(ctx, query, tags, limit)
@@ -389,7 +536,7 @@ func (r *TaskRunner) buildMemoryContext(ctx context.Context) string {
 	return b.String()
 }
 
-// maxDependencyOutputLen is the maximum length of a single dependency output
+// maxDependencyOutputLen is the default maximum length of a single dependency output
 // injected into the instruction. Prevents context window overflow.
 const maxDependencyOutputLen = 1000
 
@@ -460,8 +607,11 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen] + "..."
 }
 
-// NewTaskExecutorFactory returns a brain.TaskExecutorFactory that creates TaskRunner instances.
-func NewTaskExecutorFactory() brain.TaskExecutorFactory {
+// NewTaskExecutorFactory returns a brain.TaskExecutorFactory that creates
+// TaskRunner instances with the given context limits, compactor (may be nil),
+// default ReAct iteration cap (0 = taskMaxIterations) and highest cap a task
+// may request (0 = the default cap).
+func NewTaskExecutorFactory(limits ContextLimits, compactor brain.ContextCompactor, maxIterations, maxIterLimit int) brain.TaskExecutorFactory {
 	return func(task *brain.Task, cfg brain.TaskExecutorConfig) brain.TaskExecutor {
 		return NewTaskRunner(task, TaskRunnerConfig{
 			Store:           cfg.Store,
@@ -478,6 +628,10 @@ func NewTaskExecutorFactory() brain.TaskExecutorFactory {
 			Perms:           cfg.Perms,
 			ClientFacing:    cfg.ClientFacing,
 			Persona:         cfg.Persona,
+			Limits:          limits,
+			Compactor:       compactor,
+			MaxIterations:   maxIterations,
+			MaxIterLimit:    maxIterLimit,
 		})
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
 	"github.com/dohr-michael/ozzie/pkg/memory"
 )
 
@@ -120,6 +122,11 @@ func (m *mockStore) Delete(string) error                             { return ni
 func (m *mockStore) AppendCheckpoint(string, Checkpoint) error       { return nil }
 func (m *mockStore) LoadCheckpoints(string) ([]Checkpoint, error)    { return nil, nil }
 func (m *mockStore) WriteOutput(string, string) error                { return nil }
+func (m *mockStore) WriteArtifact(string, Artifact, []byte) error    { return nil }
+func (m *mockStore) ListArtifacts(string) ([]Artifact, error)        { return nil, nil }
+func (m *mockStore) ReadArtifact(string, string) (*Artifact, []byte, error) {
+	return nil, nil, fmt.Errorf("not found")
+}
 
 func TestBuildDependencyContext_NoDeps(t *testing.T) {
 	got := buildDependencyContext(&mockStore{}, nil)
@@ -383,3 +390,158 @@ func TestBuildMemoryContext_QueryTruncation(t *testing.T) {
 		t.Errorf("expected empty for no results, got %q", got)
 	}
 }
+
+// --- context overflow recovery tests ---
+
+// overflowRunnerFactory creates runners rejecting instructions longer than maxLen.
+type overflowRunnerFactory struct {
+	maxLen       int
+	instructions []string
+}
+
+func (f *overflowRunnerFactory) CreateRunner(_ context.Context, _ string, instruction string, _ []brain.Tool, _ ...brain.RunnerOption) (brain.Runner, error) {
+	f.instructions = append(f.instructions, instruction)
+	return &overflowRunner{overflow: len(instruction) > f.maxLen}, nil
+}
+
+type overflowRunner struct{ overflow bool }
+
+func (r *overflowRunner) Run(context.Context, []brain.Message) (string, error) {
+	if r.overflow {
+		return "", &brain.ErrContextOverflow{Cause: fmt.Errorf("prompt is too long")}
+	}
+	return "done", nil
+}
+
+type stubCompactor struct{ summary string }
+
+func (c *stubCompactor) CompactContext(context.Context, string, int) (string, error) {
+	return c.summary, nil
+}
+
+func newOverflowFixture(t *testing.T, maxLen int, compactor brain.ContextCompactor) (*Task, *TaskRunner, *overflowRunnerFactory) {
+	t.Helper()
+	bus := events.NewBus(16)
+	t.Cleanup(bus.Close)
+
+	store := &mockStore{
+		tasks: map[string]*Task{
+			"dep": {ID: "dep", Title: "Collect logs", Status: TaskCompleted},
+		},
+		outputs: map[string]string{"dep": strings.Repeat("log line\n", 400)},
+	}
+	task := &Task{ID: "task", Title: "Analyze", Description: "Find errors", DependsOn: []string{"dep"}}
+	store.tasks["task"] = task
+
+	factory := &overflowRunnerFactory{maxLen: maxLen}
+	runner := NewTaskRunner(task, TaskRunnerConfig{
+		Store:         store,
+		Bus:           bus,
+		RunnerFactory: factory,
+		Compactor:     compactor,
+		Limits:        ContextLimits{DependencyOutputChars: 3000},
+	})
+	return task, runner, factory
+}
+
+func TestTaskRunner_OverflowRetriesTruncated(t *testing.T) {
+	task, runner, factory := newOverflowFixture(t, 2500, nil)
+
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != TaskCompleted {
+		t.Fatalf("status = %s, want completed after a compacted retry", task.Status)
+	}
+	if len(factory.instructions) != 2 || len(factory.instructions[1]) >= len(factory.instructions[0]) {
+		t.Errorf("expected a second, shorter instruction, got lengths %d", len(factory.instructions))
+	}
+}
+
+func TestTaskRunner_OverflowUsesCompactor(t *testing.T) {
+	task, runner, factory := newOverflowFixture(t, 2500, &stubCompactor{summary: "Logs: 400 lines, no errors."})
+
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != TaskCompleted {
+		t.Fatalf("status = %s, want completed", task.Status)
+	}
+	if last := factory.instructions[len(factory.instructions)-1]; !strings.Contains(last, "Logs: 400 lines") {
+		t.Errorf("retry instruction should carry the compacted context:\n%s", last)
+	}
+}
+
+func TestTaskRunner_OverflowRetriesOnce(t *testing.T) {
+	task, runner, factory := newOverflowFixture(t, 100, nil)
+
+	_ = runner.Run(context.Background())
+	if task.Status != TaskFailed {
+		t.Errorf("status = %s, want failed when the compacted context still overflows", task.Status)
+	}
+	if len(factory.instructions) != 2 {
+		t.Errorf("runner created %d times, want 2 (one retry)", len(factory.instructions))
+	}
+}
+
+// cappedRunnerFactory creates runners that hit their iteration cap after
+// producing partial output, recording the cap they were given.
+type cappedRunnerFactory struct{ maxIterations []int }
+
+func (f *cappedRunnerFactory) CreateRunner(_ context.Context, _ string, _ string, _ []brain.Tool, opts ...brain.RunnerOption) (brain.Runner, error) {
+	o := brain.ApplyRunnerOpts(opts)
+	f.maxIterations = append(f.maxIterations, o.MaxIterations)
+	return &cappedRunner{limit: o.MaxIterations}, nil
+}
+
+type cappedRunner struct{ limit int }
+
+func (r *cappedRunner) Run(context.Context, []brain.Message) (string, error) {
+	return "halfway there", &brain.ErrMaxIterations{Limit: r.limit}
+}
+
+func TestTaskRunner_MaxIterations(t *testing.T) {
+	tests := []struct {
+		name       string
+		taskCap    int
+		runnerCap  int
+		limit      int
+		wantCapArg int
+	}{
+		{"default", 0, 0, 0, taskMaxIterations},
+		{"configured", 0, 12, 0, 12},
+		{"task lowers cap", 8, 12, 0, 8},
+		{"task override clamped to cap", 50, 12, 0, 12},
+		{"task override within limit", 50, 12, 60, 50},
+		{"task override clamped to limit", 500, 12, 60, 60},
+		{"default cap limits unconfigured runner", 1000, 0, 0, taskMaxIterations},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := events.NewBus(16)
+			defer bus.Close()
+			task := &Task{ID: "task", Title: "Refactor", Description: "Big refactor", Config: TaskConfig{MaxIterations: tt.taskCap}}
+			store := &outputStore{mockStore: &mockStore{tasks: map[string]*Task{"task": task}}}
+			factory := &cappedRunnerFactory{}
+
+			err := NewTaskRunner(task, TaskRunnerConfig{
+				Store:         store,
+				Bus:           bus,
+				RunnerFactory: factory,
+				MaxIterations: tt.runnerCap,
+				MaxIterLimit:  tt.limit,
+			}).Run(context.Background())
+
+			if len(factory.maxIterations) != 1 || factory.maxIterations[0] != tt.wantCapArg {
+				t.Errorf("runner caps = %v, want [%d]", factory.maxIterations, tt.wantCapArg)
+			}
+			want := fmt.Sprintf("max iterations reached (%d)", tt.wantCapArg)
+			if task.Status != TaskFailed || err == nil || !strings.Contains(task.Result.Error, want) {
+				t.Errorf("status = %s, error = %v, want failed with %q", task.Status, err, want)
+			}
+			if store.output != "halfway there" {
+				t.Errorf("partial output = %q, want it kept", store.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// simulateInstruction tells the agent of a simulated task that its actions are not executed.
+const simulateInstruction = "\n\n## Dry Run\n" +
+	"This is a simulation: tools that modify state are not executed, their calls are recorded for review. " +
+	"Explore with read-only tools as usual, then make the calls you would make to complete the task.\n"
+
+// simulatedCall is a tool invocation a simulated task intended to make.
+type simulatedCall struct {
+	Tool      string
+	Arguments string
+}
+
+// simulation records the tool calls of a simulated (dry-run) task.
+type simulation struct {
+	mu    sync.Mutex
+	calls []simulatedCall
+}
+
+func (s *simulation) record(call simulatedCall) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+// Report formats the recorded calls as a markdown block appended to the task output.
+func (s *simulation) Report() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("\n\n## Simulation Report\n")
+	if len(s.calls) == 0 {
+		b.WriteString("No side-effecting tool calls were planned.\n")
+		return b.String()
+	}
+	b.WriteString("Planned tool calls (not executed):\n")
+	for i, c := range s.calls {
+		fmt.Fprintf(&b, "%d. %s %s\n", i+1, c.Tool, c.Arguments)
+	}
+	return b.String()
+}
+
+// simulationReadOnlyTools are the tools a simulated task still runs for real:
+// they only read state. Every other call is recorded instead of executed,
+// whatever provides the tool (registry or middleware, e.g. the filesystem
+// middleware's write_file/edit_file/execute).
+var simulationReadOnlyTools = map[string]bool{
+	"read_file":      true,
+	"ls":             true,
+	"glob":           true,
+	"grep":           true,
+	"query_memories": true,
+	"query_tasks":    true,
+	"get_var":        true,
+	"list_schedules": true,
+}
+
+// intercept implements brain.ToolCallInterceptor for a simulated task.
+func (s *simulation) intercept(ctx context.Context, tool, argumentsInJSON string) (string, bool) {
+	if simulationReadOnlyTools[tool] {
+		return "", false
+	}
+	s.record(simulatedCall{Tool: tool, Arguments: argumentsInJSON})
+	slog.InfoContext(ctx, "simulated tool call", "tool", tool, "arguments", truncate(argumentsInJSON, 200))
+	return fmt.Sprintf("[simulated] %s was not executed (dry run). Assume it succeeded and continue planning.", tool), true
+}
+
+// runSimulatedSkillStep describes the steps a skill task would run instead of
+// running them. The description comes from the skill runner when it can plan
+// a skill (see PlanSkill), the requested skill and variables otherwise.
+func (r *TaskRunner) runSimulatedSkillStep(ctx context.Context, task *Task, startedAt time.Time) error {
+	vars := map[string]string{"request": task.Description}
+	maps.Copy(vars, task.Config.SkillVars)
+
+	var plan string
+	if planner, ok := r.skillRunner.(interface {
+		PlanSkill(name string, vars map[string]string) (string, error)
+	}); ok {
+		var err error
+		if plan, err = planner.PlanSkill(task.Config.Skill, vars); err != nil {
+			return r.failTask(task, startedAt, fmt.Errorf("skill %s: %w", task.Config.Skill, err))
+		}
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "Skill %s would run with:\n", task.Config.Skill)
+		for _, k := range slices.Sorted(maps.Keys(vars)) {
+			fmt.Fprintf(&b, "- %s: %s\n", k, vars[k])
+		}
+		plan = b.String()
+	}
+
+	slog.InfoContext(ctx, "simulated skill task", "task_id", task.ID, "skill", task.Config.Skill)
+	return r.completeTask(task, startedAt, "## Simulation Report\n"+plan)
+}
@@ -0,0 +1,175 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+// countingTool counts its real executions.
+type countingTool struct {
+	name string
+	runs int
+}
+
+func (t *countingTool) Info(context.Context) (*brain.ToolInfo, error) {
+	return &brain.ToolInfo{Name: t.name}, nil
+}
+
+func (t *countingTool) Run(context.Context, string) (string, error) {
+	t.runs++
+	return t.name + " ran", nil
+}
+
+// toolMap resolves countingTools by name.
+type toolMap map[string]*countingTool
+
+func (m toolMap) ToolsByNames(names []string) []brain.Tool {
+	var result []brain.Tool
+	for _, n := range names {
+		if t, ok := m[n]; ok {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func (m toolMap) ToolNames() []string { return nil }
+
+// callingRunnerFactory creates runners that call every tool once, then
+// write_file as a middleware-injected tool would: outside the tool list,
+// through the runner's interceptor only.
+type callingRunnerFactory struct {
+	writePath string
+	results   []string
+}
+
+func (f *callingRunnerFactory) CreateRunner(_ context.Context, _ string, _ string, tools []brain.Tool, opts ...brain.RunnerOption) (brain.Runner, error) {
+	return &callingRunner{factory: f, tools: tools, intercept: brain.ApplyRunnerOpts(opts).InterceptTool}, nil
+}
+
+type callingRunner struct {
+	factory   *callingRunnerFactory
+	tools     []brain.Tool
+	intercept brain.ToolCallInterceptor
+}
+
+// call runs a tool call the way the agent adapter does: interceptor first.
+func (r *callingRunner) call(ctx context.Context, name, args string, run func() (string, error)) error {
+	if r.intercept != nil {
+		if out, ok := r.intercept(ctx, name, args); ok {
+			r.factory.results = append(r.factory.results, out)
+			return nil
+		}
+	}
+	out, err := run()
+	r.factory.results = append(r.factory.results, out)
+	return err
+}
+
+func (r *callingRunner) Run(ctx context.Context, _ []brain.Message) (string, error) {
+	for _, t := range r.tools {
+		info, _ := t.Info(ctx)
+		if err := r.call(ctx, info.Name, `{"command":"make deploy"}`, func() (string, error) {
+			return t.Run(ctx, `{"command":"make deploy"}`)
+		}); err != nil {
+			return "", err
+		}
+	}
+	args := fmt.Sprintf(`{"file_path":%q,"content":"v2"}`, r.factory.writePath)
+	if err := r.call(ctx, "write_file", args, func() (string, error) {
+		return "written", os.WriteFile(r.factory.writePath, []byte("v2"), 0o644)
+	}); err != nil {
+		return "", err
+	}
+	return "deployed", nil
+}
+
+type recordingSkillRunner struct{ runs int }
+
+func (s *recordingSkillRunner) RunSkill(context.Context, string, map[string]string) (string, error) {
+	s.runs++
+	return "skill ran", nil
+}
+
+// newSimulateFixture returns a task with the given config, a runner config
+// and the output the task writes.
+func newSimulateFixture(t *testing.T, cfg TaskConfig) (*Task, TaskRunnerConfig, *string) {
+	t.Helper()
+	bus := events.NewBus(16)
+	t.Cleanup(bus.Close)
+
+	task := &Task{ID: "task", Title: "Deploy", Description: "Deploy the app", Config: cfg}
+	store := &outputStore{mockStore: &mockStore{tasks: map[string]*Task{"task": task}}}
+	return task, TaskRunnerConfig{Store: store, Bus: bus}, &store.output
+}
+
+func TestTaskRunner_SimulateRecordsSideEffects(t *testing.T) {
+	tools := toolMap{
+		"run_command": {name: "run_command"},
+		"submit_task": {name: "submit_task"}, // not dangerous, still a side effect
+		"read_file":   {name: "read_file"},
+	}
+	task, cfg, output := newSimulateFixture(t, TaskConfig{Tools: []string{"run_command", "submit_task", "read_file"}, Simulate: true})
+	cfg.ToolLookup = tools
+	factory := &callingRunnerFactory{writePath: filepath.Join(t.TempDir(), "created.txt")}
+	cfg.RunnerFactory = factory
+
+	if err := NewTaskRunner(task, cfg).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(factory.writePath); !os.IsNotExist(err) {
+		t.Errorf("simulated task created a file (stat err: %v)", err)
+	}
+	if tools["run_command"].runs+tools["submit_task"].runs != 0 {
+		t.Error("side-effecting tools executed in a simulation")
+	}
+	if tools["read_file"].runs != 1 {
+		t.Errorf("read-only tool executed %d times, want 1", tools["read_file"].runs)
+	}
+	if !strings.HasPrefix(factory.results[0], "[simulated]") {
+		t.Errorf("stub result = %q", factory.results[0])
+	}
+	for _, want := range []string{"## Simulation Report", `1. run_command {"command":"make deploy"}`, "2. submit_task", "3. write_file"} {
+		if !strings.Contains(*output, want) {
+			t.Errorf("output should report %q:\n%s", want, *output)
+		}
+	}
+	if strings.Contains(*output, "read_file {") {
+		t.Errorf("read-only calls should not be reported:\n%s", *output)
+	}
+}
+
+func TestTaskRunner_SimulateSkill(t *testing.T) {
+	task, cfg, output := newSimulateFixture(t, TaskConfig{Skill: "deploy", Simulate: true})
+	skills := &recordingSkillRunner{}
+	cfg.SkillRunner = skills
+
+	if err := NewTaskRunner(task, cfg).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if skills.runs != 0 {
+		t.Error("simulated skill task ran the skill")
+	}
+	if task.Status != TaskCompleted || !strings.Contains(*output, "Skill deploy would run with:\n- request: Deploy the app") {
+		t.Errorf("status = %s, output:\n%s", task.Status, *output)
+	}
+}
+
+// outputStore captures the output written by the runner.
+type outputStore struct {
+	*mockStore
+	output string
+}
+
+func (s *outputStore) WriteOutput(_ string, output string) error {
+	s.output = output
+	return nil
+}
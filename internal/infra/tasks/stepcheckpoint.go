@@ -0,0 +1,156 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+)
+
+// checkpointStepProgress is the checkpoint type recording the sub-agent's
+// tool results and interim reasoning while a step runs.
+const checkpointStepProgress = "step_progress"
+
+const (
+	// maxStepCheckpointLen caps a single step progress checkpoint.
+	maxStepCheckpointLen = 2000
+	// maxResumeProgressLen caps the progress rehydrated into a resumed step
+	// (the most recent progress is kept).
+	maxResumeProgressLen = 12000
+	// overflowProgressLen caps the compacted progress a step resumes with
+	// after its run overflowed the model's context window.
+	overflowProgressLen = maxResumeProgressLen / 2
+)
+
+// stepCheckpointer checkpoints the progress of a running step, so that a
+// task interrupted by a crash or a preemption resumes the step with that
+// context instead of re-running all its tool calls.
+type stepCheckpointer struct {
+	store  Store
+	taskID string
+}
+
+// ToolResult checkpoints the result of a tool call.
+func (c *stepCheckpointer) ToolResult(tool, result string) {
+	c.append(fmt.Sprintf("[tool %s] %s", tool, result))
+}
+
+// AssistantText checkpoints an assistant message.
+func (c *stepCheckpointer) AssistantText(text string) {
+	c.append("[assistant] " + text)
+}
+
+func (c *stepCheckpointer) append(summary string) {
+	_ = c.store.AppendCheckpoint(c.taskID, Checkpoint{
+		Ts:      time.Now(),
+		Type:    checkpointStepProgress,
+		Summary: truncate(summary, maxStepCheckpointLen),
+	})
+}
+
+// resumeProgress returns the step progress recorded before the task was
+// interrupted (recovered after a crash or preempted), or "" when the task
+// has no progress to resume.
+func resumeProgress(store Store, taskID string) string {
+	cps, err := store.LoadCheckpoints(taskID)
+	if err != nil {
+		return ""
+	}
+
+	var progress []string
+	interrupted := false
+	for _, cp := range cps {
+		switch cp.Type {
+		case checkpointStepProgress:
+			progress = append(progress, cp.Summary)
+		case "recovery", "preempted":
+			interrupted = interrupted || len(progress) > 0
+		}
+	}
+	if !interrupted {
+		return ""
+	}
+	return recentProgress(progress, maxResumeProgressLen)
+}
+
+// recentProgress joins the most recent progress entries within budget.
+func recentProgress(progress []string, budget int) string {
+	size, start := 0, len(progress)
+	for start > 0 && size+len(progress[start-1]) <= budget {
+		start--
+		size += len(progress[start])
+	}
+	return strings.Join(progress[start:], "\n\n")
+}
+
+// overflowMessages returns the messages a step resumes with after its run
+// overflowed the model's context window: the task description followed by
+// the run's history (its checkpointed tool results and notes), summarized by
+// the compactor when it exceeds overflowProgressLen, or cut to its most
+// recent part. The agent reuses those results instead of repeating the tool
+// calls, which may have side effects.
+func (r *TaskRunner) overflowMessages(ctx context.Context, task *Task) []brain.Message {
+	messages := []brain.Message{
+		{Role: brain.RoleUser, Content: task.Description},
+	}
+	cps, err := r.store.LoadCheckpoints(task.ID)
+	if err != nil {
+		return messages
+	}
+	var progress []string
+	for _, cp := range cps {
+		if cp.Type == checkpointStepProgress {
+			progress = append(progress, cp.Summary)
+		}
+	}
+	if len(progress) == 0 {
+		return messages
+	}
+
+	history := strings.Join(progress, "\n\n")
+	if len(history) > overflowProgressLen {
+		compacted := ""
+		if r.compactor != nil {
+			summary, err := r.compactor.CompactContext(ctx, history, overflowProgressLen)
+			if err != nil {
+				slog.WarnContext(ctx, "task history compaction failed, keeping the most recent", "task_id", task.ID, "error", err)
+			} else {
+				compacted = strings.TrimSpace(summary)
+			}
+		}
+		if compacted == "" {
+			compacted = recentProgress(progress, overflowProgressLen)
+		}
+		_ = r.store.AppendCheckpoint(task.ID, Checkpoint{
+			Ts:      time.Now(),
+			Type:    "history_compacted",
+			Summary: fmt.Sprintf("Context overflowed the model window: run history compacted from %d to %d chars", len(history), len(compacted)),
+		})
+		history = compacted
+	}
+
+	return append(messages,
+		brain.Message{Role: brain.RoleAssistant, Content: "Progress so far (tool results and notes):\n\n" + history},
+		brain.Message{Role: brain.RoleUser, Content: "The conversation overflowed the context window and was compacted. Continue from where you left off: reuse the results above instead of repeating their tool calls."},
+	)
+}
+
+// initialMessages returns the messages a task's agent starts with: the task
+// description, followed on resume by the progress made before the
+// interruption.
+func (r *TaskRunner) initialMessages(task *Task) []brain.Message {
+	messages := []brain.Message{
+		{Role: brain.RoleUser, Content: task.Description},
+	}
+	progress := resumeProgress(r.store, task.ID)
+	if progress == "" {
+		return messages
+	}
+	return append(messages,
+		brain.Message{Role: brain.RoleAssistant, Content: "Progress before the interruption (tool results and notes):\n\n" + progress},
+		brain.Message{Role: brain.RoleUser, Content: "The task was interrupted. Continue from where you left off: reuse the results above instead of repeating their tool calls."},
+	)
+}
@@ -0,0 +1,173 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/core/brain"
+	"github.com/dohr-michael/ozzie/internal/core/events"
+)
+
+func TestResumeProgress(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	task := &Task{Title: "Audit", Description: "Audit the repo"}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := &stepCheckpointer{store: store, taskID: task.ID}
+	steps.ToolResult("run_command", "42 files")
+	steps.AssistantText("Now checking licenses")
+	if got := resumeProgress(store, task.ID); got != "" {
+		t.Errorf("uninterrupted task should not resume, got %q", got)
+	}
+
+	_ = store.AppendCheckpoint(task.ID, Checkpoint{Ts: time.Now(), Type: "recovery"})
+	got := resumeProgress(store, task.ID)
+	if !strings.Contains(got, "[tool run_command] 42 files") || !strings.Contains(got, "[assistant] Now checking licenses") {
+		t.Errorf("resume progress = %q", got)
+	}
+}
+
+func TestResumeProgress_KeepsMostRecent(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	task := &Task{Title: "Audit"}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := &stepCheckpointer{store: store, taskID: task.ID}
+	steps.ToolResult("first", "old")
+	for range maxResumeProgressLen / maxStepCheckpointLen {
+		steps.ToolResult("read_file", strings.Repeat("x", maxStepCheckpointLen))
+	}
+	steps.ToolResult("last", "new")
+	_ = store.AppendCheckpoint(task.ID, Checkpoint{Ts: time.Now(), Type: "preempted"})
+
+	got := resumeProgress(store, task.ID)
+	if strings.Contains(got, "[tool first]") || !strings.Contains(got, "[tool last] new") {
+		t.Errorf("resume progress should keep the most recent checkpoints")
+	}
+	if len(got) > maxResumeProgressLen+64 {
+		t.Errorf("resume progress too long: %d chars", len(got))
+	}
+}
+
+// progressRunnerFactory records the messages its runners start with and
+// reports a tool result before completing.
+type progressRunnerFactory struct {
+	opts     brain.RunnerOpts
+	messages []brain.Message
+}
+
+func (f *progressRunnerFactory) CreateRunner(_ context.Context, _ string, _ string, _ []brain.Tool, opts ...brain.RunnerOption) (brain.Runner, error) {
+	f.opts = brain.ApplyRunnerOpts(opts)
+	return f, nil
+}
+
+func (f *progressRunnerFactory) Run(_ context.Context, messages []brain.Message) (string, error) {
+	f.messages = messages
+	f.opts.OnToolResult("run_command", "build ok")
+	return "done", nil
+}
+
+func TestTaskRunner_ResumesStepProgress(t *testing.T) {
+	bus := events.NewBus(16)
+	defer bus.Close()
+	store := NewFileStore(t.TempDir())
+	task := &Task{Title: "Build", Description: "Build the project"}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+	factory := &progressRunnerFactory{}
+	cfg := TaskRunnerConfig{Store: store, Bus: bus, RunnerFactory: factory}
+
+	if err := NewTaskRunner(task, cfg).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(factory.messages) != 1 {
+		t.Fatalf("fresh task started with %d messages, want 1", len(factory.messages))
+	}
+
+	// Simulate a crash after the step progress was checkpointed.
+	_ = store.AppendCheckpoint(task.ID, Checkpoint{Ts: time.Now(), Type: "recovery"})
+	task.Status = TaskPending
+	if err := NewTaskRunner(task, cfg).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(factory.messages) != 3 || !strings.Contains(factory.messages[1].Content, "build ok") {
+		t.Errorf("resumed task should start with its prior progress, got %+v", factory.messages)
+	}
+}
+
+// sideEffectRunnerFactory creates runners whose first run sends an email
+// (a side-effecting tool call) and then overflows the context window.
+type sideEffectRunnerFactory struct {
+	runs     int
+	sent     int
+	messages [][]brain.Message
+}
+
+func (f *sideEffectRunnerFactory) CreateRunner(_ context.Context, _ string, _ string, _ []brain.Tool, opts ...brain.RunnerOption) (brain.Runner, error) {
+	return &sideEffectRunner{f: f, opts: brain.ApplyRunnerOpts(opts)}, nil
+}
+
+type sideEffectRunner struct {
+	f    *sideEffectRunnerFactory
+	opts brain.RunnerOpts
+}
+
+func (r *sideEffectRunner) Run(_ context.Context, messages []brain.Message) (string, error) {
+	r.f.runs++
+	r.f.messages = append(r.f.messages, messages)
+	for _, m := range messages {
+		if strings.Contains(m.Content, "[tool send_email] sent") {
+			return "done", nil
+		}
+	}
+	r.f.sent++
+	r.opts.OnToolResult("send_email", "sent")
+	return "", &brain.ErrContextOverflow{Cause: fmt.Errorf("prompt is too long")}
+}
+
+func TestTaskRunner_OverflowResumesFromCheckpoints(t *testing.T) {
+	bus := events.NewBus(16)
+	t.Cleanup(bus.Close)
+	store := NewFileStore(t.TempDir())
+	task := &Task{Title: "Notify", Description: "Email the team"}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+	factory := &sideEffectRunnerFactory{}
+	runner := NewTaskRunner(task, TaskRunnerConfig{Store: store, Bus: bus, RunnerFactory: factory})
+
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != TaskCompleted {
+		t.Fatalf("status = %s, want completed after resuming", task.Status)
+	}
+	if factory.runs != 2 || factory.sent != 1 {
+		t.Errorf("runs = %d, emails sent = %d; want 2 runs and a single email", factory.runs, factory.sent)
+	}
+}
+
+func TestTaskRunner_OverflowCompactsHistory(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	task := &Task{Title: "Notify", Description: "Email the team"}
+	if err := store.Create(task); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		_ = store.AppendCheckpoint(task.ID, Checkpoint{Type: checkpointStepProgress, Summary: strings.Repeat("x", maxStepCheckpointLen)})
+	}
+	runner := NewTaskRunner(task, TaskRunnerConfig{Store: store, Compactor: &stubCompactor{summary: "Read 10 files."}})
+
+	msgs := runner.overflowMessages(context.Background(), task)
+	if len(msgs) != 3 || !strings.Contains(msgs[1].Content, "Read 10 files.") {
+		t.Fatalf("expected the compacted history to be resumed, got %+v", msgs)
+	}
+}
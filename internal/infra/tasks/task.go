@@ -30,4 +30,6 @@ type TaskResult = brain.TaskResult
 type Task = brain.Task
 type Checkpoint = brain.Checkpoint
 type ListFilter = brain.ListFilter
+type CancelledTask = brain.CancelledTask
+type Artifact = brain.Artifact
 type ActorInfo = brain.ActorInfo
@@ -0,0 +1,170 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dohr-michael/ozzie/internal/infra/storage/dirstore"
+)
+
+// TaskTemplate is a named, reusable task definition. Its title and
+// description may contain ${var} placeholders filled at submit time.
+type TaskTemplate struct {
+	Name         string       `json:"name"`
+	Title        string       `json:"title,omitempty"`
+	Description  string       `json:"description"`
+	Priority     TaskPriority `json:"priority,omitempty"`
+	Config       TaskConfig   `json:"config"`
+	SourceTaskID string       `json:"source_task_id,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// Vars returns the placeholder names used by the template, in order of first use.
+func (t *TaskTemplate) Vars() []string {
+	var vars []string
+	for _, text := range []string{t.Title, t.Description} {
+		for _, m := range placeholderRe.FindAllStringSubmatch(text, -1) {
+			if !slices.Contains(vars, m[1]) {
+				vars = append(vars, m[1])
+			}
+		}
+	}
+	return vars
+}
+
+// TemplateFromTask builds a template named name from an existing task.
+// Session-bound settings (approvals) are not carried over.
+func TemplateFromTask(name string, task *Task) *TaskTemplate {
+	cfg := task.Config
+	cfg.ApprovedTools = nil
+	return &TaskTemplate{
+		Name:         name,
+		Title:        task.Title,
+		Description:  task.Description,
+		Priority:     task.Priority,
+		Config:       cfg,
+		SourceTaskID: task.ID,
+	}
+}
+
+var (
+	templateNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	placeholderRe  = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ValidateTemplateName checks that name is a lowercase slug (letters, digits, - and _).
+func ValidateTemplateName(name string) error {
+	if !templateNameRe.MatchString(name) {
+		return fmt.Errorf("invalid template name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ExpandPlaceholders replaces ${var} placeholders in text with vars. Other
+// "$" sequences (e.g. shell variables) are left untouched. Placeholders
+// without a value are reported as an error.
+func ExpandPlaceholders(text string, vars map[string]string) (string, error) {
+	var missing []string
+	out := placeholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		name := m[2 : len(m)-1]
+		v, ok := vars[name]
+		if !ok {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return m
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// TemplateStore persists task templates as directories with meta.json,
+// one directory per template name.
+type TemplateStore struct {
+	ds *dirstore.DirStore
+}
+
+// NewTemplateStore creates a TemplateStore rooted at baseDir.
+func NewTemplateStore(baseDir string) *TemplateStore {
+	return &TemplateStore{ds: dirstore.NewDirStore(baseDir, "template")}
+}
+
+// Save writes a template, replacing any template of the same name.
+func (s *TemplateStore) Save(tpl *TaskTemplate) error {
+	if err := ValidateTemplateName(tpl.Name); err != nil {
+		return err
+	}
+
+	s.ds.Lock()
+	defer s.ds.Unlock()
+
+	if tpl.CreatedAt.IsZero() {
+		tpl.CreatedAt = time.Now()
+	}
+	if err := s.ds.EnsureDir(tpl.Name); err != nil {
+		return err
+	}
+	return s.ds.WriteMeta(tpl.Name, tpl)
+}
+
+// Get reads a template by name.
+func (s *TemplateStore) Get(name string) (*TaskTemplate, error) {
+	if err := ValidateTemplateName(name); err != nil {
+		return nil, err
+	}
+
+	s.ds.RLock()
+	defer s.ds.RUnlock()
+
+	var tpl TaskTemplate
+	if err := s.ds.ReadMeta(name, &tpl); err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+// Delete removes a template.
+func (s *TemplateStore) Delete(name string) error {
+	if err := ValidateTemplateName(name); err != nil {
+		return err
+	}
+
+	s.ds.Lock()
+	defer s.ds.Unlock()
+
+	if _, err := os.Stat(s.ds.Dir(name)); err != nil {
+		return fmt.Errorf("template not found: %s", name)
+	}
+	return s.ds.RemoveDir(name)
+}
+
+// List returns all templates sorted by name.
+func (s *TemplateStore) List() ([]*TaskTemplate, error) {
+	s.ds.RLock()
+	defer s.ds.RUnlock()
+
+	dirs, err := s.ds.ListDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*TaskTemplate
+	for _, name := range dirs {
+		var tpl TaskTemplate
+		if err := s.ds.ReadMeta(name, &tpl); err != nil {
+			continue // skip corrupted entries
+		}
+		list = append(list, &tpl)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
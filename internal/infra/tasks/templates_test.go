@@ -0,0 +1,93 @@
+package tasks
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTemplateStore_CRUD(t *testing.T) {
+	store := NewTemplateStore(t.TempDir())
+
+	tpl := &TaskTemplate{
+		Name:        "deploy",
+		Title:       "Deploy ${service}",
+		Description: "deploy ${service} to ${env}",
+		Config:      TaskConfig{Tools: []string{"run_command"}, WorkDir: "/srv"},
+	}
+	if err := store.Save(tpl); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if tpl.CreatedAt.IsZero() {
+		t.Fatal("expected CreatedAt to be set")
+	}
+
+	got, err := store.Get("deploy")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Config.WorkDir != "/srv" || got.Description != tpl.Description {
+		t.Fatalf("unexpected template: %+v", got)
+	}
+
+	if err := store.Save(&TaskTemplate{Name: "backup", Description: "backup"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "backup" || list[1].Name != "deploy" {
+		t.Fatalf("expected [backup deploy], got %d entries", len(list))
+	}
+
+	if err := store.Delete("deploy"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get("deploy"); err == nil {
+		t.Fatal("expected error after delete")
+	}
+	if err := store.Delete("deploy"); err == nil {
+		t.Fatal("expected error deleting a missing template")
+	}
+}
+
+func TestTemplateStore_InvalidName(t *testing.T) {
+	store := NewTemplateStore(t.TempDir())
+	for _, name := range []string{"", "../x", "Deploy", "a/b", "-x"} {
+		if err := store.Save(&TaskTemplate{Name: name}); err == nil {
+			t.Errorf("expected error for name %q", name)
+		}
+	}
+}
+
+func TestExpandPlaceholders(t *testing.T) {
+	got, err := ExpandPlaceholders("deploy ${service} in $HOME (${service})", map[string]string{"service": "api"})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	if got != "deploy api in $HOME (api)" {
+		t.Errorf("got %q", got)
+	}
+
+	_, err = ExpandPlaceholders("${a} ${b} ${a}", map[string]string{"b": "x"})
+	if err == nil || !strings.Contains(err.Error(), "missing template variables: a") {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+}
+
+func TestTemplateFromTask(t *testing.T) {
+	task := &Task{
+		ID:          "task_1",
+		Title:       "Deploy",
+		Description: "deploy ${service}",
+		Config:      TaskConfig{Tools: []string{"run_command"}, ApprovedTools: []string{"run_command"}},
+	}
+	tpl := TemplateFromTask("deploy", task)
+	if tpl.SourceTaskID != "task_1" || tpl.Config.ApprovedTools != nil {
+		t.Errorf("unexpected template: %+v", tpl)
+	}
+	if !slices.Equal(tpl.Vars(), []string{"service"}) {
+		t.Errorf("vars = %v", tpl.Vars())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/dohr-michael/ozzie/internal/infra/i18n"
+	"github.com/dohr-michael/ozzie/pkg/editor"
 )
 
 // ToolCallStatus represents the state of a tool call.
@@ -29,6 +30,15 @@ type ToolCall struct {
 	Error     error
 	Status    ToolCallStatus
 	Completed bool
+
+	// Attempt / MaxAttempts are set when the call is a retry of a failed run.
+	Attempt     int
+	MaxAttempts int
+
+	// CallID / StreamedArgs hold the raw arguments of a call the model is
+	// still generating, before it starts.
+	CallID       string
+	StreamedArgs string
 }
 
 // ---------------------------------------------------------------------------
@@ -107,6 +117,31 @@ func RenderStreamingText(content string) string {
 	return content + SpinnerStyle.Render("▌")
 }
 
+// RenderStreamingMarkdown renders a partial assistant message as markdown.
+// A code fence still open is closed first so the block renders as code
+// rather than swallowing the rest of the layout on each update.
+func RenderStreamingMarkdown(content string, width int) string {
+	return RenderAssistantMessage(closeOpenFence(content), width)
+}
+
+// closeOpenFence appends a closing ``` when content has an unterminated
+// fenced code block.
+func closeOpenFence(content string) string {
+	open := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			open = !open
+		}
+	}
+	if !open {
+		return content
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "```"
+}
+
 // ---------------------------------------------------------------------------
 // Internal helpers
 // ---------------------------------------------------------------------------
@@ -158,21 +193,44 @@ func renderSingleTool(tool ToolCall, width int) string {
 	case ToolStatusDenied:
 		b.WriteString(ConfirmDeniedStyle.Render(i18n.T("chat.tool.denied")))
 	}
+	if tool.MaxAttempts > 0 && !tool.Completed {
+		b.WriteString(ToolArgsStyle.Render(fmt.Sprintf(i18n.T("chat.tool.retrying"), tool.Attempt, tool.MaxAttempts)))
+	}
+
+	// Arguments still being generated: their last lines
+	if tool.StreamedArgs != "" && !tool.Completed {
+		resultPrefix := ToolResultPrefixStyle.Render("  ⎿  ")
+		lines := strings.Split(wrapText(tool.StreamedArgs, width-6), "\n")
+		if len(lines) > streamedArgsMaxLines {
+			b.WriteString("\n" + resultPrefix + ToolArgsStyle.Render(fmt.Sprintf(i18n.T("chat.tool.more_lines"), len(lines)-streamedArgsMaxLines)))
+			lines = lines[len(lines)-streamedArgsMaxLines:]
+		}
+		for _, line := range lines {
+			b.WriteString("\n" + resultPrefix + ToolArgsStyle.Render(line))
+		}
+	}
 
 	// Result lines with ⎿ prefix
 	if tool.Completed && tool.Error == nil {
 		resultPrefix := ToolResultPrefixStyle.Render("  ⎿  ")
 		if tool.Result == "" {
 			b.WriteString("\n" + resultPrefix + ToolResultStyle.Render(i18n.T("chat.tool.no_output")))
+		} else if isDiffTool(tool.Name) && editor.IsUnifiedDiff(tool.Result) {
+			b.WriteString(renderDiffResult(tool.Result, resultPrefix))
 		} else {
 			result := wrapText(tool.Result, width-6)
 			lines := strings.Split(result, "\n")
+			highlighted := highlightFencedLines(lines)
 			maxLines := 10
 			for j, line := range lines {
 				if j >= maxLines {
 					b.WriteString("\n" + resultPrefix + ToolResultStyle.Render(fmt.Sprintf(i18n.T("chat.tool.more_lines"), len(lines)-maxLines)))
 					break
 				}
+				if h, ok := highlighted[j]; ok {
+					b.WriteString("\n" + resultPrefix + h)
+					continue
+				}
 				b.WriteString("\n" + resultPrefix + ToolResultStyle.Render(line))
 			}
 		}
@@ -187,6 +245,45 @@ func renderSingleTool(tool ToolCall, width int) string {
 	return b.String()
 }
 
+// streamedArgsMaxLines bounds the argument lines shown while a call is
+// being generated.
+const streamedArgsMaxLines = 5
+
+// diffMaxLines bounds the diff lines shown for a file-modifying tool.
+const diffMaxLines = 40
+
+// isDiffTool reports whether the tool returns a unified diff of its change.
+func isDiffTool(name string) bool {
+	return name == "write_file" || name == "edit_file"
+}
+
+// renderDiffResult renders a tool result containing a unified diff, coloring
+// added, removed and hunk header lines. Lines are not wrapped so the diff
+// markers stay aligned.
+func renderDiffResult(result, prefix string) string {
+	var b strings.Builder
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	for j, line := range lines {
+		if j >= diffMaxLines {
+			b.WriteString("\n" + prefix + ToolResultStyle.Render(fmt.Sprintf(i18n.T("chat.tool.more_lines"), len(lines)-diffMaxLines)))
+			break
+		}
+		style := ToolResultStyle
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			style = ToolArgsStyle
+		case strings.HasPrefix(line, "@@"):
+			style = DiffHunkStyle
+		case strings.HasPrefix(line, "+"):
+			style = DiffAddStyle
+		case strings.HasPrefix(line, "-"):
+			style = DiffRemoveStyle
+		}
+		b.WriteString("\n" + prefix + style.Render(line))
+	}
+	return b.String()
+}
+
 // wrapText wraps text to the specified width.
 func wrapText(text string, width int) string {
 	if width <= 0 {
@@ -0,0 +1,22 @@
+package components
+
+import "testing"
+
+func TestCloseOpenFence(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"no fence", "# Title\n- item", "# Title\n- item"},
+		{"closed fence", "```go\nx := 1\n```\ndone", "```go\nx := 1\n```\ndone"},
+		{"open fence", "text\n```go\nx := 1", "text\n```go\nx := 1\n```"},
+		{"open fence ending with newline", "```\nls\n", "```\nls\n```"},
+		{"second fence open", "```\na\n```\n  ```sh\nb", "```\na\n```\n  ```sh\nb\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closeOpenFence(tt.in); got != tt.want {
+				t.Errorf("closeOpenFence(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package components
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/glamour/ansi"
+)
+
+// Syntax highlighting of fenced code blocks, in assistant markdown (through
+// glamour) and in tool results. Colors follow the code block palette of
+// customStyleConfig and are limited to what the terminal supports.
+var (
+	highlightMu      sync.RWMutex
+	highlightEnabled = true
+	highlightProfile = sync.OnceValue(func() colorprofile.Profile {
+		return colorprofile.Detect(os.Stdout, os.Environ())
+	})
+	codeStyle = sync.OnceValue(newCodeStyle)
+)
+
+// SetSyntaxHighlighting enables or disables code highlighting (default:
+// enabled). Disabled, code blocks render as plain text.
+func SetSyntaxHighlighting(enabled bool) {
+	highlightMu.Lock()
+	highlightEnabled = enabled
+	highlightMu.Unlock()
+}
+
+// chromaFormatter returns the chroma formatter matching the terminal's color
+// capability, or "" when code must not be highlighted.
+func chromaFormatter() string {
+	highlightMu.RLock()
+	enabled := highlightEnabled
+	highlightMu.RUnlock()
+	if !enabled {
+		return ""
+	}
+	return formatterForProfile(highlightProfile())
+}
+
+func formatterForProfile(p colorprofile.Profile) string {
+	switch p {
+	case colorprofile.TrueColor:
+		return "terminal16m"
+	case colorprofile.ANSI256:
+		return "terminal256"
+	case colorprofile.ANSI:
+		return "terminal16"
+	default: // no colors
+		return ""
+	}
+}
+
+// markdownStyle returns the glamour style, without code highlighting when
+// the formatter is empty.
+func markdownStyle(formatter string) ansi.StyleConfig {
+	style := customStyleConfig()
+	if formatter == "" {
+		style.CodeBlock.Chroma = nil
+	}
+	return style
+}
+
+// highlightFencedLines highlights the code inside ``` fences of a text split
+// in lines, for the fences whose info string names a known language. It
+// returns the highlighted lines by index; other lines are left to the caller.
+func highlightFencedLines(lines []string) map[int]string {
+	formatter := chromaFormatter()
+	if formatter == "" {
+		return nil
+	}
+
+	out := map[int]string{}
+	inFence, start := false, 0
+	var lexer chroma.Lexer
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		if !inFence {
+			inFence, start = true, i+1
+			lexer = nil
+			if fields := strings.Fields(strings.TrimPrefix(trimmed, "```")); len(fields) > 0 {
+				lexer = lexers.Get(fields[0])
+			}
+			continue
+		}
+		inFence = false
+		if lexer != nil {
+			highlightBlock(out, lines[start:i], start, lexer, formatter)
+		}
+	}
+	// Unterminated fence (e.g. a truncated result): highlight to the end.
+	if inFence && lexer != nil {
+		highlightBlock(out, lines[start:], start, lexer, formatter)
+	}
+	return out
+}
+
+// highlightBlock highlights code lines and stores them in out from offset.
+// On failure the lines stay plain.
+func highlightBlock(out map[int]string, code []string, offset int, lexer chroma.Lexer, formatter string) {
+	if len(code) == 0 {
+		return
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, strings.Join(code, "\n"))
+	if err != nil {
+		return
+	}
+	// Format line by line so every line carries its own escape sequences.
+	f, style := formatters.Get(formatter), codeStyle()
+	tokenLines := chroma.SplitTokensIntoLines(it.Tokens())
+	if len(tokenLines) < len(code) {
+		return
+	}
+	highlighted := make([]string, len(code))
+	for i := range code {
+		var b strings.Builder
+		if err := f.Format(&b, style, chroma.Literator(tokenLines[i]...)); err != nil {
+			return
+		}
+		highlighted[i] = strings.TrimSuffix(b.String(), "\n")
+	}
+	for i, line := range highlighted {
+		out[offset+i] = line
+	}
+}
+
+// newCodeStyle converts the code block palette of customStyleConfig into a
+// chroma style.
+func newCodeStyle() *chroma.Style {
+	c := customStyleConfig().CodeBlock.Chroma
+	entries := chroma.StyleEntries{
+		chroma.Text:                chromaEntry(c.Text),
+		chroma.Error:               chromaEntry(c.Error),
+		chroma.Comment:             chromaEntry(c.Comment),
+		chroma.CommentPreproc:      chromaEntry(c.CommentPreproc),
+		chroma.Keyword:             chromaEntry(c.Keyword),
+		chroma.KeywordReserved:     chromaEntry(c.KeywordReserved),
+		chroma.KeywordNamespace:    chromaEntry(c.KeywordNamespace),
+		chroma.KeywordType:         chromaEntry(c.KeywordType),
+		chroma.Operator:            chromaEntry(c.Operator),
+		chroma.Punctuation:         chromaEntry(c.Punctuation),
+		chroma.Name:                chromaEntry(c.Name),
+		chroma.NameBuiltin:         chromaEntry(c.NameBuiltin),
+		chroma.NameTag:             chromaEntry(c.NameTag),
+		chroma.NameAttribute:       chromaEntry(c.NameAttribute),
+		chroma.NameClass:           chromaEntry(c.NameClass),
+		chroma.NameConstant:        chromaEntry(c.NameConstant),
+		chroma.NameDecorator:       chromaEntry(c.NameDecorator),
+		chroma.NameFunction:        chromaEntry(c.NameFunction),
+		chroma.LiteralNumber:       chromaEntry(c.LiteralNumber),
+		chroma.LiteralString:       chromaEntry(c.LiteralString),
+		chroma.LiteralStringEscape: chromaEntry(c.LiteralStringEscape),
+		chroma.GenericDeleted:      chromaEntry(c.GenericDeleted),
+		chroma.GenericEmph:         chromaEntry(c.GenericEmph),
+		chroma.GenericInserted:     chromaEntry(c.GenericInserted),
+		chroma.GenericStrong:       chromaEntry(c.GenericStrong),
+		chroma.GenericSubheading:   chromaEntry(c.GenericSubheading),
+	}
+	style, err := chroma.NewStyle("ozzie", entries)
+	if err != nil {
+		return chroma.MustNewStyle("ozzie", chroma.StyleEntries{})
+	}
+	return style
+}
+
+// chromaEntry converts a glamour style primitive into a chroma style entry.
+func chromaEntry(p ansi.StylePrimitive) string {
+	var parts []string
+	if p.Color != nil {
+		parts = append(parts, *p.Color)
+	}
+	if p.Bold != nil && *p.Bold {
+		parts = append(parts, "bold")
+	}
+	if p.Italic != nil && *p.Italic {
+		parts = append(parts, "italic")
+	}
+	return strings.Join(parts, " ")
+}
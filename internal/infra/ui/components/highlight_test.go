@@ -0,0 +1,65 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/colorprofile"
+)
+
+// withProfile forces the detected terminal color profile for a test.
+func withProfile(t *testing.T, p colorprofile.Profile) {
+	t.Helper()
+	prev := highlightProfile
+	highlightProfile = func() colorprofile.Profile { return p }
+	t.Cleanup(func() { highlightProfile = prev })
+}
+
+func TestFormatterForProfile(t *testing.T) {
+	tests := []struct {
+		profile colorprofile.Profile
+		want    string
+	}{
+		{colorprofile.TrueColor, "terminal16m"},
+		{colorprofile.ANSI256, "terminal256"},
+		{colorprofile.ANSI, "terminal16"},
+		{colorprofile.ASCII, ""},
+		{colorprofile.NoTTY, ""},
+	}
+	for _, tt := range tests {
+		if got := formatterForProfile(tt.profile); got != tt.want {
+			t.Errorf("formatterForProfile(%v) = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightFencedLines(t *testing.T) {
+	withProfile(t, colorprofile.ANSI256)
+	lines := strings.Split("intro\n```go\nfunc main() {}\n```\n```nosuchlang\nplain\n```\n```python\nx = 1", "\n")
+
+	got := highlightFencedLines(lines)
+	if h, ok := got[2]; !ok || !strings.Contains(h, "\x1b[") || !strings.Contains(h, "func") {
+		t.Errorf("go line not highlighted: %q", h)
+	}
+	for _, i := range []int{0, 1, 3, 4, 5, 6} {
+		if h, ok := got[i]; ok {
+			t.Errorf("line %d (%q) should stay plain, got %q", i, lines[i], h)
+		}
+	}
+	if _, ok := got[8]; !ok {
+		t.Error("unterminated fence with a known language should be highlighted")
+	}
+
+	SetSyntaxHighlighting(false)
+	defer SetSyntaxHighlighting(true)
+	if got := highlightFencedLines(lines); len(got) != 0 {
+		t.Errorf("highlighting disabled, got %v", got)
+	}
+}
+
+func TestHighlightFencedLines_NoColors(t *testing.T) {
+	withProfile(t, colorprofile.ASCII)
+	if got := highlightFencedLines([]string{"```go", "func main() {}", "```"}); len(got) != 0 {
+		t.Errorf("terminal without colors, got %v", got)
+	}
+}
@@ -11,6 +11,13 @@ import (
 	"github.com/dohr-michael/ozzie/internal/infra/i18n"
 )
 
+const (
+	// maxInputHistory bounds the number of chat messages kept for recall.
+	maxInputHistory = 100
+	// maxPaletteItems bounds the number of commands shown by the palette.
+	maxPaletteItems = 8
+)
+
 // InputMode represents the current input mode.
 type InputMode int
 
@@ -57,6 +64,9 @@ type PromptConfig struct {
 	Required    bool          // Whether the field is required
 	Validation  string        // Regex validation pattern
 	Options     []InputOption // Options (for select/multi)
+	Defaults    []string      // Pre-checked values (for multi)
+	MinSelect   int           // Minimum checked options (for multi, 0 = no minimum)
+	MaxSelect   int           // Maximum checked options (for multi, 0 = no maximum)
 	ResumeToken string        // Token for resuming agent
 }
 
@@ -72,6 +82,7 @@ type InputZone struct {
 	field       string // Field name for prompts
 	resumeToken string
 	required    bool
+	keys        *KeyMap
 
 	// Text input
 	textInput  textinput.Model
@@ -82,10 +93,21 @@ type InputZone struct {
 	options     []InputOption
 	selectIdx   int
 	multiSelect map[int]bool
+	minSelect   int // 0 = no minimum
+	maxSelect   int // 0 = no maximum
 
 	// Completed fields (for workflow display)
 	completedFields []CompletedField
 
+	// Chat history (ModeChat up/down recall, oldest first)
+	history    []string
+	historyIdx int    // index into history while navigating, len(history) otherwise
+	draft      string // partial input saved when navigation starts
+
+	// Command palette (ModeChat, opens on a leading "/")
+	commands   []InputOption
+	paletteIdx int
+
 	// Confirm state (uses selectIdx: 0=Yes, 1=No)
 }
 
@@ -99,6 +121,7 @@ func NewInputZone() *InputZone {
 
 	return &InputZone{
 		mode:        ModeChat,
+		keys:        DefaultKeyMap(),
 		textInput:   ti,
 		multiSelect: make(map[int]bool),
 	}
@@ -124,20 +147,54 @@ func (z *InputZone) Update(msg tea.Msg) (*InputZone, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
-		case "enter":
+		action := z.keys.Action(msg)
+		if (z.mode == ModeChat || z.mode == ModeText) && msg.Text != "" {
+			action = "" // printable keys are typed, even when remapped (e.g. vim-style j/k)
+		}
+
+		switch action {
+		case KeySubmit:
+			if z.mode == ModeChat {
+				if matches := z.paletteMatches(); len(matches) > 0 {
+					z.setChatValue(matches[z.paletteIdx].Value)
+				}
+			}
 			return z.submit()
-		case "esc":
+		case KeyComplete:
+			if z.mode == ModeChat {
+				if matches := z.paletteMatches(); len(matches) > 0 {
+					z.setChatValue(matches[z.paletteIdx].Value + " ")
+					z.paletteIdx = 0
+				}
+				return z, nil
+			}
+		case KeyCancel:
 			if z.mode != ModeChat {
 				return z.cancel()
 			}
-		case "up":
+		case KeyUp:
+			if z.mode == ModeChat {
+				if len(z.paletteMatches()) > 0 {
+					z.paletteIdx = max(z.paletteIdx-1, 0)
+				} else {
+					z.recallPrev()
+				}
+				return z, nil
+			}
 			if z.mode == ModeSelect || z.mode == ModeMulti || z.mode == ModeConfirm {
 				if z.selectIdx > 0 {
 					z.selectIdx--
 				}
 			}
-		case "down":
+		case KeyDown:
+			if z.mode == ModeChat {
+				if matches := z.paletteMatches(); len(matches) > 0 {
+					z.paletteIdx = min(z.paletteIdx+1, len(matches)-1)
+				} else {
+					z.recallNext()
+				}
+				return z, nil
+			}
 			if z.mode == ModeSelect || z.mode == ModeMulti || z.mode == ModeConfirm {
 				maxIdx := len(z.options) - 1
 				if z.mode == ModeConfirm {
@@ -147,16 +204,16 @@ func (z *InputZone) Update(msg tea.Msg) (*InputZone, tea.Cmd) {
 					z.selectIdx++
 				}
 			}
-		case " ":
+		case KeyToggle:
 			if z.mode == ModeMulti {
-				z.multiSelect[z.selectIdx] = !z.multiSelect[z.selectIdx]
+				z.toggleMulti()
 			}
-		case "y", "Y":
+		case KeyConfirmYes:
 			if z.mode == ModeConfirm {
 				z.selectIdx = 0
 				return z.submit()
 			}
-		case "n", "N":
+		case KeyConfirmNo:
 			if z.mode == ModeConfirm {
 				z.selectIdx = 1
 				return z.submit()
@@ -165,8 +222,12 @@ func (z *InputZone) Update(msg tea.Msg) (*InputZone, tea.Cmd) {
 
 		// Update text input for text modes (only for key messages)
 		if z.mode == ModeChat || z.mode == ModeText {
+			before := z.textInput.Value()
 			var cmd tea.Cmd
 			z.textInput, cmd = z.textInput.Update(msg)
+			if z.textInput.Value() != before {
+				z.paletteIdx = 0 // the filter changed
+			}
 			z.validate()
 			return z, cmd
 		}
@@ -185,7 +246,7 @@ func (z *InputZone) Update(msg tea.Msg) (*InputZone, tea.Cmd) {
 		z.PromptSelect(msg.Question, msg.Field, "", msg.Options, msg.Default)
 
 	case InputPromptMultiMsg:
-		z.PromptMulti(msg.Question, msg.Field, "", msg.Options)
+		z.PromptMulti(msg.Question, msg.Field, "", msg.Options, msg.Defaults, msg.MinSelect, msg.MaxSelect)
 
 	case InputResetMsg:
 		z.Reset()
@@ -209,6 +270,7 @@ func (z *InputZone) submit() (*InputZone, tea.Cmd) {
 			return z, nil
 		}
 		result.Text = text
+		z.AddHistory(text)
 		z.textInput.SetValue("")
 		return z, func() tea.Msg { return result }
 
@@ -242,6 +304,11 @@ func (z *InputZone) submit() (*InputZone, tea.Cmd) {
 				selected = append(selected, opt.Value)
 			}
 		}
+		if len(selected) < z.minSelect {
+			z.errorMsg = fmt.Sprintf(i18n.T("input.error.min_select"), z.minSelect)
+			return z, nil
+		}
+		z.errorMsg = ""
 		result.MultiSelect = selected
 		// Don't reset - preserve completedFields for workflow
 		return z, func() tea.Msg { return result }
@@ -256,6 +323,122 @@ func (z *InputZone) submit() (*InputZone, tea.Cmd) {
 	return z, nil
 }
 
+// toggleMulti flips the option under the cursor, refusing to check more than maxSelect options.
+func (z *InputZone) toggleMulti() {
+	if !z.multiSelect[z.selectIdx] && z.maxSelect > 0 && z.checkedCount() >= z.maxSelect {
+		z.errorMsg = fmt.Sprintf(i18n.T("input.error.max_select"), z.maxSelect)
+		return
+	}
+	z.multiSelect[z.selectIdx] = !z.multiSelect[z.selectIdx]
+	z.errorMsg = ""
+}
+
+// checkedCount returns the number of checked options.
+func (z *InputZone) checkedCount() int {
+	n := 0
+	for _, checked := range z.multiSelect {
+		if checked {
+			n++
+		}
+	}
+	return n
+}
+
+// AddHistory appends a submitted chat message to the recall history and ends
+// any navigation in progress. Consecutive duplicates are stored once.
+func (z *InputZone) AddHistory(text string) {
+	if text != "" && (len(z.history) == 0 || z.history[len(z.history)-1] != text) {
+		z.history = append(z.history, text)
+		if len(z.history) > maxInputHistory {
+			z.history = z.history[len(z.history)-maxInputHistory:]
+		}
+	}
+	z.historyIdx = len(z.history)
+	z.draft = ""
+}
+
+// recallPrev replaces the input with the previous history entry, saving the
+// partial input when navigation starts.
+func (z *InputZone) recallPrev() {
+	if z.historyIdx == 0 || len(z.history) == 0 {
+		return
+	}
+	if z.historyIdx >= len(z.history) {
+		z.historyIdx = len(z.history)
+		z.draft = z.textInput.Value()
+	}
+	z.historyIdx--
+	z.setChatValue(z.history[z.historyIdx])
+}
+
+// recallNext moves towards the newest history entry, restoring the saved
+// partial input when moving past it.
+func (z *InputZone) recallNext() {
+	if z.historyIdx >= len(z.history) {
+		return
+	}
+	z.historyIdx++
+	if z.historyIdx == len(z.history) {
+		z.setChatValue(z.draft)
+		z.draft = ""
+		return
+	}
+	z.setChatValue(z.history[z.historyIdx])
+}
+
+// SetCommands sets the slash commands offered by the command palette.
+func (z *InputZone) SetCommands(commands []InputOption) {
+	z.commands = commands
+	z.paletteIdx = 0
+}
+
+// paletteMatches returns the commands matching the chat input while it is a
+// bare "/command" (no arguments yet): prefix matches first, then fuzzy
+// (subsequence) matches, at most maxPaletteItems of them.
+func (z *InputZone) paletteMatches() []InputOption {
+	value := z.textInput.Value()
+	if z.mode != ModeChat || !strings.HasPrefix(value, "/") || strings.ContainsAny(value, " \t") {
+		return nil
+	}
+	query := strings.ToLower(value)
+	var prefix, fuzzy []InputOption
+	for _, c := range z.commands {
+		name := strings.ToLower(c.Value)
+		switch {
+		case strings.HasPrefix(name, query):
+			prefix = append(prefix, c)
+		case isSubsequence(query, name):
+			fuzzy = append(fuzzy, c)
+		}
+	}
+	matches := append(prefix, fuzzy...)
+	if len(matches) > maxPaletteItems {
+		matches = matches[:maxPaletteItems]
+	}
+	if z.paletteIdx >= len(matches) {
+		z.paletteIdx = max(len(matches)-1, 0)
+	}
+	return matches
+}
+
+// isSubsequence reports whether the runes of query appear in order in s.
+func isSubsequence(query, s string) bool {
+	rest := s
+	for _, r := range query {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(string(r)):]
+	}
+	return true
+}
+
+func (z *InputZone) setChatValue(text string) {
+	z.textInput.SetValue(text)
+	z.textInput.CursorEnd()
+}
+
 // cancel cancels the current prompt.
 func (z *InputZone) cancel() (*InputZone, tea.Cmd) {
 	result := InputResult{
@@ -333,7 +516,31 @@ func (z *InputZone) renderDisabled() string {
 
 func (z *InputZone) renderChat() string {
 	sep := z.separator()
-	return sep + "\n" + InputPromptCharStyle.Render("❯ ") + z.textInput.View() + "\n" + sep
+	return sep + "\n" + z.renderPalette() + InputPromptCharStyle.Render("❯ ") + z.textInput.View() + "\n" + sep
+}
+
+// renderPalette renders the commands matching a leading "/" above the chat input.
+func (z *InputZone) renderPalette() string {
+	matches := z.paletteMatches()
+	if len(matches) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, c := range matches {
+		if i == z.paletteIdx {
+			b.WriteString(SelectedOptionStyle.Render("> " + c.Label))
+		} else {
+			b.WriteString(OptionStyle.Render("  " + c.Label))
+		}
+		if c.Description != "" {
+			b.WriteString(DescriptionStyle.Render(" - " + c.Description))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(z.renderHint(i18n.T("hint.palette")))
+	b.WriteString("\n")
+	return b.String()
 }
 
 // renderCompletedFields renders the completed workflow fields inline.
@@ -442,11 +649,36 @@ func (z *InputZone) renderMulti() string {
 		b.WriteString("\n")
 	}
 
+	// Error
+	if z.errorMsg != "" {
+		b.WriteString(ErrorStyle.Render("  " + z.errorMsg))
+		b.WriteString("\n")
+	}
+
+	if bounds := z.selectBoundsHint(); bounds != "" {
+		b.WriteString(z.renderHint(bounds))
+		b.WriteString("\n")
+	}
 	b.WriteString(z.renderHint(i18n.T("hint.multi")))
 
 	return z.wrapWithSeparators(b.String())
 }
 
+// selectBoundsHint describes the MinSelect/MaxSelect constraints, e.g. "select 2–3".
+func (z *InputZone) selectBoundsHint() string {
+	switch {
+	case z.minSelect > 0 && z.maxSelect > 0 && z.minSelect == z.maxSelect:
+		return fmt.Sprintf(i18n.T("hint.select.exact"), z.minSelect)
+	case z.minSelect > 0 && z.maxSelect > 0:
+		return fmt.Sprintf(i18n.T("hint.select.range"), z.minSelect, z.maxSelect)
+	case z.minSelect > 0:
+		return fmt.Sprintf(i18n.T("hint.select.min"), z.minSelect)
+	case z.maxSelect > 0:
+		return fmt.Sprintf(i18n.T("hint.select.max"), z.maxSelect)
+	}
+	return ""
+}
+
 func (z *InputZone) renderConfirm() string {
 	var b strings.Builder
 
@@ -482,9 +714,13 @@ func (z *InputZone) Reset() {
 	z.options = nil
 	z.selectIdx = 0
 	z.multiSelect = make(map[int]bool)
+	z.minSelect = 0
+	z.maxSelect = 0
 	z.validation = nil
 	z.errorMsg = ""
 	z.completedFields = nil
+	z.historyIdx = len(z.history)
+	z.draft = ""
 	z.textInput.SetValue("")
 	z.textInput.Placeholder = i18n.T("input.placeholder.chat")
 }
@@ -511,6 +747,11 @@ func (z *InputZone) SetDisabled(disabled bool) {
 	z.disabled = disabled
 }
 
+// SetKeyMap replaces the key bindings.
+func (z *InputZone) SetKeyMap(keys *KeyMap) {
+	z.keys = keys
+}
+
 // Focus focuses the text input.
 func (z *InputZone) Focus() tea.Cmd {
 	return z.textInput.Focus()
@@ -567,27 +808,34 @@ func (z *InputZone) PromptSelect(question, field, resumeToken string, options []
 	z.textInput.Blur()
 }
 
-// PromptMulti sets up a multi-select prompt.
-func (z *InputZone) PromptMulti(question, field, resumeToken string, options []InputOption) {
+// PromptMulti sets up a multi-select prompt. Options whose Value is in
+// defaults start checked; minSelect and maxSelect bound the number of
+// checked options (0 = unbounded).
+func (z *InputZone) PromptMulti(question, field, resumeToken string, options []InputOption, defaults []string, minSelect, maxSelect int) {
 	z.mode = ModeMulti
 	z.question = question
 	z.field = field
 	z.resumeToken = resumeToken
+	z.setupMulti(options, defaults, minSelect, maxSelect)
+	z.textInput.Blur()
+}
+
+// setupMulti resets the multi-select state and pre-checks the defaults.
+// Defaults beyond maxSelect are ignored.
+func (z *InputZone) setupMulti(options []InputOption, defaults []string, minSelect, maxSelect int) {
 	z.options = options
 	z.selectIdx = 0
+	z.minSelect = minSelect
+	z.maxSelect = maxSelect
+	z.errorMsg = ""
 	z.multiSelect = make(map[int]bool)
-	z.textInput.Blur()
-}
 
-// PreSelectMulti pre-checks options whose Value is in the given set.
-// Must be called after PromptMulti.
-func (z *InputZone) PreSelectMulti(values []string) {
-	set := make(map[string]bool, len(values))
-	for _, v := range values {
+	set := make(map[string]bool, len(defaults))
+	for _, v := range defaults {
 		set[v] = true
 	}
-	for i, opt := range z.options {
-		if set[opt.Value] {
+	for i, opt := range options {
+		if set[opt.Value] && (maxSelect <= 0 || z.checkedCount() < maxSelect) {
 			z.multiSelect[i] = true
 		}
 	}
@@ -637,9 +885,7 @@ func (z *InputZone) Prompt(mode InputMode, cfg PromptConfig) {
 		z.textInput.Blur()
 
 	case ModeMulti:
-		z.options = cfg.Options
-		z.selectIdx = 0
-		z.multiSelect = make(map[int]bool)
+		z.setupMulti(cfg.Options, cfg.Defaults, cfg.MinSelect, cfg.MaxSelect)
 		z.textInput.Blur()
 
 	case ModeConfirm:
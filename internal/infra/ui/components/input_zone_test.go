@@ -0,0 +1,195 @@
+package components
+
+import (
+	"slices"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func multiOptions(values ...string) []InputOption {
+	opts := make([]InputOption, len(values))
+	for i, v := range values {
+		opts[i] = InputOption{Value: v, Label: v}
+	}
+	return opts
+}
+
+func TestPromptMulti_Defaults(t *testing.T) {
+	z := NewInputZone()
+	z.PromptMulti("Pick", "f", "", multiOptions("a", "b", "c"), []string{"c", "a", "unknown"}, 0, 0)
+
+	_, cmd := z.submit()
+	if cmd == nil {
+		t.Fatal("expected submit to produce a result")
+	}
+	res := cmd().(InputResult)
+	if !slices.Equal(res.MultiSelect, []string{"a", "c"}) {
+		t.Errorf("selected = %v, want [a c]", res.MultiSelect)
+	}
+}
+
+func TestPromptMulti_MinSelect(t *testing.T) {
+	z := NewInputZone()
+	z.PromptMulti("Pick", "f", "", multiOptions("a", "b", "c"), nil, 2, 0)
+
+	if _, cmd := z.submit(); cmd != nil {
+		t.Fatal("submit below the minimum should be blocked")
+	}
+	if z.errorMsg == "" {
+		t.Error("expected an error message below the minimum")
+	}
+
+	z.toggleMulti()
+	z.selectIdx = 1
+	z.toggleMulti()
+	if z.errorMsg != "" {
+		t.Errorf("toggling should clear the error, got %q", z.errorMsg)
+	}
+	if _, cmd := z.submit(); cmd == nil {
+		t.Error("submit at the minimum should succeed")
+	}
+}
+
+func TestPromptMulti_MaxSelect(t *testing.T) {
+	z := NewInputZone()
+	z.PromptMulti("Pick", "f", "", multiOptions("a", "b", "c"), []string{"a", "b", "c"}, 0, 2)
+
+	if got := z.checkedCount(); got != 2 {
+		t.Fatalf("defaults beyond the maximum should be ignored, checked = %d", got)
+	}
+
+	z.selectIdx = 2
+	z.toggleMulti()
+	if z.multiSelect[2] {
+		t.Error("toggle above the maximum should be blocked")
+	}
+
+	z.selectIdx = 0
+	z.toggleMulti() // uncheck is always allowed
+	if z.multiSelect[0] {
+		t.Error("unchecking should be allowed at the maximum")
+	}
+}
+
+func TestSelectBoundsHint(t *testing.T) {
+	z := NewInputZone()
+	tests := []struct {
+		min, max int
+		want     bool
+	}{
+		{0, 0, false},
+		{2, 0, true},
+		{0, 3, true},
+		{2, 3, true},
+		{2, 2, true},
+	}
+	for _, tt := range tests {
+		z.Prompt(ModeMulti, PromptConfig{Options: multiOptions("a"), MinSelect: tt.min, MaxSelect: tt.max})
+		if got := z.selectBoundsHint() != ""; got != tt.want {
+			t.Errorf("min=%d max=%d: hint present = %v, want %v", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestInputHistory_Recall(t *testing.T) {
+	z := NewInputZone()
+	z.AddHistory("first")
+	z.AddHistory("second")
+	z.AddHistory("second") // consecutive duplicate
+	z.textInput.SetValue("draft")
+
+	z.recallPrev()
+	if got := z.textInput.Value(); got != "second" {
+		t.Fatalf("up = %q, want second", got)
+	}
+	z.recallPrev()
+	z.recallPrev() // stays on the oldest entry
+	if got := z.textInput.Value(); got != "first" {
+		t.Fatalf("up x3 = %q, want first", got)
+	}
+	z.recallNext()
+	z.recallNext()
+	if got := z.textInput.Value(); got != "draft" {
+		t.Errorf("down past newest = %q, want the saved draft", got)
+	}
+}
+
+func TestInputHistory_SubmitResetsNavigation(t *testing.T) {
+	z := NewInputZone()
+	for i := range maxInputHistory + 5 {
+		z.AddHistory(string(rune('a' + i%26)))
+	}
+	if len(z.history) != maxInputHistory {
+		t.Fatalf("history len = %d, want %d", len(z.history), maxInputHistory)
+	}
+
+	z.recallPrev()
+	z.recallPrev()
+	z.textInput.SetValue("new message")
+	if _, cmd := z.submit(); cmd == nil {
+		t.Fatal("expected submit to produce a result")
+	}
+	if z.history[len(z.history)-1] != "new message" {
+		t.Errorf("newest entry = %q, want the submitted message", z.history[len(z.history)-1])
+	}
+	z.recallPrev()
+	if got := z.textInput.Value(); got != "new message" {
+		t.Errorf("up after submit = %q, want the submitted message", got)
+	}
+}
+
+func TestCommandPalette_Matches(t *testing.T) {
+	z := NewInputZone()
+	z.SetCommands(multiOptions("/audit", "/model", "/quit", "/task-graph"))
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"hello", nil},
+		{"/", []string{"/audit", "/model", "/quit", "/task-graph"}},
+		{"/m", []string{"/model"}},
+		{"/tg", []string{"/task-graph"}},          // fuzzy
+		{"/a", []string{"/audit", "/task-graph"}}, // prefix first
+		{"/audit 5", nil},                         // arguments close the palette
+		{"/nope", nil},
+	}
+	for _, tt := range tests {
+		z.textInput.SetValue(tt.input)
+		var got []string
+		for _, m := range z.paletteMatches() {
+			got = append(got, m.Value)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: matches = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCommandPalette_CompleteAndSubmit(t *testing.T) {
+	z := NewInputZone()
+	z.SetCommands(multiOptions("/audit", "/model"))
+
+	z.textInput.SetValue("/")
+	z.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	z.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if got := z.textInput.Value(); got != "/model " {
+		t.Fatalf("tab = %q, want %q", got, "/model ")
+	}
+
+	z.textInput.SetValue("/au")
+	_, cmd := z.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter to submit")
+	}
+	if res := cmd().(InputResult); res.Text != "/audit" {
+		t.Errorf("enter submitted %q, want /audit", res.Text)
+	}
+
+	z.textInput.SetValue("/unknown")
+	_, cmd = z.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if res := cmd().(InputResult); res.Text != "/unknown" {
+		t.Errorf("unmatched input submitted %q, want it verbatim", res.Text)
+	}
+}
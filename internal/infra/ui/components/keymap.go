@@ -0,0 +1,109 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// KeyAction names a remappable TUI action.
+type KeyAction string
+
+const (
+	KeyQuit       KeyAction = "quit"        // Exit the TUI
+	KeySubmit     KeyAction = "submit"      // Send the message / submit the prompt
+	KeyCancel     KeyAction = "cancel"      // Cancel the current prompt
+	KeyUp         KeyAction = "up"          // Previous option / history entry
+	KeyDown       KeyAction = "down"        // Next option / history entry
+	KeyComplete   KeyAction = "complete"    // Complete the selected slash command
+	KeyToggle     KeyAction = "toggle"      // Toggle a multi-select option
+	KeyConfirmYes KeyAction = "confirm_yes" // Answer yes to a confirmation
+	KeyConfirmNo  KeyAction = "confirm_no"  // Answer no to a confirmation
+	KeyTasks      KeyAction = "tasks"       // Open / close the task panel
+)
+
+// keyActions lists the actions in display order. It also decides which
+// action keeps a key bound to several of them.
+var keyActions = []KeyAction{
+	KeyQuit, KeySubmit, KeyCancel, KeyUp, KeyDown, KeyComplete, KeyToggle, KeyConfirmYes, KeyConfirmNo, KeyTasks,
+}
+
+// DefaultKeyBindings returns the built-in bindings, keyed by action.
+// Keys use bubbletea's key string format ("ctrl+c", "enter", "space", "y").
+func DefaultKeyBindings() map[KeyAction][]string {
+	return map[KeyAction][]string{
+		KeyQuit:       {"ctrl+c"},
+		KeySubmit:     {"enter"},
+		KeyCancel:     {"esc"},
+		KeyUp:         {"up"},
+		KeyDown:       {"down"},
+		KeyComplete:   {"tab"},
+		KeyToggle:     {"space"},
+		KeyConfirmYes: {"y", "Y"},
+		KeyConfirmNo:  {"n", "N"},
+		KeyTasks:      {"ctrl+t"},
+	}
+}
+
+// KeyMap resolves key presses to actions.
+type KeyMap struct {
+	bindings map[KeyAction][]string
+	actions  map[string]KeyAction
+}
+
+// DefaultKeyMap returns a keymap with the built-in bindings.
+func DefaultKeyMap() *KeyMap {
+	km, _ := NewKeyMap(nil)
+	return km
+}
+
+// NewKeyMap builds a keymap from the defaults, where each action present in
+// custom replaces the default keys of that action. It returns a warning for
+// every unknown action and for every key bound to several actions; such a
+// key keeps the first of its actions in display order.
+func NewKeyMap(custom map[string][]string) (*KeyMap, []string) {
+	var warnings []string
+
+	bindings := DefaultKeyBindings()
+	for name, keys := range custom {
+		action := KeyAction(name)
+		if !slices.Contains(keyActions, action) {
+			warnings = append(warnings, fmt.Sprintf("unknown key action %q", name))
+			continue
+		}
+		bindings[action] = keys
+	}
+
+	km := &KeyMap{bindings: bindings, actions: make(map[string]KeyAction)}
+	for _, action := range keyActions {
+		for _, key := range bindings[action] {
+			if prev, ok := km.actions[key]; ok && prev != action {
+				warnings = append(warnings, fmt.Sprintf("key %q is bound to both %q and %q; using %q", key, prev, action, prev))
+				continue
+			}
+			km.actions[key] = action
+		}
+	}
+	slices.Sort(warnings)
+	return km, warnings
+}
+
+// Action returns the action bound to a key press, or "" when there is none.
+func (k *KeyMap) Action(msg tea.KeyPressMsg) KeyAction {
+	return k.actions[msg.String()]
+}
+
+// Describe returns one "action  keys" line per action, for /keys.
+func (k *KeyMap) Describe() []string {
+	lines := make([]string, 0, len(keyActions))
+	for _, action := range keyActions {
+		keys := "(unbound)"
+		if bound := k.bindings[action]; len(bound) > 0 {
+			keys = strings.Join(bound, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("%-12s %s", action, keys))
+	}
+	return lines
+}
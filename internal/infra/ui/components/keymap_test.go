@@ -0,0 +1,60 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestNewKeyMap_Defaults(t *testing.T) {
+	km, warnings := NewKeyMap(nil)
+	if len(warnings) != 0 {
+		t.Fatalf("default bindings should not conflict: %v", warnings)
+	}
+	if got := km.Action(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl}); got != KeyQuit {
+		t.Errorf("ctrl+c = %q, want quit", got)
+	}
+	if got := km.Action(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}); got != KeyToggle {
+		t.Errorf("space = %q, want toggle", got)
+	}
+}
+
+func TestNewKeyMap_Custom(t *testing.T) {
+	km, warnings := NewKeyMap(map[string][]string{
+		"down":  {"ctrl+j"},
+		"bogus": {"x"},
+	})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "bogus") {
+		t.Errorf("warnings = %v, want one about the unknown action", warnings)
+	}
+	if got := km.Action(tea.KeyPressMsg{Code: 'j', Mod: tea.ModCtrl}); got != KeyDown {
+		t.Errorf("ctrl+j = %q, want down", got)
+	}
+	if got := km.Action(tea.KeyPressMsg{Code: tea.KeyDown}); got != "" {
+		t.Errorf("down should be unbound once remapped, got %q", got)
+	}
+}
+
+func TestNewKeyMap_Conflict(t *testing.T) {
+	km, warnings := NewKeyMap(map[string][]string{"cancel": {"ctrl+c"}})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"ctrl+c"`) {
+		t.Fatalf("warnings = %v, want one conflict on ctrl+c", warnings)
+	}
+	if got := km.Action(tea.KeyPressMsg{Code: 'c', Mod: tea.ModCtrl}); got != KeyQuit {
+		t.Errorf("ctrl+c = %q, want the first action (quit) to win", got)
+	}
+}
+
+func TestInputZone_RemappedPrintableKeyIsTyped(t *testing.T) {
+	km, _ := NewKeyMap(map[string][]string{"up": {"up", "k"}})
+	z := NewInputZone()
+	z.SetKeyMap(km)
+	z.Focus()
+	z.AddHistory("previous")
+
+	z.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+	if got := z.textInput.Value(); got != "k" {
+		t.Errorf("typing k in chat = %q, want it typed rather than recalling history", got)
+	}
+}
@@ -18,6 +18,8 @@ func init() {
 		"input.placeholder.value": "Enter value...",
 		"input.error.required":    "This field is required",
 		"input.error.invalid":     "Invalid format",
+		"input.error.min_select":  "Select at least %d options",
+		"input.error.max_select":  "Select at most %d options",
 		"input.waiting":           "  Waiting for response...",
 
 		// Hints
@@ -26,6 +28,14 @@ func init() {
 		"hint.multi":   "↑↓=navigate • space=toggle • enter=submit • esc=cancel",
 		"hint.confirm": "y/n or ↑↓ + enter • esc=cancel",
 		"hint.scroll":  "↑↓=scroll",
+		"hint.palette": "↑↓=navigate • tab=complete • enter=run",
+		"hint.tasks":   "↑↓=navigate • enter=check • c=cancel task • esc=close",
+
+		// Multi-select bounds
+		"hint.select.exact": "select %d",
+		"hint.select.range": "select %d–%d",
+		"hint.select.min":   "select at least %d",
+		"hint.select.max":   "select up to %d",
 
 		// Chat
 		"chat.thinking":        "Thinking...",
@@ -35,11 +45,17 @@ func init() {
 		"chat.tool.more_lines": "... (%d more lines)",
 		"chat.tool.awaiting":   " (awaiting confirmation)",
 		"chat.tool.denied":     " (denied)",
+		"chat.tool.retrying":   " (retrying %d/%d)",
 
 		// Header
 		"header.tokens":    " tokens",
 		"header.streaming": "● streaming",
 
+		// Task panel
+		"tasks.title": "Tasks (%d)",
+		"tasks.empty": "No tasks in this session",
+		"tasks.more":  "... (%d more)",
+
 		// Roles
 		"role.system": "System: ",
 	})
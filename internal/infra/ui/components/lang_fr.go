@@ -18,6 +18,8 @@ func init() {
 		"input.placeholder.value": "Entrez une valeur...",
 		"input.error.required":    "Ce champ est obligatoire",
 		"input.error.invalid":     "Format invalide",
+		"input.error.min_select":  "Sélectionnez au moins %d options",
+		"input.error.max_select":  "Sélectionnez au plus %d options",
 		"input.waiting":           "  En attente de réponse...",
 
 		// Hints
@@ -26,6 +28,14 @@ func init() {
 		"hint.multi":   "↑↓=naviguer • espace=basculer • entrée=soumettre • esc=annuler",
 		"hint.confirm": "y/n ou ↑↓ + entrée • esc=annuler",
 		"hint.scroll":  "↑↓=défiler",
+		"hint.palette": "↑↓=naviguer • tab=compléter • entrée=exécuter",
+		"hint.tasks":   "↑↓=naviguer • entrée=vérifier • c=annuler la tâche • esc=fermer",
+
+		// Multi-select bounds
+		"hint.select.exact": "sélectionnez %d",
+		"hint.select.range": "sélectionnez %d–%d",
+		"hint.select.min":   "sélectionnez au moins %d",
+		"hint.select.max":   "sélectionnez jusqu'à %d",
 
 		// Chat
 		"chat.thinking":        "Réflexion en cours...",
@@ -35,11 +45,17 @@ func init() {
 		"chat.tool.more_lines": "... (%d lignes supplémentaires)",
 		"chat.tool.awaiting":   " (en attente de confirmation)",
 		"chat.tool.denied":     " (refusé)",
+		"chat.tool.retrying":   " (nouvel essai %d/%d)",
 
 		// Header
 		"header.tokens":    " tokens",
 		"header.streaming": "● streaming",
 
+		// Task panel
+		"tasks.title": "Tâches (%d)",
+		"tasks.empty": "Aucune tâche dans cette session",
+		"tasks.more":  "... (%d de plus)",
+
 		// Roles
 		"role.system": "Système : ",
 	})
@@ -277,11 +277,7 @@ func uintPtr(u uint) *uint       { return &u }
 // GetMarkdownRenderer returns a singleton markdown renderer.
 func GetMarkdownRenderer(width int) *glamour.TermRenderer {
 	markdownRendererOnce.Do(func() {
-		markdownRenderer, _ = glamour.NewTermRenderer(
-			glamour.WithStyles(customStyleConfig()),
-			glamour.WithWordWrap(width),
-			glamour.WithEmoji(),
-		)
+		markdownRenderer, _ = newMarkdownRenderer(width, chromaFormatter())
 	})
 	return markdownRenderer
 }
@@ -307,16 +303,32 @@ func RenderMarkdown(content string, width int) string {
 	return strings.TrimRight(rendered, "\n")
 }
 
+// newMarkdownRenderer creates a renderer with our theme. Code blocks are
+// highlighted with the chroma formatter, or left plain when it is empty.
+func newMarkdownRenderer(width int, formatter string) (*glamour.TermRenderer, error) {
+	opts := []glamour.TermRendererOption{
+		glamour.WithStyles(markdownStyle(formatter)),
+		glamour.WithWordWrap(width),
+		glamour.WithEmoji(),
+	}
+	if formatter != "" {
+		opts = append(opts, glamour.WithChromaFormatter(formatter))
+	}
+	return glamour.NewTermRenderer(opts...)
+}
+
 // Cached renderer for RenderMarkdownWithWidth — avoids re-creating a
 // glamour.TermRenderer on every call when the width hasn't changed.
 var (
-	cachedRenderer      *glamour.TermRenderer
-	cachedRendererWidth int
-	rendererMu          sync.Mutex
+	cachedRenderer          *glamour.TermRenderer
+	cachedRendererWidth     int
+	cachedRendererFormatter string
+	rendererMu              sync.Mutex
 )
 
 // RenderMarkdownWithWidth renders markdown content with the given width.
-// The renderer is cached and re-created only when width changes.
+// The renderer is cached and re-created only when width or the code
+// highlighting setting changes.
 func RenderMarkdownWithWidth(content string, width int) string {
 	if content == "" {
 		return ""
@@ -325,17 +337,15 @@ func RenderMarkdownWithWidth(content string, width int) string {
 	rendererMu.Lock()
 	defer rendererMu.Unlock()
 
-	if cachedRenderer == nil || cachedRendererWidth != width {
-		r, err := glamour.NewTermRenderer(
-			glamour.WithStyles(customStyleConfig()),
-			glamour.WithWordWrap(width),
-			glamour.WithEmoji(),
-		)
+	formatter := chromaFormatter()
+	if cachedRenderer == nil || cachedRendererWidth != width || cachedRendererFormatter != formatter {
+		r, err := newMarkdownRenderer(width, formatter)
 		if err != nil {
 			return content
 		}
 		cachedRenderer = r
 		cachedRendererWidth = width
+		cachedRendererFormatter = formatter
 	}
 
 	rendered, err := cachedRenderer.Render(content)
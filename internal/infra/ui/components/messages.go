@@ -47,9 +47,12 @@ type InputPromptSelectMsg struct {
 
 // InputPromptMultiMsg sets up a multi-select prompt.
 type InputPromptMultiMsg struct {
-	Question string
-	Field    string
-	Options  []InputOption
+	Question  string
+	Field     string
+	Options   []InputOption
+	Defaults  []string
+	MinSelect int
+	MaxSelect int
 }
 
 // InputResetMsg resets to chat mode.
@@ -93,6 +93,16 @@ var (
 	ToolErrorStyle = lipgloss.NewStyle().
 			Foreground(Error)
 
+	// Diff line styles for write_file / edit_file results
+	DiffAddStyle = lipgloss.NewStyle().
+			Foreground(Secondary)
+
+	DiffRemoveStyle = lipgloss.NewStyle().
+			Foreground(Error)
+
+	DiffHunkStyle = lipgloss.NewStyle().
+			Foreground(Accent)
+
 	ToolSpinnerStyle = lipgloss.NewStyle().
 				Foreground(Primary)
 
@@ -0,0 +1,221 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dohr-michael/ozzie/internal/infra/i18n"
+)
+
+// TaskEntry is a task listed in the task panel.
+type TaskEntry struct {
+	ID         string
+	Title      string
+	Status     string // pending, running, completed, failed, cancelled
+	Percentage int
+	StepLabel  string
+	Error      string
+}
+
+// Active reports whether the task is still pending or running.
+func (e TaskEntry) Active() bool {
+	return e.Status == "pending" || e.Status == "running"
+}
+
+// taskPanelRows is the number of tasks visible at once.
+const taskPanelRows = 8
+
+// TaskPanel lists the session's tasks with their status and progress, and
+// tracks the selected one. It is fed by list_tasks results and task events;
+// the App performs the actions on the selection.
+type TaskPanel struct {
+	tasks  []TaskEntry
+	cursor int
+	offset int // first visible row
+	width  int
+}
+
+// NewTaskPanel creates an empty task panel.
+func NewTaskPanel() *TaskPanel {
+	return &TaskPanel{}
+}
+
+// SetWidth sets the component width.
+func (p *TaskPanel) SetWidth(width int) {
+	p.width = width
+}
+
+// SetTasks replaces the listed tasks, keeping the selection on the same task
+// when it is still listed.
+func (p *TaskPanel) SetTasks(tasks []TaskEntry) {
+	selected, _ := p.Selected()
+	p.tasks = slices.Clone(tasks)
+	p.sort()
+	p.selectID(selected.ID)
+}
+
+// Task returns a listed task by ID.
+func (p *TaskPanel) Task(id string) (TaskEntry, bool) {
+	for _, t := range p.tasks {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return TaskEntry{}, false
+}
+
+// UpsertTask adds a task or replaces the listed task with the same ID.
+func (p *TaskPanel) UpsertTask(task TaskEntry) {
+	selected, _ := p.Selected()
+	if i := slices.IndexFunc(p.tasks, func(t TaskEntry) bool { return t.ID == task.ID }); i >= 0 {
+		p.tasks[i] = task
+	} else {
+		p.tasks = append(p.tasks, task)
+	}
+	p.sort()
+	p.selectID(selected.ID)
+}
+
+// Len returns the number of listed tasks.
+func (p *TaskPanel) Len() int {
+	return len(p.tasks)
+}
+
+// Selected returns the selected task, if any.
+func (p *TaskPanel) Selected() (TaskEntry, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.tasks) {
+		return TaskEntry{}, false
+	}
+	return p.tasks[p.cursor], true
+}
+
+// MoveUp selects the previous task.
+func (p *TaskPanel) MoveUp() {
+	if p.cursor > 0 {
+		p.cursor--
+	}
+	p.scroll()
+}
+
+// MoveDown selects the next task.
+func (p *TaskPanel) MoveDown() {
+	if p.cursor < len(p.tasks)-1 {
+		p.cursor++
+	}
+	p.scroll()
+}
+
+// sort lists active tasks first; the order within each group is kept.
+func (p *TaskPanel) sort() {
+	slices.SortStableFunc(p.tasks, func(a, b TaskEntry) int {
+		switch {
+		case a.Active() == b.Active():
+			return 0
+		case a.Active():
+			return -1
+		default:
+			return 1
+		}
+	})
+}
+
+// selectID moves the cursor to a task, or clamps it when the task is gone.
+func (p *TaskPanel) selectID(id string) {
+	if i := slices.IndexFunc(p.tasks, func(t TaskEntry) bool { return t.ID == id }); id != "" && i >= 0 {
+		p.cursor = i
+	}
+	p.cursor = max(min(p.cursor, len(p.tasks)-1), 0)
+	p.scroll()
+}
+
+// scroll keeps the cursor inside the visible rows.
+func (p *TaskPanel) scroll() {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+taskPanelRows {
+		p.offset = p.cursor - taskPanelRows + 1
+	}
+	p.offset = max(min(p.offset, len(p.tasks)-taskPanelRows), 0)
+}
+
+// View renders the panel: one row per task and a hint line.
+func (p *TaskPanel) View() string {
+	var b strings.Builder
+	b.WriteString(LabelStyle.Render(fmt.Sprintf(i18n.T("tasks.title"), len(p.tasks))))
+	b.WriteString("\n")
+
+	if len(p.tasks) == 0 {
+		b.WriteString(HintStyle.Render(i18n.T("tasks.empty")))
+		b.WriteString("\n")
+	}
+	end := min(p.offset+taskPanelRows, len(p.tasks))
+	for i := p.offset; i < end; i++ {
+		b.WriteString(p.renderRow(p.tasks[i], i == p.cursor))
+		b.WriteString("\n")
+	}
+	if hidden := len(p.tasks) - end + p.offset; hidden > 0 {
+		b.WriteString(HintStyle.Render(fmt.Sprintf(i18n.T("tasks.more"), hidden)))
+		b.WriteString("\n")
+	}
+	b.WriteString(HintStyle.Render(i18n.T("hint.tasks")))
+
+	style := BorderStyle.Padding(0, 1)
+	if p.width > 0 {
+		style = style.Width(p.width)
+	}
+	return style.Render(b.String())
+}
+
+// renderRow renders a task: status icon, title, progress and status.
+func (p *TaskPanel) renderRow(t TaskEntry, selected bool) string {
+	cursor := "  "
+	titleStyle := OptionStyle
+	if selected {
+		cursor = SelectedOptionStyle.Render("❯ ")
+		titleStyle = SelectedOptionStyle
+	}
+
+	title := t.Title
+	if title == "" {
+		title = t.ID
+	}
+	row := cursor + taskStatusIcon(t.Status) + " " + titleStyle.Render(TruncateString(title, 40))
+
+	switch {
+	case t.Status == "running":
+		row += "  " + renderProgressBar(t.Percentage, 10) + DescriptionStyle.Render(fmt.Sprintf(" %3d%%", t.Percentage))
+		if t.StepLabel != "" {
+			row += DescriptionStyle.Render("  " + TruncateString(t.StepLabel, 30))
+		}
+	case t.Status == "failed" && t.Error != "":
+		row += "  " + ToolErrorStyle.Render(TruncateString(t.Error, 40))
+	default:
+		row += "  " + DescriptionStyle.Render(t.Status)
+	}
+	return row
+}
+
+// taskStatusIcon returns the styled icon of a task status.
+func taskStatusIcon(status string) string {
+	switch status {
+	case "running":
+		return ConfirmWaitStyle.Render("◐")
+	case "completed":
+		return ToolSuccessStyle.Render("✓")
+	case "failed":
+		return ToolErrorStyle.Render("✗")
+	case "cancelled":
+		return DisabledStyle.Render("⊘")
+	default: // pending
+		return DescriptionStyle.Render("○")
+	}
+}
+
+// renderProgressBar renders a percentage as a bar of width cells.
+func renderProgressBar(percentage, width int) string {
+	filled := max(min(percentage, 100), 0) * width / 100
+	return ToolSuccessStyle.Render(strings.Repeat("█", filled)) +
+		InputSeparatorStyle.Render(strings.Repeat("░", width-filled))
+}
@@ -0,0 +1,89 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTaskPanel_ActiveTasksFirst(t *testing.T) {
+	p := NewTaskPanel()
+	p.SetTasks([]TaskEntry{
+		{ID: "t1", Status: "completed"},
+		{ID: "t2", Status: "running"},
+		{ID: "t3", Status: "failed"},
+		{ID: "t4", Status: "pending"},
+	})
+
+	var got []string
+	for _, task := range p.tasks {
+		got = append(got, task.ID)
+	}
+	if want := "t2 t4 t1 t3"; strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
+
+func TestTaskPanel_SelectionFollowsTask(t *testing.T) {
+	p := NewTaskPanel()
+	p.SetTasks([]TaskEntry{{ID: "t1", Status: "running"}, {ID: "t2", Status: "running"}})
+	p.MoveDown()
+	p.MoveDown() // stays on the last task
+	if sel, _ := p.Selected(); sel.ID != "t2" {
+		t.Fatalf("selected %q, want t2", sel.ID)
+	}
+
+	// t1 finishing moves it after t2: the selection stays on t2.
+	p.UpsertTask(TaskEntry{ID: "t1", Status: "completed"})
+	if sel, _ := p.Selected(); sel.ID != "t2" {
+		t.Errorf("selected %q after reorder, want t2", sel.ID)
+	}
+
+	// t2 gone: the cursor is clamped to the remaining task.
+	p.SetTasks([]TaskEntry{{ID: "t1", Status: "completed"}})
+	if sel, ok := p.Selected(); !ok || sel.ID != "t1" {
+		t.Errorf("selected %q (%v), want t1", sel.ID, ok)
+	}
+
+	p.SetTasks(nil)
+	if _, ok := p.Selected(); ok {
+		t.Error("empty panel should have no selection")
+	}
+}
+
+func TestTaskPanel_ViewScrolls(t *testing.T) {
+	p := NewTaskPanel()
+	var tasks []TaskEntry
+	for i := range taskPanelRows + 3 {
+		tasks = append(tasks, TaskEntry{ID: fmt.Sprintf("task-%02d", i), Status: "pending"})
+	}
+	p.SetTasks(tasks)
+	for range taskPanelRows + 1 {
+		p.MoveDown()
+	}
+
+	view := p.View()
+	if strings.Contains(view, "task-00") || !strings.Contains(view, "task-09") {
+		t.Errorf("view should scroll to the selected task:\n%s", view)
+	}
+	if !strings.Contains(view, "3 more") {
+		t.Errorf("view should count hidden tasks:\n%s", view)
+	}
+}
+
+func TestRenderProgressBar(t *testing.T) {
+	tests := []struct {
+		percentage, filled int
+	}{
+		{0, 0}, {40, 4}, {100, 10}, {150, 10}, {-5, 0},
+	}
+	for _, tt := range tests {
+		bar := renderProgressBar(tt.percentage, 10)
+		if got := strings.Count(bar, "█"); got != tt.filled {
+			t.Errorf("renderProgressBar(%d) filled %d cells, want %d", tt.percentage, got, tt.filled)
+		}
+		if got := strings.Count(bar, "█") + strings.Count(bar, "░"); got != 10 {
+			t.Errorf("renderProgressBar(%d) has %d cells, want 10", tt.percentage, got)
+		}
+	}
+}
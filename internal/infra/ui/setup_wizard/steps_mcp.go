@@ -276,7 +276,7 @@ func (s *mcpStep) showTrustedTools() {
 	s.input.PromptMulti(
 		i18n.T("wizard.mcp.trusted_tools"),
 		"mcp_trusted_tools", "",
-		options,
+		options, nil, 0, 0,
 	)
 }
 
@@ -351,18 +351,15 @@ func (s *providerStep) showAPIKeyInput() {
 }
 
 func (s *providerStep) showCapabilities() {
-	s.input.PromptMulti(
-		i18n.T("wizard.provider.caps"),
-		"capabilities", "",
-		capabilityOptions(),
-	)
 	preselect := s.current.Capabilities
 	if len(preselect) == 0 {
 		preselect = defaultCapsForModel(s.current.Model)
 	}
-	if len(preselect) > 0 {
-		s.input.PreSelectMulti(preselect)
-	}
+	s.input.PromptMulti(
+		i18n.T("wizard.provider.caps"),
+		"capabilities", "",
+		capabilityOptions(), preselect, 0, 0,
+	)
 }
 
 func (s *providerStep) showTags() {
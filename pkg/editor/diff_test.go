@@ -0,0 +1,57 @@
+package editor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dohr-michael/ozzie/pkg/editor"
+)
+
+func TestUnifiedDiff_Identical(t *testing.T) {
+	if d := editor.UnifiedDiff("a.txt", "same\n", "same\n"); d != "" {
+		t.Fatalf("expected empty diff, got %q", d)
+	}
+}
+
+func TestUnifiedDiff_NewFile(t *testing.T) {
+	d := editor.UnifiedDiff("new.txt", "", "one\ntwo\n")
+	want := "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+one\n+two\n"
+	if d != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", d, want)
+	}
+}
+
+func TestUnifiedDiff_Edit(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	updated := "a\nb\nc\nd\nE\nf\ng\nh\n"
+	d := editor.UnifiedDiff("f.txt", old, updated)
+	want := "--- a/f.txt\n+++ b/f.txt\n@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n"
+	if d != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", d, want)
+	}
+	if !editor.IsUnifiedDiff("Updated file f.txt\n\n" + d) {
+		t.Error("IsUnifiedDiff should detect the diff")
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 30; i++ {
+		oldLines = append(oldLines, "line")
+		newLines = append(newLines, "line")
+	}
+	oldLines[2], newLines[2] = "x", "y"
+	oldLines[25], newLines[25] = "p", "q"
+
+	d := editor.UnifiedDiff("f", strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	if n := strings.Count(d, "\n@@ "); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, d)
+	}
+}
+
+func TestUnifiedDiff_Truncated(t *testing.T) {
+	d := editor.UnifiedDiff("big", "", strings.Repeat("x\n", 1000))
+	if !strings.Contains(d, "diff truncated") {
+		t.Fatal("expected truncation marker")
+	}
+}
@@ -63,6 +63,9 @@ func (t *QueryMemoriesTool) InvokableRun(ctx context.Context, argumentsInJSON st
 	if err := json.Unmarshal([]byte(argumentsInJSON), &input); err != nil {
 		return "", fmt.Errorf("query_memories: parse input: %w", err)
 	}
+	if input.Query == "" {
+		return "", fmt.Errorf("query_memories: query is required")
+	}
 
 	var tags []string
 	if input.Tags != "" {
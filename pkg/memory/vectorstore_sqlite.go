@@ -4,13 +4,39 @@ import (
 	"context"
 	"database/sql"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
+	"sync"
 
 	"github.com/cloudwego/eino/components/embedding"
 )
 
+// ErrEmbeddingModelChanged is returned when the configured embedder does not
+// match the model or dimension recorded in the vector store header.
+var ErrEmbeddingModelChanged = errors.New("embedding model changed, reindex required")
+
+// EmbeddingMismatchError details an embedder/vector store mismatch.
+// It unwraps to ErrEmbeddingModelChanged.
+type EmbeddingMismatchError struct {
+	StoredModel string
+	StoredDims  int
+	Model       string
+	Dims        int
+}
+
+func (e *EmbeddingMismatchError) Error() string {
+	stored := e.StoredModel
+	if stored == "" {
+		stored = "unknown"
+	}
+	return fmt.Sprintf("embedding model changed, reindex required (stored: %s, %d dims; configured: %s, %d dims)",
+		stored, e.StoredDims, e.Model, e.Dims)
+}
+
+func (e *EmbeddingMismatchError) Unwrap() error { return ErrEmbeddingModelChanged }
+
 // VectorResult holds a single vector search result.
 type VectorResult struct {
 	ID         string
@@ -21,24 +47,147 @@ type VectorResult struct {
 
 // SQLiteVectorStore stores embeddings as BLOBs in a standard SQLite table
 // and performs brute-force cosine similarity search in Go.
+// A single-row header records the embedding model and dimension so that
+// vectors from different models are never compared.
 type SQLiteVectorStore struct {
 	db       *sql.DB
 	embedder embedding.Embedder
-	dims     int // embedding dimensions
+	model    string
+	mu       sync.RWMutex
+	dims     int // embedding dimensions (0 = learned from the first embedding)
 }
 
 // NewSQLiteVectorStore creates or opens the embeddings table.
 // embedder is used to compute embeddings for upsert and query.
-// dims must match the embedding model's output dimension.
-func NewSQLiteVectorStore(db *sql.DB, embedder embedding.Embedder, dims int) (*SQLiteVectorStore, error) {
-	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS memory_embeddings (
-		id TEXT PRIMARY KEY,
-		embedding BLOB NOT NULL
-	)`)
+// model identifies the embedding model; dims is its output dimension, or 0
+// to adopt the recorded dimension (or learn it from the first embedding).
+// Returns an *EmbeddingMismatchError if the store was built with another
+// model or dimension; call ResetSQLiteVectorStore then Reindex to rebuild.
+func NewSQLiteVectorStore(db *sql.DB, embedder embedding.Embedder, model string, dims int) (*SQLiteVectorStore, error) {
+	if err := createVectorTables(db); err != nil {
+		return nil, err
+	}
+
+	storedModel, storedDims, found, err := readVectorHeader(db)
 	if err != nil {
-		return nil, fmt.Errorf("create embeddings table: %w", err)
+		return nil, err
 	}
-	return &SQLiteVectorStore{db: db, embedder: embedder, dims: dims}, nil
+	if !found {
+		// Legacy store without header: infer the dimension from existing vectors.
+		storedDims, err = inferStoredDims(db)
+		if err != nil {
+			return nil, err
+		}
+		storedModel = model
+	}
+
+	if storedModel != model || (dims > 0 && storedDims > 0 && storedDims != dims) {
+		return nil, &EmbeddingMismatchError{
+			StoredModel: storedModel,
+			StoredDims:  storedDims,
+			Model:       model,
+			Dims:        dims,
+		}
+	}
+	if dims <= 0 {
+		dims = storedDims
+	}
+	if !found || storedDims != dims {
+		if err := writeVectorHeader(db, model, dims); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLiteVectorStore{db: db, embedder: embedder, model: model, dims: dims}, nil
+}
+
+// ResetSQLiteVectorStore deletes all stored embeddings and the model header,
+// and clears the indexing metadata of memories so that Reindex rebuilds them.
+func ResetSQLiteVectorStore(db *sql.DB) error {
+	if err := createVectorTables(db); err != nil {
+		return err
+	}
+	stmts := []string{
+		`DELETE FROM memory_embeddings`,
+		`DELETE FROM session_embeddings`,
+		`DELETE FROM memory_embeddings_header`,
+		`UPDATE memories SET embedding_model = '', indexed_at = NULL`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("reset embeddings: %w", err)
+		}
+	}
+	return nil
+}
+
+func createVectorTables(db *sql.DB) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS memory_embeddings (
+			id TEXT PRIMARY KEY,
+			embedding BLOB NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS session_embeddings (
+			id        TEXT PRIMARY KEY,
+			version   TEXT NOT NULL,
+			embedding BLOB NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS memory_embeddings_header (
+			id    INTEGER PRIMARY KEY CHECK (id = 1),
+			model TEXT NOT NULL,
+			dims  INTEGER NOT NULL
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("create embeddings table: %w", err)
+		}
+	}
+	return nil
+}
+
+func readVectorHeader(db *sql.DB) (model string, dims int, found bool, err error) {
+	err = db.QueryRow(`SELECT model, dims FROM memory_embeddings_header WHERE id = 1`).Scan(&model, &dims)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, false, nil
+	}
+	if err != nil {
+		return "", 0, false, fmt.Errorf("read embeddings header: %w", err)
+	}
+	return model, dims, true, nil
+}
+
+func writeVectorHeader(db *sql.DB, model string, dims int) error {
+	_, err := db.Exec(`INSERT OR REPLACE INTO memory_embeddings_header(id, model, dims) VALUES (1, ?, ?)`,
+		model, dims)
+	if err != nil {
+		return fmt.Errorf("write embeddings header: %w", err)
+	}
+	return nil
+}
+
+// inferStoredDims returns the dimension of an arbitrary stored vector, or 0 if empty.
+func inferStoredDims(db *sql.DB) (int, error) {
+	var size int
+	err := db.QueryRow(`SELECT length(embedding) FROM memory_embeddings LIMIT 1`).Scan(&size)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("inspect embeddings: %w", err)
+	}
+	return size / 4, nil
+}
+
+// Model returns the embedding model recorded for this store.
+func (vs *SQLiteVectorStore) Model() string {
+	return vs.model
+}
+
+// Dims returns the embedding dimension, or 0 if not yet known.
+func (vs *SQLiteVectorStore) Dims() int {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+	return vs.dims
 }
 
 // Upsert adds or updates a document's embedding.
@@ -85,6 +234,10 @@ func (vs *SQLiteVectorStore) Query(ctx context.Context, queryText string, nResul
 			return nil, fmt.Errorf("scan embedding: %w", err)
 		}
 		vec := decodeEmbedding(blob)
+		if len(vec) != len(queryVec) {
+			return nil, fmt.Errorf("embedding %s has %d dims, expected %d: %w",
+				id, len(vec), len(queryVec), ErrEmbeddingModelChanged)
+		}
 		sim := cosineSimilarity(queryVec, vec)
 		results = append(results, VectorResult{
 			ID:         id,
@@ -112,6 +265,52 @@ func (vs *SQLiteVectorStore) Count() int {
 	return count
 }
 
+// StoredVector is a document embedding persisted with the version of the
+// document it was computed from, to tell when it is stale.
+type StoredVector struct {
+	Version string
+	Vector  []float32
+}
+
+// LoadSessionVectors returns the stored session embeddings by session ID.
+// Session embeddings share the store's model header (and reset) but live in
+// their own table: they never show up in memory queries.
+func (vs *SQLiteVectorStore) LoadSessionVectors(ctx context.Context) (map[string]StoredVector, error) {
+	rows, err := vs.db.QueryContext(ctx, `SELECT id, version, embedding FROM session_embeddings`)
+	if err != nil {
+		return nil, fmt.Errorf("load session embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	vectors := make(map[string]StoredVector)
+	for rows.Next() {
+		var id, version string
+		var blob []byte
+		if err := rows.Scan(&id, &version, &blob); err != nil {
+			return nil, fmt.Errorf("scan session embedding: %w", err)
+		}
+		vectors[id] = StoredVector{Version: version, Vector: decodeEmbedding(blob)}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate session embeddings: %w", err)
+	}
+	return vectors, nil
+}
+
+// SaveSessionVector stores the embedding of a session (normalized), computed
+// from the given version of the session.
+func (vs *SQLiteVectorStore) SaveSessionVector(ctx context.Context, id, version string, vec []float64) error {
+	if err := vs.checkDims(len(vec)); err != nil {
+		return err
+	}
+	_, err := vs.db.ExecContext(ctx, `INSERT OR REPLACE INTO session_embeddings(id, version, embedding) VALUES (?, ?, ?)`,
+		id, version, encodeEmbedding(normalize(vec)))
+	if err != nil {
+		return fmt.Errorf("save session embedding: %w", err)
+	}
+	return nil
+}
+
 // embed computes the embedding for a text string.
 func (vs *SQLiteVectorStore) embed(ctx context.Context, text string) ([]float32, error) {
 	vectors, err := vs.embedder.EmbedStrings(ctx, []string{text})
@@ -123,7 +322,14 @@ func (vs *SQLiteVectorStore) embed(ctx context.Context, text string) ([]float32,
 	}
 
 	f64 := vectors[0]
-	// Normalize to unit vector
+	if err := vs.checkDims(len(f64)); err != nil {
+		return nil, err
+	}
+	return normalize(f64), nil
+}
+
+// normalize converts an embedding to a float32 unit vector.
+func normalize(f64 []float64) []float32 {
 	var norm float64
 	for _, v := range f64 {
 		norm += v * v
@@ -138,7 +344,34 @@ func (vs *SQLiteVectorStore) embed(ctx context.Context, text string) ([]float32,
 			f32[i] = float32(v)
 		}
 	}
-	return f32, nil
+	return f32
+}
+
+// checkDims verifies an embedding dimension against the store, recording it
+// if the store dimension is not yet known.
+func (vs *SQLiteVectorStore) checkDims(n int) error {
+	vs.mu.RLock()
+	dims := vs.dims
+	vs.mu.RUnlock()
+	if dims == n {
+		return nil
+	}
+	if dims > 0 {
+		return &EmbeddingMismatchError{StoredModel: vs.model, StoredDims: dims, Model: vs.model, Dims: n}
+	}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.dims == 0 {
+		if err := writeVectorHeader(vs.db, vs.model, n); err != nil {
+			return err
+		}
+		vs.dims = n
+	}
+	if vs.dims != n {
+		return &EmbeddingMismatchError{StoredModel: vs.model, StoredDims: vs.dims, Model: vs.model, Dims: n}
+	}
+	return nil
 }
 
 // encodeEmbedding converts float32 slice to little-endian bytes.
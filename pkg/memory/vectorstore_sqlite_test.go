@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// fakeEmbedder returns a deterministic vector of a fixed dimension.
+type fakeEmbedder struct {
+	dims int
+}
+
+func (f *fakeEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	out := make([][]float64, len(texts))
+	for i, text := range texts {
+		vec := make([]float64, f.dims)
+		for j := range vec {
+			vec[j] = float64(len(text) + j + 1)
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func newTestVectorDB(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := NewSQLiteStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteVectorStore_RecordsHeader(t *testing.T) {
+	store := newTestVectorDB(t)
+	ctx := context.Background()
+
+	vs, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 4}, "model-a", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore: %v", err)
+	}
+	if err := vs.Upsert(ctx, "mem_1", "hello", nil); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if vs.Dims() != 4 {
+		t.Fatalf("expected learned dims 4, got %d", vs.Dims())
+	}
+
+	// Reopening with the same model adopts the recorded dimension.
+	vs2, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 4}, "model-a", 0)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if vs2.Dims() != 4 {
+		t.Fatalf("expected dims 4 from header, got %d", vs2.Dims())
+	}
+}
+
+func TestSQLiteVectorStore_ModelChanged(t *testing.T) {
+	store := newTestVectorDB(t)
+	ctx := context.Background()
+
+	vs, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 4}, "model-a", 4)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore: %v", err)
+	}
+	if err := vs.Upsert(ctx, "mem_1", "hello", nil); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	_, err = NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 8}, "model-b", 8)
+	if !errors.Is(err, ErrEmbeddingModelChanged) {
+		t.Fatalf("expected ErrEmbeddingModelChanged, got %v", err)
+	}
+	var mismatch *EmbeddingMismatchError
+	if !errors.As(err, &mismatch) || mismatch.StoredModel != "model-a" || mismatch.StoredDims != 4 {
+		t.Fatalf("unexpected mismatch details: %+v", mismatch)
+	}
+
+	// Same model name, different dimension is also refused.
+	_, err = NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 8}, "model-a", 8)
+	if !errors.Is(err, ErrEmbeddingModelChanged) {
+		t.Fatalf("expected ErrEmbeddingModelChanged for dims change, got %v", err)
+	}
+}
+
+func TestSQLiteVectorStore_ResetAllowsNewModel(t *testing.T) {
+	store := newTestVectorDB(t)
+	ctx := context.Background()
+
+	entry := &MemoryEntry{Title: "t", Type: MemoryFact, Importance: ImportanceNormal}
+	if err := store.Create(entry, "content"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	vs, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 4}, "model-a", 4)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore: %v", err)
+	}
+	if _, err := Reindex(ctx, store, vs, "model-a"); err != nil {
+		t.Fatalf("Reindex: %v", err)
+	}
+
+	if err := ResetSQLiteVectorStore(store.DB()); err != nil {
+		t.Fatalf("ResetSQLiteVectorStore: %v", err)
+	}
+	vs2, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 8}, "model-a", 8)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore after reset: %v", err)
+	}
+	stats, err := Reindex(ctx, store, vs2, "model-a")
+	if err != nil {
+		t.Fatalf("Reindex after reset: %v", err)
+	}
+	if stats.Indexed != 1 {
+		t.Fatalf("expected 1 entry reindexed after reset, got %d", stats.Indexed)
+	}
+	if _, err := vs2.Query(ctx, "content", 5); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+}
+
+func TestSQLiteVectorStore_EmbedderDimensionMismatch(t *testing.T) {
+	store := newTestVectorDB(t)
+	ctx := context.Background()
+
+	// Configured for 4 dims but the embedder returns 8.
+	vs, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 8}, "model-a", 4)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore: %v", err)
+	}
+	if err := vs.Upsert(ctx, "mem_1", "hello", nil); !errors.Is(err, ErrEmbeddingModelChanged) {
+		t.Fatalf("expected dimension mismatch on upsert, got %v", err)
+	}
+	if _, err := vs.Query(ctx, "hello", 5); !errors.Is(err, ErrEmbeddingModelChanged) {
+		t.Fatalf("expected dimension mismatch on query, got %v", err)
+	}
+}
+
+func TestSQLiteVectorStore_LegacyStoreInfersDims(t *testing.T) {
+	store := newTestVectorDB(t)
+
+	// Simulate a store created before the header existed.
+	if _, err := store.DB().Exec(`CREATE TABLE memory_embeddings (id TEXT PRIMARY KEY, embedding BLOB NOT NULL)`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	if _, err := store.DB().Exec(`INSERT INTO memory_embeddings(id, embedding) VALUES ('mem_1', ?)`,
+		encodeEmbedding([]float32{1, 0, 0, 0})); err != nil {
+		t.Fatalf("insert legacy vector: %v", err)
+	}
+
+	if _, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 8}, "model-a", 8); !errors.Is(err, ErrEmbeddingModelChanged) {
+		t.Fatalf("expected mismatch against legacy 4-dim vectors, got %v", err)
+	}
+	vs, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 4}, "model-a", 0)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore: %v", err)
+	}
+	if vs.Dims() != 4 {
+		t.Fatalf("expected inferred dims 4, got %d", vs.Dims())
+	}
+}
+
+func TestSQLiteVectorStore_SessionVectors(t *testing.T) {
+	store := newTestVectorDB(t)
+	ctx := context.Background()
+
+	vs, err := NewSQLiteVectorStore(store.DB(), &fakeEmbedder{dims: 4}, "model-a", 4)
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorStore: %v", err)
+	}
+	if err := vs.SaveSessionVector(ctx, "sess_1", "v1", []float64{3, 0, 4, 0}); err != nil {
+		t.Fatalf("SaveSessionVector: %v", err)
+	}
+	if err := vs.SaveSessionVector(ctx, "sess_1", "v2", []float64{0, 2, 0, 0}); err != nil {
+		t.Fatalf("SaveSessionVector: %v", err)
+	}
+	if err := vs.SaveSessionVector(ctx, "sess_2", "v1", []float64{1, 2}); err == nil {
+		t.Error("expected a dimension mismatch error")
+	}
+
+	vectors, err := vs.LoadSessionVectors(ctx)
+	if err != nil {
+		t.Fatalf("LoadSessionVectors: %v", err)
+	}
+	got, ok := vectors["sess_1"]
+	if len(vectors) != 1 || !ok || got.Version != "v2" || got.Vector[1] != 1 {
+		t.Fatalf("session vectors = %+v", vectors)
+	}
+
+	// Session vectors are not memories.
+	if n := vs.Count(); n != 0 {
+		t.Errorf("Count = %d, want 0", n)
+	}
+
+	if err := ResetSQLiteVectorStore(store.DB()); err != nil {
+		t.Fatalf("ResetSQLiteVectorStore: %v", err)
+	}
+	if vectors, _ := vs.LoadSessionVectors(ctx); len(vectors) != 0 {
+		t.Errorf("session vectors survived a reset: %+v", vectors)
+	}
+}